@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/config"
@@ -665,6 +666,82 @@ func TestAddEvolveTasks(t *testing.T) {
 	require.True(t, status == bindinfo.Enabled || status == bindinfo.Rejected)
 }
 
+func TestEvolveHistory(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, index idx_a(a), index idx_b(b), index idx_c(c))")
+	tk.MustExec("insert into t values (1,1,1), (2,2,2), (3,3,3), (4,4,4), (5,5,5)")
+	tk.MustExec("analyze table t")
+	tk.MustExec("create global binding for select * from t where a >= 1 and b >= 1 and c = 0 using select * from t use index(idx_a) where a >= 1 and b >= 1 and c = 0")
+	// The baseline evolution can be enabled without the test config.
+	tk.MustExec("set @@tidb_evolve_plan_baselines=1")
+	tk.MustQuery("select * from t where a >= 4 and b >= 1 and c = 0")
+	tk.MustExec("admin flush bindings")
+	rows := tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 2)
+	require.Equal(t, "pending verify", rows[0][3])
+	tk.MustQuery("select count(*) from mysql.bind_evolve_history").Check(testkit.Rows("0"))
+
+	tk.MustExec("admin evolve bindings")
+	rows = tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 2)
+	status := rows[0][3].(string)
+	require.True(t, status == bindinfo.Enabled || status == bindinfo.Rejected)
+	history := tk.MustQuery("select original_sql, bind_sql, default_db, status from mysql.bind_evolve_history").Rows()
+	require.Len(t, history, 1)
+	require.Equal(t, []interface{}{rows[0][0], rows[0][1], "test", status}, history[0])
+	tk.MustQuery("select accepted_plan_time >= 0, verify_plan_time >= -1 from mysql.bind_evolve_history").Check(testkit.Rows("1 1"))
+}
+
+func TestEvolveMemoryBudget(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, index idx_a(a), index idx_b(b), index idx_c(c))")
+	tk.MustExec("insert into t values (1,1,1), (2,2,2), (3,3,3), (4,4,4), (5,5,5)")
+	tk.MustExec("analyze table t")
+	tk.MustExec("create global binding for select * from t where a >= 1 and b >= 1 and c = 0 using select * from t use index(idx_a) where a >= 1 and b >= 1 and c = 0")
+	tk.MustExec("set @@tidb_evolve_plan_baselines=1")
+	tk.MustQuery("select * from t where a >= 4 and b >= 1 and c = 0")
+	tk.MustExec("admin flush bindings")
+	tk.MustQuery("select @@global.tidb_evolve_plan_task_max_memory").Check(testkit.Rows("1073741824"))
+
+	// The statements of the evolve task exceed the memory budget.
+	defer tk.MustExec("set global tidb_mem_oom_action = default")
+	tk.MustExec("set global tidb_mem_oom_action = 'cancel'")
+	tk.MustExec("set @@global.tidb_evolve_plan_task_max_memory=1")
+	tk.MustExec("admin evolve bindings")
+	rows := tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	tk.MustQuery("select status, reason like '%exceeding the allowed memory limit%' from mysql.bind_evolve_history").Check(testkit.Rows("deleted 1"))
+	// The memory quota of the session is not changed.
+	tk.MustQuery("select @@tidb_mem_quota_query").Check(testkit.Rows("1073741824"))
+}
+
+func TestGCEvolveHistory(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select @@global.tidb_evolve_plan_history_retention_days").Check(testkit.Rows("30"))
+	tk.MustExec("set @@global.tidb_evolve_plan_history_retention_days=0")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect tidb_evolve_plan_history_retention_days value: '0'"))
+	insert := "insert into mysql.bind_evolve_history(original_sql, bind_sql, default_db, status, accepted_plan_time, verify_plan_time, evolve_time) values (?, '', 'test', 'enabled', 1, 1, ?)"
+	now := time.Now()
+	tk.MustExec(insert, "a", now.Add(-40*24*time.Hour).Format("2006-01-02 15:04:05"))
+	tk.MustExec(insert, "b", now.Add(-20*24*time.Hour).Format("2006-01-02 15:04:05"))
+	tk.MustExec(insert, "c", now.Format("2006-01-02 15:04:05"))
+
+	require.NoError(t, dom.BindHandle().GCEvolveHistory(30))
+	tk.MustQuery("select original_sql from mysql.bind_evolve_history order by original_sql").Check(testkit.Rows("b", "c"))
+	require.NoError(t, dom.BindHandle().GCEvolveHistory(10))
+	tk.MustQuery("select original_sql from mysql.bind_evolve_history").Check(testkit.Rows("c"))
+}
+
 func TestRuntimeHintsInEvolveTasks(t *testing.T) {
 	originalVal := config.CheckTableBeforeDrop
 	config.CheckTableBeforeDrop = true
@@ -1113,7 +1190,7 @@ func TestDMLIndexHintBind(t *testing.T) {
 	require.True(t, tk.MustUseIndex("delete from t where b = 1 and c > 1", "idx_c(c)"))
 }
 
-func TestForbidEvolvePlanBaseLinesBeforeGA(t *testing.T) {
+func TestEvolvePlanBaseLinesWithoutTestConfig(t *testing.T) {
 	originalVal := config.CheckTableBeforeDrop
 	config.CheckTableBeforeDrop = false
 	defer func() {
@@ -1123,14 +1200,13 @@ func TestForbidEvolvePlanBaseLinesBeforeGA(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
 	tk := testkit.NewTestKit(t, store)
-	err := tk.ExecToErr("set @@tidb_evolve_plan_baselines=0")
-	require.Equal(t, nil, err)
-	err = tk.ExecToErr("set @@TiDB_Evolve_pLan_baselines=1")
-	require.EqualError(t, err, "Cannot enable baseline evolution feature, it is not generally available now")
-	err = tk.ExecToErr("set @@TiDB_Evolve_pLan_baselines=oN")
-	require.EqualError(t, err, "Cannot enable baseline evolution feature, it is not generally available now")
-	err = tk.ExecToErr("admin evolve bindings")
-	require.EqualError(t, err, "Cannot enable baseline evolution feature, it is not generally available now")
+	tk.MustExec("set @@tidb_evolve_plan_baselines=0")
+	tk.MustQuery("select @@tidb_evolve_plan_baselines").Check(testkit.Rows("0"))
+	tk.MustExec("set @@TiDB_Evolve_pLan_baselines=1")
+	tk.MustQuery("select @@tidb_evolve_plan_baselines").Check(testkit.Rows("1"))
+	tk.MustExec("set @@TiDB_Evolve_pLan_baselines=oN")
+	tk.MustQuery("select @@tidb_evolve_plan_baselines").Check(testkit.Rows("1"))
+	tk.MustExec("admin evolve bindings")
 }
 
 func TestExplainTableStmts(t *testing.T) {
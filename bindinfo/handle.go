@@ -39,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	utilparser "github.com/pingcap/tidb/util/parser"
 	"github.com/pingcap/tidb/util/sqlexec"
 	stmtsummaryv2 "github.com/pingcap/tidb/util/stmtsummary/v2"
@@ -1078,26 +1079,35 @@ func (h *BindHandle) SaveEvolveTasksToStore() {
 	h.pendingVerifyBindRecordMap.flushToStore()
 }
 
-func getEvolveParameters(sctx sessionctx.Context) (time.Duration, time.Time, time.Time, error) {
+// getEvolveParameters returns the max time and the max memory of a statement
+// of the evolve task, and the time period in a day to run the evolve tasks.
+func getEvolveParameters(sctx sessionctx.Context) (time.Duration, int64, time.Time, time.Time, error) {
 	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo)
 	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(
 		ctx,
 		nil,
-		"SELECT variable_name, variable_value FROM mysql.global_variables WHERE variable_name IN (%?, %?, %?)",
+		"SELECT variable_name, variable_value FROM mysql.global_variables WHERE variable_name IN (%?, %?, %?, %?)",
 		variable.TiDBEvolvePlanTaskMaxTime,
+		variable.TiDBEvolvePlanTaskMaxMemory,
 		variable.TiDBEvolvePlanTaskStartTime,
 		variable.TiDBEvolvePlanTaskEndTime,
 	)
 	if err != nil {
-		return 0, time.Time{}, time.Time{}, err
+		return 0, 0, time.Time{}, time.Time{}, err
 	}
 	maxTime, startTimeStr, endTimeStr := int64(variable.DefTiDBEvolvePlanTaskMaxTime), variable.DefTiDBEvolvePlanTaskStartTime, variable.DefAutoAnalyzeEndTime
+	maxMemory := int64(variable.DefTiDBEvolvePlanTaskMaxMemory)
 	for _, row := range rows {
 		switch row.GetString(0) {
 		case variable.TiDBEvolvePlanTaskMaxTime:
 			maxTime, err = strconv.ParseInt(row.GetString(1), 10, 64)
 			if err != nil {
-				return 0, time.Time{}, time.Time{}, err
+				return 0, 0, time.Time{}, time.Time{}, err
+			}
+		case variable.TiDBEvolvePlanTaskMaxMemory:
+			maxMemory, err = strconv.ParseInt(row.GetString(1), 10, 64)
+			if err != nil {
+				return 0, 0, time.Time{}, time.Time{}, err
 			}
 		case variable.TiDBEvolvePlanTaskStartTime:
 			startTimeStr = row.GetString(1)
@@ -1107,13 +1117,13 @@ func getEvolveParameters(sctx sessionctx.Context) (time.Duration, time.Time, tim
 	}
 	startTime, err := time.ParseInLocation(variable.FullDayTimeFormat, startTimeStr, time.UTC)
 	if err != nil {
-		return 0, time.Time{}, time.Time{}, err
+		return 0, 0, time.Time{}, time.Time{}, err
 	}
 	endTime, err := time.ParseInLocation(variable.FullDayTimeFormat, endTimeStr, time.UTC)
 	if err != nil {
-		return 0, time.Time{}, time.Time{}, err
+		return 0, 0, time.Time{}, time.Time{}, err
 	}
-	return time.Duration(maxTime) * time.Second, startTime, endTime, nil
+	return time.Duration(maxTime) * time.Second, maxMemory, startTime, endTime, nil
 }
 
 const (
@@ -1152,7 +1162,10 @@ func (h *BindHandle) getOnePendingVerifyJob() (originalSQL, db string, binding B
 	return "", "", Binding{}
 }
 
-func (*BindHandle) getRunningDuration(sctx sessionctx.Context, db, sql string, maxTime time.Duration) (time.Duration, error) {
+// getRunningDuration runs the sql and returns its execution time, it returns -1 if the sql
+// doesn't finish within maxTime. The sql is cancelled if it exceeds the memory budget, so
+// the evolve tasks won't take too much memory from the online workload.
+func (*BindHandle) getRunningDuration(sctx sessionctx.Context, db, sql string, maxTime time.Duration, maxMemory int64) (time.Duration, error) {
 	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo)
 	if db != "" {
 		_, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(ctx, "use %n", db)
@@ -1160,6 +1173,13 @@ func (*BindHandle) getRunningDuration(sctx sessionctx.Context, db, sql string, m
 			return 0, err
 		}
 	}
+	if maxMemory > 0 {
+		originalMemQuota := sctx.GetSessionVars().MemQuotaQuery
+		sctx.GetSessionVars().MemQuotaQuery = maxMemory
+		defer func() {
+			sctx.GetSessionVars().MemQuotaQuery = originalMemQuota
+		}()
+	}
 	ctx, cancelFunc := context.WithCancel(ctx)
 	timer := time.NewTimer(maxTime)
 	defer timer.Stop()
@@ -1216,7 +1236,7 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 	if originalSQL == "" {
 		return nil
 	}
-	maxTime, startTime, endTime, err := getEvolveParameters(sctx)
+	maxTime, maxMemory, startTime, endTime, err := getEvolveParameters(sctx)
 	if err != nil {
 		return err
 	}
@@ -1224,11 +1244,12 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 		return nil
 	}
 	sctx.GetSessionVars().UsePlanBaselines = true
-	currentPlanTime, err := h.getRunningDuration(sctx, db, binding.BindSQL, maxTime)
+	currentPlanTime, err := h.getRunningDuration(sctx, db, binding.BindSQL, maxTime, maxMemory)
 	// If we just return the error to the caller, this job will be retried again and again and cause endless logs,
 	// since it is still in the bind record. Now we just drop it and if it is actually retryable,
 	// we will hope for that we can capture this evolve task again.
 	if err != nil {
+		h.recordEvolveHistory(sctx, originalSQL, db, &binding, deleted, 0, 0, "run accepted plan failed: "+err.Error())
 		_, err = h.DropBindRecord(originalSQL, db, &binding)
 		return err
 	}
@@ -1238,13 +1259,25 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 		maxTime = time.Duration(float64(currentPlanTime) * verifyTimeoutFactor)
 	}
 	sctx.GetSessionVars().UsePlanBaselines = false
-	verifyPlanTime, err := h.getRunningDuration(sctx, db, binding.BindSQL, maxTime)
+	verifyPlanTime, err := h.getRunningDuration(sctx, db, binding.BindSQL, maxTime, maxMemory)
+	var reason string
+	if err != nil && strings.Contains(err.Error(), memory.PanicMemoryExceedWarnMsg) {
+		verifyPlanTime, err, reason = -1, nil, "the verified plan exceeded the memory budget"
+	}
 	if err != nil {
+		h.recordEvolveHistory(sctx, originalSQL, db, &binding, deleted, currentPlanTime, 0, "run verified plan failed: "+err.Error())
 		_, err = h.DropBindRecord(originalSQL, db, &binding)
 		return err
 	}
 	if verifyPlanTime == -1 || (float64(verifyPlanTime)*acceptFactor > float64(currentPlanTime)) {
 		binding.Status = Rejected
+		switch {
+		case reason != "":
+		case verifyPlanTime == -1:
+			reason = "the verified plan timed out"
+		default:
+			reason = fmt.Sprintf("the verified plan is not %v times faster than the accepted plans", acceptFactor)
+		}
 		digestText, _ := parser.NormalizeDigest(binding.BindSQL) // for log desensitization
 		logutil.BgLogger().Debug("new plan rejected", zap.String("category", "sql-bind"),
 			zap.Duration("currentPlanTime", currentPlanTime),
@@ -1254,10 +1287,54 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 	} else {
 		binding.Status = Enabled
 	}
+	h.recordEvolveHistory(sctx, originalSQL, db, &binding, binding.Status, currentPlanTime, verifyPlanTime, reason)
 	// We don't need to pass the `sctx` because the BindSQL has been validated already.
 	return h.AddBindRecord(nil, &BindRecord{OriginalSQL: originalSQL, Db: db, Bindings: []Binding{binding}})
 }
 
+// recordEvolveHistory saves the result of the evolve task into mysql.bind_evolve_history.
+// The error is only logged since the history shouldn't block the evolution.
+func (*BindHandle) recordEvolveHistory(sctx sessionctx.Context, originalSQL, db string, binding *Binding, status string,
+	currentPlanTime, verifyPlanTime time.Duration, reason string) {
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo)
+	_, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(ctx,
+		`INSERT INTO mysql.bind_evolve_history(original_sql, bind_sql, default_db, sql_digest, plan_digest,
+		status, accepted_plan_time, verify_plan_time, reason, evolve_time) VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, NOW(3))`,
+		originalSQL,
+		binding.BindSQL,
+		db,
+		binding.SQLDigest,
+		binding.PlanDigest,
+		status,
+		evolvePlanTimeInMs(currentPlanTime),
+		evolvePlanTimeInMs(verifyPlanTime),
+		reason,
+	)
+	if err != nil {
+		logutil.BgLogger().Warn("record evolve history failed", zap.String("category", "sql-bind"), zap.Error(err))
+	}
+}
+
+// GCEvolveHistory removes the records in mysql.bind_evolve_history which are
+// older than the retention days.
+func (h *BindHandle) GCEvolveHistory(retentionDays int64) error {
+	h.sctx.Lock()
+	defer h.sctx.Unlock()
+	exec, _ := h.sctx.Context.(sqlexec.SQLExecutor)
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo)
+	expireTime := time.Now().Add(-time.Duration(retentionDays) * 24 * time.Hour)
+	expireTimeStr := types.NewTime(types.FromGoTime(expireTime), mysql.TypeTimestamp, 3).String()
+	_, err := exec.ExecuteInternal(ctx, `DELETE FROM mysql.bind_evolve_history WHERE evolve_time < %?`, expireTimeStr)
+	return err
+}
+
+func evolvePlanTimeInMs(d time.Duration) int64 {
+	if d < 0 {
+		return -1
+	}
+	return d.Milliseconds()
+}
+
 // Clear resets the bind handle. It is only used for test.
 func (h *BindHandle) Clear() {
 	h.bindInfo.Lock()
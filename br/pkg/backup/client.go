@@ -459,6 +459,17 @@ func BuildTableRanges(tbl *model.TableInfo) ([]kv.KeyRange, error) {
 		}
 		ranges = append(ranges, rgs...)
 	}
+	// The global indexes are stored with the table ID instead of the partition IDs.
+	for _, index := range tbl.Indices {
+		if index.State != model.StatePublic || !index.Global {
+			continue
+		}
+		idxRanges, err := distsql.IndexRangesToKVRanges(nil, tbl.ID, index.ID, ranger.FullRange(), nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ranges = idxRanges.AppendSelfTo(ranges)
+	}
 	return ranges, nil
 }
 
@@ -478,7 +489,7 @@ func appendRanges(tbl *model.TableInfo, tblID int64) ([]kv.KeyRange, error) {
 	retRanges = kvRanges.AppendSelfTo(retRanges)
 
 	for _, index := range tbl.Indices {
-		if index.State != model.StatePublic {
+		if index.State != model.StatePublic || index.Global {
 			continue
 		}
 		ranges = ranger.FullRange()
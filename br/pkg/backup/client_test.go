@@ -169,6 +169,22 @@ func TestBuildTableRangeIntHandle(t *testing.T) {
 	require.Equal(t, []kv.KeyRange{
 		{StartKey: tablecodec.EncodeRowKey(7, low), EndKey: tablecodec.EncodeRowKey(7, high)},
 	}, ranges)
+
+	// The global indexes are backed up with the table ID.
+	tbl = &model.TableInfo{ID: 7, Partition: &model.PartitionInfo{Enable: true, Definitions: []model.PartitionDefinition{{ID: 1}, {ID: 2}}},
+		Indices: []*model.IndexInfo{{ID: 1, State: model.StatePublic}, {ID: 2, State: model.StatePublic, Global: true, Unique: true}}}
+	ranges, err = backup.BuildTableRanges(tbl)
+	require.NoError(t, err)
+	require.Len(t, ranges, 5)
+	for i, prefix := range []kv.Key{
+		tablecodec.GenTableRecordPrefix(1),
+		tablecodec.EncodeTableIndexPrefix(1, 1),
+		tablecodec.GenTableRecordPrefix(2),
+		tablecodec.EncodeTableIndexPrefix(2, 1),
+		tablecodec.EncodeTableIndexPrefix(7, 2),
+	} {
+		require.True(t, ranges[i].StartKey.HasPrefix(prefix))
+	}
 }
 
 func TestBuildTableRangeCommonHandle(t *testing.T) {
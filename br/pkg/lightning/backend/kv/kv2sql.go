@@ -49,7 +49,16 @@ func (*TableKVDecoder) DecodeHandleFromRowKey(key []byte) (kv.Handle, error) {
 // DecodeHandleFromIndex implements KVDecoder.DecodeHandleFromIndex.
 func (t *TableKVDecoder) DecodeHandleFromIndex(indexInfo *model.IndexInfo, key, value []byte) (kv.Handle, error) {
 	cols := tables.BuildRowcodecColInfoForIndexColumns(indexInfo, t.tbl.Meta())
-	return tablecodec.DecodeIndexHandle(key, value, len(cols))
+	h, err := tablecodec.DecodeIndexHandle(key, value, len(cols))
+	if err != nil || !indexInfo.Global {
+		return h, err
+	}
+	// The row of a global index is in the partition recorded in the value.
+	pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(value)
+	if err != nil || !ok {
+		return h, err
+	}
+	return kv.NewPartitionHandle(pid, h), nil
 }
 
 // DecodeRawRowData decodes raw row data into a datum slice and a (columnID:columnValue) map.
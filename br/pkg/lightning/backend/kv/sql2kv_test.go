@@ -256,6 +256,63 @@ func TestDecodeIndex(t *testing.T) {
 	require.Equal(t, rawData, rows)
 }
 
+func TestEncodeGlobalIndex(t *testing.T) {
+	tblInfo := &model.TableInfo{
+		ID:   1,
+		Name: model.NewCIStr("t"),
+		Columns: []*model.ColumnInfo{
+			{ID: 1, Name: model.NewCIStr("a"), State: model.StatePublic, Offset: 0, FieldType: *types.NewFieldType(mysql.TypeLong)},
+			{ID: 2, Name: model.NewCIStr("b"), State: model.StatePublic, Offset: 1, FieldType: *types.NewFieldType(mysql.TypeLong)},
+		},
+		Indices: []*model.IndexInfo{
+			{
+				ID:      1,
+				Name:    model.NewCIStr("uk"),
+				Columns: []*model.IndexColumn{{Name: model.NewCIStr("b"), Offset: 1, Length: types.UnspecifiedLength}},
+				Unique:  true,
+				Global:  true,
+				State:   model.StatePublic,
+			},
+		},
+		Partition: &model.PartitionInfo{
+			Type:        model.PartitionTypeHash,
+			Expr:        "`a`",
+			Enable:      true,
+			Num:         2,
+			Definitions: []model.PartitionDefinition{{ID: 11, Name: model.NewCIStr("p0")}, {ID: 12, Name: model.NewCIStr("p1")}},
+		},
+		State: model.StatePublic,
+	}
+	tbl, err := tables.TableFromMeta(lkv.NewPanickingAllocators(0), tblInfo)
+	require.NoError(t, err)
+
+	encoder, err := lkv.NewTableKVEncoder(&encode.EncodingConfig{
+		Table: tbl,
+		SessionOptions: encode.SessionOptions{
+			SQLMode:   mysql.ModeStrictAllTables,
+			Timestamp: 1234567890,
+		},
+		Logger: log.L(),
+	}, nil)
+	require.NoError(t, err)
+	pairs, err := encoder.Encode([]types.Datum{types.NewIntDatum(3), types.NewIntDatum(5)}, 1, []int{0, 1, -1}, 123)
+	require.NoError(t, err)
+	data := fromRow(pairs)
+	require.Len(t, data.pairs, 2)
+	// The row is in the partition p1, while the global index is stored with the table ID.
+	require.Equal(t, int64(12), tablecodec.DecodeTableID(data.pairs[0].Key))
+	require.Equal(t, int64(1), tablecodec.DecodeTableID(data.pairs[1].Key))
+
+	decoder, err := lkv.NewTableKVDecoder(tbl, "`test`.`t`", &encode.SessionOptions{
+		SQLMode:   mysql.ModeStrictAllTables,
+		Timestamp: 1234567890,
+	}, log.L())
+	require.NoError(t, err)
+	h, err := decoder.DecodeHandleFromIndex(tblInfo.Indices[0], data.pairs[1].Key, data.pairs[1].Val)
+	require.NoError(t, err)
+	require.Equal(t, kv.NewPartitionHandle(12, kv.IntHandle(1)), h)
+}
+
 func TestEncodeRowFormatV2(t *testing.T) {
 	// Test encoding in row format v2, as described in <https://github.com/pingcap/tidb/blob/master/docs/design/2018-07-19-row-format.md>.
 
@@ -539,13 +539,18 @@ func (m *DupeDetector) RecordIndexConflictError(ctx context.Context, stream DupK
 		if err != nil {
 			return errors.Trace(err)
 		}
+		rowTableID := tableID
+		// The handle decoded from a global index contains the partition ID of the row.
+		if ph, ok := h.(tidbkv.PartitionHandle); ok {
+			rowTableID, h = ph.PartitionID, ph.Handle
+		}
 		conflictInfo := errormanager.DataConflictInfo{
 			RawKey:   key,
 			RawValue: val,
 			KeyData:  h.String(),
 		}
 		indexHandles.append(conflictInfo, indexInfo.Name.O,
-			h, tablecodec.EncodeRowKeyWithHandle(tableID, h))
+			h, tablecodec.EncodeRowKeyWithHandle(rowTableID, h))
 
 		if indexHandles.Len() >= defaultRecordConflictErrorBatch {
 			if err := m.saveIndexHandles(ctx, indexHandles); err != nil {
@@ -737,8 +737,8 @@ func EstimateCompactionThreshold(files []mydump.FileInfo, cp *checkpoints.TableC
 			if !ok {
 				size = chunk.FileMeta.FileSize
 			}
-			if chunk.FileMeta.Type == mydump.SourceTypeParquet {
-				// parquet file is compressed, thus estimates with a factor of 2
+			if chunk.FileMeta.Type == mydump.SourceTypeParquet || chunk.FileMeta.Type == mydump.SourceTypeORC {
+				// parquet and orc files are compressed, thus estimates with a factor of 2
 				size *= 2
 			}
 			totalRawFileSize += size
@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/keyspace"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/store/driver/txn"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
@@ -109,6 +110,15 @@ func openParser(
 		if err != nil {
 			return nil, err
 		}
+	case mydump.SourceTypeJSONLines:
+		jsonParser := mydump.NewJSONLinesParser(ctx, reader, blockBufSize, ioWorkers, jsonLinesColumns(tblInfo))
+		jsonParser.SetJSONColumns(jsonTypedColumns(tblInfo))
+		parser = jsonParser
+	case mydump.SourceTypeORC:
+		parser, err = mydump.NewORCParser(ctx, reader)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.Errorf("file '%s' with unknown source type '%s'", chunk.Key.Path, chunk.FileMeta.Type.String())
 	}
@@ -132,6 +142,47 @@ func openParser(
 	return parser, nil
 }
 
+// jsonLinesColumns returns the columns of the JSON Lines files of the table, the
+// fields of the JSON objects are mapped to the non-generated columns by name.
+func jsonLinesColumns(tblInfo *model.TableInfo) []string {
+	columns := make([]string, 0, len(tblInfo.Columns))
+	for _, col := range tblInfo.Columns {
+		if col.IsGenerated() {
+			continue
+		}
+		columns = append(columns, col.Name.L)
+	}
+	return columns
+}
+
+// jsonTypedColumns returns the names of the JSON columns of the table, whose
+// values in the JSON Lines files are imported as JSON text.
+func jsonTypedColumns(tblInfo *model.TableInfo) []string {
+	var columns []string
+	for _, col := range tblInfo.Columns {
+		if col.GetType() == mysql.TypeJSON {
+			columns = append(columns, col.Name.L)
+		}
+	}
+	return columns
+}
+
+// rowColumnPermutation returns the column permutation of a row. The values
+// missing in the row are mapped to -1, so they're filled with the default values.
+func rowColumnPermutation(permutation []int, missing []bool) []int {
+	if len(missing) == 0 {
+		return permutation
+	}
+	perm := make([]int, len(permutation))
+	for i, idx := range permutation {
+		if idx >= 0 && idx < len(missing) && missing[idx] {
+			idx = -1
+		}
+		perm[i] = idx
+	}
+	return perm
+}
+
 func getColumnNames(tableInfo *model.TableInfo, permutation []int) []string {
 	colIndexes := make([]int, 0, len(permutation))
 	for i := 0; i < len(permutation); i++ {
@@ -344,7 +395,8 @@ func (cr *chunkProcessor) encodeLoop(
 			err = cr.parser.ReadRow()
 			columnNames := cr.parser.Columns()
 			newOffset, rowID = cr.parser.Pos()
-			if cr.chunk.FileMeta.Compression != mydump.CompressionNone || cr.chunk.FileMeta.Type == mydump.SourceTypeParquet {
+			if cr.chunk.FileMeta.Compression != mydump.CompressionNone || cr.chunk.FileMeta.Type == mydump.SourceTypeParquet ||
+				cr.chunk.FileMeta.Type == mydump.SourceTypeORC {
 				newScannedOffset, scannedOffsetErr = cr.parser.ScannedPos()
 				if scannedOffsetErr != nil {
 					logger.Warn("fail to get data engine ScannedPos, progress may not be accurate",
@@ -395,6 +447,7 @@ func (cr *chunkProcessor) encodeLoop(
 			readDur += time.Since(readDurStart)
 			encodeDurStart := time.Now()
 			lastRow := cr.parser.LastRow()
+			columnPermutation := rowColumnPermutation(cr.chunk.ColumnPermutation, lastRow.Missing)
 			lastRow.Row = append(lastRow.Row, extendVals...)
 
 			// Skip duplicated rows.
@@ -434,7 +487,7 @@ func (cr *chunkProcessor) encodeLoop(
 						t.tableInfo.Desired,
 						logger,
 					)
-					rowText := tidb.EncodeRowForRecord(ctx, t.encTable, rc.cfg.TiDB.SQLMode, lastRow.Row, cr.chunk.ColumnPermutation)
+					rowText := tidb.EncodeRowForRecord(ctx, t.encTable, rc.cfg.TiDB.SQLMode, lastRow.Row, columnPermutation)
 					err = rc.errorMgr.RecordDuplicate(
 						ctx,
 						logger,
@@ -453,12 +506,12 @@ func (cr *chunkProcessor) encodeLoop(
 			}
 
 			// sql -> kv
-			kvs, encodeErr := kvEncoder.Encode(lastRow.Row, lastRow.RowID, cr.chunk.ColumnPermutation, curOffset)
+			kvs, encodeErr := kvEncoder.Encode(lastRow.Row, lastRow.RowID, columnPermutation, curOffset)
 			encodeDur += time.Since(encodeDurStart)
 
 			hasIgnoredEncodeErr := false
 			if encodeErr != nil {
-				rowText := tidb.EncodeRowForRecord(ctx, t.encTable, rc.cfg.TiDB.SQLMode, lastRow.Row, cr.chunk.ColumnPermutation)
+				rowText := tidb.EncodeRowForRecord(ctx, t.encTable, rc.cfg.TiDB.SQLMode, lastRow.Row, columnPermutation)
 				encodeErr = rc.errorMgr.RecordTypeError(ctx, logger, t.tableName, cr.chunk.Key.Path, newOffset, rowText, encodeErr)
 				if encodeErr != nil {
 					err = common.ErrEncodeKV.Wrap(encodeErr).GenWithStackByArgs(&cr.chunk.Key, newOffset)
@@ -494,7 +547,7 @@ func (cr *chunkProcessor) encodeLoop(
 		if m, ok := metric.FromContext(ctx); ok {
 			m.RowEncodeSecondsHistogram.Observe(encodeDur.Seconds())
 			m.RowReadSecondsHistogram.Observe(readDur.Seconds())
-			if cr.chunk.FileMeta.Type == mydump.SourceTypeParquet {
+			if cr.chunk.FileMeta.Type == mydump.SourceTypeParquet || cr.chunk.FileMeta.Type == mydump.SourceTypeORC {
 				m.RowReadBytesHistogram.Observe(float64(newScannedOffset - scannedOffset))
 			} else {
 				m.RowReadBytesHistogram.Observe(float64(newOffset - offset))
@@ -531,7 +584,7 @@ func (cr *chunkProcessor) getDuplicateMessage(
 	if err != nil {
 		return err.Error()
 	}
-	kvs, err := kvEncoder.Encode(lastRow.Row, lastRow.RowID, cr.chunk.ColumnPermutation, lastOffset)
+	kvs, err := kvEncoder.Encode(lastRow.Row, lastRow.RowID, rowColumnPermutation(cr.chunk.ColumnPermutation, lastRow.Missing), lastOffset)
 	if err != nil {
 		return err.Error()
 	}
@@ -711,7 +764,7 @@ func (cr *chunkProcessor) deliverLoop(
 			}
 			delta := highOffset - lowOffset
 			if delta >= 0 {
-				if cr.chunk.FileMeta.Type == mydump.SourceTypeParquet {
+				if cr.chunk.FileMeta.Type == mydump.SourceTypeParquet || cr.chunk.FileMeta.Type == mydump.SourceTypeORC {
 					if currRealOffset > startRealOffset {
 						m.BytesCounter.WithLabelValues(metric.StateRestored).Add(float64(currRealOffset - startRealOffset))
 					}
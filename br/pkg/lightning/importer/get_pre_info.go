@@ -487,6 +487,15 @@ func (p *PreImportInfoGetterImpl) ReadFirstNRowsByFileMeta(ctx context.Context,
 		if err != nil {
 			return nil, nil, errors.Trace(err)
 		}
+	case mydump.SourceTypeJSONLines:
+		// the columns are inferred from the first object, so they can be
+		// checked against the target table.
+		parser = mydump.NewJSONLinesParser(ctx, reader, blockBufSize, p.ioWorkers, nil)
+	case mydump.SourceTypeORC:
+		parser, err = mydump.NewORCParser(ctx, reader)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
 	default:
 		panic(fmt.Sprintf("unknown file type '%s'", dataFileMeta.Type))
 	}
@@ -655,6 +664,13 @@ func (p *PreImportInfoGetterImpl) sampleDataFromTable(
 		if err != nil {
 			return 0.0, false, errors.Trace(err)
 		}
+	case mydump.SourceTypeJSONLines:
+		parser = mydump.NewJSONLinesParser(ctx, reader, blockBufSize, p.ioWorkers, jsonLinesColumns(tableInfo))
+	case mydump.SourceTypeORC:
+		parser, err = mydump.NewORCParser(ctx, reader)
+		if err != nil {
+			return 0.0, false, errors.Trace(err)
+		}
 	default:
 		panic(fmt.Sprintf("file '%s' with unknown source type '%s'", sampleFile.Path, sampleFile.Type.String()))
 	}
@@ -1010,7 +1010,9 @@ func (ci *schemaCheckItem) SchemaIsValid(ctx context.Context, tableInfo *mydump.
 	// get columns name from data file.
 	dataFileMeta := dataFile.FileMeta
 
-	if tp := dataFileMeta.Type; tp != mydump.SourceTypeCSV && tp != mydump.SourceTypeSQL && tp != mydump.SourceTypeParquet {
+	switch dataFileMeta.Type {
+	case mydump.SourceTypeCSV, mydump.SourceTypeSQL, mydump.SourceTypeParquet, mydump.SourceTypeJSONLines, mydump.SourceTypeORC:
+	default:
 		msgs = append(msgs, fmt.Sprintf("file '%s' with unknown source type '%s'", dataFileMeta.Path, dataFileMeta.Type.String()))
 		return msgs, nil
 	}
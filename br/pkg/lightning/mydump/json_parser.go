@@ -0,0 +1,279 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mydump
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/lightning/log"
+	"github.com/pingcap/tidb/br/pkg/lightning/metric"
+	"github.com/pingcap/tidb/br/pkg/lightning/worker"
+	"github.com/pingcap/tidb/types"
+	"go.uber.org/zap"
+)
+
+// JSONLinesParser is a parser for JSON Lines files, in which each line is a JSON
+// object representing a row. The fields of the object are mapped to the columns
+// by name case-insensitively, the missing fields are marked in Row.Missing and the
+// unknown fields are ignored. Nested objects and arrays are kept as JSON text, so they can be
+// imported into JSON columns. The values of the JSON columns set by SetJSONColumns are always
+// kept as JSON text, e.g. `true` and `"a"` are imported as the JSON boolean and string.
+type JSONLinesParser struct {
+	blockParser
+
+	// colIndexes maps the lower-case column names to the index in the row.
+	colIndexes map[string]int
+	// jsonColumns are the lower-case names of the JSON columns.
+	jsonColumns map[string]struct{}
+	// isJSONColumn marks the JSON columns of the rows.
+	isJSONColumn []bool
+	fields       []jsonField
+	// present marks the columns which exist in the last object.
+	present []bool
+}
+
+type jsonField struct {
+	name  string
+	value json.RawMessage
+}
+
+// NewJSONLinesParser creates a JSON Lines parser. The `columns` are the
+// _lower-case_ column names of the rows. If `columns` is empty, the columns are
+// inferred from the fields of the first object.
+func NewJSONLinesParser(
+	ctx context.Context,
+	reader ReadSeekCloser,
+	blockBufSize int64,
+	ioWorkers *worker.Pool,
+	columns []string,
+) *JSONLinesParser {
+	metrics, _ := metric.FromContext(ctx)
+	parser := &JSONLinesParser{
+		blockParser: makeBlockParser(reader, blockBufSize, ioWorkers, metrics, log.FromContext(ctx)),
+	}
+	parser.SetColumns(columns)
+	return parser
+}
+
+// SetColumns sets the column names of the rows.
+func (parser *JSONLinesParser) SetColumns(columns []string) {
+	parser.columns = columns
+	parser.colIndexes = make(map[string]int, len(columns))
+	for i, col := range columns {
+		parser.colIndexes[col] = i
+	}
+	parser.markJSONColumns()
+}
+
+// SetJSONColumns sets the _lower-case_ names of the JSON columns, whose values are
+// kept as JSON text instead of being converted to the scalar values.
+func (parser *JSONLinesParser) SetJSONColumns(jsonColumns []string) {
+	parser.jsonColumns = make(map[string]struct{}, len(jsonColumns))
+	for _, col := range jsonColumns {
+		parser.jsonColumns[col] = struct{}{}
+	}
+	parser.markJSONColumns()
+}
+
+func (parser *JSONLinesParser) markJSONColumns() {
+	parser.isJSONColumn = parser.isJSONColumn[:0]
+	for _, col := range parser.columns {
+		_, ok := parser.jsonColumns[col]
+		parser.isJSONColumn = append(parser.isJSONColumn, ok)
+	}
+}
+
+// readLine reads the next line without the line terminator, it returns io.EOF if
+// there is no more line. `n` is the length of the line in the file, which
+// includes the line terminator.
+func (parser *JSONLinesParser) readLine() (line []byte, n int, err error) {
+	for {
+		if i := bytes.IndexByte(parser.buf, '\n'); i >= 0 {
+			line = parser.buf[:i]
+			parser.buf = parser.buf[i+1:]
+			return line, i + 1, nil
+		}
+		if parser.isLastChunk {
+			if len(parser.buf) == 0 {
+				return nil, 0, io.EOF
+			}
+			line = parser.buf
+			parser.buf = parser.buf[len(parser.buf):]
+			return line, len(line), nil
+		}
+		if err = parser.readBlock(); err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+// ReadRow reads a row from the data file.
+func (parser *JSONLinesParser) ReadRow() error {
+	for {
+		line, n, err := parser.readLine()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		offset := parser.pos
+		parser.pos += int64(n)
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if err = parser.parseObject(line); err != nil {
+			content := line
+			if len(content) > 256 {
+				content = content[:256]
+			}
+			parser.Logger.Error("syntax error", zap.Int64("pos", offset), zap.ByteString("content", content))
+			return errors.Errorf("syntax error: invalid JSON object at offset %d: %s", offset, err.Error())
+		}
+
+		if len(parser.columns) == 0 {
+			parser.inferColumns()
+		}
+
+		row := &parser.lastRow
+		row.RowID++
+		row.Length = len(line)
+		row.Row = parser.acquireDatumSlice()
+		parser.present = parser.present[:0]
+		for range parser.columns {
+			row.Row = append(row.Row, types.Datum{})
+			parser.present = append(parser.present, false)
+		}
+		presentCnt := 0
+		for _, field := range parser.fields {
+			if i, ok := parser.colIndexes[strings.ToLower(field.name)]; ok {
+				if err = setJSONDatum(&row.Row[i], field.value, parser.isJSONColumn[i]); err != nil {
+					return errors.Annotatef(err, "invalid value of field '%s' at offset %d", field.name, offset)
+				}
+				if !parser.present[i] {
+					parser.present[i] = true
+					presentCnt++
+				}
+			}
+		}
+		row.Missing = nil
+		if presentCnt < len(parser.columns) {
+			row.Missing = make([]bool, 0, len(parser.present))
+			for _, ok := range parser.present {
+				row.Missing = append(row.Missing, !ok)
+			}
+		}
+		return nil
+	}
+}
+
+// parseObject parses the fields of a JSON object in their order.
+func (parser *JSONLinesParser) parseObject(line []byte) error {
+	parser.fields = parser.fields[:0]
+	dec := json.NewDecoder(bytes.NewReader(line))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return errors.New("each line should be a JSON object")
+	}
+	for dec.More() {
+		if tok, err = dec.Token(); err != nil {
+			return err
+		}
+		// keys of object are always strings
+		//nolint: forcetypeassert
+		field := jsonField{name: tok.(string)}
+		if err = dec.Decode(&field.value); err != nil {
+			return err
+		}
+		parser.fields = append(parser.fields, field)
+	}
+	if _, err = dec.Token(); err != nil {
+		return err
+	}
+	if _, err = dec.Token(); err != io.EOF {
+		return errors.New("unexpected content after the JSON object")
+	}
+	return nil
+}
+
+// inferColumns uses the fields of the last object as the columns.
+func (parser *JSONLinesParser) inferColumns() {
+	columns := make([]string, 0, len(parser.fields))
+	seen := make(map[string]struct{}, len(parser.fields))
+	for _, field := range parser.fields {
+		name := strings.ToLower(field.name)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		columns = append(columns, name)
+	}
+	parser.SetColumns(columns)
+}
+
+func setJSONDatum(d *types.Datum, value json.RawMessage, isJSONColumn bool) error {
+	switch {
+	case value[0] == 'n':
+		d.SetNull()
+		return nil
+	case isJSONColumn:
+		// Keep the JSON text, so the booleans and strings are not changed.
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, value); err != nil {
+			return errors.Trace(err)
+		}
+		d.SetString(buf.String(), "utf8mb4_bin")
+		return nil
+	}
+	switch value[0] {
+	case 't':
+		d.SetInt64(1)
+	case 'f':
+		d.SetInt64(0)
+	case '"':
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return errors.Trace(err)
+		}
+		d.SetString(s, "utf8mb4_bin")
+	case '{', '[':
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, value); err != nil {
+			return errors.Trace(err)
+		}
+		d.SetString(buf.String(), "utf8mb4_bin")
+	default:
+		// number, keep the integers as int and others as string to avoid
+		// losing precision.
+		s := string(value)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			d.SetInt64(i)
+		} else if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			d.SetUint64(u)
+		} else {
+			d.SetString(s, "utf8mb4_bin")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mydump_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLinesParser(t *testing.T) {
+	input := `{"id": 1, "Name": "a\"b", "score": 1.5, "ok": true, "tags": ["x", {"y": 1}], "extra": 1}
+
+{"name": null, "id": 18446744073709551615, "ok": false, "tags": {"z" : [1, 2]}}
+{"id": -2}`
+	for _, blockBufSize := range []int64{1, 4, 1024} {
+		parser := mydump.NewJSONLinesParser(context.Background(), mydump.NewStringReader(input), blockBufSize, ioWorkersForCSV,
+			[]string{"id", "name", "score", "ok", "tags"})
+
+		require.NoError(t, parser.ReadRow())
+		require.Equal(t, mydump.Row{
+			RowID: 1,
+			Row: []types.Datum{
+				types.NewIntDatum(1),
+				types.NewCollationStringDatum(`a"b`, "utf8mb4_bin"),
+				types.NewCollationStringDatum("1.5", "utf8mb4_bin"),
+				types.NewIntDatum(1),
+				types.NewCollationStringDatum(`["x",{"y":1}]`, "utf8mb4_bin"),
+			},
+			Length: 88,
+		}, parser.LastRow())
+		pos, rowID := parser.Pos()
+		require.Equal(t, int64(89), pos)
+		require.Equal(t, int64(1), rowID)
+
+		require.NoError(t, parser.ReadRow())
+		require.Equal(t, mydump.Row{
+			RowID: 2,
+			Row: []types.Datum{
+				types.NewUintDatum(18446744073709551615),
+				{},
+				{},
+				types.NewIntDatum(0),
+				types.NewCollationStringDatum(`{"z":[1,2]}`, "utf8mb4_bin"),
+			},
+			Length: 79,
+			// the explicit null of "name" is not missing
+			Missing: []bool{false, false, true, false, false},
+		}, parser.LastRow())
+
+		require.NoError(t, parser.ReadRow())
+		require.Equal(t, []types.Datum{types.NewIntDatum(-2), {}, {}, {}, {}}, parser.LastRow().Row)
+		require.Equal(t, []bool{false, true, true, true, true}, parser.LastRow().Missing)
+		pos, _ = parser.Pos()
+		require.Equal(t, int64(len(input)), pos)
+
+		require.ErrorIs(t, errors.Cause(parser.ReadRow()), io.EOF)
+		require.NoError(t, parser.Close())
+	}
+}
+
+func TestJSONLinesParserJSONColumns(t *testing.T) {
+	input := `{"id": 1, "j": {"a": true, "b": [1, "x"]}}
+{"id": 2, "j": true}
+{"id": 3, "j": "a\"b"}
+{"id": 4, "j": 1.50}
+{"id": 5, "j": null}
+{"id": false, "j": false}`
+	parser := mydump.NewJSONLinesParser(context.Background(), mydump.NewStringReader(input), 1024, ioWorkersForCSV,
+		[]string{"id", "j"})
+	parser.SetJSONColumns([]string{"j"})
+	// the JSON columns are kept after the columns are reordered.
+	parser.SetColumns([]string{"j", "id"})
+	expected := [][]types.Datum{
+		{types.NewCollationStringDatum(`{"a":true,"b":[1,"x"]}`, "utf8mb4_bin"), types.NewIntDatum(1)},
+		{types.NewCollationStringDatum(`true`, "utf8mb4_bin"), types.NewIntDatum(2)},
+		{types.NewCollationStringDatum(`"a\"b"`, "utf8mb4_bin"), types.NewIntDatum(3)},
+		{types.NewCollationStringDatum(`1.50`, "utf8mb4_bin"), types.NewIntDatum(4)},
+		{{}, types.NewIntDatum(5)},
+		{types.NewCollationStringDatum(`false`, "utf8mb4_bin"), types.NewIntDatum(0)},
+	}
+	for _, row := range expected {
+		require.NoError(t, parser.ReadRow())
+		require.Equal(t, row, parser.LastRow().Row)
+	}
+	require.ErrorIs(t, errors.Cause(parser.ReadRow()), io.EOF)
+	require.NoError(t, parser.Close())
+}
+
+func TestJSONLinesParserInferColumns(t *testing.T) {
+	input := "{\"ID\": 1, \"b\": \"x\", \"id\": 2}\n{\"b\": \"y\", \"c\": 3}\n"
+	parser := mydump.NewJSONLinesParser(context.Background(), mydump.NewStringReader(input), 1024, ioWorkersForCSV, nil)
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, []string{"id", "b"}, parser.Columns())
+	// the later field with the same name overrides the former one.
+	require.Equal(t, []types.Datum{types.NewIntDatum(2), types.NewCollationStringDatum("x", "utf8mb4_bin")}, parser.LastRow().Row)
+	require.Nil(t, parser.LastRow().Missing)
+
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, []types.Datum{{}, types.NewCollationStringDatum("y", "utf8mb4_bin")}, parser.LastRow().Row)
+	require.Equal(t, []bool{true, false}, parser.LastRow().Missing)
+	require.ErrorIs(t, errors.Cause(parser.ReadRow()), io.EOF)
+}
+
+func TestJSONLinesParserSetPos(t *testing.T) {
+	input := "{\"a\": 1}\n{\"a\": 2}\n{\"a\": 3}\n"
+	parser := mydump.NewJSONLinesParser(context.Background(), mydump.NewStringReader(input), 1024, ioWorkersForCSV, []string{"a"})
+	require.NoError(t, parser.SetPos(9, 100))
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, mydump.Row{RowID: 101, Row: []types.Datum{types.NewIntDatum(2)}, Length: 8}, parser.LastRow())
+}
+
+func TestJSONLinesParserError(t *testing.T) {
+	for _, input := range []string{
+		`{"a": 1`,
+		`[1, 2]`,
+		`{"a": 1} {"a": 2}`,
+		`"a"`,
+		`{"a": tru}`,
+	} {
+		parser := mydump.NewJSONLinesParser(context.Background(), mydump.NewStringReader(input), 1024, ioWorkersForCSV, []string{"a"})
+		require.Regexp(t, "syntax error: invalid JSON object at offset 0.*", parser.ReadRow().Error(), "input = %q", input)
+	}
+}
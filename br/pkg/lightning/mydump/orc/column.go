@@ -0,0 +1,581 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// JSON is the JSON text of a value of the compound types, i.e. struct, list
+// and map. Fields of struct are kept in the order of the schema, and map is
+// converted to a JSON object whose keys are the string form of the map keys.
+type JSON []byte
+
+const (
+	// DateLayout is the layout used to format the values of date type in JSON.
+	DateLayout = "2006-01-02"
+	// TimestampLayout is the layout used to format the values of timestamp types in JSON.
+	TimestampLayout = "2006-01-02 15:04:05.999999999"
+)
+
+// columnReader reads the values of a column in a stripe. `next` returns nil for
+// null values, and the other values are:
+//
+//   - boolean: bool
+//   - tinyint, smallint, int, bigint: int64
+//   - float: float32
+//   - double: float64
+//   - string, varchar, char: string
+//   - binary: []byte
+//   - decimal: string
+//   - date, timestamp: time.Time
+//   - struct, list, map: JSON
+type columnReader interface {
+	next() (interface{}, error)
+}
+
+// streamKey locates a stream in a stripe.
+type streamKey struct {
+	column uint32
+	kind   streamKind
+}
+
+// stripeStreams holds the decompressed streams and the encodings of a stripe.
+type stripeStreams struct {
+	types     []*Type
+	streams   map[streamKey]*byteStream
+	encodings []columnEncoding
+	location  *time.Location
+}
+
+// stream returns the stream of the column. Writers may omit the empty streams,
+// e.g. when all values of the column are null, so an empty stream is returned
+// if the stream is not found.
+func (s *stripeStreams) stream(column uint32, kind streamKind) *byteStream {
+	if st, ok := s.streams[streamKey{column: column, kind: kind}]; ok {
+		return st
+	}
+	return newByteStream(nil)
+}
+
+func (s *stripeStreams) encoding(column uint32) encodingKind {
+	if int(column) < len(s.encodings) {
+		return s.encodings[column].kind
+	}
+	return encodingDirect
+}
+
+// newPresent returns nil if the column doesn't have PRESENT stream, which means
+// all values are not null.
+func (s *stripeStreams) newPresent(column uint32) *boolReader {
+	if st, ok := s.streams[streamKey{column: column, kind: streamPresent}]; ok {
+		return newBoolReader(st)
+	}
+	return nil
+}
+
+func (s *stripeStreams) buildColumnReader(column uint32) (columnReader, error) {
+	if int(column) >= len(s.types) {
+		return nil, errors.Errorf("orc column %d is out of range", column)
+	}
+	tp := s.types[column]
+	present := s.newPresent(column)
+	encoding := s.encoding(column)
+
+	switch tp.Kind {
+	case KindBoolean:
+		data := s.stream(column, streamData)
+		return &boolColumnReader{present: present, data: newBoolReader(data)}, nil
+	case KindByte:
+		data := s.stream(column, streamData)
+		return &byteColumnReader{present: present, data: newByteRLEReader(data)}, nil
+	case KindShort, KindInt, KindLong:
+		data := s.stream(column, streamData)
+		return &intColumnReader{present: present, data: newIntReader(data, encoding, true)}, nil
+	case KindFloat, KindDouble:
+		data := s.stream(column, streamData)
+		return &floatColumnReader{present: present, data: data, isDouble: tp.Kind == KindDouble}, nil
+	case KindString, KindVarchar, KindChar, KindBinary:
+		return s.buildStringColumnReader(column, present, encoding, tp.Kind == KindBinary)
+	case KindDecimal:
+		data := s.stream(column, streamData)
+		scale := s.stream(column, streamSecondary)
+		return &decimalColumnReader{present: present, data: data, scale: newIntReader(scale, encoding, true)}, nil
+	case KindDate:
+		data := s.stream(column, streamData)
+		return &dateColumnReader{present: present, data: newIntReader(data, encoding, true)}, nil
+	case KindTimestamp, KindTimestampInstant:
+		data := s.stream(column, streamData)
+		nanos := s.stream(column, streamSecondary)
+		location := time.UTC
+		if tp.Kind == KindTimestamp && s.location != nil {
+			location = s.location
+		}
+		return &timestampColumnReader{
+			present:  present,
+			seconds:  newIntReader(data, encoding, true),
+			nanos:    newIntReader(nanos, encoding, false),
+			base:     time.Date(2015, 1, 1, 0, 0, 0, 0, location).Unix(),
+			location: location,
+		}, nil
+	case KindStruct:
+		children := make([]columnReader, 0, len(tp.Subtypes))
+		for _, sub := range tp.Subtypes {
+			child, err := s.buildColumnReader(sub)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+		return &structColumnReader{present: present, tp: tp, types: s.types, children: children}, nil
+	case KindList, KindMap:
+		length := s.stream(column, streamLength)
+		expected := 1
+		if tp.Kind == KindMap {
+			expected = 2
+		}
+		if len(tp.Subtypes) != expected {
+			return nil, errors.Errorf("orc %s column %d should have %d subtypes, but got %d", tp.Kind, column, expected, len(tp.Subtypes))
+		}
+		children := make([]columnReader, 0, len(tp.Subtypes))
+		for _, sub := range tp.Subtypes {
+			child, err := s.buildColumnReader(sub)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+		return &listColumnReader{
+			present:  present,
+			length:   newIntReader(length, encoding, false),
+			tp:       tp,
+			types:    s.types,
+			children: children,
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported orc type %s of column %d", tp.Kind, column)
+	}
+}
+
+func (s *stripeStreams) buildStringColumnReader(
+	column uint32,
+	present *boolReader,
+	encoding encodingKind,
+	isBinary bool,
+) (columnReader, error) {
+	data := s.stream(column, streamData)
+	lengthStream := s.stream(column, streamLength)
+	length := newIntReader(lengthStream, encoding, false)
+
+	if encoding != encodingDictionary && encoding != encodingDictionaryV2 {
+		return &stringColumnReader{present: present, data: data, length: length, isBinary: isBinary}, nil
+	}
+
+	dictData := s.stream(column, streamDictionaryData)
+	size := int(s.encodings[column].dictionarySize)
+	dict := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		l, err := length.next()
+		if err != nil {
+			return nil, errors.Annotatef(err, "read dictionary of orc column %d", column)
+		}
+		b, err := dictData.readBytes(int(l))
+		if err != nil {
+			return nil, errors.Annotatef(err, "read dictionary of orc column %d", column)
+		}
+		dict = append(dict, string(b))
+	}
+	return &dictColumnReader{present: present, index: newIntReader(data, encoding, false), dict: dict}, nil
+}
+
+// isNull reads the PRESENT stream of a column.
+func isNull(present *boolReader) (bool, error) {
+	if present == nil {
+		return false, nil
+	}
+	ok, err := present.next()
+	return !ok, err
+}
+
+type boolColumnReader struct {
+	present *boolReader
+	data    *boolReader
+}
+
+func (r *boolColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	return r.data.next()
+}
+
+type byteColumnReader struct {
+	present *boolReader
+	data    *byteRLEReader
+}
+
+func (r *byteColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	b, err := r.data.next()
+	return int64(int8(b)), err
+}
+
+type intColumnReader struct {
+	present *boolReader
+	data    intReader
+}
+
+func (r *intColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	return r.data.next()
+}
+
+type floatColumnReader struct {
+	present  *boolReader
+	data     *byteStream
+	isDouble bool
+}
+
+func (r *floatColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	if r.isDouble {
+		b, err := r.data.readBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	}
+	b, err := r.data.readBytes(4)
+	if err != nil {
+		return nil, err
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+}
+
+type stringColumnReader struct {
+	present  *boolReader
+	data     *byteStream
+	length   intReader
+	isBinary bool
+}
+
+func (r *stringColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	l, err := r.length.next()
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.data.readBytes(int(l))
+	if err != nil {
+		return nil, err
+	}
+	if r.isBinary {
+		return append([]byte{}, b...), nil
+	}
+	return string(b), nil
+}
+
+type dictColumnReader struct {
+	present *boolReader
+	index   intReader
+	dict    []string
+}
+
+func (r *dictColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	idx, err := r.index.next()
+	if err != nil {
+		return nil, err
+	}
+	if idx < 0 || int(idx) >= len(r.dict) {
+		return nil, errors.Errorf("orc dictionary index %d out of range [0, %d)", idx, len(r.dict))
+	}
+	return r.dict[idx], nil
+}
+
+type decimalColumnReader struct {
+	present *boolReader
+	data    *byteStream
+	scale   intReader
+}
+
+func (r *decimalColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+
+	// the unscaled value is an unbounded zigzag varint.
+	var (
+		x     big.Int
+		shift uint
+		tmp   big.Int
+	)
+	for {
+		b, err := r.data.readByte()
+		if err != nil {
+			return nil, err
+		}
+		tmp.SetUint64(uint64(b & 0x7f))
+		x.Or(&x, tmp.Lsh(&tmp, shift))
+		if b < 0x80 {
+			break
+		}
+		shift += 7
+	}
+	negative := x.Bit(0) == 1
+	x.Rsh(&x, 1)
+	if negative {
+		x.Neg(&x)
+		x.Sub(&x, big.NewInt(1))
+	}
+
+	scale, err := r.scale.next()
+	if err != nil {
+		return nil, err
+	}
+	return formatDecimal(&x, int(scale)), nil
+}
+
+func formatDecimal(x *big.Int, scale int) string {
+	s := x.String()
+	if scale <= 0 {
+		if x.Sign() == 0 {
+			return "0"
+		}
+		for ; scale < 0; scale++ {
+			s += "0"
+		}
+		return s
+	}
+
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	for len(s) <= scale {
+		s = "0" + s
+	}
+	return sign + s[:len(s)-scale] + "." + s[len(s)-scale:]
+}
+
+type dateColumnReader struct {
+	present *boolReader
+	data    intReader
+}
+
+func (r *dateColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	days, err := r.data.next()
+	if err != nil {
+		return nil, err
+	}
+	return time.Unix(days*24*60*60, 0).UTC(), nil
+}
+
+type timestampColumnReader struct {
+	present *boolReader
+	seconds intReader
+	nanos   intReader
+	// base is the unix seconds of 2015-01-01 00:00:00 in the location.
+	base     int64
+	location *time.Location
+}
+
+func (r *timestampColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	seconds, err := r.seconds.next()
+	if err != nil {
+		return nil, err
+	}
+	encodedNanos, err := r.nanos.next()
+	if err != nil {
+		return nil, err
+	}
+
+	// the lowest 3 bits are the count of trailing zeros which are removed.
+	nanos := uint64(encodedNanos) >> 3
+	if zeros := encodedNanos & 0x07; zeros != 0 {
+		for i := int64(0); i <= zeros; i++ {
+			nanos *= 10
+		}
+	}
+	seconds += r.base
+	// the writer truncates the seconds towards zero, adjust the negative seconds.
+	if seconds < 0 && nanos > 999999 {
+		seconds--
+	}
+	return time.Unix(seconds, int64(nanos)).In(r.location), nil
+}
+
+type structColumnReader struct {
+	present  *boolReader
+	tp       *Type
+	types    []*Type
+	children []columnReader
+	buf      []byte
+}
+
+func (r *structColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	r.buf = append(r.buf[:0], '{')
+	for i, child := range r.children {
+		v, err := child.next()
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			r.buf = append(r.buf, ',')
+		}
+		name := strconv.Itoa(i)
+		if i < len(r.tp.FieldNames) {
+			name = r.tp.FieldNames[i]
+		}
+		r.buf = appendJSONString(r.buf, name)
+		r.buf = append(r.buf, ':')
+		r.buf = appendJSONValue(r.buf, r.types[r.tp.Subtypes[i]].Kind, v)
+	}
+	r.buf = append(r.buf, '}')
+	return JSON(append([]byte{}, r.buf...)), nil
+}
+
+// listColumnReader reads the list and map columns. A map column has two
+// children for the keys and the values.
+type listColumnReader struct {
+	present  *boolReader
+	length   intReader
+	tp       *Type
+	types    []*Type
+	children []columnReader
+	buf      []byte
+}
+
+func (r *listColumnReader) next() (interface{}, error) {
+	if null, err := isNull(r.present); err != nil || null {
+		return nil, err
+	}
+	n, err := r.length.next()
+	if err != nil {
+		return nil, err
+	}
+
+	isMap := r.tp.Kind == KindMap
+	if isMap {
+		r.buf = append(r.buf[:0], '{')
+	} else {
+		r.buf = append(r.buf[:0], '[')
+	}
+	for i := int64(0); i < n; i++ {
+		if i > 0 {
+			r.buf = append(r.buf, ',')
+		}
+		v, err := r.children[0].next()
+		if err != nil {
+			return nil, err
+		}
+		if !isMap {
+			r.buf = appendJSONValue(r.buf, r.types[r.tp.Subtypes[0]].Kind, v)
+			continue
+		}
+
+		// JSON object only supports string keys.
+		if s, ok := v.(string); ok {
+			r.buf = appendJSONString(r.buf, s)
+		} else {
+			r.buf = appendJSONString(r.buf, string(appendJSONValue(nil, r.types[r.tp.Subtypes[0]].Kind, v)))
+		}
+		r.buf = append(r.buf, ':')
+		if v, err = r.children[1].next(); err != nil {
+			return nil, err
+		}
+		r.buf = appendJSONValue(r.buf, r.types[r.tp.Subtypes[1]].Kind, v)
+	}
+	if isMap {
+		r.buf = append(r.buf, '}')
+	} else {
+		r.buf = append(r.buf, ']')
+	}
+	return JSON(append([]byte{}, r.buf...)), nil
+}
+
+func appendJSONString(buf []byte, s string) []byte {
+	// json.Marshal never fails for string.
+	//nolint: errchkjson
+	b, _ := json.Marshal(s)
+	return append(buf, b...)
+}
+
+// appendJSONValue appends the JSON text of a value returned by columnReader.
+func appendJSONValue(buf []byte, kind TypeKind, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case bool:
+		return strconv.AppendBool(buf, x)
+	case int64:
+		return strconv.AppendInt(buf, x, 10)
+	case float32:
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return append(buf, "null"...)
+		}
+		return strconv.AppendFloat(buf, float64(x), 'g', -1, 32)
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return append(buf, "null"...)
+		}
+		return strconv.AppendFloat(buf, x, 'g', -1, 64)
+	case string:
+		if kind == KindDecimal {
+			return append(buf, x...)
+		}
+		return appendJSONString(buf, x)
+	case []byte:
+		// binary is encoded as base64 string like encoding/json does.
+		//nolint: errchkjson
+		b, _ := json.Marshal(x)
+		return append(buf, b...)
+	case time.Time:
+		if kind == KindDate {
+			return appendJSONString(buf, x.Format(DateLayout))
+		}
+		return appendJSONString(buf, x.Format(TimestampLayout))
+	case JSON:
+		return append(buf, x...)
+	default:
+		return appendJSONString(buf, "")
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orc
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// packBits packs the values of `width` bits from the most significant bit.
+func packBits(values []uint64, width int) []byte {
+	var (
+		data     []byte
+		cur      byte
+		bitsUsed int
+	)
+	for _, v := range values {
+		for left := width; left > 0; left-- {
+			cur = cur<<1 | byte(v>>(left-1)&1)
+			bitsUsed++
+			if bitsUsed == 8 {
+				data = append(data, cur)
+				cur, bitsUsed = 0, 0
+			}
+		}
+	}
+	if bitsUsed > 0 {
+		data = append(data, cur<<(8-bitsUsed))
+	}
+	return data
+}
+
+// encodeBitWidth is the reverse of decodeBitWidth.
+func encodeBitWidth(t *testing.T, width int) int {
+	for code := 0; code < 32; code++ {
+		if decodeBitWidth(code) == width {
+			return code
+		}
+	}
+	require.FailNow(t, "invalid bit width", "width %d", width)
+	return 0
+}
+
+func TestIntRLEv2BitWidths(t *testing.T) {
+	for code := 0; code < 32; code++ {
+		width := decodeBitWidth(code)
+		require.Equal(t, width, closestFixedBits(width))
+		require.Equal(t, code, encodeBitWidth(t, width))
+
+		values := []uint64{0, 1, 1<<(width-1) | 1, math.MaxUint64 >> (64 - width)}
+		length := len(values) - 1
+		data := []byte{byte(rleV2Direct<<6 | code<<1 | length>>8), byte(length)}
+		data = append(data, packBits(values, width)...)
+
+		r := newIntReader(newByteStream(data), encodingDirectV2, false)
+		for _, expected := range values {
+			v, err := r.next()
+			require.NoError(t, err)
+			require.Equal(t, int64(expected), v, "width %d", width)
+		}
+		_, err := r.next()
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	}
+
+	cases := []struct {
+		n, expected int
+	}{
+		{0, 1}, {1, 1}, {24, 24}, {25, 26}, {27, 28}, {29, 30}, {31, 32},
+		{33, 40}, {41, 48}, {49, 56}, {57, 64}, {64, 64},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, closestFixedBits(c.n), "n %d", c.n)
+	}
+}
+
+// patchEntry is an entry in the patch list of the PATCHED_BASE sub-encoding.
+type patchEntry struct {
+	gap   uint64
+	patch uint64
+}
+
+func encodePatchedBase(t *testing.T, base int64, width int, values []uint64, patchWidth int, patches []patchEntry) []byte {
+	const baseWidth, patchGapWidth = 2, 8
+	length := len(values) - 1
+	data := []byte{
+		byte(rleV2PatchedBase<<6 | encodeBitWidth(t, width)<<1 | length>>8),
+		byte(length),
+		byte((baseWidth-1)<<5 | encodeBitWidth(t, patchWidth)),
+		byte((patchGapWidth-1)<<5 | len(patches)),
+	}
+	// the base value is stored in sign-magnitude format.
+	magnitude := uint64(base)
+	if base < 0 {
+		magnitude = uint64(-base) | 1<<(baseWidth*8-1)
+	}
+	data = append(data, byte(magnitude>>8), byte(magnitude))
+	data = append(data, packBits(values, width)...)
+
+	entries := make([]uint64, 0, len(patches))
+	for _, p := range patches {
+		entries = append(entries, p.gap<<patchWidth|p.patch)
+	}
+	return append(data, packBits(entries, closestFixedBits(patchWidth+patchGapWidth))...)
+}
+
+func TestIntRLEv2PatchedBase(t *testing.T) {
+	values := make([]uint64, 300)
+	expected := make([]int64, 300)
+	for i := range values {
+		values[i] = uint64(i % 4)
+		expected[i] = -5 + int64(i%4)
+	}
+
+	// the gap 290 is larger than 255, so it is split into 2 entries.
+	data := encodePatchedBase(t, -5, 2, values, 8, []patchEntry{{gap: 255}, {gap: 35, patch: 3}, {gap: 5, patch: 1}})
+	expected[290] = -5 + int64(290%4|3<<2)
+	expected[295] = -5 + int64(295%4|1<<2)
+	r := newIntReader(newByteStream(data), encodingDirectV2, false)
+	require.Equal(t, expected, readInts(t, r, len(expected)))
+	_, err := r.next()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// a positive base with the patch on the first value
+	data = encodePatchedBase(t, 1000, 2, values[:4], 8, []patchEntry{{gap: 0, patch: 0xff}})
+	r = newIntReader(newByteStream(data), encodingDirectV2, false)
+	require.Equal(t, []int64{1000 + 0xff<<2, 1001, 1002, 1003}, readInts(t, r, 4))
+
+	// a run without patches is invalid
+	data = encodePatchedBase(t, 1, 2, values[:4], 8, nil)
+	r = newIntReader(newByteStream(data), encodingDirectV2, false)
+	_, err = r.next()
+	require.ErrorContains(t, err, "invalid orc patched base run without patches")
+}
+
+func TestIntReaderTruncated(t *testing.T) {
+	runs := [][]byte{
+		{0x0a, 0x27, 0x10},
+		{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef},
+		{
+			0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46, 0x50, 0x5a,
+			0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8,
+		},
+		{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46},
+	}
+	// every truncated run should return an error instead of panic or wrong values.
+	for _, run := range runs {
+		for n := 0; n < len(run); n++ {
+			r := newIntReader(newByteStream(run[:n]), encodingDirectV2, false)
+			_, err := r.next()
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF, "run %x", run[:n])
+		}
+	}
+
+	for _, run := range [][]byte{{0x61, 0x00}, {0xfb, 0x02, 0x03}, {0xfd, 0x81}} {
+		r := newIntReader(newByteStream(run), encodingDirect, false)
+		var err error
+		for err == nil {
+			_, err = r.next()
+		}
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF, "run %x", run)
+	}
+
+	// varint longer than 64 bits
+	s := newByteStream(bytes.Repeat([]byte{0xff}, 11))
+	_, err := s.readUvarint()
+	require.ErrorContains(t, err, "orc varint overflows 64 bits")
+}
+
+func TestDecodeLZ4Block(t *testing.T) {
+	// literals and match with the extended lengths
+	literals := bytes.Repeat([]byte("x"), 20)
+	src := append([]byte{0xff, 20 - 15}, literals...)
+	src = append(src, 0x01, 0x00, 30-4-15)
+	src = append(src, 0x10, 'y')
+	data, err := decodeLZ4Block(nil, src)
+	require.NoError(t, err)
+	require.Equal(t, string(bytes.Repeat([]byte("x"), 50))+"y", string(data))
+
+	cases := []struct {
+		src []byte
+		err string
+	}{
+		{[]byte{0xf0}, "length is truncated"},
+		{[]byte{0x30, 'a'}, "literals are truncated"},
+		{[]byte{0x10, 'a', 0x01}, "offset is truncated"},
+		{[]byte{0x10, 'a', 0x00, 0x00}, "offset 0 out of range"},
+		{[]byte{0x10, 'a', 0x02, 0x00}, "offset 2 out of range"},
+		{[]byte{0x1f, 'a', 0x01, 0x00}, "length is truncated"},
+	}
+	for _, c := range cases {
+		_, err = decodeLZ4Block(nil, c.src)
+		require.ErrorContains(t, err, c.err, "src %x", c.src)
+	}
+
+	// a corrupted zstd frame
+	_, err = decompress(CompressionZstd, []byte{0x0a, 0x00, 0x00, 'h', 'e', 'l', 'l', 'o'})
+	require.ErrorContains(t, err, "magic number mismatch")
+}
+
+var primitiveTypes = []*Type{
+	{Kind: KindStruct, Subtypes: []uint32{1, 2, 3, 4, 5, 6, 7}, FieldNames: []string{"b", "s", "f", "c", "v", "bin", "l"}},
+	{Kind: KindByte},
+	{Kind: KindShort},
+	{Kind: KindFloat},
+	{Kind: KindChar},
+	{Kind: KindVarchar},
+	{Kind: KindBinary},
+	{Kind: KindList, Subtypes: []uint32{8}},
+	{Kind: KindFloat},
+}
+
+func TestPrimitiveColumns(t *testing.T) {
+	rows := [][]interface{}{
+		{int64(-128), int64(-32768), float32(1.5), "c", "varchar", []byte{0x00, 0xff}, []interface{}{float32(0.25), nil}},
+		{nil, nil, nil, nil, nil, nil, nil},
+		{int64(127), int64(32767), float32(math.Inf(1)), "", "", []byte{}, []interface{}{float32(math.NaN())}},
+	}
+	for _, compression := range []CompressionKind{CompressionNone, CompressionZstd} {
+		w := newTestWriter(t, primitiveTypes, compression)
+		w.writeStripe(rows)
+		r, err := NewReader(bytes.NewReader(w.close()))
+		require.NoError(t, err)
+		require.Equal(t, []string{"b", "s", "f", "c", "v", "bin", "l"}, r.FieldNames())
+
+		row, err := r.Next()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{int64(-128), int64(-32768), float32(1.5), "c", "varchar", []byte{0x00, 0xff}, JSON("[0.25,null]")}, row)
+
+		row, err = r.Next()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{nil, nil, nil, nil, nil, nil, nil}, row)
+
+		row, err = r.Next()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{int64(127), int64(32767), float32(math.Inf(1)), "", "", []byte{}, JSON("[null]")}, row)
+
+		_, err = r.Next()
+		require.Equal(t, io.EOF, err)
+	}
+}
+
+func TestAppendJSONValue(t *testing.T) {
+	ts := time.Date(2023, 6, 1, 12, 30, 1, 500, time.UTC)
+	cases := []struct {
+		kind     TypeKind
+		value    interface{}
+		expected string
+	}{
+		{KindBoolean, nil, "null"},
+		{KindBoolean, true, "true"},
+		{KindLong, int64(-3), "-3"},
+		{KindFloat, float32(0.1), "0.1"},
+		{KindFloat, float32(math.NaN()), "null"},
+		{KindDouble, 0.1, "0.1"},
+		{KindDouble, math.Inf(-1), "null"},
+		{KindString, "a\"b", `"a\"b"`},
+		{KindDecimal, "-1.50", "-1.50"},
+		{KindBinary, []byte("hi"), `"aGk="`},
+		{KindDate, ts, `"2023-06-01"`},
+		{KindTimestamp, ts, `"2023-06-01 12:30:01.0000005"`},
+		{KindStruct, JSON(`{"a":1}`), `{"a":1}`},
+		{KindUnion, struct{}{}, `""`},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, string(appendJSONValue(nil, c.kind, c.value)), "value %v", c.value)
+	}
+}
+
+func TestBuildColumnReaderErrors(t *testing.T) {
+	s := &stripeStreams{
+		types: []*Type{
+			{Kind: KindList, Subtypes: []uint32{1, 2}},
+			{Kind: KindInt},
+			{Kind: KindInt},
+			{Kind: KindUnion, Subtypes: []uint32{1}},
+			{Kind: KindMap, Subtypes: []uint32{1}},
+		},
+		streams: map[streamKey]*byteStream{},
+	}
+	_, err := s.buildColumnReader(0)
+	require.ErrorContains(t, err, "orc array column 0 should have 1 subtypes, but got 2")
+	_, err = s.buildColumnReader(3)
+	require.ErrorContains(t, err, "unsupported orc type uniontype of column 3")
+	_, err = s.buildColumnReader(4)
+	require.ErrorContains(t, err, "orc map column 4 should have 2 subtypes, but got 1")
+	_, err = s.buildColumnReader(5)
+	require.ErrorContains(t, err, "orc column 5 is out of range")
+
+	// the index of dictionary is out of range
+	r := &dictColumnReader{index: newIntReader(newByteStream([]byte{0xff, 0x02}), encodingDirect, false), dict: []string{"a"}}
+	_, err = r.next()
+	require.ErrorContains(t, err, "orc dictionary index 2 out of range [0, 1)")
+
+	require.Equal(t, "ZSTD", CompressionZstd.String())
+	require.Equal(t, "UNKNOWN", CompressionKind(100).String())
+	require.Equal(t, "char", KindChar.String())
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orc
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/pingcap/errors"
+)
+
+// compressionHeaderSize is the size of the header before each compressed chunk.
+const compressionHeaderSize = 3
+
+// decompress decodes the data of a stream, footer or metadata section.
+//
+// When the file is compressed, the data is a sequence of chunks and each chunk
+// starts with a 3 bytes little-endian header: `length << 1 | isOriginal`.
+func decompress(kind CompressionKind, src []byte) ([]byte, error) {
+	if kind == CompressionNone {
+		return src, nil
+	}
+
+	dst := make([]byte, 0, len(src)*2)
+	for len(src) > 0 {
+		if len(src) < compressionHeaderSize {
+			return nil, errors.Errorf("invalid orc compression chunk header, remaining %d bytes", len(src))
+		}
+		header := int(src[0]) | int(src[1])<<8 | int(src[2])<<16
+		isOriginal := header&1 == 1
+		length := header >> 1
+		src = src[compressionHeaderSize:]
+		if length > len(src) {
+			return nil, errors.Errorf("orc compression chunk is truncated, expected %d bytes, remaining %d bytes", length, len(src))
+		}
+
+		chunk := src[:length]
+		src = src[length:]
+		if isOriginal {
+			dst = append(dst, chunk...)
+			continue
+		}
+
+		var err error
+		if dst, err = decompressChunk(kind, dst, chunk); err != nil {
+			return nil, errors.Annotatef(err, "decompress orc chunk with %s", kind)
+		}
+	}
+	return dst, nil
+}
+
+func decompressChunk(kind CompressionKind, dst, chunk []byte) ([]byte, error) {
+	switch kind {
+	case CompressionZlib:
+		// ORC uses the raw deflate stream without the zlib header.
+		r := flate.NewReader(bytes.NewReader(chunk))
+		//nolint: errcheck
+		defer r.Close()
+		buf := bytes.NewBuffer(dst)
+		if _, err := io.Copy(buf, r); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return buf.Bytes(), nil
+	case CompressionSnappy:
+		n, err := snappy.DecodedLen(chunk)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		decoded, err := snappy.Decode(make([]byte, n), chunk)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(dst, decoded...), nil
+	case CompressionLz4:
+		return decodeLZ4Block(dst, chunk)
+	case CompressionZstd:
+		return decodeZstd(dst, chunk)
+	default:
+		return nil, errors.Errorf("unsupported orc compression %s", kind)
+	}
+}
+
+// decodeLZ4Block decodes a raw LZ4 block (without the frame header) and
+// appends the result to dst.
+func decodeLZ4Block(dst, src []byte) ([]byte, error) {
+	readLength := func(i int, length int) (int, int, error) {
+		for {
+			if i >= len(src) {
+				return 0, 0, errors.New("invalid lz4 block: length is truncated")
+			}
+			b := src[i]
+			i++
+			length += int(b)
+			if b != 255 {
+				return i, length, nil
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+
+		var err error
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			if i, litLen, err = readLength(i, litLen); err != nil {
+				return nil, err
+			}
+		}
+		if i+litLen > len(src) {
+			return nil, errors.New("invalid lz4 block: literals are truncated")
+		}
+		dst = append(dst, src[i:i+litLen]...)
+		i += litLen
+		// the last sequence only contains literals.
+		if i == len(src) {
+			break
+		}
+
+		if i+2 > len(src) {
+			return nil, errors.New("invalid lz4 block: offset is truncated")
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return nil, errors.Errorf("invalid lz4 block: offset %d out of range", offset)
+		}
+
+		matchLen := int(token & 0x0f)
+		if matchLen == 15 {
+			if i, matchLen, err = readLength(i, matchLen); err != nil {
+				return nil, err
+			}
+		}
+		matchLen += 4
+
+		// the match may overlap with itself, so copy it byte by byte.
+		start := len(dst) - offset
+		for k := 0; k < matchLen; k++ {
+			dst = append(dst, dst[start+k])
+		}
+	}
+	return dst, nil
+}
@@ -0,0 +1,370 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orc
+
+import (
+	"github.com/pingcap/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The messages below are the subset of orc_proto.proto which is needed to read
+// the data of an ORC file. They are decoded manually with protowire to avoid
+// depending on the generated code of the ORC project.
+
+// CompressionKind is the compression codec of an ORC file.
+type CompressionKind int
+
+// The compression codecs defined by the ORC specification.
+const (
+	CompressionNone CompressionKind = iota
+	CompressionZlib
+	CompressionSnappy
+	CompressionLzo
+	CompressionLz4
+	CompressionZstd
+)
+
+// String implements the fmt.Stringer interface.
+func (k CompressionKind) String() string {
+	switch k {
+	case CompressionNone:
+		return "NONE"
+	case CompressionZlib:
+		return "ZLIB"
+	case CompressionSnappy:
+		return "SNAPPY"
+	case CompressionLzo:
+		return "LZO"
+	case CompressionLz4:
+		return "LZ4"
+	case CompressionZstd:
+		return "ZSTD"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// TypeKind is the kind of ORC column type.
+type TypeKind int
+
+// The type kinds defined by the ORC specification.
+const (
+	KindBoolean TypeKind = iota
+	KindByte
+	KindShort
+	KindInt
+	KindLong
+	KindFloat
+	KindDouble
+	KindString
+	KindBinary
+	KindTimestamp
+	KindList
+	KindMap
+	KindStruct
+	KindUnion
+	KindDecimal
+	KindDate
+	KindVarchar
+	KindChar
+	KindTimestampInstant
+)
+
+var typeKindNames = [...]string{
+	KindBoolean:          "boolean",
+	KindByte:             "tinyint",
+	KindShort:            "smallint",
+	KindInt:              "int",
+	KindLong:             "bigint",
+	KindFloat:            "float",
+	KindDouble:           "double",
+	KindString:           "string",
+	KindBinary:           "binary",
+	KindTimestamp:        "timestamp",
+	KindList:             "array",
+	KindMap:              "map",
+	KindStruct:           "struct",
+	KindUnion:            "uniontype",
+	KindDecimal:          "decimal",
+	KindDate:             "date",
+	KindVarchar:          "varchar",
+	KindChar:             "char",
+	KindTimestampInstant: "timestamp with local time zone",
+}
+
+// String implements the fmt.Stringer interface.
+func (k TypeKind) String() string {
+	if k >= 0 && int(k) < len(typeKindNames) {
+		return typeKindNames[k]
+	}
+	return "unknown"
+}
+
+// streamKind is the kind of a stream in a stripe.
+type streamKind int
+
+const (
+	streamPresent streamKind = iota
+	streamData
+	streamLength
+	streamDictionaryData
+	streamDictionaryCount
+	streamSecondary
+	streamRowIndex
+	streamBloomFilter
+	streamBloomFilterUTF8
+)
+
+// encodingKind is the encoding of a column in a stripe.
+type encodingKind int
+
+const (
+	encodingDirect encodingKind = iota
+	encodingDictionary
+	encodingDirectV2
+	encodingDictionaryV2
+)
+
+type postScript struct {
+	footerLength         uint64
+	compression          CompressionKind
+	compressionBlockSize uint64
+	metadataLength       uint64
+	magic                string
+}
+
+// StripeInformation describes the location and the size of a stripe.
+type StripeInformation struct {
+	Offset       uint64
+	IndexLength  uint64
+	DataLength   uint64
+	FooterLength uint64
+	NumberOfRows uint64
+}
+
+// Type is a node of the ORC type tree. Types are stored in the footer in
+// pre-order, and the root type is always at index 0.
+type Type struct {
+	Kind       TypeKind
+	Subtypes   []uint32
+	FieldNames []string
+	Precision  uint32
+	Scale      uint32
+}
+
+type footer struct {
+	numberOfRows uint64
+	stripes      []StripeInformation
+	types        []*Type
+}
+
+type stream struct {
+	kind   streamKind
+	column uint32
+	length uint64
+}
+
+type columnEncoding struct {
+	kind           encodingKind
+	dictionarySize uint32
+}
+
+type stripeFooter struct {
+	streams        []stream
+	columns        []columnEncoding
+	writerTimezone string
+}
+
+// decodeMessage iterates the fields of a protobuf message. Only the varint and
+// the length-delimited fields are passed to `fn`, other fields are skipped.
+func decodeMessage(b []byte, fn func(num protowire.Number, typ protowire.Type, x uint64, v []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return errors.Annotate(protowire.ParseError(n), "invalid protobuf tag")
+		}
+		b = b[n:]
+
+		var (
+			x uint64
+			v []byte
+		)
+		switch typ {
+		case protowire.VarintType:
+			x, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n >= 0 {
+				b = b[n:]
+				continue
+			}
+		}
+		if n < 0 {
+			return errors.Annotatef(protowire.ParseError(n), "invalid protobuf field %d", num)
+		}
+		b = b[n:]
+
+		if err := fn(num, typ, x, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendRepeatedUint32 decodes a repeated uint32 field which may be packed or not.
+func appendRepeatedUint32(dst []uint32, typ protowire.Type, x uint64, v []byte) ([]uint32, error) {
+	if typ == protowire.VarintType {
+		return append(dst, uint32(x)), nil
+	}
+	for len(v) > 0 {
+		x, n := protowire.ConsumeVarint(v)
+		if n < 0 {
+			return nil, errors.Annotate(protowire.ParseError(n), "invalid packed field")
+		}
+		dst = append(dst, uint32(x))
+		v = v[n:]
+	}
+	return dst, nil
+}
+
+func decodePostScript(b []byte) (*postScript, error) {
+	ps := &postScript{}
+	err := decodeMessage(b, func(num protowire.Number, _ protowire.Type, x uint64, v []byte) error {
+		switch num {
+		case 1:
+			ps.footerLength = x
+		case 2:
+			ps.compression = CompressionKind(x)
+		case 3:
+			ps.compressionBlockSize = x
+		case 5:
+			ps.metadataLength = x
+		case 8000:
+			ps.magic = string(v)
+		}
+		return nil
+	})
+	return ps, errors.Annotate(err, "decode orc postscript")
+}
+
+func decodeStripeInformation(b []byte) (StripeInformation, error) {
+	var si StripeInformation
+	err := decodeMessage(b, func(num protowire.Number, _ protowire.Type, x uint64, _ []byte) error {
+		switch num {
+		case 1:
+			si.Offset = x
+		case 2:
+			si.IndexLength = x
+		case 3:
+			si.DataLength = x
+		case 4:
+			si.FooterLength = x
+		case 5:
+			si.NumberOfRows = x
+		}
+		return nil
+	})
+	return si, err
+}
+
+func decodeType(b []byte) (*Type, error) {
+	t := &Type{}
+	err := decodeMessage(b, func(num protowire.Number, typ protowire.Type, x uint64, v []byte) (err error) {
+		switch num {
+		case 1:
+			t.Kind = TypeKind(x)
+		case 2:
+			t.Subtypes, err = appendRepeatedUint32(t.Subtypes, typ, x, v)
+		case 3:
+			t.FieldNames = append(t.FieldNames, string(v))
+		case 5:
+			t.Precision = uint32(x)
+		case 6:
+			t.Scale = uint32(x)
+		}
+		return err
+	})
+	return t, err
+}
+
+func decodeFooter(b []byte) (*footer, error) {
+	f := &footer{}
+	err := decodeMessage(b, func(num protowire.Number, _ protowire.Type, x uint64, v []byte) error {
+		switch num {
+		case 3:
+			si, err := decodeStripeInformation(v)
+			if err != nil {
+				return err
+			}
+			f.stripes = append(f.stripes, si)
+		case 4:
+			t, err := decodeType(v)
+			if err != nil {
+				return err
+			}
+			f.types = append(f.types, t)
+		case 6:
+			f.numberOfRows = x
+		}
+		return nil
+	})
+	return f, errors.Annotate(err, "decode orc footer")
+}
+
+func decodeStripeFooter(b []byte) (*stripeFooter, error) {
+	sf := &stripeFooter{}
+	err := decodeMessage(b, func(num protowire.Number, _ protowire.Type, _ uint64, v []byte) error {
+		switch num {
+		case 1:
+			var s stream
+			err := decodeMessage(v, func(num protowire.Number, _ protowire.Type, x uint64, _ []byte) error {
+				switch num {
+				case 1:
+					s.kind = streamKind(x)
+				case 2:
+					s.column = uint32(x)
+				case 3:
+					s.length = x
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			sf.streams = append(sf.streams, s)
+		case 2:
+			var e columnEncoding
+			err := decodeMessage(v, func(num protowire.Number, _ protowire.Type, x uint64, _ []byte) error {
+				switch num {
+				case 1:
+					e.kind = encodingKind(x)
+				case 2:
+					e.dictionarySize = uint32(x)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			sf.columns = append(sf.columns, e)
+		case 3:
+			sf.writerTimezone = string(v)
+		}
+		return nil
+	})
+	return sf, errors.Annotate(err, "decode orc stripe footer")
+}
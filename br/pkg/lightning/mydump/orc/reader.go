@@ -0,0 +1,283 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package orc implements a reader of the Apache ORC file format, which reads
+// the rows of the top-level struct one by one.
+//
+// See https://orc.apache.org/specification/ORCv1/ for the file format.
+package orc
+
+import (
+	"io"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	magic = "ORC"
+	// tailReadSize is the size of the file tail which is read at first, it
+	// usually contains the whole footer and postscript.
+	tailReadSize = 16 * 1024
+	// maxPostScriptSize is the max size of postscript, its length is stored in one byte.
+	maxPostScriptSize = 255
+)
+
+// Reader reads the rows of an ORC file.
+type Reader struct {
+	r           io.ReadSeeker
+	compression CompressionKind
+	footer      *footer
+	fieldNames  []string
+	fieldTypes  []*Type
+
+	// states of the current stripe.
+	stripeIdx    int
+	stripeRows   int64
+	stripeRowIdx int64
+	columns      []columnReader
+	// the row number of the next row
+	rowIdx int64
+}
+
+// NewReader reads the metadata of the ORC file and creates a Reader.
+func NewReader(r io.ReadSeeker) (*Reader, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if size <= int64(len(magic)) {
+		return nil, errors.Errorf("invalid orc file, file size %d is too small", size)
+	}
+
+	tailSize := int64(tailReadSize)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail, err := readAt(r, size-tailSize, tailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	psLen := int64(tail[len(tail)-1])
+	if psLen+1 > tailSize || psLen > maxPostScriptSize {
+		return nil, errors.Errorf("invalid orc file, postscript length %d is out of range", psLen)
+	}
+	ps, err := decodePostScript(tail[tailSize-1-psLen : tailSize-1])
+	if err != nil {
+		return nil, err
+	}
+	if ps.magic != magic {
+		return nil, errors.New("invalid orc file, magic of postscript mismatch")
+	}
+
+	footerLen := int64(ps.footerLength)
+	footerStart := size - 1 - psLen - footerLen
+	if footerStart < int64(len(magic)) {
+		return nil, errors.Errorf("invalid orc file, footer length %d is out of range", footerLen)
+	}
+	var footerData []byte
+	if footerLen+psLen+1 <= tailSize {
+		footerData = tail[tailSize-1-psLen-footerLen : tailSize-1-psLen]
+	} else if footerData, err = readAt(r, footerStart, footerLen); err != nil {
+		return nil, err
+	}
+	if footerData, err = decompress(ps.compression, footerData); err != nil {
+		return nil, err
+	}
+	f, err := decodeFooter(footerData)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &Reader{
+		r:           r,
+		compression: ps.compression,
+		footer:      f,
+		stripeIdx:   -1,
+	}
+	if len(f.types) > 0 {
+		root := f.types[0]
+		if root.Kind != KindStruct {
+			return nil, errors.Errorf("the root type of orc file must be struct, but got %s", root.Kind)
+		}
+		for i, sub := range root.Subtypes {
+			if int(sub) >= len(f.types) {
+				return nil, errors.Errorf("invalid orc file, subtype %d is out of range", sub)
+			}
+			name := ""
+			if i < len(root.FieldNames) {
+				name = root.FieldNames[i]
+			}
+			reader.fieldNames = append(reader.fieldNames, name)
+			reader.fieldTypes = append(reader.fieldTypes, f.types[sub])
+		}
+	}
+	return reader, nil
+}
+
+func readAt(r io.ReadSeeker, offset, length int64) ([]byte, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, errors.Trace(err)
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buf, nil
+}
+
+// Compression returns the compression codec of the file.
+func (r *Reader) Compression() CompressionKind {
+	return r.compression
+}
+
+// NumRows returns the total row count of the file.
+func (r *Reader) NumRows() int64 {
+	return int64(r.footer.numberOfRows)
+}
+
+// Stripes returns the information of all stripes.
+func (r *Reader) Stripes() []StripeInformation {
+	return r.footer.stripes
+}
+
+// FieldNames returns the names of the fields of the top-level struct, which are
+// the columns of the rows.
+func (r *Reader) FieldNames() []string {
+	return r.fieldNames
+}
+
+// FieldTypes returns the types of the fields of the top-level struct.
+func (r *Reader) FieldTypes() []*Type {
+	return r.fieldTypes
+}
+
+// SeekRow moves the reader to the row with the row number.
+func (r *Reader) SeekRow(row int64) error {
+	if row < 0 || row > r.NumRows() {
+		return errors.Errorf("orc row number %d out of range [0, %d]", row, r.NumRows())
+	}
+
+	var start int64
+	for i, stripe := range r.footer.stripes {
+		end := start + int64(stripe.NumberOfRows)
+		if row < end {
+			if i != r.stripeIdx || row < r.rowIdx {
+				if err := r.loadStripe(i); err != nil {
+					return err
+				}
+				r.rowIdx = start
+			}
+			for r.rowIdx < row {
+				if _, err := r.Next(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		start = end
+	}
+
+	// seek to the end of the file.
+	r.stripeIdx = len(r.footer.stripes)
+	r.stripeRows, r.stripeRowIdx = 0, 0
+	r.columns = nil
+	r.rowIdx = row
+	return nil
+}
+
+// Next reads the next row, it returns io.EOF if there are no more rows. See
+// columnReader for the types of the values.
+func (r *Reader) Next() ([]interface{}, error) {
+	for r.stripeRowIdx >= r.stripeRows {
+		if r.stripeIdx+1 >= len(r.footer.stripes) {
+			return nil, io.EOF
+		}
+		if err := r.loadStripe(r.stripeIdx + 1); err != nil {
+			return nil, err
+		}
+	}
+
+	row := make([]interface{}, 0, len(r.columns))
+	for i, col := range r.columns {
+		v, err := col.next()
+		if err != nil {
+			return nil, errors.Annotatef(err, "read column '%s' of orc stripe %d row %d", r.fieldNames[i], r.stripeIdx, r.stripeRowIdx)
+		}
+		row = append(row, v)
+	}
+	r.stripeRowIdx++
+	r.rowIdx++
+	return row, nil
+}
+
+func (r *Reader) loadStripe(idx int) error {
+	stripe := r.footer.stripes[idx]
+	data, err := readAt(r.r, int64(stripe.Offset), int64(stripe.IndexLength+stripe.DataLength+stripe.FooterLength))
+	if err != nil {
+		return err
+	}
+
+	footerData, err := decompress(r.compression, data[stripe.IndexLength+stripe.DataLength:])
+	if err != nil {
+		return err
+	}
+	sf, err := decodeStripeFooter(footerData)
+	if err != nil {
+		return err
+	}
+
+	streams := &stripeStreams{
+		types:     r.footer.types,
+		streams:   make(map[streamKey]*byteStream, len(sf.streams)),
+		encodings: sf.columns,
+	}
+	if sf.writerTimezone != "" {
+		if streams.location, err = time.LoadLocation(sf.writerTimezone); err != nil {
+			return errors.Annotatef(err, "load writer timezone of orc stripe %d", idx)
+		}
+	}
+
+	var offset uint64
+	for _, s := range sf.streams {
+		if offset+s.length > stripe.IndexLength+stripe.DataLength {
+			return errors.Errorf("invalid orc stripe %d, stream of column %d is out of range", idx, s.column)
+		}
+		buf := data[offset : offset+s.length]
+		offset += s.length
+		if s.kind == streamRowIndex || s.kind == streamBloomFilter || s.kind == streamBloomFilterUTF8 {
+			continue
+		}
+		if buf, err = decompress(r.compression, buf); err != nil {
+			return errors.Annotatef(err, "decompress stream of column %d in orc stripe %d", s.column, idx)
+		}
+		streams.streams[streamKey{column: s.column, kind: s.kind}] = newByteStream(buf)
+	}
+
+	columns := make([]columnReader, 0, len(r.fieldTypes))
+	for _, sub := range r.footer.types[0].Subtypes {
+		col, err := streams.buildColumnReader(sub)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, col)
+	}
+
+	r.stripeIdx = idx
+	r.stripeRows = int64(stripe.NumberOfRows)
+	r.stripeRowIdx = 0
+	r.columns = columns
+	return nil
+}
@@ -0,0 +1,345 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orc
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readInts(t *testing.T, r intReader, n int) []int64 {
+	values := make([]int64, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := r.next()
+		require.NoError(t, err)
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestIntRLEv1(t *testing.T) {
+	// examples in the ORC specification
+	r := newIntReader(newByteStream([]byte{0x61, 0x00, 0x07}), encodingDirect, false)
+	values := readInts(t, r, 100)
+	for _, v := range values {
+		require.Equal(t, int64(7), v)
+	}
+	_, err := r.next()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	r = newIntReader(newByteStream([]byte{0x61, 0xff, 0x64}), encodingDirect, false)
+	values = readInts(t, r, 100)
+	require.Equal(t, int64(100), values[0])
+	require.Equal(t, int64(1), values[99])
+
+	r = newIntReader(newByteStream([]byte{0xfb, 0x02, 0x03, 0x06, 0x07, 0x0b}), encodingDirect, false)
+	require.Equal(t, []int64{2, 3, 6, 7, 11}, readInts(t, r, 5))
+
+	// signed values are zigzag encoded
+	r = newIntReader(newByteStream([]byte{0xfd, 0x01, 0x02, 0x03}), encodingDirect, true)
+	require.Equal(t, []int64{-1, 1, -2}, readInts(t, r, 3))
+}
+
+func TestIntRLEv2(t *testing.T) {
+	// examples in the ORC specification
+	cases := []struct {
+		data     []byte
+		expected []int64
+	}{
+		// SHORT_REPEAT
+		{
+			data:     []byte{0x0a, 0x27, 0x10},
+			expected: []int64{10000, 10000, 10000, 10000, 10000},
+		},
+		// DIRECT
+		{
+			data:     []byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef},
+			expected: []int64{23713, 43806, 57005, 48879},
+		},
+		// PATCHED_BASE
+		{
+			data: []byte{
+				0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46, 0x50, 0x5a,
+				0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8,
+			},
+			expected: []int64{
+				2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090,
+				2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190,
+			},
+		},
+		// DELTA
+		{
+			data:     []byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46},
+			expected: []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29},
+		},
+	}
+
+	var all []byte
+	var allExpected []int64
+	for _, c := range cases {
+		r := newIntReader(newByteStream(c.data), encodingDirectV2, false)
+		require.Equal(t, c.expected, readInts(t, r, len(c.expected)))
+		_, err := r.next()
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+		all = append(all, c.data...)
+		allExpected = append(allExpected, c.expected...)
+	}
+
+	// runs are read continuously
+	r := newIntReader(newByteStream(all), encodingDictionaryV2, false)
+	require.Equal(t, allExpected, readInts(t, r, len(allExpected)))
+
+	// fixed delta with signed values: base -1 and delta -2
+	r = newIntReader(newByteStream([]byte{0xc0, 0x03, 0x01, 0x03}), encodingDirectV2, true)
+	require.Equal(t, []int64{-1, -3, -5, -7}, readInts(t, r, 4))
+}
+
+func TestByteAndBoolRLE(t *testing.T) {
+	r := newByteRLEReader(newByteStream([]byte{0x61, 0x44, 0xfe, 0x44, 0x45}))
+	for i := 0; i < 100; i++ {
+		b, err := r.next()
+		require.NoError(t, err)
+		require.Equal(t, byte(0x44), b)
+	}
+	for _, expected := range []byte{0x44, 0x45} {
+		b, err := r.next()
+		require.NoError(t, err)
+		require.Equal(t, expected, b)
+	}
+
+	br := newBoolReader(newByteStream([]byte{0xff, 0x80}))
+	for i := 0; i < 8; i++ {
+		b, err := br.next()
+		require.NoError(t, err)
+		require.Equal(t, i == 0, b)
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	// chunk stored as original
+	data, err := decompress(CompressionZlib, []byte{0x0b, 0x00, 0x00, 'h', 'e', 'l', 'l', 'o'})
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	_, err = decompress(CompressionZlib, []byte{0x0b, 0x00, 0x00, 'h', 'e'})
+	require.ErrorContains(t, err, "orc compression chunk is truncated")
+
+	_, err = decompress(CompressionLzo, []byte{0x04, 0x00, 0x00, 'h', 'e'})
+	require.ErrorContains(t, err, "unsupported orc compression LZO")
+
+	// lz4 block with a match which overlaps with itself
+	data, err = decompress(CompressionLz4, []byte{0x10, 0x00, 0x00, 0x32, 'a', 'b', 'c', 0x03, 0x00, 0x10, 'd'})
+	require.NoError(t, err)
+	require.Equal(t, "abcabcabcd", string(data))
+}
+
+func TestFormatDecimal(t *testing.T) {
+	cases := []struct {
+		unscaled int64
+		scale    int
+		expected string
+	}{
+		{12345, 2, "123.45"},
+		{-12345, 2, "-123.45"},
+		{5, 3, "0.005"},
+		{-5, 3, "-0.005"},
+		{12, 0, "12"},
+		{12, -2, "1200"},
+		{0, -2, "0"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, formatDecimalInt64(c.unscaled, c.scale))
+	}
+}
+
+var sampleTypes = []*Type{
+	{Kind: KindStruct, Subtypes: []uint32{1, 2, 3, 4, 5, 6, 7, 8, 11, 13}, FieldNames: []string{"id", "Name", "score", "ok", "price", "d", "ts", "s", "l", "m"}},
+	{Kind: KindLong},
+	{Kind: KindString},
+	{Kind: KindDouble},
+	{Kind: KindBoolean},
+	{Kind: KindDecimal, Precision: 10, Scale: 2},
+	{Kind: KindDate},
+	{Kind: KindTimestamp},
+	{Kind: KindStruct, Subtypes: []uint32{9, 10}, FieldNames: []string{"a", "b"}},
+	{Kind: KindInt},
+	{Kind: KindString},
+	{Kind: KindList, Subtypes: []uint32{12}},
+	{Kind: KindInt},
+	{Kind: KindMap, Subtypes: []uint32{14, 15}},
+	{Kind: KindString},
+	{Kind: KindInt},
+}
+
+func sampleRow(i int64) []interface{} {
+	if i%3 == 2 {
+		return []interface{}{i, nil, nil, nil, nil, nil, nil, nil, nil, nil}
+	}
+	return []interface{}{
+		i,
+		"name\"" + string(rune('a'+i)),
+		float64(i) + 0.5,
+		i%2 == 0,
+		testDecimal{unscaled: i*100 + 1, scale: 2},
+		i,
+		time.Date(2023, 6, 1, 12, 30, int(i), 123000000, time.UTC),
+		[]interface{}{i, sampleStructField(i)},
+		[]interface{}{i, nil, i + 1},
+		[]testMapEntry{{key: "k", value: i}},
+	}
+}
+
+func sampleStructField(i int64) interface{} {
+	if i == 0 {
+		return nil
+	}
+	return "b" + itoa(i%2)
+}
+
+// writeSampleFile writes 2 stripes with 3 rows in the first stripe and 2 rows in the second stripe.
+func writeSampleFile(t *testing.T, compression CompressionKind) []byte {
+	w := newTestWriter(t, sampleTypes, compression)
+	w.timezone = "Asia/Shanghai"
+	w.dictColumns[10] = true
+	w.writeStripe([][]interface{}{sampleRow(0), sampleRow(1), sampleRow(2)})
+	w.writeStripe([][]interface{}{sampleRow(3), sampleRow(4)})
+	return w.close()
+}
+
+func checkSampleRow(t *testing.T, i int64, row []interface{}) {
+	require.Len(t, row, 10)
+	require.Equal(t, i, row[0])
+	if i%3 == 2 {
+		for _, v := range row[1:] {
+			require.Nil(t, v)
+		}
+		return
+	}
+
+	require.Equal(t, "name\""+string(rune('a'+i)), row[1])
+	require.Equal(t, float64(i)+0.5, row[2])
+	require.Equal(t, i%2 == 0, row[3])
+	require.Equal(t, formatDecimalInt64(i*100+1, 2), row[4])
+	require.Equal(t, time.Unix(i*24*60*60, 0).UTC(), row[5])
+	ts := row[6].(time.Time)
+	require.Equal(t, "Asia/Shanghai", ts.Location().String())
+	require.Equal(t, time.Date(2023, 6, 1, 12, 30, int(i), 123000000, time.UTC).Format(TimestampLayout), ts.Format(TimestampLayout))
+	b := "null"
+	if v := sampleStructField(i); v != nil {
+		b = `"` + v.(string) + `"`
+	}
+	require.Equal(t, JSON(`{"a":`+itoa(i)+`,"b":`+b+`}`), row[7])
+	require.Equal(t, JSON(`[`+itoa(i)+`,null,`+itoa(i+1)+`]`), row[8])
+	require.Equal(t, JSON(`{"k":`+itoa(i)+`}`), row[9])
+}
+
+func formatDecimalInt64(unscaled int64, scale int) string {
+	return formatDecimal(big.NewInt(unscaled), scale)
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+func TestReader(t *testing.T) {
+	for _, compression := range []CompressionKind{CompressionNone, CompressionZlib, CompressionSnappy, CompressionZstd} {
+		data := writeSampleFile(t, compression)
+		r, err := NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, compression, r.Compression())
+		require.Equal(t, int64(5), r.NumRows())
+		require.Len(t, r.Stripes(), 2)
+		require.Equal(t, []string{"id", "Name", "score", "ok", "price", "d", "ts", "s", "l", "m"}, r.FieldNames())
+		require.Equal(t, KindDecimal, r.FieldTypes()[4].Kind)
+
+		for i := int64(0); i < 5; i++ {
+			row, err := r.Next()
+			require.NoError(t, err)
+			checkSampleRow(t, i, row)
+		}
+		_, err = r.Next()
+		require.Equal(t, io.EOF, err)
+
+		// seek to the rows in different stripes
+		for _, i := range []int64{4, 1, 2, 3, 0} {
+			require.NoError(t, r.SeekRow(i))
+			row, err := r.Next()
+			require.NoError(t, err)
+			checkSampleRow(t, i, row)
+		}
+		require.NoError(t, r.SeekRow(5))
+		_, err = r.Next()
+		require.Equal(t, io.EOF, err)
+		require.ErrorContains(t, r.SeekRow(6), "out of range")
+	}
+}
+
+func TestReaderInvalidFile(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("OR")))
+	require.ErrorContains(t, err, "too small")
+
+	_, err = NewReader(bytes.NewReader([]byte("ORC\x08\x00\x02")))
+	require.ErrorContains(t, err, "magic of postscript mismatch")
+
+	data := writeSampleFile(t, CompressionNone)
+	data[len(data)-1] = 0xff
+	_, err = NewReader(bytes.NewReader(data))
+	require.Error(t, err)
+
+	w := newTestWriter(t, []*Type{{Kind: KindLong}}, CompressionNone)
+	_, err = NewReader(bytes.NewReader(w.close()))
+	require.ErrorContains(t, err, "the root type of orc file must be struct")
+
+	// a corrupted stripe should return an error instead of panic
+	data = writeSampleFile(t, CompressionNone)
+	for i := len(magic); i < len(data); i++ {
+		corrupted := append([]byte{}, data...)
+		corrupted[i] ^= 0xff
+		r, err := NewReader(bytes.NewReader(corrupted))
+		if err != nil {
+			continue
+		}
+		for err == nil {
+			_, err = r.Next()
+		}
+	}
+}
+
+func TestSampleFile(t *testing.T) {
+	// testdata/sample.orc is written by writeSampleFile with ZLIB compression,
+	// and it's also used by the tests of other packages.
+	f, err := os.Open("testdata/sample.orc")
+	require.NoError(t, err)
+	defer f.Close()
+
+	r, err := NewReader(f)
+	require.NoError(t, err)
+	require.Equal(t, CompressionZlib, r.Compression())
+	for i := int64(0); i < 5; i++ {
+		row, err := r.Next()
+		require.NoError(t, err)
+		checkSampleRow(t, i, row)
+	}
+	_, err = r.Next()
+	require.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,505 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orc
+
+import (
+	"io"
+
+	"github.com/pingcap/errors"
+)
+
+// byteStream is a decompressed stream of a column.
+type byteStream struct {
+	buf []byte
+	pos int
+}
+
+func newByteStream(buf []byte) *byteStream {
+	return &byteStream{buf: buf}
+}
+
+func (s *byteStream) eof() bool {
+	return s == nil || s.pos >= len(s.buf)
+}
+
+func (s *byteStream) readByte() (byte, error) {
+	if s.eof() {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := s.buf[s.pos]
+	s.pos++
+	return b, nil
+}
+
+func (s *byteStream) readBytes(n int) ([]byte, error) {
+	if s == nil || n < 0 || s.pos+n > len(s.buf) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := s.buf[s.pos : s.pos+n]
+	s.pos += n
+	return b, nil
+}
+
+func (s *byteStream) readUvarint() (uint64, error) {
+	var (
+		x     uint64
+		shift uint
+	)
+	for {
+		b, err := s.readByte()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= 64 {
+			return 0, errors.New("orc varint overflows 64 bits")
+		}
+		x |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return x, nil
+		}
+		shift += 7
+	}
+}
+
+func (s *byteStream) readVarint(signed bool) (int64, error) {
+	x, err := s.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if signed {
+		return zigzagDecode(x), nil
+	}
+	return int64(x), nil
+}
+
+// readBigEndian reads an unsigned integer of n bytes in big-endian.
+func (s *byteStream) readBigEndian(n int) (uint64, error) {
+	b, err := s.readBytes(n)
+	if err != nil {
+		return 0, err
+	}
+	var x uint64
+	for _, c := range b {
+		x = x<<8 | uint64(c)
+	}
+	return x, nil
+}
+
+// readBitPacked reads n integers of `width` bits each, which are packed from
+// the most significant bit. The packed integers always end at a byte boundary.
+func (s *byteStream) readBitPacked(dst []uint64, n int, width int) ([]uint64, error) {
+	var (
+		cur      uint64
+		bitsLeft int
+	)
+	for i := 0; i < n; i++ {
+		var v uint64
+		for need := width; need > 0; {
+			if bitsLeft == 0 {
+				b, err := s.readByte()
+				if err != nil {
+					return nil, err
+				}
+				cur, bitsLeft = uint64(b), 8
+			}
+			take := need
+			if take > bitsLeft {
+				take = bitsLeft
+			}
+			v = v<<take | (cur>>(bitsLeft-take))&(1<<take-1)
+			bitsLeft -= take
+			need -= take
+		}
+		dst = append(dst, v)
+	}
+	return dst, nil
+}
+
+func zigzagDecode(x uint64) int64 {
+	return int64(x>>1) ^ -int64(x&1)
+}
+
+// byteRLEReader decodes the byte run length encoding.
+type byteRLEReader struct {
+	s         *byteStream
+	remaining int
+	isRun     bool
+	value     byte
+}
+
+func newByteRLEReader(s *byteStream) *byteRLEReader {
+	return &byteRLEReader{s: s}
+}
+
+func (r *byteRLEReader) next() (byte, error) {
+	if r.remaining == 0 {
+		h, err := r.s.readByte()
+		if err != nil {
+			return 0, err
+		}
+		if int8(h) >= 0 {
+			r.remaining, r.isRun = int(h)+3, true
+			if r.value, err = r.s.readByte(); err != nil {
+				return 0, err
+			}
+		} else {
+			r.remaining, r.isRun = -int(int8(h)), false
+		}
+	}
+
+	r.remaining--
+	if r.isRun {
+		return r.value, nil
+	}
+	return r.s.readByte()
+}
+
+// boolReader decodes the boolean run length encoding, the bits are stored
+// from the most significant bit of each byte.
+type boolReader struct {
+	r        *byteRLEReader
+	cur      byte
+	bitsLeft int
+}
+
+func newBoolReader(s *byteStream) *boolReader {
+	return &boolReader{r: newByteRLEReader(s)}
+}
+
+func (r *boolReader) next() (bool, error) {
+	if r.bitsLeft == 0 {
+		b, err := r.r.next()
+		if err != nil {
+			return false, err
+		}
+		r.cur, r.bitsLeft = b, 8
+	}
+	r.bitsLeft--
+	return (r.cur>>r.bitsLeft)&1 == 1, nil
+}
+
+// intReader decodes a stream of integers. Unsigned integers are returned as
+// int64 with the same bits.
+type intReader interface {
+	next() (int64, error)
+}
+
+func newIntReader(s *byteStream, encoding encodingKind, signed bool) intReader {
+	if encoding == encodingDirectV2 || encoding == encodingDictionaryV2 {
+		return &intRLEv2Reader{s: s, signed: signed}
+	}
+	return &intRLEv1Reader{s: s, signed: signed}
+}
+
+// intRLEv1Reader decodes the integer run length encoding version 1.
+type intRLEv1Reader struct {
+	s         *byteStream
+	signed    bool
+	remaining int
+	isRun     bool
+	value     int64
+	delta     int64
+}
+
+func (r *intRLEv1Reader) next() (int64, error) {
+	if r.remaining == 0 {
+		h, err := r.s.readByte()
+		if err != nil {
+			return 0, err
+		}
+		if int8(h) >= 0 {
+			r.remaining, r.isRun = int(h)+3, true
+			d, err := r.s.readByte()
+			if err != nil {
+				return 0, err
+			}
+			r.delta = int64(int8(d))
+			if r.value, err = r.s.readVarint(r.signed); err != nil {
+				return 0, err
+			}
+		} else {
+			r.remaining, r.isRun = -int(int8(h)), false
+		}
+	}
+
+	r.remaining--
+	if r.isRun {
+		v := r.value
+		r.value += r.delta
+		return v, nil
+	}
+	return r.s.readVarint(r.signed)
+}
+
+// The sub-encodings of the integer run length encoding version 2.
+const (
+	rleV2ShortRepeat = iota
+	rleV2Direct
+	rleV2PatchedBase
+	rleV2Delta
+)
+
+// decodeBitWidth decodes the 5 bits width code of RLE v2.
+func decodeBitWidth(code int) int {
+	switch {
+	case code <= 23:
+		return code + 1
+	case code == 24:
+		return 26
+	case code == 25:
+		return 28
+	case code == 26:
+		return 30
+	case code == 27:
+		return 32
+	case code == 28:
+		return 40
+	case code == 29:
+		return 48
+	case code == 30:
+		return 56
+	default:
+		return 64
+	}
+}
+
+// closestFixedBits rounds the width up to a width which can be encoded by RLE v2.
+func closestFixedBits(n int) int {
+	switch {
+	case n == 0:
+		return 1
+	case n <= 24:
+		return n
+	case n <= 26:
+		return 26
+	case n <= 28:
+		return 28
+	case n <= 30:
+		return 30
+	case n <= 32:
+		return 32
+	case n <= 40:
+		return 40
+	case n <= 48:
+		return 48
+	case n <= 56:
+		return 56
+	default:
+		return 64
+	}
+}
+
+// intRLEv2Reader decodes the integer run length encoding version 2. It decodes
+// a whole run each time and returns the values one by one.
+type intRLEv2Reader struct {
+	s      *byteStream
+	signed bool
+	values []int64
+	idx    int
+	packed []uint64
+}
+
+func (r *intRLEv2Reader) next() (int64, error) {
+	if r.idx >= len(r.values) {
+		r.values, r.idx = r.values[:0], 0
+		if err := r.readRun(); err != nil {
+			return 0, err
+		}
+	}
+	v := r.values[r.idx]
+	r.idx++
+	return v, nil
+}
+
+func (r *intRLEv2Reader) decodeValue(x uint64) int64 {
+	if r.signed {
+		return zigzagDecode(x)
+	}
+	return int64(x)
+}
+
+func (r *intRLEv2Reader) readRun() error {
+	h, err := r.s.readByte()
+	if err != nil {
+		return err
+	}
+
+	switch int(h >> 6) {
+	case rleV2ShortRepeat:
+		width := int(h>>3&0x07) + 1
+		count := int(h&0x07) + 3
+		x, err := r.s.readBigEndian(width)
+		if err != nil {
+			return err
+		}
+		v := r.decodeValue(x)
+		for i := 0; i < count; i++ {
+			r.values = append(r.values, v)
+		}
+		return nil
+	case rleV2Direct:
+		length, err := r.readLength(h)
+		if err != nil {
+			return err
+		}
+		width := decodeBitWidth(int(h >> 1 & 0x1f))
+		if r.packed, err = r.s.readBitPacked(r.packed[:0], length, width); err != nil {
+			return err
+		}
+		for _, x := range r.packed {
+			r.values = append(r.values, r.decodeValue(x))
+		}
+		return nil
+	case rleV2PatchedBase:
+		return r.readPatchedBase(h)
+	default:
+		return r.readDelta(h)
+	}
+}
+
+// readLength reads the 9 bits run length of the DIRECT, PATCHED_BASE and DELTA
+// sub-encodings, which is stored in the lowest bit of the header and the next byte.
+func (r *intRLEv2Reader) readLength(h byte) (int, error) {
+	b, err := r.s.readByte()
+	if err != nil {
+		return 0, err
+	}
+	return (int(h&0x01)<<8 | int(b)) + 1, nil
+}
+
+func (r *intRLEv2Reader) readPatchedBase(h byte) error {
+	length, err := r.readLength(h)
+	if err != nil {
+		return err
+	}
+	width := decodeBitWidth(int(h >> 1 & 0x1f))
+
+	b2, err := r.s.readByte()
+	if err != nil {
+		return err
+	}
+	baseWidth := int(b2>>5&0x07) + 1
+	patchWidth := decodeBitWidth(int(b2 & 0x1f))
+
+	b3, err := r.s.readByte()
+	if err != nil {
+		return err
+	}
+	patchGapWidth := int(b3>>5&0x07) + 1
+	patchListLength := int(b3 & 0x1f)
+
+	// the base value is stored in sign-magnitude format.
+	x, err := r.s.readBigEndian(baseWidth)
+	if err != nil {
+		return err
+	}
+	signMask := uint64(1) << (baseWidth*8 - 1)
+	base := int64(x &^ signMask)
+	if x&signMask != 0 {
+		base = -base
+	}
+
+	if r.packed, err = r.s.readBitPacked(r.packed[:0], length, width); err != nil {
+		return err
+	}
+	patches, err := r.s.readBitPacked(nil, patchListLength, closestFixedBits(patchWidth+patchGapWidth))
+	if err != nil {
+		return err
+	}
+	if patchListLength == 0 {
+		return errors.New("invalid orc patched base run without patches")
+	}
+
+	patchMask := uint64(1)<<patchWidth - 1
+	patchIdx := 0
+	// nextPatch returns the gap to the next patch and its value, the gap may
+	// be larger than 255 and it is split into multiple entries with zero patch.
+	nextPatch := func() (int, uint64) {
+		gap := 0
+		for {
+			g, p := int(patches[patchIdx]>>patchWidth), patches[patchIdx]&patchMask
+			if g == 255 && p == 0 && patchIdx+1 < len(patches) {
+				gap += 255
+				patchIdx++
+				continue
+			}
+			return gap + g, p
+		}
+	}
+
+	patchPos, patch := nextPatch()
+	for i, v := range r.packed {
+		if i == patchPos {
+			v |= patch << width
+			patchIdx++
+			if patchIdx < len(patches) {
+				var gap int
+				gap, patch = nextPatch()
+				patchPos = i + gap
+			}
+		}
+		r.values = append(r.values, base+int64(v))
+	}
+	return nil
+}
+
+func (r *intRLEv2Reader) readDelta(h byte) error {
+	length, err := r.readLength(h)
+	if err != nil {
+		return err
+	}
+	width := 0
+	if code := int(h >> 1 & 0x1f); code != 0 {
+		width = decodeBitWidth(code)
+	}
+
+	base, err := r.s.readVarint(r.signed)
+	if err != nil {
+		return err
+	}
+	deltaBase, err := r.s.readVarint(true)
+	if err != nil {
+		return err
+	}
+
+	r.values = append(r.values, base)
+	if length == 1 {
+		return nil
+	}
+	prev := base + deltaBase
+	r.values = append(r.values, prev)
+
+	if width == 0 {
+		// fixed delta
+		for i := 2; i < length; i++ {
+			prev += deltaBase
+			r.values = append(r.values, prev)
+		}
+		return nil
+	}
+
+	if r.packed, err = r.s.readBitPacked(r.packed[:0], length-2, width); err != nil {
+		return err
+	}
+	for _, d := range r.packed {
+		if deltaBase < 0 {
+			prev -= int64(d)
+		} else {
+			prev += int64(d)
+		}
+		r.values = append(r.values, prev)
+	}
+	return nil
+}
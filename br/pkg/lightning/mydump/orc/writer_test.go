@@ -0,0 +1,447 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orc
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// testWriter writes a simple ORC file for tests. It only uses the RLE v1
+// literals, which is enough to cover the reader since the RLE v2 decoding is
+// tested separately.
+type testWriter struct {
+	t           *testing.T
+	types       []*Type
+	compression CompressionKind
+	timezone    string
+	// columns use the dictionary encoding
+	dictColumns map[uint32]bool
+
+	buf     bytes.Buffer
+	stripes []StripeInformation
+	numRows uint64
+}
+
+// testDecimal is the value of decimal column for testWriter.
+type testDecimal struct {
+	unscaled int64
+	scale    int64
+}
+
+// testMapEntry is the entry of the value of map column for testWriter.
+type testMapEntry struct {
+	key, value interface{}
+}
+
+type testStream struct {
+	kind   streamKind
+	column uint32
+	data   []byte
+}
+
+func newTestWriter(t *testing.T, types []*Type, compression CompressionKind) *testWriter {
+	w := &testWriter{t: t, types: types, compression: compression, dictColumns: map[uint32]bool{}}
+	w.buf.WriteString(magic)
+	return w
+}
+
+func (w *testWriter) compress(data []byte) []byte {
+	if w.compression == CompressionNone {
+		return data
+	}
+
+	var compressed []byte
+	switch w.compression {
+	case CompressionZlib:
+		var b bytes.Buffer
+		fw, err := flate.NewWriter(&b, flate.DefaultCompression)
+		require.NoError(w.t, err)
+		_, err = fw.Write(data)
+		require.NoError(w.t, err)
+		require.NoError(w.t, fw.Close())
+		compressed = b.Bytes()
+	case CompressionSnappy:
+		compressed = snappy.Encode(nil, data)
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(nil)
+		require.NoError(w.t, err)
+		compressed = enc.EncodeAll(data, nil)
+		require.NoError(w.t, enc.Close())
+	default:
+		require.FailNow(w.t, "unsupported compression")
+	}
+
+	header := len(compressed) << 1
+	isOriginal := len(compressed) >= len(data)
+	if isOriginal {
+		header = len(data)<<1 | 1
+		compressed = data
+	}
+	return append([]byte{byte(header), byte(header >> 8), byte(header >> 16)}, compressed...)
+}
+
+// writeStripe writes a stripe, rows are the values of the top-level struct.
+func (w *testWriter) writeStripe(rows [][]interface{}) {
+	var (
+		streams   []testStream
+		encodings = make([]columnEncoding, len(w.types))
+	)
+	root := w.types[0]
+	for i, sub := range root.Subtypes {
+		values := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			values = append(values, row[i])
+		}
+		streams = w.encodeColumn(sub, values, streams, encodings)
+	}
+
+	offset := uint64(w.buf.Len())
+	var dataLength uint64
+	var sf []byte
+	for _, s := range streams {
+		data := w.compress(s.data)
+		w.buf.Write(data)
+		dataLength += uint64(len(data))
+
+		var msg []byte
+		msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(s.kind))
+		msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(s.column))
+		msg = protowire.AppendTag(msg, 3, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(len(data)))
+		sf = protowire.AppendTag(sf, 1, protowire.BytesType)
+		sf = protowire.AppendBytes(sf, msg)
+	}
+	for _, e := range encodings {
+		var msg []byte
+		msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(e.kind))
+		if e.dictionarySize > 0 {
+			msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, uint64(e.dictionarySize))
+		}
+		sf = protowire.AppendTag(sf, 2, protowire.BytesType)
+		sf = protowire.AppendBytes(sf, msg)
+	}
+	if w.timezone != "" {
+		sf = protowire.AppendTag(sf, 3, protowire.BytesType)
+		sf = protowire.AppendString(sf, w.timezone)
+	}
+	sf = w.compress(sf)
+	w.buf.Write(sf)
+
+	w.stripes = append(w.stripes, StripeInformation{
+		Offset:       offset,
+		DataLength:   dataLength,
+		FooterLength: uint64(len(sf)),
+		NumberOfRows: uint64(len(rows)),
+	})
+	w.numRows += uint64(len(rows))
+}
+
+func (w *testWriter) encodeColumn(column uint32, values []interface{}, streams []testStream, encodings []columnEncoding) []testStream {
+	tp := w.types[column]
+	present := make([]bool, 0, len(values))
+	hasNull := false
+	notNull := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		present = append(present, v != nil)
+		if v == nil {
+			hasNull = true
+		} else {
+			notNull = append(notNull, v)
+		}
+	}
+	if hasNull {
+		streams = append(streams, testStream{kind: streamPresent, column: column, data: encodeBools(present)})
+	}
+
+	var data, length, secondary []byte
+	switch tp.Kind {
+	case KindBoolean:
+		bools := make([]bool, 0, len(notNull))
+		for _, v := range notNull {
+			bools = append(bools, v.(bool))
+		}
+		data = encodeBools(bools)
+	case KindByte:
+		bs := make([]byte, 0, len(notNull))
+		for _, v := range notNull {
+			bs = append(bs, byte(v.(int64)))
+		}
+		data = encodeBytes(bs)
+	case KindShort, KindInt, KindLong, KindDate:
+		ints := make([]int64, 0, len(notNull))
+		for _, v := range notNull {
+			ints = append(ints, v.(int64))
+		}
+		data = encodeInts(ints, true)
+	case KindFloat:
+		for _, v := range notNull {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v.(float32)))
+		}
+	case KindDouble:
+		for _, v := range notNull {
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v.(float64)))
+		}
+	case KindBinary:
+		lengths := make([]int64, 0, len(notNull))
+		for _, v := range notNull {
+			data = append(data, v.([]byte)...)
+			lengths = append(lengths, int64(len(v.([]byte))))
+		}
+		length = encodeInts(lengths, false)
+	case KindString, KindVarchar, KindChar:
+		lengths := make([]int64, 0, len(notNull))
+		if w.dictColumns[column] {
+			dict := map[string]int64{}
+			indexes := make([]int64, 0, len(notNull))
+			var dictData []byte
+			for _, v := range notNull {
+				s := v.(string)
+				idx, ok := dict[s]
+				if !ok {
+					idx = int64(len(dict))
+					dict[s] = idx
+					dictData = append(dictData, s...)
+					lengths = append(lengths, int64(len(s)))
+				}
+				indexes = append(indexes, idx)
+			}
+			data = encodeInts(indexes, false)
+			streams = append(streams, testStream{kind: streamDictionaryData, column: column, data: dictData})
+			encodings[column] = columnEncoding{kind: encodingDictionary, dictionarySize: uint32(len(dict))}
+		} else {
+			for _, v := range notNull {
+				data = append(data, v.(string)...)
+				lengths = append(lengths, int64(len(v.(string))))
+			}
+		}
+		length = encodeInts(lengths, false)
+	case KindDecimal:
+		scales := make([]int64, 0, len(notNull))
+		for _, v := range notNull {
+			d := v.(testDecimal)
+			data = protowire.AppendVarint(data, protowire.EncodeZigZag(d.unscaled))
+			scales = append(scales, d.scale)
+		}
+		secondary = encodeInts(scales, true)
+	case KindTimestamp:
+		seconds := make([]int64, 0, len(notNull))
+		nanos := make([]int64, 0, len(notNull))
+		loc := time.UTC
+		if w.timezone != "" {
+			var err error
+			loc, err = time.LoadLocation(w.timezone)
+			require.NoError(w.t, err)
+		}
+		base := time.Date(2015, 1, 1, 0, 0, 0, 0, loc).Unix()
+		for _, v := range notNull {
+			ts := v.(time.Time)
+			wallClock := time.Date(ts.Year(), ts.Month(), ts.Day(), ts.Hour(), ts.Minute(), ts.Second(), ts.Nanosecond(), loc)
+			seconds = append(seconds, wallClock.Unix()-base)
+			nanos = append(nanos, encodeNanos(int64(ts.Nanosecond())))
+		}
+		data = encodeInts(seconds, true)
+		secondary = encodeInts(nanos, false)
+	case KindStruct:
+		for i, sub := range tp.Subtypes {
+			children := make([]interface{}, 0, len(notNull))
+			for _, v := range notNull {
+				children = append(children, v.([]interface{})[i])
+			}
+			streams = w.encodeColumn(sub, children, streams, encodings)
+		}
+	case KindList:
+		lengths := make([]int64, 0, len(notNull))
+		var children []interface{}
+		for _, v := range notNull {
+			lengths = append(lengths, int64(len(v.([]interface{}))))
+			children = append(children, v.([]interface{})...)
+		}
+		length = encodeInts(lengths, false)
+		streams = w.encodeColumn(tp.Subtypes[0], children, streams, encodings)
+	case KindMap:
+		lengths := make([]int64, 0, len(notNull))
+		var keys, vals []interface{}
+		for _, v := range notNull {
+			lengths = append(lengths, int64(len(v.([]testMapEntry))))
+			for _, e := range v.([]testMapEntry) {
+				keys = append(keys, e.key)
+				vals = append(vals, e.value)
+			}
+		}
+		length = encodeInts(lengths, false)
+		streams = w.encodeColumn(tp.Subtypes[0], keys, streams, encodings)
+		streams = w.encodeColumn(tp.Subtypes[1], vals, streams, encodings)
+	default:
+		require.FailNow(w.t, "unsupported type")
+	}
+
+	if tp.Kind != KindStruct {
+		streams = append(streams, testStream{kind: streamData, column: column, data: data})
+	}
+	if length != nil {
+		streams = append(streams, testStream{kind: streamLength, column: column, data: length})
+	}
+	if secondary != nil {
+		streams = append(streams, testStream{kind: streamSecondary, column: column, data: secondary})
+	}
+	return streams
+}
+
+// close writes the footer and the postscript, and returns the file content.
+func (w *testWriter) close() []byte {
+	var f []byte
+	f = protowire.AppendTag(f, 1, protowire.VarintType)
+	f = protowire.AppendVarint(f, uint64(len(magic)))
+	f = protowire.AppendTag(f, 2, protowire.VarintType)
+	f = protowire.AppendVarint(f, uint64(w.buf.Len()))
+	for _, s := range w.stripes {
+		var msg []byte
+		for i, v := range []uint64{s.Offset, s.IndexLength, s.DataLength, s.FooterLength, s.NumberOfRows} {
+			msg = protowire.AppendTag(msg, protowire.Number(i+1), protowire.VarintType)
+			msg = protowire.AppendVarint(msg, v)
+		}
+		f = protowire.AppendTag(f, 3, protowire.BytesType)
+		f = protowire.AppendBytes(f, msg)
+	}
+	for _, tp := range w.types {
+		var msg []byte
+		msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+		msg = protowire.AppendVarint(msg, uint64(tp.Kind))
+		if len(tp.Subtypes) > 0 {
+			var packed []byte
+			for _, sub := range tp.Subtypes {
+				packed = protowire.AppendVarint(packed, uint64(sub))
+			}
+			msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+			msg = protowire.AppendBytes(msg, packed)
+		}
+		for _, name := range tp.FieldNames {
+			msg = protowire.AppendTag(msg, 3, protowire.BytesType)
+			msg = protowire.AppendString(msg, name)
+		}
+		if tp.Kind == KindDecimal {
+			msg = protowire.AppendTag(msg, 5, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, uint64(tp.Precision))
+			msg = protowire.AppendTag(msg, 6, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, uint64(tp.Scale))
+		}
+		f = protowire.AppendTag(f, 4, protowire.BytesType)
+		f = protowire.AppendBytes(f, msg)
+	}
+	f = protowire.AppendTag(f, 6, protowire.VarintType)
+	f = protowire.AppendVarint(f, w.numRows)
+	f = w.compress(f)
+	w.buf.Write(f)
+
+	var ps []byte
+	ps = protowire.AppendTag(ps, 1, protowire.VarintType)
+	ps = protowire.AppendVarint(ps, uint64(len(f)))
+	ps = protowire.AppendTag(ps, 2, protowire.VarintType)
+	ps = protowire.AppendVarint(ps, uint64(w.compression))
+	ps = protowire.AppendTag(ps, 3, protowire.VarintType)
+	ps = protowire.AppendVarint(ps, 256*1024)
+	ps = protowire.AppendTag(ps, 8000, protowire.BytesType)
+	ps = protowire.AppendString(ps, magic)
+	w.buf.Write(ps)
+	w.buf.WriteByte(byte(len(ps)))
+	return w.buf.Bytes()
+}
+
+func encodeBools(values []bool) []byte {
+	packed := make([]byte, 0, (len(values)+7)/8)
+	for i, v := range values {
+		if i%8 == 0 {
+			packed = append(packed, 0)
+		}
+		if v {
+			packed[len(packed)-1] |= 1 << (7 - i%8)
+		}
+	}
+	var data []byte
+	for len(packed) > 0 {
+		n := len(packed)
+		if n > 128 {
+			n = 128
+		}
+		data = append(data, byte(-int8(n-1)-1))
+		data = append(data, packed[:n]...)
+		packed = packed[n:]
+	}
+	return data
+}
+
+func encodeBytes(values []byte) []byte {
+	var data []byte
+	for len(values) > 0 {
+		n := len(values)
+		if n > 128 {
+			n = 128
+		}
+		data = append(data, byte(-int8(n-1)-1))
+		data = append(data, values[:n]...)
+		values = values[n:]
+	}
+	return data
+}
+
+func encodeInts(values []int64, signed bool) []byte {
+	var data []byte
+	for len(values) > 0 {
+		n := len(values)
+		if n > 128 {
+			n = 128
+		}
+		data = append(data, byte(-int8(n-1)-1))
+		for _, v := range values[:n] {
+			if signed {
+				data = protowire.AppendVarint(data, protowire.EncodeZigZag(v))
+			} else {
+				data = protowire.AppendVarint(data, uint64(v))
+			}
+		}
+		values = values[n:]
+	}
+	return data
+}
+
+func encodeNanos(nanos int64) int64 {
+	if nanos == 0 {
+		return 0
+	}
+	if nanos%100 != 0 {
+		return nanos << 3
+	}
+	nanos /= 100
+	zeros := int64(1)
+	for nanos%10 == 0 && zeros < 7 {
+		nanos /= 10
+		zeros++
+	}
+	return nanos<<3 | zeros
+}
@@ -0,0 +1,226 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mydump
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/lightning/log"
+	"github.com/pingcap/tidb/br/pkg/lightning/mydump/orc"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/types"
+)
+
+// ORCParser parses an ORC file for import.
+// It implements the Parser interface.
+type ORCParser struct {
+	reader     *orc.Reader
+	columns    []string
+	fieldTypes []*orc.Type
+	readRows   int64
+	lastRow    Row
+	logger     log.Logger
+
+	readSeekCloser ReadSeekCloser
+}
+
+// NewORCParser generates an ORC parser.
+func NewORCParser(ctx context.Context, r storage.ReadSeekCloser) (*ORCParser, error) {
+	reader, err := orc.NewReader(r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	columns := make([]string, 0, len(reader.FieldNames()))
+	for _, name := range reader.FieldNames() {
+		columns = append(columns, strings.ToLower(name))
+	}
+
+	return &ORCParser{
+		reader:         reader,
+		columns:        columns,
+		fieldTypes:     reader.FieldTypes(),
+		logger:         log.FromContext(ctx),
+		readSeekCloser: r,
+	}, nil
+}
+
+// ReadORCFileRowCountByFile reads the ORC file row count through fileMeta.
+func ReadORCFileRowCountByFile(
+	ctx context.Context,
+	store storage.ExternalStorage,
+	fileMeta SourceFileMeta,
+) (int64, error) {
+	stripes, err := ReadORCFileStripesByFile(ctx, store, fileMeta)
+	if err != nil {
+		return 0, err
+	}
+	var numberRows int64
+	for _, stripe := range stripes {
+		numberRows += int64(stripe.NumberOfRows)
+	}
+	return numberRows, nil
+}
+
+// ReadORCFileStripesByFile reads the stripes of the ORC file through fileMeta.
+func ReadORCFileStripesByFile(
+	ctx context.Context,
+	store storage.ExternalStorage,
+	fileMeta SourceFileMeta,
+) ([]orc.StripeInformation, error) {
+	r, err := store.Open(ctx, fileMeta.Path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	//nolint: errcheck
+	defer r.Close()
+	reader, err := orc.NewReader(r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return reader.Stripes(), nil
+}
+
+// Pos returns the currently row number of the ORC file.
+func (op *ORCParser) Pos() (pos int64, rowID int64) {
+	return op.readRows, op.lastRow.RowID
+}
+
+// SetPos sets the position in an ORC file, the position is the row number.
+// It implements the Parser interface.
+func (op *ORCParser) SetPos(pos int64, rowID int64) error {
+	if err := op.reader.SeekRow(pos); err != nil {
+		return errors.Trace(err)
+	}
+	op.readRows = pos
+	op.lastRow.RowID = rowID
+	return nil
+}
+
+// ScannedPos implements the Parser interface.
+// For ORC it's the ORC file's reader current position.
+func (op *ORCParser) ScannedPos() (int64, error) {
+	return op.readSeekCloser.Seek(0, io.SeekCurrent)
+}
+
+// Close closes the ORC file of the parser.
+// It implements the Parser interface.
+func (op *ORCParser) Close() error {
+	return op.readSeekCloser.Close()
+}
+
+// ReadRow reads a row in the ORC file by the parser.
+// It implements the Parser interface.
+func (op *ORCParser) ReadRow() error {
+	op.lastRow.RowID++
+	op.lastRow.Length = 0
+	values, err := op.reader.Next()
+	if err != nil {
+		if errors.Cause(err) == io.EOF {
+			return io.EOF
+		}
+		return errors.Trace(err)
+	}
+	op.readRows++
+
+	if cap(op.lastRow.Row) < len(values) {
+		op.lastRow.Row = make([]types.Datum, len(values))
+	} else {
+		op.lastRow.Row = op.lastRow.Row[:len(values)]
+	}
+	for i, v := range values {
+		op.lastRow.Length += setORCDatum(&op.lastRow.Row[i], v, op.fieldTypes[i])
+	}
+	return nil
+}
+
+// setORCDatum converts a value read from ORC file to Datum, it returns the
+// estimated size of the value.
+func setORCDatum(d *types.Datum, v interface{}, tp *orc.Type) int {
+	switch x := v.(type) {
+	case nil:
+		d.SetNull()
+		return 0
+	case bool:
+		if x {
+			d.SetUint64(1)
+		} else {
+			d.SetUint64(0)
+		}
+	case int64:
+		d.SetInt64(x)
+	case float32:
+		d.SetFloat32(x)
+	case float64:
+		d.SetFloat64(x)
+	case string:
+		// decimal values are also strings to avoid losing precision
+		d.SetString(x, "utf8mb4_bin")
+		return len(x)
+	case []byte:
+		d.SetBytes(x)
+		return len(x)
+	case time.Time:
+		switch tp.Kind {
+		case orc.KindDate:
+			d.SetString(x.Format(orc.DateLayout), "utf8mb4_bin")
+		case orc.KindTimestampInstant:
+			d.SetString(x.UTC().Format(utcTimeLayout), "utf8mb4_bin")
+		default:
+			d.SetString(x.Format(timeLayout), "utf8mb4_bin")
+		}
+	case orc.JSON:
+		d.SetString(string(x), "utf8mb4_bin")
+		return len(x)
+	}
+	return 8
+}
+
+// LastRow gets the last row parsed by the parser.
+// It implements the Parser interface.
+func (op *ORCParser) LastRow() Row {
+	return op.lastRow
+}
+
+// RecycleRow implements the Parser interface.
+func (*ORCParser) RecycleRow(_ Row) {
+}
+
+// Columns returns the _lower-case_ column names corresponding to values in
+// the LastRow.
+func (op *ORCParser) Columns() []string {
+	return op.columns
+}
+
+// SetColumns set restored column names to parser
+func (*ORCParser) SetColumns(_ []string) {
+	// just do nothing
+}
+
+// SetLogger sets the logger used in the parser.
+// It implements the Parser interface.
+func (op *ORCParser) SetLogger(l log.Logger) {
+	op.logger = l
+}
+
+// SetRowID sets the rowID in an ORC file.
+// It implements the Parser interface.
+func (op *ORCParser) SetRowID(rowID int64) {
+	op.lastRow.RowID = rowID
+}
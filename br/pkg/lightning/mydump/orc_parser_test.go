@@ -0,0 +1,83 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mydump
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestORCParser(t *testing.T) {
+	// orc/testdata/sample.orc has 5 rows in 2 stripes, the fields of the rows
+	// whose id%3 == 2 are all NULL except id.
+	ctx := context.Background()
+	store, err := storage.NewLocalStorage("orc/testdata")
+	require.NoError(t, err)
+	fileMeta := SourceFileMeta{Path: "sample.orc", Type: SourceTypeORC}
+
+	rows, err := ReadORCFileRowCountByFile(ctx, store, fileMeta)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), rows)
+
+	r, err := store.Open(ctx, fileMeta.Path)
+	require.NoError(t, err)
+	parser, err := NewORCParser(ctx, r)
+	require.NoError(t, err)
+	defer parser.Close()
+
+	require.Equal(t, []string{"id", "name", "score", "ok", "price", "d", "ts", "s", "l", "m"}, parser.Columns())
+
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, int64(1), parser.LastRow().RowID)
+	require.Equal(t, []types.Datum{
+		types.NewIntDatum(0),
+		types.NewCollationStringDatum(`name"a`, "utf8mb4_bin"),
+		types.NewFloat64Datum(0.5),
+		types.NewUintDatum(1),
+		types.NewCollationStringDatum("0.01", "utf8mb4_bin"),
+		types.NewCollationStringDatum("1970-01-01", "utf8mb4_bin"),
+		types.NewCollationStringDatum("2023-06-01 12:30:00.123", "utf8mb4_bin"),
+		types.NewCollationStringDatum(`{"a":0,"b":null}`, "utf8mb4_bin"),
+		types.NewCollationStringDatum(`[0,null,1]`, "utf8mb4_bin"),
+		types.NewCollationStringDatum(`{"k":0}`, "utf8mb4_bin"),
+	}, parser.LastRow().Row)
+
+	// seek to the second stripe and read backward.
+	require.NoError(t, parser.SetPos(3, 10))
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, int64(11), parser.LastRow().RowID)
+	require.Equal(t, types.NewIntDatum(3), parser.LastRow().Row[0])
+	require.Equal(t, types.NewCollationStringDatum(`name"d`, "utf8mb4_bin"), parser.LastRow().Row[1])
+	require.Equal(t, types.NewUintDatum(0), parser.LastRow().Row[3])
+
+	require.NoError(t, parser.SetPos(2, 20))
+	require.NoError(t, parser.ReadRow())
+	row := parser.LastRow().Row
+	require.Equal(t, types.NewIntDatum(2), row[0])
+	for _, d := range row[1:] {
+		require.True(t, d.IsNull())
+	}
+	pos, rowID := parser.Pos()
+	require.Equal(t, int64(3), pos)
+	require.Equal(t, int64(21), rowID)
+
+	require.NoError(t, parser.SetPos(5, 0))
+	require.Equal(t, io.EOF, parser.ReadRow())
+}
@@ -96,6 +96,8 @@ type Chunk struct {
 	Offset int64
 	// for parquet file, it's the total row count
 	// see makeParquetFileRegion
+	// for orc file, Offset and EndOffset are row numbers of stripe boundaries
+	// see makeORCFileRegions
 	EndOffset  int64
 	RealOffset int64
 	// we estimate row-id range of the chunk using file-size divided by some factor(depends on column count)
@@ -114,6 +116,10 @@ type Row struct {
 	RowID  int64
 	Row    []types.Datum
 	Length int
+	// Missing marks the values which don't exist in the data file, such as the
+	// missing fields of a JSON Lines object. They should be filled with the
+	// default values of the columns rather than NULL. It's nil if all values exist.
+	Missing []bool
 }
 
 // MarshalLogArray implements the zapcore.ArrayMarshaler interface
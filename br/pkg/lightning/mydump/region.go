@@ -15,6 +15,7 @@
 package mydump
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"math"
@@ -165,7 +166,7 @@ type DataDivideConfig struct {
 	// used to split large CSV files, to limit concurrency of data read/seek operations
 	// when nil, no limit.
 	IOWorkers *worker.Pool
-	// we need it read row-count for parquet, and to read line terminator to split large CSV files
+	// we need it read row-count for parquet and orc, and to read line terminator to split large CSV and JSON Lines files
 	Store     storage.ExternalStorage
 	TableMeta *MDTableMeta
 
@@ -241,6 +242,14 @@ func MakeTableRegions(
 			dataFileSize := info.FileMeta.FileSize
 			if info.FileMeta.Type == SourceTypeParquet {
 				regions, sizes, err = makeParquetFileRegion(egCtx, cfg, info)
+			} else if info.FileMeta.Type == SourceTypeORC {
+				regions, sizes, err = makeORCFileRegions(egCtx, cfg, info)
+			} else if info.FileMeta.Type == SourceTypeJSONLines &&
+				info.FileMeta.Compression == CompressionNone &&
+				dataFileSize > cfg.MaxChunkSize+cfg.MaxChunkSize/largeCSVLowerThresholdRation {
+				// Each line of a JSON Lines file is a complete row, so it can be
+				// split at any line terminator without the strict format.
+				regions, sizes, err = SplitLargeJSONLines(egCtx, cfg, info)
 			} else if info.FileMeta.Type == SourceTypeCSV && cfg.StrictFormat &&
 				info.FileMeta.Compression == CompressionNone &&
 				dataFileSize > cfg.MaxChunkSize+cfg.MaxChunkSize/largeCSVLowerThresholdRation {
@@ -392,6 +401,132 @@ func makeParquetFileRegion(
 	return []*TableRegion{region}, []float64{float64(dataFile.FileMeta.FileSize)}, nil
 }
 
+// makeORCFileRegions splits an ORC file at the stripe boundaries, each region
+// contains whole stripes and its size is about `config.MaxRegionSize`.
+// Like parquet files, the offsets of the regions are row numbers.
+func makeORCFileRegions(
+	ctx context.Context,
+	cfg *DataDivideConfig,
+	dataFile FileInfo,
+) ([]*TableRegion, []float64, error) {
+	stripes, err := ReadORCFileStripesByFile(ctx, cfg.Store, dataFile.FileMeta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		regions       []*TableRegion
+		dataFileSizes []float64
+		startRow      int64
+		endRow        int64
+		size          int64
+	)
+	appendRegion := func() {
+		regions = append(regions, &TableRegion{
+			DB:       cfg.TableMeta.DB,
+			Table:    cfg.TableMeta.Name,
+			FileMeta: dataFile.FileMeta,
+			Chunk: Chunk{
+				Offset:       startRow,
+				EndOffset:    endRow,
+				RealOffset:   0,
+				PrevRowIDMax: startRow,
+				RowIDMax:     endRow,
+			},
+		})
+		dataFileSizes = append(dataFileSizes, float64(size))
+		startRow, size = endRow, 0
+	}
+	for _, stripe := range stripes {
+		endRow += int64(stripe.NumberOfRows)
+		size += int64(stripe.IndexLength + stripe.DataLength + stripe.FooterLength)
+		if cfg.MaxChunkSize > 0 && size >= cfg.MaxChunkSize {
+			appendRegion()
+		}
+	}
+	if size > 0 || len(regions) == 0 {
+		appendRegion()
+	}
+	return regions, dataFileSizes, nil
+}
+
+// SplitLargeJSONLines splits a large JSON Lines file into multiple regions at
+// the line terminators, the size of each regions is about `config.MaxRegionSize`.
+func SplitLargeJSONLines(
+	ctx context.Context,
+	cfg *DataDivideConfig,
+	dataFile FileInfo,
+) (regions []*TableRegion, dataFileSizes []float64, err error) {
+	maxRegionSize := cfg.MaxChunkSize
+	fileSize := dataFile.FileMeta.FileSize
+	dataFileSizes = make([]float64, 0, fileSize/maxRegionSize+1)
+	divisor := int64(cfg.ColumnCnt) + 2
+	startOffset, endOffset := int64(0), mathutil.Min(maxRegionSize, fileSize)
+	var prevRowIDMax int64
+	for {
+		if endOffset < fileSize {
+			if endOffset, err = findNextLineEnd(ctx, cfg.Store, dataFile.FileMeta.Path, endOffset); err != nil {
+				return nil, nil, err
+			}
+			if endOffset < 0 {
+				log.FromContext(ctx).Warn("file contains no terminator at end",
+					zap.String("path", dataFile.FileMeta.Path))
+				endOffset = fileSize
+			}
+		}
+		rowIDMax := prevRowIDMax + (endOffset-startOffset)/divisor
+		regions = append(regions,
+			&TableRegion{
+				DB:       cfg.TableMeta.DB,
+				Table:    cfg.TableMeta.Name,
+				FileMeta: dataFile.FileMeta,
+				Chunk: Chunk{
+					Offset:       startOffset,
+					EndOffset:    endOffset,
+					PrevRowIDMax: prevRowIDMax,
+					RowIDMax:     rowIDMax,
+				},
+			})
+		dataFileSizes = append(dataFileSizes, float64(endOffset-startOffset))
+		prevRowIDMax = rowIDMax
+		if endOffset == fileSize {
+			break
+		}
+		startOffset = endOffset
+		endOffset = mathutil.Min(endOffset+maxRegionSize, fileSize)
+	}
+	return regions, dataFileSizes, nil
+}
+
+// findNextLineEnd returns the offset after the first '\n' at or after `offset`,
+// it returns -1 if there is no more '\n' in the file.
+func findNextLineEnd(ctx context.Context, store storage.ExternalStorage, path string, offset int64) (int64, error) {
+	r, err := store.Open(ctx, path)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	//nolint: errcheck
+	defer r.Close()
+	if _, err = r.Seek(offset, io.SeekStart); err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return offset + int64(i) + 1, nil
+		}
+		offset += int64(n)
+		if err == io.EOF {
+			return -1, nil
+		}
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+}
+
 // SplitLargeCSV splits a large csv file into multiple regions, the size of
 // each regions is specified by `config.MaxRegionSize`.
 // Note: We split the file coarsely, thus the format of csv file is needed to be
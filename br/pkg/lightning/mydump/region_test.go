@@ -487,3 +487,92 @@ func TestSplitLargeFileOnlyOneChunk(t *testing.T) {
 		require.Equal(t, columns, regions[i].Chunk.Columns)
 	}
 }
+
+func TestSplitLargeJSONLines(t *testing.T) {
+	meta := &MDTableMeta{
+		DB:   "jsonl",
+		Name: "large_jsonl_file",
+	}
+	cfg := &config.Config{
+		Mydumper: config.MydumperRuntime{
+			ReadBlockSize: config.ReadBlockSize,
+			Filter:        []string{"*.*"},
+			MaxRegionSize: 5,
+		},
+	}
+
+	dir := t.TempDir()
+
+	fileName := "test.jsonl"
+	filePath := filepath.Join(dir, fileName)
+
+	content := []byte("{\"a\":1}\n{\"a\":22}\n{\"a\":333}")
+	err := os.WriteFile(filePath, content, 0o644)
+	require.NoError(t, err)
+
+	fileInfo := FileInfo{FileMeta: SourceFileMeta{Path: fileName, Type: SourceTypeJSONLines, FileSize: int64(len(content))}}
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	meta.DataFiles = []FileInfo{fileInfo}
+	divideConfig := NewDataDivideConfig(cfg, 1, nil, store, meta)
+
+	offsets := [][]int64{{0, 8}, {8, 17}, {17, 26}}
+
+	regions, _, err := SplitLargeJSONLines(context.Background(), divideConfig, fileInfo)
+	require.NoError(t, err)
+	require.Len(t, regions, len(offsets))
+	for i := range offsets {
+		require.Equal(t, offsets[i][0], regions[i].Chunk.Offset)
+		require.Equal(t, offsets[i][1], regions[i].Chunk.EndOffset)
+	}
+
+	regions, err = MakeTableRegions(context.Background(), divideConfig)
+	require.NoError(t, err)
+	require.Len(t, regions, len(offsets))
+	for i := range offsets {
+		require.Equal(t, offsets[i][0], regions[i].Chunk.Offset)
+		require.Equal(t, offsets[i][1], regions[i].Chunk.EndOffset)
+	}
+}
+
+func TestMakeORCFileRegions(t *testing.T) {
+	dir := "./orc/testdata"
+	fileName := "sample.orc"
+	dataFileInfo, err := os.Stat(filepath.Join(dir, fileName))
+	require.NoError(t, err)
+	fileInfo := FileInfo{FileMeta: SourceFileMeta{Path: fileName, Type: SourceTypeORC, FileSize: dataFileInfo.Size()}}
+	meta := &MDTableMeta{
+		DB:        "orc",
+		Name:      "sample",
+		DataFiles: []FileInfo{fileInfo},
+	}
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+
+	// the file has 2 stripes, which have 3 and 2 rows.
+	for _, c := range []struct {
+		maxRegionSize config.ByteSize
+		offsets       [][]int64
+	}{
+		{maxRegionSize: 1, offsets: [][]int64{{0, 3}, {3, 5}}},
+		{maxRegionSize: config.MaxRegionSize, offsets: [][]int64{{0, 5}}},
+	} {
+		cfg := &config.Config{
+			Mydumper: config.MydumperRuntime{
+				ReadBlockSize: config.ReadBlockSize,
+				Filter:        []string{"*.*"},
+				MaxRegionSize: c.maxRegionSize,
+			},
+		}
+		divideConfig := NewDataDivideConfig(cfg, 10, nil, store, meta)
+		regions, err := MakeTableRegions(context.Background(), divideConfig)
+		require.NoError(t, err)
+		require.Len(t, regions, len(c.offsets))
+		for i := range c.offsets {
+			require.Equal(t, c.offsets[i][0], regions[i].Chunk.Offset)
+			require.Equal(t, c.offsets[i][1], regions[i].Chunk.EndOffset)
+			require.Equal(t, c.offsets[i][0], regions[i].Chunk.PrevRowIDMax)
+			require.Equal(t, c.offsets[i][1], regions[i].Chunk.RowIDMax)
+		}
+	}
+}
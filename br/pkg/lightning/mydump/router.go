@@ -34,6 +34,10 @@ const (
 	SourceTypeParquet
 	// SourceTypeViewSchema means this source file is a schema file for the view.
 	SourceTypeViewSchema
+	// SourceTypeJSONLines means this source file is a JSON Lines data file.
+	SourceTypeJSONLines
+	// SourceTypeORC means this source file is an ORC data file.
+	SourceTypeORC
 )
 
 const (
@@ -49,6 +53,12 @@ const (
 	TypeCSV = "csv"
 	// TypeParquet is the source type value for parquet data file.
 	TypeParquet = "parquet"
+	// TypeJSONLines is the source type value for JSON Lines data file.
+	TypeJSONLines = "jsonl"
+	// TypeNDJSON is an alias of TypeJSONLines, for files with the .ndjson extension.
+	TypeNDJSON = "ndjson"
+	// TypeORC is the source type value for ORC data file.
+	TypeORC = "orc"
 	// TypeIgnore is the source type value for a ignored data file.
 	TypeIgnore = "ignore"
 )
@@ -102,6 +112,10 @@ func parseSourceType(t string) (SourceType, error) {
 		return SourceTypeCSV, nil
 	case TypeParquet:
 		return SourceTypeParquet, nil
+	case TypeJSONLines, TypeNDJSON:
+		return SourceTypeJSONLines, nil
+	case TypeORC:
+		return SourceTypeORC, nil
 	case TypeIgnore:
 		return SourceTypeIgnore, nil
 	case ViewSchema:
@@ -123,6 +137,10 @@ func (s SourceType) String() string {
 		return TypeSQL
 	case SourceTypeParquet:
 		return TypeParquet
+	case SourceTypeJSONLines:
+		return TypeJSONLines
+	case SourceTypeORC:
+		return TypeORC
 	case SourceTypeViewSchema:
 		return ViewSchema
 	default:
@@ -171,7 +189,7 @@ var defaultFileRouteRules = []*config.FileRouteRule{
 	// ignore *-schema-trigger.sql, *-schema-post.sql files
 	{Pattern: `(?i).*(-schema-trigger|-schema-post)\.sql(?:\.(\w*?))?$`, Type: "ignore"},
 	// ignore backup files
-	{Pattern: `(?i).*\.(sql|csv|parquet)(\.(\w+))?\.(bak|BAK)$`, Type: "ignore"},
+	{Pattern: `(?i).*\.(sql|csv|parquet|jsonl|ndjson|orc)(\.(\w+))?\.(bak|BAK)$`, Type: "ignore"},
 	// db schema create file pattern, matches files like '{schema}-schema-create.sql[.{compress}]'
 	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)-schema-create\.sql(?:\.(\w*?))?$`,
 		Schema: "$1", Table: "", Type: SchemaSchema, Compression: "$2", Unescape: true},
@@ -181,8 +199,8 @@ var defaultFileRouteRules = []*config.FileRouteRule{
 	// view schema create file pattern, matches files like '{schema}.{table}-schema-view.sql[.{compress}]'
 	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)-schema-view\.sql(?:\.(\w*?))?$`,
 		Schema: "$1", Table: "$2", Type: ViewSchema, Compression: "$3", Unescape: true},
-	// source file pattern, matches files like '{schema}.{table}.0001.{sql|csv}[.{compress}]'
-	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)(?:\.([0-9]+))?\.(sql|csv|parquet)(?:\.(\w+))?$`,
+	// source file pattern, matches files like '{schema}.{table}.0001.{sql|csv|parquet|jsonl|ndjson|orc}[.{compress}]'
+	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)(?:\.([0-9]+))?\.(sql|csv|parquet|jsonl|ndjson|orc)(?:\.(\w+))?$`,
 		Schema: "$1", Table: "$2", Type: "$4", Key: "$3", Compression: "$5", Unescape: true},
 }
 
@@ -347,6 +365,9 @@ func (p regexRouterParser) Parse(r *config.FileRouteRule, logger log.Logger) (*R
 			if result.Type == SourceTypeParquet && compression != CompressionNone {
 				return errors.Errorf("can't support whole compressed parquet file, should compress parquet files by choosing correct parquet compress writer, path: %s", r.Path)
 			}
+			if result.Type == SourceTypeORC && compression != CompressionNone {
+				return errors.Errorf("can't support whole compressed orc file, should compress orc files by choosing correct orc compression codec, path: %s", r.Path)
+			}
 			result.Compression = compression
 			return nil
 		})
@@ -55,6 +55,10 @@ func TestDefaultRouter(t *testing.T) {
 		"/test/123/my_schema.my_table.sql.gz":    {"my_schema", "my_table", "", "gz", "sql"},
 		"my_dir/my_schema.my_table.csv.lzo":      {"my_schema", "my_table", "", "lzo", "csv"},
 		"my_schema.my_table.0001.sql.snappy":     {"my_schema", "my_table", "0001", "snappy", "sql"},
+		"my_schema.my_table.0001.jsonl":          {"my_schema", "my_table", "0001", "", "jsonl"},
+		"my_schema.my_table.ndjson.gz":           {"my_schema", "my_table", "", "gz", "ndjson"},
+		"my_schema.my_table.orc":                 {"my_schema", "my_table", "", "", "orc"},
+		"my_schema.my_table.orc.bak":             nil,
 	}
 	for path, fields := range inputOutputMap {
 		res, err := r.Route(path)
@@ -1677,6 +1677,85 @@ func (rc *Client) GoValidateChecksum(
 	return outCh
 }
 
+// GoRewriteGlobalIndexes forks a goroutine to rewrite the partition IDs in the values of the global indexes
+// after restore, because only the keys are rewritten when importing the SST files.
+func (rc *Client) GoRewriteGlobalIndexes(
+	ctx context.Context,
+	inCh <-chan *CreatedTable,
+	errCh chan<- error,
+) chan *CreatedTable {
+	log.Info("Start to rewrite global indexes")
+	outCh := DefaultOutputTableChan()
+	workers := utils.NewWorkerPool(defaultChecksumConcurrency, "RestoreGlobalIndex")
+	go concurrentHandleTablesCh(ctx, inCh, outCh, errCh, workers, func(c context.Context, tbl *CreatedTable) error {
+		return errors.Trace(rewriteGlobalIndexes(c, rc.GetDomain().Store(), tbl.Table, tbl.OldTable.Info))
+	}, func() {
+		log.Info("all global indexes rewritten")
+	})
+	return outCh
+}
+
+// rewriteGlobalIndexBatchSize is the number of the index entries rewritten in a transaction.
+const rewriteGlobalIndexBatchSize = 1024
+
+func rewriteGlobalIndexes(ctx context.Context, store kv.Storage, newTable, oldTable *model.TableInfo) error {
+	if newTable.Partition == nil || oldTable.Partition == nil {
+		return nil
+	}
+	partIDs := make(map[int64]int64, len(newTable.Partition.Definitions))
+	for oldID, newID := range getTableIDMap(newTable, oldTable) {
+		if oldID != oldTable.ID {
+			partIDs[oldID] = newID
+		}
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnBR)
+	for _, idx := range newTable.Indices {
+		if !idx.Global {
+			continue
+		}
+		start := tablecodec.EncodeTableIndexPrefix(newTable.ID, idx.ID)
+		end := start.PrefixNext()
+		for len(start) > 0 {
+			err := kv.RunInNewTxn(ctx, store, true, func(_ context.Context, txn kv.Transaction) error {
+				it, err := txn.Iter(start, end)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				defer it.Close()
+				next := kv.Key(nil)
+				for cnt := 0; it.Valid(); cnt++ {
+					if cnt == rewriteGlobalIndexBatchSize {
+						next = it.Key().Clone()
+						break
+					}
+					pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(it.Value())
+					if err != nil {
+						return errors.Trace(err)
+					}
+					// The partition ID is already rewritten if it is not an old partition ID,
+					// the partition IDs are never reused.
+					if newPID, exists := partIDs[pid]; ok && exists {
+						val, _ := tablecodec.RewritePartitionIDInIndexValue(it.Value(), newPID)
+						if err = txn.Set(it.Key(), val); err != nil {
+							return errors.Trace(err)
+						}
+					}
+					if err = it.Next(); err != nil {
+						return errors.Trace(err)
+					}
+				}
+				start = next
+				return nil
+			})
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
 func (rc *Client) execChecksum(
 	ctx context.Context,
 	tbl *CreatedTable,
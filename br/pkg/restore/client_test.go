@@ -26,9 +26,11 @@ import (
 	"github.com/pingcap/tidb/br/pkg/stream"
 	"github.com/pingcap/tidb/br/pkg/utils"
 	"github.com/pingcap/tidb/br/pkg/utils/iter"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/tablecodec"
 	filter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/stretchr/testify/require"
@@ -115,6 +117,57 @@ func TestIsOnline(t *testing.T) {
 	require.True(t, client.IsOnline())
 }
 
+func TestRewriteGlobalIndexes(t *testing.T) {
+	m := mc
+	g := gluetidb.New()
+	client := restore.NewRestoreClient(m.PDClient, nil, defaultKeepaliveCfg, false)
+	err := client.Init(g, m.Storage)
+	require.NoError(t, err)
+
+	idx := &model.IndexInfo{ID: 1, Name: model.NewCIStr("idx"), Unique: true, Global: true, State: model.StatePublic}
+	oldTable := &model.TableInfo{ID: 10, Indices: []*model.IndexInfo{idx}, Partition: &model.PartitionInfo{Definitions: []model.PartitionDefinition{
+		{ID: 11, Name: model.NewCIStr("p0")}, {ID: 12, Name: model.NewCIStr("p1")}}}}
+	newTable := &model.TableInfo{ID: 20, Indices: []*model.IndexInfo{idx}, Partition: &model.PartitionInfo{Definitions: []model.PartitionDefinition{
+		{ID: 21, Name: model.NewCIStr("p0")}, {ID: 22, Name: model.NewCIStr("p1")}}}}
+
+	// The keys are rewritten by the SST importer, but the partition IDs in the values are not.
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBR)
+	pids := []int64{11, 12, 22}
+	keys := make([]kv.Key, 0, len(pids))
+	err = kv.RunInNewTxn(ctx, m.Storage, true, func(_ context.Context, txn kv.Transaction) error {
+		for i, pid := range pids {
+			key := tablecodec.EncodeIndexSeekKey(newTable.ID, idx.ID, []byte{byte(i)})
+			val, err := tablecodec.GenIndexValuePortal(&stmtctx.StatementContext{}, newTable, idx, false, true, false, nil, kv.IntHandle(i), pid, nil)
+			require.NoError(t, err)
+			require.NoError(t, txn.Set(key, val))
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	inCh := make(chan *restore.CreatedTable, 1)
+	errCh := make(chan error, 1)
+	inCh <- &restore.CreatedTable{Table: newTable, OldTable: &metautil.Table{Info: oldTable}}
+	close(inCh)
+	for range client.GoRewriteGlobalIndexes(ctx, inCh, errCh) {
+	}
+	require.Len(t, errCh, 0)
+
+	err = kv.RunInNewTxn(ctx, m.Storage, false, func(_ context.Context, txn kv.Transaction) error {
+		for i, expected := range []int64{21, 22, 22} {
+			val, err := txn.Get(context.Background(), keys[i])
+			require.NoError(t, err)
+			pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(val)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, expected, pid)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func getStartedMockedCluster(t *testing.T) *mock.Cluster {
 	t.Helper()
 	cluster, err := mock.NewCluster()
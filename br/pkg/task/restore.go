@@ -965,6 +965,9 @@ func runRestore(c context.Context, g glue.Glue, cmdName string, cfg *RestoreConf
 		postHandleCh = afterTableLoadStatsCh
 	}
 
+	// pipeline rewrite the partition IDs in global indexes, after the checksum which is calculated on the backup data
+	postHandleCh = client.GoRewriteGlobalIndexes(ctx, postHandleCh, errCh)
+
 	// pipeline wait Tiflash synced
 	if cfg.WaitTiflashReady {
 		postHandleCh = client.GoWaitTiFlashReady(ctx, postHandleCh, updateCh, errCh)
@@ -198,6 +198,7 @@ type Config struct {
 	Performance                Performance             `toml:"performance" json:"performance"`
 	PreparedPlanCache          PreparedPlanCache       `toml:"prepared-plan-cache" json:"prepared-plan-cache"`
 	OpenTracing                OpenTracing             `toml:"opentracing" json:"opentracing"`
+	OpenTelemetry              OpenTelemetry           `toml:"opentelemetry" json:"opentelemetry"`
 	ProxyProtocol              ProxyProtocol           `toml:"proxy-protocol" json:"proxy-protocol"`
 	PDClient                   tikvcfg.PDClient        `toml:"pd-client" json:"pd-client"`
 	TiKVClient                 tikvcfg.TiKVClient      `toml:"tikv-client" json:"tikv-client"`
@@ -240,7 +241,8 @@ type Config struct {
 	// 2. 'zone' is a special key that indicates the DC location of this tidb-server. If it is set, the value for this
 	// key will be the default value of the session variable `txn_scope` for this tidb-server.
 	Labels map[string]string `toml:"labels" json:"labels"`
-	// EnableGlobalIndex enables creating global index.
+	// EnableGlobalIndex makes the unique indexes which do not include all the partitioning columns
+	// global indexes implicitly. Global indexes can always be created by the GLOBAL index option.
 	EnableGlobalIndex bool `toml:"enable-global-index" json:"enable-global-index"`
 	// DeprecateIntegerDisplayWidth indicates whether deprecating the max display length for integer.
 	DeprecateIntegerDisplayWidth bool `toml:"deprecate-integer-display-length" json:"deprecate-integer-display-length"`
@@ -527,6 +529,16 @@ type Instance struct {
 	// StmtSummaryFileMaxBackups indicates the maximum number of files written
 	// by stmtsummary when StmtSummaryEnablePersistent is true.
 	StmtSummaryFileMaxBackups int `toml:"tidb_stmt_summary_file_max_backups" json:"tidb_stmt_summary_file_max_backups"`
+	// StmtSummaryEnableCompaction indicates whether to persist stmtsummary to a compacted store
+	// instead of the log files when StmtSummaryEnablePersistent is true.
+	StmtSummaryEnableCompaction bool `toml:"tidb_stmt_summary_enable_compaction" json:"tidb_stmt_summary_enable_compaction"`
+	// StmtSummaryCompactionInterval indicates the length (in seconds) of the time buckets which
+	// the statements are compacted into. The buckets are aligned to the unix epoch.
+	StmtSummaryCompactionInterval int `toml:"tidb_stmt_summary_compaction_interval" json:"tidb_stmt_summary_compaction_interval"`
+	// StmtSummaryHistoryMaxDays indicates how many days the compacted statements will be kept.
+	StmtSummaryHistoryMaxDays int `toml:"tidb_stmt_summary_history_max_days" json:"tidb_stmt_summary_history_max_days"`
+	// StmtSummaryHistoryMaxSize indicates the maximum total size (in mb) of the compacted statements.
+	StmtSummaryHistoryMaxSize int `toml:"tidb_stmt_summary_history_max_size" json:"tidb_stmt_summary_history_max_size"`
 
 	// These variables exist in both 'instance' section and another place.
 	// The configuration in 'instance' section takes precedence.
@@ -776,6 +788,28 @@ type OpenTracingReporter struct {
 	LocalAgentHostPort  string        `toml:"local-agent-host-port" json:"local-agent-host-port"`
 }
 
+// OpenTelemetry is the opentelemetry section of the config.
+// The spans of the SQL execution are exported to the OTLP collector when it's enabled.
+type OpenTelemetry struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// Exporter is the way to export the spans, it can be "grpc", "http", "file" or "stdout".
+	Exporter string `toml:"exporter" json:"exporter"`
+	// Endpoint is `host:port` of the collector for "grpc", or the URL of the collector for "http".
+	Endpoint string `toml:"endpoint" json:"endpoint"`
+	// Insecure disables TLS for "grpc".
+	Insecure bool `toml:"insecure" json:"insecure"`
+	// Headers are sent with the export requests, e.g. the authentication token of the collector.
+	Headers map[string]string `toml:"headers" json:"-"`
+	// FilePath is the file written by the "file" exporter.
+	FilePath string `toml:"file-path" json:"file-path"`
+	// SampleRate is the ratio of the statements traced if the client doesn't pass the W3C trace context.
+	SampleRate float64 `toml:"sample-rate" json:"sample-rate"`
+	// BatchSize is the max number of spans exported in one request.
+	BatchSize int `toml:"batch-size" json:"batch-size"`
+	// QueueSize is the max number of spans waiting to be exported, the spans exceeding it are dropped.
+	QueueSize int `toml:"queue-size" json:"queue-size"`
+}
+
 // ProxyProtocol is the PROXY protocol section of the config.
 type ProxyProtocol struct {
 	// PROXY protocol acceptable client networks.
@@ -862,6 +896,40 @@ type Plugin struct {
 type TopSQL struct {
 	// The TopSQL's data receiver address.
 	ReceiverAddress string `toml:"receiver-address" json:"receiver-address"`
+	// FileSink writes the TopSQL data to local files.
+	FileSink TopSQLFileSink `toml:"file-sink" json:"file-sink"`
+	// MetricsSink exposes the top N SQL as metrics.
+	MetricsSink TopSQLMetricsSink `toml:"metrics-sink" json:"metrics-sink"`
+}
+
+// TopSQLFileSink is the config for writing the TopSQL data to local files.
+type TopSQLFileSink struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// Filename is the file written by the sink, the data are written in JSON lines.
+	Filename string `toml:"filename" json:"filename"`
+	// MaxSize is the max size (in MB) of a file before it's rotated.
+	MaxSize int `toml:"max-size" json:"max-size"`
+	// MaxDays is the max days to keep the rotated files, 0 means never removing them by the age.
+	MaxDays int `toml:"max-days" json:"max-days"`
+	// MaxBackups is the max number of the rotated files, 0 means never removing them by the number.
+	MaxBackups int `toml:"max-backups" json:"max-backups"`
+}
+
+// TopSQLMetricsSink is the config for exposing the top N SQL as metrics.
+type TopSQLMetricsSink struct {
+	// TopN is the number of SQL exposed in each report interval, the others are aggregated into one.
+	TopN int `toml:"top-n" json:"top-n"`
+	// EnablePrometheus exposes the top N SQL by the Prometheus metrics of the status port.
+	EnablePrometheus bool `toml:"enable-prometheus" json:"enable-prometheus"`
+	// OTLPEndpoint is the address of the OTLP collector, empty means not exporting the metrics by OTLP.
+	// It's `host:port` for "grpc", or the URL of the collector for "http".
+	OTLPEndpoint string `toml:"otlp-endpoint" json:"otlp-endpoint"`
+	// OTLPProtocol is "grpc" or "http".
+	OTLPProtocol string `toml:"otlp-protocol" json:"otlp-protocol"`
+	// OTLPInsecure disables TLS for "grpc".
+	OTLPInsecure bool `toml:"otlp-insecure" json:"otlp-insecure"`
+	// OTLPHeaders are sent with the export requests, e.g. the authentication token of the collector.
+	OTLPHeaders map[string]string `toml:"otlp-headers" json:"-"`
 }
 
 // IsolationRead is the config for isolation read.
@@ -933,28 +1001,32 @@ var defaultConf = Config{
 		EnableSlowLog:       *NewAtomicBool(logutil.DefaultTiDBEnableSlowLog),
 	},
 	Instance: Instance{
-		TiDBGeneralLog:              false,
-		EnablePProfSQLCPU:           false,
-		DDLSlowOprThreshold:         DefDDLSlowOprThreshold,
-		ExpensiveQueryTimeThreshold: DefExpensiveQueryTimeThreshold,
-		ExpensiveTxnTimeThreshold:   DefExpensiveTxnTimeThreshold,
-		StmtSummaryEnablePersistent: false,
-		StmtSummaryFilename:         "tidb-statements.log",
-		StmtSummaryFileMaxDays:      3,
-		StmtSummaryFileMaxSize:      64,
-		StmtSummaryFileMaxBackups:   0,
-		EnableSlowLog:               *NewAtomicBool(logutil.DefaultTiDBEnableSlowLog),
-		SlowThreshold:               logutil.DefaultSlowThreshold,
-		RecordPlanInSlowLog:         logutil.DefaultRecordPlanInSlowLog,
-		CheckMb4ValueInUTF8:         *NewAtomicBool(true),
-		ForcePriority:               "NO_PRIORITY",
-		MemoryUsageAlarmRatio:       DefMemoryUsageAlarmRatio,
-		EnableCollectExecutionInfo:  *NewAtomicBool(true),
-		PluginDir:                   "/data/deploy/plugin",
-		PluginLoad:                  "",
-		MaxConnections:              0,
-		TiDBEnableDDL:               *NewAtomicBool(true),
-		TiDBRCReadCheckTS:           false,
+		TiDBGeneralLog:                false,
+		EnablePProfSQLCPU:             false,
+		DDLSlowOprThreshold:           DefDDLSlowOprThreshold,
+		ExpensiveQueryTimeThreshold:   DefExpensiveQueryTimeThreshold,
+		ExpensiveTxnTimeThreshold:     DefExpensiveTxnTimeThreshold,
+		StmtSummaryEnablePersistent:   false,
+		StmtSummaryFilename:           "tidb-statements.log",
+		StmtSummaryFileMaxDays:        3,
+		StmtSummaryFileMaxSize:        64,
+		StmtSummaryFileMaxBackups:     0,
+		StmtSummaryEnableCompaction:   false,
+		StmtSummaryCompactionInterval: 3600,
+		StmtSummaryHistoryMaxDays:     30,
+		StmtSummaryHistoryMaxSize:     1024,
+		EnableSlowLog:                 *NewAtomicBool(logutil.DefaultTiDBEnableSlowLog),
+		SlowThreshold:                 logutil.DefaultSlowThreshold,
+		RecordPlanInSlowLog:           logutil.DefaultRecordPlanInSlowLog,
+		CheckMb4ValueInUTF8:           *NewAtomicBool(true),
+		ForcePriority:                 "NO_PRIORITY",
+		MemoryUsageAlarmRatio:         DefMemoryUsageAlarmRatio,
+		EnableCollectExecutionInfo:    *NewAtomicBool(true),
+		PluginDir:                     "/data/deploy/plugin",
+		PluginLoad:                    "",
+		MaxConnections:                0,
+		TiDBEnableDDL:                 *NewAtomicBool(true),
+		TiDBRCReadCheckTS:             false,
 	},
 	Status: Status{
 		ReportStatus:          true,
@@ -1020,6 +1092,15 @@ var defaultConf = Config{
 		},
 		Reporter: OpenTracingReporter{},
 	},
+	OpenTelemetry: OpenTelemetry{
+		Enable:     false,
+		Exporter:   "grpc",
+		Endpoint:   "127.0.0.1:4317",
+		Insecure:   true,
+		SampleRate: 0.01,
+		BatchSize:  512,
+		QueueSize:  4096,
+	},
 	PDClient:   defTiKVCfg.PDClient,
 	TiKVClient: defTiKVCfg.TiKVClient,
 	Binlog: Binlog{
@@ -1031,6 +1112,17 @@ var defaultConf = Config{
 		Load: "",
 	},
 	PessimisticTxn: DefaultPessimisticTxn(),
+	TopSQL: TopSQL{
+		FileSink: TopSQLFileSink{
+			Filename: "tidb-topsql.log",
+			MaxSize:  64,
+		},
+		MetricsSink: TopSQLMetricsSink{
+			TopN:         10,
+			OTLPProtocol: "grpc",
+			OTLPInsecure: true,
+		},
+	},
 	IsolationRead: IsolationRead{
 		Engines: []string{"tikv", "tiflash", "tidb"},
 	},
@@ -1351,6 +1443,9 @@ func (c *Config) Valid() error {
 	if c.Instance.MemoryUsageAlarmRatio > 1 || c.Instance.MemoryUsageAlarmRatio < 0 {
 		return fmt.Errorf("tidb_memory_usage_alarm_ratio in [Instance] must be greater than or equal to 0 and less than or equal to 1")
 	}
+	if c.Instance.StmtSummaryEnableCompaction && c.Instance.StmtSummaryCompactionInterval < 60 {
+		return fmt.Errorf("tidb_stmt_summary_compaction_interval in [Instance] must be greater than or equal to 60")
+	}
 
 	if len(c.IsolationRead.Engines) < 1 {
 		return fmt.Errorf("the number of [isolation-read]engines for isolation read should be at least 1")
@@ -1361,6 +1456,28 @@ func (c *Config) Valid() error {
 		}
 	}
 
+	if c.OpenTelemetry.Enable {
+		switch strings.ToLower(c.OpenTelemetry.Exporter) {
+		case "grpc", "http", "file", "stdout":
+		default:
+			return fmt.Errorf("unsupported [opentelemetry]exporter %v, it should be one of grpc, http, file or stdout", c.OpenTelemetry.Exporter)
+		}
+		if c.OpenTelemetry.SampleRate < 0 || c.OpenTelemetry.SampleRate > 1 {
+			return fmt.Errorf("[opentelemetry]sample-rate should be [0, 1]")
+		}
+	}
+
+	if c.TopSQL.FileSink.Enable && c.TopSQL.FileSink.Filename == "" {
+		return fmt.Errorf("[top-sql.file-sink]filename can't be empty")
+	}
+	if c.TopSQL.MetricsSink.OTLPEndpoint != "" {
+		switch strings.ToLower(c.TopSQL.MetricsSink.OTLPProtocol) {
+		case "grpc", "http":
+		default:
+			return fmt.Errorf("unsupported [top-sql.metrics-sink]otlp-protocol %v, it should be grpc or http", c.TopSQL.MetricsSink.OTLPProtocol)
+		}
+	}
+
 	// test security
 	c.Security.SpilledFileEncryptionMethod = strings.ToLower(c.Security.SpilledFileEncryptionMethod)
 	switch c.Security.SpilledFileEncryptionMethod {
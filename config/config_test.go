@@ -1170,6 +1170,24 @@ func TestTableColumnCountLimit(t *testing.T) {
 	checkValid(DefMaxOfTableColumnCountLimit+1, false)
 }
 
+func TestOpenTelemetryValid(t *testing.T) {
+	conf := NewConfig()
+	conf.OpenTelemetry.Enable = true
+	require.NoError(t, conf.Valid())
+	for _, exporter := range []string{"grpc", "http", "FILE", "stdout"} {
+		conf.OpenTelemetry.Exporter = exporter
+		require.NoError(t, conf.Valid())
+	}
+	conf.OpenTelemetry.Exporter = "jaeger"
+	require.Error(t, conf.Valid())
+	conf.OpenTelemetry.Exporter = "grpc"
+	conf.OpenTelemetry.SampleRate = 1.5
+	require.Error(t, conf.Valid())
+	// the config isn't checked if it's disabled
+	conf.OpenTelemetry.Enable = false
+	require.NoError(t, conf.Valid())
+}
+
 func TestEncodeDefTempStorageDir(t *testing.T) {
 	tests := []struct {
 		host       string
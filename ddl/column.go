@@ -294,6 +294,7 @@ func onDropColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error)
 		// All reorganization jobs are done, drop this column.
 		tblInfo.MoveColumnInfo(colInfo.Offset, len(tblInfo.Columns)-1)
 		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
+		removeMaskingPolicyOfColumn(tblInfo, colInfo.ID)
 		colInfo.State = model.StateNone
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != colInfo.State)
 		if err != nil {
@@ -555,10 +556,6 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
-	if tblInfo.Partition != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("table is partition table"))
-	}
 
 	changingCol := modifyInfo.changingCol
 	if changingCol == nil {
@@ -885,6 +882,7 @@ func adjustTableInfoAfterModifyColumnWithData(tblInfo *model.TableInfo, pos *ast
 	if tblInfo.TTLInfo != nil {
 		updateTTLInfoWhenModifyColumn(tblInfo, oldCol.Name, changingCol.Name)
 	}
+	updateMaskingPolicyWhenModifyColumn(tblInfo, oldCol.ID, changingCol.ID)
 	// Move the new column to a correct offset.
 	destOffset, err := LocateOffsetToMove(changingCol.Offset, pos, tblInfo)
 	if err != nil {
@@ -1069,11 +1067,11 @@ func (w *worker) updatePhysicalTableRow(t table.Table, reorgInfo *reorgInfo) err
 				return dbterror.ErrCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
 			}
 			workType := typeReorgPartitionWorker
-			if reorgInfo.Job.Type != model.ActionReorganizePartition {
-				// workType = typeUpdateColumnWorker
-				// TODO: Support Modify Column on partitioned table
-				// https://github.com/pingcap/tidb/issues/38297
-				return dbterror.ErrCancelledDDLJob.GenWithStack("Modify Column on partitioned table / typeUpdateColumnWorker not yet supported.")
+			if reorgInfo.Job.Type != model.ActionReorganizePartition &&
+				reorgInfo.Job.Type != model.ActionAlterTablePartitioning &&
+				reorgInfo.Job.Type != model.ActionRemovePartitioning {
+				// Modify Column on partitioned table, convert the rows partition by partition.
+				workType = typeUpdateColumnWorker
 			}
 			err := w.writePhysicalTableRecord(w.sessPool, p, workType, reorgInfo)
 			if err != nil {
@@ -1111,27 +1109,28 @@ func (w *worker) updateCurrentElement(t table.Table, reorgInfo *reorgInfo) error
 			}
 		}
 	})
-	// TODO: Support partition tables.
 	if bytes.Equal(reorgInfo.currElement.TypeKey, meta.ColumnElementKey) {
-		//nolint:forcetypeassert
-		err := w.updatePhysicalTableRow(t.(table.PhysicalTable), reorgInfo)
+		err := w.updatePhysicalTableRow(t, reorgInfo)
 		if err != nil {
 			return errors.Trace(err)
 		}
 	}
 
-	if _, ok := t.(table.PartitionedTable); ok {
-		// TODO: remove when modify column of partitioned table is supported
-		// https://github.com/pingcap/tidb/issues/38297
-		return dbterror.ErrCancelledDDLJob.GenWithStack("Modify Column on partitioned table / typeUpdateColumnWorker not yet supported.")
+	// The indexes of a partitioned table are backfilled partition by partition,
+	// so every index starts from the first partition.
+	var firstPhysTbl table.PhysicalTable
+	if tbl, ok := t.(table.PartitionedTable); ok {
+		firstPhysTbl = tbl.GetPartition(t.Meta().Partition.Definitions[0].ID)
+	} else {
+		//nolint:forcetypeassert
+		firstPhysTbl = t.(table.PhysicalTable)
 	}
 	// Get the original start handle and end handle.
 	currentVer, err := getValidCurrentVersion(reorgInfo.d.store)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	//nolint:forcetypeassert
-	originalStartHandle, originalEndHandle, err := getTableRange(reorgInfo.d.jobContext(reorgInfo.Job.ID), reorgInfo.d, t.(table.PhysicalTable), currentVer.Ver, reorgInfo.Job.Priority)
+	originalStartHandle, originalEndHandle, err := getTableRange(reorgInfo.d.jobContext(reorgInfo.Job.ID), reorgInfo.d, firstPhysTbl, currentVer.Ver, reorgInfo.Job.Priority)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1155,6 +1154,11 @@ func (w *worker) updateCurrentElement(t table.Table, reorgInfo *reorgInfo) error
 		if i == startElementOffsetToResetHandle+1 {
 			reorgInfo.StartKey, reorgInfo.EndKey = originalStartHandle, originalEndHandle
 		}
+		if _, ok := t.(table.PartitionedTable); ok && i > startElementOffsetToResetHandle {
+			// The previous element has been processed up to the last partition.
+			reorgInfo.PhysicalTableID = firstPhysTbl.GetPhysicalID()
+			reorgInfo.StartKey, reorgInfo.EndKey = originalStartHandle, originalEndHandle
+		}
 
 		// Update the element in the reorgInfo for updating the reorg meta below.
 		reorgInfo.currElement = reorgInfo.elements[i+1]
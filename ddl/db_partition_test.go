@@ -1992,6 +1992,113 @@ func TestCreatePartitionTableWithGlobalIndex(t *testing.T) {
 	tk.MustGetErrMsg("insert into test_global values (11,2,2)", "[kv:1062]Duplicate entry '2' for key 'test_global.PRIMARY'")
 }
 
+func TestGlobalIndexOption(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustGetErrMsg(`create table t (a int, b int, unique key idx_b(b)) partition by hash(a) partitions 3`,
+		"[ddl:1503]A UNIQUE INDEX must include all columns in the table's partitioning function")
+	tk.MustGetErrMsg(`create table t (a int, b int, key idx_b(b) global) partition by hash(a) partitions 3`,
+		"[ddl:8200]Unsupported GLOBAL IndexOption on non-unique index")
+	tk.MustGetErrMsg(`create table t (a int, b int, unique key idx_b(b) global)`,
+		"[ddl:8200]Unsupported GLOBAL IndexOption on non-partitioned table")
+	tk.MustGetErrMsg(`create table t (a int, b int, primary key (b) clustered global) partition by hash(a) partitions 3`,
+		"[ddl:8200]Unsupported GLOBAL IndexOption on clustered primary key")
+
+	tk.MustExec(`create table t (a int, b int, c int, unique key idx_b(b) global, unique key idx_ab(a, b) global) partition by hash(a) partitions 3`)
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  UNIQUE KEY `idx_b` (`b`) /*T![global_index] GLOBAL */,\n" +
+		"  UNIQUE KEY `idx_ab` (`a`,`b`) /*T![global_index] GLOBAL */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY HASH (`a`) PARTITIONS 3"))
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2)")
+	tk.MustGetErrMsg("insert into t values (3, 1, 3)", "[kv:1062]Duplicate entry '1' for key 't.idx_b'")
+
+	tk.MustGetErrMsg("alter table t add unique index idx_c(c)",
+		"[ddl:1503]A UNIQUE INDEX must include all columns in the table's partitioning function")
+	tk.MustGetErrMsg("alter table t add index idx_c(c) global", "[ddl:8200]Unsupported GLOBAL IndexOption on non-unique index")
+	tk.MustExec("alter table t add unique index idx_c(c) global")
+	tk.MustGetErrMsg("insert into t values (3, 3, 1)", "[kv:1062]Duplicate entry '1' for key 't.idx_c'")
+	tk.MustExec("alter table t add primary key (c) nonclustered global")
+	tt := external.GetTableByName(t, tk, "test", "t")
+	for _, idx := range tt.Meta().Indices {
+		require.True(t, idx.Global, idx.Name.O)
+	}
+	tk.MustExec("admin check table t")
+}
+
+func TestPartitionManagementWithGlobalIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table t (a int, b int, unique key idx_b(b) global) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 1), (11, 11), (21, 21), (22, 22)")
+
+	// The entries of the dropped and truncated partitions are not visible and can be overwritten.
+	tk.MustExec("alter table t drop partition p1")
+	tk.MustQuery("select * from t use index(idx_b) where b > 0 order by b").Check(testkit.Rows("1 1", "21 21", "22 22"))
+	tk.MustExec("insert into t values (2, 11)")
+	tk.MustExec("alter table t truncate partition p2")
+	tk.MustQuery("select * from t use index(idx_b) where b > 0 order by b").Check(testkit.Rows("1 1", "2 11"))
+	tk.MustExec("insert into t values (23, 21)")
+	tk.MustExec("admin check table t")
+
+	tk.MustExec("alter table t reorganize partition p0, p2 into (partition p0 values less than (5), partition p1 values less than (30))")
+	tk.MustQuery("select * from t use index(idx_b) where b > 0 order by b").Check(testkit.Rows("1 1", "2 11", "23 21"))
+	tk.MustGetErrMsg("insert into t values (3, 21)", "[kv:1062]Duplicate entry '21' for key 't.idx_b'")
+	tk.MustExec("admin check table t")
+
+	// The unique key stays global if it does not include all the columns of the new partitioning.
+	tk.MustExec("alter table t partition by hash(a) partitions 3")
+	tt := external.GetTableByName(t, tk, "test", "t")
+	require.True(t, tt.Meta().Indices[0].Global)
+	tk.MustQuery("select * from t use index(idx_b) where b > 0 order by b").Check(testkit.Rows("1 1", "2 11", "23 21"))
+	tk.MustExec("admin check table t")
+	tk.MustExec("alter table t remove partitioning")
+	tt = external.GetTableByName(t, tk, "test", "t")
+	require.False(t, tt.Meta().Indices[0].Global)
+	tk.MustQuery("select * from t use index(idx_b) where b > 0 order by b").Check(testkit.Rows("1 1", "2 11", "23 21"))
+	tk.MustExec("admin check table t")
+}
+
+func TestExchangePartitionWithGlobalIndex(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table pt (a int, b int, unique key idx_b(b) global) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20))`)
+	tk.MustExec("create table nt (a int, b int, unique key idx_b(b))")
+	tk.MustExec("insert into pt values (1, 1), (2, 2), (11, 11)")
+	tk.MustExec("insert into nt values (3, 3), (4, 4)")
+
+	// The global index entries of the exchanged rows are replaced.
+	tk.MustExec("alter table pt exchange partition p0 with table nt")
+	tk.MustQuery("select * from pt use index(idx_b) where b > 0 order by b").Check(testkit.Rows("3 3", "4 4", "11 11"))
+	tk.MustQuery("select * from pt where b = 1").Check(testkit.Rows())
+	tk.MustQuery("select * from nt order by a").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustExec("insert into pt values (5, 1)")
+	tk.MustExec("admin check table pt")
+	tk.MustExec("admin check table nt")
+
+	// The exchange is rolled back if the rows of the table conflict with the other partitions.
+	tk.MustExec("insert into nt values (6, 11)")
+	tk.MustGetErrMsg("alter table pt exchange partition p0 with table nt", "[kv:1062]Duplicate entry '11' for key 'pt.idx_b'")
+	tk.MustQuery("select * from pt use index(idx_b) where b > 0 order by b").Check(testkit.Rows("5 1", "3 3", "4 4", "11 11"))
+	tk.MustQuery("select * from nt order by a").Check(testkit.Rows("1 1", "2 2", "6 11"))
+	tk.MustExec("insert into nt values (7, 7)")
+	tk.MustExec("insert into pt values (8, 8)")
+	tk.MustExec("admin check table pt")
+	tk.MustExec("admin check table nt")
+}
+
 func TestDropPartitionWithGlobalIndex(t *testing.T) {
 	defer config.RestoreFunc()()
 	config.UpdateGlobal(func(conf *config.Config) {
@@ -3852,10 +3959,11 @@ func TestPartitionErrorCode(t *testing.T) {
 	tk.MustGetDBError("alter table t_part coalesce partition 4;", dbterror.ErrCoalesceOnlyOnHashPartition)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part rebuild partition p0,p1;", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part remove partitioning;", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t_part optimize partition p0,p1;")
+	tk.MustExec("alter table t_part rebuild partition p0,p1;")
 	tk.MustGetErrCode("alter table t_part repair partition p1;", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t_part remove partitioning;")
+	tk.MustGetDBError("alter table t_part rebuild partition p0;", dbterror.ErrPartitionMgmtOnNonpartitioned)
 
 	// Reduce the impact on DML when executing partition DDL
 	tk1 := testkit.NewTestKit(t, store)
@@ -4300,8 +4408,12 @@ func TestUnsupportedPartitionManagementDDLs(t *testing.T) {
 		);
 	`)
 
-	_, err := tk.Exec("alter table test_1465 partition by hash(a)")
-	require.Regexp(t, ".*alter table partition is unsupported", err.Error())
+	tk.MustExec("alter table test_1465 partition by hash(a)")
+	tk.MustQuery("show create table test_1465").Check(testkit.Rows("" +
+		"test_1465 CREATE TABLE `test_1465` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY HASH (`a`) PARTITIONS 1"))
 }
 
 func TestCommitWhenSchemaChange(t *testing.T) {
@@ -5452,12 +5564,11 @@ func TestIssue40135Ver2(t *testing.T) {
 	dom.DDL().SetHook(hook)
 	tk.MustExec("alter table t40135 modify column a bigint NULL DEFAULT '6243108' FIRST")
 	wg.Wait()
-	require.ErrorContains(t, checkErr, "[ddl:8200]Unsupported modify column: table is partition table")
+	require.NoError(t, checkErr)
 	tk.MustExec("admin check table t40135")
 }
 
 func TestAlterModifyPartitionColTruncateWarning(t *testing.T) {
-	t.Skip("waiting for supporting Modify Partition Column again")
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
 	schemaName := "truncWarn"
@@ -5468,14 +5579,82 @@ func TestAlterModifyPartitionColTruncateWarning(t *testing.T) {
 	tk.MustExec(`insert into t values ("123456"),(" 654321")`)
 	tk.MustContainErrMsg(`alter table t modify a varchar(5)`, "[types:1265]Data truncated for column 'a', value is '")
 	tk.MustExec(`set sql_mode = ''`)
-	tk.MustExec(`alter table t modify a varchar(5)`)
-	// Fix the duplicate warning, see https://github.com/pingcap/tidb/issues/38699
-	tk.MustQuery(`show warnings`).Check(testkit.Rows(""+
-		"Warning 1265 Data truncated for column 'a', value is ' 654321'",
-		"Warning 1265 Data truncated for column 'a', value is ' 654321'"))
+	// Truncated values may no longer belong to their partition.
+	tk.MustContainErrMsg(`alter table t modify a varchar(5)`, "[ddl:8200]Unsupported modify column: changing the type of a partitioning column requires strict SQL mode")
 	tk.MustExec(`admin check table t`)
 }
 
+func TestAlterModifyColumnOnPartitionedTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	schemaName := "modColPart"
+	tk.MustExec("create database " + schemaName)
+	tk.MustExec("use " + schemaName)
+	tk.MustExec(`create table t (a int, b varchar(255), c int, key (b), key (c, b)) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20), partition pMax values less than (maxvalue))`)
+	tk.MustExec(`insert into t values (1, "1", 1), (11, "11", 11), (21, "21", 21), (null, "null", null)`)
+	// Non partitioning column, with indexes.
+	tk.MustExec(`alter table t modify b varchar(10)`)
+	tk.MustContainErrMsg(`alter table t modify b varchar(1)`, "[types:1265]Data truncated for column 'b', value is 'null'")
+	tk.MustExec(`alter table t modify c bigint`)
+	tk.MustExec(`alter table t modify c tinyint unsigned`)
+	tk.MustContainErrMsg(`alter table t modify c char(1)`, "[types:1265]Data truncated for column 'c', value is '11'")
+	tk.MustExec(`admin check table t`)
+	// Partitioning column, the partition definitions must still be valid.
+	tk.MustExec(`alter table t modify a bigint`)
+	tk.MustExec(`alter table t modify a smallint`)
+	tk.MustContainErrMsg(`alter table t modify a varchar(10)`, "[ddl:8200]Unsupported modify column: can't change the partitioning column, since it would require reorganize all partitions")
+	tk.MustExec(`alter table t modify a tinyint unsigned`)
+	tk.MustQuery(`show create table t`).Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` tinyint(3) unsigned DEFAULT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL,\n" +
+		"  `c` tinyint(3) unsigned DEFAULT NULL,\n" +
+		"  KEY `b` (`b`),\n" +
+		"  KEY `c` (`c`,`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`)\n" +
+		"(PARTITION `p0` VALUES LESS THAN (10),\n" +
+		" PARTITION `p1` VALUES LESS THAN (20),\n" +
+		" PARTITION `pMax` VALUES LESS THAN (MAXVALUE))"))
+	tk.MustQuery(`select * from t partition (p0)`).Sort().Check(testkit.Rows("1 1 1", "<nil> null <nil>"))
+	tk.MustQuery(`select * from t partition (p1)`).Check(testkit.Rows("11 11 11"))
+	tk.MustQuery(`select * from t partition (pMax)`).Check(testkit.Rows("21 21 21"))
+	tk.MustQuery(`select b from t use index (c) where c = 11`).Check(testkit.Rows("11"))
+	tk.MustExec(`admin check table t`)
+
+	tk.MustExec(`create table t2 (a varchar(10), b int, unique key (a)) partition by list columns (a) (partition p0 values in ("a", "b"), partition p1 values in ("abcdefgh"))`)
+	tk.MustExec(`insert into t2 values ("a", 1), ("b", 2), ("abcdefgh", 3)`)
+	tk.MustContainErrMsg(`alter table t2 modify a varchar(5)`, "[ddl:8200]New column does not match partition definitions: [ddl:1654]Partition column values of incorrect type")
+	tk.MustExec(`alter table t2 modify a varchar(20)`)
+	tk.MustExec(`alter table t2 modify b bigint`)
+	tk.MustQuery(`select * from t2 partition (p1)`).Check(testkit.Rows("abcdefgh 3"))
+	tk.MustExec(`admin check table t2`)
+}
+
+func TestAlterModifyPartitionColFractionalPart(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table t (a datetime(2), b int) partition by range columns (a) (partition p0 values less than ('2023-01-01'), partition p1 values less than (maxvalue))`)
+	tk.MustExec(`insert into t values ('2022-12-31 23:59:59.99', 1), ('2023-01-01 00:00:00', 2)`)
+	// '2022-12-31 23:59:59.99' would be rounded to '2023-01-01 00:00:00' but kept in p0.
+	tk.MustContainErrMsg(`alter table t modify a datetime`, "[ddl:8200]Unsupported modify column: can't decrease the fractional seconds precision or the scale of the partitioning column")
+	tk.MustExec(`alter table t modify a datetime(4)`)
+	tk.MustQuery(`select b from t where a < '2023-01-01'`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select b from t where a = '2022-12-31 23:59:59.99'`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select b from t partition (p1)`).Check(testkit.Rows("2"))
+	tk.MustExec(`admin check table t`)
+
+	tk.MustExec(`create table t2 (a decimal(10, 2), b int) partition by key (a) partitions 4`)
+	tk.MustExec(`insert into t2 values (1.25, 1), (1.3, 2), (2, 3)`)
+	tk.MustContainErrMsg(`alter table t2 modify a decimal(10, 1)`, "[ddl:8200]Unsupported modify column: can't decrease the fractional seconds precision or the scale of the partitioning column")
+	tk.MustContainErrMsg(`alter table t2 modify a decimal(10, 0)`, "[ddl:8200]Unsupported modify column: can't decrease the fractional seconds precision or the scale of the partitioning column")
+	tk.MustExec(`alter table t2 modify a decimal(12, 2)`)
+	tk.MustQuery(`select b from t2 where a = 1.25`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select b from t2 where a = 1.3`).Check(testkit.Rows("2"))
+	tk.MustQuery(`select b from t2 where a in (1.25, 2) order by b`).Check(testkit.Rows("1", "3"))
+	tk.MustExec(`admin check table t2`)
+}
+
 func TestAlterModifyColumnOnPartitionedTableRename(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -173,6 +173,12 @@ type DDL interface {
 	RecoverTable(ctx sessionctx.Context, recoverInfo *RecoverInfo) (err error)
 	RecoverSchema(ctx sessionctx.Context, recoverSchemaInfo *RecoverSchemaInfo) error
 	DropView(ctx sessionctx.Context, stmt *ast.DropTableStmt) (err error)
+	CreateMaterializedView(ctx sessionctx.Context, stmt *ast.CreateMaterializedViewStmt) error
+	DropMaterializedView(ctx sessionctx.Context, stmt *ast.DropMaterializedViewStmt) error
+	CreateMaskingPolicy(ctx sessionctx.Context, stmt *ast.CreateMaskingPolicyStmt) error
+	DropMaskingPolicy(ctx sessionctx.Context, stmt *ast.DropMaskingPolicyStmt) error
+	CreateRowAccessPolicy(ctx sessionctx.Context, stmt *ast.CreateRowAccessPolicyStmt) error
+	DropRowAccessPolicy(ctx sessionctx.Context, stmt *ast.DropRowAccessPolicyStmt) error
 	CreateIndex(ctx sessionctx.Context, stmt *ast.CreateIndexStmt) error
 	DropIndex(ctx sessionctx.Context, stmt *ast.DropIndexStmt) error
 	AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast.AlterTableStmt) error
@@ -977,7 +983,8 @@ func getIntervalFromPolicy(policy []time.Duration, i int) (time.Duration, bool)
 func getJobCheckInterval(job *model.Job, i int) (time.Duration, bool) {
 	switch job.Type {
 	case model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionModifyColumn,
-		model.ActionReorganizePartition:
+		model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+		model.ActionRemovePartitioning:
 		return getIntervalFromPolicy(slowDDLIntervalPolicy, i)
 	case model.ActionCreateTable, model.ActionCreateSchema:
 		return getIntervalFromPolicy(fastDDLIntervalPolicy, i)
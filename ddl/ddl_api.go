@@ -1955,6 +1955,9 @@ func BuildTableInfo(
 				if constr.Option != nil && constr.Option.Visibility == ast.IndexVisibilityInvisible {
 					return nil, dbterror.ErrPKIndexCantBeInvisible
 				}
+				if constr.Option != nil && constr.Option.Global {
+					return nil, dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("GLOBAL IndexOption on clustered primary key")
+				}
 			}
 			if tbInfo.PKIsHandle {
 				continue
@@ -2051,7 +2054,7 @@ func BuildTableInfo(
 			model.NewCIStr(indexName),
 			primary,
 			unique,
-			false,
+			constr.Option != nil && constr.Option.Global,
 			constr.Keys,
 			constr.Option,
 			model.StatePublic,
@@ -2214,7 +2217,13 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 	if !ctx.GetSessionVars().InRestrictedSQL && ctx.GetSessionVars().PrimaryKeyRequired && len(tbInfo.GetPkName().String()) == 0 {
 		return infoschema.ErrTableWithoutPrimaryKey
 	}
-	if tbInfo.Partition != nil {
+	if tbInfo.Partition == nil {
+		for _, index := range tbInfo.Indices {
+			if index.Global {
+				return checkGlobalIndex(tbInfo, index.Primary, index.Unique)
+			}
+		}
+	} else {
 		if err := checkPartitionDefinitionConstraints(ctx, tbInfo); err != nil {
 			return errors.Trace(err)
 		}
@@ -2232,6 +2241,9 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 			return errors.Trace(err)
 		}
 	}
+	if err := checkIntervalPartitionInfoValid(ctx, tbInfo); err != nil {
+		return errors.Trace(err)
+	}
 
 	return nil
 }
@@ -2314,6 +2326,9 @@ func BuildTableInfoWithLike(ctx sessionctx.Context, ident ast.Ident, referTblInf
 	if referTblInfo.TTLInfo != nil {
 		tblInfo.TTLInfo = referTblInfo.TTLInfo.Clone()
 	}
+	if referTblInfo.IntervalPartitionInfo != nil {
+		tblInfo.IntervalPartitionInfo = referTblInfo.IntervalPartitionInfo.Clone()
+	}
 	renameCheckConstraint(&tblInfo)
 	return &tblInfo, nil
 }
@@ -2967,6 +2982,268 @@ func BuildViewInfo(_ sessionctx.Context, s *ast.CreateViewStmt) (*model.ViewInfo
 		Security: s.Security, SelectStmt: sb.String(), CheckOption: s.CheckOption, Cols: nil}, nil
 }
 
+// CreateMaterializedView creates a materialized view whose data is stored in a table. If the view can be
+// refreshed incrementally, a log table is created to record the changes of the base table.
+func (d *ddl) CreateMaterializedView(ctx sessionctx.Context, s *ast.CreateMaterializedViewStmt) (err error) {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(s.ViewName.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.ViewName.Schema)
+	}
+	if is.TableExists(s.ViewName.Schema, s.ViewName.Name) {
+		err = infoschema.ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name})
+		if s.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	if err = checkTooLongTable(s.ViewName.Name); err != nil {
+		return err
+	}
+
+	mvInfo, err := BuildMaterializedViewInfo(s)
+	if err != nil {
+		return err
+	}
+	incremental, baseTable, logCols := buildMaterializedViewIncrementalInfo(s)
+	mvInfo.Incremental = incremental
+	tbInfo, err := buildMaterializedViewTableInfo(ctx, s, mvInfo)
+	if err != nil {
+		return err
+	}
+	if err = d.assignTableID(tbInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	var logInfo *model.TableInfo
+	schemaIDs, tableIDs := []int64{schema.ID}, []int64{tbInfo.ID}
+	if incremental != nil {
+		logInfo, err = buildMaterializedViewLogTableInfo(ctx, baseTable.TableInfo, logCols, tbInfo.ID)
+		if err != nil {
+			return err
+		}
+		if err = d.assignTableID(logInfo); err != nil {
+			return errors.Trace(err)
+		}
+		incremental.LogTableID = logInfo.ID
+		schemaIDs = append(schemaIDs, incremental.BaseSchemaID)
+		tableIDs = append(tableIDs, incremental.BaseTableID, logInfo.ID)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionCreateMaterializedView,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo, logInfo},
+		CtxVars:    []interface{}{schemaIDs, tableIDs},
+	}
+	err = d.DoDDLJob(ctx, job)
+	if err != nil {
+		if s.IfNotExists && infoschema.ErrTableExists.Equal(err) {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			err = nil
+		}
+	} else {
+		err = d.createTableWithInfoPost(ctx, tbInfo, schema.ID)
+	}
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// DropMaterializedView drops a materialized view and its log table.
+func (d *ddl) DropMaterializedView(ctx sessionctx.Context, s *ast.DropMaterializedViewStmt) (err error) {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(s.ViewName.Schema)
+	var tbl table.Table
+	if ok {
+		tbl, err = is.TableByName(s.ViewName.Schema, s.ViewName.Name)
+	}
+	if !ok || infoschema.ErrTableNotExists.Equal(err) {
+		err = infoschema.ErrTableDropExists.GenWithStackByArgs(ast.Ident{Schema: s.ViewName.Schema, Name: s.ViewName.Name})
+		if s.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tbl.Meta()
+	if tbInfo.MaterializedView == nil {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(s.ViewName.Schema, s.ViewName.Name, "MATERIALIZED VIEW")
+	}
+
+	schemaIDs, tableIDs := []int64{schema.ID}, []int64{tbInfo.ID}
+	if inc := tbInfo.MaterializedView.Incremental; inc != nil {
+		schemaIDs = append(schemaIDs, inc.BaseSchemaID)
+		tableIDs = append(tableIDs, inc.BaseTableID, inc.LogTableID)
+	}
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionDropMaterializedView,
+		BinlogInfo: &model.HistoryInfo{},
+		CtxVars:    []interface{}{schemaIDs, tableIDs},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// CreateMaskingPolicy creates a masking policy on a column of the table.
+func (d *ddl) CreateMaskingPolicy(ctx sessionctx.Context, s *ast.CreateMaskingPolicyStmt) error {
+	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	schema, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tbl.Meta()
+	if tbInfo.IsView() || tbInfo.IsSequence() {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
+	}
+	if tbInfo.TempTableType != model.TempTableNone {
+		return dbterror.ErrOptOnTemporaryTable.GenWithStackByArgs("masking policy")
+	}
+	if tbInfo.FindMaskingPolicy(s.PolicyName.L) != nil {
+		err = infoschema.ErrMaskingPolicyExists.GenWithStackByArgs(s.PolicyName.O)
+		if s.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	policy, err := buildMaskingPolicyInfo(ctx, tbInfo, s)
+	if err != nil {
+		return err
+	}
+	if masked := tbInfo.FindMaskingPolicyByColumnID(policy.ColumnID); masked != nil {
+		return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(
+			fmt.Sprintf("column '%s' is already masked by policy '%s'", s.Column.Name.O, masked.Name.O))
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionCreateMaskingPolicy,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{policy},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// DropMaskingPolicy drops a masking policy of the table.
+func (d *ddl) DropMaskingPolicy(ctx sessionctx.Context, s *ast.DropMaskingPolicyStmt) error {
+	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	schema, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tbl.Meta()
+	if tbInfo.FindMaskingPolicy(s.PolicyName.L) == nil {
+		err = infoschema.ErrMaskingPolicyNotExists.GenWithStackByArgs(s.PolicyName.O)
+		if s.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionDropMaskingPolicy,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{s.PolicyName},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// CreateRowAccessPolicy creates a row access policy on the table.
+func (d *ddl) CreateRowAccessPolicy(ctx sessionctx.Context, s *ast.CreateRowAccessPolicyStmt) error {
+	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	schema, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tbl.Meta()
+	if tbInfo.IsView() || tbInfo.IsSequence() {
+		return dbterror.ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
+	}
+	if tbInfo.TempTableType != model.TempTableNone {
+		return dbterror.ErrOptOnTemporaryTable.GenWithStackByArgs("row access policy")
+	}
+	if tbInfo.FindRowAccessPolicy(s.PolicyName.L) != nil {
+		err = infoschema.ErrRowAccessPolicyExists.GenWithStackByArgs(s.PolicyName.O)
+		if s.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	policy, err := buildRowAccessPolicyInfo(ctx, schema.Name, tbInfo, s)
+	if err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionCreateRowAccessPolicy,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{policy},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// DropRowAccessPolicy drops a row access policy of the table.
+func (d *ddl) DropRowAccessPolicy(ctx sessionctx.Context, s *ast.DropRowAccessPolicyStmt) error {
+	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	schema, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo := tbl.Meta()
+	if tbInfo.FindRowAccessPolicy(s.PolicyName.L) == nil {
+		err = infoschema.ErrRowAccessPolicyNotExists.GenWithStackByArgs(s.PolicyName.O)
+		if s.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tbInfo.Name.L,
+		Type:       model.ActionDropRowAccessPolicy,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{s.PolicyName},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
 func checkPartitionByHash(ctx sessionctx.Context, tbInfo *model.TableInfo) error {
 	return checkNoHashPartitions(ctx, tbInfo.Partition.Num)
 }
@@ -3200,7 +3477,7 @@ func SetDirectResourceGroupSettings(groupInfo *model.ResourceGroupInfo, opt *ast
 			resourceGroupSettings.Runaway = nil
 		}
 		for _, opt := range opt.RunawayOptionList {
-			if err := SetDirectResourceGroupRunawayOption(resourceGroupSettings, opt.Tp, opt.StrValue, opt.IntValue); err != nil {
+			if err := SetDirectResourceGroupRunawayOption(resourceGroupSettings, opt); err != nil {
 				return err
 			}
 		}
@@ -3217,6 +3494,15 @@ func SetDirectResourceGroupSettings(groupInfo *model.ResourceGroupInfo, opt *ast
 				return err
 			}
 		}
+	case ast.ResourceGroupQueue:
+		if len(opt.QueueOptions) == 0 {
+			resourceGroupSettings.Queue = nil
+		}
+		for _, opt := range opt.QueueOptions {
+			if err := SetDirectResourceGroupQueueOption(resourceGroupSettings, opt); err != nil {
+				return err
+			}
+		}
 	default:
 		return errors.Trace(errors.New("unknown resource unit type"))
 	}
@@ -3224,24 +3510,32 @@ func SetDirectResourceGroupSettings(groupInfo *model.ResourceGroupInfo, opt *ast
 }
 
 // SetDirectResourceGroupRunawayOption tries to set runaway part of the ResourceGroupSettings.
-func SetDirectResourceGroupRunawayOption(resourceGroupSettings *model.ResourceGroupSettings, typ ast.RunawayOptionType, stringVal string, intVal int32) error {
+func SetDirectResourceGroupRunawayOption(resourceGroupSettings *model.ResourceGroupSettings, opt *ast.ResourceGroupRunawayOption) error {
 	if resourceGroupSettings.Runaway == nil {
 		resourceGroupSettings.Runaway = &model.ResourceGroupRunawaySettings{}
 	}
 	settings := resourceGroupSettings.Runaway
-	switch typ {
+	switch opt.Tp {
 	case ast.RunawayRule:
 		// because execute time won't be too long, we use `time` pkg which does not support to parse unit 'd'.
-		dur, err := time.ParseDuration(stringVal)
+		dur, err := time.ParseDuration(opt.StrValue)
 		if err != nil {
 			return err
 		}
 		settings.ExecElapsedTimeMs = uint64(dur.Milliseconds())
+	case ast.RunawayProcessedKeys:
+		settings.ProcessedKeys = opt.UintValue
+	case ast.RunawayRequestUnit:
+		settings.RequestUnit = opt.UintValue
+	case ast.RunawayResultRows:
+		settings.ResultRows = opt.UintValue
+	case ast.RunawayMemory:
+		settings.MemoryBytes = opt.UintValue
 	case ast.RunawayAction:
-		settings.Action = model.RunawayActionType(intVal)
+		settings.Action = model.RunawayActionType(opt.IntValue)
 	case ast.RunawayWatch:
-		settings.WatchType = model.RunawayWatchType(intVal)
-		dur, err := time.ParseDuration(stringVal)
+		settings.WatchType = model.RunawayWatchType(opt.IntValue)
+		dur, err := time.ParseDuration(opt.StrValue)
 		if err != nil {
 			return err
 		}
@@ -3270,6 +3564,35 @@ func SetDirectResourceGroupBackgroundOption(resourceGroupSettings *model.Resourc
 	return nil
 }
 
+// SetDirectResourceGroupQueueOption sets the admission queue configs of the ResourceGroupSettings.
+func SetDirectResourceGroupQueueOption(resourceGroupSettings *model.ResourceGroupSettings, opt *ast.ResourceGroupQueueOption) error {
+	// Copy the settings because they may be shared with the old resource group info.
+	queue := &model.ResourceGroupQueueSettings{}
+	if resourceGroupSettings.Queue != nil {
+		*queue = *resourceGroupSettings.Queue
+	}
+	switch opt.Type {
+	case ast.QueueOptionMaxConcurrency:
+		queue.MaxConcurrency = opt.UintValue
+	case ast.QueueOptionMaxQueueLength:
+		queue.MaxQueueLength = opt.UintValue
+	case ast.QueueOptionTimeout:
+		dur, err := time.ParseDuration(opt.StrValue)
+		if err != nil {
+			return err
+		}
+		// A timeout under 1ms would be truncated to 0, which means no timeout.
+		if dur < 0 || (dur > 0 && dur < time.Millisecond) {
+			return resourcegroup.ErrInvalidResourceGroupQueueTimeout
+		}
+		queue.QueueTimeoutMs = uint64(dur.Milliseconds())
+	default:
+		return errors.Trace(errors.New("unknown queue option type"))
+	}
+	resourceGroupSettings.Queue = queue
+	return nil
+}
+
 func parseBackgroundJobTypes(t string) ([]string, error) {
 	if len(t) == 0 {
 		return []string{}, nil
@@ -3291,7 +3614,7 @@ func parseBackgroundJobTypes(t string) ([]string, error) {
 
 // handleTableOptions updates tableInfo according to table options.
 func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) error {
-	var ttlOptionsHandled bool
+	var ttlOptionsHandled, intervalPartitionOptionsHandled bool
 
 	for _, op := range options {
 		switch op.Tp {
@@ -3351,6 +3674,17 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) err
 
 			tbInfo.TTLInfo = ttlInfo
 			ttlOptionsHandled = true
+		case ast.TableOptionPartitionInterval, ast.TableOptionPartitionPrecreate, ast.TableOptionPartitionRetention:
+			if intervalPartitionOptionsHandled {
+				continue
+			}
+
+			info, err := buildIntervalPartitionInfo(nil, options)
+			if err != nil {
+				return err
+			}
+			tbInfo.IntervalPartitionInfo = info
+			intervalPartitionOptionsHandled = true
 		}
 	}
 	shardingBits := shardingBits(tbInfo)
@@ -3563,7 +3897,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 	}
 	for _, spec := range validSpecs {
 		var handledCharsetOrCollate bool
-		var ttlOptionsHandled bool
+		var ttlOptionsHandled, intervalPartitionOptionsHandled bool
 		switch spec.Tp {
 		case ast.AlterTableAddColumns:
 			err = d.AddColumn(sctx, ident, spec)
@@ -3579,12 +3913,10 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 			err = dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("SPLIT LAST PARTITION")
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(dbterror.ErrUnsupportedCheckPartition)
-		case ast.AlterTableRebuildPartition:
-			err = errors.Trace(dbterror.ErrUnsupportedRebuildPartition)
-		case ast.AlterTableOptimizePartition:
-			err = errors.Trace(dbterror.ErrUnsupportedOptimizePartition)
+		case ast.AlterTableRebuildPartition, ast.AlterTableOptimizePartition:
+			err = d.RebuildPartitions(sctx, ident, spec)
 		case ast.AlterTableRemovePartitioning:
-			err = errors.Trace(dbterror.ErrUnsupportedRemovePartition)
+			err = d.RemovePartitioning(sctx, ident)
 		case ast.AlterTableRepairPartition:
 			err = errors.Trace(dbterror.ErrUnsupportedRepairPartition)
 		case ast.AlterTableDropColumn:
@@ -3661,8 +3993,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 			isAlterTable := true
 			err = d.renameTable(sctx, ident, newIdent, isAlterTable)
 		case ast.AlterTablePartition:
-			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
-			err = errors.New("alter table partition is unsupported")
+			err = d.AlterTablePartitioning(sctx, ident, spec)
 		case ast.AlterTableOption:
 			var placementPolicyRef *model.PolicyRefInfo
 			for i, opt := range spec.Options {
@@ -3719,6 +4050,13 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 					err = d.AlterTableTTLInfoOrEnable(sctx, ident, ttlInfo, ttlEnable, ttlJobInterval)
 
 					ttlOptionsHandled = true
+				case ast.TableOptionPartitionInterval, ast.TableOptionPartitionPrecreate, ast.TableOptionPartitionRetention:
+					if intervalPartitionOptionsHandled {
+						continue
+					}
+					err = d.AlterTableIntervalPartition(sctx, ident, spec.Options)
+
+					intervalPartitionOptionsHandled = true
 				default:
 					err = dbterror.ErrUnsupportedAlterTableOption
 				}
@@ -3773,6 +4111,8 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt *ast
 		case ast.AlterTableRemoveTTL:
 			// the parser makes sure we have only one `ast.AlterTableRemoveTTL` in an alter statement
 			err = d.AlterTableRemoveTTL(sctx, ident)
+		case ast.AlterTableRemovePartitionInterval:
+			err = d.AlterTableRemoveIntervalPartition(sctx, ident)
 		default:
 			err = errors.Trace(dbterror.ErrUnsupportedAlterTableSpec)
 		}
@@ -4305,6 +4645,320 @@ func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec
 	return errors.Trace(err)
 }
 
+// AlterTablePartitioning changes the partitioning of a table, including a non-partitioned table,
+// by reorganizing all its current partitions into the new ones.
+func (d *ddl) AlterTablePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.FastGenByArgs(ident.Schema, ident.Name))
+	}
+	if spec.Partition == nil {
+		return errors.Trace(dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("ALTER TABLE PARTITION without PARTITION BY"))
+	}
+
+	meta := t.Meta()
+	if len(meta.ForeignKeys) > 0 {
+		return errors.Trace(infoschema.ErrForeignKeyOnPartitioned)
+	}
+	is := d.infoCache.GetLatest()
+	if len(is.GetTableReferredForeignKeys(schema.Name.L, meta.Name.L)) > 0 {
+		return errors.Trace(infoschema.ErrForeignKeyOnPartitioned)
+	}
+	piOld := meta.GetPartitionInfo()
+	var partNames []model.CIStr
+	if piOld != nil {
+		partNames = make([]model.CIStr, 0, len(piOld.Definitions))
+		for _, def := range piOld.Definitions {
+			partNames = append(partNames, def.Name)
+		}
+	} else {
+		piOld = getPartitionInfoTypeNone(meta)
+		partNames = []model.CIStr{piOld.Definitions[0].Name}
+	}
+
+	newMeta := meta.Clone()
+	newMeta.Partition = nil
+	if err = buildTablePartitionInfo(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if newMeta.Partition == nil {
+		// The partitioning is ignored with a warning, do not silently succeed.
+		return errors.Trace(dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("ALTER TABLE PARTITION BY, since the partitioning is ignored"))
+	}
+	if err = checkPartitionFuncType(ctx, spec.Partition.Expr, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	partInfo := newMeta.Partition
+	if err = checkAlterTablePartitioningDefs(ctx, meta, partInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if err = handlePartitionPlacement(ctx, partInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	// The table needs a new ID, since the current one is the ID of the partition to drop
+	// if the table is not partitioned yet.
+	newIDs, err := d.genGlobalIDs(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	partInfo.NewTableID = newIDs[0]
+	partInfo.DDLType = piOld.Type
+
+	tzName, tzOffset := ddlutil.GetTimeZone(ctx)
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		TableName:  meta.Name.L,
+		Type:       model.ActionAlterTablePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+			Location:      &model.TimeZoneLocation{Name: tzName, Offset: tzOffset},
+		},
+	}
+
+	// No preSplitAndScatter here, it will be done by the worker in onReorganizePartition instead.
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	if err == nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("The statistics of new partitions will be outdated after reorganizing partitions. Please use 'ANALYZE TABLE' statement if you want to update it now"))
+	}
+	return errors.Trace(err)
+}
+
+// RemovePartitioning removes the partitioning of a table, by reorganizing all its partitions
+// into a single one, which becomes the non-partitioned table.
+func (d *ddl) RemovePartitioning(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.FastGenByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return dbterror.ErrPartitionMgmtOnNonpartitioned
+	}
+	partNames := make([]model.CIStr, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		partNames = append(partNames, def.Name)
+	}
+	partInfo := &model.PartitionInfo{
+		Type:   model.PartitionTypeNone,
+		Enable: true,
+		Definitions: []model.PartitionDefinition{{
+			Name:    model.NewCIStr("CollapsedPartitions"),
+			Comment: "Intermediate partition during ALTER TABLE ... REMOVE PARTITIONING",
+		}},
+		Num: 1,
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	// The single new partition becomes the table, so no data needs to be moved in the end.
+	partInfo.NewTableID = partInfo.Definitions[0].ID
+	partInfo.DDLType = pi.Type
+
+	tzName, tzOffset := ddlutil.GetTimeZone(ctx)
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		TableName:  meta.Name.L,
+		Type:       model.ActionRemovePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+			Location:      &model.TimeZoneLocation{Name: tzName, Offset: tzOffset},
+		},
+	}
+
+	// No preSplitAndScatter here, it will be done by the worker in onReorganizePartition instead.
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	if err == nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("The statistics of the table will be outdated after removing partitioning. Please use 'ANALYZE TABLE' statement if you want to update it now"))
+	}
+	return errors.Trace(err)
+}
+
+// RebuildPartitions rebuilds partitions by reorganizing them into new partitions with the same definitions.
+// It is also used for OPTIMIZE PARTITION, like InnoDB which does not support optimizing partitions.
+func (d *ddl) RebuildPartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.FastGenByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return dbterror.ErrPartitionMgmtOnNonpartitioned
+	}
+	partNames := spec.PartitionNames
+	if spec.OnAllPartitions {
+		partNames = nil
+	}
+	named := make(map[string]struct{}, len(partNames))
+	for _, name := range partNames {
+		if pi.FindPartitionDefinitionByName(name.L) == -1 {
+			return errors.Trace(table.ErrUnknownPartition.GenWithStackByArgs(name.O, meta.Name.O))
+		}
+		named[name.L] = struct{}{}
+	}
+	switch pi.Type {
+	case model.PartitionTypeRange, model.PartitionTypeList:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
+		// Reorganizing HASH/KEY partitions must reorganize all of them.
+		partNames = nil
+	default:
+		return errors.Trace(dbterror.ErrUnsupportedReorganizePartition)
+	}
+	if len(partNames) == 0 {
+		partNames = make([]model.CIStr, 0, len(pi.Definitions))
+		for _, def := range pi.Definitions {
+			partNames = append(partNames, def.Name)
+		}
+	}
+	if pi.Type == model.PartitionTypeRange {
+		// Reorganizing RANGE partitions must reorganize adjacent ones,
+		// so also rebuild the partitions in between.
+		first, last := len(pi.Definitions), -1
+		for _, name := range partNames {
+			idx := pi.FindPartitionDefinitionByName(name.L)
+			first = mathutil.Min(first, idx)
+			last = mathutil.Max(last, idx)
+		}
+		partNames = make([]model.CIStr, 0, last-first+1)
+		for _, def := range pi.Definitions[first : last+1] {
+			partNames = append(partNames, def.Name)
+		}
+	}
+	if len(named) > 0 {
+		// Let the users know the partitions rebuilt but not named by them.
+		extra := make([]string, 0, len(partNames))
+		for _, name := range partNames {
+			if _, ok := named[name.L]; !ok {
+				extra = append(extra, name.O)
+			}
+		}
+		if len(extra) > 0 {
+			ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("The partitions %s are also rebuilt, since the partitions to rebuild must be adjacent for RANGE partitioning and all the partitions are rebuilt for HASH and KEY partitioning", strings.Join(extra, ",")))
+		}
+	}
+	firstPartIdx, lastPartIdx, idMap, err := getReplacedPartitionIDs(partNames, pi)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	partInfo := &model.PartitionInfo{
+		Type:    pi.Type,
+		Expr:    pi.Expr,
+		Columns: pi.Columns,
+		Enable:  pi.Enable,
+		Num:     uint64(len(partNames)),
+	}
+	partInfo.Definitions = make([]model.PartitionDefinition, 0, len(partNames))
+	for _, name := range partNames {
+		def := pi.Definitions[pi.FindPartitionDefinitionByName(name.L)].Clone()
+		def.ID = 0
+		partInfo.Definitions = append(partInfo.Definitions, def)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkReorgPartitionDefs(ctx, meta, partInfo, firstPartIdx, lastPartIdx, idMap); err != nil {
+		return errors.Trace(err)
+	}
+	if err = handlePartitionPlacement(ctx, partInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	tzName, tzOffset := ddlutil.GetTimeZone(ctx)
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		TableName:  meta.Name.L,
+		Type:       model.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+			Location:      &model.TimeZoneLocation{Name: tzName, Offset: tzOffset},
+		},
+	}
+
+	// No preSplitAndScatter here, it will be done by the worker in onReorganizePartition instead.
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	if err == nil {
+		if spec.Tp == ast.AlterTableOptimizePartition {
+			ctx.GetSessionVars().StmtCtx.AppendNote(errors.New("Table does not support optimize, doing rebuild instead"))
+		}
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("The statistics of related partitions will be outdated after reorganizing partitions. Please use 'ANALYZE TABLE' statement if you want to update it now"))
+	}
+	return errors.Trace(err)
+}
+
+// checkAlterTablePartitioningDefs checks the new partitioning of ALTER TABLE ... PARTITION BY against the table.
+func checkAlterTablePartitioningDefs(ctx sessionctx.Context, tblInfo *model.TableInfo, partInfo *model.PartitionInfo) error {
+	newMeta := tblInfo.Clone()
+	newMeta.Partition = partInfo.Clone()
+	newMeta.Partition.DDLType = model.PartitionTypeNone
+	newMeta.Partition.AddingDefinitions = nil
+	newMeta.Partition.DroppingDefinitions = nil
+	if err := checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	// A unique key not including all the columns of the new partitioning becomes a global index,
+	// which is only allowed by enable-global-index like creating the table.
+	for _, index := range newMeta.Indices {
+		if !index.Unique || index.Global {
+			continue
+		}
+		ok, err := checkPartitionKeysConstraint(newMeta.Partition, index.Columns, newMeta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			switch {
+			case index.Primary && newMeta.IsCommonHandle:
+				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("CLUSTERED INDEX")
+			case config.GetGlobalConfig().EnableGlobalIndex:
+			case index.Primary:
+				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+			default:
+				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+			}
+		}
+	}
+	// when PKIsHandle, tblInfo.Indices will not contain the primary key.
+	if pkCol := newMeta.GetPkColInfo(); newMeta.PKIsHandle && pkCol != nil {
+		indexCols := []*model.IndexColumn{{Name: pkCol.Name, Offset: pkCol.Offset, Length: types.UnspecifiedLength}}
+		ok, err := checkPartitionKeysConstraint(newMeta.Partition, indexCols, newMeta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("CLUSTERED INDEX")
+		}
+	}
+	return nil
+}
+
 func checkReorgPartitionDefs(ctx sessionctx.Context, tblInfo *model.TableInfo, partInfo *model.PartitionInfo, firstPartIdx, lastPartIdx int, idMap map[int]struct{}) error {
 	// partInfo contains only the new added partition, we have to combine it with the
 	// old partitions to check all partitions is strictly increasing.
@@ -4781,6 +5435,7 @@ func (d *ddl) ExchangeTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 		return errors.Trace(err)
 	}
 
+	tzName, tzOffset := ddlutil.GetTimeZone(ctx)
 	job := &model.Job{
 		SchemaID:   ntSchema.ID,
 		TableID:    ntMeta.ID,
@@ -4790,6 +5445,12 @@ func (d *ddl) ExchangeTablePartition(ctx sessionctx.Context, ident ast.Ident, sp
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       []interface{}{defID, ptSchema.ID, ptMeta.ID, partName, spec.WithValidation},
 		CtxVars:    []interface{}{[]int64{ntSchema.ID, ptSchema.ID}, []int64{ntMeta.ID, ptMeta.ID}},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+			Location:      &model.TimeZoneLocation{Name: tzName, Offset: tzOffset},
+		},
 	}
 
 	err = d.DoDDLJob(ctx, job)
@@ -4867,6 +5528,10 @@ func checkIsDroppableColumn(ctx sessionctx.Context, is infoschema.InfoSchema, sc
 	if err != nil {
 		return false, errors.Trace(err)
 	}
+	err = checkColumnWithRowAccessPolicies(tblInfo, colName)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
 	// We don't support dropping column with PK handle covered now.
 	if col.IsPKHandleColumn(tblInfo) {
 		return false, dbterror.ErrUnsupportedPKHandle
@@ -5196,6 +5861,9 @@ func GetModifiableColumnJob(
 		if errG != nil {
 			return nil, errors.Trace(errG)
 		}
+		if err = checkColumnWithRowAccessPolicies(t.Meta(), originalColName); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -5283,9 +5951,6 @@ func GetModifiableColumnJob(
 		if err = isGeneratedRelatedColumn(t.Meta(), newCol.ColumnInfo, col.ColumnInfo); err != nil {
 			return nil, errors.Trace(err)
 		}
-		if t.Meta().Partition != nil {
-			return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("table is partition table")
-		}
 	}
 
 	// Check that the column change does not affect the partitioning column
@@ -5312,22 +5977,26 @@ func GetModifiableColumnJob(
 			if !isColTypeAllowedAsPartitioningCol(t.Meta().Partition.Type, newCol.FieldType) {
 				return nil, dbterror.ErrNotAllowedTypeInPartition.GenWithStackByArgs(newCol.Name.O)
 			}
-			pi := pt.Meta().GetPartitionInfo()
-			if len(pi.Columns) == 0 {
-				// non COLUMNS partitioning, only checks INTs, not their actual range
-				// There are many edge cases, like when truncating SQL Mode is allowed
-				// which will change the partitioning expression value resulting in a
-				// different partition. Better be safe and not allow decreasing of length.
-				// TODO: Should we allow it in strict mode? Wait for a use case / request.
-				if newCol.FieldType.GetFlen() < col.FieldType.GetFlen() {
-					return nil, dbterror.ErrUnsupportedModifyCollation.GenWithStack("Unsupported modify column, decreasing length of int may result in truncation and change of partition")
+			// The rows are converted within their current partition, so a converted value
+			// must be the same as the original one. There are many edge cases, like when
+			// truncating SQL Mode is allowed, which will change the partitioning expression
+			// value resulting in a different partition. So only allow it in strict mode,
+			// where the conversion fails instead of truncating.
+			if needChangeColData && !sctx.GetSessionVars().SQLMode.HasStrictMode() {
+				return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("changing the type of a partitioning column requires strict SQL mode, since truncated values may belong to another partition")
+			}
+			// Lowering the fractional seconds precision or the scale rounds the values silently even in
+			// strict mode, and a rounded value may belong to another partition or hash to another one.
+			switch col.GetType() {
+			case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration, mysql.TypeNewDecimal:
+				if newCol.GetDecimal() < col.GetDecimal() {
+					return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't decrease the fractional seconds precision or the scale of the partitioning column, since the rounded values may belong to another partition")
 				}
 			}
-			// Basically only allow changes of the length/decimals for the column
+			// Basically only allow changes of the length/decimals/signedness for the column
 			// Note that enum is not allowed, so elems are not checked
 			// TODO: support partition by ENUM
 			if newCol.FieldType.EvalType() != col.FieldType.EvalType() ||
-				newCol.FieldType.GetFlag() != col.FieldType.GetFlag() ||
 				newCol.FieldType.GetCollate() != col.FieldType.GetCollate() ||
 				newCol.FieldType.GetCharset() != col.FieldType.GetCharset() {
 				return nil, dbterror.ErrUnsupportedModifyColumn.GenWithStackByArgs("can't change the partitioning column, since it would require reorganize all partitions")
@@ -5692,6 +6361,10 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 	if err != nil {
 		return errors.Trace(err)
 	}
+	err = checkColumnWithRowAccessPolicies(tbl.Meta(), oldColName)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	tzName, tzOffset := ddlutil.GetTimeZone(ctx)
 
@@ -6075,6 +6748,77 @@ func (d *ddl) AlterTableRemoveTTL(ctx sessionctx.Context, ident ast.Ident) error
 	return nil
 }
 
+// AlterTableIntervalPartition submits a ddl job to change the interval partition policy of a table
+// according to the PARTITION_INTERVAL, PARTITION_PRECREATE and PARTITION_RETENTION options.
+func (d *ddl) AlterTableIntervalPartition(ctx sessionctx.Context, ident ast.Ident, options []*ast.TableOption) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema)
+	}
+
+	tb, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	tblInfo := tb.Meta().Clone()
+	info, err := buildIntervalPartitionInfo(tblInfo.IntervalPartitionInfo, options)
+	if err != nil {
+		return err
+	}
+	tblInfo.IntervalPartitionInfo = info
+	if err = checkIntervalPartitionInfoValid(ctx, tblInfo); err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tblInfo.Name.L,
+		Type:       model.ActionAlterIntervalPartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{info},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
+// AlterTableRemoveIntervalPartition submits a ddl job to remove the interval partition policy of a table.
+// The partitions are kept, but they are not maintained automatically any more.
+func (d *ddl) AlterTableRemoveIntervalPartition(ctx sessionctx.Context, ident ast.Ident) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema)
+	}
+
+	tb, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	tblInfo := tb.Meta()
+	if tblInfo.IntervalPartitionInfo == nil {
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		TableName:  tblInfo.Name.L,
+		Type:       model.ActionAlterIntervalPartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{(*model.IntervalPartitionInfo)(nil)},
+	}
+	err = d.DoDDLJob(ctx, job)
+	err = d.callHookOnChanged(job, err)
+	return errors.Trace(err)
+}
+
 func isTableTiFlashSupported(schema *model.DBInfo, tb table.Table) error {
 	// Memory tables and system tables are not supported by TiFlash
 	if util.IsMemOrSysDB(schema.Name.L) {
@@ -6422,6 +7166,9 @@ func (d *ddl) dropTableObject(
 			if tableInfo.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 				return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Drop Table")
 			}
+			if err = checkMaterializedViewTableDDL(tableInfo.Meta(), "DROP TABLE"); err != nil {
+				return err
+			}
 		case viewObject:
 			if !tableInfo.Meta().IsView() {
 				return dbterror.ErrWrongObject.GenWithStackByArgs(fullti.Schema, fullti.Name, "VIEW")
@@ -6501,6 +7248,9 @@ func (d *ddl) TruncateTable(ctx sessionctx.Context, ti ast.Ident) error {
 	if tb.Meta().TableCacheStatusType != model.TableCacheStatusDisable {
 		return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Truncate Table")
 	}
+	if err = checkMaterializedViewTableDDL(tb.Meta(), "TRUNCATE TABLE"); err != nil {
+		return err
+	}
 	fkCheck := ctx.GetSessionVars().ForeignKeyChecks
 	referredFK := checkTableHasForeignKeyReferred(d.GetInfoSchemaWithInterceptor(ctx), ti.Schema.L, ti.Name.L, []ast.Ident{{Name: ti.Name, Schema: ti.Schema}}, fkCheck)
 	if referredFK != nil {
@@ -6808,19 +7558,9 @@ func (d *ddl) CreatePrimaryKey(ctx sessionctx.Context, ti ast.Ident, indexName m
 		return err
 	}
 
-	global := false
-	if tblInfo.GetPartitionInfo() != nil {
-		ck, err := checkPartitionKeysConstraint(tblInfo.GetPartitionInfo(), indexColumns, tblInfo)
-		if err != nil {
-			return err
-		}
-		if !ck {
-			if !config.GetGlobalConfig().EnableGlobalIndex {
-				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY")
-			}
-			// index columns does not contain all partition columns, must set global
-			global = true
-		}
+	global, err := decideGlobalIndex(tblInfo, indexColumns, true, true, indexOption)
+	if err != nil {
+		return err
 	}
 
 	// May be truncate comment here, when index comment too long and sql_mode is't strict.
@@ -7052,19 +7792,9 @@ func (d *ddl) createIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 		return errors.Trace(err)
 	}
 
-	global := false
-	if unique && tblInfo.GetPartitionInfo() != nil {
-		ck, err := checkPartitionKeysConstraint(tblInfo.GetPartitionInfo(), indexColumns, tblInfo)
-		if err != nil {
-			return err
-		}
-		if !ck {
-			if !config.GetGlobalConfig().EnableGlobalIndex {
-				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
-			}
-			// index columns does not contain all partition columns, must set global
-			global = true
-		}
+	global, err := decideGlobalIndex(tblInfo, indexColumns, false, unique, indexOption)
+	if err != nil {
+		return err
 	}
 	// May be truncate comment here, when index comment too long and sql_mode is't strict.
 	if indexOption != nil {
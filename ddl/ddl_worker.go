@@ -545,7 +545,9 @@ func jobNeedGC(job *model.Job) bool {
 			model.ActionDropTablePartition, model.ActionTruncateTablePartition,
 			model.ActionDropColumn, model.ActionModifyColumn,
 			model.ActionAddIndex, model.ActionAddPrimaryKey,
-			model.ActionReorganizePartition:
+			model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+			model.ActionRemovePartitioning, model.ActionDropMaterializedView,
+			model.ActionExchangeTablePartition:
 			return true
 		case model.ActionMultiSchemaChange:
 			for _, sub := range job.MultiSchemaInfo.SubJobs {
@@ -682,10 +684,12 @@ func (w *worker) unlockSeqNum(err error) {
 
 // DDLBackfillers contains the DDL need backfill step.
 var DDLBackfillers = map[model.ActionType]string{
-	model.ActionAddIndex:            "add_index",
-	model.ActionModifyColumn:        "modify_column",
-	model.ActionDropIndex:           "drop_index",
-	model.ActionReorganizePartition: "reorganize_partition",
+	model.ActionAddIndex:               "add_index",
+	model.ActionModifyColumn:           "modify_column",
+	model.ActionDropIndex:              "drop_index",
+	model.ActionReorganizePartition:    "reorganize_partition",
+	model.ActionAlterTablePartitioning: "alter_table_partitioning",
+	model.ActionRemovePartitioning:     "remove_partitioning",
 }
 
 func getDDLRequestSource(jobType model.ActionType) string {
@@ -1034,6 +1038,10 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onCreateView(d, t, job)
 	case model.ActionDropTable, model.ActionDropView, model.ActionDropSequence:
 		ver, err = onDropTableOrView(d, t, job)
+	case model.ActionCreateMaterializedView:
+		ver, err = onCreateMaterializedView(d, t, job)
+	case model.ActionDropMaterializedView:
+		ver, err = onDropMaterializedView(d, t, job)
 	case model.ActionDropTablePartition:
 		ver, err = w.onDropTablePartition(d, t, job)
 	case model.ActionTruncateTablePartition:
@@ -1124,12 +1132,23 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = w.onFlashbackCluster(d, t, job)
 	case model.ActionMultiSchemaChange:
 		ver, err = onMultiSchemaChange(w, d, t, job)
-	case model.ActionReorganizePartition:
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+		model.ActionRemovePartitioning:
 		ver, err = w.onReorganizePartition(d, t, job)
 	case model.ActionAlterTTLInfo:
 		ver, err = onTTLInfoChange(d, t, job)
 	case model.ActionAlterTTLRemove:
 		ver, err = onTTLInfoRemove(d, t, job)
+	case model.ActionAlterIntervalPartition:
+		ver, err = onAlterIntervalPartition(d, t, job)
+	case model.ActionCreateMaskingPolicy:
+		ver, err = onCreateMaskingPolicy(d, t, job)
+	case model.ActionDropMaskingPolicy:
+		ver, err = onDropMaskingPolicy(d, t, job)
+	case model.ActionCreateRowAccessPolicy:
+		ver, err = onCreateRowAccessPolicy(d, t, job)
+	case model.ActionDropRowAccessPolicy:
+		ver, err = onDropRowAccessPolicy(d, t, job)
 	case model.ActionAddCheckConstraint:
 		ver, err = w.onAddCheckConstraint(d, t, job)
 	case model.ActionDropCheckConstraint:
@@ -1343,6 +1362,18 @@ func updateSchemaVersion(d *ddlCtx, t *meta.Meta, job *model.Job, multiInfos ...
 			diff.OldTableID = oldTbInfoID
 		}
 		diff.TableID = tbInfo.ID
+	case model.ActionCreateMaterializedView, model.ActionDropMaterializedView:
+		// The log table of the materialized view and the base table are changed at the same time.
+		if job.Type == model.ActionCreateMaterializedView {
+			diff.TableID = job.TableID
+		} else {
+			diff.OldTableID = job.TableID
+		}
+		if len(job.CtxVars) > 0 {
+			if affects, ok := job.CtxVars[0].([]*model.AffectedOption); ok {
+				diff.AffectedOpts = affects
+			}
+		}
 	case model.ActionRenameTable:
 		err = job.DecodeArgs(&diff.OldSchemaID)
 		if err != nil {
@@ -1405,8 +1436,13 @@ func updateSchemaVersion(d *ddlCtx, t *meta.Meta, job *model.Job, multiInfos ...
 				diff.AffectedOpts = buildPlacementAffects(oldIDs, oldIDs)
 			}
 		}
-	case model.ActionReorganizePartition:
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+		model.ActionRemovePartitioning:
 		diff.TableID = job.TableID
+		if job.Type != model.ActionReorganizePartition {
+			// The table is replaced by one with a new ID in the end.
+			diff.OldTableID = job.TableID
+		}
 		if len(job.CtxVars) > 0 {
 			if droppedIDs, ok := job.CtxVars[0].([]int64); ok {
 				if addedIDs, ok := job.CtxVars[1].([]int64); ok {
@@ -1420,6 +1456,11 @@ func updateSchemaVersion(d *ddlCtx, t *meta.Meta, job *model.Job, multiInfos ...
 					diff.AffectedOpts = buildPlacementAffects(oldIDs, newIDs)
 				}
 			}
+			if len(job.CtxVars) > 2 {
+				if newTableID, ok := job.CtxVars[2].(int64); ok {
+					diff.TableID = newTableID
+				}
+			}
 		}
 	case model.ActionCreateTable:
 		diff.TableID = job.TableID
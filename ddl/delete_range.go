@@ -313,13 +313,29 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 		endKey := tablecodec.EncodeTablePrefix(tableID + 1)
 		elemID := ea.allocForPhysicalID(tableID)
 		return doInsert(ctx, s, job.ID, elemID, startKey, endKey, now, fmt.Sprintf("table ID is %d", tableID))
-	case model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionReorganizePartition:
+	case model.ActionDropMaterializedView:
+		var tableIDs []int64
+		if err := job.DecodeArgs(&tableIDs); err != nil {
+			return errors.Trace(err)
+		}
+		for _, tableID := range tableIDs {
+			startKey := tablecodec.EncodeTablePrefix(tableID)
+			endKey := tablecodec.EncodeTablePrefix(tableID + 1)
+			elemID := ea.allocForPhysicalID(tableID)
+			if err := doInsert(ctx, s, job.ID, elemID, startKey, endKey, now, fmt.Sprintf("table ID is %d", tableID)); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	case model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionReorganizePartition,
+		model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
 		var physicalTableIDs []int64
 		// partInfo is not used, but is set in ReorgPartition.
 		// Better to have an additional argument in job.DecodeArgs since it is ignored,
 		// instead of having one to few, which will remove the data from the job arguments...
 		var partInfo model.PartitionInfo
-		if err := job.DecodeArgs(&physicalTableIDs, &partInfo); err != nil {
+		// globalIndexIDs are the global indexes replaced or rolled back when reorganizing partitions.
+		var globalIndexIDs []int64
+		if err := job.DecodeArgs(&physicalTableIDs, &partInfo, &globalIndexIDs); err != nil {
 			return errors.Trace(err)
 		}
 		for _, physicalTableID := range physicalTableIDs {
@@ -330,6 +346,35 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 				return errors.Trace(err)
 			}
 		}
+		for _, indexID := range globalIndexIDs {
+			startKey := tablecodec.EncodeTableIndexPrefix(job.TableID, indexID)
+			endKey := tablecodec.EncodeTableIndexPrefix(job.TableID, indexID+1)
+			elemID := ea.allocForIndexID(job.TableID, indexID)
+			if err := doInsert(ctx, s, job.ID, elemID, startKey, endKey, now, fmt.Sprintf("index ID is %d", indexID)); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	case model.ActionExchangeTablePartition:
+		var (
+			defID          int64
+			ptSchemaID     int64
+			ptID           int64
+			partName       string
+			withValidation bool
+			// indexIDs are the indexes of the table, which are global indexes in the partition after exchanging.
+			indexIDs []int64
+		)
+		if err := job.DecodeArgs(&defID, &ptSchemaID, &ptID, &partName, &withValidation, &indexIDs); err != nil {
+			return errors.Trace(err)
+		}
+		for _, indexID := range indexIDs {
+			startKey := tablecodec.EncodeTableIndexPrefix(job.TableID, indexID)
+			endKey := tablecodec.EncodeTableIndexPrefix(job.TableID, indexID+1)
+			elemID := ea.allocForIndexID(job.TableID, indexID)
+			if err := doInsert(ctx, s, job.ID, elemID, startKey, endKey, now, fmt.Sprintf("index ID is %d", indexID)); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	// ActionAddIndex, ActionAddPrimaryKey needs do it, because it needs to be rolled back when it's canceled.
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
 		var indexID int64
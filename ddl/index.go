@@ -1510,6 +1510,14 @@ func (w *addIndexTxnWorker) checkHandleExists(key kv.Key, value []byte, handle k
 		return errors.Trace(err)
 	}
 	hasBeenBackFilled := h.Equal(handle)
+	if hasBeenBackFilled && idxInfo.Global {
+		// The same handle may exist in another partition.
+		pid, _, err := tablecodec.DecodePartitionIDInIndexValue(value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		hasBeenBackFilled = pid == w.table.(table.PhysicalTable).GetPhysicalID()
+	}
 	if hasBeenBackFilled {
 		return nil
 	}
@@ -1726,6 +1734,14 @@ func writeOneKVToLocal(writer ingest.Writer,
 	return nil
 }
 
+// backfilledHandle returns the handle decoded from the index value written by the backfill of the row.
+func (w *addIndexTxnWorker) backfilledHandle(h kv.Handle) kv.Handle {
+	if w.index.Meta().Global {
+		return kv.NewPartitionHandle(w.table.(table.PhysicalTable).GetPhysicalID(), h)
+	}
+	return h
+}
+
 // BackfillData will backfill table index in a transaction. A lock corresponds to a rowKey if the value of rowKey is changed,
 // Note that index columns values may change, and an index is not allowed to be added, so the txn will rollback and retry.
 // BackfillData will add w.batchCnt indices once, default value of w.batchCnt is 128.
@@ -1778,7 +1794,7 @@ func (w *addIndexTxnWorker) BackfillData(handleRange reorgBackfillTask) (taskCtx
 
 			handle, err := w.index.Create(w.sessCtx, txn, idxRecord.vals, idxRecord.handle, idxRecord.rsData, table.WithIgnoreAssertion, table.FromBackfill)
 			if err != nil {
-				if kv.ErrKeyExists.Equal(err) && idxRecord.handle.Equal(handle) {
+				if kv.ErrKeyExists.Equal(err) && w.backfilledHandle(idxRecord.handle).Equal(handle) {
 					// Index already exists, skip it.
 					continue
 				}
@@ -1940,7 +1956,7 @@ func getNextPartitionInfo(reorg *reorgInfo, t table.PartitionedTable, currPhysic
 	nextPartitionDefs := pi.DroppingDefinitions
 	if bytes.Equal(reorg.currElement.TypeKey, meta.IndexElementKey) {
 		// During index re-creation, process data from partitions to be added
-		nextPartitionDefs = pi.AddingDefinitions
+		nextPartitionDefs = getReorgIndexPartitionDefs(pi, reorg.currElement.ID)
 	}
 	if len(nextPartitionDefs) == 0 {
 		nextPartitionDefs = pi.Definitions
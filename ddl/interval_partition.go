@@ -0,0 +1,262 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+func onAlterIntervalPartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	// A nil info removes the interval partition policy.
+	var info *model.IntervalPartitionInfo
+	if err := job.DecodeArgs(&info); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo.IntervalPartitionInfo = info
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// buildIntervalPartitionInfo returns the interval partition policy after applying the PARTITION_INTERVAL,
+// PARTITION_PRECREATE and PARTITION_RETENTION options to `orig`, which is nil if the table has no policy.
+func buildIntervalPartitionInfo(orig *model.IntervalPartitionInfo, options []*ast.TableOption) (*model.IntervalPartitionInfo, error) {
+	var info *model.IntervalPartitionInfo
+	if orig != nil {
+		info = orig.Clone()
+	}
+	for _, op := range options {
+		if op.Tp == ast.TableOptionPartitionInterval {
+			exprStr, err := restoreIntervalPartitionValue(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			if info == nil {
+				info = &model.IntervalPartitionInfo{Precreate: model.DefaultIntervalPartitionPrecreate}
+			}
+			info.IntervalExprStr = exprStr
+			info.IntervalTimeUnit = int(op.TimeUnitValue.Unit)
+		}
+	}
+	for _, op := range options {
+		switch op.Tp {
+		case ast.TableOptionPartitionPrecreate:
+			if info == nil {
+				return nil, dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("PARTITION_PRECREATE without PARTITION_INTERVAL")
+			}
+			info.Precreate = op.UintValue
+		case ast.TableOptionPartitionRetention:
+			if info == nil {
+				return nil, dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("PARTITION_RETENTION without PARTITION_INTERVAL")
+			}
+			exprStr, err := restoreIntervalPartitionValue(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			info.RetentionExprStr = exprStr
+			info.RetentionTimeUnit = int(op.TimeUnitValue.Unit)
+		}
+	}
+	return info, nil
+}
+
+func restoreIntervalPartitionValue(value ast.ExprNode) (string, error) {
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.RestoreStringSingleQuotes, &sb)
+	if err := value.Restore(restoreCtx); err != nil {
+		return "", err
+	}
+	return driver.UnwrapFromSingleQuotes(sb.String()), nil
+}
+
+// checkIntervalPartitionInfoValid checks whether the partitions of the table can be maintained by its
+// interval partition policy.
+func checkIntervalPartitionInfoValid(ctx sessionctx.Context, tblInfo *model.TableInfo) error {
+	info := tblInfo.IntervalPartitionInfo
+	if info == nil {
+		return nil
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil || pi.Type != model.PartitionTypeRange || len(pi.Columns) != 1 {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("PARTITION_INTERVAL, only allowed on RANGE COLUMNS partitioning with a single column")
+	}
+	partCol := findColumnByName(pi.Columns[0].L, tblInfo)
+	if partCol == nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("PARTITION_INTERVAL, could not find the partitioning column")
+	}
+	tp := partCol.FieldType.GetType()
+	if tp != mysql.TypeDate && tp != mysql.TypeDatetime {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("PARTITION_INTERVAL, only supports Date and Datetime partitioning columns")
+	}
+	if err := checkIntervalPartitionDuration("PARTITION_INTERVAL", info.IntervalExprStr, ast.TimeUnitType(info.IntervalTimeUnit), tp); err != nil {
+		return err
+	}
+	if info.Precreate < 1 || info.Precreate > mysql.PartitionCountLimit {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(fmt.Sprintf("PARTITION_PRECREATE, should be between 1 and %d", mysql.PartitionCountLimit))
+	}
+	if info.RetentionExprStr != "" {
+		if err := checkIntervalPartitionDuration("PARTITION_RETENTION", info.RetentionExprStr, ast.TimeUnitType(info.RetentionTimeUnit), tp); err != nil {
+			return err
+		}
+	}
+	for _, def := range pi.Definitions {
+		if strings.EqualFold(def.LessThan[0], partitionMaxValue) {
+			return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("PARTITION_INTERVAL when MAXVALUE partition exists")
+		}
+	}
+	if getPartitionIntervalFromTable(ctx, tblInfo) == nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("PARTITION_INTERVAL, the partitions do not match the interval")
+	}
+	return nil
+}
+
+func checkIntervalPartitionDuration(option, exprStr string, unit ast.TimeUnitType, colTp byte) error {
+	if val, err := strconv.ParseUint(exprStr, 10, 64); err != nil || val == 0 {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(option + ", should be a positive integer")
+	}
+	switch unit {
+	case ast.TimeUnitYear, ast.TimeUnitQuarter, ast.TimeUnitMonth, ast.TimeUnitWeek, ast.TimeUnitDay:
+	case ast.TimeUnitHour:
+		if colTp == mysql.TypeDate {
+			return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(option + ", HOUR is not supported for Date partitioning columns")
+		}
+	default:
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(option + ", only supports YEAR, QUARTER, MONTH, WEEK, DAY and HOUR as time unit")
+	}
+	return nil
+}
+
+// GetIntervalPartitionChanges returns the values to maintain the partitions of a table by its interval
+// partition policy at `now`:
+//   - lastLessThan is the value for `ALTER TABLE ... LAST PARTITION LESS THAN`, to make sure there are
+//     partitions for at least `PARTITION_PRECREATE` intervals after `now`.
+//   - firstLessThan is the value for `ALTER TABLE ... FIRST PARTITION LESS THAN`, to drop the partitions
+//     whose data are all older than `PARTITION_RETENTION` before `now`.
+//
+// An empty value means no change is needed.
+func GetIntervalPartitionChanges(ctx sessionctx.Context, tblInfo *model.TableInfo, now time.Time) (lastLessThan, firstLessThan string, err error) {
+	if tblInfo.IntervalPartitionInfo == nil {
+		return "", "", errors.Errorf("table '%s' does not have an interval partition policy", tblInfo.Name.O)
+	}
+	if err = checkIntervalPartitionInfoValid(ctx, tblInfo); err != nil {
+		return "", "", err
+	}
+	info := tblInfo.IntervalPartitionInfo
+	interval := getPartitionIntervalFromTable(ctx, tblInfo)
+	nowStr := now.In(ctx.GetSessionVars().Location()).Format(types.TimeFormat)
+
+	defs := tblInfo.Partition.Definitions
+	lastStr := driver.UnwrapFromSingleQuotes(defs[len(defs)-1].LessThan[0])
+	_, lastTime, err := evalIntervalPartitionTime(ctx, lastStr, 0, info.IntervalExprStr, info.IntervalTimeUnit)
+	if err != nil {
+		return "", "", err
+	}
+	_, precreateTime, err := evalIntervalPartitionTime(ctx, nowStr, int64(info.Precreate), info.IntervalExprStr, info.IntervalTimeUnit)
+	if err != nil {
+		return "", "", err
+	}
+	for i := int64(1); lastTime.Compare(precreateTime) <= 0; i++ {
+		if i > mysql.PartitionCountLimit {
+			return "", "", errors.Trace(dbterror.ErrTooManyPartitions)
+		}
+		lastLessThan, lastTime, err = evalIntervalPartitionTime(ctx, lastStr, i, info.IntervalExprStr, info.IntervalTimeUnit)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if info.RetentionExprStr == "" {
+		return lastLessThan, "", nil
+	}
+	_, expireTime, err := evalIntervalPartitionTime(ctx, nowStr, -1, info.RetentionExprStr, info.RetentionTimeUnit)
+	if err != nil {
+		return "", "", err
+	}
+	if interval.NullPart {
+		defs = defs[1:]
+	}
+	for i, def := range defs {
+		lessThanStr, lessThan, err := evalIntervalPartitionTime(ctx, driver.UnwrapFromSingleQuotes(def.LessThan[0]), 0, info.IntervalExprStr, info.IntervalTimeUnit)
+		if err != nil {
+			return "", "", err
+		}
+		if lessThan.Compare(expireTime) > 0 {
+			if i > 0 {
+				firstLessThan = lessThanStr
+			}
+			break
+		}
+	}
+	return lastLessThan, firstLessThan, nil
+}
+
+// evalIntervalPartitionTime evaluates `DATE_ADD(start, INTERVAL n * interval unit)`, it returns both the
+// result string, which is used as the LESS THAN value of the partitions, and the result time to compare.
+func evalIntervalPartitionTime(ctx sessionctx.Context, start string, n int64, interval string, unit int) (string, types.Time, error) {
+	expr := &ast.FuncCallExpr{
+		FnName: model.NewCIStr("DATE_ADD"),
+		Args: []ast.ExprNode{
+			ast.NewValueExpr(start, "", ""),
+			&ast.BinaryOperationExpr{
+				Op: opcode.Mul,
+				L:  ast.NewValueExpr(n, "", ""),
+				R:  ast.NewValueExpr(interval, "", ""),
+			},
+			&ast.TimeUnitExpr{Unit: ast.TimeUnitType(unit)},
+		},
+	}
+	val, err := expression.EvalAstExpr(ctx, expr)
+	if err != nil {
+		return "", types.ZeroTime, err
+	}
+	if val.IsNull() {
+		return "", types.ZeroTime, errors.Errorf("invalid time value '%s'", start)
+	}
+	str, err := val.ToString()
+	if err != nil {
+		return "", types.ZeroTime, err
+	}
+	t, err := types.ParseDatetime(ctx.GetSessionVars().StmtCtx, str)
+	if err != nil {
+		return "", types.ZeroTime, err
+	}
+	return str, t, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalPartitionTableOptions(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec(`create table t (id int, d date) partition_interval = interval 1 day partition_retention = interval 7 day
+		partition by range columns (d) interval (1 day) first partition less than ('2023-01-01') last partition less than ('2023-01-03')`)
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) DEFAULT NULL,\n" +
+		"  `d` date DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![interval_partition] PARTITION_INTERVAL=INTERVAL 1 DAY PARTITION_PRECREATE=2 PARTITION_RETENTION=INTERVAL 7 DAY */\n" +
+		"PARTITION BY RANGE COLUMNS(`d`)\n" +
+		"(PARTITION `P_LT_2023-01-01` VALUES LESS THAN ('2023-01-01'),\n" +
+		" PARTITION `P_LT_2023-01-02` VALUES LESS THAN ('2023-01-02'),\n" +
+		" PARTITION `P_LT_2023-01-03` VALUES LESS THAN ('2023-01-03'))"))
+	tk.MustExec("alter table t partition_precreate = 3")
+	tk.MustQuery("select create_options from information_schema.tables where table_name = 't'").Check(testkit.Rows("partitioned"))
+	tbl, err := domain.GetDomain(tk.Session()).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	require.Equal(t, &model.IntervalPartitionInfo{
+		IntervalExprStr:   "1",
+		IntervalTimeUnit:  int(ast.TimeUnitDay),
+		Precreate:         3,
+		RetentionExprStr:  "7",
+		RetentionTimeUnit: int(ast.TimeUnitDay),
+	}, tbl.Meta().IntervalPartitionInfo)
+
+	// The interval of the policy is used instead of calculating it from the partitions.
+	tk.MustExec("alter table t last partition less than ('2023-01-05')")
+	tk.MustQuery("select partition_description from information_schema.partitions where table_name = 't'").Check(testkit.Rows(
+		"'2023-01-01'", "'2023-01-02'", "'2023-01-03'", "'2023-01-04'", "'2023-01-05'"))
+
+	// The statement can be executed again with the special comment.
+	showCreate := tk.MustQuery("show create table t").Rows()[0][1].(string)
+	tk.MustExec("drop table t")
+	tk.MustExec(showCreate)
+	tk.MustQuery("show create table t").Check(testkit.Rows("t " + showCreate))
+	tk.MustExec("create table t2 like t")
+	tbl2, err := domain.GetDomain(tk.Session()).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	require.NoError(t, err)
+	require.Equal(t, tbl.Meta().IntervalPartitionInfo, tbl2.Meta().IntervalPartitionInfo)
+
+	tk.MustExec("alter table t remove partition_interval")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `id` int(11) DEFAULT NULL,\n" +
+		"  `d` date DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE COLUMNS(`d`)\n" +
+		"(PARTITION `P_LT_2023-01-01` VALUES LESS THAN ('2023-01-01'),\n" +
+		" PARTITION `P_LT_2023-01-02` VALUES LESS THAN ('2023-01-02'),\n" +
+		" PARTITION `P_LT_2023-01-03` VALUES LESS THAN ('2023-01-03'),\n" +
+		" PARTITION `P_LT_2023-01-04` VALUES LESS THAN ('2023-01-04'),\n" +
+		" PARTITION `P_LT_2023-01-05` VALUES LESS THAN ('2023-01-05'))"))
+	tk.MustExec("alter table t remove partition_interval")
+	tk.MustGetErrMsg("alter table t partition_precreate = 3", "[ddl:8200]Unsupported PARTITION_PRECREATE without PARTITION_INTERVAL")
+	tk.MustExec("alter table t partition_interval = interval 1 day")
+	tk.MustGetErrMsg("alter table t partition_interval = interval 2 day", "[ddl:8200]Unsupported PARTITION_INTERVAL, the partitions do not match the interval")
+	tk.MustGetErrMsg("alter table t partition_interval = interval 1 hour", "[ddl:8200]Unsupported PARTITION_INTERVAL, HOUR is not supported for Date partitioning columns")
+	tk.MustGetErrMsg("alter table t partition_interval = interval 1 second", "[ddl:8200]Unsupported PARTITION_INTERVAL, only supports YEAR, QUARTER, MONTH, WEEK, DAY and HOUR as time unit")
+	tk.MustGetErrMsg("alter table t partition_interval = interval '1.5' day", "[ddl:8200]Unsupported PARTITION_INTERVAL, should be a positive integer")
+	tk.MustGetErrMsg("alter table t partition_precreate = 0", "[ddl:8200]Unsupported PARTITION_PRECREATE, should be between 1 and 8192")
+	tk.MustGetErrMsg("alter table t partition_retention = interval 0 day", "[ddl:8200]Unsupported PARTITION_RETENTION, should be a positive integer")
+
+	tk.MustGetErrMsg("create table t3 (id int, d date) partition_interval = interval 1 day",
+		"[ddl:8200]Unsupported PARTITION_INTERVAL, only allowed on RANGE COLUMNS partitioning with a single column")
+	tk.MustGetErrMsg("create table t3 (id int, d date) partition_interval = interval 1 day partition by range (id) (partition p0 values less than (10))",
+		"[ddl:8200]Unsupported PARTITION_INTERVAL, only allowed on RANGE COLUMNS partitioning with a single column")
+	tk.MustGetErrMsg("create table t3 (id int, d date) partition_interval = interval 1 day partition by range columns (id) (partition p0 values less than (10))",
+		"[ddl:8200]Unsupported PARTITION_INTERVAL, only supports Date and Datetime partitioning columns")
+	tk.MustGetErrMsg("create table t3 (id int, d date) partition_interval = interval 1 day partition by range columns (d) (partition p0 values less than ('2023-01-01'), partition p1 values less than (maxvalue))",
+		"[ddl:8200]Unsupported PARTITION_INTERVAL when MAXVALUE partition exists")
+	tk.MustGetErrMsg("create table t3 (id int, d date) partition_retention = interval 1 day partition by range columns (d) (partition p0 values less than ('2023-01-01'))",
+		"[ddl:8200]Unsupported PARTITION_RETENTION without PARTITION_INTERVAL")
+	// A single partition is enough to start with.
+	tk.MustExec("create table t3 (id int, d datetime) partition_interval = interval 6 hour partition by range columns (d) (partition p0 values less than ('2023-01-01 00:00:00'))")
+	tk.MustExec("alter table t3 last partition less than ('2023-01-01 12:00:00')")
+	tk.MustQuery("select partition_name, partition_description from information_schema.partitions where table_name = 't3'").Check(testkit.Rows(
+		"p0 '2023-01-01 00:00:00'", "P_LT_2023-01-01 06:00:00 '2023-01-01 06:00:00'", "P_LT_2023-01-01 12:00:00 '2023-01-01 12:00:00'"))
+}
+
+func TestGetIntervalPartitionChanges(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@time_zone = 'UTC'")
+
+	tk.MustExec(`create table t (id int, d datetime) partition_interval = interval 1 month partition_precreate = 2 partition_retention = interval 2 month
+		partition by range columns (d) interval (1 month) first partition less than ('2023-01-01 00:00:00') last partition less than ('2023-05-01 00:00:00')`)
+	getChanges := func(now string) (string, string) {
+		tbl, err := domain.GetDomain(tk.Session()).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+		require.NoError(t, err)
+		nowTime, err := time.ParseInLocation(time.DateTime, now, time.UTC)
+		require.NoError(t, err)
+		last, first, err := ddl.GetIntervalPartitionChanges(tk.Session(), tbl.Meta(), nowTime)
+		require.NoError(t, err)
+		return last, first
+	}
+
+	// The partitions of March and April cover the 2 months after now.
+	last, first := getChanges("2023-02-15 10:00:00")
+	require.Equal(t, "", last)
+	require.Equal(t, "", first)
+	last, first = getChanges("2023-03-01 00:00:00")
+	require.Equal(t, "2023-06-01 00:00:00", last)
+	require.Equal(t, "2023-02-01 00:00:00", first)
+	last, first = getChanges("2023-06-20 00:00:00")
+	require.Equal(t, "2023-09-01 00:00:00", last)
+	require.Equal(t, "2023-05-01 00:00:00", first)
+
+	tk.MustExec("alter table t last partition less than ('2023-09-01 00:00:00')")
+	tk.MustExec("alter table t first partition less than ('2023-05-01 00:00:00')")
+	tk.MustQuery("select partition_description from information_schema.partitions where table_name = 't'").Check(testkit.Rows(
+		"'2023-05-01 00:00:00'", "'2023-06-01 00:00:00'", "'2023-07-01 00:00:00'", "'2023-08-01 00:00:00'", "'2023-09-01 00:00:00'"))
+	last, first = getChanges("2023-06-20 00:00:00")
+	require.Equal(t, "", last)
+	require.Equal(t, "", first)
+
+	// The changes fail if the partitions don't match the policy any more.
+	tk.MustExec("alter table t reorganize partition `P_LT_2023-09-01 00:00:00` into (partition p0 values less than ('2023-08-15 00:00:00'), partition p1 values less than ('2023-09-01 00:00:00'))")
+	tbl, err := domain.GetDomain(tk.Session()).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	_, _, err = ddl.GetIntervalPartitionChanges(tk.Session(), tbl.Meta(), time.Now())
+	require.EqualError(t, err, "[ddl:8200]Unsupported PARTITION_INTERVAL, the partitions do not match the interval")
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalpartition
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/pingcap/tidb/timer/periodic"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/tikv/client-go/v2/util"
+	"go.uber.org/zap"
+)
+
+type sessionPool interface {
+	Get() (pools.Resource, error)
+	Put(pools.Resource)
+}
+
+// Maintainer maintains the partitions of all the tables with interval partition policies at `now`,
+// and records the result of each table in `summary`.
+// The error of a single table does not stop maintaining the others, it is only recorded in `summary`.
+type Maintainer func(ctx context.Context, now time.Time, summary *TimerSummary) error
+
+// timerConfig is the config of the timer maintaining the interval partitions. The first event is scheduled
+// one period after the timer is created, the partitions created with the tables are enough before it.
+var timerConfig = periodic.TimerConfig{
+	Name:            "interval_partition",
+	HookClass:       HookClass,
+	Key:             TimerKey,
+	Interval:        TimerInterval,
+	DelayFirstEvent: true,
+}
+
+// NewRuntime creates a new Runtime which maintains the partitions of the tables with interval partition
+// policies periodically by the timer in `store`.
+func NewRuntime(store *timerapi.TimerStore, pool sessionPool, getIS func() infoschema.InfoSchema) *periodic.Runtime {
+	return periodic.NewRuntime(timerConfig, store, newMaintainJob(NewSessionPoolMaintainer(pool, getIS)))
+}
+
+// newMaintainJob returns a periodic job which runs `maintain` with the summary stored in the timer.
+func newMaintainJob(maintain Maintainer) periodic.Job {
+	return func(ctx context.Context, now time.Time, data []byte) ([]byte, error) {
+		summary, err := decodeTimerSummary(TimerKey, data)
+		if err != nil {
+			logutil.BgLogger().Warn("invalid interval partition timer summary, reset it", zap.Error(err))
+			summary = &TimerSummary{Tables: make(map[int64]*TableStatus)}
+		}
+		// The summary is stored even if it fails, since the status of the tables are recorded in it.
+		err = maintain(ctx, now, summary)
+		data, marshalErr := json.Marshal(summary)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		return data, err
+	}
+}
+
+// NewSessionPoolMaintainer returns a Maintainer which maintains the partitions with the sessions in `pool`.
+// The tables to maintain are got from the info schema returned by `getIS`.
+func NewSessionPoolMaintainer(pool sessionPool, getIS func() infoschema.InfoSchema) Maintainer {
+	return func(ctx context.Context, now time.Time, summary *TimerSummary) error {
+		r, err := pool.Get()
+		if err != nil {
+			return err
+		}
+
+		sctx, ok := r.(sessionctx.Context)
+		if !ok {
+			pool.Put(r)
+			return errors.New("session is not the type sessionctx.Context")
+		}
+
+		exec, ok := r.(sqlexec.SQLExecutor)
+		if !ok {
+			pool.Put(r)
+			return errors.New("session is not the type of SQLExecutor")
+		}
+
+		defer func() {
+			if _, err := exec.ExecuteInternal(util.WithInternalSourceType(context.Background(), kv.InternalTimer), "ROLLBACK"); err != nil {
+				terror.Log(err)
+				return
+			}
+			pool.Put(r)
+		}()
+
+		ctx = util.WithInternalSourceType(ctx, kv.InternalTimer)
+		tables := make(map[int64]*TableStatus, len(summary.Tables))
+		is := getIS()
+		for _, db := range is.AllSchemas() {
+			for _, tbl := range is.SchemaTables(db.Name) {
+				tblInfo := tbl.Meta()
+				if tblInfo.IntervalPartitionInfo == nil {
+					continue
+				}
+				if err = ctx.Err(); err != nil {
+					return err
+				}
+
+				status, ok := summary.Tables[tblInfo.ID]
+				if !ok {
+					status = &TableStatus{}
+				}
+				status.Schema = db.Name.O
+				status.Table = tblInfo.Name.O
+				maintainTable(ctx, sctx, exec, db.Name.O, tblInfo, now, status)
+				tables[tblInfo.ID] = status
+			}
+		}
+		// The tables which are dropped or without policies any more are removed from the summary.
+		summary.Tables = tables
+		return nil
+	}
+}
+
+// maintainTable creates the partitions in advance and drops the expired partitions of a table by its
+// interval partition policy, the result is recorded in `status`.
+func maintainTable(ctx context.Context, sctx sessionctx.Context, exec sqlexec.SQLExecutor, schema string, tblInfo *model.TableInfo, now time.Time, status *TableStatus) {
+	logger := logutil.BgLogger().With(zap.String("schema", schema), zap.String("table", tblInfo.Name.O))
+	status.LastCheckTime = now
+	err := func() error {
+		lastLessThan, firstLessThan, err := ddl.GetIntervalPartitionChanges(sctx, tblInfo, now)
+		if err != nil {
+			return err
+		}
+
+		// Create the new partitions first, so the new data can always be written even if dropping fails.
+		changes := make([]string, 0, 2)
+		if lastLessThan != "" {
+			sql, err := sqlexec.EscapeSQL("ALTER TABLE %n.%n LAST PARTITION LESS THAN (%?)", schema, tblInfo.Name.O, lastLessThan)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, sql)
+		}
+		if firstLessThan != "" {
+			sql, err := sqlexec.EscapeSQL("ALTER TABLE %n.%n FIRST PARTITION LESS THAN (%?)", schema, tblInfo.Name.O, firstLessThan)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, sql)
+		}
+
+		for _, sql := range changes {
+			logger.Info("maintain interval partitions", zap.String("sql", sql))
+			rs, err := exec.ExecuteInternal(ctx, sql)
+			if rs != nil {
+				terror.Call(rs.Close)
+			}
+			if err != nil {
+				return err
+			}
+			status.LastChange = sql
+			status.LastChangeTime = now
+		}
+		return nil
+	}()
+
+	if err != nil {
+		logger.Warn("failed to maintain interval partitions", zap.Error(err))
+		status.LastStatus = StatusFailed
+		status.LastError = err.Error()
+		return
+	}
+	status.LastStatus = StatusSuccess
+	status.LastError = ""
+	status.LastSuccessTime = now
+}
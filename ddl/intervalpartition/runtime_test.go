@@ -0,0 +1,110 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalpartition_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/ddl/intervalpartition"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/testkit"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintainIntervalPartitions(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create table t1 (id int, d date) partition_interval = interval 1 day partition_precreate = 3 partition_retention = interval 2 day
+		partition by range columns (d) interval (1 day) first partition less than ('2023-01-01') last partition less than ('2023-01-03')`)
+	tk.MustExec(`create table t2 (id int, d datetime) partition_interval = interval 1 month
+		partition by range columns (d) interval (1 month) first partition less than ('2023-01-01 00:00:00') last partition less than ('2023-04-01 00:00:00')`)
+	tk.MustExec("create table t3 (id int, d date) partition by range columns (d) (partition p0 values less than ('2023-01-01'))")
+	t1, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	require.NoError(t, err)
+	t2, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	require.NoError(t, err)
+
+	maintain := intervalpartition.NewSessionPoolMaintainer(dom.SysSessionPool(), dom.InfoSchema)
+	now := time.Date(2023, 1, 4, 10, 0, 0, 0, time.Local)
+	summary := &intervalpartition.TimerSummary{Tables: map[int64]*intervalpartition.TableStatus{
+		// the status of the tables without policies are removed
+		10000: {Schema: "test", Table: "dropped"},
+	}}
+	require.NoError(t, maintain(context.Background(), now, summary))
+	tk.MustQuery("select partition_description from information_schema.partitions where table_name = 't1'").Check(testkit.Rows(
+		"'2023-01-03'", "'2023-01-04'", "'2023-01-05'", "'2023-01-06'", "'2023-01-07'", "'2023-01-08'"))
+	tk.MustQuery("select partition_description from information_schema.partitions where table_name = 't2'").Check(testkit.Rows(
+		"'2023-01-01 00:00:00'", "'2023-02-01 00:00:00'", "'2023-03-01 00:00:00'", "'2023-04-01 00:00:00'"))
+	require.Equal(t, map[int64]*intervalpartition.TableStatus{
+		t1.Meta().ID: {
+			Schema:          "test",
+			Table:           "t1",
+			LastCheckTime:   now,
+			LastStatus:      intervalpartition.StatusSuccess,
+			LastSuccessTime: now,
+			LastChange:      "ALTER TABLE `test`.`t1` FIRST PARTITION LESS THAN ('2023-01-03')",
+			LastChangeTime:  now,
+		},
+		t2.Meta().ID: {
+			Schema:          "test",
+			Table:           "t2",
+			LastCheckTime:   now,
+			LastStatus:      intervalpartition.StatusSuccess,
+			LastSuccessTime: now,
+		},
+	}, summary.Tables)
+
+	// the failure of a table is recorded and does not affect the others
+	tk.MustExec("alter table t1 reorganize partition `P_LT_2023-01-07`, `P_LT_2023-01-08` into (partition p0 values less than ('2023-01-08'))")
+	now2 := now.AddDate(0, 1, 0)
+	require.NoError(t, maintain(context.Background(), now2, summary))
+	tk.MustQuery("select partition_description from information_schema.partitions where table_name = 't2'").Check(testkit.Rows(
+		"'2023-01-01 00:00:00'", "'2023-02-01 00:00:00'", "'2023-03-01 00:00:00'", "'2023-04-01 00:00:00'", "'2023-05-01 00:00:00'"))
+	require.Equal(t, &intervalpartition.TableStatus{
+		Schema:          "test",
+		Table:           "t1",
+		LastCheckTime:   now2,
+		LastStatus:      intervalpartition.StatusFailed,
+		LastError:       "[ddl:8200]Unsupported PARTITION_INTERVAL, the partitions do not match the interval",
+		LastSuccessTime: now,
+		LastChange:      "ALTER TABLE `test`.`t1` FIRST PARTITION LESS THAN ('2023-01-03')",
+		LastChangeTime:  now,
+	}, summary.Tables[t1.Meta().ID])
+	require.Equal(t, "ALTER TABLE `test`.`t2` LAST PARTITION LESS THAN ('2023-05-01 00:00:00')", summary.Tables[t2.Meta().ID].LastChange)
+
+	// the status is shown in information_schema.interval_partitions
+	tk.MustQuery("select table_name, partition_interval, partition_precreate, partition_retention, last_status from information_schema.interval_partitions order by table_name").Check(testkit.Rows(
+		"t1 1 DAY 3 2 DAY <nil>", "t2 1 MONTH 2 <nil> <nil>"))
+	cli := timerapi.NewDefaultTimerClient(dom.IntervalPartitionTimerStore())
+	var timer *timerapi.TimerRecord
+	require.Eventually(t, func() bool {
+		timer, err = cli.GetTimerByKey(context.Background(), intervalpartition.TimerKey)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	require.NoError(t, cli.UpdateTimer(context.Background(), timer.ID, timerapi.WithSetSummaryData(data)))
+	tk.MustQuery("select table_name, last_check_time, last_status, last_error, last_success_time, last_change_time from information_schema.interval_partitions order by table_name").Check(testkit.Rows(
+		"t1 2023-02-04 10:00:00 FAILED [ddl:8200]Unsupported PARTITION_INTERVAL, the partitions do not match the interval 2023-01-04 10:00:00 2023-01-04 10:00:00",
+		"t2 2023-02-04 10:00:00 SUCCESS  2023-02-04 10:00:00 2023-02-04 10:00:00"))
+
+	tk.MustExec("alter table t1 remove partition_interval")
+	tk.MustQuery("select table_name from information_schema.interval_partitions").Check(testkit.Rows("t2"))
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalpartition
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+)
+
+const (
+	// HookClass is the hook class of the timer maintaining the interval partitions.
+	HookClass = "tidb.interval_partition"
+	// TimerKey is the key of the timer maintaining the interval partitions.
+	TimerKey = "/tidb/interval_partition/maintain"
+	// TimerInterval is the interval between two maintenance of the interval partitions.
+	TimerInterval = "10m"
+)
+
+const (
+	// StatusSuccess indicates the partitions of the table are maintained successfully.
+	StatusSuccess = "SUCCESS"
+	// StatusFailed indicates it fails to maintain the partitions of the table.
+	StatusFailed = "FAILED"
+)
+
+// TableStatus is the result of the last maintenance of a table.
+type TableStatus struct {
+	Schema          string    `json:"schema"`
+	Table           string    `json:"table"`
+	LastCheckTime   time.Time `json:"last_check_time"`
+	LastStatus      string    `json:"last_status,omitempty"`
+	LastError       string    `json:"last_error,omitempty"`
+	LastSuccessTime time.Time `json:"last_success_time"`
+	// LastChange is the last statement executed to maintain the partitions.
+	LastChange     string    `json:"last_change,omitempty"`
+	LastChangeTime time.Time `json:"last_change_time"`
+}
+
+// TimerSummary is the summary stored in the timer, it records the status of each table by its id.
+type TimerSummary struct {
+	Tables map[int64]*TableStatus `json:"tables,omitempty"`
+}
+
+// DecodeTimerSummary decodes the summary of the timer maintaining the interval partitions.
+// An empty summary is returned if no event has been finished.
+func DecodeTimerSummary(timer *timerapi.TimerRecord) (*TimerSummary, error) {
+	return decodeTimerSummary(timer.Key, timer.SummaryData)
+}
+
+func decodeTimerSummary(key string, data []byte) (*TimerSummary, error) {
+	summary := TimerSummary{Tables: make(map[int64]*TableStatus)}
+	if len(data) == 0 {
+		return &summary, nil
+	}
+
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, errors.Wrapf(err, "invalid summary of timer '%s'", key)
+	}
+	if summary.Tables == nil {
+		summary.Tables = make(map[int64]*TableStatus)
+	}
+	return &summary, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalpartition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintainJobSummary(t *testing.T) {
+	timer := &timerapi.TimerRecord{TimerSpec: timerapi.TimerSpec{Key: TimerKey}}
+	summary, err := DecodeTimerSummary(timer)
+	require.NoError(t, err)
+	require.Empty(t, summary.Tables)
+	timer.SummaryData = []byte("invalid")
+	_, err = DecodeTimerSummary(timer)
+	require.ErrorContains(t, err, "invalid summary of timer '/tidb/interval_partition/maintain'")
+
+	now := time.Unix(time.Now().Unix(), 0).UTC()
+	var sizes []int
+	job := newMaintainJob(func(_ context.Context, n time.Time, summary *TimerSummary) error {
+		require.Equal(t, now, n)
+		sizes = append(sizes, len(summary.Tables))
+		if _, ok := summary.Tables[1]; ok {
+			summary.Tables[1].LastStatus = StatusFailed
+			return errors.New("mock error")
+		}
+		summary.Tables[1] = &TableStatus{Schema: "test", Table: "t1", LastCheckTime: n, LastStatus: StatusSuccess}
+		return nil
+	})
+
+	// the invalid summary is reset
+	data, err := job(context.Background(), now, []byte("invalid"))
+	require.NoError(t, err)
+	timer.SummaryData = data
+	summary, err = DecodeTimerSummary(timer)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]*TableStatus{
+		1: {Schema: "test", Table: "t1", LastCheckTime: now, LastStatus: StatusSuccess},
+	}, summary.Tables)
+
+	// the status of the tables is stored even if the maintainer returns an error
+	data, err = job(context.Background(), now, data)
+	require.ErrorContains(t, err, "mock error")
+	timer.SummaryData = data
+	summary, err = DecodeTimerSummary(timer)
+	require.NoError(t, err)
+	require.Equal(t, StatusFailed, summary.Tables[1].LastStatus)
+	require.Equal(t, []int{0, 1}, sizes)
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+const defaultMaskingPad = "*"
+
+// buildMaskingPolicyInfo builds a MaskingPolicyInfo from an ast.CreateMaskingPolicyStmt, the args of
+// the masking function are evaluated and checked against the type of the column.
+func buildMaskingPolicyInfo(ctx sessionctx.Context, tblInfo *model.TableInfo, s *ast.CreateMaskingPolicyStmt) (*model.MaskingPolicyInfo, error) {
+	colInfo := model.FindColumnInfo(tblInfo.Columns, s.Column.Name.L)
+	if colInfo == nil || colInfo.Hidden {
+		return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(s.Column.Name, tblInfo.Name)
+	}
+	policy := &model.MaskingPolicyInfo{
+		Name:     s.PolicyName,
+		ColumnID: colInfo.ID,
+		Type:     s.MaskingType,
+	}
+	for _, arg := range s.Args {
+		v, err := expression.EvalAstExpr(ctx, arg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if v.IsNull() {
+			return nil, dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("the args of %s can't be NULL", policy.Type))
+		}
+		str, err := v.ToString()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		policy.Args = append(policy.Args, str)
+	}
+	if err := checkMaskingPolicyArgs(policy); err != nil {
+		return nil, err
+	}
+	if !policy.SupportColumnType(colInfo.GetType()) {
+		return nil, dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(
+			fmt.Sprintf("%s can't be applied to column '%s' of type %s", policy.Type, colInfo.Name.O, colInfo.FieldType.CompactStr()))
+	}
+	return policy, nil
+}
+
+// checkMaskingPolicyArgs checks the args of the masking function, and fills the default pad of MASK_PARTIAL.
+func checkMaskingPolicyArgs(policy *model.MaskingPolicyInfo) error {
+	switch policy.Type {
+	case model.MaskingFull, model.MaskingHash, model.MaskingNull:
+		if len(policy.Args) != 0 {
+			return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("%s takes no args", policy.Type))
+		}
+	case model.MaskingPartial:
+		if len(policy.Args) != 2 && len(policy.Args) != 3 {
+			return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("%s takes the length of the prefix, the length of the suffix and an optional pad character", policy.Type))
+		}
+		for _, arg := range policy.Args[:2] {
+			if n, err := strconv.ParseUint(arg, 10, 32); err != nil || n > 1024 {
+				return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("the length '%s' of %s should be an integer in [0, 1024]", arg, policy.Type))
+			}
+		}
+		if len(policy.Args) == 2 {
+			policy.Args = append(policy.Args, defaultMaskingPad)
+		}
+		if utf8.RuneCountInString(policy.Args[2]) != 1 {
+			return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("the pad '%s' of %s should be a single character", policy.Args[2], policy.Type))
+		}
+	case model.MaskingDate:
+		if len(policy.Args) != 1 {
+			return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("%s takes a unit of YEAR, MONTH or DAY", policy.Type))
+		}
+		policy.Args[0] = strings.ToUpper(policy.Args[0])
+		switch policy.Args[0] {
+		case "YEAR", "MONTH", "DAY":
+		default:
+			return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("unknown unit '%s' of %s", policy.Args[0], policy.Type))
+		}
+	default:
+		return dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(fmt.Sprintf("unknown masking function %s", policy.Type))
+	}
+	return nil
+}
+
+func onCreateMaskingPolicy(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	policy := &model.MaskingPolicyInfo{}
+	if err := job.DecodeArgs(policy); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// The table may be changed after the policy is checked in the DDL API.
+	if tblInfo.FindMaskingPolicy(policy.Name.L) != nil {
+		job.State = model.JobStateCancelled
+		return ver, infoschema.ErrMaskingPolicyExists.GenWithStackByArgs(policy.Name.O)
+	}
+	colInfo := model.FindColumnInfoByID(tblInfo.Columns, policy.ColumnID)
+	if colInfo == nil || colInfo.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs("the masked column doesn't exist")
+	}
+	if masked := tblInfo.FindMaskingPolicyByColumnID(colInfo.ID); masked != nil {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrInvalidMaskingPolicy.GenWithStackByArgs(
+			fmt.Sprintf("column '%s' is already masked by policy '%s'", colInfo.Name.O, masked.Name.O))
+	}
+
+	tblInfo.MaskingPolicies = append(tblInfo.MaskingPolicies, policy)
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropMaskingPolicy(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var policyName model.CIStr
+	if err := job.DecodeArgs(&policyName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if tblInfo.FindMaskingPolicy(policyName.L) == nil {
+		job.State = model.JobStateCancelled
+		return ver, infoschema.ErrMaskingPolicyNotExists.GenWithStackByArgs(policyName.O)
+	}
+
+	policies := tblInfo.MaskingPolicies[:0]
+	for _, policy := range tblInfo.MaskingPolicies {
+		if policy.Name.L != policyName.L {
+			policies = append(policies, policy)
+		}
+	}
+	tblInfo.MaskingPolicies = policies
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// removeMaskingPolicyOfColumn removes the masking policy bound to the dropped column.
+func removeMaskingPolicyOfColumn(tblInfo *model.TableInfo, colID int64) {
+	if len(tblInfo.MaskingPolicies) == 0 {
+		return
+	}
+	policies := tblInfo.MaskingPolicies[:0]
+	for _, policy := range tblInfo.MaskingPolicies {
+		if policy.ColumnID != colID {
+			policies = append(policies, policy)
+		}
+	}
+	tblInfo.MaskingPolicies = policies
+}
+
+// updateMaskingPolicyWhenModifyColumn binds the masking policy to the new column which replaces the old one.
+func updateMaskingPolicyWhenModifyColumn(tblInfo *model.TableInfo, oldColID, newColID int64) {
+	if policy := tblInfo.FindMaskingPolicyByColumnID(oldColID); policy != nil {
+		policy.ColumnID = newColID
+	}
+}
@@ -0,0 +1,479 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+// BuildMaterializedViewInfo builds a MaterializedViewInfo structure from an ast.CreateMaterializedViewStmt.
+// The table names in the select statement should have been qualified by the schema names.
+func BuildMaterializedViewInfo(s *ast.CreateMaterializedViewStmt) (*model.MaterializedViewInfo, error) {
+	restoreFlag := format.RestoreStringSingleQuotes | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+	var sb strings.Builder
+	if err := s.Select.Restore(format.NewRestoreCtx(restoreFlag, &sb)); err != nil {
+		return nil, err
+	}
+	mvInfo := &model.MaterializedViewInfo{SelectStmt: sb.String(), Cols: s.Cols}
+	if sched := s.RefreshSchedule; sched != nil {
+		if _, err := timerapi.CreateSchedEventPolicy(timerapi.SchedPolicyType(sched.SchedPolicyType), sched.StrValue); err != nil {
+			return nil, err
+		}
+		mvInfo.RefreshSchedPolicyType, mvInfo.RefreshSchedPolicyExpr = sched.SchedPolicyType, sched.StrValue
+	}
+	return mvInfo, nil
+}
+
+// buildMaterializedViewTableInfo builds the table storing the data of the materialized view.
+// The group by columns are the unique key of the table if the view can be refreshed incrementally.
+func buildMaterializedViewTableInfo(ctx sessionctx.Context, s *ast.CreateMaterializedViewStmt, mvInfo *model.MaterializedViewInfo) (*model.TableInfo, error) {
+	if len(s.SchemaCols) != len(s.SchemaColTypes) {
+		return nil, dbterror.ErrViewWrongList
+	}
+	cols := make([]*table.Column, len(s.SchemaCols))
+	for i, name := range s.SchemaCols {
+		ft := s.SchemaColTypes[i].Clone()
+		ft.DelFlag(mysql.PriKeyFlag | mysql.UniqueKeyFlag | mysql.MultipleKeyFlag | mysql.AutoIncrementFlag | mysql.OnUpdateNowFlag)
+		cols[i] = table.ToColumn(&model.ColumnInfo{
+			Name:      name,
+			Offset:    i,
+			State:     model.StatePublic,
+			FieldType: *ft,
+		})
+	}
+
+	var constraints []*ast.Constraint
+	if inc := mvInfo.Incremental; inc != nil {
+		keys := make([]*ast.IndexPartSpecification, 0, len(inc.GroupByCols))
+		for _, groupBy := range inc.GroupByCols {
+			for _, col := range cols {
+				if col.Name.L == groupBy.Col.L {
+					col.AddFlag(mysql.NotNullFlag)
+				}
+			}
+			keys = append(keys, &ast.IndexPartSpecification{
+				Column: &ast.ColumnName{Name: groupBy.Col},
+				Length: types.UnspecifiedLength,
+			})
+		}
+		constraints = append(constraints, &ast.Constraint{Tp: ast.ConstraintUniq, Name: "group_key", Keys: keys})
+	}
+
+	tblCharset, tblCollate := "", ""
+	if v, ok := ctx.GetSessionVars().GetSystemVar(variable.CharacterSetConnection); ok {
+		tblCharset = v
+	}
+	if v, ok := ctx.GetSessionVars().GetSystemVar(variable.CollationConnection); ok {
+		tblCollate = v
+	}
+	tbInfo, err := BuildTableInfo(ctx, s.ViewName.Name, cols, constraints, tblCharset, tblCollate)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkDuplicateColumn(tbInfo.Columns); err != nil {
+		return nil, err
+	}
+	if err = checkTooLongColumns(tbInfo.Columns); err != nil {
+		return nil, err
+	}
+	if err = checkColumnsAttributes(tbInfo.Columns); err != nil {
+		return nil, err
+	}
+	tbInfo.MaterializedView = mvInfo
+	return tbInfo, nil
+}
+
+// buildMaterializedViewLogTableInfo builds the table logging the changes of `cols` of the base table.
+// The log table is in the same schema with the base table.
+func buildMaterializedViewLogTableInfo(ctx sessionctx.Context, baseInfo *model.TableInfo, cols []model.CIStr, viewID int64) (*model.TableInfo, error) {
+	logCols := make([]*table.Column, 0, len(cols)+1)
+	for i, name := range cols {
+		col := model.FindColumnInfo(baseInfo.Columns, name.L).Clone()
+		col.ID = 0
+		col.Offset = i
+		col.DelFlag(mysql.PriKeyFlag | mysql.UniqueKeyFlag | mysql.MultipleKeyFlag | mysql.AutoIncrementFlag | mysql.OnUpdateNowFlag)
+		col.GeneratedExprString = ""
+		col.GeneratedStored = false
+		col.Dependences = nil
+		col.DefaultValue = nil
+		col.OriginDefaultValue = nil
+		col.DefaultIsExpr = false
+		col.Comment = ""
+		logCols = append(logCols, table.ToColumn(col))
+	}
+	signType := types.NewFieldType(mysql.TypeTiny)
+	signType.AddFlag(mysql.NotNullFlag)
+	logCols = append(logCols, table.ToColumn(&model.ColumnInfo{
+		Name:      model.NewCIStr(model.MaterializedViewLogSignCol),
+		Offset:    len(cols),
+		State:     model.StatePublic,
+		FieldType: *signType,
+	}))
+
+	name := model.NewCIStr(fmt.Sprintf("%s%d", model.MaterializedViewLogTablePrefix, viewID))
+	return BuildTableInfo(ctx, name, logCols, nil, baseInfo.Charset, baseInfo.Collate)
+}
+
+// buildMaterializedViewIncrementalInfo checks whether the materialized view can be refreshed incrementally.
+// It returns nil if not, otherwise it returns the base table and its columns which should be logged.
+// The view can be refreshed incrementally if it's a SUM/COUNT aggregation of a single table grouped
+// by the NOT NULL columns and COUNT(*) is in the select list, for example:
+//
+//	SELECT a, b, SUM(c), COUNT(d), COUNT(*) FROM t WHERE e > 0 GROUP BY a, b
+func buildMaterializedViewIncrementalInfo(s *ast.CreateMaterializedViewStmt) (*model.MaterializedViewIncrementalInfo, *ast.TableName, []model.CIStr) {
+	sel, ok := s.Select.(*ast.SelectStmt)
+	if !ok || sel.Distinct || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil || sel.With != nil ||
+		sel.LockInfo != nil || sel.WindowSpecs != nil || sel.GroupBy == nil || sel.GroupBy.Rollup ||
+		sel.From == nil || sel.From.TableRefs.Right != nil || len(s.SchemaCols) != len(sel.Fields.Fields) {
+		return nil, nil, nil
+	}
+	ts, ok := sel.From.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil, nil, nil
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok || tn.TableInfo == nil || tn.DBInfo == nil || !tn.TableInfo.IsBaseTable() ||
+		tn.TableInfo.TempTableType != model.TempTableNone || tn.TableInfo.MaterializedView != nil ||
+		strings.HasPrefix(tn.Name.L, model.MaterializedViewLogTablePrefix) {
+		return nil, nil, nil
+	}
+	baseInfo := tn.TableInfo
+
+	referred := make(map[string]struct{})
+	baseColumn := func(expr ast.ExprNode) *model.ColumnInfo {
+		colExpr, ok := expr.(*ast.ColumnNameExpr)
+		if !ok {
+			return nil
+		}
+		col := model.FindColumnInfo(baseInfo.Columns, colExpr.Name.Name.L)
+		if col == nil || col.State != model.StatePublic || col.Hidden {
+			return nil
+		}
+		referred[col.Name.L] = struct{}{}
+		return col
+	}
+
+	inc := &model.MaterializedViewIncrementalInfo{
+		BaseSchemaID: tn.DBInfo.ID,
+		BaseTableID:  baseInfo.ID,
+	}
+	groupBy := make(map[string]struct{}, len(sel.GroupBy.Items))
+	for _, item := range sel.GroupBy.Items {
+		col := baseColumn(item.Expr)
+		if col == nil || !mysql.HasNotNullFlag(col.GetFlag()) || types.IsTypeBlob(col.GetType()) || col.GetType() == mysql.TypeJSON {
+			return nil, nil, nil
+		}
+		groupBy[col.Name.L] = struct{}{}
+	}
+	for i, field := range sel.Fields.Fields {
+		if field.WildCard != nil {
+			return nil, nil, nil
+		}
+		viewCol := s.SchemaCols[i]
+		switch x := field.Expr.(type) {
+		case *ast.ColumnNameExpr:
+			col := baseColumn(x)
+			if col == nil {
+				return nil, nil, nil
+			}
+			if _, ok := groupBy[col.Name.L]; !ok {
+				return nil, nil, nil
+			}
+			delete(groupBy, col.Name.L)
+			inc.GroupByCols = append(inc.GroupByCols, model.MaterializedViewGroupByCol{Arg: col.Name, Col: viewCol})
+		case *ast.AggregateFuncExpr:
+			if x.Distinct || len(x.Args) != 1 || x.Order != nil {
+				return nil, nil, nil
+			}
+			agg := model.MaterializedViewAgg{Name: strings.ToLower(x.F), Col: viewCol}
+			switch agg.Name {
+			case ast.AggFuncSum:
+				col := baseColumn(x.Args[0])
+				if col == nil || !mysql.HasNotNullFlag(col.GetFlag()) || !types.IsTypeNumeric(col.GetType()) {
+					return nil, nil, nil
+				}
+				agg.Arg = col.Name
+			case ast.AggFuncCount:
+				if v, ok := x.Args[0].(ast.ValueExpr); ok {
+					// COUNT(*) is parsed as COUNT(1).
+					if v.GetValue() == nil {
+						return nil, nil, nil
+					}
+					if inc.CountCol.L == "" {
+						inc.CountCol = viewCol
+					}
+				} else {
+					col := baseColumn(x.Args[0])
+					if col == nil {
+						return nil, nil, nil
+					}
+					agg.Arg = col.Name
+				}
+			default:
+				return nil, nil, nil
+			}
+			inc.Aggs = append(inc.Aggs, agg)
+		default:
+			return nil, nil, nil
+		}
+	}
+	// All the group by columns should be in the view, so they can be the unique key of it.
+	if len(groupBy) > 0 || inc.CountCol.L == "" {
+		return nil, nil, nil
+	}
+
+	if sel.Where != nil {
+		checker := &incrementalWhereChecker{baseColumn: baseColumn}
+		sel.Where.Accept(checker)
+		if !checker.ok() {
+			return nil, nil, nil
+		}
+		// The filter is applied to the log table later, so the column names are restored without qualifiers.
+		for _, name := range checker.names {
+			name.Schema, name.Table = model.CIStr{}, model.CIStr{}
+		}
+		var sb strings.Builder
+		err := sel.Where.Restore(format.NewRestoreCtx(format.RestoreStringSingleQuotes|format.RestoreKeyWordUppercase|format.RestoreNameBackQuotes, &sb))
+		for i, name := range checker.names {
+			name.Schema, name.Table = checker.qualifiers[i][0], checker.qualifiers[i][1]
+		}
+		if err != nil {
+			return nil, nil, nil
+		}
+		inc.Where = sb.String()
+	}
+
+	logCols := make([]model.CIStr, 0, len(referred))
+	for _, col := range baseInfo.Columns {
+		if _, ok := referred[col.Name.L]; ok {
+			logCols = append(logCols, col.Name)
+		}
+	}
+	return inc, tn, logCols
+}
+
+// incrementalWhereChecker checks the filter of a materialized view which is refreshed incrementally.
+// Only the deterministic expressions on the columns of the base table are allowed.
+type incrementalWhereChecker struct {
+	baseColumn func(ast.ExprNode) *model.ColumnInfo
+	names      []*ast.ColumnName
+	qualifiers [][2]model.CIStr
+	invalid    bool
+}
+
+func (c *incrementalWhereChecker) ok() bool {
+	return !c.invalid
+}
+
+// Enter implements ast.Visitor interface.
+func (c *incrementalWhereChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.ColumnNameExpr:
+		if c.baseColumn(x) == nil {
+			c.invalid = true
+		}
+		c.names = append(c.names, x.Name)
+		c.qualifiers = append(c.qualifiers, [2]model.CIStr{x.Name.Schema, x.Name.Table})
+		return in, true
+	case *ast.BinaryOperationExpr, *ast.UnaryOperationExpr, *ast.ParenthesesExpr, *ast.BetweenExpr,
+		*ast.IsNullExpr, *ast.IsTruthExpr, *ast.PatternInExpr, *ast.PatternLikeOrIlikeExpr, ast.ValueExpr:
+		return in, c.invalid
+	default:
+		c.invalid = true
+		return in, true
+	}
+}
+
+// Leave implements ast.Visitor interface.
+func (*incrementalWhereChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// checkMaterializedViewTableDDL checks the DDL `op` doesn't break the materialized views. The tables storing
+// the views, the base tables having change logs and the log tables can't be dropped or truncated directly.
+func checkMaterializedViewTableDDL(tblInfo *model.TableInfo, op string) error {
+	switch {
+	case tblInfo.MaterializedView != nil:
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(op + " on materialized view")
+	case len(tblInfo.MaterializedViewLogs) > 0:
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(op + " on the base table of materialized views")
+	case strings.HasPrefix(tblInfo.Name.L, model.MaterializedViewLogTablePrefix):
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(op + " on the log table of materialized view")
+	}
+	return nil
+}
+
+func onCreateMaterializedView(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	schemaID := job.SchemaID
+	tbInfo := &model.TableInfo{}
+	var logInfo *model.TableInfo
+	if err := job.DecodeArgs(tbInfo, &logInfo); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if err := checkTableNotExists(d, t, schemaID, tbInfo.Name.L); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	affects := []*model.AffectedOption{}
+	if inc := tbInfo.MaterializedView.Incremental; inc != nil && logInfo != nil {
+		baseInfo, err := getTableInfo(t, inc.BaseTableID, inc.BaseSchemaID)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if err = checkMaterializedViewLogCols(baseInfo, logInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		logInfo.State = model.StatePublic
+		logInfo.UpdateTS = t.StartTS
+		if err = createTableOrViewWithCheck(t, job, inc.BaseSchemaID, logInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		cols := make([]model.CIStr, 0, len(logInfo.Columns)-1)
+		for _, col := range logInfo.Columns[:len(logInfo.Columns)-1] {
+			cols = append(cols, col.Name)
+		}
+		baseInfo.MaterializedViewLogs = append(baseInfo.MaterializedViewLogs, &model.MaterializedViewLogInfo{
+			ViewID:  tbInfo.ID,
+			TableID: logInfo.ID,
+			Cols:    cols,
+		})
+		baseInfo.UpdateTS = t.StartTS
+		if err = t.UpdateTable(inc.BaseSchemaID, baseInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+		affects = append(affects,
+			&model.AffectedOption{SchemaID: inc.BaseSchemaID, TableID: logInfo.ID},
+			&model.AffectedOption{SchemaID: inc.BaseSchemaID, TableID: baseInfo.ID, OldTableID: baseInfo.ID},
+		)
+	}
+
+	tbInfo.State = model.StatePublic
+	tbInfo.UpdateTS = t.StartTS
+	if err := createTableOrViewWithCheck(t, job, schemaID, tbInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	job.CtxVars = []interface{}{affects}
+	ver, err := updateSchemaVersion(d, t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tbInfo)
+	return ver, nil
+}
+
+// checkMaterializedViewLogCols checks the logged columns are still in the base table.
+func checkMaterializedViewLogCols(baseInfo, logInfo *model.TableInfo) error {
+	for _, col := range logInfo.Columns[:len(logInfo.Columns)-1] {
+		baseCol := model.FindColumnInfo(baseInfo.Columns, col.Name.L)
+		if baseCol == nil || baseCol.State != model.StatePublic {
+			return infoschema.ErrColumnNotExists.GenWithStackByArgs(col.Name, baseInfo.Name)
+		}
+	}
+	return nil
+}
+
+func onDropMaterializedView(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if tblInfo.MaterializedView == nil {
+		job.State = model.JobStateCancelled
+		return ver, dbterror.ErrWrongObject.GenWithStackByArgs(job.SchemaName, tblInfo.Name, "MATERIALIZED VIEW")
+	}
+
+	droppedIDs := []int64{tblInfo.ID}
+	affects := []*model.AffectedOption{}
+	if inc := tblInfo.MaterializedView.Incremental; inc != nil {
+		baseInfo, err := getTableInfo(t, inc.BaseTableID, inc.BaseSchemaID)
+		switch {
+		case err == nil:
+			if err = dropMaterializedViewLog(t, baseInfo, inc, tblInfo.ID); err != nil {
+				return ver, errors.Trace(err)
+			}
+			droppedIDs = append(droppedIDs, inc.LogTableID)
+			affects = append(affects,
+				&model.AffectedOption{SchemaID: inc.BaseSchemaID, OldTableID: inc.LogTableID},
+				&model.AffectedOption{SchemaID: inc.BaseSchemaID, TableID: baseInfo.ID, OldTableID: baseInfo.ID},
+			)
+		case infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err):
+			// The base table and the log table have been dropped with the schema.
+		default:
+			return ver, errors.Trace(err)
+		}
+	}
+
+	if err = t.DropTableOrView(job.SchemaID, job.TableID); err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = t.GetAutoIDAccessors(job.SchemaID, job.TableID).Del(); err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.CtxVars = []interface{}{affects}
+	ver, err = updateSchemaVersion(d, t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo.State = model.StateNone
+	// Finish this job, the data of the view and the log table are deleted by the delete range manager.
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	job.Args = append(job.Args, droppedIDs)
+	return ver, nil
+}
+
+// dropMaterializedViewLog drops the log table of the materialized view and removes it from the base table.
+func dropMaterializedViewLog(t *meta.Meta, baseInfo *model.TableInfo, inc *model.MaterializedViewIncrementalInfo, viewID int64) error {
+	logs := baseInfo.MaterializedViewLogs[:0]
+	for _, log := range baseInfo.MaterializedViewLogs {
+		if log.ViewID != viewID {
+			logs = append(logs, log)
+		}
+	}
+	if len(logs) == 0 {
+		logs = nil
+	}
+	baseInfo.MaterializedViewLogs = logs
+	baseInfo.UpdateTS = t.StartTS
+	if err := t.UpdateTable(inc.BaseSchemaID, baseInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if err := t.DropTableOrView(inc.BaseSchemaID, inc.LogTableID); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(t.GetAutoIDAccessors(inc.BaseSchemaID, inc.LogTableID).Del())
+}
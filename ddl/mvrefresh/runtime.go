@@ -0,0 +1,159 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvrefresh
+
+import (
+	"context"
+	"time"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/pingcap/tidb/timer/periodic"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/tikv/client-go/v2/util"
+	"go.uber.org/zap"
+)
+
+type sessionPool interface {
+	Get() (pools.Resource, error)
+	Put(pools.Resource)
+}
+
+// Refresher refreshes the materialized views whose refresh schedules are due at `now`.
+// The error of a single view does not stop refreshing the others.
+type Refresher func(ctx context.Context, now time.Time) error
+
+var timerConfig = periodic.TimerConfig{
+	Name:      "materialized_view_refresh",
+	HookClass: HookClass,
+	Key:       TimerKey,
+	Interval:  TimerInterval,
+}
+
+// NewRuntime creates a new Runtime which refreshes the materialized views with refresh schedules
+// periodically by the timer in `store`.
+func NewRuntime(store *timerapi.TimerStore, pool sessionPool, getIS func() infoschema.InfoSchema) *periodic.Runtime {
+	refresh := NewSessionPoolRefresher(pool, getIS)
+	// The views which have been refreshed are not due any more, so nothing is stored in the summary.
+	return periodic.NewRuntime(timerConfig, store, func(ctx context.Context, now time.Time, _ []byte) ([]byte, error) {
+		return nil, refresh(ctx, now)
+	})
+}
+
+// NewSessionPoolRefresher returns a Refresher which refreshes the views with the sessions in `pool`.
+// The views to refresh are got from the info schema returned by `getIS`, a view is refreshed if the next
+// time of its refresh schedule after the last refresh is not after `now`.
+func NewSessionPoolRefresher(pool sessionPool, getIS func() infoschema.InfoSchema) Refresher {
+	return func(ctx context.Context, now time.Time) error {
+		r, err := pool.Get()
+		if err != nil {
+			return err
+		}
+
+		exec, ok := r.(sqlexec.SQLExecutor)
+		if !ok {
+			pool.Put(r)
+			return errors.New("session is not the type of SQLExecutor")
+		}
+
+		defer func() {
+			if _, err := exec.ExecuteInternal(util.WithInternalSourceType(context.Background(), kv.InternalTimer), "ROLLBACK"); err != nil {
+				terror.Log(err)
+				return
+			}
+			pool.Put(r)
+		}()
+
+		ctx = util.WithInternalSourceType(ctx, kv.InternalTimer)
+		lastRefresh, err := getLastRefreshTimes(ctx, exec)
+		if err != nil {
+			return err
+		}
+
+		is := getIS()
+		for _, db := range is.AllSchemas() {
+			for _, tbl := range is.SchemaTables(db.Name) {
+				tblInfo := tbl.Meta()
+				if tblInfo.MaterializedView == nil {
+					continue
+				}
+				// The view is refreshed when it's created, if it fails, the last refresh time is zero and
+				// the view is refreshed now.
+				last := lastRefresh[tblInfo.ID]
+				delete(lastRefresh, tblInfo.ID)
+				if err = ctx.Err(); err != nil {
+					return err
+				}
+				refreshView(ctx, exec, db.Name, tblInfo, last, now)
+			}
+		}
+
+		// The records of the dropped views are removed.
+		for viewID := range lastRefresh {
+			if _, err = exec.ExecuteInternal(ctx, "DELETE FROM mysql.tidb_materialized_view_refresh WHERE view_id = %?", viewID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// getLastRefreshTimes returns the last refresh time of the views by their ids.
+func getLastRefreshTimes(ctx context.Context, exec sqlexec.SQLExecutor) (map[int64]time.Time, error) {
+	rs, err := exec.ExecuteInternal(ctx, "SELECT view_id, CAST(UNIX_TIMESTAMP(last_refresh_time) * 1000000 AS SIGNED) FROM mysql.tidb_materialized_view_refresh")
+	if err != nil {
+		return nil, err
+	}
+	defer terror.Call(rs.Close)
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		times[row.GetInt64(0)] = time.UnixMicro(row.GetInt64(1))
+	}
+	return times, nil
+}
+
+// refreshView refreshes the view if its refresh schedule is due at `now`.
+func refreshView(ctx context.Context, exec sqlexec.SQLExecutor, schema model.CIStr, tblInfo *model.TableInfo, last, now time.Time) {
+	mvInfo := tblInfo.MaterializedView
+	if mvInfo.RefreshSchedPolicyType == "" {
+		return
+	}
+
+	logger := logutil.BgLogger().With(zap.String("schema", schema.O), zap.String("view", tblInfo.Name.O))
+	policy, err := timerapi.CreateSchedEventPolicy(timerapi.SchedPolicyType(mvInfo.RefreshSchedPolicyType), mvInfo.RefreshSchedPolicyExpr)
+	if err != nil {
+		logger.Warn("invalid refresh schedule of materialized view", zap.Error(err))
+		return
+	}
+	if next, ok := policy.NextEventTime(last); !ok || next.After(now) {
+		return
+	}
+
+	logger.Info("refresh materialized view by schedule", zap.Time("lastRefresh", last))
+	if _, err = exec.ExecuteInternal(ctx, "REFRESH MATERIALIZED VIEW %n.%n", schema.O, tblInfo.Name.O); err != nil {
+		logger.Warn("failed to refresh materialized view", zap.Error(err))
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvrefresh_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/ddl/mvrefresh"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshMaterializedViews(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int not null, b int not null)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustExec("create materialized view mv1 refresh schedule interval '1h' as select a, sum(b), count(*) from t group by a")
+	tk.MustExec("create materialized view mv2 as select a, max(b) from t group by a")
+	tk.MustExec("create materialized view mv3 refresh schedule cron '0 0 * * *' as select a, max(b) from t group by a")
+	tk.MustContainErrMsg("create materialized view mv4 refresh schedule interval 'x' as select a from t", "invalid schedule event expr 'x'")
+	tk.MustQuery("select count(*) from mysql.tidb_materialized_view_refresh").Check(testkit.Rows("3"))
+	// the record of a dropped view is removed
+	tk.MustExec("insert into mysql.tidb_materialized_view_refresh values (1000000, 'COMPLETE', now())")
+
+	refresh := mvrefresh.NewSessionPoolRefresher(dom.SysSessionPool(), dom.InfoSchema)
+	tk.MustExec("insert into t values (1, 10)")
+	require.NoError(t, refresh(context.Background(), time.Now()))
+	tk.MustQuery("select * from mv1 order by a").Check(testkit.Rows("1 1 1", "2 2 1"))
+	tk.MustQuery("select count(*) from mysql.tidb_materialized_view_refresh").Check(testkit.Rows("3"))
+
+	// only the views whose schedules are due are refreshed
+	require.NoError(t, refresh(context.Background(), time.Now().Add(time.Hour)))
+	tk.MustQuery("select * from mv1 order by a").Check(testkit.Rows("1 11 2", "2 2 1"))
+	tk.MustQuery("select * from mv2 order by a").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustQuery("select * from mv3 order by a").Check(testkit.Rows("1 1", "2 2"))
+	require.NoError(t, refresh(context.Background(), time.Now().Add(25*time.Hour)))
+	tk.MustQuery("select * from mv3 order by a").Check(testkit.Rows("1 10", "2 2"))
+	tk.MustQuery("select count(*) from mysql.tidb_materialized_view_refresh where last_refresh_time > now() - interval 1 minute").Check(testkit.Rows("3"))
+
+	tk.MustExec("drop materialized view mv1")
+	require.NoError(t, refresh(context.Background(), time.Now()))
+	tk.MustQuery("select count(*) from mysql.tidb_materialized_view_refresh").Check(testkit.Rows("2"))
+}
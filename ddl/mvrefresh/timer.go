@@ -0,0 +1,25 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvrefresh
+
+const (
+	// HookClass is the hook class of the timer refreshing the materialized views.
+	HookClass = "tidb.materialized_view_refresh"
+	// TimerKey is the key of the timer refreshing the materialized views.
+	TimerKey = "/tidb/materialized_view/refresh"
+	// TimerInterval is the interval to check whether the materialized views should be refreshed.
+	// The refresh schedules of the views are accurate to this interval.
+	TimerInterval = "1m"
+)
@@ -554,7 +554,7 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 
 	for _, index := range tbInfo.Indices {
 		if index.Unique && !checkUniqueKeyIncludePartKey(partCols, index.Columns) {
-			index.Global = config.GetGlobalConfig().EnableGlobalIndex
+			index.Global = index.Global || config.GetGlobalConfig().EnableGlobalIndex
 		}
 	}
 	return nil
@@ -578,7 +578,8 @@ func getPartitionColSlices(sctx sessionctx.Context, tblInfo *model.TableInfo, s
 }
 
 // getPartitionIntervalFromTable checks if a partitioned table matches a generated INTERVAL partitioned scheme
-// will return nil if error occurs, i.e. not an INTERVAL partitioned table
+// will return nil if error occurs, i.e. not an INTERVAL partitioned table.
+// If the table has an interval partition policy, its interval is used instead of calculating it.
 func getPartitionIntervalFromTable(ctx sessionctx.Context, tbInfo *model.TableInfo) *ast.PartitionInterval {
 	if tbInfo.Partition == nil ||
 		tbInfo.Partition.Type != model.PartitionTypeRange {
@@ -588,7 +589,8 @@ func getPartitionIntervalFromTable(ctx sessionctx.Context, tbInfo *model.TableIn
 		// Multi-column RANGE COLUMNS is not supported with INTERVAL
 		return nil
 	}
-	if len(tbInfo.Partition.Definitions) < 2 {
+	policy := tbInfo.IntervalPartitionInfo
+	if len(tbInfo.Partition.Definitions) < 2 && policy == nil {
 		// Must have at least two partitions to calculate an INTERVAL
 		return nil
 	}
@@ -623,6 +625,9 @@ func getPartitionIntervalFromTable(ctx sessionctx.Context, tbInfo *model.TableIn
 	if strings.EqualFold(firstPartLessThan, minVal) {
 		interval.NullPart = true
 		startIdx++
+		if startIdx > endIdx {
+			return nil
+		}
 		firstPartLessThan = driver.UnwrapFromSingleQuotes(tbInfo.Partition.Definitions[startIdx].LessThan[0])
 	}
 	// flag if MAXVALUE partition
@@ -630,10 +635,13 @@ func getPartitionIntervalFromTable(ctx sessionctx.Context, tbInfo *model.TableIn
 	if strings.EqualFold(lastPartLessThan, partitionMaxValue) {
 		interval.MaxValPart = true
 		endIdx--
+		if startIdx > endIdx {
+			return nil
+		}
 		lastPartLessThan = driver.UnwrapFromSingleQuotes(tbInfo.Partition.Definitions[endIdx].LessThan[0])
 	}
 	// Guess the interval
-	if startIdx >= endIdx {
+	if startIdx == endIdx && (policy == nil || isIntType) {
 		// Must have at least two partitions to calculate an INTERVAL
 		return nil
 	}
@@ -661,6 +669,17 @@ func getPartitionIntervalFromTable(ctx sessionctx.Context, tbInfo *model.TableIn
 			return nil
 		}
 		interval.LastRangeEnd = &lastExpr
+	} else if policy != nil {
+		val, err := strconv.ParseInt(policy.IntervalExprStr, 10, 64)
+		if err != nil {
+			return nil
+		}
+		interval.IntervalExpr.Expr = ast.NewValueExpr(val, "", "")
+		interval.IntervalExpr.TimeUnit = ast.TimeUnitType(policy.IntervalTimeUnit)
+		firstExpr = ast.NewValueExpr(firstPartLessThan, "", "")
+		lastExpr = ast.NewValueExpr(lastPartLessThan, "", "")
+		interval.FirstRangeEnd = &firstExpr
+		interval.LastRangeEnd = &lastExpr
 	} else { // types.ETDatetime
 		exprStr := fmt.Sprintf("TIMESTAMPDIFF(SECOND, '%s', '%s')", firstPartLessThan, lastPartLessThan)
 		exprs, err := expression.ParseSimpleExprsWithNames(ctx, exprStr, nil, nil)
@@ -1744,6 +1763,147 @@ func getPartitionIDsFromDefinitions(defs []model.PartitionDefinition) []int64 {
 	return pids
 }
 
+// getPartitionInfoTypeNone returns the partition info of a non-partitioned table during
+// ALTER TABLE ... PARTITION BY, where the whole table is a single partition with the table ID.
+func getPartitionInfoTypeNone(tblInfo *model.TableInfo) *model.PartitionInfo {
+	return &model.PartitionInfo{
+		Type:   model.PartitionTypeNone,
+		Enable: true,
+		Definitions: []model.PartitionDefinition{{
+			ID:      tblInfo.ID,
+			Name:    model.NewCIStr("pFullTable"),
+			Comment: "Intermediate partition during ALTER TABLE ... PARTITION BY ...",
+		}},
+		Num: 1,
+	}
+}
+
+// addReorgChangedIndexes adds the indexes replacing the ones which cannot be kept when reorganizing partitions.
+// The entries of a global index contain the partition IDs, so it is rebuilt with the new partitions.
+// REMOVE PARTITIONING changes the global indexes into local ones, and ALTER TABLE ... PARTITION BY
+// changes the unique indexes not including all the new partitioning columns into global ones.
+func addReorgChangedIndexes(job *model.Job, tblInfo *model.TableInfo, partInfo *model.PartitionInfo) error {
+	pi := tblInfo.Partition
+	var newIndexes []*model.IndexInfo
+	for _, idxInfo := range tblInfo.Indices {
+		global := idxInfo.Global
+		switch job.Type {
+		case model.ActionRemovePartitioning:
+			global = false
+		case model.ActionAlterTablePartitioning:
+			if idxInfo.Unique && !global {
+				ok, err := checkPartitionKeysConstraint(partInfo, idxInfo.Columns, tblInfo)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				global = !ok
+			}
+		}
+		if !idxInfo.Global && !global {
+			continue
+		}
+		newIdxInfo := idxInfo.Clone()
+		newIdxInfo.ID = AllocateIndexID(tblInfo)
+		newIdxInfo.Name = model.NewCIStr(genChangingIndexUniqueName(tblInfo, idxInfo))
+		newIdxInfo.State = model.StateDeleteOnly
+		newIdxInfo.Global = global
+		newIndexes = append(newIndexes, newIdxInfo)
+		if pi.DDLChangedIndex == nil {
+			pi.DDLChangedIndex = make(map[int64]bool)
+		}
+		pi.DDLChangedIndex[idxInfo.ID] = false
+		pi.DDLChangedIndex[newIdxInfo.ID] = true
+	}
+	tblInfo.Indices = append(tblInfo.Indices, newIndexes...)
+	return nil
+}
+
+// getReorgIndexPartitionDefs returns the partitions to create the index on when reorganizing partitions.
+// A new global index is also created on the partitions which are not reorganized.
+func getReorgIndexPartitionDefs(pi *model.PartitionInfo, idxID int64) []model.PartitionDefinition {
+	if !pi.DDLChangedIndex[idxID] {
+		return pi.AddingDefinitions
+	}
+	defs := make([]model.PartitionDefinition, 0, len(pi.AddingDefinitions)+len(pi.Definitions))
+	defs = append(defs, pi.AddingDefinitions...)
+	for _, def := range pi.Definitions {
+		if !isDroppingPartition(pi, def.ID) {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+func isDroppingPartition(pi *model.PartitionInfo, pid int64) bool {
+	for _, def := range pi.DroppingDefinitions {
+		if def.ID == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// setReorgChangedIndexesState sets the state of the new indexes added by addReorgChangedIndexes.
+func setReorgChangedIndexesState(tblInfo *model.TableInfo, state model.SchemaState) {
+	for _, idxInfo := range tblInfo.Indices {
+		if tblInfo.Partition.DDLChangedIndex[idxInfo.ID] {
+			idxInfo.State = state
+		}
+	}
+}
+
+// replaceReorgChangedIndexes replaces the old indexes with the new ones in the end of reorganizing partitions,
+// and returns the IDs of the old indexes.
+func replaceReorgChangedIndexes(tblInfo *model.TableInfo) []int64 {
+	pi := tblInfo.Partition
+	var oldIDs []int64
+	newIdxByName := make(map[string]*model.IndexInfo)
+	for _, idxInfo := range tblInfo.Indices {
+		if pi.DDLChangedIndex[idxInfo.ID] {
+			newIdxByName[strings.ToLower(getChangingIndexOriginName(idxInfo))] = idxInfo
+		}
+	}
+	indices := make([]*model.IndexInfo, 0, len(tblInfo.Indices)-len(newIdxByName))
+	for _, idxInfo := range tblInfo.Indices {
+		isNew, changed := pi.DDLChangedIndex[idxInfo.ID]
+		switch {
+		case !changed:
+			indices = append(indices, idxInfo)
+		case !isNew:
+			// Keep the position of the old index.
+			oldIDs = append(oldIDs, idxInfo.ID)
+			newIdxInfo := newIdxByName[idxInfo.Name.L]
+			newIdxInfo.Name = idxInfo.Name
+			newIdxInfo.State = model.StatePublic
+			indices = append(indices, newIdxInfo)
+		}
+	}
+	tblInfo.Indices = indices
+	return oldIDs
+}
+
+// removeReorgChangedIndexes removes the new indexes when rolling back reorganizing partitions,
+// and returns their IDs.
+func removeReorgChangedIndexes(tblInfo *model.TableInfo) []int64 {
+	pi := tblInfo.Partition
+	var newIDs []int64
+	indices := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+	for _, idxInfo := range tblInfo.Indices {
+		isNew, changed := pi.DDLChangedIndex[idxInfo.ID]
+		if changed && isNew {
+			newIDs = append(newIDs, idxInfo.ID)
+			continue
+		}
+		if changed {
+			idxInfo.State = model.StatePublic
+		}
+		indices = append(indices, idxInfo)
+	}
+	tblInfo.Indices = indices
+	pi.DDLChangedIndex = nil
+	return newIDs
+}
+
 func hasGlobalIndex(tblInfo *model.TableInfo) bool {
 	for _, idxInfo := range tblInfo.Indices {
 		if idxInfo.Global {
@@ -1816,11 +1976,27 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
-	if job.Type == model.ActionAddTablePartition || job.Type == model.ActionReorganizePartition {
+	if job.Type == model.ActionAddTablePartition || job.Type == model.ActionReorganizePartition ||
+		job.Type == model.ActionAlterTablePartitioning || job.Type == model.ActionRemovePartitioning {
 		// It is rollback from reorganize partition, just remove DroppingDefinitions from tableInfo
 		tblInfo.Partition.DroppingDefinitions = nil
 		// It is rollback from adding table partition, just remove addingDefinitions from tableInfo.
 		physicalTableIDs, pNames, rollbackBundles := rollbackAddingPartitionInfo(tblInfo)
+		// The new global indexes are keyed by the new table ID for ALTER TABLE ... PARTITION BY,
+		// and by the table ID for REORGANIZE PARTITION.
+		var globalIndexIDs []int64
+		if newIndexIDs := removeReorgChangedIndexes(tblInfo); len(newIndexIDs) > 0 {
+			if job.Type == model.ActionReorganizePartition {
+				globalIndexIDs = newIndexIDs
+			} else if job.Type == model.ActionAlterTablePartitioning {
+				physicalTableIDs = append(physicalTableIDs, tblInfo.Partition.NewTableID)
+			}
+		}
+		tblInfo.Partition.ClearReorgIntermediateInfo()
+		if tblInfo.Partition.Type == model.PartitionTypeNone {
+			// It is rollback from ALTER TABLE ... PARTITION BY of a non-partitioned table.
+			tblInfo.Partition = nil
+		}
 		err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), rollbackBundles)
 		if err != nil {
 			job.State = model.JobStateCancelled
@@ -1842,7 +2018,7 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 			return ver, errors.Trace(err)
 		}
 		job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
-		job.Args = []interface{}{physicalTableIDs}
+		job.Args = []interface{}{physicalTableIDs, nil, globalIndexIDs}
 		return ver, nil
 	}
 
@@ -2294,6 +2470,10 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
+	if job.SchemaState == model.StateDeleteReorganization {
+		// The IDs have been exchanged, rebuild the global indexes.
+		return w.onExchangeTablePartitionGlobalIndexes(d, t, job, defID, ptSchemaID, ptID)
+	}
 
 	ntDbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
 	if err != nil {
@@ -2335,25 +2515,65 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
-	if nt.ExchangePartitionInfo == nil || !nt.ExchangePartitionInfo.ExchangePartitionFlag {
+	// The global indexes contain the partition IDs, so they are rebuilt for the exchanged partition.
+	// Neither the table nor the partition can be written until the exchange finishes.
+	globalIndex := hasGlobalIndex(pt)
+	if nt.ExchangePartitionInfo == nil || !nt.ExchangePartitionInfo.ExchangePartitionFlag ||
+		(globalIndex && job.SchemaState == model.StateNone) {
 		nt.ExchangePartitionInfo = &model.ExchangePartitionInfo{
 			ExchangePartitionFlag:  true,
 			ExchangePartitionID:    ptID,
 			ExchangePartitionDefID: defID,
+			ReadOnly:               globalIndex,
+		}
+		if globalIndex {
+			pt.Partition.DDLExchangeTableID = nt.ID
+			pt.Partition.DDLExchangePartitionID = defID
+			if err = t.UpdateTable(ptSchemaID, pt); err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(err)
+			}
+			job.SchemaState = model.StateWriteOnly
 		}
 		return updateVersionAndTableInfoWithCheck(d, t, job, nt, true)
 	}
 
-	if d.lease > 0 {
-		delayForAsyncCommit()
-	}
-
-	if withValidation {
-		err = checkExchangePartitionRecordValidation(w, pt, index, ntDbInfo.Name, nt.Name)
+	switch {
+	case globalIndex && job.SchemaState == model.StateWriteOnly:
+		if d.lease > 0 {
+			delayForAsyncCommit()
+		}
+		if withValidation {
+			err = checkExchangePartitionRecordValidation(w, pt, index, ntDbInfo.Name, nt.Name)
+		}
+		if err == nil {
+			err = checkExchangePartitionGlobalIndexes(w, t, pt, ptSchemaID, partName, ntDbInfo.Name, nt.Name)
+		}
 		if err != nil {
-			job.State = model.JobStateCancelled
+			return rollbackExchangeTablePartition(d, t, job, nt, pt, ptSchemaID, err)
+		}
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+		return ver, nil
+	case globalIndex:
+		// The indexes of the table are global indexes in the partitioned table, add their entries
+		// for the rows of the partition, which are kept by the table after exchanging the IDs.
+		done, err := w.addExchangeTableIndexes(d, job, ntDbInfo, nt, pt, defID)
+		if err != nil || !done {
 			return ver, errors.Trace(err)
 		}
+	default:
+		if d.lease > 0 {
+			delayForAsyncCommit()
+		}
+
+		if withValidation {
+			err = checkExchangePartitionRecordValidation(w, pt, index, ntDbInfo.Name, nt.Name)
+			if err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(err)
+			}
+		}
 	}
 
 	// partition table auto IDs.
@@ -2384,6 +2604,18 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 		}
 	}
 
+	if globalIndex {
+		// The entries of the rows exchanged out of the partition are cleaned up like dropping the partition,
+		// and the global indexes are not used until the entries of the exchanged rows are added.
+		pt.Partition.DroppingDefinitions = []model.PartitionDefinition{partDef.Clone()}
+		pt.Partition.DDLExchangePartitionID = nt.ID
+		for _, idxInfo := range pt.Indices {
+			if idxInfo.Global {
+				idxInfo.State = model.StateWriteOnly
+			}
+		}
+	}
+
 	// exchange table meta id
 	partDef.ID, nt.ID = nt.ID, partDef.ID
 
@@ -2502,15 +2734,218 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 	}
 
 	nt.ExchangePartitionInfo = nil
+	if globalIndex {
+		nt.ExchangePartitionInfo = &model.ExchangePartitionInfo{ReadOnly: true}
+	}
 	ver, err = updateVersionAndTableInfoWithCheck(d, t, job, nt, true)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
 
+	if globalIndex {
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateDeleteReorganization
+		return ver, nil
+	}
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, pt)
+	return ver, nil
+}
+
+// onExchangeTablePartitionGlobalIndexes cleans up the global index entries of the rows exchanged out of
+// the partition and adds the ones of the rows exchanged into the partition, after the IDs are exchanged.
+func (w *worker) onExchangeTablePartitionGlobalIndexes(d *ddlCtx, t *meta.Meta, job *model.Job, defID, ptSchemaID, ptID int64) (ver int64, _ error) {
+	// The table has the original ID of the partition now.
+	nt, err := getTableInfo(t, defID, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pt, err := getTableInfo(t, ptID, ptSchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	ptDbInfo, err := t.GetDatabase(ptSchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	oldTbl, err := getTable(d.store, ptSchemaID, getTableInfoWithDroppingPartitions(pt))
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Only the exchanged partition is backfilled.
+	newTblInfo := pt.Clone()
+	newTblInfo.Partition.Definitions = nil
+	for _, def := range pt.Partition.Definitions {
+		if def.ID == job.TableID {
+			newTblInfo.Partition.Definitions = append(newTblInfo.Partition.Definitions, def)
+		}
+	}
+	newTbl, err := getTable(d.store, ptSchemaID, newTblInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	oldPartTbl, ok1 := oldTbl.(table.PartitionedTable)
+	newPartTbl, ok2 := newTbl.(table.PartitionedTable)
+	if !ok1 || !ok2 || len(newTblInfo.Partition.Definitions) == 0 {
+		return ver, errors.Trace(dbterror.ErrInvalidDDLState.GenWithStack("can not find the exchanged partition %d of table %s", job.TableID, pt.Name))
+	}
+
+	elements := buildGlobalIndexElements(pt)
+	done, err := w.runExchangePartitionReorg(d, job, pt, func(rh *reorgHandler) (*reorgInfo, error) {
+		return getReorgInfoFromPartitions(d.jobContext(job.ID), d, rh, job, ptDbInfo, oldPartTbl, []int64{defID}, elements)
+	}, func(reorgInfo *reorgInfo) error {
+		if reorgInfo.PhysicalTableID == defID {
+			if err := w.cleanupGlobalIndexes(oldPartTbl, []int64{defID}, reorgInfo); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return w.addIndexesOnPhysicalTable(newPartTbl, newPartTbl.GetPartition(job.TableID), reorgInfo)
+	})
+	if err != nil || !done {
+		return ver, errors.Trace(err)
+	}
+
+	indexIDs := make([]int64, 0, len(elements))
+	for _, idxInfo := range pt.Indices {
+		if idxInfo.Global {
+			idxInfo.State = model.StatePublic
+			indexIDs = append(indexIDs, idxInfo.ID)
+		}
+	}
+	pt.Partition.DroppingDefinitions = nil
+	pt.Partition.DDLExchangeTableID = 0
+	pt.Partition.DDLExchangePartitionID = 0
+	if err = t.UpdateTable(ptSchemaID, pt); err != nil {
+		return ver, errors.Trace(err)
+	}
+	nt.ExchangePartitionInfo = nil
+	ver, err = updateVersionAndTableInfoWithCheck(d, t, job, nt, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
 	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, pt)
+	// The entries of the original indexes of the table are deleted, they are global indexes in the partition.
+	job.Args = append(job.Args, indexIDs)
 	return ver, nil
 }
 
+// addExchangeTableIndexes adds the entries of the indexes of the table for the rows of the partition to exchange,
+// whose indexes are global indexes.
+func (w *worker) addExchangeTableIndexes(d *ddlCtx, job *model.Job, dbInfo *model.DBInfo, nt, pt *model.TableInfo, defID int64) (bool, error) {
+	// The table has the ID of the partition after exchanging the IDs.
+	tblInfo := nt.Clone()
+	tblInfo.ID = defID
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	physTbl, ok := tbl.(table.PhysicalTable)
+	if !ok {
+		return false, errors.Trace(dbterror.ErrPartitionExchangePartTable.GenWithStackByArgs(nt.Name))
+	}
+	elements := buildGlobalIndexElements(pt)
+	return w.runExchangePartitionReorg(d, job, tblInfo, func(rh *reorgHandler) (*reorgInfo, error) {
+		return getReorgInfo(d.jobContext(job.ID), d, rh, job, dbInfo, tbl, elements, false)
+	}, func(reorgInfo *reorgInfo) error {
+		return w.addIndexesOnPhysicalTable(tbl, physTbl, reorgInfo)
+	})
+}
+
+func buildGlobalIndexElements(tblInfo *model.TableInfo) []*meta.Element {
+	elements := make([]*meta.Element, 0, len(tblInfo.Indices))
+	for _, idxInfo := range tblInfo.Indices {
+		if idxInfo.Global {
+			elements = append(elements, &meta.Element{ID: idxInfo.ID, TypeKey: meta.IndexElementKey})
+		}
+	}
+	return elements
+}
+
+// runExchangePartitionReorg runs the reorganization of the indexes when exchanging a partition,
+// it returns true if the reorganization is done.
+func (w *worker) runExchangePartitionReorg(d *ddlCtx, job *model.Job, tblInfo *model.TableInfo,
+	getReorgInfo func(rh *reorgHandler) (*reorgInfo, error), reorg func(reorgInfo *reorgInfo) error) (bool, error) {
+	job.ReorgMeta.ReorgTp = model.ReorgTypeTxn
+	sctx, err := w.sessPool.Get()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer w.sessPool.Put(sctx)
+	rh := newReorgHandler(sess.NewSession(sctx))
+	reorgInfo, err := getReorgInfo(rh)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, errors.Trace(err)
+	}
+	err = w.runReorgJob(reorgInfo, tblInfo, d.lease, func() (reorgErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onExchangeTablePartition",
+			func() {
+				reorgErr = dbterror.ErrCancelledDDLJob.GenWithStack("exchange partition panic")
+			}, false)
+		return reorg(reorgInfo)
+	})
+	if err != nil {
+		if dbterror.ErrWaitReorgTimeout.Equal(err) || dbterror.ErrPausedDDLJob.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// addIndexesOnPhysicalTable adds the indexes of the elements one by one on the physical table,
+// starting from the current element of the reorganization.
+func (w *worker) addIndexesOnPhysicalTable(t table.Table, physTbl table.PhysicalTable, reorgInfo *reorgInfo) error {
+	startElementOffset := 0
+	if reorgInfo.PhysicalTableID == physTbl.GetPhysicalID() {
+		for i, element := range reorgInfo.elements {
+			if reorgInfo.currElement.ID == element.ID {
+				startElementOffset = i
+				break
+			}
+		}
+	}
+	for i := startElementOffset; i < len(reorgInfo.elements); i++ {
+		currentVer, err := getValidCurrentVersion(reorgInfo.d.store)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Always (re)start with the full range of the physical table.
+		startKey, endKey, err := getTableRange(reorgInfo.d.jobContext(reorgInfo.Job.ID), reorgInfo.d, physTbl, currentVer.Ver, reorgInfo.Job.Priority)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		reorgInfo.currElement = reorgInfo.elements[i]
+		reorgInfo.PhysicalTableID, reorgInfo.StartKey, reorgInfo.EndKey = physTbl.GetPhysicalID(), startKey, endKey
+		// Write the reorg info to store so the whole reorganize process can recover from panic.
+		if err = reorgInfo.UpdateReorgMeta(reorgInfo.StartKey, w.sessPool); err != nil {
+			return errors.Trace(err)
+		}
+		if err = w.addTableIndex(t, reorgInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// rollbackExchangeTablePartition clears the exchange information of the tables,
+// when the exchange with global indexes fails before the IDs are exchanged.
+func rollbackExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job, nt, pt *model.TableInfo, ptSchemaID int64, err error) (ver int64, _ error) {
+	nt.ExchangePartitionInfo = nil
+	pt.Partition.DDLExchangeTableID = 0
+	pt.Partition.DDLExchangePartitionID = 0
+	if err1 := t.UpdateTable(ptSchemaID, pt); err1 != nil {
+		return ver, errors.Trace(err1)
+	}
+	ver, err1 := updateVersionAndTableInfo(d, t, job, nt, true)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, pt)
+	return ver, errors.Trace(err)
+}
+
 func checkReorgPartition(t *meta.Meta, job *model.Job) (*model.TableInfo, []model.CIStr, *model.PartitionInfo, []model.PartitionDefinition, []model.PartitionDefinition, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, schemaID)
@@ -2524,6 +2959,10 @@ func checkReorgPartition(t *meta.Meta, job *model.Job) (*model.TableInfo, []mode
 		job.State = model.JobStateCancelled
 		return nil, nil, nil, nil, nil, errors.Trace(err)
 	}
+	if job.Type == model.ActionAlterTablePartitioning && tblInfo.Partition == nil {
+		// The non-partitioned table is seen as a table with a single partition during the reorganization.
+		tblInfo.Partition = getPartitionInfoTypeNone(tblInfo)
+	}
 	addingDefs := tblInfo.Partition.AddingDefinitions
 	droppingDefs := tblInfo.Partition.DroppingDefinitions
 	if len(addingDefs) == 0 {
@@ -2587,7 +3026,20 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 			return ver, err
 		}
 		sctx := w.sess.Context
-		if err = checkReorgPartitionDefs(sctx, tblInfo, partInfo, firstPartIdx, lastPartIdx, idMap); err != nil {
+		switch job.Type {
+		case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+			if len(idMap) != len(tblInfo.Partition.Definitions) {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs(
+					job.Type.String() + ", since the partitions of the table have changed"))
+			}
+			if job.Type == model.ActionAlterTablePartitioning {
+				err = checkAlterTablePartitioningDefs(sctx, tblInfo, partInfo)
+			}
+		default:
+			err = checkReorgPartitionDefs(sctx, tblInfo, partInfo, firstPartIdx, lastPartIdx, idMap)
+		}
+		if err != nil {
 			job.State = model.JobStateCancelled
 			return ver, err
 		}
@@ -2598,6 +3050,30 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		_ = updateDroppingPartitionInfo(tblInfo, partNames)
 		// Reset original partitions, and keep DroppedDefinitions
 		tblInfo.Partition.Definitions = orgDefs
+		// The partitioning of the AddingDefinitions, which is new for ALTER TABLE ... PARTITION BY
+		// and REMOVE PARTITIONING
+		if job.Type != model.ActionReorganizePartition {
+			tblInfo.Partition.DDLType = partInfo.Type
+			tblInfo.Partition.DDLExpr = partInfo.Expr
+			tblInfo.Partition.DDLColumns = partInfo.Columns
+			tblInfo.Partition.NewTableID = partInfo.NewTableID
+		} else {
+			tblInfo.Partition.DDLType = tblInfo.Partition.Type
+			tblInfo.Partition.DDLExpr = tblInfo.Partition.Expr
+			tblInfo.Partition.DDLColumns = tblInfo.Partition.Columns
+			// The older versions don't set the DDL partitioning for REORGANIZE PARTITION.
+			failpoint.Inject("reorgPartitionUnsetDDLPartitioning", func(val failpoint.Value) {
+				if val.(bool) {
+					tblInfo.Partition.DDLType = model.PartitionTypeNone
+					tblInfo.Partition.DDLExpr = ""
+					tblInfo.Partition.DDLColumns = nil
+				}
+			})
+		}
+		if err = addReorgChangedIndexes(job, tblInfo, partInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
 
 		// modify placement settings
 		for _, def := range tblInfo.Partition.AddingDefinitions {
@@ -2718,6 +3194,7 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 
 		job.SchemaState = model.StateWriteOnly
 		tblInfo.Partition.DDLState = model.StateWriteOnly
+		setReorgChangedIndexesState(tblInfo, model.StateWriteOnly)
 		metrics.GetBackfillProgressByLabel(metrics.LblReorgPartition, job.SchemaName, tblInfo.Name.String()).Set(0.2 / float64(math.MaxUint64))
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != job.SchemaState)
 	case model.StateWriteOnly:
@@ -2726,6 +3203,7 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		job.SnapshotVer = 0
 		job.SchemaState = model.StateWriteReorganization
 		tblInfo.Partition.DDLState = model.StateWriteReorganization
+		setReorgChangedIndexesState(tblInfo, model.StateWriteReorganization)
 		metrics.GetBackfillProgressByLabel(metrics.LblReorgPartition, job.SchemaName, tblInfo.Name.String()).Set(0.3 / float64(math.MaxUint64))
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, originalState != job.SchemaState)
 	case model.StateWriteReorganization:
@@ -2734,12 +3212,6 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		if err2 != nil {
 			return ver, errors.Trace(err2)
 		}
-		// TODO: If table has global indexes, we need reorg to clean up them.
-		// and then add the new partition ids back...
-		if _, ok := tbl.(table.PartitionedTable); ok && hasGlobalIndex(tblInfo) {
-			err = errors.Trace(dbterror.ErrCancelledDDLJob.GenWithStack("global indexes is not supported yet for reorganize partition"))
-			return convertAddTablePartitionJob2RollbackJob(d, t, job, err, tblInfo)
-		}
 		var done bool
 		done, ver, err = doPartitionReorgWork(w, d, t, job, tbl, physicalTableIDs)
 
@@ -2761,6 +3233,20 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		// From now on, use the new definitions, but keep the Adding and Dropping for double write
 		tblInfo.Partition.Definitions = newDefs
 		tblInfo.Partition.Num = uint64(len(newDefs))
+		if job.Type != model.ActionReorganizePartition {
+			// Also use the new partitioning, and keep the old one for the DroppingDefinitions
+			pi := tblInfo.Partition
+			pi.Type, pi.DDLType = pi.DDLType, pi.Type
+			pi.Expr, pi.DDLExpr = pi.DDLExpr, pi.Expr
+			pi.Columns, pi.DDLColumns = pi.DDLColumns, pi.Columns
+		}
+		// The old indexes are still written for the sessions using the old definitions,
+		// and the new ones become public at the end, after the old definitions are no longer used.
+		for _, idxInfo := range tblInfo.Indices {
+			if isNew, changed := tblInfo.Partition.DDLChangedIndex[idxInfo.ID]; changed && !isNew {
+				idxInfo.State = model.StateWriteOnly
+			}
+		}
 
 		// Now all the data copying is done, but we cannot simply remove the droppingDefinitions
 		// since they are a part of the normal Definitions that other nodes with
@@ -2785,8 +3271,19 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 		newIDs := getPartitionIDsFromDefinitions(partInfo.Definitions)
 		job.CtxVars = []interface{}{physicalTableIDs, newIDs}
 		definitionsToAdd := tblInfo.Partition.AddingDefinitions
-		tblInfo.Partition.DroppingDefinitions = nil
-		tblInfo.Partition.AddingDefinitions = nil
+		// The old indexes of the table are deleted with the old table ID for the other types.
+		droppedIndexIDs := replaceReorgChangedIndexes(tblInfo)
+		if job.Type != model.ActionReorganizePartition {
+			droppedIndexIDs = nil
+			physicalTableIDs, err = replaceTableForPartitioning(t, job, tblInfo, physicalTableIDs)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.CtxVars = []interface{}{physicalTableIDs, newIDs, tblInfo.ID}
+		}
+		if tblInfo.Partition != nil {
+			tblInfo.Partition.ClearReorgIntermediateInfo()
+		}
 		ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
 		failpoint.Inject("reorgPartWriteReorgSchemaVersionUpdateFail", func(val failpoint.Value) {
 			if val.(bool) {
@@ -2797,14 +3294,13 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 			return ver, errors.Trace(err)
 		}
 		job.SchemaState = model.StateNone
-		tblInfo.Partition.DDLState = model.StateNone
 		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
 		// How to handle this?
 		// Seems to only trigger asynchronous update of statistics.
 		// Should it actually be synchronous?
-		asyncNotifyEvent(d, &util.Event{Tp: model.ActionReorganizePartition, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: definitionsToAdd}})
-		// A background job will be created to delete old partition data.
-		job.Args = []interface{}{physicalTableIDs}
+		asyncNotifyEvent(d, &util.Event{Tp: job.Type, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: definitionsToAdd}})
+		// A background job will be created to delete old partition data and the replaced indexes.
+		job.Args = []interface{}{physicalTableIDs, nil, droppedIndexIDs}
 
 	default:
 		err = dbterror.ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
@@ -2813,6 +3309,50 @@ func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job)
 	return ver, errors.Trace(err)
 }
 
+// replaceTableForPartitioning replaces the table with the one of the new table ID in the end of
+// ALTER TABLE ... PARTITION BY and REMOVE PARTITIONING, and returns the physical table IDs to delete.
+func replaceTableForPartitioning(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, physicalTableIDs []int64) ([]int64, error) {
+	oldTblID := tblInfo.ID
+	autoIDs, err := t.GetAutoIDAccessors(job.SchemaID, oldTblID).Get()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = t.DropTableOrView(job.SchemaID, oldTblID); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = t.GetAutoIDAccessors(job.SchemaID, oldTblID).Del(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tblInfo.Partition.DDLType != model.PartitionTypeNone {
+		// The old table ID is not one of the dropped partitions if the table was partitioned.
+		physicalTableIDs = append(physicalTableIDs, oldTblID)
+	}
+	tblInfo.ID = tblInfo.Partition.NewTableID
+	if tblInfo.Partition.Type == model.PartitionTypeNone {
+		// REMOVE PARTITIONING, the single new partition is the table itself.
+		tblInfo.Partition = nil
+	}
+	if err = t.GetAutoIDAccessors(job.SchemaID, tblInfo.ID).Put(autoIDs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = t.CreateTableOrView(job.SchemaID, tblInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// The placement rules and label rules refer to the table ID.
+	bundles, err := placement.NewFullTableBundles(t, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = infosync.PutRuleBundlesWithDefaultRetry(context.TODO(), bundles); err != nil {
+		return nil, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+	if _, err = alterTableLabelRule(job.SchemaName, tblInfo, getIDs([]*model.TableInfo{tblInfo})); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return physicalTableIDs, nil
+}
+
 func doPartitionReorgWork(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job, tbl table.Table, physTblIDs []int64) (done bool, ver int64, err error) {
 	job.ReorgMeta.ReorgTp = model.ReorgTypeTxn
 	sctx, err1 := w.sessPool.Get()
@@ -2821,7 +3361,14 @@ func doPartitionReorgWork(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job, tb
 	}
 	defer w.sessPool.Put(sctx)
 	rh := newReorgHandler(sess.NewSession(sctx))
-	elements := BuildElements(tbl.Meta().Columns[0], tbl.Meta().Indices)
+	// The old indexes replaced by the new ones are not created on the new partitions.
+	indices := make([]*model.IndexInfo, 0, len(tbl.Meta().Indices))
+	for _, idxInfo := range tbl.Meta().Indices {
+		if isNew, changed := tbl.Meta().Partition.DDLChangedIndex[idxInfo.ID]; !changed || isNew {
+			indices = append(indices, idxInfo)
+		}
+	}
+	elements := BuildElements(tbl.Meta().Columns[0], indices)
 	partTbl, ok := tbl.(table.PartitionedTable)
 	if !ok {
 		return false, ver, dbterror.ErrUnsupportedReorganizePartition.GenWithStackByArgs()
@@ -2881,7 +3428,8 @@ func newReorgPartitionWorker(sessCtx sessionctx.Context, i int, t table.Physical
 	if pt == nil {
 		return nil, dbterror.ErrUnsupportedReorganizePartition.GenWithStackByArgs()
 	}
-	partColIDs := pt.GetPartitionColumnIDs()
+	// Use the columns of the new partitioning, which may differ for ALTER TABLE ... PARTITION BY
+	partColIDs := reorgedTbl.GetPartitionColumnIDs()
 	writeColOffsetMap := make(map[int64]int, len(partColIDs))
 	maxOffset := 0
 	for _, col := range pt.Cols() {
@@ -3247,6 +3795,88 @@ func checkExchangePartitionRecordValidation(w *worker, pt *model.TableInfo, inde
 	return nil
 }
 
+// checkExchangePartitionGlobalIndexes checks the rows of the table to exchange do not conflict with the rows
+// of the other partitions on the unique global indexes.
+func checkExchangePartitionGlobalIndexes(w *worker, t *meta.Meta, pt *model.TableInfo, ptSchemaID int64, partName string, schemaName, tableName model.CIStr) error {
+	ptDbInfo, err := t.GetDatabase(ptSchemaID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var partNames []interface{}
+	for _, def := range pt.Partition.Definitions {
+		if def.Name.L != strings.ToLower(partName) {
+			partNames = append(partNames, def.Name.L)
+		}
+	}
+	if len(partNames) == 0 {
+		return nil
+	}
+
+	var ctx sessionctx.Context
+	ctx, err = w.sessPool.Get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.Put(ctx)
+
+	for _, idxInfo := range pt.Indices {
+		if !idxInfo.Global {
+			continue
+		}
+		var buf strings.Builder
+		paramList := make([]interface{}, 0, 4+len(partNames)+4*len(idxInfo.Columns))
+		buf.WriteString("select ")
+		for i, col := range idxInfo.Columns {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("nt.%n")
+			paramList = append(paramList, col.Name.L)
+		}
+		buf.WriteString(" from %n.%n as nt join %n.%n partition(")
+		paramList = append(paramList, schemaName.L, tableName.L, ptDbInfo.Name.L, pt.Name.L)
+		for i, name := range partNames {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("%n")
+			paramList = append(paramList, name)
+		}
+		buf.WriteString(") as pt on ")
+		for i, col := range idxInfo.Columns {
+			if i > 0 {
+				buf.WriteString(" and ")
+			}
+			if col.Length != types.UnspecifiedLength {
+				buf.WriteString("left(nt.%n, %?) = left(pt.%n, %?)")
+				paramList = append(paramList, col.Name.L, col.Length, col.Name.L, col.Length)
+			} else {
+				buf.WriteString("nt.%n = pt.%n")
+				paramList = append(paramList, col.Name.L, col.Name.L)
+			}
+		}
+		buf.WriteString(" limit 1")
+
+		rows, fields, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL(w.ctx, nil, buf.String(), paramList...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(rows) != 0 {
+			vals := make([]string, 0, len(fields))
+			for i, field := range fields {
+				d := rows[0].GetDatum(i, &field.Column.FieldType)
+				val, err := d.ToString()
+				if err != nil {
+					return errors.Trace(err)
+				}
+				vals = append(vals, val)
+			}
+			return errors.Trace(kv.ErrKeyExists.FastGenByArgs(strings.Join(vals, "-"), fmt.Sprintf("%s.%s", pt.Name.O, idxInfo.Name.O)))
+		}
+	}
+	return nil
+}
+
 func checkExchangePartitionPlacementPolicy(t *meta.Meta, ntPlacementPolicyRef *model.PolicyRefInfo, ptPlacementPolicyRef *model.PolicyRefInfo) error {
 	if ntPlacementPolicyRef == nil && ptPlacementPolicyRef == nil {
 		return nil
@@ -3427,14 +4057,19 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 				if tblInfo.IsCommonHandle {
 					return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("CLUSTERED INDEX")
 				}
-				if !config.GetGlobalConfig().EnableGlobalIndex {
+				if !index.Global {
 					return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
 				}
 			}
-			if !config.GetGlobalConfig().EnableGlobalIndex {
+			if !index.Global {
 				return dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
 			}
 		}
+		if index.Global {
+			if err := checkGlobalIndex(tblInfo, index.Primary, index.Unique); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	}
 	// when PKIsHandle, tblInfo.Indices will not contain the primary key.
 	if tblInfo.PKIsHandle {
@@ -3475,7 +4110,7 @@ func checkPartitionKeysConstraint(pi *model.PartitionInfo, indexColumns []*model
 
 	// In MySQL, every unique key on the table must use every column in the table's partitioning expression.(This
 	// also includes the table's primary key.)
-	// In TiDB, global index will be built when this constraint is not satisfied and EnableGlobalIndex is set.
+	// In TiDB, a global index is required when this constraint is not satisfied.
 	// See https://dev.mysql.com/doc/refman/5.7/en/partitioning-limitations-partitioning-keys-unique-keys.html
 	return checkUniqueKeyIncludePartKey(columnInfoSlice(partCols), indexColumns), nil
 }
@@ -3539,6 +4174,50 @@ type stringSlice interface {
 	At(i int) string
 }
 
+// checkGlobalIndex checks whether an index with the GLOBAL option can be created on the table.
+func checkGlobalIndex(tblInfo *model.TableInfo, primary, unique bool) error {
+	if tblInfo.GetPartitionInfo() == nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("GLOBAL IndexOption on non-partitioned table")
+	}
+	if !unique {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("GLOBAL IndexOption on non-unique index")
+	}
+	if primary && tblInfo.HasClusteredIndex() {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("GLOBAL IndexOption on clustered primary key")
+	}
+	return nil
+}
+
+// decideGlobalIndex decides whether a new unique index of the table is a global index.
+// The index is global if it is declared with the GLOBAL option, or it does not include
+// all the partitioning columns and the deprecated `enable-global-index` is set.
+func decideGlobalIndex(tblInfo *model.TableInfo, indexColumns []*model.IndexColumn, primary, unique bool, indexOption *ast.IndexOption) (bool, error) {
+	global := indexOption != nil && indexOption.Global
+	if global {
+		if err := checkGlobalIndex(tblInfo, primary, unique); err != nil {
+			return false, err
+		}
+	}
+	if !unique || tblInfo.GetPartitionInfo() == nil {
+		return global, nil
+	}
+	ck, err := checkPartitionKeysConstraint(tblInfo.GetPartitionInfo(), indexColumns, tblInfo)
+	if err != nil {
+		return false, err
+	}
+	if !ck && !global {
+		if !config.GetGlobalConfig().EnableGlobalIndex {
+			if primary {
+				return false, dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY")
+			}
+			return false, dbterror.ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+		}
+		// index columns does not contain all partition columns, must set global
+		global = true
+	}
+	return global, nil
+}
+
 // checkUniqueKeyIncludePartKey checks that the partitioning key is included in the constraint.
 func checkUniqueKeyIncludePartKey(partCols stringSlice, idxCols []*model.IndexColumn) bool {
 	for i := 0; i < partCols.Len(); i++ {
@@ -3843,7 +4522,8 @@ func writeColumnListToBuffer(partitionInfo *model.PartitionInfo, sqlMode mysql.S
 // AppendPartitionInfo is used in SHOW CREATE TABLE as well as generation the SQL syntax
 // for the PartitionInfo during validation of various DDL commands
 func AppendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer, sqlMode mysql.SQLMode) {
-	if partitionInfo == nil {
+	// PartitionTypeNone is a non-partitioned table during ALTER TABLE ... PARTITION BY
+	if partitionInfo == nil || partitionInfo.Type == model.PartitionTypeNone {
 		return
 	}
 	// Since MySQL 5.1/5.5 is very old and TiDB aims for 5.7/8.0 compatibility, we will not
@@ -1306,6 +1306,59 @@ func TestReorgPartitionFailInject(t *testing.T) {
 		" PARTITION `pMax` VALUES LESS THAN (MAXVALUE))"))
 }
 
+func TestReorgPartitionWithoutDDLPartitioning(t *testing.T) {
+	// The DDL partitioning is not set by the older versions.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/ddl/reorgPartitionUnsetDDLPartitioning", `return(true)`))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/ddl/reorgPartitionUnsetDDLPartitioning"))
+	}()
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	schemaName := "ReorgPartWithoutDDLPartitioning"
+	tk.MustExec("create database " + schemaName)
+	tk.MustExec("use " + schemaName)
+	tk.MustExec(`create table t (a int unsigned PRIMARY KEY, b varchar(255), c int, key (b), key (c,b))` +
+		` partition by range (a) ` +
+		`(partition p0 values less than (10),` +
+		` partition p1 values less than (20),` +
+		` partition pMax values less than (MAXVALUE))`)
+	tk.MustExec(`insert into t values (1,"1",1), (12,"12",21), (17,"17",71), (23,"23",32)`)
+
+	dom := domain.GetDomain(tk.Session())
+	originHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originHook)
+	hook := &callback.TestDDLCallback{Do: dom}
+	dom.DDL().SetHook(hook)
+
+	wait := make(chan bool)
+	defer close(wait)
+
+	injected := false
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type == model.ActionReorganizePartition && job.SchemaState == model.StateWriteReorganization && !injected {
+			injected = true
+			<-wait
+			<-wait
+		}
+	}
+	alterErr := make(chan error, 1)
+	go backgroundExec(store, schemaName, "alter table t reorganize partition p1 into (partition p1a values less than (15), partition p1b values less than (20))", alterErr)
+	wait <- true
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr(schemaName), model.NewCIStr("t"))
+	require.NoError(t, err)
+	require.NotEmpty(t, tbl.Meta().Partition.AddingDefinitions)
+	require.Equal(t, model.PartitionTypeNone, tbl.Meta().Partition.DDLType)
+	// The rows are double written to the adding partitions by the current partitioning.
+	tk.MustExec(`insert into t values (14, "14", 14), (16, "16", 16)`)
+	tk.MustExec(`admin check table t`)
+	wait <- true
+	require.NoError(t, <-alterErr)
+	tk.MustExec(`admin check table t`)
+	tk.MustQuery(`select a from t partition (p1a)`).Sort().Check(testkit.Rows("12", "14"))
+	tk.MustQuery(`select a from t partition (p1b)`).Sort().Check(testkit.Rows("16", "17"))
+	tk.MustQuery(`select a from t partition (pMax)`).Check(testkit.Rows("23"))
+}
+
 func TestReorgPartitionRollback(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -1374,3 +1427,180 @@ func TestReorgPartitionData(t *testing.T) {
 	tk.MustQuery(`select * from t`).Sort().Check(testkit.Rows("0 Zero value! 0 2022-02-30 00:00:00"))
 	tk.MustExec(`admin check table t`)
 }
+
+func TestAlterTablePartitionBy(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	schemaName := "AlterTablePartitionBy"
+	tk.MustExec("create database " + schemaName)
+	tk.MustExec("use " + schemaName)
+	tk.MustExec(`create table t (a int unsigned PRIMARY KEY, b varchar(255), c int, key (b), key (c,b))`)
+	tk.MustExec(`insert into t values (1,"1",1), (12,"12",21),(23,"23",32),(34,"34",43),(45,"45",54),(56,"56",65)`)
+	tk.MustContainErrMsg(`alter table t partition by range (c) (partition p0 values less than (30), partition pMax values less than (MAXVALUE))`,
+		"[ddl:1503]A CLUSTERED INDEX must include all columns in the table's partitioning function")
+
+	tk.MustExec(`alter table t partition by range (a) (partition p0 values less than (20), partition pMax values less than (MAXVALUE))`)
+	tk.MustExec(`admin check table t`)
+	tk.MustQuery(`show create table t`).Check(testkit.Rows("" +
+		"t CREATE TABLE `t` (\n" +
+		"  `a` int(10) unsigned NOT NULL,\n" +
+		"  `b` varchar(255) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `b` (`b`),\n" +
+		"  KEY `c` (`c`,`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`)\n" +
+		"(PARTITION `p0` VALUES LESS THAN (20),\n" +
+		" PARTITION `pMax` VALUES LESS THAN (MAXVALUE))"))
+	tk.MustQuery(`select * from t partition (p0)`).Sort().Check(testkit.Rows("1 1 1", "12 12 21"))
+	tk.MustQuery(`select * from t partition (pMax)`).Sort().Check(testkit.Rows("23 23 32", "34 34 43", "45 45 54", "56 56 65"))
+
+	tk.MustExec(`alter table t partition by hash (a) partitions 3`)
+	tk.MustExec(`admin check table t`)
+	tk.MustQuery(`select * from t partition (p0)`).Sort().Check(testkit.Rows("12 12 21", "45 45 54"))
+	tk.MustQuery(`select * from t partition (p1)`).Sort().Check(testkit.Rows("1 1 1", "34 34 43"))
+	tk.MustQuery(`select * from t partition (p2)`).Sort().Check(testkit.Rows("23 23 32", "56 56 65"))
+	tk.MustQuery(`select b from t use index (b) where b = "34"`).Check(testkit.Rows("34"))
+	tk.MustExec(`insert into t values (67,"67",76)`)
+	tk.MustQuery(`select * from t partition (p1)`).Sort().Check(testkit.Rows("1 1 1", "34 34 43", "67 67 76"))
+
+	ctx := tk.Session()
+	is := domain.GetDomain(ctx).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr(schemaName), model.NewCIStr("t"))
+	require.NoError(t, err)
+	noNewTablesAfter(t, tk, ctx, tbl)
+}
+
+func TestAlterTablePartitionByRollback(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	schemaName := "AlterTablePartitionByRollback"
+	tk.MustExec("create database " + schemaName)
+	tk.MustExec("use " + schemaName)
+	tk.MustExec(`create table t (a int unsigned PRIMARY KEY, b varchar(255), c int, key (b), key (c,b))`)
+	tk.MustExec(`insert into t values (1,"1",1), (12,"12",21),(23,"23",32),(34,"34",43),(45,"45",54),(56,"56",65)`)
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/ddl/reorgPartitionAfterDataCopy", `return(true)`))
+	defer func() {
+		err := failpoint.Disable("github.com/pingcap/tidb/ddl/reorgPartitionAfterDataCopy")
+		require.NoError(t, err)
+	}()
+	tk.MustExecToErr(`alter table t partition by range (a) (partition p0 values less than (20), partition pMax values less than (MAXVALUE))`)
+	tk.MustExec(`admin check table t`)
+	tk.MustQuery(`show create table t`).Check(testkit.Rows("" +
+		"t CREATE TABLE `t` (\n" +
+		"  `a` int(10) unsigned NOT NULL,\n" +
+		"  `b` varchar(255) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `b` (`b`),\n" +
+		"  KEY `c` (`c`,`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery(`select * from t where a < 20`).Sort().Check(testkit.Rows("1 1 1", "12 12 21"))
+}
+
+func TestRemovePartitioning(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	schemaName := "RemovePartitioning"
+	tk.MustExec("create database " + schemaName)
+	tk.MustExec("use " + schemaName)
+	tk.MustExec(`create table t (a int unsigned PRIMARY KEY AUTO_INCREMENT, b varchar(255), c int, key (b), key (c,b))` +
+		` partition by list (a) ` +
+		`(partition p0 values in (1,12,23,34),` +
+		` partition p1 values in (45,56,67,78,89))`)
+	tk.MustExec(`insert into t values (1,"1",1), (12,"12",21),(23,"23",32),(34,"34",43),(45,"45",54),(56,"56",65)`)
+	tk.MustExec(`alter table t remove partitioning`)
+	tk.MustExec(`admin check table t`)
+	tk.MustQuery(`show create table t`).Check(testkit.Rows("" +
+		"t CREATE TABLE `t` (\n" +
+		"  `a` int(10) unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `b` varchar(255) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `b` (`b`),\n" +
+		"  KEY `c` (`c`,`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin AUTO_INCREMENT=5002"))
+	tk.MustQuery(`select * from t`).Sort().Check(testkit.Rows("1 1 1", "12 12 21", "23 23 32", "34 34 43", "45 45 54", "56 56 65"))
+	tk.MustQuery(`select b from t use index (c) where c = 43`).Check(testkit.Rows("34"))
+	tk.MustExec(`insert into t values (100,"100",100)`)
+	tk.MustQuery(`select count(*) from t`).Check(testkit.Rows("7"))
+	tk.MustContainErrMsg(`alter table t remove partitioning`, "[ddl:1505]Partition management on a not partitioned table is not possible")
+
+	ctx := tk.Session()
+	is := domain.GetDomain(ctx).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr(schemaName), model.NewCIStr("t"))
+	require.NoError(t, err)
+	require.Nil(t, tbl.Meta().Partition)
+	noNewTablesAfter(t, tk, ctx, tbl)
+}
+
+func TestRebuildPartition(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	schemaName := "RebuildPartition"
+	tk.MustExec("create database " + schemaName)
+	tk.MustExec("use " + schemaName)
+	tk.MustExec(`create table t (a int unsigned PRIMARY KEY, b varchar(255), c int, key (b), key (c,b))` +
+		` partition by range (a) ` +
+		`(partition p0 values less than (10),` +
+		` partition p1 values less than (20),` +
+		` partition p2 values less than (30),` +
+		` partition pMax values less than (MAXVALUE))`)
+	tk.MustExec(`insert into t values (1,"1",1), (12,"12",21),(23,"23",32),(34,"34",43),(45,"45",54),(56,"56",65)`)
+	ctx := tk.Session()
+	getPartitionIDs := func() []int64 {
+		tbl, err := domain.GetDomain(ctx).InfoSchema().TableByName(model.NewCIStr(schemaName), model.NewCIStr("t"))
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(tbl.Meta().Partition.Definitions))
+		for _, def := range tbl.Meta().Partition.Definitions {
+			ids = append(ids, def.ID)
+		}
+		return ids
+	}
+	orgIDs := getPartitionIDs()
+	// Not adjacent, so p1 is also rebuilt
+	tk.MustExec(`alter table t rebuild partition p0, p2`)
+	tk.MustQuery(`show warnings`).Check(testkit.Rows(
+		"Warning 1105 The partitions p1 are also rebuilt, since the partitions to rebuild must be adjacent for RANGE partitioning and all the partitions are rebuilt for HASH and KEY partitioning",
+		"Warning 1105 The statistics of related partitions will be outdated after reorganizing partitions. Please use 'ANALYZE TABLE' statement if you want to update it now"))
+	tk.MustExec(`admin check table t`)
+	newIDs := getPartitionIDs()
+	require.NotEqual(t, orgIDs[0], newIDs[0])
+	require.NotEqual(t, orgIDs[1], newIDs[1])
+	require.NotEqual(t, orgIDs[2], newIDs[2])
+	require.Equal(t, orgIDs[3], newIDs[3])
+	tk.MustQuery(`select * from t partition (p1)`).Check(testkit.Rows("12 12 21"))
+	tk.MustExec(`alter table t optimize partition all`)
+	tk.MustQuery(`show warnings`).Check(testkit.Rows(
+		"Note 1105 Table does not support optimize, doing rebuild instead",
+		"Warning 1105 The statistics of related partitions will be outdated after reorganizing partitions. Please use 'ANALYZE TABLE' statement if you want to update it now"))
+	tk.MustExec(`admin check table t`)
+	tk.MustQuery(`select * from t`).Sort().Check(testkit.Rows("1 1 1", "12 12 21", "23 23 32", "34 34 43", "45 45 54", "56 56 65"))
+	tk.MustQuery(`show create table t`).Check(testkit.Rows("" +
+		"t CREATE TABLE `t` (\n" +
+		"  `a` int(10) unsigned NOT NULL,\n" +
+		"  `b` varchar(255) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `b` (`b`),\n" +
+		"  KEY `c` (`c`,`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE (`a`)\n" +
+		"(PARTITION `p0` VALUES LESS THAN (10),\n" +
+		" PARTITION `p1` VALUES LESS THAN (20),\n" +
+		" PARTITION `p2` VALUES LESS THAN (30),\n" +
+		" PARTITION `pMax` VALUES LESS THAN (MAXVALUE))"))
+	tk.MustContainErrMsg(`alter table t rebuild partition p5`, "[table:1735]Unknown partition 'p5' in table 't'")
+	tk.MustContainErrMsg(`alter table t rebuild partition p0, p5`, "[table:1735]Unknown partition 'p5' in table 't'")
+
+	tk.MustExec(`create table t2 (a int) partition by hash (a) partitions 3`)
+	tk.MustExec(`insert into t2 values (1), (2), (3)`)
+	tk.MustContainErrMsg(`alter table t2 rebuild partition no_such_part`, "[table:1735]Unknown partition 'no_such_part' in table 't2'")
+	tk.MustExec(`alter table t2 rebuild partition p0`)
+	tk.MustQuery(`show warnings`).Check(testkit.Rows(
+		"Warning 1105 The partitions p1,p2 are also rebuilt, since the partitions to rebuild must be adjacent for RANGE partitioning and all the partitions are rebuilt for HASH and KEY partitioning",
+		"Warning 1105 The statistics of related partitions will be outdated after reorganizing partitions. Please use 'ANALYZE TABLE' statement if you want to update it now"))
+	tk.MustQuery(`select * from t2`).Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustExec(`admin check table t2`)
+}
@@ -35,4 +35,8 @@ var (
 	ErrInvalidResourceGroupRunawayExecElapsedTime = errors.New("invalid exec elapsed time")
 	// ErrUnknownResourceGroupRunawayAction is from group.go.
 	ErrUnknownResourceGroupRunawayAction = errors.New("unknown resource group runaway action")
+	// ErrInvalidResourceGroupQueueMaxConcurrency is from group.go.
+	ErrInvalidResourceGroupQueueMaxConcurrency = errors.New("invalid max concurrency of the queue, it must be greater than 0")
+	// ErrInvalidResourceGroupQueueTimeout is from ddl_api.go.
+	ErrInvalidResourceGroupQueueTimeout = errors.New("invalid queue timeout, it must be 0 or at least 1ms")
 )
@@ -40,7 +40,9 @@ func NewGroupFromOptions(groupName string, options *model.ResourceGroupSettings)
 		runaway := &rmpb.RunawaySettings{
 			Rule: &rmpb.RunawayRule{},
 		}
-		if options.Runaway.ExecElapsedTimeMs == 0 {
+		// The rules except EXEC_ELAPSED are only stored in TiDB, so a query limit without
+		// EXEC_ELAPSED is sent to the resource manager with ExecElapsedTimeMs as 0.
+		if !options.Runaway.HasRule() {
 			return nil, ErrInvalidResourceGroupRunawayExecElapsedTime
 		}
 		runaway.Rule.ExecElapsedTimeMs = options.Runaway.ExecElapsedTimeMs
@@ -57,6 +59,11 @@ func NewGroupFromOptions(groupName string, options *model.ResourceGroupSettings)
 		group.RunawaySettings = runaway
 	}
 
+	// The queue is only used by TiDB, so it's not sent to the resource manager.
+	if options.Queue != nil && options.Queue.MaxConcurrency == 0 {
+		return nil, ErrInvalidResourceGroupQueueMaxConcurrency
+	}
+
 	if options.Background != nil {
 		group.BackgroundSettings = &rmpb.BackgroundSettings{
 			JobTypes: options.Background.JobTypes,
@@ -0,0 +1,177 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+// rowAccessPolicyChecker checks the expression of a row access policy and collects the columns referred by it.
+// The qualifiers of the column names are removed, so the expression is still valid after the table is renamed.
+type rowAccessPolicyChecker struct {
+	schema  model.CIStr
+	tblInfo *model.TableInfo
+	columns []model.CIStr
+	err     error
+}
+
+// Enter implements ast.Visitor interface.
+func (c *rowAccessPolicyChecker) Enter(inNode ast.Node) (ast.Node, bool) {
+	switch x := inNode.(type) {
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr:
+		c.err = dbterror.ErrInvalidRowAccessPolicy.GenWithStackByArgs("subqueries are not allowed")
+	case *ast.AggregateFuncExpr:
+		c.err = dbterror.ErrInvalidRowAccessPolicy.GenWithStackByArgs("aggregate functions are not allowed")
+	case *ast.WindowFuncExpr:
+		c.err = dbterror.ErrInvalidRowAccessPolicy.GenWithStackByArgs("window functions are not allowed")
+	case *ast.VariableExpr:
+		// The variables can be set by the users, so they can't be used to isolate the rows.
+		c.err = dbterror.ErrInvalidRowAccessPolicy.GenWithStackByArgs("variables are not allowed")
+	case *ast.DefaultExpr:
+		c.err = dbterror.ErrInvalidRowAccessPolicy.GenWithStackByArgs("DEFAULT is not allowed")
+	case *ast.ColumnName:
+		if (x.Schema.L != "" && x.Schema.L != c.schema.L) || (x.Table.L != "" && x.Table.L != c.tblInfo.Name.L) {
+			c.err = infoschema.ErrColumnNotExists.GenWithStackByArgs(x.OrigColName(), "row access policy")
+			break
+		}
+		colInfo := model.FindColumnInfo(c.tblInfo.Columns, x.Name.L)
+		if colInfo == nil || colInfo.Hidden {
+			c.err = infoschema.ErrColumnNotExists.GenWithStackByArgs(x.Name, c.tblInfo.Name)
+			break
+		}
+		x.Schema, x.Table = model.CIStr{}, model.CIStr{}
+		for _, col := range c.columns {
+			if col.L == colInfo.Name.L {
+				return inNode, false
+			}
+		}
+		c.columns = append(c.columns, colInfo.Name)
+	}
+	return inNode, c.err != nil
+}
+
+// Leave implements ast.Visitor interface.
+func (c *rowAccessPolicyChecker) Leave(inNode ast.Node) (ast.Node, bool) {
+	return inNode, c.err == nil
+}
+
+// buildRowAccessPolicyInfo builds a RowAccessPolicyInfo from an ast.CreateRowAccessPolicyStmt.
+func buildRowAccessPolicyInfo(ctx sessionctx.Context, schema model.CIStr, tblInfo *model.TableInfo, s *ast.CreateRowAccessPolicyStmt) (*model.RowAccessPolicyInfo, error) {
+	checker := &rowAccessPolicyChecker{schema: schema, tblInfo: tblInfo}
+	s.Expr.Accept(checker)
+	if checker.err != nil {
+		return nil, checker.err
+	}
+	// Make sure the functions in the expression exist and get the right args.
+	if _, err := expression.RewriteSimpleExprWithTableInfo(ctx, tblInfo, s.Expr, false); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)
+	if err := s.Expr.Restore(restoreCtx); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &model.RowAccessPolicyInfo{
+		Name:       s.PolicyName,
+		ExprString: sb.String(),
+		Columns:    checker.columns,
+	}, nil
+}
+
+// checkColumnWithRowAccessPolicies checks whether the column which is going to be dropped or renamed
+// is referred by a row access policy.
+func checkColumnWithRowAccessPolicies(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, policy := range tblInfo.RowAccessPolicies {
+		if policy.DependsOnColumn(colName.L) {
+			return dbterror.ErrDependentByRowAccessPolicy.GenWithStackByArgs(colName.O, policy.Name.O)
+		}
+	}
+	return nil
+}
+
+func onCreateRowAccessPolicy(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	policy := &model.RowAccessPolicyInfo{}
+	if err := job.DecodeArgs(policy); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// The table may be changed after the policy is checked in the DDL API.
+	if tblInfo.FindRowAccessPolicy(policy.Name.L) != nil {
+		job.State = model.JobStateCancelled
+		return ver, infoschema.ErrRowAccessPolicyExists.GenWithStackByArgs(policy.Name.O)
+	}
+	for _, colName := range policy.Columns {
+		colInfo := model.FindColumnInfo(tblInfo.Columns, colName.L)
+		if colInfo == nil || colInfo.State != model.StatePublic {
+			job.State = model.JobStateCancelled
+			return ver, infoschema.ErrColumnNotExists.GenWithStackByArgs(colName, tblInfo.Name)
+		}
+	}
+
+	tblInfo.RowAccessPolicies = append(tblInfo.RowAccessPolicies, policy)
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropRowAccessPolicy(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var policyName model.CIStr
+	if err := job.DecodeArgs(&policyName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if tblInfo.FindRowAccessPolicy(policyName.L) == nil {
+		job.State = model.JobStateCancelled
+		return ver, infoschema.ErrRowAccessPolicyNotExists.GenWithStackByArgs(policyName.O)
+	}
+
+	policies := tblInfo.RowAccessPolicies[:0]
+	for _, policy := range tblInfo.RowAccessPolicies {
+		if policy.Name.L != policyName.L {
+			policies = append(policies, policy)
+		}
+	}
+	tblInfo.RowAccessPolicies = policies
+	ver, err = updateVersionAndTableInfo(d, t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
@@ -99,13 +99,40 @@ func expectedDeleteRangeCnt(ctx delRangeCntCtx, job *model.Job) (int, error) {
 			return 0, errors.Trace(err)
 		}
 		return len(physicalTableIDs) + 1, nil
-	case model.ActionDropTablePartition, model.ActionTruncateTablePartition,
-		model.ActionReorganizePartition:
+	case model.ActionDropTablePartition, model.ActionTruncateTablePartition:
 		var physicalTableIDs []int64
 		if err := job.DecodeArgs(&physicalTableIDs); err != nil {
 			return 0, errors.Trace(err)
 		}
 		return len(physicalTableIDs), nil
+	case model.ActionDropMaterializedView:
+		var tableIDs []int64
+		if err := job.DecodeArgs(&tableIDs); err != nil {
+			return 0, errors.Trace(err)
+		}
+		return len(tableIDs), nil
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+		model.ActionRemovePartitioning:
+		var physicalTableIDs []int64
+		var partInfo model.PartitionInfo
+		var globalIndexIDs []int64
+		if err := job.DecodeArgs(&physicalTableIDs, &partInfo, &globalIndexIDs); err != nil {
+			return 0, errors.Trace(err)
+		}
+		return len(physicalTableIDs) + len(globalIndexIDs), nil
+	case model.ActionExchangeTablePartition:
+		var (
+			defID          int64
+			ptSchemaID     int64
+			ptID           int64
+			partName       string
+			withValidation bool
+			indexIDs       []int64
+		)
+		if err := job.DecodeArgs(&defID, &ptSchemaID, &ptID, &partName, &withValidation, &indexIDs); err != nil {
+			return 0, errors.Trace(err)
+		}
+		return len(indexIDs), nil
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
 		var indexID int64
 		var ifExists bool
@@ -257,6 +257,42 @@ func (d *Checker) CreateView(ctx sessionctx.Context, stmt *ast.CreateViewStmt) e
 	return nil
 }
 
+// CreateMaterializedView implements the DDL interface.
+func (*Checker) CreateMaterializedView(_ sessionctx.Context, _ *ast.CreateMaterializedViewStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// DropMaterializedView implements the DDL interface.
+func (*Checker) DropMaterializedView(_ sessionctx.Context, _ *ast.DropMaterializedViewStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// CreateMaskingPolicy implements the DDL interface.
+func (*Checker) CreateMaskingPolicy(_ sessionctx.Context, _ *ast.CreateMaskingPolicyStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// DropMaskingPolicy implements the DDL interface.
+func (*Checker) DropMaskingPolicy(_ sessionctx.Context, _ *ast.DropMaskingPolicyStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// CreateRowAccessPolicy implements the DDL interface.
+func (*Checker) CreateRowAccessPolicy(_ sessionctx.Context, _ *ast.CreateRowAccessPolicyStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+// DropRowAccessPolicy implements the DDL interface.
+func (*Checker) DropRowAccessPolicy(_ sessionctx.Context, _ *ast.DropRowAccessPolicyStmt) error {
+	//TODO implement me
+	panic("implement me")
+}
+
 // DropTable implements the DDL interface.
 func (d *Checker) DropTable(ctx sessionctx.Context, stmt *ast.DropTableStmt) (err error) {
 	err = d.realDDL.DropTable(ctx, stmt)
@@ -290,6 +290,36 @@ func (d SchemaTracker) CreateView(ctx sessionctx.Context, s *ast.CreateViewStmt)
 	return d.CreateTableWithInfo(ctx, s.ViewName.Schema, tbInfo, onExist)
 }
 
+// CreateMaterializedView implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) CreateMaterializedView(_ sessionctx.Context, _ *ast.CreateMaterializedViewStmt) error {
+	return nil
+}
+
+// DropMaterializedView implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) DropMaterializedView(_ sessionctx.Context, _ *ast.DropMaterializedViewStmt) error {
+	return nil
+}
+
+// CreateMaskingPolicy implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) CreateMaskingPolicy(_ sessionctx.Context, _ *ast.CreateMaskingPolicyStmt) error {
+	return nil
+}
+
+// DropMaskingPolicy implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) DropMaskingPolicy(_ sessionctx.Context, _ *ast.DropMaskingPolicyStmt) error {
+	return nil
+}
+
+// CreateRowAccessPolicy implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) CreateRowAccessPolicy(_ sessionctx.Context, _ *ast.CreateRowAccessPolicyStmt) error {
+	return nil
+}
+
+// DropRowAccessPolicy implements the DDL interface, it's no-op in DM's case.
+func (SchemaTracker) DropRowAccessPolicy(_ sessionctx.Context, _ *ast.DropRowAccessPolicyStmt) error {
+	return nil
+}
+
 // DropTable implements the DDL interface.
 func (d SchemaTracker) DropTable(_ sessionctx.Context, stmt *ast.DropTableStmt) (err error) {
 	notExistTables := make([]string, 0, len(stmt.Tables))
@@ -288,6 +288,90 @@ func TestResourceGroupRunaway(t *testing.T) {
 	tk.MustQuery("select /*+ resource_group(rg2) */ * from t").Check(testkit.Rows("1"))
 }
 
+func TestResourceGroupRunawayRules(t *testing.T) {
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/domain/FastRunawayGC", `return(true)`))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/domain/FastRunawayGC"))
+	}()
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil, nil))
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("insert into t values(1), (2), (3), (4), (5)")
+
+	tk.MustExec("set global tidb_enable_resource_control='on'")
+	tk.MustExec("create resource group rg1 RU_PER_SEC=1000 QUERY_LIMIT=(RESULT_ROWS=3 ACTION=KILL)")
+	tk.MustQuery("show create resource group rg1").Check(testkit.Rows("rg1 CREATE RESOURCE GROUP `rg1` RU_PER_SEC=1000, PRIORITY=MEDIUM, QUERY_LIMIT=(RESULT_ROWS=3 ACTION=KILL)"))
+	tk.MustExec("create resource group rg2 RU_PER_SEC=1000 QUERY_LIMIT=(EXEC_ELAPSED='1h' PROCESSED_KEYS=100000 RU=100000 MEMORY=1 ACTION=DRYRUN)")
+	tk.MustQuery("show create resource group rg2").Check(testkit.Rows("rg2 CREATE RESOURCE GROUP `rg2` RU_PER_SEC=1000, PRIORITY=MEDIUM, QUERY_LIMIT=(EXEC_ELAPSED=\"1h0m0s\" PROCESSED_KEYS=100000 RU=100000 MEMORY=1 ACTION=DRYRUN)"))
+	g := testResourceGroupNameFromIS(t, tk.Session(), "rg2")
+	require.EqualValues(t, 100000, g.Runaway.ProcessedKeys)
+	require.EqualValues(t, 100000, g.Runaway.RequestUnit)
+	require.EqualValues(t, 1, g.Runaway.MemoryBytes)
+
+	tk.MustQuery("select /*+ resource_group(rg1) */ * from t where a <= 3").Check(testkit.Rows("1", "2", "3"))
+	err := tk.QueryToErr("select /*+ resource_group(rg1) */ * from t")
+	require.ErrorContains(t, err, "Query execution was interrupted, identified as runaway query")
+	tk.MustQuery("select /*+ resource_group(rg2) */ * from t order by a").Check(testkit.Rows("1", "2", "3", "4", "5"))
+
+	tryInterval := time.Millisecond * 200
+	maxWaitDuration := time.Second * 5
+	tk.EventuallyMustQueryAndCheck("select SQL_NO_CACHE resource_group_name, original_sql, match_type, action, rule from mysql.tidb_runaway_queries order by resource_group_name", nil,
+		testkit.Rows("rg1 select /*+ resource_group(rg1) */ * from t identify kill RESULT_ROWS=3",
+			"rg2 select /*+ resource_group(rg2) */ * from t order by a identify dryrun MEMORY=1"), maxWaitDuration, tryInterval)
+
+	// The MEMORY rule is checked by the memory tracker, so it also works for the DML which returns no rows.
+	tk.MustExec("create resource group rg3 RU_PER_SEC=1000 QUERY_LIMIT=(MEMORY=1 ACTION=KILL)")
+	err = tk.ExecToErr("insert /*+ resource_group(rg3) */ into t select a + 10 from t")
+	require.ErrorContains(t, err, "Query execution was interrupted, identified as runaway query")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("5"))
+
+	// The RU rule is checked by the RU collected by the KV client.
+	tk.MustExec("create table t2(a int, b varchar(255))")
+	tk.MustExec("insert into t2 values(1, repeat('a', 255))")
+	for i := 0; i < 11; i++ {
+		tk.MustExec("insert into t2 select a, b from t2")
+	}
+	tk.MustExec("create resource group rg4 RU_PER_SEC=1000 QUERY_LIMIT=(RU=1 ACTION=DRYRUN)")
+	require.Len(t, tk.MustQuery("select /*+ resource_group(rg4) */ * from t2").Rows(), 2048)
+	tk.MustQuery("select /*+ resource_group(rg4) */ * from t where a = 1").Check(testkit.Rows("1"))
+	tk.EventuallyMustQueryAndCheck("select SQL_NO_CACHE resource_group_name, original_sql, match_type, action, rule from mysql.tidb_runaway_queries where resource_group_name in ('rg3', 'rg4') order by resource_group_name", nil,
+		testkit.Rows("rg3 insert /*+ resource_group(rg3) */ into t select a + 10 from t identify kill MEMORY=1",
+			"rg4 select /*+ resource_group(rg4) */ * from t2 identify dryrun RU=1"), maxWaitDuration, tryInterval)
+}
+
+func TestResourceGroupRunawayResultRowsRetry(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("insert into t values(1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	tk.MustExec("set global tidb_enable_resource_control='on'")
+	tk.MustExec("create resource group rg1 RU_PER_SEC=1000 QUERY_LIMIT=(RESULT_ROWS=5 ACTION=KILL)")
+
+	// The row 5 is locked, so the select for update returns the first chunks
+	// before it meets the write conflict and retries.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("begin pessimistic")
+	tk2.MustQuery("select * from t where a = 5 for update").Check(testkit.Rows("5 5"))
+
+	tk.MustExec("set tidb_init_chunk_size = 1")
+	tk.MustExec("begin pessimistic")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tk.MustQuery("select /*+ resource_group(rg1) */ * from t for update").Check(testkit.Rows("1 1", "2 2", "3 3", "4 4", "5 6"))
+	}()
+	time.Sleep(200 * time.Millisecond)
+	tk2.MustExec("update t set b = 6 where a = 5")
+	tk2.MustExec("commit")
+	<-done
+	tk.MustExec("commit")
+}
+
 func TestResourceGroupHint(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -462,3 +546,60 @@ func TestBindHints(t *testing.T) {
 	re.Equal("", tk.Session().GetSessionVars().StmtCtx.ResourceGroup)
 	re.Equal("default", tk.Session().GetSessionVars().ResourceGroupName)
 }
+
+func TestResourceGroupQueue(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_resource_control='on'")
+	tk.MustExec("create resource group rg1 RU_PER_SEC=1000 QUEUE=(MAX_CONCURRENCY=1 MAX_QUEUE_LENGTH=0)")
+	tk.MustQuery("show create resource group rg1").Check(testkit.Rows("rg1 CREATE RESOURCE GROUP `rg1` RU_PER_SEC=1000, PRIORITY=MEDIUM, QUEUE=(MAX_CONCURRENCY=1, MAX_QUEUE_LENGTH=0)"))
+	tk.MustContainErrMsg("create resource group rg2 RU_PER_SEC=1000 QUEUE=(MAX_QUEUE_LENGTH=10)", "invalid max concurrency of the queue")
+	tk.MustContainErrMsg("alter resource group rg1 QUEUE=(MAX_CONCURRENCY=0)", "invalid max concurrency of the queue")
+	tk.MustContainErrMsg("alter resource group rg1 QUEUE=(QUEUE_TIMEOUT='-1s')", "invalid queue timeout")
+	tk.MustContainErrMsg("alter resource group rg1 QUEUE=(QUEUE_TIMEOUT='500us')", "invalid queue timeout")
+	tk.MustContainErrMsg("create resource group rg2 RU_PER_SEC=1000 QUEUE=(MAX_CONCURRENCY=1, QUEUE_TIMEOUT='-10ms')", "invalid queue timeout")
+	tk.MustExec("alter resource group rg1 QUEUE=(MAX_QUEUE_LENGTH=0, QUEUE_TIMEOUT='1s')")
+	tk.MustQuery("show create resource group rg1").Check(testkit.Rows("rg1 CREATE RESOURCE GROUP `rg1` RU_PER_SEC=1000, PRIORITY=MEDIUM, QUEUE=(MAX_CONCURRENCY=1, MAX_QUEUE_LENGTH=0, QUEUE_TIMEOUT='1s')"))
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	runSleep := func(tk *testkit.TestKit, seconds int) <-chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			tk.MustQuery(fmt.Sprintf("select /*+ resource_group(rg1) */ sleep(%d)", seconds)).Check(testkit.Rows("0"))
+		}()
+		return done
+	}
+	waitProcess := func(state string) {
+		require.Eventually(t, func() bool {
+			rows := tk.MustQuery("select count(*) from information_schema.processlist where info like 'select /*+ resource_group(rg1) */ sleep%' and state like ?", "%"+state+"%").Rows()
+			return rows[0][0] == "1"
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// the queue is full
+	done := runSleep(tk1, 1)
+	waitProcess("")
+	tk2.MustGetErrCode("select /*+ resource_group(rg1) */ 1", mysql.ErrResourceGroupQueueFull)
+	<-done
+
+	// the statement times out in the queue
+	tk.MustExec("alter resource group rg1 QUEUE=(MAX_QUEUE_LENGTH=1, QUEUE_TIMEOUT='100ms')")
+	done = runSleep(tk1, 1)
+	waitProcess("")
+	tk2.MustGetErrCode("select /*+ resource_group(rg1) */ 1", mysql.ErrResourceGroupQueueTimeout)
+	<-done
+
+	// the statement is executed after the running one finishes
+	tk.MustExec("alter resource group rg1 QUEUE=(QUEUE_TIMEOUT='0s')")
+	done = runSleep(tk1, 1)
+	waitProcess("")
+	done2 := runSleep(tk2, 0)
+	waitProcess("waiting in resource group queue")
+	<-done
+	<-done2
+
+	tk.MustExec("alter resource group rg1 QUEUE=NULL")
+	tk.MustQuery("show create resource group rg1").Check(testkit.Rows("rg1 CREATE RESOURCE GROUP `rg1` RU_PER_SEC=1000, PRIORITY=MEDIUM"))
+}
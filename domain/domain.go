@@ -38,6 +38,8 @@ import (
 	"github.com/pingcap/tidb/br/pkg/streamhelper/daemon"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/ddl/intervalpartition"
+	"github.com/pingcap/tidb/ddl/mvrefresh"
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/ddl/schematracker"
 	ddlutil "github.com/pingcap/tidb/ddl/util"
@@ -66,6 +68,9 @@ import (
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/telemetry"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/pingcap/tidb/timer/tablestore"
+	"github.com/pingcap/tidb/timer/usertimer"
 	"github.com/pingcap/tidb/ttl/cache"
 	"github.com/pingcap/tidb/ttl/sqlbuilder"
 	"github.com/pingcap/tidb/ttl/ttlworker"
@@ -164,7 +169,10 @@ type Domain struct {
 	logBackupAdvancer        *daemon.OwnerDaemon
 	historicalStatsWorker    *HistoricalStatsWorker
 	ttlJobManager            atomic.Pointer[ttlworker.JobManager]
+	userTimerStore           atomic.Pointer[timerapi.TimerStore]
+	intervalPartTimerStore   atomic.Pointer[timerapi.TimerStore]
 	runawayManager           *resourcegroup.RunawayManager
+	admissionController      *resourcegroup.AdmissionController
 	resourceGroupsController *rmclient.ResourceGroupsController
 
 	serverID             uint64
@@ -1046,7 +1054,8 @@ func NewDomain(store kv.Storage, ddlLease time.Duration, statsLease time.Duratio
 			jobsVerMap: make(map[int64]int64),
 			jobsIdsMap: make(map[int64]string),
 		},
-		mdlCheckCh: make(chan struct{}),
+		mdlCheckCh:          make(chan struct{}),
+		admissionController: resourcegroup.NewAdmissionController(),
 	}
 	do.stopAutoAnalyze.Store(false)
 	do.wg = util.NewWaitGroupEnhancedWrapper("domain", do.exit, config.GetGlobalConfig().TiDBEnableExitCheck)
@@ -1450,13 +1459,13 @@ func (do *Domain) execRestrictedSQL(sql string, params []interface{}) ([]chunk.R
 
 func genRunawayQueriesStmt(records []*resourcegroup.RunawayRecord) (string, []interface{}) {
 	var builder strings.Builder
-	params := make([]interface{}, 0, len(records)*7)
+	params := make([]interface{}, 0, len(records)*8)
 	builder.WriteString("insert into mysql.tidb_runaway_queries VALUES ")
 	for count, r := range records {
 		if count > 0 {
 			builder.WriteByte(',')
 		}
-		builder.WriteString("(%?, %?, %?, %?, %?, %?, %?)")
+		builder.WriteString("(%?, %?, %?, %?, %?, %?, %?, %?)")
 		params = append(params, r.ResourceGroupName)
 		params = append(params, r.Time)
 		params = append(params, r.Match)
@@ -1464,6 +1473,7 @@ func genRunawayQueriesStmt(records []*resourcegroup.RunawayRecord) (string, []in
 		params = append(params, r.SQLText)
 		params = append(params, r.PlanDigest)
 		params = append(params, r.From)
+		params = append(params, r.Rule)
 	}
 	return builder.String(), params
 }
@@ -2079,6 +2089,14 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 				if err != nil {
 					logutil.BgLogger().Error("GC bind record failed", zap.Error(err))
 				}
+				retentionDays := int64(variable.DefTiDBEvolvePlanHistoryRetentionDays)
+				if val, err := do.GetGlobalVar(variable.TiDBEvolvePlanHistoryRetentionDays); err == nil {
+					retentionDays = variable.TidbOptInt64(val, retentionDays)
+				}
+				err = do.bindHandle.Load().GCEvolveHistory(retentionDays)
+				if err != nil {
+					logutil.BgLogger().Error("GC evolve history failed", zap.Error(err))
+				}
 			}
 		}
 	}, "globalBindHandleWorkerLoop")
@@ -2197,6 +2215,11 @@ func (do *Domain) RunawayManager() *resourcegroup.RunawayManager {
 	return do.runawayManager
 }
 
+// ResourceGroupAdmissionController returns the admission controller of the resource groups.
+func (do *Domain) ResourceGroupAdmissionController() *resourcegroup.AdmissionController {
+	return do.admissionController
+}
+
 // ResourceGroupsController returns the resource groups controller.
 func (do *Domain) ResourceGroupsController() *rmclient.ResourceGroupsController {
 	return do.resourceGroupsController
@@ -3103,6 +3126,91 @@ func (do *Domain) TTLJobManager() *ttlworker.JobManager {
 	return do.ttlJobManager.Load()
 }
 
+// timerCheckOwnerInterval is the interval to check whether the timer runtimes should run.
+const timerCheckOwnerInterval = 10 * time.Second
+
+// ownerTimerRuntime is a timer runtime which should only run in one TiDB instance of the cluster.
+type ownerTimerRuntime interface {
+	Resume()
+	Pause()
+}
+
+// runTimerRuntimeInOwner creates a store of the timers in mysql.tidb_timers and starts a loop to run the runtime
+// created by `newRuntime` with the store. The runtime is only resumed in the DDL owner to make sure each event
+// is executed in one TiDB instance.
+func (do *Domain) runTimerRuntimeInOwner(name string, newRuntime func(*timerapi.TimerStore) ownerTimerRuntime) *timerapi.TimerStore {
+	var clusterID uint64
+	if pdCli := do.GetPDClient(); pdCli != nil {
+		clusterID = pdCli.GetClusterID(context.Background())
+	}
+
+	store := tablestore.NewTableTimerStore(clusterID, do.sysSessionPool, "mysql", "tidb_timers", do.etcdClient)
+	do.wg.Run(func() {
+		defer func() {
+			store.Close()
+			logutil.BgLogger().Info(name + " exited.")
+		}()
+
+		rt := newRuntime(store)
+		defer rt.Pause()
+
+		ticker := time.NewTicker(timerCheckOwnerInterval)
+		defer ticker.Stop()
+		for {
+			if do.ddl.OwnerManager().IsOwner() {
+				rt.Resume()
+			} else {
+				rt.Pause()
+			}
+
+			select {
+			case <-do.exit:
+				return
+			case <-ticker.C:
+			}
+		}
+	}, name)
+	return store
+}
+
+// StartUserTimers creates the store of the user-defined timers and starts a loop to run them.
+// The timers are only triggered in the DDL owner to make sure each event is executed in one TiDB instance.
+func (do *Domain) StartUserTimers() {
+	store := do.runTimerRuntimeInOwner("userTimerRuntime", func(store *timerapi.TimerStore) ownerTimerRuntime {
+		return usertimer.NewRuntime(store, do.sysSessionPool, do.PrivilegeHandle)
+	})
+	do.userTimerStore.Store(store)
+}
+
+// UserTimerStore returns the store of the user-defined timers.
+// It returns nil if StartUserTimers is not called.
+func (do *Domain) UserTimerStore() *timerapi.TimerStore {
+	return do.userTimerStore.Load()
+}
+
+// StartIntervalPartitionWorker creates the timer store and starts a loop to maintain the partitions of
+// the tables with interval partition policies. The partitions are only maintained in the DDL owner.
+func (do *Domain) StartIntervalPartitionWorker() {
+	store := do.runTimerRuntimeInOwner("intervalPartitionRuntime", func(store *timerapi.TimerStore) ownerTimerRuntime {
+		return intervalpartition.NewRuntime(store, do.sysSessionPool, do.InfoSchema)
+	})
+	do.intervalPartTimerStore.Store(store)
+}
+
+// StartMaterializedViewRefreshWorker creates the timer store and starts a loop to refresh the materialized
+// views by their refresh schedules. The views are only refreshed in the DDL owner.
+func (do *Domain) StartMaterializedViewRefreshWorker() {
+	do.runTimerRuntimeInOwner("materializedViewRefreshRuntime", func(store *timerapi.TimerStore) ownerTimerRuntime {
+		return mvrefresh.NewRuntime(store, do.sysSessionPool, do.InfoSchema)
+	})
+}
+
+// IntervalPartitionTimerStore returns the store of the timer maintaining the interval partitions.
+// It returns nil if StartIntervalPartitionWorker is not called.
+func (do *Domain) IntervalPartitionTimerStore() *timerapi.TimerStore {
+	return do.intervalPartTimerStore.Load()
+}
+
 // StopAutoAnalyze stops (*Domain).autoAnalyzeWorker to launch new auto analyze jobs.
 func (do *Domain) StopAutoAnalyze() {
 	do.stopAutoAnalyze.Store(true)
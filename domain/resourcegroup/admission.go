@@ -0,0 +1,180 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
+)
+
+// killCheckInterval is the interval to check whether a waiting statement is killed.
+const killCheckInterval = 100 * time.Millisecond
+
+// admissionWaiter is a statement waiting in the admission queue of a resource group.
+type admissionWaiter struct {
+	ready    chan struct{}
+	admitted bool
+}
+
+// admissionQueue is the admission queue of a resource group.
+type admissionQueue struct {
+	maxConcurrency int
+	running        int
+	waiters        *list.List
+}
+
+// AdmissionController limits the number of statements executed concurrently in each resource group.
+// The statements exceeding the max concurrency of the resource group wait in its queue in the FIFO
+// order, until they're admitted, the queue times out or they're killed.
+type AdmissionController struct {
+	mu     sync.Mutex
+	queues map[string]*admissionQueue
+}
+
+// NewAdmissionController creates a new AdmissionController.
+func NewAdmissionController() *AdmissionController {
+	return &AdmissionController{queues: make(map[string]*admissionQueue)}
+}
+
+// Admit waits until the statement is allowed to be executed in the resource group.
+// `killed` reports whether the statement is killed, and `onWait` is called with true when the
+// statement starts waiting in the queue and with false when it stops waiting.
+// The returned function must be called after the statement finishes if the error is nil.
+func (c *AdmissionController) Admit(ctx context.Context, groupName string, settings *model.ResourceGroupQueueSettings,
+	killed func() bool, onWait func(waiting bool)) (release func(), err error) {
+	if settings == nil || settings.MaxConcurrency == 0 {
+		return func() {}, nil
+	}
+
+	c.mu.Lock()
+	q, ok := c.queues[groupName]
+	if !ok {
+		q = &admissionQueue{waiters: list.New()}
+		c.queues[groupName] = q
+	}
+	// The settings may be changed by ALTER RESOURCE GROUP, always use the latest one, and admit the
+	// waiters allowed by a raised max concurrency before the statement.
+	q.maxConcurrency = int(settings.MaxConcurrency)
+	q.admitWaitersLocked()
+	if q.running < q.maxConcurrency && q.waiters.Len() == 0 {
+		q.running++
+		c.mu.Unlock()
+		return c.releaseFunc(groupName), nil
+	}
+	if uint64(q.waiters.Len()) >= settings.MaxQueueLength {
+		c.mu.Unlock()
+		metrics.ResourceGroupQueueRejectCounter.WithLabelValues(groupName, metrics.LblQueueFull).Inc()
+		return nil, exeerrors.ErrResourceGroupQueueFull.GenWithStackByArgs(groupName)
+	}
+	w := &admissionWaiter{ready: make(chan struct{})}
+	elem := q.waiters.PushBack(w)
+	c.mu.Unlock()
+
+	start := time.Now()
+	metrics.ResourceGroupQueueWaitingGauge.WithLabelValues(groupName).Inc()
+	onWait(true)
+	defer func() {
+		onWait(false)
+		metrics.ResourceGroupQueueWaitingGauge.WithLabelValues(groupName).Dec()
+		metrics.ResourceGroupQueueWaitDuration.WithLabelValues(groupName).Observe(time.Since(start).Seconds())
+	}()
+
+	var timeout <-chan time.Time
+	if settings.QueueTimeoutMs > 0 {
+		timer := time.NewTimer(time.Duration(settings.QueueTimeoutMs) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	ticker := time.NewTicker(killCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ready:
+			return c.releaseFunc(groupName), nil
+		case <-timeout:
+			err = exeerrors.ErrResourceGroupQueueTimeout.GenWithStackByArgs(time.Since(start).Round(time.Millisecond).String(), groupName)
+			metrics.ResourceGroupQueueRejectCounter.WithLabelValues(groupName, metrics.LblQueueTimeout).Inc()
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-ticker.C:
+			if !killed() {
+				continue
+			}
+			err = exeerrors.ErrQueryInterrupted
+		}
+		if c.cancelWaiter(groupName, q, elem) {
+			// The statement has been admitted just before it stops waiting.
+			return c.releaseFunc(groupName), nil
+		}
+		return nil, err
+	}
+}
+
+// cancelWaiter removes the waiter from the queue. It returns true if the waiter has been admitted.
+func (c *AdmissionController) cancelWaiter(groupName string, q *admissionQueue, elem *list.Element) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := elem.Value.(*admissionWaiter)
+	if w.admitted {
+		return true
+	}
+	q.waiters.Remove(elem)
+	c.cleanQueueLocked(groupName, q)
+	return false
+}
+
+func (c *AdmissionController) releaseFunc(groupName string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.release(groupName)
+		})
+	}
+}
+
+// release hands over the slot of a finished statement to the waiters in the queue.
+func (c *AdmissionController) release(groupName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	q, ok := c.queues[groupName]
+	if !ok {
+		return
+	}
+	q.running--
+	q.admitWaitersLocked()
+	c.cleanQueueLocked(groupName, q)
+}
+
+// admitWaitersLocked admits the waiters in the FIFO order until the max concurrency is reached.
+func (q *admissionQueue) admitWaitersLocked() {
+	for q.running < q.maxConcurrency && q.waiters.Len() > 0 {
+		w := q.waiters.Remove(q.waiters.Front()).(*admissionWaiter)
+		w.admitted = true
+		close(w.ready)
+		q.running++
+	}
+}
+
+func (c *AdmissionController) cleanQueueLocked(groupName string, q *admissionQueue) {
+	if q.running <= 0 && q.waiters.Len() == 0 {
+		delete(c.queues, groupName)
+	}
+}
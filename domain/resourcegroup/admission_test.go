@@ -0,0 +1,74 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionRaiseMaxConcurrency(t *testing.T) {
+	c := NewAdmissionController()
+	settings := &model.ResourceGroupQueueSettings{MaxConcurrency: 1, MaxQueueLength: 10}
+	notKilled := func() bool { return false }
+	noWait := func(bool) {}
+
+	release1, err := c.Admit(context.Background(), "rg1", settings, notKilled, noWait)
+	require.NoError(t, err)
+
+	type result struct {
+		release func()
+		err     error
+	}
+	waiting := make(chan struct{})
+	admitted := make(chan result, 1)
+	go func() {
+		release, err := c.Admit(context.Background(), "rg1", settings, notKilled, func(w bool) {
+			if w {
+				close(waiting)
+			}
+		})
+		admitted <- result{release, err}
+	}()
+	<-waiting
+
+	// The waiter is admitted when the max concurrency is raised, before the new statement.
+	raised := &model.ResourceGroupQueueSettings{MaxConcurrency: 3, MaxQueueLength: 10}
+	release3, err := c.Admit(context.Background(), "rg1", raised, notKilled, noWait)
+	require.NoError(t, err)
+	var res result
+	select {
+	case res = <-admitted:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the waiter isn't admitted")
+	}
+	require.NoError(t, res.err)
+
+	c.mu.Lock()
+	require.Equal(t, 3, c.queues["rg1"].running)
+	require.Equal(t, 0, c.queues["rg1"].waiters.Len())
+	c.mu.Unlock()
+
+	release1()
+	res.release()
+	release3()
+	c.mu.Lock()
+	require.Empty(t, c.queues)
+	c.mu.Unlock()
+}
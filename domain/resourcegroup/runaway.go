@@ -15,17 +15,22 @@
 package resourcegroup
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/pingcap/kvproto/pkg/coprocessor"
 	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/tikvrpc"
+	clientutil "github.com/tikv/client-go/v2/util"
 	rmclient "github.com/tikv/pd/client/resource_group/controller"
 	"go.uber.org/zap"
 )
@@ -39,6 +44,15 @@ const (
 	maxWatchRecordChannelSize = 1024
 )
 
+// The names of the runaway rules.
+const (
+	RunawayRuleExecElapsed   = "EXEC_ELAPSED"
+	RunawayRuleProcessedKeys = "PROCESSED_KEYS"
+	RunawayRuleRequestUnit   = "RU"
+	RunawayRuleResultRows    = "RESULT_ROWS"
+	RunawayRuleMemory        = "MEMORY"
+)
+
 // RunawayMatchType is used to indicates whether qurey was interrupted by runaway identification or quarantine watch.
 type RunawayMatchType uint
 
@@ -69,6 +83,8 @@ type RunawayRecord struct {
 	SQLText           string
 	PlanDigest        string
 	From              string
+	// Rule is the rule matched by the query, it's empty if the query is matched by the watch list.
+	Rule string
 }
 
 // QuarantineRecord is used to save records which will be insert into mysql.tidb_runaway_quarantined_watch.
@@ -104,8 +120,10 @@ func NewRunawayManager(resourceGroupCtl *rmclient.ResourceGroupsController, serv
 	}
 }
 
-// DeriveChecker derives a RunawayChecker from the given resource group
-func (rm *RunawayManager) DeriveChecker(resourceGroupName string, originalSQL string, planDigest string) *RunawayChecker {
+// DeriveChecker derives a RunawayChecker from the given resource group.
+// `rules` is the runaway settings of the resource group stored in TiDB, it's used to get the rules
+// unknown by the resource manager, and it may be nil.
+func (rm *RunawayManager) DeriveChecker(resourceGroupName string, originalSQL string, planDigest string, rules *model.ResourceGroupRunawaySettings) *RunawayChecker {
 	group, err := rm.resourceGroupCtl.GetResourceGroup(resourceGroupName)
 	if err != nil || group == nil {
 		logutil.BgLogger().Warn("cannot setup up runaway checker", zap.Error(err))
@@ -114,7 +132,7 @@ func (rm *RunawayManager) DeriveChecker(resourceGroupName string, originalSQL st
 	if group.RunawaySettings == nil {
 		return nil
 	}
-	return newRunawayChecker(rm, resourceGroupName, group.RunawaySettings, originalSQL, planDigest)
+	return newRunawayChecker(rm, resourceGroupName, group.RunawaySettings, rules, originalSQL, planDigest)
 }
 
 func (rm *RunawayManager) markQuarantine(resourceGroupName, convict, watchType string, ttl time.Duration, action string, now *time.Time) {
@@ -139,7 +157,7 @@ func (rm *RunawayManager) markQuarantine(resourceGroupName, convict, watchType s
 		// TODO: add warning for discard flush records
 	}
 }
-func (rm *RunawayManager) markRunaway(resourceGroupName, originalSQL, planDigest string, action string, matchType RunawayMatchType, now *time.Time) {
+func (rm *RunawayManager) markRunaway(resourceGroupName, originalSQL, planDigest string, action string, matchType RunawayMatchType, rule string, now *time.Time) {
 	select {
 	case rm.runawayQueriesChan <- &RunawayRecord{
 		ResourceGroupName: resourceGroupName,
@@ -149,6 +167,7 @@ func (rm *RunawayManager) markRunaway(resourceGroupName, originalSQL, planDigest
 		SQLText:           originalSQL,
 		PlanDigest:        planDigest,
 		From:              rm.serverID,
+		Rule:              rule,
 	}:
 	default:
 		// TODO: add warning for discard flush records
@@ -189,21 +208,39 @@ type RunawayChecker struct {
 	originalSQL       string
 	planDigest        string
 
+	// deadline is zero if there is no EXEC_ELAPSED rule.
 	deadline time.Time
 	setting  *rmpb.RunawaySettings
-	action   string
+	// rules is the runaway settings stored in TiDB, which contains the rules unknown by the resource manager.
+	rules  *model.ResourceGroupRunawaySettings
+	action string
 
 	marked atomic.Bool
+	// killedByMemory indicates the query is killed by the MEMORY rule through the statement memory tracker.
+	killedByMemory atomic.Bool
+
+	// processedKeys is the number of the keys processed by the coprocessor requests of the query.
+	processedKeys atomic.Uint64
+	// ruStats are the RU consumed by the KV requests with the timestamps of the query, which are collected
+	// by the KV client. They may be shared by the statements of a transaction, so ruBase is the RU consumed
+	// before the query.
+	ruStats []*clientutil.RURuntimeStats
+	ruBase  float64
 }
 
-func newRunawayChecker(manager *RunawayManager, resourceGroupName string, setting *rmpb.RunawaySettings, originalSQL string, planDigest string) *RunawayChecker {
+func newRunawayChecker(manager *RunawayManager, resourceGroupName string, setting *rmpb.RunawaySettings, rules *model.ResourceGroupRunawaySettings, originalSQL string, planDigest string) *RunawayChecker {
+	var deadline time.Time
+	if setting.Rule.GetExecElapsedTimeMs() > 0 {
+		deadline = time.Now().Add(time.Duration(setting.Rule.ExecElapsedTimeMs) * time.Millisecond)
+	}
 	return &RunawayChecker{
 		manager:           manager,
 		resourceGroupName: resourceGroupName,
 		originalSQL:       originalSQL,
 		planDigest:        planDigest,
-		deadline:          time.Now().Add(time.Duration(setting.Rule.ExecElapsedTimeMs) * time.Millisecond),
+		deadline:          deadline,
 		setting:           setting,
+		rules:             rules,
 		marked:            atomic.Bool{},
 		action:            strings.ToLower(setting.Action.String()),
 	}
@@ -219,7 +256,7 @@ func (r *RunawayChecker) BeforeExecutor() error {
 		r.marked.Store(result)
 		if result {
 			now := time.Now()
-			r.markRunaway(RunawayMatchTypeWatch, &now)
+			r.markRunaway(RunawayMatchTypeWatch, "", &now)
 		}
 		switch r.setting.Action {
 		case rmpb.RunawayAction_Kill:
@@ -238,22 +275,19 @@ func (r *RunawayChecker) BeforeExecutor() error {
 func (r *RunawayChecker) BeforeCopRequest(req *tikvrpc.Request) error {
 	marked := r.marked.Load()
 	if !marked {
-		until := time.Until(r.deadline)
-		if until > 0 {
-			if r.setting.Action == rmpb.RunawayAction_Kill {
+		now := time.Now()
+		rule := r.matchCopRules(now)
+		if rule == "" {
+			if !r.deadline.IsZero() && r.setting.Action == rmpb.RunawayAction_Kill {
 				// if the execution time is close to the threshold, set a timeout
-				if until < tikv.ReadTimeoutMedium {
+				if until := r.deadline.Sub(now); until < tikv.ReadTimeoutMedium {
 					req.Context.MaxExecutionDurationMs = uint64(until.Milliseconds())
 				}
 			}
 			return nil
 		}
-		// execution time exceeds the threshold, mark the query as runaway
-		if r.marked.CompareAndSwap(false, true) {
-			now := time.Now()
-			r.markRunaway(RunawayMatchTypeIdentify, &now)
-			r.markQuarantine(&now)
-		}
+		// a rule is matched, mark the query as runaway
+		r.identify(rule, &now)
 	}
 	switch r.setting.Action {
 	case rmpb.RunawayAction_Kill:
@@ -269,18 +303,106 @@ func (r *RunawayChecker) BeforeCopRequest(req *tikvrpc.Request) error {
 }
 
 // AfterCopRequest checks runaway after receiving coprocessor response.
-func (r *RunawayChecker) AfterCopRequest() {
+func (r *RunawayChecker) AfterCopRequest(resp *coprocessor.Response) {
+	if scanDetail := resp.GetExecDetailsV2().GetScanDetailV2(); scanDetail != nil {
+		r.processedKeys.Add(scanDetail.GetProcessedVersions())
+	}
 	// Do not perform action here as it may be the last cop request and just let it finish. If it's not the last cop request, action would be performed in `BeforeCopRequest` when handling the next cop request.
 	// Here only marks the query as runaway
-	if !r.marked.Load() && r.deadline.Before(time.Now()) {
-		if r.marked.CompareAndSwap(false, true) {
-			now := time.Now()
-			r.markRunaway(RunawayMatchTypeIdentify, &now)
-			r.markQuarantine(&now)
+	if !r.marked.Load() {
+		now := time.Now()
+		if rule := r.matchCopRules(now); rule != "" {
+			r.identify(rule, &now)
 		}
 	}
 }
 
+// CheckResult checks the RESULT_ROWS rule on the result rows of the query during execution.
+// It returns an error if the query should be killed.
+func (r *RunawayChecker) CheckResult(resultRows uint64) error {
+	if r == nil || r.rules == nil || r.rules.ResultRows == 0 || resultRows <= r.rules.ResultRows || r.marked.Load() {
+		return nil
+	}
+	now := time.Now()
+	if !r.identify(fmt.Sprintf("%s=%d", RunawayRuleResultRows, r.rules.ResultRows), &now) {
+		return nil
+	}
+	if r.setting.Action == rmpb.RunawayAction_Kill {
+		return exeerrors.ErrResourceGroupQueryRunawayInterrupted
+	}
+	return nil
+}
+
+// TrackMemory checks the MEMORY rule by the statement memory tracker of the query, so it's checked whenever
+// the memory is consumed by any executor, including the DML executors which return no rows. The rule is set
+// as the bytes limit of the tracker, and the query is identified as runaway by the action of the tracker when
+// the limit is exceeded. If the action of the rule is KILL, the query is killed by setting `killed`, which is
+// the kill flag of the session.
+func (r *RunawayChecker) TrackMemory(tracker *memory.Tracker, killed *uint32) {
+	if r == nil || r.rules == nil || r.rules.MemoryBytes == 0 || tracker == nil {
+		return
+	}
+	tracker.SetBytesLimit(int64(r.rules.MemoryBytes))
+	tracker.SetActionOnExceed(&runawayMemoryAction{checker: r, killed: killed})
+}
+
+// KilledByMemory checks whether the query is killed by the MEMORY rule, then the error caused by the killing
+// should be replaced by the runaway error.
+func (r *RunawayChecker) KilledByMemory() bool {
+	return r != nil && r.killedByMemory.Load()
+}
+
+// SetRUStats sets the RU consumed by the KV requests with the timestamps of the query, which are used to check
+// the RU rule. The RU consumed by the requests before the query are excluded.
+func (r *RunawayChecker) SetRUStats(stats ...*clientutil.RURuntimeStats) {
+	if r == nil {
+		return
+	}
+	r.ruStats = stats
+	r.ruBase = r.consumedRU()
+}
+
+// NeedRUStats checks whether the RU rule is set, then the RU consumed by the query should be collected.
+func (r *RunawayChecker) NeedRUStats() bool {
+	return r != nil && r.rules != nil && r.rules.RequestUnit > 0
+}
+
+// matchCopRules returns the matched rule which is checked on the coprocessor requests, or empty if no rule is matched.
+func (r *RunawayChecker) matchCopRules(now time.Time) string {
+	if !r.deadline.IsZero() && !now.Before(r.deadline) {
+		return fmt.Sprintf("%s=%s", RunawayRuleExecElapsed, time.Duration(r.setting.Rule.ExecElapsedTimeMs)*time.Millisecond)
+	}
+	if r.rules == nil {
+		return ""
+	}
+	if r.rules.ProcessedKeys > 0 && r.processedKeys.Load() > r.rules.ProcessedKeys {
+		return fmt.Sprintf("%s=%d", RunawayRuleProcessedKeys, r.rules.ProcessedKeys)
+	}
+	if r.rules.RequestUnit > 0 && r.consumedRU() > float64(r.rules.RequestUnit) {
+		return fmt.Sprintf("%s=%d", RunawayRuleRequestUnit, r.rules.RequestUnit)
+	}
+	return ""
+}
+
+// consumedRU returns the RU consumed by the KV requests of the query.
+func (r *RunawayChecker) consumedRU() float64 {
+	var ru float64
+	for _, stats := range r.ruStats {
+		ru += stats.RRU() + stats.WRU()
+	}
+	return ru - r.ruBase
+}
+
+// identify marks the query as runaway identified by the rule. It returns false if the query has been marked.
+func (r *RunawayChecker) identify(rule string, now *time.Time) bool {
+	if !r.marked.CompareAndSwap(false, true) {
+		return false
+	}
+	r.markRunaway(RunawayMatchTypeIdentify, rule, now)
+	r.markQuarantine(now)
+	return true
+}
+
 func (r *RunawayChecker) markQuarantine(now *time.Time) {
 	if r.setting.Watch == nil {
 		return
@@ -291,8 +413,8 @@ func (r *RunawayChecker) markQuarantine(now *time.Time) {
 	r.manager.markQuarantine(r.resourceGroupName, r.getConvictIdentifier(), watchType, ttl, r.action, now)
 }
 
-func (r *RunawayChecker) markRunaway(matchType RunawayMatchType, now *time.Time) {
-	r.manager.markRunaway(r.resourceGroupName, r.originalSQL, r.planDigest, r.action, matchType, now)
+func (r *RunawayChecker) markRunaway(matchType RunawayMatchType, rule string, now *time.Time) {
+	r.manager.markRunaway(r.resourceGroupName, r.originalSQL, r.planDigest, r.action, matchType, rule, now)
 }
 
 func (r *RunawayChecker) getConvictIdentifier() string {
@@ -308,3 +430,30 @@ func (r *RunawayChecker) getConvictIdentifier() string {
 		return ""
 	}
 }
+
+// runawayMemoryAction is the action of the statement memory tracker which checks the MEMORY rule.
+type runawayMemoryAction struct {
+	memory.BaseOOMAction
+	checker *RunawayChecker
+	killed  *uint32
+}
+
+// Action implements the memory.ActionOnExceed interface.
+func (a *runawayMemoryAction) Action(*memory.Tracker) {
+	// The query is identified only once.
+	a.SetFinished()
+	r := a.checker
+	now := time.Now()
+	if !r.identify(fmt.Sprintf("%s=%d", RunawayRuleMemory, r.rules.MemoryBytes), &now) {
+		return
+	}
+	if r.setting.Action == rmpb.RunawayAction_Kill {
+		r.killedByMemory.Store(true)
+		atomic.StoreUint32(a.killed, 1)
+	}
+}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (*runawayMemoryAction) GetPriority() int64 {
+	return memory.DefLogPriority
+}
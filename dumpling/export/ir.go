@@ -28,6 +28,7 @@ type TableMeta interface {
 	ColumnCount() uint
 	ColumnTypes() []string
 	ColumnNames() []string
+	ColumnDecimalSize(i int) (precision, scale int64, ok bool)
 	SelectedField() string
 	SelectedLen() int
 	SpecialComments() StringIter
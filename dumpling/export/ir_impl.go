@@ -283,6 +283,10 @@ func (tm *tableMeta) ColumnNames() []string {
 	return colNames
 }
 
+func (tm *tableMeta) ColumnDecimalSize(i int) (precision, scale int64, ok bool) {
+	return tm.colTypes[i].DecimalSize()
+}
+
 func (tm *tableMeta) DatabaseName() string {
 	return tm.database
 }
@@ -66,6 +66,7 @@ type mockTableIR struct {
 	specCmt          []string
 	colTypes         []string
 	colNames         []string
+	colDecimalSizes  map[int][2]int64
 	escapeBackSlash  bool
 	hasImplicitRowID bool
 	rowErr           error
@@ -121,6 +122,11 @@ func (m *mockTableIR) ColumnNames() []string {
 	return m.colNames
 }
 
+func (m *mockTableIR) ColumnDecimalSize(i int) (precision, scale int64, ok bool) {
+	size, ok := m.colDecimalSizes[i]
+	return size[0], size[1], ok
+}
+
 func (m *mockTableIR) SelectedField() string {
 	return m.selectedField
 }
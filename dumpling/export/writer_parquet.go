@@ -0,0 +1,476 @@
+// Copyright 2023 PingCAP, Inc. Licensed under Apache-2.0.
+
+package export
+
+import (
+	"database/sql"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/br/pkg/summary"
+	tcontext "github.com/pingcap/tidb/dumpling/context"
+	"github.com/pingcap/tidb/dumpling/log"
+	"github.com/xitongsys/parquet-go/marshal"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+	"go.uber.org/zap"
+)
+
+const (
+	// parquetRowGroupSize is the max size of the row groups buffered in memory
+	// before flushing to the file.
+	parquetRowGroupSize = 128 * 1024 * 1024
+	// mysqlMaxDecimalPrecision is the max precision of DECIMAL in MySQL/TiDB.
+	mysqlMaxDecimalPrecision = 65
+
+	parquetDateLayout     = "2006-01-02"
+	parquetDatetimeLayout = "2006-01-02 15:04:05.999999999"
+)
+
+// parquetColumn describes how a column is stored in the parquet file.
+type parquetColumn struct {
+	schema *parquet.SchemaElement
+	// convert converts the text value returned by the server to the value of
+	// the parquet physical type. The value is never NULL.
+	convert func(v []byte) (interface{}, error)
+}
+
+// parquetFileWriter adapts storage.ExternalFileWriter to io.Writer.
+type parquetFileWriter struct {
+	tctx    *tcontext.Context
+	w       storage.ExternalFileWriter
+	written uint64
+}
+
+// Write implements io.Writer.
+func (w *parquetFileWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(w.tctx, p)
+	w.written += uint64(n)
+	return n, err
+}
+
+// WriteInsertInParquet writes TableDataIR to a storage.ExternalFileWriter in parquet type
+func WriteInsertInParquet(
+	pCtx *tcontext.Context,
+	cfg *Config,
+	meta TableMeta,
+	tblIR TableDataIR,
+	w storage.ExternalFileWriter,
+	metrics *metrics,
+) (n uint64, err error) {
+	fileRowIter := tblIR.Rows()
+	if !fileRowIter.HasNext() {
+		return 0, fileRowIter.Error()
+	}
+	if meta.SelectedField() == "" {
+		return 0, errors.Errorf("can't dump table %s.%s without any selected column in parquet file format",
+			meta.DatabaseName(), meta.TableName())
+	}
+
+	columns, err := buildParquetColumns(meta)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	schemaElements := make([]*parquet.SchemaElement, 0, len(columns)+1)
+	numChildren := int32(len(columns))
+	schemaElements = append(schemaElements, &parquet.SchemaElement{
+		Name:           "schema",
+		RepetitionType: parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_REQUIRED),
+		NumChildren:    &numChildren,
+	})
+	for _, col := range columns {
+		schemaElements = append(schemaElements, col.schema)
+	}
+
+	fw := &parquetFileWriter{tctx: pCtx, w: w}
+	pw, err := writer.NewParquetWriterFromWriter(fw, schemaElements, 1)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	// the rows are passed as []interface{} of the physical values.
+	pw.MarshalFunc = marshal.MarshalCSV
+	pw.CompressionType = parquetCompressionCodec(cfg.CompressType)
+	pw.RowGroupSize = parquetRowGroupSize
+	if cfg.FileSize != UnspecifiedSize && cfg.FileSize < parquetRowGroupSize {
+		pw.RowGroupSize = int64(cfg.FileSize)
+	}
+
+	var (
+		row         = MakeRowReceiver(meta.ColumnTypes())
+		counter     uint64
+		lastCounter uint64
+		// currentFileSize is the size of the text values, it's used to
+		// decide when to switch to the next file like sql/csv do.
+		currentFileSize uint64
+		lastFileSize    uint64
+	)
+
+	defer func() {
+		if err != nil {
+			pCtx.L().Warn("fail to dumping table(chunk), will revert some metrics and start a retry if possible",
+				zap.String("database", meta.DatabaseName()),
+				zap.String("table", meta.TableName()),
+				zap.Uint64("finished rows", lastCounter),
+				zap.Uint64("finished size", fw.written),
+				log.ShortError(err))
+			SubGauge(metrics.finishedRowsGauge, float64(lastCounter))
+		} else {
+			pCtx.L().Debug("finish dumping table(chunk)",
+				zap.String("database", meta.DatabaseName()),
+				zap.String("table", meta.TableName()),
+				zap.Uint64("finished rows", counter),
+				zap.Uint64("finished size", fw.written))
+			AddGauge(metrics.finishedSizeGauge, float64(fw.written))
+			summary.CollectSuccessUnit(summary.TotalBytes, 1, fw.written)
+			summary.CollectSuccessUnit("total rows", 1, counter)
+		}
+	}()
+
+	for fileRowIter.HasNext() {
+		if err = fileRowIter.Decode(row); err != nil {
+			return counter, errors.Trace(err)
+		}
+		record := make([]interface{}, len(columns))
+		for i, receiver := range row.receivers {
+			v := rawBytesOfReceiver(receiver)
+			if v == nil {
+				continue
+			}
+			if record[i], err = columns[i].convert(v); err != nil {
+				return counter, errors.Annotatef(err, "fail to convert value of column %s in table %s.%s",
+					columns[i].schema.Name, meta.DatabaseName(), meta.TableName())
+			}
+			currentFileSize += uint64(len(v))
+		}
+		if err = pw.Write(record); err != nil {
+			return counter, newWriterError(err)
+		}
+		counter++
+		failpoint.Inject("ChaosBrokenWriterConn", func(_ failpoint.Value) {
+			failpoint.Return(0, errors.New("connection is closed"))
+		})
+		failpoint.Inject("AtEveryRow", nil)
+
+		if currentFileSize-lastFileSize >= lengthLimit {
+			select {
+			case <-pCtx.Done():
+				return counter, pCtx.Err()
+			default:
+			}
+			AddGauge(metrics.finishedRowsGauge, float64(counter-lastCounter))
+			lastCounter = counter
+			lastFileSize = currentFileSize
+		}
+
+		fileRowIter.Next()
+		if cfg.FileSize != UnspecifiedSize && currentFileSize >= cfg.FileSize {
+			break
+		}
+	}
+	if err = fileRowIter.Error(); err != nil {
+		return counter, errors.Trace(err)
+	}
+	if err = pw.WriteStop(); err != nil {
+		return counter, newWriterError(err)
+	}
+	AddGauge(metrics.finishedRowsGauge, float64(counter-lastCounter))
+	lastCounter = counter
+	return counter, nil
+}
+
+// parquetCompressionCodec returns the codec used to compress the pages of the
+// parquet file. The parquet file is never compressed as a whole, otherwise it
+// can't be read by the readers which need to seek in it.
+func parquetCompressionCodec(compressType storage.CompressType) parquet.CompressionCodec {
+	switch compressType {
+	case storage.Gzip:
+		return parquet.CompressionCodec_GZIP
+	case storage.Snappy:
+		return parquet.CompressionCodec_SNAPPY
+	case storage.Zstd:
+		return parquet.CompressionCodec_ZSTD
+	default:
+		return parquet.CompressionCodec_UNCOMPRESSED
+	}
+}
+
+func rawBytesOfReceiver(r RowReceiverStringer) sql.RawBytes {
+	switch x := r.(type) {
+	case *SQLTypeString:
+		return x.RawBytes
+	case *SQLTypeNumber:
+		return x.RawBytes
+	case *SQLTypeBytes:
+		return x.RawBytes
+	}
+	return nil
+}
+
+// buildParquetColumns maps the column types to the parquet types.
+//
+// See: https://github.com/apache/parquet-format/blob/master/LogicalTypes.md
+func buildParquetColumns(meta TableMeta) ([]parquetColumn, error) {
+	colTypes := meta.ColumnTypes()
+	colNames := meta.ColumnNames()
+	if len(colNames) != len(colTypes) {
+		return nil, errors.Errorf("the count of column names %d doesn't match the count of column types %d",
+			len(colNames), len(colTypes))
+	}
+	columns := make([]parquetColumn, 0, len(colTypes))
+	for i, tp := range colTypes {
+		se := &parquet.SchemaElement{
+			Name:           colNames[i],
+			RepetitionType: parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_OPTIONAL),
+		}
+		col := parquetColumn{schema: se}
+		switch tp {
+		case "TINYINT", "INT1":
+			setParquetInt(&col, 8, true)
+		case "SMALLINT", "INT2", "YEAR", "SQL_TSI_YEAR":
+			setParquetInt(&col, 16, true)
+		case "MEDIUMINT", "INT3", "INT", "INTEGER":
+			setParquetInt(&col, 32, true)
+		case "BIGINT", "INT8":
+			setParquetInt(&col, 64, true)
+		case "UNSIGNED TINYINT":
+			setParquetInt(&col, 8, false)
+		case "UNSIGNED SMALLINT":
+			setParquetInt(&col, 16, false)
+		case "UNSIGNED INT":
+			setParquetInt(&col, 32, false)
+		case "UNSIGNED BIGINT":
+			setParquetInt(&col, 64, false)
+		case "FLOAT", "REAL":
+			se.Type = parquet.TypePtr(parquet.Type_FLOAT)
+			col.convert = func(v []byte) (interface{}, error) {
+				f, err := strconv.ParseFloat(string(v), 32)
+				return float32(f), err
+			}
+		case "DOUBLE", "DOUBLE PRECISION":
+			se.Type = parquet.TypePtr(parquet.Type_DOUBLE)
+			col.convert = func(v []byte) (interface{}, error) {
+				return strconv.ParseFloat(string(v), 64)
+			}
+		case "DECIMAL", "NUMERIC", "FIXED":
+			precision, scale, ok := meta.ColumnDecimalSize(i)
+			if !ok {
+				// we don't know the scale, keep the text to avoid losing precision.
+				setParquetString(&col)
+				break
+			}
+			setParquetDecimal(&col, precision, scale)
+		case "DATE":
+			se.Type = parquet.TypePtr(parquet.Type_INT32)
+			se.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_DATE)
+			se.LogicalType = &parquet.LogicalType{DATE: parquet.NewDateType()}
+			col.convert = func(v []byte) (interface{}, error) {
+				t, err := time.Parse(parquetDateLayout, string(v))
+				if err != nil {
+					return nil, errors.Errorf("invalid date '%s' can't be written to parquet file", v)
+				}
+				return int32(t.Unix() / 86400), nil
+			}
+		case "DATETIME", "TIMESTAMP":
+			// the values of TIMESTAMP are in the session time zone, like
+			// the ones written to sql/csv files. So both DATETIME and
+			// TIMESTAMP are stored as the local time, i.e. not adjusted to UTC.
+			se.Type = parquet.TypePtr(parquet.Type_INT64)
+			se.LogicalType = &parquet.LogicalType{TIMESTAMP: &parquet.TimestampType{
+				IsAdjustedToUTC: false,
+				Unit:            &parquet.TimeUnit{MICROS: parquet.NewMicroSeconds()},
+			}}
+			col.convert = func(v []byte) (interface{}, error) {
+				t, err := time.Parse(parquetDatetimeLayout, string(v))
+				if err != nil {
+					return nil, errors.Errorf("invalid datetime '%s' can't be written to parquet file", v)
+				}
+				return t.UnixMicro(), nil
+			}
+		case "JSON":
+			se.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+			se.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_JSON)
+			se.LogicalType = &parquet.LogicalType{JSON: parquet.NewJsonType()}
+			col.convert = convertParquetByteArray
+		case "ENUM":
+			// ConvertedType_ENUM isn't supported by the parquet writer, so only
+			// the logical type is set.
+			se.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+			se.LogicalType = &parquet.LogicalType{ENUM: parquet.NewEnumType()}
+			col.convert = convertParquetByteArray
+		default:
+			if _, ok := dataTypeBin[tp]; ok {
+				se.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+				col.convert = convertParquetByteArray
+				break
+			}
+			// SET, TIME and other string types
+			setParquetString(&col)
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func convertParquetByteArray(v []byte) (interface{}, error) {
+	return string(v), nil
+}
+
+func setParquetString(col *parquetColumn) {
+	col.schema.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+	col.schema.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8)
+	col.schema.LogicalType = &parquet.LogicalType{STRING: parquet.NewStringType()}
+	col.convert = convertParquetByteArray
+}
+
+func setParquetInt(col *parquetColumn, bitWidth int, signed bool) {
+	var convertedType parquet.ConvertedType
+	switch {
+	case bitWidth == 8 && signed:
+		convertedType = parquet.ConvertedType_INT_8
+	case bitWidth == 16 && signed:
+		convertedType = parquet.ConvertedType_INT_16
+	case bitWidth == 32 && signed:
+		convertedType = parquet.ConvertedType_INT_32
+	case bitWidth == 64 && signed:
+		convertedType = parquet.ConvertedType_INT_64
+	case bitWidth == 8:
+		convertedType = parquet.ConvertedType_UINT_8
+	case bitWidth == 16:
+		convertedType = parquet.ConvertedType_UINT_16
+	case bitWidth == 32:
+		convertedType = parquet.ConvertedType_UINT_32
+	default:
+		convertedType = parquet.ConvertedType_UINT_64
+	}
+	col.schema.ConvertedType = parquet.ConvertedTypePtr(convertedType)
+	col.schema.LogicalType = &parquet.LogicalType{INTEGER: &parquet.IntType{
+		BitWidth: int8(bitWidth),
+		IsSigned: signed,
+	}}
+
+	if bitWidth == 64 {
+		col.schema.Type = parquet.TypePtr(parquet.Type_INT64)
+		if signed {
+			col.convert = func(v []byte) (interface{}, error) {
+				return strconv.ParseInt(string(v), 10, 64)
+			}
+		} else {
+			// unsigned values are stored as the same bits in signed type.
+			col.convert = func(v []byte) (interface{}, error) {
+				u, err := strconv.ParseUint(string(v), 10, 64)
+				return int64(u), err
+			}
+		}
+		return
+	}
+	col.schema.Type = parquet.TypePtr(parquet.Type_INT32)
+	if signed {
+		col.convert = func(v []byte) (interface{}, error) {
+			i, err := strconv.ParseInt(string(v), 10, 32)
+			return int32(i), err
+		}
+	} else {
+		col.convert = func(v []byte) (interface{}, error) {
+			u, err := strconv.ParseUint(string(v), 10, 32)
+			return int32(u), err
+		}
+	}
+}
+
+func setParquetDecimal(col *parquetColumn, precision, scale int64) {
+	// the driver calculates the precision from the display length, which
+	// doesn't contain the sign for the unsigned columns. So reserve a digit
+	// to make sure the values always fit.
+	precision++
+	if precision > mysqlMaxDecimalPrecision {
+		precision = mysqlMaxDecimalPrecision
+	}
+	precision32, scale32 := int32(precision), int32(scale)
+	se := col.schema
+	se.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL)
+	se.Precision = &precision32
+	se.Scale = &scale32
+	se.LogicalType = &parquet.LogicalType{DECIMAL: &parquet.DecimalType{
+		Precision: precision32,
+		Scale:     scale32,
+	}}
+
+	switch {
+	case precision <= 9:
+		se.Type = parquet.TypePtr(parquet.Type_INT32)
+		col.convert = func(v []byte) (interface{}, error) {
+			unscaled, err := decimalToUnscaled(v, int(scale))
+			if err != nil {
+				return nil, err
+			}
+			if !unscaled.IsInt64() || unscaled.Int64() < math.MinInt32 || unscaled.Int64() > math.MaxInt32 {
+				return nil, errors.Errorf("decimal '%s' overflows the precision %d", v, precision)
+			}
+			return int32(unscaled.Int64()), nil
+		}
+	case precision <= 18:
+		se.Type = parquet.TypePtr(parquet.Type_INT64)
+		col.convert = func(v []byte) (interface{}, error) {
+			unscaled, err := decimalToUnscaled(v, int(scale))
+			if err != nil {
+				return nil, err
+			}
+			if !unscaled.IsInt64() {
+				return nil, errors.Errorf("decimal '%s' overflows the precision %d", v, precision)
+			}
+			return unscaled.Int64(), nil
+		}
+	default:
+		se.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+		col.convert = func(v []byte) (interface{}, error) {
+			unscaled, err := decimalToUnscaled(v, int(scale))
+			if err != nil {
+				return nil, err
+			}
+			return string(bigIntToTwosComplement(unscaled)), nil
+		}
+	}
+}
+
+// decimalToUnscaled converts the decimal text to the unscaled integer, i.e. the
+// value * 10^scale. It's done on the text to avoid losing precision.
+func decimalToUnscaled(v []byte, scale int) (*big.Int, error) {
+	s := string(v)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if len(fracPart) > scale {
+		return nil, errors.Errorf("decimal '%s' has more fraction digits than the scale %d", s, scale)
+	}
+	digits := intPart + fracPart + strings.Repeat("0", scale-len(fracPart))
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid decimal '%s'", s)
+	}
+	return unscaled, nil
+}
+
+// bigIntToTwosComplement returns the big-endian two's complement bytes of the
+// integer with the minimal length.
+func bigIntToTwosComplement(v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return b
+	}
+	// -v-1 has the inverted bits of v
+	b := new(big.Int).Not(v).Bytes()
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	if len(b) == 0 || b[0]&0x80 == 0 {
+		b = append([]byte{0xff}, b...)
+	}
+	return b
+}
@@ -5,17 +5,25 @@ package export
 import (
 	"context"
 	"database/sql/driver"
+	"math"
+	"math/big"
 	"os"
 	"path"
+	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/br/pkg/version"
 	tcontext "github.com/pingcap/tidb/dumpling/context"
 	"github.com/pingcap/tidb/util/promutil"
 	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
 )
 
 func TestWriteDatabaseMeta(t *testing.T) {
@@ -342,6 +350,162 @@ func TestWriteTableDataWithStatementSize(t *testing.T) {
 	}
 }
 
+func TestWriteTableDataInParquet(t *testing.T) {
+	dir := t.TempDir()
+	config := defaultConfigForTest(t)
+	config.OutputDirPath = dir
+	config.FileType = FileFormatParquetString
+	config.CompressType = storage.Snappy
+
+	writer := createTestWriter(config, t)
+
+	data := [][]driver.Value{
+		{"1", "-12345678.90", "-1234567890123456789012345.00001", "2023-01-02 03:04:05.123456", "2023-01-02", `{"a": 1}`, "red", "bob", "18446744073709551615", []byte{1, 2}},
+		{"2", nil, nil, nil, nil, nil, nil, nil, nil, nil},
+	}
+	colTypes := []string{"INT", "DECIMAL", "DECIMAL", "DATETIME", "DATE", "JSON", "ENUM", "VARCHAR", "UNSIGNED BIGINT", "BLOB"}
+	tableIR := newMockTableIR("test", "employee", data, nil, colTypes)
+	tableIR.colNames = []string{"id", "salary", "balance", "updated_at", "birthday", "attrs", "color", "name", "flags", "avatar"}
+	tableIR.colDecimalSizes = map[int][2]int64{1: {10, 2}, 2: {30, 5}}
+	err := writer.WriteTableData(tableIR, tableIR, 0)
+	require.NoError(t, err)
+
+	// the pages are compressed inside the parquet file
+	p := path.Join(dir, "test.employee.000000000.parquet")
+	columns, schema, rows := readParquetFile(t, p)
+
+	require.Equal(t, tableIR.colNames, columns)
+	require.Len(t, schema, 11)
+	require.Equal(t, parquet.Type_INT32, schema[1].GetType())
+	require.Equal(t, parquet.ConvertedType_INT_32, schema[1].GetConvertedType())
+	require.Equal(t, parquet.Type_INT64, schema[2].GetType())
+	require.Equal(t, int32(11), schema[2].GetLogicalType().GetDECIMAL().GetPrecision())
+	require.Equal(t, int32(2), schema[2].GetLogicalType().GetDECIMAL().GetScale())
+	require.Equal(t, parquet.Type_BYTE_ARRAY, schema[3].GetType())
+	require.Equal(t, int32(5), schema[3].GetScale())
+	require.False(t, schema[4].GetLogicalType().GetTIMESTAMP().GetIsAdjustedToUTC())
+	require.NotNil(t, schema[5].GetLogicalType().GetDATE())
+	require.Equal(t, parquet.ConvertedType_JSON, schema[6].GetConvertedType())
+	require.NotNil(t, schema[7].GetLogicalType().GetENUM())
+	require.Equal(t, parquet.ConvertedType_UTF8, schema[8].GetConvertedType())
+	require.Equal(t, parquet.ConvertedType_UINT_64, schema[9].GetConvertedType())
+	require.Nil(t, schema[10].ConvertedType)
+
+	balance, ok := new(big.Int).SetString("-123456789012345678901234500001", 10)
+	require.True(t, ok)
+	require.Equal(t, [][]interface{}{
+		{
+			int32(1), int64(-1234567890), string(bigIntToTwosComplement(balance)),
+			time.Date(2023, 1, 2, 3, 4, 5, 123456000, time.UTC).UnixMicro(), int32(19359),
+			`{"a": 1}`, "red", "bob", uint64(math.MaxUint64), "\x01\x02",
+		},
+		{int32(2), nil, nil, nil, nil, nil, nil, nil, nil, nil},
+	}, rows)
+
+	// zero dates can't be represented in parquet
+	data = [][]driver.Value{
+		{"1", "1.00", "1.00000", "0000-00-00 00:00:00", "2023-01-02", nil, nil, nil, nil, nil},
+	}
+	tableIR = newMockTableIR("test", "employee", data, nil, colTypes)
+	tableIR.colNames = []string{"id", "salary", "balance", "updated_at", "birthday", "attrs", "color", "name", "flags", "avatar"}
+	tableIR.colDecimalSizes = map[int][2]int64{1: {10, 2}, 2: {30, 5}}
+	m := newMetrics(config.PromFactory, config.Labels)
+	_, err = WriteInsertInParquet(tcontext.Background(), config, tableIR, tableIR, storage.NewBufferWriter(), m)
+	require.ErrorContains(t, err, "invalid datetime '0000-00-00 00:00:00'")
+}
+
+func TestWriteTableDataInParquetWithFileSize(t *testing.T) {
+	dir := t.TempDir()
+	config := defaultConfigForTest(t)
+	config.OutputDirPath = dir
+	config.FileType = FileFormatParquetString
+	config.FileSize = 20
+
+	writer := createTestWriter(config, t)
+
+	data := [][]driver.Value{
+		{"1", "bob@mail.com"},
+		{"2", "sarah@mail.com"},
+		{"3", "john@mail.com"},
+	}
+	colTypes := []string{"BIGINT", "VARCHAR"}
+	tableIR := newMockTableIR("test", "employee", data, nil, colTypes)
+	tableIR.colNames = []string{"id", "email"}
+	err := writer.WriteTableData(tableIR, tableIR, 0)
+	require.NoError(t, err)
+
+	cases := map[string][][]interface{}{
+		"test.employee.000000000.parquet": {{int64(1), "bob@mail.com"}, {int64(2), "sarah@mail.com"}},
+		"test.employee.000000001.parquet": {{int64(3), "john@mail.com"}},
+	}
+	for p, expected := range cases {
+		_, _, rows := readParquetFile(t, path.Join(dir, p))
+		require.Equal(t, expected, rows)
+	}
+	_, err = os.Stat(path.Join(dir, "test.employee.000000002.parquet"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestParquetDecimal(t *testing.T) {
+	cases := []struct {
+		value    string
+		scale    int
+		unscaled string
+		bytes    []byte
+	}{
+		{"0", 0, "0", []byte{0x00}},
+		{"1.28", 2, "128", []byte{0x00, 0x80}},
+		{"-1.28", 2, "-128", []byte{0x80}},
+		{"-1.29", 2, "-129", []byte{0xff, 0x7f}},
+		{"-0.01", 2, "-1", []byte{0xff}},
+		{"12.5", 3, "12500", []byte{0x30, 0xd4}},
+	}
+	for _, c := range cases {
+		unscaled, err := decimalToUnscaled([]byte(c.value), c.scale)
+		require.NoError(t, err)
+		require.Equal(t, c.unscaled, unscaled.String())
+		require.Equal(t, c.bytes, bigIntToTwosComplement(unscaled))
+	}
+
+	_, err := decimalToUnscaled([]byte("1.234"), 2)
+	require.ErrorContains(t, err, "more fraction digits than the scale")
+	_, err = decimalToUnscaled([]byte("1.2a"), 2)
+	require.ErrorContains(t, err, "invalid decimal")
+}
+
+// readParquetFile reads the column names, the schema and the physical values of
+// the rows from the parquet file, NULL is returned as nil.
+func readParquetFile(t *testing.T, p string) ([]string, []*parquet.SchemaElement, [][]interface{}) {
+	t.Helper()
+	fr, err := local.NewLocalFileReader(p)
+	require.NoError(t, err)
+	defer fr.Close()
+	pr, err := reader.NewParquetReader(fr, nil, 1)
+	require.NoError(t, err)
+	defer pr.ReadStop()
+
+	objs, err := pr.ReadByNumber(int(pr.GetNumRows()))
+	require.NoError(t, err)
+	rows := make([][]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		v := reflect.ValueOf(obj)
+		row := make([]interface{}, 0, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).IsNil() {
+				row = append(row, nil)
+			} else {
+				row = append(row, v.Field(i).Elem().Interface())
+			}
+		}
+		rows = append(rows, row)
+	}
+	columns := make([]string, 0, len(pr.SchemaHandler.SchemaElements)-1)
+	for i := 1; i < len(pr.SchemaHandler.SchemaElements); i++ {
+		columns = append(columns, pr.SchemaHandler.GetExName(i))
+	}
+	return columns, pr.SchemaHandler.SchemaElements, rows
+}
+
 var mu sync.Mutex
 
 func createTestWriter(conf *Config, t *testing.T) *Writer {
@@ -1133,6 +1133,22 @@ const (
 	ErrCannotResumeDDLJob = 8261
 	ErrPausedDDLJob       = 8262
 
+	// User-defined timer errors.
+	ErrTimerExists    = 8263
+	ErrTimerNotExists = 8264
+
+	// Masking policy errors.
+	ErrMaskingPolicyExists    = 8267
+	ErrMaskingPolicyNotExists = 8268
+	ErrInvalidMaskingPolicy   = 8269
+
+	// Row access policy errors.
+	ErrRowAccessPolicyExists      = 8270
+	ErrRowAccessPolicyNotExists   = 8271
+	ErrInvalidRowAccessPolicy     = 8272
+	ErrDependentByRowAccessPolicy = 8273
+	ErrRowAccessPolicyViolation   = 8274
+
 	// Resource group errors.
 	ErrResourceGroupExists                    = 8248
 	ErrResourceGroupNotExists                 = 8249
@@ -1142,6 +1158,8 @@ const (
 	ErrResourceGroupQueryRunawayInterrupted   = 8253
 	ErrResourceGroupQueryRunawayQuarantine    = 8254
 	ErrResourceGroupInvalidBackgroundTaskName = 8255
+	ErrResourceGroupQueueFull                 = 8265
+	ErrResourceGroupQueueTimeout              = 8266
 
 	// TiKV/PD/TiFlash errors.
 	ErrPDServerTimeout           = 9001
@@ -1142,6 +1142,8 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrResourceGroupQueryRunawayInterrupted:   mysql.Message("Query execution was interrupted, identified as runaway query", nil),
 	ErrResourceGroupQueryRunawayQuarantine:    mysql.Message("Quarantined and interrupted because of being in runaway watch list", nil),
 	ErrResourceGroupInvalidBackgroundTaskName: mysql.Message("Unknown background task name '%-.192s'", nil),
+	ErrResourceGroupQueueFull:                 mysql.Message("The admission queue of resource group '%-.192s' is full", nil),
+	ErrResourceGroupQueueTimeout:              mysql.Message("Timed out after waiting %s in the admission queue of resource group '%-.192s'", nil),
 
 	// TiKV/PD errors.
 	ErrPDServerTimeout:           mysql.Message("PD server timeout: %s", nil),
@@ -1161,4 +1163,17 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrCannotPauseDDLJob:  mysql.Message("Job [%v] can't be paused: %s", nil),
 	ErrCannotResumeDDLJob: mysql.Message("Job [%v] can't be resumed: %s", nil),
 	ErrPausedDDLJob:       mysql.Message("Job [%v] has already been paused", nil),
+
+	ErrTimerExists:    mysql.Message("Timer '%-.192s' already exists", nil),
+	ErrTimerNotExists: mysql.Message("Unknown timer '%-.192s'", nil),
+
+	ErrMaskingPolicyExists:    mysql.Message("Masking policy '%-.192s' already exists", nil),
+	ErrMaskingPolicyNotExists: mysql.Message("Unknown masking policy '%-.192s'", nil),
+	ErrInvalidMaskingPolicy:   mysql.Message("Invalid masking policy: %s", nil),
+
+	ErrRowAccessPolicyExists:      mysql.Message("Row access policy '%-.192s' already exists", nil),
+	ErrRowAccessPolicyNotExists:   mysql.Message("Unknown row access policy '%-.192s'", nil),
+	ErrInvalidRowAccessPolicy:     mysql.Message("Invalid row access policy: %s", nil),
+	ErrDependentByRowAccessPolicy: mysql.Message("Column '%-.192s' has a row access policy '%-.192s' dependency", nil),
+	ErrRowAccessPolicyViolation:   mysql.Message("The row violates the row access policy '%-.192s' of table '%-.192s'", nil),
 }
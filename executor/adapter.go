@@ -56,6 +56,7 @@ import (
 	"github.com/pingcap/tidb/util/breakpoint"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/logutil"
@@ -181,7 +182,7 @@ func (a *recordSet) NewChunk(alloc chunk.Allocator) *chunk.Chunk {
 }
 
 func (a *recordSet) Close() error {
-	err := a.executor.Close()
+	err := exec.Close(a.executor)
 	err1 := a.stmt.CloseRecordSet(a.txnStartTS, a.lastErr)
 	if err != nil {
 		return err
@@ -273,6 +274,11 @@ type ExecStmt struct {
 	OutputNames []*types.FieldName
 	PsStmt      *plannercore.PlanCacheStmt
 	Ti          *TelemetryInfo
+
+	// releaseAdmission releases the slot held by the statement in the admission queue of its resource group.
+	releaseAdmission func()
+	// resultRows is the number of rows returned by the statement, it's used to check the runaway rules.
+	resultRows uint64
 }
 
 // GetStmtNode returns the stmtNode inside Statement
@@ -333,7 +339,7 @@ func (a *ExecStmt) PointGet(ctx context.Context) (*recordSet, error) {
 		}
 	}
 
-	if err = pointExecutor.Open(ctx); err != nil {
+	if err = exec.Open(ctx, pointExecutor); err != nil {
 		terror.Call(pointExecutor.Close)
 		return nil, err
 	}
@@ -463,7 +469,7 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 			}
 			return
 		}
-		if str, ok := r.(string); !ok || !strings.Contains(str, memory.PanicMemoryExceedWarnMsg) {
+		if str, ok := r.(string); !ok || !strings.Contains(str, memory.PanicMemoryExceedWarnMsg) && !strings.Contains(str, disk.PanicDiskExceedWarnMsg) {
 			panic(r)
 		}
 		err = errors.Errorf("%v", r)
@@ -533,10 +539,26 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	if variable.EnableResourceControl.Load() && domain.GetDomain(sctx).RunawayManager() != nil {
 		stmtCtx := sctx.GetSessionVars().StmtCtx
 		_, planDigest := GetPlanDigest(stmtCtx)
-		stmtCtx.RunawayChecker = domain.GetDomain(sctx).RunawayManager().DeriveChecker(sctx.GetSessionVars().ResourceGroupName, stmtCtx.OriginalSQL, planDigest.String())
+		groupName := sctx.GetSessionVars().ResourceGroupName
+		// The rules except EXEC_ELAPSED are only stored in TiDB, so get them from the information schema.
+		var rules *model.ResourceGroupRunawaySettings
+		if group, ok := domain.GetDomain(sctx).InfoSchema().ResourceGroupByName(model.NewCIStr(groupName)); ok && group.ResourceGroupSettings != nil {
+			rules = group.Runaway
+		}
+		stmtCtx.RunawayChecker = domain.GetDomain(sctx).RunawayManager().DeriveChecker(groupName, stmtCtx.OriginalSQL, planDigest.String(), rules)
 		if err := stmtCtx.RunawayChecker.BeforeExecutor(); err != nil {
 			return nil, err
 		}
+		stmtCtx.RunawayChecker.TrackMemory(stmtCtx.MemTracker, &sctx.GetSessionVars().Killed)
+		if err := a.setRunawayRUStats(); err != nil {
+			return nil, err
+		}
+	}
+	if variable.EnableResourceControl.Load() && !sctx.GetSessionVars().InRestrictedSQL {
+		if err = a.admitByResourceGroup(ctx); err != nil {
+			terror.Call(e.Close)
+			return nil, err
+		}
 	}
 
 	breakpoint.Inject(a.Ctx, sessiontxn.BreakPointBeforeExecutorFirstRun)
@@ -916,7 +938,7 @@ func (a *ExecStmt) handlePessimisticSelectForUpdate(ctx context.Context, e exec.
 
 func (a *ExecStmt) runPessimisticSelectForUpdate(ctx context.Context, e exec.Executor) (sqlexec.RecordSet, error) {
 	defer func() {
-		terror.Log(e.Close())
+		terror.Log(exec.Close(e))
 	}()
 	var rows []chunk.Row
 	var err error
@@ -946,7 +968,7 @@ func (a *ExecStmt) handleNoDelayExecutor(ctx context.Context, e exec.Executor) (
 
 	var err error
 	defer func() {
-		terror.Log(e.Close())
+		terror.Log(exec.Close(e))
 		a.logAudit()
 	}()
 
@@ -1134,6 +1156,8 @@ func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, lockErr error
 	breakpoint.Inject(a.Ctx, sessiontxn.BreakPointOnStmtRetryAfterLockError)
 
 	a.resetPhaseDurations()
+	// The rows returned before the retry are discarded, so they are not counted by the runaway rules.
+	a.resultRows = 0
 
 	e, err := a.buildExecutor()
 	if err != nil {
@@ -1205,6 +1229,42 @@ func (a *ExecStmt) buildExecutor() (exec.Executor, error) {
 	return e, nil
 }
 
+// admitByResourceGroup waits in the admission queue of the resource group until the statement is
+// allowed to be executed, if the max concurrency of the resource group is limited.
+func (a *ExecStmt) admitByResourceGroup(ctx context.Context) error {
+	if a.releaseAdmission != nil {
+		// The statement is executed again and it has been admitted.
+		return nil
+	}
+	switch p := a.Plan.(type) {
+	case plannercore.PhysicalPlan, *plannercore.Insert, *plannercore.Update, *plannercore.Delete:
+	case *plannercore.Explain:
+		if !p.Analyze {
+			return nil
+		}
+	default:
+		return nil
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	group, ok := domain.GetDomain(a.Ctx).InfoSchema().ResourceGroupByName(model.NewCIStr(sessVars.ResourceGroupName))
+	if !ok || group.ResourceGroupSettings == nil || group.Queue == nil {
+		return nil
+	}
+	stmtCtx := sessVars.StmtCtx
+	release, err := domain.GetDomain(a.Ctx).ResourceGroupAdmissionController().Admit(ctx, group.Name.L, group.Queue,
+		func() bool {
+			return atomic.LoadUint32(&sessVars.Killed) == 1
+		},
+		func(waiting bool) {
+			stmtCtx.WaitingInResourceGroupQueue.Store(waiting)
+		})
+	if err != nil {
+		return err
+	}
+	a.releaseAdmission = release
+	return nil
+}
+
 func (a *ExecStmt) openExecutor(ctx context.Context, e exec.Executor) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -1212,7 +1272,7 @@ func (a *ExecStmt) openExecutor(ctx context.Context, e exec.Executor) (err error
 		}
 	}()
 	start := time.Now()
-	err = e.Open(ctx)
+	err = exec.Open(ctx, e)
 	a.phaseOpenDurations[0] += time.Since(start)
 	return err
 }
@@ -1221,7 +1281,60 @@ func (a *ExecStmt) next(ctx context.Context, e exec.Executor, req *chunk.Chunk)
 	start := time.Now()
 	err := Next(ctx, e, req)
 	a.phaseNextDurations[0] += time.Since(start)
-	return err
+	if err != nil {
+		if checker := a.Ctx.GetSessionVars().StmtCtx.RunawayChecker; checker.KilledByMemory() {
+			// The query is killed by the MEMORY rule, so reset the kill flag and return the runaway error.
+			atomic.CompareAndSwapUint32(&a.Ctx.GetSessionVars().Killed, 1, 0)
+			return exeerrors.ErrResourceGroupQueryRunawayInterrupted
+		}
+		return err
+	}
+	return a.checkRunaway(req.NumRows())
+}
+
+// checkRunaway checks the runaway rules on the result rows of the statement.
+func (a *ExecStmt) checkRunaway(numRows int) error {
+	stmtCtx := a.Ctx.GetSessionVars().StmtCtx
+	if stmtCtx.RunawayChecker == nil {
+		return nil
+	}
+	a.resultRows += uint64(numRows)
+	return stmtCtx.RunawayChecker.CheckResult(a.resultRows)
+}
+
+// setRunawayRUStats registers the RU runtime stats of the timestamps used by the statement, so that the RU rule
+// of the runaway checker is checked with the RU collected beneath the KV storage client.
+func (a *ExecStmt) setRunawayRUStats() error {
+	stmtCtx := a.Ctx.GetSessionVars().StmtCtx
+	if !stmtCtx.RunawayChecker.NeedRUStats() {
+		return nil
+	}
+	store, ok := a.Ctx.GetStore().(interface {
+		CreateRURuntimeStats(uint64) *util.RURuntimeStats
+	})
+	if !ok {
+		return nil
+	}
+	txnManager := sessiontxn.GetTxnManager(a.Ctx)
+	var (
+		readTS uint64
+		err    error
+	)
+	if stmtCtx.InInsertStmt || stmtCtx.InUpdateStmt || stmtCtx.InDeleteStmt || a.isSelectForUpdate {
+		readTS, err = txnManager.GetStmtForUpdateTS()
+	} else {
+		readTS, err = txnManager.GetStmtReadTS()
+	}
+	if err != nil {
+		return err
+	}
+	stats := []*util.RURuntimeStats{store.CreateRURuntimeStats(readTS)}
+	// The writes of the statement are sent with the start ts of the transaction.
+	if txn, err := a.Ctx.Txn(false); err == nil && txn.Valid() && txn.StartTS() != readTS {
+		stats = append(stats, store.CreateRURuntimeStats(txn.StartTS()))
+	}
+	stmtCtx.RunawayChecker.SetRUStats(stats...)
+	return nil
 }
 
 func (a *ExecStmt) resetPhaseDurations() {
@@ -1340,6 +1453,10 @@ func (a *ExecStmt) observePhaseDurations(internal bool, commitDetails *util.Comm
 // 4. update the `PrevStmt` in session variable.
 // 5. reset `DurationParse` in session variable.
 func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults bool) {
+	if a.releaseAdmission != nil {
+		a.releaseAdmission()
+		a.releaseAdmission = nil
+	}
 	a.checkPlanReplayerCapture(txnTS)
 
 	sessVars := a.Ctx.GetSessionVars()
@@ -1542,6 +1659,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 	copTaskInfo := stmtCtx.CopTasksDetails()
 	memMax := sessVars.MemTracker.MaxConsumed()
 	diskMax := sessVars.DiskTracker.MaxConsumed()
+	diskSpilled := sessVars.DiskTracker.TotalConsumed()
 	_, planDigest := GetPlanDigest(stmtCtx)
 
 	binaryPlan := ""
@@ -1579,6 +1697,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		ExecDetail:        execDetail,
 		MemMax:            memMax,
 		DiskMax:           diskMax,
+		DiskSpilled:       diskSpilled,
 		Succ:              succ,
 		Plan:              getPlanTree(stmtCtx),
 		PlanDigest:        planDigest.String(),
@@ -1859,6 +1978,7 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 	copTaskInfo := stmtCtx.CopTasksDetails()
 	memMax := sessVars.MemTracker.MaxConsumed()
 	diskMax := sessVars.DiskTracker.MaxConsumed()
+	diskSpilled := sessVars.DiskTracker.TotalConsumed()
 	sql := a.GetTextToLog(false)
 	var stmtDetail execdetails.StmtExecDetails
 	stmtDetailRaw := a.GoCtx.Value(execdetails.StmtExecDetailKey)
@@ -1913,6 +2033,7 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 		ExecDetail:          &execDetail,
 		MemMax:              memMax,
 		DiskMax:             diskMax,
+		DiskSpilled:         diskSpilled,
 		StartTime:           sessVars.StartTime,
 		IsInternal:          sessVars.InRestrictedSQL,
 		Succeed:             succ,
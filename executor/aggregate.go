@@ -91,6 +91,12 @@ type HashAggPartialWorker struct {
 	// chk stores the input data from child,
 	// and is reused by childExec and partial worker.
 	chk *chunk.Chunk
+
+	// spillHelper is nil if spilling is disabled.
+	spillHelper *parallelHashAggSpillHelper
+	workerIdx   int
+	// spillChks[i] stores the rows to be spilled whose groups belong to the i-th final worker.
+	spillChks []*chunk.Chunk
 }
 
 // HashAggFinalWorker indicates the final workers of parallel hash agg execution,
@@ -106,6 +112,20 @@ type HashAggFinalWorker struct {
 	outputCh            chan *AfFinalResult
 	finalResultHolderCh chan *chunk.Chunk
 	groupKeys           [][]byte
+
+	// intermDataConsumed is called after the intermediate data from the partial workers are consumed.
+	intermDataConsumed func()
+
+	// spillHelper is nil if spilling is disabled.
+	spillHelper     *parallelHashAggSpillHelper
+	workerIdx       int
+	partialAggFuncs []aggfuncs.AggFunc
+	groupByItems    []expression.Expression
+	spillGroupKeys  [][]byte
+	// respilled stores the rows whose groups are not processed in the current round, they're processed in the
+	// next round after the results of the current round are returned.
+	respilled  *chunk.ListInDisk
+	respillChk *chunk.Chunk
 }
 
 // AfFinalResult indicates aggregation functions final result.
@@ -198,6 +218,8 @@ type HashAggExec struct {
 	// listInDisk is the chunks to store row values for spilled data.
 	// The HashAggExec may be set to `spill mode` multiple times, and all spilled data will be appended to ListInDisk.
 	listInDisk *chunk.ListInDisk
+	// spillHelper holds the spilled data of the parallel execution.
+	spillHelper *parallelHashAggSpillHelper
 	// numOfSpilledChks indicates the number of all the spilled chunks.
 	numOfSpilledChks int
 	// offsetOfSpilledChks indicates the offset of the chunk be read from the disk.
@@ -206,6 +228,9 @@ type HashAggExec struct {
 	// inSpillMode indicates whether HashAgg is in `spill mode`.
 	// When HashAgg is in `spill mode`, the size of `partialResultMap` is no longer growing and all the data fetched
 	// from the child executor is spilled to the disk.
+	// For the parallel execution, the rows of the new groups are spilled by the partial workers to the partitions
+	// of the final workers, and each final worker aggregates the rows of its partition after merging the partial
+	// results, in several rounds if it's set to `spill mode` again.
 	inSpillMode uint32
 	// tmpChkForSpill is the temp chunk for spilling.
 	tmpChkForSpill *chunk.Chunk
@@ -215,6 +240,45 @@ type HashAggExec struct {
 	isChildDrained bool
 }
 
+// parallelHashAggSpillHelper holds the data spilled by the workers of the parallel hash aggregation.
+type parallelHashAggSpillHelper struct {
+	inSpillMode *uint32
+	diskTracker *disk.Tracker
+	fieldTypes  []*types.FieldType
+	// partitions[i][j] stores the rows spilled by the j-th partial worker, whose groups belong to the i-th final worker.
+	partitions [][]*chunk.ListInDisk
+}
+
+func newParallelHashAggSpillHelper(inSpillMode *uint32, diskTracker *disk.Tracker, fieldTypes []*types.FieldType,
+	finalConcurrency, partialConcurrency int) *parallelHashAggSpillHelper {
+	partitions := make([][]*chunk.ListInDisk, finalConcurrency)
+	for i := range partitions {
+		partitions[i] = make([]*chunk.ListInDisk, partialConcurrency)
+	}
+	return &parallelHashAggSpillHelper{
+		inSpillMode: inSpillMode,
+		diskTracker: diskTracker,
+		fieldTypes:  fieldTypes,
+		partitions:  partitions,
+	}
+}
+
+func (h *parallelHashAggSpillHelper) newListInDisk() *chunk.ListInDisk {
+	l := chunk.NewListInDisk(h.fieldTypes)
+	l.GetDiskTracker().AttachTo(h.diskTracker)
+	return l
+}
+
+func (h *parallelHashAggSpillHelper) close() {
+	for _, partition := range h.partitions {
+		for _, l := range partition {
+			if l != nil {
+				terror.Log(l.Close())
+			}
+		}
+	}
+}
+
 // HashAggInput indicates the input of hash agg exec.
 type HashAggInput struct {
 	chk *chunk.Chunk
@@ -289,6 +353,7 @@ func (e *HashAggExec) Close() error {
 		if e.memTracker != nil {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
+		e.spillHelper, e.spillAction = nil, nil
 	}
 	return e.BaseExecutor.Close()
 }
@@ -378,10 +443,23 @@ func (e *HashAggExec) initForParallelExec(_ sessionctx.Context) {
 	e.finalWorkers = make([]HashAggFinalWorker, finalConcurrency)
 	e.initRuntimeStats()
 
+	atomic.StoreUint32(&e.inSpillMode, 0)
+	e.spillHelper = nil
+	if sessionVars.TrackAggregateMemoryUsage && variable.EnableTmpStorageOnOOM.Load() {
+		e.diskTracker = disk.NewTracker(e.ID(), -1)
+		e.diskTracker.AttachTo(sessionVars.StmtCtx.DiskTracker)
+		e.spillHelper = newParallelHashAggSpillHelper(&e.inSpillMode, e.diskTracker, retTypes(e.Children(0)), finalConcurrency, partialConcurrency)
+		sessionVars.MemTracker.FallbackOldAndSetNewActionForSoftLimit(e.ActionSpill())
+	}
+
 	// Init partial workers.
 	for i := 0; i < partialConcurrency; i++ {
+		// Each partial worker tracks its memory separately, so that the memory can be released after its partial
+		// results are merged by the final workers.
+		memTracker := memory.NewTracker(e.ID(), -1)
+		memTracker.AttachTo(e.memTracker)
 		w := HashAggPartialWorker{
-			baseHashAggWorker: newBaseHashAggWorker(e.Ctx(), e.finishCh, e.PartialAggFuncs, e.MaxChunkSize(), e.memTracker),
+			baseHashAggWorker: newBaseHashAggWorker(e.Ctx(), e.finishCh, e.PartialAggFuncs, e.MaxChunkSize(), memTracker),
 			inputCh:           e.partialInputChs[i],
 			outputChs:         e.partialOutputChs,
 			giveBackCh:        e.inputCh,
@@ -390,15 +468,18 @@ func (e *HashAggExec) initForParallelExec(_ sessionctx.Context) {
 			groupByItems:      e.GroupByItems,
 			chk:               tryNewCacheChunk(e.Children(0)),
 			groupKey:          make([][]byte, 0, 8),
+			spillHelper:       e.spillHelper,
+			workerIdx:         i,
+			spillChks:         make([]*chunk.Chunk, finalConcurrency),
 		}
 		// There is a bucket in the empty partialResultsMap.
 		failpoint.Inject("ConsumeRandomPanic", nil)
-		e.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice * (1 << w.BInMap))
+		w.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice * (1 << w.BInMap))
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.PartialStats = append(e.stats.PartialStats, w.stats)
 		}
-		e.memTracker.Consume(w.chk.MemoryUsage())
+		w.memTracker.Consume(w.chk.MemoryUsage())
 		e.partialWorkers[i] = w
 		input := &HashAggInput{
 			chk:        newFirstChunk(e.Children(0)),
@@ -409,10 +490,26 @@ func (e *HashAggExec) initForParallelExec(_ sessionctx.Context) {
 	}
 
 	// Init final workers.
+	partialWorkers := e.partialWorkers
+	remainingFinalWorkers := int32(finalConcurrency)
+	releasePartialResults := func() {
+		if atomic.AddInt32(&remainingFinalWorkers, -1) > 0 {
+			return
+		}
+		// All the partial results have been merged by the final workers.
+		for i := range partialWorkers {
+			partialWorkers[i].partialResultsMap = nil
+			partialWorkers[i].memTracker.ReplaceBytesUsed(0)
+		}
+	}
 	for i := 0; i < finalConcurrency; i++ {
 		groupSet, setSize := set.NewStringSetWithMemoryUsage()
+		// Each final worker tracks its memory separately, so that the memory can be released after the results
+		// of a round are returned in spill mode.
+		memTracker := memory.NewTracker(e.ID(), -1)
+		memTracker.AttachTo(e.memTracker)
 		w := HashAggFinalWorker{
-			baseHashAggWorker:   newBaseHashAggWorker(e.Ctx(), e.finishCh, e.FinalAggFuncs, e.MaxChunkSize(), e.memTracker),
+			baseHashAggWorker:   newBaseHashAggWorker(e.Ctx(), e.finishCh, e.FinalAggFuncs, e.MaxChunkSize(), memTracker),
 			partialResultMap:    make(aggPartialResultMapper),
 			groupSet:            groupSet,
 			inputCh:             e.partialOutputChs[i],
@@ -421,10 +518,15 @@ func (e *HashAggExec) initForParallelExec(_ sessionctx.Context) {
 			rowBuffer:           make([]types.Datum, 0, e.Schema().Len()),
 			mutableRow:          chunk.MutRowFromTypes(retTypes(e)),
 			groupKeys:           make([][]byte, 0, 8),
+			intermDataConsumed:  releasePartialResults,
+			spillHelper:         e.spillHelper,
+			workerIdx:           i,
+			partialAggFuncs:     e.PartialAggFuncs,
+			groupByItems:        e.GroupByItems,
 		}
 		// There is a bucket in the empty partialResultsMap.
-		e.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice*(1<<w.BInMap) + setSize)
-		groupSet.SetTracker(e.memTracker)
+		w.memTracker.Consume(hack.DefBucketMemoryUsageForMapStrToSlice*(1<<w.BInMap) + setSize)
+		groupSet.SetTracker(w.memTracker)
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.FinalStats = append(e.stats.FinalStats, w.stats)
@@ -482,6 +584,9 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 			w.stats.WaitTime += int64(time.Since(waitStart))
 		}
 		if !ok {
+			if err := w.flushSpilledChks(); err != nil {
+				w.globalOutputCh <- &AfFinalResult{err: err}
+			}
 			return
 		}
 		execStart := time.Now()
@@ -517,13 +622,29 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 		return err
 	}
 
-	partialResults := w.getPartialResult(sc, w.groupKey, w.partialResultsMap)
+	failpoint.Inject("triggerParallelHashAggSpill", func(val failpoint.Value) {
+		if val.(bool) && w.spillHelper != nil {
+			atomic.StoreUint32(w.spillHelper.inSpillMode, 1)
+		}
+	})
 	numRows := chk.NumRows()
+	groupKey, rowIdxs := w.groupKey[:numRows], []int(nil)
+	if w.spillHelper != nil && atomic.LoadUint32(w.spillHelper.inSpillMode) == 1 && len(w.partialResultsMap) > 0 {
+		groupKey, rowIdxs, err = w.spillRowsOfNewGroups(chk)
+		if err != nil {
+			return err
+		}
+	}
+	partialResults := w.getPartialResult(sc, groupKey, w.partialResultsMap)
 	rows := make([]chunk.Row, 1)
 	allMemDelta := int64(0)
-	for i := 0; i < numRows; i++ {
+	for i := range groupKey {
+		rowIdx := i
+		if rowIdxs != nil {
+			rowIdx = rowIdxs[i]
+		}
 		for j, af := range w.aggFuncs {
-			rows[0] = chk.GetRow(i)
+			rows[0] = chk.GetRow(rowIdx)
 			memDelta, err := af.UpdatePartialResult(ctx, rows, partialResults[i][j])
 			if err != nil {
 				return err
@@ -535,6 +656,59 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 	return nil
 }
 
+// spillRowsOfNewGroups spills the rows whose groups are not in partialResultsMap to the partitions of the
+// final workers, and returns the group keys and the indexes of the other rows.
+func (w *HashAggPartialWorker) spillRowsOfNewGroups(chk *chunk.Chunk) (groupKey [][]byte, rowIdxs []int, err error) {
+	numRows := chk.NumRows()
+	groupKey = make([][]byte, 0, numRows)
+	rowIdxs = make([]int, 0, numRows)
+	for i := 0; i < numRows; i++ {
+		if _, ok := w.partialResultsMap[string(w.groupKey[i])]; ok {
+			groupKey = append(groupKey, w.groupKey[i])
+			rowIdxs = append(rowIdxs, i)
+			continue
+		}
+		// Use the same hash function as shuffleIntermData, so the spilled rows are sent to the final worker
+		// which the partial results of the same group are sent to.
+		finalWorkerIdx := int(murmur3.Sum32(w.groupKey[i])) % len(w.spillChks)
+		if w.spillChks[finalWorkerIdx] == nil {
+			w.spillChks[finalWorkerIdx] = chunk.New(w.spillHelper.fieldTypes, w.maxChunkSize, w.maxChunkSize)
+		}
+		w.spillChks[finalWorkerIdx].AppendRow(chk.GetRow(i))
+		if w.spillChks[finalWorkerIdx].IsFull() {
+			if err = w.flushSpilledChk(finalWorkerIdx); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return groupKey, rowIdxs, nil
+}
+
+func (w *HashAggPartialWorker) flushSpilledChks() error {
+	for i := range w.spillChks {
+		if err := w.flushSpilledChk(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *HashAggPartialWorker) flushSpilledChk(finalWorkerIdx int) error {
+	chk := w.spillChks[finalWorkerIdx]
+	if chk == nil || chk.NumRows() == 0 {
+		return nil
+	}
+	partition := w.spillHelper.partitions[finalWorkerIdx]
+	if partition[w.workerIdx] == nil {
+		partition[w.workerIdx] = w.spillHelper.newListInDisk()
+	}
+	if err := partition[w.workerIdx].Add(chk); err != nil {
+		return err
+	}
+	chk.Reset()
+	return nil
+}
+
 // shuffleIntermData shuffles the intermediate data of partial workers to corresponded final workers.
 // We only support parallel execution for single-machine, so process of encode and decode can be skipped.
 func (w *HashAggPartialWorker) shuffleIntermData(_ *stmtctx.StatementContext, finalConcurrency int) {
@@ -772,15 +946,175 @@ func (w *HashAggFinalWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitGro
 		if r := recover(); r != nil {
 			recoveryHashAgg(w.outputCh, r)
 		}
+		if w.respilled != nil {
+			terror.Log(w.respilled.Close())
+			w.respilled = nil
+		}
 		if w.stats != nil {
 			w.stats.WorkerTime += int64(time.Since(start))
 		}
 		waitGroup.Done()
 	}()
-	if err := w.consumeIntermData(ctx); err != nil {
+	err := w.consumeIntermData(ctx)
+	w.intermDataConsumed()
+	if err != nil {
 		w.outputCh <- &AfFinalResult{err: err}
 	}
-	w.loadFinalResult(ctx)
+	if w.spillHelper == nil {
+		w.loadFinalResult(ctx)
+		return
+	}
+	spilled := w.spillHelper.partitions[w.workerIdx]
+	for round := 0; ; round++ {
+		err := w.consumeSpilledData(ctx, spilled)
+		if round > 0 {
+			// The data spilled again in the last round have been consumed.
+			terror.Log(spilled[0].Close())
+		}
+		if err != nil {
+			w.outputCh <- &AfFinalResult{err: err}
+			return
+		}
+		w.loadFinalResult(ctx)
+		if w.respilled == nil || w.isFinished() {
+			return
+		}
+		spilled = []*chunk.ListInDisk{w.respilled}
+		w.respilled = nil
+		w.resetForNextRound()
+	}
+}
+
+func (w *HashAggFinalWorker) isFinished() bool {
+	select {
+	case <-w.finishCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// consumeSpilledData aggregates the rows spilled by the partial workers or by itself in the last round.
+func (w *HashAggFinalWorker) consumeSpilledData(sctx sessionctx.Context, spilled []*chunk.ListInDisk) error {
+	execStart := time.Now()
+	for _, l := range spilled {
+		if l == nil {
+			continue
+		}
+		for i := 0; i < l.NumChunks(); i++ {
+			if w.isFinished() {
+				return nil
+			}
+			chk, err := l.GetChunk(i)
+			if err != nil {
+				return err
+			}
+			if err = w.consumeSpilledChunk(sctx, chk); err != nil {
+				return err
+			}
+		}
+	}
+	if w.stats != nil {
+		w.stats.ExecTime += int64(time.Since(execStart))
+	}
+	return w.flushRespilledChk()
+}
+
+// consumeSpilledChunk aggregates the spilled rows by the partial aggregate functions, then merges the partial
+// results into the final results. In spill mode, the rows of the new groups are spilled again.
+func (w *HashAggFinalWorker) consumeSpilledChunk(sctx sessionctx.Context, chk *chunk.Chunk) (err error) {
+	memSize := getGroupKeyMemUsage(w.spillGroupKeys)
+	w.spillGroupKeys, err = getGroupKey(sctx, chk, w.spillGroupKeys, w.groupByItems)
+	failpoint.Inject("ConsumeRandomPanic", nil)
+	w.memTracker.Consume(getGroupKeyMemUsage(w.spillGroupKeys) - memSize)
+	if err != nil {
+		return err
+	}
+
+	numRows := chk.NumRows()
+	partialResultMap := make(aggPartialResultMapper)
+	groupKeys := make([][]byte, 0, numRows)
+	rows := make([]chunk.Row, 1)
+	allMemDelta := int64(0)
+	for i := 0; i < numRows; i++ {
+		groupKey := w.spillGroupKeys[i]
+		if !w.groupSet.Exist(string(groupKey)) {
+			if atomic.LoadUint32(w.spillHelper.inSpillMode) == 1 && w.groupSet.Count() > 0 {
+				if err = w.respillRow(chk.GetRow(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			allMemDelta += w.groupSet.Insert(string(groupKey))
+		}
+		prs, ok := partialResultMap[string(groupKey)]
+		if !ok {
+			prs = make([]aggfuncs.PartialResult, 0, len(w.partialAggFuncs))
+			for _, af := range w.partialAggFuncs {
+				pr, _ := af.AllocPartialResult()
+				prs = append(prs, pr)
+			}
+			partialResultMap[string(groupKey)] = prs
+			groupKeys = append(groupKeys, groupKey)
+		}
+		rows[0] = chk.GetRow(i)
+		for j, af := range w.partialAggFuncs {
+			if _, err = af.UpdatePartialResult(sctx, rows, prs[j]); err != nil {
+				return err
+			}
+		}
+	}
+	finalPartialResults := w.getPartialResult(sctx.GetSessionVars().StmtCtx, groupKeys, w.partialResultMap)
+	for i, groupKey := range groupKeys {
+		prs := partialResultMap[string(groupKey)]
+		for j, af := range w.aggFuncs {
+			memDelta, err := af.MergePartialResult(sctx, prs[j], finalPartialResults[i][j])
+			if err != nil {
+				return err
+			}
+			allMemDelta += memDelta
+		}
+	}
+	w.memTracker.Consume(allMemDelta)
+	return nil
+}
+
+func (w *HashAggFinalWorker) respillRow(row chunk.Row) error {
+	if w.respillChk == nil {
+		w.respillChk = chunk.New(w.spillHelper.fieldTypes, w.maxChunkSize, w.maxChunkSize)
+	}
+	w.respillChk.AppendRow(row)
+	if !w.respillChk.IsFull() {
+		return nil
+	}
+	return w.flushRespilledChk()
+}
+
+func (w *HashAggFinalWorker) flushRespilledChk() error {
+	if w.respillChk == nil || w.respillChk.NumRows() == 0 {
+		return nil
+	}
+	if w.respilled == nil {
+		w.respilled = w.spillHelper.newListInDisk()
+	}
+	if err := w.respilled.Add(w.respillChk); err != nil {
+		return err
+	}
+	w.respillChk.Reset()
+	return nil
+}
+
+// resetForNextRound releases the results of the current round, and resets the spill mode to process the
+// spilled data in the next round.
+func (w *HashAggFinalWorker) resetForNextRound() {
+	var setSize int64
+	w.groupSet, setSize = set.NewStringSetWithMemoryUsage()
+	w.groupSet.SetTracker(w.memTracker)
+	w.partialResultMap = make(aggPartialResultMapper)
+	w.groupKeys, w.spillGroupKeys = w.groupKeys[:0], nil
+	w.BInMap = 0
+	w.memTracker.ReplaceBytesUsed(hack.DefBucketMemoryUsageForMapStrToSlice*(1<<w.BInMap) + setSize + getGroupKeyMemUsage(w.groupKeys))
+	atomic.StoreUint32(w.spillHelper.inSpillMode, 0)
 }
 
 // Next implements the Executor Next interface.
@@ -846,10 +1180,13 @@ func (e *HashAggExec) waitPartialWorkerAndCloseOutputChs(waitGroup *sync.WaitGro
 	}
 }
 
-func (e *HashAggExec) waitAllWorkersAndCloseFinalOutputCh(waitGroups ...*sync.WaitGroup) {
+func (e *HashAggExec) waitAllWorkersAndCloseFinalOutputCh(spillHelper *parallelHashAggSpillHelper, waitGroups ...*sync.WaitGroup) {
 	for _, waitGroup := range waitGroups {
 		waitGroup.Wait()
 	}
+	if spillHelper != nil {
+		spillHelper.close()
+	}
 	close(e.finalOutputCh)
 }
 
@@ -896,7 +1233,7 @@ func (e *HashAggExec) prepare4ParallelExec(ctx context.Context) {
 
 	// All workers may send error message to e.finalOutputCh when they panic.
 	// And e.finalOutputCh should be closed after all goroutines gone.
-	go e.waitAllWorkersAndCloseFinalOutputCh(fetchChildWorkerWaitGroup, partialWorkerWaitGroup, finalWorkerWaitGroup)
+	go e.waitAllWorkersAndCloseFinalOutputCh(e.spillHelper, fetchChildWorkerWaitGroup, partialWorkerWaitGroup, finalWorkerWaitGroup)
 }
 
 // HashAggExec employs one input reader, M partial workers and N final workers to execute parallelly.
@@ -1931,7 +2268,7 @@ func (e *HashAggExec) ActionSpill() *AggSpillDiskAction {
 // maxSpillTimes indicates how many times the data can spill at most.
 const maxSpillTimes = 10
 
-// AggSpillDiskAction implements memory.ActionOnExceed for unparalleled HashAgg.
+// AggSpillDiskAction implements memory.ActionOnExceed for HashAgg.
 // If the memory quota of a query is exceeded, AggSpillDiskAction.Action is
 // triggered.
 type AggSpillDiskAction struct {
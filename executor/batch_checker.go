@@ -257,6 +257,42 @@ func formatDataForDupError(data []types.Datum) (string, error) {
 	return strings.Join(strs, "-"), nil
 }
 
+// getDupRowTable returns the physical table and the handle of a duplicated row.
+// The duplicated row found by a global index may be in another partition than t.
+func getDupRowTable(t table.Table, handle kv.Handle) (table.Table, kv.Handle) {
+	ph, ok := handle.(kv.PartitionHandle)
+	if !ok {
+		return t, handle
+	}
+	if pt := t.GetPartitionedTable(); pt != nil {
+		if p := pt.GetPartition(ph.PartitionID); p != nil {
+			return p, ph.Handle
+		}
+	}
+	return t, ph.Handle
+}
+
+// isDroppedPartitionHandle checks whether the duplicated row found by a global index is in a partition
+// being dropped or truncated, whose global index entries are not cleaned up yet.
+func isDroppedPartitionHandle(t table.Table, handle kv.Handle) bool {
+	ph, ok := handle.(kv.PartitionHandle)
+	if !ok {
+		return false
+	}
+	pt := t.GetPartitionedTable()
+	return pt != nil && pt.GetPartition(ph.PartitionID) == nil
+}
+
+// isStaleGlobalIndexValue checks whether the unique key is found in a global index entry
+// of a partition being dropped or truncated.
+func isStaleGlobalIndexValue(t table.Table, uk *keyValueWithDupInfo, val []byte) bool {
+	if tablecodec.IsTempIndexKey(uk.newKey) {
+		return false
+	}
+	handle, err := tablecodec.DecodeHandleInGlobalIndexValue(val, uk.commonHandle)
+	return err == nil && isDroppedPartitionHandle(t, handle)
+}
+
 // getOldRow gets the table record row from storage for batch check.
 // t could be a normal table or a partition, but it must not be a PartitionedTable.
 func getOldRow(ctx context.Context, sctx sessionctx.Context, txn kv.Transaction, t table.Table, handle kv.Handle,
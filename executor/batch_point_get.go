@@ -226,7 +226,9 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 			}
 
 			var physID int64
-			if len(e.planPhysIDs) > 0 {
+			if e.idxInfo.Global {
+				physID = e.tblInfo.ID
+			} else if len(e.planPhysIDs) > 0 {
 				physID = e.planPhysIDs[i]
 			} else {
 				physID, err = core.GetPhysID(e.tblInfo, e.partExpr, idxVals[e.partPos])
@@ -236,7 +238,7 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 			}
 
 			// If this BatchPointGetExec is built only for the specific table partition, skip those filters not matching this partition.
-			if e.singlePart && e.partTblID != physID {
+			if e.singlePart && e.partTblID != physID && !e.idxInfo.Global {
 				continue
 			}
 			idxKey, err1 := EncodeUniqueIndexKey(e.Ctx(), e.tblInfo, e.idxInfo, idxVals, physID)
@@ -295,12 +297,23 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 			if err1 != nil {
 				return err1
 			}
+			pid := tablecodec.DecodeTableID(key)
+			if e.idxInfo.Global {
+				var ok bool
+				pid, ok, err1 = tablecodec.DecodePartitionIDInIndexValue(handleVal)
+				if err1 != nil {
+					return err1
+				}
+				// The row is not in the partition to read, or the entry belongs to a dropped partition.
+				if !ok || (e.singlePart && e.partTblID != pid) || !isPartitionOfTable(e.tblInfo, pid) {
+					continue
+				}
+			}
 			e.handles = append(e.handles, handle)
 			if rc {
 				indexKeys = append(indexKeys, key)
 			}
 			if e.tblInfo.Partition != nil {
-				pid := tablecodec.DecodeTableID(key)
 				e.physIDs = append(e.physIDs, pid)
 				if e.lock {
 					e.UpdateDeltaForTableID(pid)
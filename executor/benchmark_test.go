@@ -1861,10 +1861,11 @@ func BenchmarkMergeJoinExec(b *testing.B) {
 }
 
 type sortCase struct {
-	rows       int
-	orderByIdx []int
-	ndvs       []int
-	ctx        sessionctx.Context
+	rows        int
+	orderByIdx  []int
+	ndvs        []int
+	concurrency int
+	ctx         sessionctx.Context
 }
 
 func (tc sortCase) columns() []*expression.Column {
@@ -1875,7 +1876,7 @@ func (tc sortCase) columns() []*expression.Column {
 }
 
 func (tc sortCase) String() string {
-	return fmt.Sprintf("(rows:%v, orderBy:%v, ndvs: %v)", tc.rows, tc.orderByIdx, tc.ndvs)
+	return fmt.Sprintf("(rows:%v, orderBy:%v, ndvs: %v, concurrency: %v)", tc.rows, tc.orderByIdx, tc.ndvs, tc.concurrency)
 }
 
 func defaultSortTestCase() *sortCase {
@@ -1883,7 +1884,7 @@ func defaultSortTestCase() *sortCase {
 	ctx.GetSessionVars().InitChunkSize = variable.DefInitChunkSize
 	ctx.GetSessionVars().MaxChunkSize = variable.DefMaxChunkSize
 	ctx.GetSessionVars().StmtCtx.MemTracker = memory.NewTracker(-1, -1)
-	tc := &sortCase{rows: 300000, orderByIdx: []int{0, 1}, ndvs: []int{0, 0}, ctx: ctx}
+	tc := &sortCase{rows: 300000, orderByIdx: []int{0, 1}, ndvs: []int{0, 0}, concurrency: 1, ctx: ctx}
 	return tc
 }
 
@@ -1899,6 +1900,7 @@ func benchmarkSortExec(b *testing.B, cas *sortCase) {
 		BaseExecutor: exec.NewBaseExecutor(cas.ctx, dataSource.Schema(), 4, dataSource),
 		ByItems:      make([]*util.ByItems, 0, len(cas.orderByIdx)),
 		schema:       dataSource.Schema(),
+		concurrency:  cas.concurrency,
 	}
 	for _, idx := range cas.orderByIdx {
 		exec.ByItems = append(exec.ByItems, &util.ByItems{Expr: cas.columns()[idx]})
@@ -1960,6 +1962,16 @@ func BenchmarkSortExec(b *testing.B) {
 			benchmarkSortExec(b, cas)
 		})
 	}
+
+	// parallel sort
+	cas.ndvs = []int{0, 0}
+	cas.orderByIdx = []int{0, 1}
+	for _, concurrency := range []int{2, 4, 8} {
+		cas.concurrency = concurrency
+		b.Run(fmt.Sprintf("%v", cas), func(b *testing.B) {
+			benchmarkSortExec(b, cas)
+		})
+	}
 }
 
 type limitCase struct {
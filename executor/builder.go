@@ -271,6 +271,8 @@ func (b *executorBuilder) build(p plannercore.Plan) exec.Executor {
 		return b.buildProjection(v)
 	case *plannercore.PhysicalMemTable:
 		return b.buildMemTable(v)
+	case *plannercore.PhysicalTableChanges:
+		return b.buildTableChanges(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
 	case *plannercore.PhysicalApply:
@@ -983,6 +985,7 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) exec.Executor {
 		hasRefCols:                v.NeedFillDefaultValue,
 		SelectExec:                selectExec,
 		rowLen:                    v.RowLen,
+		rowAccessChecks:           v.RowAccessChecks,
 	}
 	err := ivs.initInsertColumns()
 	if err != nil {
@@ -1879,6 +1882,19 @@ func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) exec.
 	return e
 }
 
+func (b *executorBuilder) buildTableChanges(v *plannercore.PhysicalTableChanges) exec.Executor {
+	// The first two columns are the type and the commit ts of the changes.
+	rowSchema := expression.NewSchema(v.Schema().Columns[2:]...)
+	return &TableChangesExec{
+		BaseExecutor:     exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		table:            v.Table,
+		physicalTableIDs: v.PhysicalTableIDs,
+		startTS:          v.StartTS,
+		endTS:            v.EndTS,
+		rowSchema:        rowSchema,
+	}
+}
+
 // `getSnapshotTS` returns for-update-ts if in insert/update/delete/lock statement otherwise the isolation read ts
 // Please notice that in RC isolation, the above two ts are the same
 func (b *executorBuilder) getSnapshotTS() (ts uint64, err error) {
@@ -2052,6 +2068,9 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 			strings.ToLower(infoschema.TableTables),
 			strings.ToLower(infoschema.TableReferConst),
 			strings.ToLower(infoschema.TableSequences),
+			strings.ToLower(infoschema.TableMaskingPolicies),
+			strings.ToLower(infoschema.TableRowAccessPolicies),
+			strings.ToLower(infoschema.TableIntervalPartitions),
 			strings.ToLower(infoschema.TablePartitions),
 			strings.ToLower(infoschema.TableEngines),
 			strings.ToLower(infoschema.TableCollations),
@@ -2085,7 +2104,8 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 			strings.ToLower(infoschema.TableMemoryUsageOpsHistory),
 			strings.ToLower(infoschema.ClusterTableMemoryUsage),
 			strings.ToLower(infoschema.ClusterTableMemoryUsageOpsHistory),
-			strings.ToLower(infoschema.TableResourceGroups):
+			strings.ToLower(infoschema.TableResourceGroups),
+			strings.ToLower(infoschema.TableTimers):
 			return &MemTableReaderExec{
 				BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
@@ -2139,6 +2159,20 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 				table:        v.Table,
 				retriever:    buildStmtSummaryRetriever(b.ctx, v.Table, v.Columns, extractor),
 			}
+		case strings.ToLower(infoschema.TableClusterStatementsDigestHistory):
+			extractor, ok := v.Extractor.(*plannercore.StatementsSummaryExtractor)
+			if !ok {
+				extractor = &plannercore.StatementsSummaryExtractor{}
+			}
+			return &MemTableReaderExec{
+				BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+				table:        v.Table,
+				retriever: &stmtDigestHistoryRetriever{
+					table:     v.Table,
+					columns:   v.Columns,
+					extractor: extractor,
+				},
+			}
 		case strings.ToLower(infoschema.TableColumns):
 			return &MemTableReaderExec{
 				BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
@@ -2212,6 +2246,7 @@ func (b *executorBuilder) buildSort(v *plannercore.PhysicalSort) exec.Executor {
 		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
 		ByItems:      v.ByItems,
 		schema:       v.Schema(),
+		concurrency:  b.ctx.GetSessionVars().ExecutorConcurrency,
 	}
 	executor_metrics.ExecutorCounterSortExec.Inc()
 	return &sortExec
@@ -2464,6 +2499,7 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) exec.Executor {
 		tblID2table:               tblID2table,
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
+		rowAccessChecks:           v.RowAccessChecks,
 	}
 	updateExec.fkChecks, b.err = buildTblID2FKCheckExecs(b.ctx, tblID2table, v.FKChecks)
 	if b.err != nil {
@@ -4944,7 +4980,10 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) exec.Execut
 		resultColIdx++
 	}
 
-	if b.ctx.GetSessionVars().EnablePipelinedWindowExec {
+	// The whole partition has to be buffered by PipelinedWindowExec for the window functions without frame, so
+	// WindowExec is used for them if they can be spilled to disk.
+	spillable := windowSpillable(v)
+	if b.ctx.GetSessionVars().EnablePipelinedWindowExec && !(spillable && variable.EnableTmpStorageOnOOM.Load()) {
 		exec := &PipelinedWindowExec{
 			BaseExecutor:   base,
 			groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
@@ -5008,7 +5047,28 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) exec.Execut
 		processor:      processor,
 		groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
 		numWindowFuncs: len(v.WindowFuncDescs),
+		spillable:      spillable,
+	}
+}
+
+// windowSpillable returns whether the partitions of the window can be spilled to disk, which requires the window
+// to have no frame and the rows to have the columns from the child. The window functions have to fold the rows of
+// the partition incrementally, which holds for the aggregate functions, ROW_NUMBER, NTILE, FIRST_VALUE, LAST_VALUE
+// and NTH_VALUE. RANK, DENSE_RANK, CUME_DIST, PERCENT_RANK, LEAD and LAG keep all the rows of the partition in
+// their partial results, and the windows with frames access the rows of the partition by offset, so they're
+// evaluated in memory.
+func windowSpillable(v *plannercore.PhysicalWindow) bool {
+	if v.Frame != nil || v.Schema().Len() == len(v.WindowFuncDescs) {
+		return false
 	}
+	for _, desc := range v.WindowFuncDescs {
+		switch strings.ToLower(desc.Name) {
+		case ast.WindowFuncRank, ast.WindowFuncDenseRank, ast.WindowFuncCumeDist, ast.WindowFuncPercentRank,
+			ast.WindowFuncLead, ast.WindowFuncLag:
+			return false
+		}
+	}
+	return true
 }
 
 func (b *executorBuilder) buildShuffle(v *plannercore.PhysicalShuffle) *ShuffleExec {
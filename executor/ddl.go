@@ -157,6 +157,18 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 		err = e.executeCreateTable(x)
 	case *ast.CreateViewStmt:
 		err = e.executeCreateView(ctx, x)
+	case *ast.CreateMaterializedViewStmt:
+		err = e.executeCreateMaterializedView(ctx, x)
+	case *ast.DropMaterializedViewStmt:
+		err = e.executeDropMaterializedView(x)
+	case *ast.CreateMaskingPolicyStmt:
+		err = e.executeCreateMaskingPolicy(x)
+	case *ast.DropMaskingPolicyStmt:
+		err = e.executeDropMaskingPolicy(x)
+	case *ast.CreateRowAccessPolicyStmt:
+		err = e.executeCreateRowAccessPolicy(x)
+	case *ast.DropRowAccessPolicyStmt:
+		err = e.executeDropRowAccessPolicy(x)
 	case *ast.DropIndexStmt:
 		err = e.executeDropIndex(x)
 	case *ast.DropDatabaseStmt:
@@ -744,6 +756,22 @@ func (e *DDLExec) executeAlterPlacementPolicy(s *ast.AlterPlacementPolicyStmt) e
 	return domain.GetDomain(e.Ctx()).DDL().AlterPlacementPolicy(e.Ctx(), s)
 }
 
+func (e *DDLExec) executeCreateMaskingPolicy(s *ast.CreateMaskingPolicyStmt) error {
+	return domain.GetDomain(e.Ctx()).DDL().CreateMaskingPolicy(e.Ctx(), s)
+}
+
+func (e *DDLExec) executeDropMaskingPolicy(s *ast.DropMaskingPolicyStmt) error {
+	return domain.GetDomain(e.Ctx()).DDL().DropMaskingPolicy(e.Ctx(), s)
+}
+
+func (e *DDLExec) executeCreateRowAccessPolicy(s *ast.CreateRowAccessPolicyStmt) error {
+	return domain.GetDomain(e.Ctx()).DDL().CreateRowAccessPolicy(e.Ctx(), s)
+}
+
+func (e *DDLExec) executeDropRowAccessPolicy(s *ast.DropRowAccessPolicyStmt) error {
+	return domain.GetDomain(e.Ctx()).DDL().DropRowAccessPolicy(e.Ctx(), s)
+}
+
 func (e *DDLExec) executeCreateResourceGroup(s *ast.CreateResourceGroupStmt) error {
 	if !variable.EnableResourceControl.Load() && !e.Ctx().GetSessionVars().InRestrictedSQL {
 		return infoschema.ErrResourceGroupSupportDisabled
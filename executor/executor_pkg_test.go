@@ -29,6 +29,8 @@ import (
 	"github.com/pingcap/tidb/kv"
 	plannerutil "github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/helper"
+	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/collate"
@@ -37,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/testutils"
 )
 
 // Note: it's a tricky way to export the `inspectionSummaryRules` and `inspectionRules` for unit test but invisible for normal code
@@ -352,3 +355,34 @@ func TestSortSpillDisk(t *testing.T) {
 	err = exe.Close()
 	require.NoError(t, err)
 }
+
+func TestGetMVCCByKeyAfterRegionSplit(t *testing.T) {
+	var cluster testutils.Cluster
+	store, err := mockstore.NewMockStore(mockstore.WithClusterInspector(func(c testutils.Cluster) {
+		mockstore.BootstrapWithSingleStore(c)
+		cluster = c
+	}))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, store.Close())
+	}()
+
+	key := tablecodec.EncodeRowKeyWithHandle(1, kv.IntHandle(10))
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	require.NoError(t, txn.Set(key, []byte("v")))
+	require.NoError(t, txn.Commit(context.Background()))
+	info, err := getMVCCByKey(context.Background(), store.(helper.Storage), key)
+	require.NoError(t, err)
+	require.Len(t, info.Writes, 1)
+	commitTS := info.Writes[0].CommitTs
+
+	// The region in the region cache is stale after the split, the reading is retried in the new region.
+	region, _, _ := cluster.GetRegionByKey(key)
+	peerID := cluster.AllocID()
+	cluster.Split(region.GetId(), cluster.AllocID(), key, []uint64{peerID}, peerID)
+	info, err = getMVCCByKey(context.Background(), store.(helper.Storage), key)
+	require.NoError(t, err)
+	require.Len(t, info.Writes, 1)
+	require.Equal(t, commitTS, info.Writes[0].CommitTs)
+}
@@ -186,7 +186,7 @@ func (p *chunkProcessor) encodeLoop(ctx context.Context) error {
 			encodeDurStart := time.Now()
 			lastRow := p.parser.LastRow()
 			// sql -> kv
-			kvs, encodeErr := p.encoder.Encode(lastRow.Row, lastRow.RowID)
+			kvs, encodeErr := p.encoder.Encode(lastRow.Row, lastRow.RowID, lastRow.Missing)
 			encodeDur += time.Since(encodeDurStart)
 
 			if encodeErr != nil {
@@ -0,0 +1,99 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"github.com/pingcap/tidb/br/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/types"
+)
+
+// fieldNameParser wraps a parser whose rows have named fields, such as the ORC
+// parser, and reorders the values of the rows to the order of the field
+// mappings, so they can be encoded in the same way as the CSV rows.
+// The missing fields are marked in Row.Missing and the unknown fields are ignored.
+type fieldNameParser struct {
+	mydump.Parser
+
+	columns []string
+	// fieldIdx[i] is the index of the value of columns[i] in the rows of the
+	// underlying parser, -1 if it's missing.
+	fieldIdx []int
+	// missing is shared by all rows since the fields are missing in the schema
+	// of the file, it's nil if no field is missing.
+	missing []bool
+	lastRow mydump.Row
+}
+
+func newFieldNameParser(parser mydump.Parser, columns []string) *fieldNameParser {
+	fieldIndexes := make(map[string]int, len(parser.Columns()))
+	for i, name := range parser.Columns() {
+		if _, ok := fieldIndexes[name]; !ok {
+			fieldIndexes[name] = i
+		}
+	}
+	fieldIdx := make([]int, 0, len(columns))
+	var missing []bool
+	for i, col := range columns {
+		idx, ok := fieldIndexes[col]
+		if !ok {
+			idx = -1
+			if missing == nil {
+				missing = make([]bool, len(columns))
+			}
+			missing[i] = true
+		}
+		fieldIdx = append(fieldIdx, idx)
+	}
+	return &fieldNameParser{
+		Parser:   parser,
+		columns:  columns,
+		fieldIdx: fieldIdx,
+		missing:  missing,
+	}
+}
+
+// ReadRow implements the mydump.Parser interface.
+func (p *fieldNameParser) ReadRow() error {
+	if err := p.Parser.ReadRow(); err != nil {
+		return err
+	}
+	row := p.Parser.LastRow()
+	p.lastRow.RowID = row.RowID
+	p.lastRow.Length = row.Length
+	p.lastRow.Missing = p.missing
+	p.lastRow.Row = p.lastRow.Row[:0]
+	for _, idx := range p.fieldIdx {
+		var d types.Datum
+		if idx >= 0 && idx < len(row.Row) {
+			d = row.Row[idx]
+		}
+		p.lastRow.Row = append(p.lastRow.Row, d)
+	}
+	return nil
+}
+
+// LastRow implements the mydump.Parser interface.
+func (p *fieldNameParser) LastRow() mydump.Row {
+	return p.lastRow
+}
+
+// RecycleRow implements the mydump.Parser interface.
+func (*fieldNameParser) RecycleRow(_ mydump.Row) {
+}
+
+// Columns implements the mydump.Parser interface.
+func (p *fieldNameParser) Columns() []string {
+	return p.columns
+}
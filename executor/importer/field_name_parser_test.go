@@ -0,0 +1,287 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/lightning/backend/encode"
+	"github.com/pingcap/tidb/br/pkg/lightning/backend/kv"
+	"github.com/pingcap/tidb/br/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// orcTestDataDir contains sample.orc, which has 5 rows with the fields
+// id, name, score, ok, price, d, ts, s, l, m.
+const orcTestDataDir = "../../br/pkg/lightning/mydump/orc/testdata"
+
+func newTestController(t *testing.T, format, createTable, dir string, files ...string) *LoadDataController {
+	p := parser.New()
+	stmt, err := p.ParseOneStmt(createTable, "", "")
+	require.NoError(t, err)
+	sctx := mock.NewContext()
+	defer sctx.Close()
+	tblInfo, err := ddl.MockTableInfo(sctx, stmt.(*ast.CreateTableStmt), 1)
+	require.NoError(t, err)
+	tblInfo.State = 5 // public
+	tbl, err := tables.TableFromMeta(kv.NewPanickingAllocators(0), tblInfo)
+	require.NoError(t, err)
+
+	plan := &Plan{
+		DBName:       "test",
+		Path:         filepath.Join(dir, "*"),
+		Format:       format,
+		InImportInto: true,
+		SQLMode:      mysql.ModeStrictAllTables,
+	}
+	c, err := NewLoadDataController(plan, tbl, &ASTArgs{})
+	require.NoError(t, err)
+
+	c.dataStore, err = storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		info, err := os.Stat(filepath.Join(dir, f))
+		require.NoError(t, err)
+		c.dataFiles = append(c.dataFiles, &mydump.SourceFileMeta{Path: f, FileSize: info.Size()})
+		c.TotalFileSize += info.Size()
+	}
+	return c
+}
+
+func getTestParser(t *testing.T, c *LoadDataController, file string) mydump.Parser {
+	parser, err := c.GetParser(context.Background(), LoadDataReaderInfo{
+		Opener: func(ctx context.Context) (io.ReadSeekCloser, error) {
+			return c.dataStore.Open(ctx, file)
+		},
+	})
+	require.NoError(t, err)
+	return parser
+}
+
+func TestFieldNameParser(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalStorage(orcTestDataDir)
+	require.NoError(t, err)
+	r, err := store.Open(ctx, "sample.orc")
+	require.NoError(t, err)
+	orcParser, err := mydump.NewORCParser(ctx, r)
+	require.NoError(t, err)
+
+	parser := newFieldNameParser(orcParser, []string{"name", "not_exist", "id"})
+	defer func() {
+		require.NoError(t, parser.Close())
+	}()
+	require.Equal(t, []string{"name", "not_exist", "id"}, parser.Columns())
+
+	require.NoError(t, parser.ReadRow())
+	row := parser.LastRow()
+	require.Equal(t, int64(1), row.RowID)
+	require.Equal(t, []types.Datum{
+		types.NewCollationStringDatum(`name"a`, "utf8mb4_bin"),
+		{},
+		types.NewIntDatum(0),
+	}, row.Row)
+	require.Equal(t, []bool{false, true, false}, row.Missing)
+
+	// the rows whose id%3 == 2 are NULL except id, they're not missing.
+	require.NoError(t, parser.ReadRow())
+	require.NoError(t, parser.ReadRow())
+	row = parser.LastRow()
+	require.True(t, row.Row[0].IsNull())
+	require.True(t, row.Row[1].IsNull())
+	require.Equal(t, types.NewIntDatum(2), row.Row[2])
+	require.Equal(t, []bool{false, true, false}, row.Missing)
+
+	require.NoError(t, parser.ReadRow())
+	require.NoError(t, parser.ReadRow())
+	require.ErrorIs(t, errors.Cause(parser.ReadRow()), io.EOF)
+
+	// no field is missing
+	r, err = store.Open(ctx, "sample.orc")
+	require.NoError(t, err)
+	orcParser, err = mydump.NewORCParser(ctx, r)
+	require.NoError(t, err)
+	parser2 := newFieldNameParser(orcParser, []string{"id"})
+	defer func() {
+		require.NoError(t, parser2.Close())
+	}()
+	require.NoError(t, parser2.ReadRow())
+	require.Equal(t, []types.Datum{types.NewIntDatum(0)}, parser2.LastRow().Row)
+	require.Nil(t, parser2.LastRow().Missing)
+}
+
+func TestGetParserJSONLinesAndORC(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(`{"B": "x", "a": 1}`+"\n"+`{"a": 2}`), 0o644))
+
+	c := newTestController(t, DataFormatJSONLines, "create table t (a int, b varchar(10), c int)", dir, "a.jsonl")
+	parser := getTestParser(t, c, "a.jsonl")
+	require.IsType(t, &mydump.JSONLinesParser{}, parser)
+	require.Equal(t, []string{"a", "b", "c"}, parser.Columns())
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, []types.Datum{types.NewIntDatum(1), types.NewCollationStringDatum("x", "utf8mb4_bin"), {}}, parser.LastRow().Row)
+	require.Equal(t, []bool{false, false, true}, parser.LastRow().Missing)
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, []bool{false, true, true}, parser.LastRow().Missing)
+	require.NoError(t, parser.Close())
+
+	c = newTestController(t, DataFormatORC, "create table t (ID bigint, Name varchar(10), x int)", orcTestDataDir, "sample.orc")
+	parser = getTestParser(t, c, "sample.orc")
+	require.IsType(t, &fieldNameParser{}, parser)
+	require.Equal(t, []string{"id", "name", "x"}, parser.Columns())
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, []types.Datum{types.NewIntDatum(0), types.NewCollationStringDatum(`name"a`, "utf8mb4_bin"), {}}, parser.LastRow().Row)
+	require.Equal(t, []bool{false, false, true}, parser.LastRow().Missing)
+	require.NoError(t, parser.Close())
+
+	// the file is not an ORC file
+	_, err := c.GetParser(context.Background(), LoadDataReaderInfo{
+		Opener: func(ctx context.Context) (io.ReadSeekCloser, error) {
+			return mydump.NewStringReader("not an orc file"), nil
+		},
+	})
+	require.ErrorContains(t, err, "orc")
+}
+
+func TestCheckSourceSchema(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(`{"a": 1, "b": "x"}`+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.jsonl"), []byte("\n\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.jsonl"), []byte("[1]\n"), 0o644))
+
+	cases := []struct {
+		format      string
+		createTable string
+		dir         string
+		file        string
+		err         string
+	}{
+		{DataFormatJSONLines, "create table t (a int, b varchar(10), c int)", dir, "a.jsonl", ""},
+		// the missing NOT NULL column with default value is fine
+		{DataFormatJSONLines, "create table t (a int, c int not null default 1)", dir, "a.jsonl", ""},
+		{DataFormatJSONLines, "create table t (a int, c int not null auto_increment primary key)", dir, "a.jsonl", ""},
+		{DataFormatJSONLines, "create table t (a int, c int as (a + 1), d int not null)", dir, "a.jsonl",
+			"NOT NULL column(s) [d] without default value are missing in file 'a.jsonl'"},
+		{DataFormatJSONLines, "create table t (x int, y int)", dir, "a.jsonl",
+			"no field of file 'a.jsonl' matches the columns of target table, fields of the file are [a,b]"},
+		{DataFormatJSONLines, "create table t (x int)", dir, "empty.jsonl", ""},
+		{DataFormatJSONLines, "create table t (x int)", dir, "bad.jsonl", "failed to read the first row of file 'bad.jsonl'"},
+		{DataFormatORC, "create table t (id int, name varchar(10), x int)", orcTestDataDir, "sample.orc", ""},
+		{DataFormatORC, "create table t (id int, x int not null)", orcTestDataDir, "sample.orc",
+			"NOT NULL column(s) [x] without default value are missing in file 'sample.orc'"},
+		{DataFormatORC, "create table t (x int)", dir, "a.jsonl", "failed to read schema of file 'a.jsonl'"},
+	}
+	for _, c := range cases {
+		ctrl := newTestController(t, c.format, c.createTable, c.dir, c.file)
+		err := ctrl.checkSourceSchema(ctx)
+		if c.err == "" {
+			require.NoError(t, err, c.createTable)
+		} else {
+			require.ErrorContains(t, err, c.err, c.createTable)
+		}
+	}
+
+	// other formats are not checked
+	ctrl := newTestController(t, DataFormatCSV, "create table t (x int not null)", dir, "a.jsonl")
+	require.NoError(t, ctrl.checkSourceSchema(ctx))
+}
+
+func TestEncodeMissingFields(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(`{"a": 1, "b": null}`+"\n"+`{"a": 2}`), 0o644))
+	c := newTestController(t, DataFormatJSONLines, "create table t (a int, b int default 10, c varchar(10) not null default 'x')", dir, "a.jsonl")
+
+	ti := &TableImporter{LoadDataController: c}
+	encoder, err := newTableKVEncoder(&encode.EncodingConfig{
+		SessionOptions: encode.SessionOptions{SQLMode: c.SQLMode},
+		Table:          c.Table,
+	}, ti)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, encoder.Close())
+	}()
+
+	parser := getTestParser(t, c, "a.jsonl")
+	defer func() {
+		require.NoError(t, parser.Close())
+	}()
+
+	// the explicit null is kept, and the missing fields are filled with the default values.
+	require.NoError(t, parser.ReadRow())
+	row := parser.LastRow()
+	record, err := encoder.parserData2TableData(row.Row, row.RowID, row.Missing)
+	require.NoError(t, err)
+	require.Equal(t, []types.Datum{types.NewIntDatum(1), {}, types.NewStringDatum("x")}, record[:3])
+
+	require.NoError(t, parser.ReadRow())
+	row = parser.LastRow()
+	record, err = encoder.parserData2TableData(row.Row, row.RowID, row.Missing)
+	require.NoError(t, err)
+	require.Equal(t, []types.Datum{types.NewIntDatum(2), types.NewIntDatum(10), types.NewStringDatum("x")}, record[:3])
+
+	_, err = encoder.Encode(row.Row, row.RowID, row.Missing)
+	require.NoError(t, err)
+}
+
+func TestEncodeJSONColumn(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"id": 1, "j": {"a": true, "b": [1, "x"]}}
+{"id": 2, "j": true}
+{"id": 3, "j": "abc"}
+{"id": 4, "j": [false, null]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(content), 0o644))
+	c := newTestController(t, DataFormatJSONLines, "create table t (id int, j json)", dir, "a.jsonl")
+
+	ti := &TableImporter{LoadDataController: c}
+	encoder, err := newTableKVEncoder(&encode.EncodingConfig{
+		SessionOptions: encode.SessionOptions{SQLMode: c.SQLMode},
+		Table:          c.Table,
+	}, ti)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, encoder.Close())
+	}()
+
+	parser := getTestParser(t, c, "a.jsonl")
+	defer func() {
+		require.NoError(t, parser.Close())
+	}()
+
+	// the objects, booleans and strings are imported as JSON values.
+	for _, expected := range []string{`{"a": true, "b": [1, "x"]}`, `true`, `"abc"`, `[false, null]`} {
+		require.NoError(t, parser.ReadRow())
+		row := parser.LastRow()
+		record, err := encoder.parserData2TableData(row.Row, row.RowID, row.Missing)
+		require.NoError(t, err)
+		require.Equal(t, types.KindMysqlJSON, record[1].Kind())
+		require.Equal(t, expected, record[1].GetMysqlJSON().String())
+		_, err = encoder.Encode(row.Row, row.RowID, row.Missing)
+		require.NoError(t, err)
+	}
+}
@@ -69,6 +69,11 @@ const (
 	DataFormatSQL = "sql"
 	// DataFormatParquet represents the data source file of IMPORT INTO is parquet.
 	DataFormatParquet = "parquet"
+	// DataFormatJSONLines represents the data source file of IMPORT INTO is JSON Lines,
+	// which is also known as NDJSON.
+	DataFormatJSONLines = "jsonl"
+	// DataFormatORC represents the data source file of IMPORT INTO is orc.
+	DataFormatORC = "orc"
 
 	// DefaultDiskQuota is the default disk quota for IMPORT INTO
 	DefaultDiskQuota = config.ByteSize(50 << 30) // 50GiB
@@ -321,6 +326,10 @@ func NewImportPlan(userSctx sessionctx.Context, plan *plannercore.ImportInto, tb
 	var format string
 	if plan.Format != nil {
 		format = strings.ToLower(*plan.Format)
+		// NDJSON is another name of JSON Lines.
+		if format == "ndjson" {
+			format = DataFormatJSONLines
+		}
 	} else {
 		// without FORMAT 'xxx' clause, default to CSV
 		format = DataFormatCSV
@@ -433,7 +442,9 @@ func (e *LoadDataController) checkFieldParams() error {
 		return exeerrors.ErrLoadDataEmptyPath
 	}
 	if e.InImportInto {
-		if e.Format != DataFormatCSV && e.Format != DataFormatParquet && e.Format != DataFormatSQL {
+		switch e.Format {
+		case DataFormatCSV, DataFormatParquet, DataFormatSQL, DataFormatJSONLines, DataFormatORC:
+		default:
 			return exeerrors.ErrLoadDataUnsupportedFormat.GenWithStackByArgs(e.Format)
 		}
 	} else {
@@ -688,6 +699,32 @@ func (p *Plan) initParameters(plan *plannercore.ImportInto) error {
 	return nil
 }
 
+// fieldMappingNames returns the _lower-case_ names of the field mappings, i.e.
+// the column name or the user variable name. They're used to map the fields of
+// the data file by name, such as the JSON Lines and ORC format.
+func (e *LoadDataController) fieldMappingNames() []string {
+	names := make([]string, 0, len(e.FieldMappings))
+	for _, m := range e.FieldMappings {
+		if m.Column != nil {
+			names = append(names, m.Column.Name.L)
+		} else {
+			names = append(names, strings.ToLower(m.UserVar.Name))
+		}
+	}
+	return names
+}
+
+// jsonColumnNames returns the lower-case names of the JSON columns in the field mappings.
+func (e *LoadDataController) jsonColumnNames() []string {
+	var names []string
+	for _, m := range e.FieldMappings {
+		if m.Column != nil && m.Column.GetType() == mysql.TypeJSON {
+			names = append(names, m.Column.Name.L)
+		}
+	}
+	return names
+}
+
 // initFieldMappings make a field mapping slice to implicitly map input field to table column or user defined variable
 // the slice's order is the same as the order of the input fields.
 // Returns a slice of same ordered column names without user defined variable names.
@@ -840,7 +877,7 @@ func (e *LoadDataController) InitDataFiles(ctx context.Context) error {
 		}
 		// we add this check for security, we don't want user import any sensitive system files,
 		// most of which is readable text file and don't have a suffix, such as /etc/passwd
-		if !slices.Contains([]string{".csv", ".sql", ".parquet"}, strings.ToLower(filepath.Ext(e.Path))) {
+		if !slices.Contains([]string{".csv", ".sql", ".parquet", ".jsonl", ".ndjson", ".orc"}, strings.ToLower(filepath.Ext(e.Path))) {
 			return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs("the file suffix is not supported when import from server disk")
 		}
 		dir := filepath.Dir(e.Path)
@@ -948,6 +985,10 @@ func (e *LoadDataController) getSourceType() mydump.SourceType {
 	switch e.Format {
 	case DataFormatParquet:
 		return mydump.SourceTypeParquet
+	case DataFormatJSONLines:
+		return mydump.SourceTypeJSONLines
+	case DataFormatORC:
+		return mydump.SourceTypeORC
 	case DataFormatDelimitedData, DataFormatCSV:
 		return mydump.SourceTypeCSV
 	default:
@@ -1026,6 +1067,22 @@ func (e *LoadDataController) GetParser(
 			reader,
 			dataFileInfo.Remote.Path,
 		)
+	case DataFormatJSONLines:
+		jsonParser := mydump.NewJSONLinesParser(
+			ctx,
+			reader,
+			LoadDataReadBlockSize,
+			nil,
+			e.fieldMappingNames(),
+		)
+		jsonParser.SetJSONColumns(e.jsonColumnNames())
+		parser = jsonParser
+	case DataFormatORC:
+		var orcParser *mydump.ORCParser
+		orcParser, err = mydump.NewORCParser(ctx, reader)
+		if err == nil {
+			parser = newFieldNameParser(orcParser, e.fieldMappingNames())
+		}
 	}
 	if err != nil {
 		return nil, exeerrors.ErrLoadDataWrongFormatConfig.GenWithStack(err.Error())
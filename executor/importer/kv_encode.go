@@ -34,7 +34,9 @@ import (
 )
 
 type kvEncoder interface {
-	Encode(row []types.Datum, rowID int64) (*kv.Pairs, error)
+	// Encode encodes a row, the values marked in `missing` are filled with the
+	// default values of the columns. `missing` can be nil if all values exist.
+	Encode(row []types.Datum, rowID int64, missing []bool) (*kv.Pairs, error)
 	// GetLastInsertID returns the first auto-generated ID in the current encoder.
 	// if there's no auto-generated id column or the column value is not auto-generated, it will be 0.
 	GetLastInsertID() uint64
@@ -80,13 +82,13 @@ func newTableKVEncoder(
 }
 
 // Encode implements the kvEncoder interface.
-func (en *tableKVEncoder) Encode(row []types.Datum, rowID int64) (*kv.Pairs, error) {
+func (en *tableKVEncoder) Encode(row []types.Datum, rowID int64, missing []bool) (*kv.Pairs, error) {
 	// we ignore warnings when encoding rows now, but warnings uses the same memory as parser, since the input
 	// row []types.Datum share the same underlying buf, and when doing CastValue, we're using hack.String/hack.Slice.
 	// when generating error such as mysql.ErrDataOutOfRange, the data will be part of the error, causing the buf
 	// unable to release. So we truncate the warnings here.
 	defer en.TruncateWarns()
-	record, err := en.parserData2TableData(row, rowID)
+	record, err := en.parserData2TableData(row, rowID, missing)
 	if err != nil {
 		return nil, err
 	}
@@ -107,8 +109,10 @@ func (en *tableKVEncoder) GetColumnSize() map[int64]int64 {
 }
 
 // todo merge with code in load_data.go
-func (en *tableKVEncoder) parserData2TableData(parserData []types.Datum, rowID int64) ([]types.Datum, error) {
+func (en *tableKVEncoder) parserData2TableData(parserData []types.Datum, rowID int64, missing []bool) ([]types.Datum, error) {
 	row := make([]types.Datum, 0, len(en.insertColumns))
+	// hasValue[i] is false if the value of en.insertColumns[i] is missing in the data file.
+	hasValue := make([]bool, 0, len(en.insertColumns))
 	sessionVars := en.SessionCtx.GetSessionVars()
 	setVar := func(name string, col *types.Datum) {
 		// User variable names are not case-sensitive
@@ -131,10 +135,22 @@ func (en *tableKVEncoder) parserData2TableData(parserData []types.Datum, rowID i
 			// If some columns is missing and their type is time and has not null flag, they should be set as current time.
 			if types.IsTypeTime(en.fieldMappings[i].Column.GetType()) && mysql.HasNotNullFlag(en.fieldMappings[i].Column.GetFlag()) {
 				row = append(row, types.NewTimeDatum(types.CurrentTime(en.fieldMappings[i].Column.GetType())))
+				hasValue = append(hasValue, true)
 				continue
 			}
 
 			row = append(row, types.NewDatum(nil))
+			hasValue = append(hasValue, true)
+			continue
+		}
+
+		if i < len(missing) && missing[i] {
+			if en.fieldMappings[i].Column == nil {
+				setVar(en.fieldMappings[i].UserVar.Name, nil)
+				continue
+			}
+			row = append(row, types.NewDatum(nil))
+			hasValue = append(hasValue, false)
 			continue
 		}
 
@@ -144,6 +160,7 @@ func (en *tableKVEncoder) parserData2TableData(parserData []types.Datum, rowID i
 		}
 
 		row = append(row, parserData[i])
+		hasValue = append(hasValue, true)
 	}
 	for i := 0; i < len(en.columnAssignments); i++ {
 		// eval expression of `SET` clause
@@ -152,10 +169,11 @@ func (en *tableKVEncoder) parserData2TableData(parserData []types.Datum, rowID i
 			return nil, err
 		}
 		row = append(row, d)
+		hasValue = append(hasValue, true)
 	}
 
 	// a new row buffer will be allocated in getRow
-	newRow, err := en.getRow(row, rowID)
+	newRow, err := en.getRow(row, hasValue, rowID)
 	if err != nil {
 		return nil, err
 	}
@@ -166,11 +184,15 @@ func (en *tableKVEncoder) parserData2TableData(parserData []types.Datum, rowID i
 // getRow gets the row which from `insert into select from` or `load data`.
 // The input values from these two statements are datums instead of
 // expressions which are used in `insert into set x=y`.
+// The columns whose valHasValue is false are filled with the default values.
 // copied from InsertValues
-func (en *tableKVEncoder) getRow(vals []types.Datum, rowID int64) ([]types.Datum, error) {
+func (en *tableKVEncoder) getRow(vals []types.Datum, valHasValue []bool, rowID int64) ([]types.Datum, error) {
 	row := make([]types.Datum, len(en.Columns))
 	hasValue := make([]bool, len(en.Columns))
 	for i := 0; i < len(en.insertColumns); i++ {
+		if !valHasValue[i] {
+			continue
+		}
 		casted, err := table.CastValue(en.SessionCtx, vals[i], en.insertColumns[i].ToInfo(), false, false)
 		if err != nil {
 			return nil, err
@@ -17,14 +17,19 @@ package importer
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/br/pkg/lightning/common"
+	"github.com/pingcap/tidb/br/pkg/lightning/mydump"
 	"github.com/pingcap/tidb/br/pkg/streamhelper"
 	"github.com/pingcap/tidb/br/pkg/utils"
 	tidb "github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/etcd"
@@ -39,6 +44,7 @@ const (
 // we check the following things here:
 //  1. target table should be empty
 //  2. no CDC or PiTR tasks running
+//  3. fields of JSON Lines and ORC files can be mapped to the target table
 //
 // todo: check if there's running lightning tasks?
 // we check them one by one, and return the first error we meet.
@@ -48,6 +54,9 @@ func (e *LoadDataController) CheckRequirements(ctx context.Context, conn sqlexec
 	if err := e.checkTotalFileSize(); err != nil {
 		return err
 	}
+	if err := e.checkSourceSchema(ctx); err != nil {
+		return err
+	}
 	if err := e.checkTableEmpty(ctx, conn); err != nil {
 		return err
 	}
@@ -64,6 +73,94 @@ func (e *LoadDataController) checkTotalFileSize() error {
 	return nil
 }
 
+// checkSourceSchema checks the fields of the first data file against the field
+// mappings for the formats which map the fields by name. The fields are read
+// from the ORC file schema, or inferred from the first object of the JSON Lines
+// file.
+func (e *LoadDataController) checkSourceSchema(ctx context.Context) error {
+	if (e.Format != DataFormatJSONLines && e.Format != DataFormatORC) || len(e.dataFiles) == 0 {
+		return nil
+	}
+	fileMeta := e.dataFiles[0]
+	reader, err := mydump.OpenReader(ctx, fileMeta, e.dataStore)
+	if err != nil {
+		return exeerrors.ErrLoadDataCantRead.GenWithStackByArgs(GetMsgFromBRError(err), "failed to open data file")
+	}
+	var parser mydump.Parser
+	if e.Format == DataFormatORC {
+		parser, err = mydump.NewORCParser(ctx, reader)
+		if err != nil {
+			terror.Log(reader.Close())
+			return exeerrors.ErrLoadDataPreCheckFailed.FastGenByArgs(
+				fmt.Sprintf("failed to read schema of file '%s': %s", fileMeta.Path, err.Error()))
+		}
+	} else {
+		parser = mydump.NewJSONLinesParser(ctx, reader, LoadDataReadBlockSize, nil, nil)
+		if err = parser.ReadRow(); err != nil {
+			terror.Log(parser.Close())
+			if errors.Cause(err) == io.EOF {
+				return nil
+			}
+			return exeerrors.ErrLoadDataPreCheckFailed.FastGenByArgs(
+				fmt.Sprintf("failed to read the first row of file '%s': %s", fileMeta.Path, err.Error()))
+		}
+	}
+	fields := parser.Columns()
+	terror.Log(parser.Close())
+	return e.checkFieldsAgainstMappings(fileMeta.Path, fields)
+}
+
+// checkFieldsAgainstMappings checks that at least one field of the data file is
+// mapped, and the missing fields won't fail the import since they're filled with
+// the default values of the columns.
+func (e *LoadDataController) checkFieldsAgainstMappings(path string, fields []string) error {
+	fieldSet := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		fieldSet[f] = struct{}{}
+	}
+
+	autoRandomColID := int64(-1)
+	if tblInfo := e.Table.Meta(); tblInfo.ContainsAutoRandomBits() {
+		if col := tblInfo.GetPkColInfo(); col != nil {
+			autoRandomColID = col.ID
+		}
+	}
+	matched := false
+	var missing []string
+	for i, name := range e.fieldMappingNames() {
+		if _, ok := fieldSet[name]; ok {
+			matched = true
+			continue
+		}
+		col := e.FieldMappings[i].Column
+		if col == nil {
+			continue
+		}
+		flag := col.GetFlag()
+		if mysql.HasNotNullFlag(flag) && !mysql.HasAutoIncrementFlag(flag) && hasNoDefaultValue(col) &&
+			!col.IsGenerated() && col.ID != autoRandomColID {
+			missing = append(missing, col.Name.O)
+		}
+	}
+	if !matched {
+		return exeerrors.ErrLoadDataPreCheckFailed.FastGenByArgs(
+			fmt.Sprintf("no field of file '%s' matches the columns of target table, fields of the file are [%s]",
+				path, strings.Join(fields, ",")))
+	}
+	if len(missing) > 0 {
+		return exeerrors.ErrLoadDataPreCheckFailed.FastGenByArgs(
+			fmt.Sprintf("NOT NULL column(s) [%s] without default value are missing in file '%s'",
+				strings.Join(missing, ","), path))
+	}
+	return nil
+}
+
+// hasNoDefaultValue returns whether the column has neither default value nor
+// default expression.
+func hasNoDefaultValue(col *table.Column) bool {
+	return col.GetDefaultValue() == nil && !col.DefaultIsExpr
+}
+
 func (e *LoadDataController) checkTableEmpty(ctx context.Context, conn sqlexec.SQLExecutor) error {
 	sql := fmt.Sprintf("SELECT 1 FROM %s USE INDEX() LIMIT 1", common.UniqueTable(e.DBName, e.Table.Meta().Name.L))
 	rs, err := conn.ExecuteInternal(ctx, sql)
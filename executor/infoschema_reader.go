@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/deadlock"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/pingcap/tidb/ddl/intervalpartition"
 	"github.com/pingcap/tidb/ddl/label"
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/domain"
@@ -42,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -57,6 +59,7 @@ import (
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
+	timerapi "github.com/pingcap/tidb/timer/api"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -122,6 +125,12 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataFromReferConst(ctx, sctx, dbs)
 		case infoschema.TableSequences:
 			e.setDataFromSequences(sctx, dbs)
+		case infoschema.TableMaskingPolicies:
+			e.setDataFromMaskingPolicies(sctx, dbs)
+		case infoschema.TableRowAccessPolicies:
+			e.setDataFromRowAccessPolicies(sctx, dbs)
+		case infoschema.TableIntervalPartitions:
+			err = e.setDataFromIntervalPartitions(ctx, sctx, dbs)
 		case infoschema.TablePartitions:
 			err = e.setDataFromPartitions(ctx, sctx, dbs)
 		case infoschema.TableClusterInfo:
@@ -192,6 +201,8 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForClusterMemoryUsageOpsHistory(sctx)
 		case infoschema.TableResourceGroups:
 			err = e.setDataFromResourceGroups()
+		case infoschema.TableTimers:
+			err = e.setDataFromUserTimers(ctx, sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -2220,6 +2231,121 @@ func (e *memtableRetriever) setDataFromSequences(ctx sessionctx.Context, schemas
 	e.rows = rows
 }
 
+func (e *memtableRetriever) setDataFromMaskingPolicies(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			if len(table.MaskingPolicies) == 0 {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, policy := range table.MaskingPolicies {
+				colName := ""
+				if col := model.FindColumnInfoByID(table.Columns, policy.ColumnID); col != nil {
+					colName = col.Name.O
+				}
+				record := types.MakeDatums(
+					schema.Name.O,                   // TABLE_SCHEMA
+					table.Name.O,                    // TABLE_NAME
+					policy.Name.O,                   // POLICY_NAME
+					colName,                         // COLUMN_NAME
+					string(policy.Type),             // MASKING_TYPE
+					strings.Join(policy.Args, ", "), // MASKING_ARGS
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataFromRowAccessPolicies(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			if len(table.RowAccessPolicies) == 0 {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, policy := range table.RowAccessPolicies {
+				record := types.MakeDatums(
+					schema.Name.O,     // TABLE_SCHEMA
+					table.Name.O,      // TABLE_NAME
+					policy.Name.O,     // POLICY_NAME
+					policy.ExprString, // POLICY_EXPRESSION
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataFromIntervalPartitions(ctx context.Context, sctx sessionctx.Context, schemas []*model.DBInfo) error {
+	summary := &intervalpartition.TimerSummary{}
+	if store := domain.GetDomain(sctx).IntervalPartitionTimerStore(); store != nil {
+		timer, err := timerapi.NewDefaultTimerClient(store).GetTimerByKey(ctx, intervalpartition.TimerKey)
+		if err != nil && !errors.ErrorEqual(err, timerapi.ErrTimerNotExist) {
+			return err
+		}
+		if timer != nil {
+			if summary, err = intervalpartition.DecodeTimerSummary(timer); err != nil {
+				return err
+			}
+		}
+	}
+
+	checker := privilege.GetPrivilegeManager(sctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			info := table.IntervalPartitionInfo
+			if info == nil {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			interval := fmt.Sprintf("%s %s", info.IntervalExprStr, ast.TimeUnitType(info.IntervalTimeUnit).String())
+			var retention interface{}
+			if info.RetentionExprStr != "" {
+				retention = fmt.Sprintf("%s %s", info.RetentionExprStr, ast.TimeUnitType(info.RetentionTimeUnit).String())
+			}
+			record := types.MakeDatums(
+				schema.Name.O,  // TABLE_SCHEMA
+				table.Name.O,   // TABLE_NAME
+				interval,       // PARTITION_INTERVAL
+				info.Precreate, // PARTITION_PRECREATE
+				retention,      // PARTITION_RETENTION
+				nil,            // LAST_CHECK_TIME
+				nil,            // LAST_STATUS
+				nil,            // LAST_ERROR
+				nil,            // LAST_SUCCESS_TIME
+				nil,            // LAST_CHANGE
+				nil,            // LAST_CHANGE_TIME
+			)
+			// The status is empty if the table has not been checked by the background worker.
+			if status, ok := summary.Tables[table.ID]; ok {
+				record[5] = types.NewDatum(timerTimeValue(sctx, status.LastCheckTime))
+				record[6] = types.NewStringDatum(status.LastStatus)
+				record[7] = types.NewStringDatum(status.LastError)
+				record[8] = types.NewDatum(timerTimeValue(sctx, status.LastSuccessTime))
+				record[9] = types.NewStringDatum(status.LastChange)
+				record[10] = types.NewDatum(timerTimeValue(sctx, status.LastChangeTime))
+			}
+			rows = append(rows, record)
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
 // dataForTableTiFlashReplica constructs data for table tiflash replica info.
 func (e *memtableRetriever) dataForTableTiFlashReplica(ctx sessionctx.Context, schemas []*model.DBInfo) {
 	checker := privilege.GetPrivilegeManager(ctx)
@@ -3323,6 +3449,49 @@ func (e *memtableRetriever) setDataFromResourceGroups() error {
 	return nil
 }
 
+func (e *memtableRetriever) setDataFromUserTimers(ctx context.Context, sctx sessionctx.Context) error {
+	// Only the users who can manage the timers are able to see them, because the statements of the timers
+	// are executed with the internal sessions.
+	if !hasTimerAdminPriv(sctx) {
+		return nil
+	}
+
+	timers, err := listUserTimers(ctx, sctx, "")
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]types.Datum, 0, len(timers))
+	for _, timer := range timers {
+		row := types.MakeDatums(
+			timer.data.Schema,
+			timer.data.Name,
+			string(timer.SchedPolicyType),
+			timer.SchedPolicyExpr,
+			timer.TimeZone,
+			timerEnabledValue(timer.Enable),
+			timerTimeValue(sctx, timer.Watermark),
+			timerNextEventTimeValue(sctx, timer),
+			string(timer.EventStatus),
+			timerTimeValue(sctx, timer.EventStart),
+			timer.summary.LastEventStatus,
+			timerTimeValue(sctx, timer.summary.LastEventStart),
+			timerTimeValue(sctx, timer.summary.LastEventEnd),
+			timer.summary.LastEventError,
+			timer.summary.LastAffectedRows,
+			timer.summary.TotalSuccessCount,
+			timer.summary.TotalFailedCount,
+			timer.data.SQL,
+			timer.data.Comment,
+			timer.data.Creator,
+			timerTimeValue(sctx, timer.CreateTime),
+		)
+		rows = append(rows, row)
+	}
+	e.rows = rows
+	return nil
+}
+
 func checkRule(rule *label.Rule) (dbName, tableName string, partitionName string, err error) {
 	s := strings.Split(rule.ID, "/")
 	if len(s) < 3 {
@@ -159,11 +159,15 @@ func prefetchConflictedOldRows(ctx context.Context, txn kv.Transaction, rows []t
 					// temp indexes.
 					continue
 				}
-				handle, err := tablecodec.DecodeHandleInUniqueIndexValue(val, uk.commonHandle)
+				handle, err := tablecodec.DecodeHandleInGlobalIndexValue(val, uk.commonHandle)
 				if err != nil {
 					return err
 				}
-				batchKeys = append(batchKeys, tablecodec.EncodeRecordKey(r.t.RecordPrefix(), handle))
+				if isDroppedPartitionHandle(r.t, handle) {
+					continue
+				}
+				t, handle := getDupRowTable(r.t, handle)
+				batchKeys = append(batchKeys, tablecodec.EncodeRecordKey(t.RecordPrefix(), handle))
 			}
 		}
 	}
@@ -187,7 +191,8 @@ func (e *InsertValues) prefetchDataCache(ctx context.Context, txn kv.Transaction
 
 // updateDupRow updates a duplicate row to a new row.
 func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Transaction, row toBeCheckedRow, handle kv.Handle, onDuplicate []*expression.Assignment) error {
-	oldRow, err := getOldRow(ctx, e.Ctx(), txn, row.t, handle, e.GenExprs)
+	t, handle := getDupRowTable(row.t, handle)
+	oldRow, err := getOldRow(ctx, e.Ctx(), txn, t, handle, e.GenExprs)
 	if err != nil {
 		return err
 	}
@@ -257,7 +262,7 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 			if err != nil {
 				return err
 			}
-			if handle == nil {
+			if handle == nil || isDroppedPartitionHandle(r.t, handle) {
 				continue
 			}
 			err = e.updateDupRow(ctx, i, txn, r, handle, e.OnDuplicate)
@@ -382,6 +387,10 @@ func (e *InsertExec) initEvalBuffer4Dup() {
 // doDupRowUpdate updates the duplicate row.
 func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRow []types.Datum, newRow []types.Datum,
 	extraCols []types.Datum, cols []*expression.Assignment, idxInBatch int) error {
+	// The row which can't be accessed by the user must not be updated.
+	if err := checkRowAccessPolicies(e.Ctx(), e.rowAccessChecks, e.Table.Meta(), oldRow); err != nil {
+		return err
+	}
 	assignFlag := make([]bool, len(e.Table.WritableCols()))
 	// See http://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
 	e.curInsertVals.SetDatums(newRow...)
@@ -426,6 +435,9 @@ func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRo
 	}
 
 	newData := e.row4Update[:len(oldRow)]
+	if err := checkRowAccessPolicies(e.Ctx(), e.rowAccessChecks, e.Table.Meta(), newData); err != nil {
+		return err
+	}
 	_, err := updateRecord(ctx, e.Ctx(), handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker, e.fkChecks, e.fkCascades)
 	if err != nil {
 		return err
@@ -93,6 +93,9 @@ type InsertValues struct {
 
 	stats *InsertRuntimeStat
 
+	// rowAccessChecks contains the row access policies checked against the written rows.
+	rowAccessChecks []*core.RowAccessCheck
+
 	// fkChecks contains the foreign key checkers.
 	fkChecks   []*FKCheckExec
 	fkCascades []*FKCascadeExec
@@ -739,6 +742,9 @@ func (e *InsertValues) fillRow(ctx context.Context, row []types.Datum, hasValue
 			return nil, err
 		}
 	}
+	if err := checkRowAccessPolicies(e.Ctx(), e.rowAccessChecks, tbl, row); err != nil {
+		return nil, err
+	}
 	return row, nil
 }
 
@@ -1260,7 +1266,11 @@ CheckAndInsert:
 		}
 
 		for _, uk := range r.uniqueKeys {
-			_, err := txn.Get(ctx, uk.newKey)
+			val, err := txn.Get(ctx, uk.newKey)
+			if err == nil && isStaleGlobalIndexValue(r.t, uk, val) {
+				// The entry is overwritten when adding the record.
+				continue
+			}
 			if err == nil {
 				if !replace {
 					// If duplicate keys were found in BatchGet, mark row = nil.
@@ -1328,7 +1338,8 @@ func (e *InsertValues) removeRow(
 	inReplace bool,
 ) (bool, error) {
 	newRow := r.row
-	oldRow, err := getOldRow(ctx, e.Ctx(), txn, r.t, handle, e.GenExprs)
+	t, handle := getDupRowTable(r.t, handle)
+	oldRow, err := getOldRow(ctx, e.Ctx(), txn, t, handle, e.GenExprs)
 	if err != nil {
 		logutil.BgLogger().Error(
 			"get old row failed when replace",
@@ -1340,6 +1351,10 @@ func (e *InsertValues) removeRow(
 		}
 		return false, err
 	}
+	// The row which can't be accessed by the user must not be replaced.
+	if err := checkRowAccessPolicies(e.Ctx(), e.rowAccessChecks, e.Table.Meta(), oldRow); err != nil {
+		return false, err
+	}
 
 	identical, err := e.equalDatumsAsBinary(oldRow, newRow)
 	if err != nil {
@@ -1353,13 +1368,13 @@ func (e *InsertValues) removeRow(
 		if e.Ctx().GetSessionVars().LockUnchangedKeys {
 			keySet |= lockUniqueKeys
 		}
-		if _, err := addUnchangedKeysForLockByRow(e.Ctx(), r.t, handle, oldRow, keySet); err != nil {
+		if _, err := addUnchangedKeysForLockByRow(e.Ctx(), t, handle, oldRow, keySet); err != nil {
 			return false, err
 		}
 		return true, nil
 	}
 
-	err = r.t.RemoveRecord(e.Ctx(), handle, oldRow)
+	err = t.RemoveRecord(e.Ctx(), handle, oldRow)
 	if err != nil {
 		return false, err
 	}
@@ -16,8 +16,10 @@ package exec
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ngaut/pools"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
@@ -61,6 +63,8 @@ type BaseExecutor struct {
 	id            int
 	initCap       int
 	maxChunkSize  int
+	// parentSpan is the span in the context when the executor is opened, the span of Close is its child.
+	parentSpan opentracing.Span
 }
 
 // NewBaseExecutor creates a new BaseExecutor instance.
@@ -157,7 +161,7 @@ func (e *BaseExecutor) Base() *BaseExecutor {
 // Open initializes children recursively and "childrenResults" according to children's schemas.
 func (e *BaseExecutor) Open(ctx context.Context) error {
 	for _, child := range e.children {
-		err := child.Open(ctx)
+		err := Open(ctx, child)
 		if err != nil {
 			return err
 		}
@@ -169,13 +173,34 @@ func (e *BaseExecutor) Open(ctx context.Context) error {
 func (e *BaseExecutor) Close() error {
 	var firstErr error
 	for _, src := range e.children {
-		if err := src.Close(); err != nil && firstErr == nil {
+		if err := Close(src); err != nil && firstErr == nil {
 			firstErr = err
 		}
 	}
 	return firstErr
 }
 
+// Open opens the executor, the span of it is recorded if the context is traced.
+func Open(ctx context.Context, e Executor) error {
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		e.Base().parentSpan = span
+		span1 := span.Tracer().StartSpan(fmt.Sprintf("%T.Open", e), opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
+	}
+	return e.Open(ctx)
+}
+
+// Close closes the executor, the span of it is recorded if the executor is opened with a traced context.
+func Close(e Executor) error {
+	if span := e.Base().parentSpan; span != nil {
+		e.Base().parentSpan = nil
+		span1 := span.Tracer().StartSpan(fmt.Sprintf("%T.Close", e), opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+	}
+	return e.Close()
+}
+
 // Schema returns the current BaseExecutor's schema. If it is nil, then create and return a new one.
 func (e *BaseExecutor) Schema() *expression.Schema {
 	if e.schema == nil {
@@ -0,0 +1,208 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskingPolicy(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, name varchar(20), phone varchar(20), email varchar(64), birthday date, salary int, " +
+		"name_upper varchar(20) as (upper(name)))")
+	tk.MustExec("insert into t (id, name, phone, email, birthday, salary) values " +
+		"(1, 'alice', '13800001111', 'alice@example.com', '1990-05-17', 1000), " +
+		"(2, 'bob', '12', null, '1985-11-02', 2000)")
+
+	tk.MustExec("create masking policy p_phone on t (phone) as mask_partial(3, 4)")
+	tk.MustExec("create masking policy p_email on t (email) as mask_hash()")
+	tk.MustExec("create masking policy p_birthday on t (birthday) as mask_date('year')")
+	tk.MustExec("create masking policy p_salary on t (salary) as mask_full()")
+	tk.MustExec("create masking policy if not exists p_salary on t (name) as mask_null()")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8267 Masking policy 'p_salary' already exists"))
+	tk.MustGetErrCode("create masking policy p_salary on t (name) as mask_null()", errno.ErrMaskingPolicyExists)
+	tk.MustGetErrCode("create masking policy p on t (phone) as mask_null()", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (salary) as mask_hash()", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (name) as mask_date('day')", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (birthday) as mask_date('hour')", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (name) as mask_partial(1)", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (name) as mask_partial(1, 1, '##')", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (name) as mask_full(1)", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (name) as mask_unknown()", errno.ErrInvalidMaskingPolicy)
+	tk.MustGetErrCode("create masking policy p on t (no_such_col) as mask_null()", errno.ErrBadField)
+	tk.MustQuery("select policy_name, column_name, masking_type, masking_args from information_schema.masking_policies " +
+		"where table_schema = 'test' and table_name = 't' order by policy_name").Check(testkit.Rows(
+		"p_birthday birthday MASK_DATE YEAR",
+		"p_email email MASK_HASH ",
+		"p_phone phone MASK_PARTIAL 3, 4, *",
+		"p_salary salary MASK_FULL ",
+	))
+
+	tk.MustExec("create user u1")
+	tk.MustExec("grant select, insert, update, delete, create on test.* to u1")
+	tk.MustExec("grant file on *.* to u1")
+	tk1 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil, nil))
+	tk1.MustExec("use test")
+
+	// The users without UNMASKED_READ read the masked values.
+	tk1.MustQuery("select id, phone, email = sha2('alice@example.com', 256), birthday, salary from t order by id").Check(testkit.Rows(
+		"1 138****1111 1 1990-01-01 0",
+		"2 ** <nil> 1985-01-01 0",
+	))
+	// The filters, the aggregations and the joins see the masked values.
+	tk1.MustQuery("select id from t where phone = '13800001111'").Check(testkit.Rows())
+	tk1.MustQuery("select id from t where phone = '138****1111'").Check(testkit.Rows("1"))
+	tk1.MustQuery("select sum(salary) from t").Check(testkit.Rows("0"))
+	tk1.MustQuery("select t1.id from t t1 join t t2 on t1.birthday = t2.birthday and t1.id <> t2.id").Check(testkit.Rows())
+	tk1.MustQuery("select id from t where id = 1 for update").Check(testkit.Rows("1"))
+	tk1.MustQuery("select phone from t where id = 1").Check(testkit.Rows("138****1111"))
+	tk1.MustQuery("select phone from t where id in (1, 2) order by id").Check(testkit.Rows("138****1111", "**"))
+	// The rows copied by the queries are masked.
+	tk1.MustExec("create table t2 (phone varchar(20))")
+	tk1.MustExec("insert into t2 select phone from t")
+	tk1.MustQuery("select phone from t2 order by phone").Check(testkit.Rows("**", "138****1111"))
+	outfile := filepath.Join(t.TempDir(), "masked.csv")
+	tk1.MustExec(fmt.Sprintf("select id, phone, salary from t order by id into outfile %q", outfile))
+	content, err := os.ReadFile(outfile)
+	require.NoError(t, err)
+	require.Equal(t, "1\t138****1111\t0\n2\t**\t0\n", string(content))
+	// The prepared statements are masked by the user who executes them.
+	tk1.MustExec("prepare stmt from 'select phone from t where id = ?'")
+	tk1.MustExec("set @id = 1")
+	tk1.MustQuery("execute stmt using @id").Check(testkit.Rows("138****1111"))
+	tk.MustExec("prepare stmt from 'select phone from t where id = ?'")
+	tk.MustExec("set @id = 1")
+	tk.MustQuery("execute stmt using @id").Check(testkit.Rows("13800001111"))
+	// The rows written back by UPDATE and DELETE are not masked, but the filters, the assignments, the joins
+	// and the subqueries of them see the masked values.
+	tk1.MustExec("update t set salary = 1001 where phone = '13800001111'")
+	tk.MustQuery("select phone, salary from t where id = 1").Check(testkit.Rows("13800001111 1000"))
+	tk1.MustExec("update t set salary = 1001 where phone = '138****1111'")
+	tk.MustQuery("select phone, salary from t where id = 1").Check(testkit.Rows("13800001111 1001"))
+	tk1.MustExec("update t set name = concat(phone, 'x') where id = 1")
+	tk.MustQuery("select phone, name, name_upper from t where id = 1").Check(testkit.Rows("13800001111 138****1111x 138****1111X"))
+	tk.MustExec("update t set name = 'alice' where id = 1")
+	tk1.MustExec("create table t3 (id int, phone varchar(20))")
+	tk1.MustExec("insert into t3 values (1, null)")
+	tk1.MustExec("update t3 join t on t3.id = t.id set t3.phone = t.phone")
+	tk1.MustExec("update t3 set phone = (select phone from t where id = 1) where id = 1 and exists (select 1 from t where t.phone = '13800001111')")
+	tk1.MustQuery("select phone from t3").Check(testkit.Rows("138****1111"))
+	tk1.MustExec("update t3 set phone = (select phone from t where id = 1)")
+	tk1.MustQuery("select phone from t3").Check(testkit.Rows("138****1111"))
+	tk1.MustExec("update t3 set phone = null")
+	tk1.MustExec("update t3, t set t3.phone = t.phone where t3.id = t.id")
+	tk1.MustQuery("select phone from t3").Check(testkit.Rows("138****1111"))
+	tk1.MustExec("delete from t3 where phone = '13800001111'")
+	tk1.MustExec("delete t3 from t3 join t on t3.id = t.id where t.phone = '13800001111'")
+	tk1.MustExec("delete from t where phone = '13800001111'")
+	tk1.MustExec("delete from t where id in (select id from t3 where phone = '13800001111')")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("2"))
+	tk1.MustQuery("select count(*) from t3").Check(testkit.Rows("1"))
+	tk1.MustExec("delete t3 from t3 join t on t3.phone = t.phone")
+	tk1.MustQuery("select count(*) from t3").Check(testkit.Rows("0"))
+
+	// The internal SQL executed on behalf of a user, e.g. the timer statements, reads the masked values too.
+	rs, err := tk1.Session().ExecuteInternal(kv.WithInternalSourceType(context.Background(), kv.InternalTimer), "select phone from test.t order by id")
+	require.NoError(t, err)
+	tk1.ResultSetToResult(rs, "internal sql").Check(testkit.Rows("138****1111", "**"))
+
+	// The changes returned by TABLE_CHANGES are masked too.
+	timeSafe := time.Now().Add(-48 * time.Hour).Format("20060102-15:04:05 -0700 MST")
+	tk.MustExec(fmt.Sprintf(`INSERT HIGH_PRIORITY INTO mysql.tidb VALUES ('tikv_gc_safe_point', '%[1]s', '')
+		ON DUPLICATE KEY UPDATE variable_value = '%[1]s'`, timeSafe))
+	startVer, err := store.CurrentVersion(kv.GlobalTxnScope)
+	require.NoError(t, err)
+	tk.MustExec("update t set salary = 1002 where id = 1")
+	endVer, err := store.CurrentVersion(kv.GlobalTxnScope)
+	require.NoError(t, err)
+	query := fmt.Sprintf("select _tidb_op, id, phone, salary from table_changes(t, %d, %d) c", startVer.Ver, endVer.Ver)
+	tk1.MustQuery(query).Check(testkit.Rows("update 1 138****1111 0"))
+	tk1.MustQuery(query + " where phone = '13800001111'").Check(testkit.Rows())
+	tk.MustQuery(query).Check(testkit.Rows("update 1 13800001111 1002"))
+	tk.MustExec("update t set salary = 1001 where id = 1")
+
+	// The values are unmasked with UNMASKED_READ.
+	tk.MustExec("grant UNMASKED_READ on *.* to u1")
+	tk1.MustQuery("select id, phone, email, birthday, salary from t order by id").Check(testkit.Rows(
+		"1 13800001111 alice@example.com 1990-05-17 1001",
+		"2 12 <nil> 1985-11-02 2000",
+	))
+	tk.MustExec("revoke UNMASKED_READ on *.* from u1")
+
+	// The generated columns depending on the masked columns are masked too.
+	tk.MustExec("create masking policy p_name on t (name) as mask_full()")
+	tk1.MustQuery("select name, name_upper from t order by id").Check(testkit.Rows("XXXX <nil>", "XXXX <nil>"))
+
+	// The policy follows the renamed column and is dropped with the column.
+	tk.MustExec("alter table t rename column phone to mobile")
+	tk1.MustQuery("select mobile from t where id = 1").Check(testkit.Rows("138****1111"))
+	tk.MustExec("alter table t modify column mobile varchar(32)")
+	tk1.MustQuery("select mobile from t where id = 1").Check(testkit.Rows("138****1111"))
+	tk.MustExec("alter table t modify column mobile char(32)")
+	tk1.MustQuery("select mobile from t where id = 1").Check(testkit.Rows("138****1111"))
+	tk.MustExec("alter table t drop column salary")
+	tk.MustQuery("select count(*) from information_schema.masking_policies where policy_name = 'p_salary'").Check(testkit.Rows("0"))
+
+	// Only the users with MASKING_POLICY_ADMIN can manage the policies.
+	errMsg := "[planner:1227]Access denied; you need (at least one of) the SUPER or MASKING_POLICY_ADMIN privilege(s) for this operation"
+	tk1.MustGetErrMsg("drop masking policy p_phone on t", errMsg)
+	tk1.MustGetErrMsg("create masking policy p on t (id) as mask_null()", errMsg)
+	tk.MustExec("grant MASKING_POLICY_ADMIN on *.* to u1")
+	tk1.MustExec("drop masking policy p_phone on t")
+	tk1.MustQuery("select mobile from t where id = 1").Check(testkit.Rows("13800001111"))
+	tk1.MustExec("drop masking policy if exists p_phone on t")
+	tk1.MustQuery("show warnings").Check(testkit.Rows("Note 8268 Unknown masking policy 'p_phone'"))
+	tk1.MustGetErrCode("drop masking policy p_phone on t", errno.ErrMaskingPolicyNotExists)
+}
+
+func TestMaskingPolicyOnHandle(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (email varchar(64) primary key clustered, v int) partition by key(email) partitions 2")
+	tk.MustExec("insert into t values ('alice@example.com', 1), ('bob@example.com', 2)")
+	tk.MustExec("create masking policy p on t (email) as mask_partial(1, 12, '-')")
+	tk.MustExec("create user u1")
+	tk.MustExec("grant select, update on test.* to u1")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil, nil))
+	tk1.MustExec("use test")
+	tk1.MustQuery("select * from t order by v").Check(testkit.Rows("a----@example.com 1", "b--@example.com 2"))
+	tk1.MustQuery("select * from t where email = 'alice@example.com'").Check(testkit.Rows())
+	// The point plans of UPDATE and DELETE see the masked values too.
+	tk1.MustExec("update t set v = 10 where email = 'alice@example.com'")
+	require.Equal(t, uint64(0), tk1.Session().AffectedRows())
+	tk1.MustExec("update t set v = 10 where email in ('alice@example.com', 'bob@example.com')")
+	require.Equal(t, uint64(0), tk1.Session().AffectedRows())
+	// The rows are locked by the unmasked handles.
+	tk1.MustExec("begin pessimistic")
+	tk1.MustQuery("select * from t where v = 1 for update").Check(testkit.Rows("a----@example.com 1"))
+	tk1.MustExec("commit")
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// getMaterializedView returns the table storing the materialized view `tn`.
+func getMaterializedView(is infoschema.InfoSchema, tn *ast.TableName) (*model.TableInfo, error) {
+	tbl, err := is.TableByName(tn.Schema, tn.Name)
+	if err != nil {
+		return nil, err
+	}
+	if tbl.Meta().MaterializedView == nil {
+		return nil, dbterror.ErrWrongObject.GenWithStackByArgs(tn.Schema, tn.Name, "MATERIALIZED VIEW")
+	}
+	return tbl.Meta(), nil
+}
+
+// refreshMaterializedView refreshes the materialized view in a new transaction of the system session `se`.
+// A complete refresh recomputes the whole view and an incremental refresh merges the changes of the base
+// table logged since the last refresh. RefreshMaterializedViewDefault refreshes the view incrementally if
+// it's possible. The time of the refresh is recorded in mysql.tidb_materialized_view_refresh in the same
+// transaction, it's used to schedule the next refresh and to check the staleness of the view. The views reading
+// the tables with masking policies or row access policies can't be refreshed, see CheckMaterializedViewPolicies.
+func refreshMaterializedView(ctx context.Context, se sessionctx.Context, is infoschema.InfoSchema, schema model.CIStr,
+	tblInfo *model.TableInfo, tp ast.RefreshMaterializedViewType) error {
+	mvInfo := tblInfo.MaterializedView
+	inc := mvInfo.Incremental
+	if tp == ast.RefreshMaterializedViewIncremental && inc == nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("incremental refresh on materialized view " + tblInfo.Name.O)
+	}
+	// The policies may be created on the base tables after the view.
+	query, err := parser.New().ParseOneStmt(mvInfo.SelectStmt, tblInfo.Charset, tblInfo.Collate)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = core.CheckMaterializedViewPolicies(is, query); err != nil {
+		return err
+	}
+
+	var logSchema, logTable model.CIStr
+	if inc != nil {
+		logTbl, ok := is.TableByID(inc.LogTableID)
+		if !ok {
+			return infoschema.ErrTableNotExists.GenWithStackByArgs(schema.O, fmt.Sprintf("%s%d", model.MaterializedViewLogTablePrefix, tblInfo.ID))
+		}
+		baseSchema, ok := is.SchemaByID(inc.BaseSchemaID)
+		if !ok {
+			return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(inc.BaseSchemaID)
+		}
+		logSchema, logTable = baseSchema.Name, logTbl.Meta().Name
+	}
+
+	sqls := make([]string, 0, 5)
+	sql := new(strings.Builder)
+	// The refresh is recorded first, so the time is close to the snapshot read by the refresh.
+	complete := tp == ast.RefreshMaterializedViewComplete || inc == nil
+	refreshType := "INCREMENTAL"
+	if complete {
+		refreshType = "COMPLETE"
+	}
+	sqlexec.MustFormatSQL(sql, "REPLACE INTO mysql.tidb_materialized_view_refresh VALUES (%?, %?, NOW(6))", tblInfo.ID, refreshType)
+	sqls = append(sqls, sql.String())
+	sql.Reset()
+	if complete {
+		sqlexec.MustFormatSQL(sql, "DELETE FROM %n.%n", schema.O, tblInfo.Name.O)
+		sqls = append(sqls, sql.String())
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, "INSERT INTO %n.%n ", schema.O, tblInfo.Name.O)
+		sql.WriteString(mvInfo.SelectStmt)
+		sqls = append(sqls, sql.String())
+	} else {
+		sqls = append(sqls, buildIncrementalRefreshSQL(schema, tblInfo.Name, inc, logSchema, logTable))
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, "DELETE FROM %n.%n WHERE %n = 0", schema.O, tblInfo.Name.O, inc.CountCol.O)
+		sqls = append(sqls, sql.String())
+	}
+	if inc != nil {
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, "DELETE FROM %n.%n", logSchema.O, logTable.O)
+		sqls = append(sqls, sql.String())
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	exec := se.(sqlexec.SQLExecutor)
+	// The log rows read by the refresh are deleted in the same transaction, the optimistic transaction
+	// makes sure the rows logged by the concurrent transactions are not deleted.
+	if _, err := exec.ExecuteInternal(ctx, "BEGIN OPTIMISTIC"); err != nil {
+		return err
+	}
+	for _, sql := range sqls {
+		if _, err := exec.ExecuteInternal(ctx, sql); err != nil {
+			logutil.Logger(ctx).Warn("refresh materialized view failed", zap.String("view", tblInfo.Name.O), zap.Error(err))
+			if _, rollbackErr := exec.ExecuteInternal(ctx, "ROLLBACK"); rollbackErr != nil {
+				return rollbackErr
+			}
+			return errors.Trace(err)
+		}
+	}
+	_, err = exec.ExecuteInternal(ctx, "COMMIT")
+	return err
+}
+
+// buildIncrementalRefreshSQL builds the statement merging the logged changes into the materialized view, e.g.
+//
+//	INSERT INTO mv (a, s, c, cnt) SELECT a, SUM(b * sign), SUM(IF(c IS NULL, 0, sign)), SUM(sign) FROM log
+//	GROUP BY a ON DUPLICATE KEY UPDATE s = s + VALUES(s), c = c + VALUES(c), cnt = cnt + VALUES(cnt)
+func buildIncrementalRefreshSQL(schema, name model.CIStr, inc *model.MaterializedViewIncrementalInfo, logSchema, logTable model.CIStr) string {
+	sql := new(strings.Builder)
+	sqlexec.MustFormatSQL(sql, "INSERT INTO %n.%n (", schema.O, name.O)
+	for i, groupBy := range inc.GroupByCols {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(sql, "%n", groupBy.Col.O)
+	}
+	for _, agg := range inc.Aggs {
+		sqlexec.MustFormatSQL(sql, ", %n", agg.Col.O)
+	}
+	sql.WriteString(") SELECT ")
+	for i, groupBy := range inc.GroupByCols {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(sql, "%n", groupBy.Arg.O)
+	}
+	for _, agg := range inc.Aggs {
+		switch {
+		case agg.Name == ast.AggFuncSum:
+			sqlexec.MustFormatSQL(sql, ", SUM(%n * %n)", agg.Arg.O, model.MaterializedViewLogSignCol)
+		case agg.Arg.L != "":
+			sqlexec.MustFormatSQL(sql, ", SUM(IF(%n IS NULL, 0, %n))", agg.Arg.O, model.MaterializedViewLogSignCol)
+		default:
+			sqlexec.MustFormatSQL(sql, ", SUM(%n)", model.MaterializedViewLogSignCol)
+		}
+	}
+	sqlexec.MustFormatSQL(sql, " FROM %n.%n", logSchema.O, logTable.O)
+	if inc.Where != "" {
+		sql.WriteString(" WHERE ")
+		sql.WriteString(inc.Where)
+	}
+	sql.WriteString(" GROUP BY ")
+	for i, groupBy := range inc.GroupByCols {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(sql, "%n", groupBy.Arg.O)
+	}
+	sql.WriteString(" ON DUPLICATE KEY UPDATE ")
+	for i, agg := range inc.Aggs {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(sql, "%n = %n + VALUES(%n)", agg.Col.O, agg.Col.O, agg.Col.O)
+	}
+	return sql.String()
+}
+
+func (e *DDLExec) executeCreateMaterializedView(ctx context.Context, s *ast.CreateMaterializedViewStmt) error {
+	ret := &core.PreprocessorReturn{}
+	err := core.Preprocess(ctx, e.Ctx(), s.Select, core.InCreateMaterializedView, core.WithPreprocessorReturn(ret))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ret.IsStaleness {
+		return exeerrors.ErrViewInvalid.GenWithStackByArgs(s.ViewName.Schema.L, s.ViewName.Name.L)
+	}
+	if err = core.CheckMaterializedViewPolicies(e.is, s.Select); err != nil {
+		return err
+	}
+
+	dom := domain.GetDomain(e.Ctx())
+	if s.IfNotExists && e.is.TableExists(s.ViewName.Schema, s.ViewName.Name) {
+		return dom.DDL().CreateMaterializedView(e.Ctx(), s)
+	}
+	if err = dom.DDL().CreateMaterializedView(e.Ctx(), s); err != nil {
+		return err
+	}
+	is := dom.InfoSchema()
+	tblInfo, err := getMaterializedView(is, s.ViewName)
+	if err != nil {
+		return err
+	}
+	// Fill the materialized view with the data at the time it's created.
+	se, err := e.GetSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.ReleaseSysSession(ctx, se)
+	return refreshMaterializedView(ctx, se, is, s.ViewName.Schema, tblInfo, ast.RefreshMaterializedViewComplete)
+}
+
+func (e *DDLExec) executeDropMaterializedView(s *ast.DropMaterializedViewStmt) error {
+	return domain.GetDomain(e.Ctx()).DDL().DropMaterializedView(e.Ctx(), s)
+}
+
+func (e *SimpleExec) executeRefreshMaterializedView(ctx context.Context, s *ast.RefreshMaterializedViewStmt) error {
+	is := domain.GetDomain(e.Ctx()).InfoSchema()
+	tblInfo, err := getMaterializedView(is, s.ViewName)
+	if err != nil {
+		return err
+	}
+	se, err := e.GetSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.ReleaseSysSession(ctx, se)
+	return refreshMaterializedView(ctx, se, is, s.ViewName.Schema, tblInfo, s.Tp)
+}
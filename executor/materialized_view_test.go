@@ -0,0 +1,183 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaterializedView(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int not null, b int not null, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, null), (2, 3, 3)")
+
+	tk.MustExec("create materialized view mv (a, s, c, cnt) as select a, sum(b), count(c), count(*) from t where b > 0 group by a")
+	tk.MustExec("create materialized view if not exists mv as select a from t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1050 Table 'test.mv' already exists"))
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 3 1 2", "2 3 1 1"))
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'test' and table_name like '\\_tidb\\_mvlog\\_%'").Check(testkit.Rows("1"))
+
+	// the changes of the base table are merged by the incremental refresh
+	tk.MustExec("insert into t values (1, 4, 4), (3, 5, null)")
+	tk.MustExec("delete from t where a = 2")
+	tk.MustExec("update t set b = 10 where b = 1")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 3 1 2", "2 3 1 1"))
+	tk.MustExec("refresh materialized view mv incremental")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 16 2 3", "3 5 0 1"))
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 16 2 3", "3 5 0 1"))
+	tk.MustExec("insert into t values (3, 1, 1)")
+	tk.MustExec("refresh materialized view mv complete")
+	tk.MustQuery("select * from mv order by a").Check(testkit.Rows("1 16 2 3", "3 6 1 2"))
+
+	// the materialized view which can't be refreshed incrementally
+	tk.MustExec("create materialized view mv2 as select a, max(b) from t group by a")
+	tk.MustQuery("select * from mv2 order by a").Check(testkit.Rows("1 10", "3 5"))
+	tk.MustExec("insert into t values (4, 4, 4)")
+	tk.MustContainErrMsg("refresh materialized view mv2 incremental", "incremental refresh on materialized view mv2")
+	tk.MustExec("refresh materialized view mv2")
+	tk.MustQuery("select * from mv2 order by a").Check(testkit.Rows("1 10", "3 5", "4 4"))
+
+	// the query is answered by the materialized view
+	query := "select a, sum(b), count(c), count(*) from t where b > 0 group by a"
+	tk.MustExec("refresh materialized view mv")
+	tk.MustQuery(query).Sort().Check(testkit.Rows("1 16 2 3", "3 6 1 2", "4 4 1 1"))
+	require.True(t, tk.HasPlan(query, "Agg"))
+	tk.MustExec("set @@tidb_opt_enable_materialized_view_rewrite = on")
+	require.False(t, tk.HasPlan(query, "Agg"))
+	rows := tk.MustQuery("explain format = 'brief' " + query).Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "table:mv")
+	tk.MustQuery(query).Sort().Check(testkit.Rows("1 16 2 3", "3 6 1 2", "4 4 1 1"))
+
+	// the view which is not refreshed within the max staleness is not used if the base table has been changed
+	tk.MustExec("set @@tidb_opt_materialized_view_max_staleness = '0s'")
+	require.False(t, tk.HasPlan(query, "Agg"))
+	tk.MustExec("insert into t values (4, 1, 1)")
+	require.True(t, tk.HasPlan(query, "Agg"))
+	tk.MustQuery(query).Sort().Check(testkit.Rows("1 16 2 3", "3 6 1 2", "4 5 2 2"))
+	tk.MustExec("refresh materialized view mv")
+	require.False(t, tk.HasPlan(query, "Agg"))
+	tk.MustExec("update mysql.tidb_materialized_view_refresh set last_refresh_time = now() - interval 1 hour")
+	require.False(t, tk.HasPlan(query, "Agg"))
+	tk.MustExec("insert into t values (4, 1, 1)")
+	require.True(t, tk.HasPlan(query, "Agg"))
+	tk.MustExec("set @@tidb_opt_materialized_view_max_staleness = '2h'")
+	require.False(t, tk.HasPlan(query, "Agg"))
+	tk.MustQuery("select @@tidb_opt_materialized_view_max_staleness").Check(testkit.Rows("2h0m0s"))
+	tk.MustExec("set @@tidb_opt_materialized_view_max_staleness = default")
+	tk.MustQuery("select @@tidb_opt_materialized_view_max_staleness").Check(testkit.Rows("5m0s"))
+
+	// the view which can't be refreshed incrementally is only used within the max staleness
+	query = "select a, max(b) from t group by a"
+	tk.MustExec("refresh materialized view mv2")
+	require.False(t, tk.HasPlan(query, "Agg"))
+	tk.MustExec("set @@tidb_opt_materialized_view_max_staleness = '0s'")
+	require.True(t, tk.HasPlan(query, "Agg"))
+	tk.MustExec("set @@tidb_opt_materialized_view_max_staleness = default")
+
+	// the non-deterministic queries are not rewritten
+	tk.MustExec("create materialized view mv3 as select a, count(*) from t where b < unix_timestamp() group by a")
+	require.True(t, tk.HasPlan("select a, count(*) from t where b < unix_timestamp() group by a", "Agg"))
+	tk.MustExec("create materialized view mv4 as select a, connection_id() from t group by a")
+	require.True(t, tk.HasPlan("select a, connection_id() from t group by a", "HashAgg"))
+	tk.MustExec("set @v = 1")
+	tk.MustExec("create materialized view mv5 as select a, count(*) from t where b > @v group by a")
+	require.True(t, tk.HasPlan("select a, count(*) from t where b > @v group by a", "Agg"))
+
+	// the order of the rows is kept by the ORDER BY clause, and the rows limited by the LIMIT clause are stored
+	tk.MustExec("create materialized view mv6 (x, y) as select a, sum(b) as s from t group by a order by s desc, 1 limit 1, 2")
+	query = "select a, sum(b) as s from t group by a order by s desc, 1 limit 1, 2"
+	tk.MustQuery(query).Check(testkit.Rows("3 6", "4 6"))
+	rows = tk.MustQuery("explain format = 'brief' " + query).Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "table:mv6")
+	require.Contains(t, fmt.Sprintf("%v", rows), "test.mv6.y:desc, test.mv6.x")
+	tk.MustQuery(query).Check(testkit.Rows("3 6", "4 6"))
+	// the ORDER BY items which are not in the select fields can't be added back
+	tk.MustExec("create materialized view mv7 as select a from t group by a order by sum(b)")
+	require.True(t, tk.HasPlan("select a from t group by a order by sum(b)", "Agg"))
+	tk.MustExec("set @@tidb_opt_enable_materialized_view_rewrite = default")
+
+	// the tables of the materialized views can't be dropped directly
+	tk.MustGetErrCode("drop table mv", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("truncate table t", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("drop materialized view t", errno.ErrWrongObject)
+	tk.MustGetErrCode("refresh materialized view t", errno.ErrWrongObject)
+
+	tk.MustExec("drop materialized view mv")
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'test' and table_name like '\\_tidb\\_mvlog\\_%'").Check(testkit.Rows("0"))
+	tk.MustExec("drop materialized view test.mv2")
+	tk.MustExec("drop materialized view if exists mv2")
+	tk.MustGetErrCode("drop materialized view mv2", errno.ErrBadTable)
+	tk.MustExec("truncate table t")
+	tk.MustExec("drop table t")
+}
+
+func TestMaterializedViewPolicies(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, tenant_id varchar(16), phone varchar(20))")
+	tk.MustExec("insert into t values (1, 'a', '13800001111'), (2, 'b', '13900002222'), (3, 'a', '13700003333')")
+	tk.MustExec("create materialized view mv_phone as select id, phone from t")
+	tk.MustExec("create materialized view mv_tenant as select tenant_id, count(*) from t group by tenant_id")
+	tk.MustExec(`create user ua attribute '{"tenant": "a"}'`)
+	tk.MustExec("grant select on test.* to ua")
+	tka := testkit.NewTestKit(t, store)
+	require.NoError(t, tka.Session().Auth(&auth.UserIdentity{Username: "ua", Hostname: "localhost"}, nil, nil, nil))
+	tka.MustExec("use test")
+	tka.MustExec("set @@tidb_opt_enable_materialized_view_rewrite = on")
+	// The definer of the view is checked when it's read by the materialized view.
+	tkRoot := testkit.NewTestKit(t, store)
+	require.NoError(t, tkRoot.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tkRoot.MustExec("use test")
+	tkRoot.MustExec("create view v as select id, phone from t")
+
+	// The rows of the views are neither masked nor filtered, so the queries of the users who are not
+	// exempted from the policies are not rewritten.
+	tk.MustExec("create masking policy p_phone on t (phone) as mask_partial(3, 4)")
+	tk.MustExec("create row access policy p_tenant on t using (tenant_id = current_tenant())")
+	tka.MustQuery("select id, phone from t").Sort().Check(testkit.Rows("1 138****1111", "3 137****3333"))
+	require.True(t, tka.HasPlan("select id, phone from t", "Projection"))
+	tka.MustQuery("select tenant_id, count(*) from t group by tenant_id").Check(testkit.Rows("a 2"))
+	require.True(t, tka.HasPlan("select tenant_id, count(*) from t group by tenant_id", "Agg"))
+	tk.MustExec("grant UNMASKED_READ on *.* to ua")
+	tka.MustQuery("select id, phone from t").Sort().Check(testkit.Rows("1 13800001111", "3 13700003333"))
+	tk.MustExec("grant ROW_ACCESS_POLICY_EXEMPT on *.* to ua")
+	tka.MustQuery("select tenant_id, count(*) from t group by tenant_id").Sort().Check(testkit.Rows("a 2", "b 1"))
+	rows := tka.MustQuery("explain format = 'brief' select tenant_id, count(*) from t group by tenant_id").Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "table:mv_tenant")
+	rows = tka.MustQuery("explain format = 'brief' select id, phone from t").Rows()
+	require.Contains(t, fmt.Sprintf("%v", rows), "table:mv_phone")
+
+	// The views reading the tables with policies, directly or through the views, can't be created or refreshed.
+	tk.MustGetErrMsg("create materialized view mv2 as select id, phone from t",
+		"[ddl:8200]Unsupported materialized view on table t with masking policies")
+	tkRoot.MustGetErrCode("create materialized view mv2 as select id, phone from v", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("refresh materialized view mv_phone", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("drop masking policy p_phone on t")
+	tk.MustGetErrMsg("refresh materialized view mv_phone",
+		"[ddl:8200]Unsupported materialized view on table t with row access policies")
+	tk.MustExec("drop row access policy p_tenant on t")
+	tk.MustExec("refresh materialized view mv_phone")
+	tkRoot.MustExec("create materialized view mv2 as select id, phone from v")
+}
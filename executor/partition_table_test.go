@@ -3390,6 +3390,54 @@ partition p2 values less than (10))`)
 	failpoint.Disable("github.com/pingcap/tidb/ddl/checkDropGlobalIndex")
 }
 
+func TestGlobalIndexPointGet(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec(`create table p (id int, c int, unique key idx(id) global) partition by range (c) (
+partition p0 values less than (4),
+partition p1 values less than (7),
+partition p2 values less than (10))`)
+	tk.MustExec("insert into p values (1,3), (3,4), (5,6), (7,9)")
+	tk.MustExec("analyze table p")
+
+	// The partition of the row is found by the global index.
+	require.True(t, tk.HasPlan("select * from p where id = 3", "Point_Get"))
+	tk.MustQuery("select * from p where id = 3").Check(testkit.Rows("3 4"))
+	tk.MustQuery("select * from p where id = 4").Check(testkit.Rows())
+	tk.MustQuery("select * from p where id = 3 and c = 4").Check(testkit.Rows("3 4"))
+	tk.MustQuery("select * from p where id = 3 and c = 1").Check(testkit.Rows())
+	require.True(t, tk.HasPlan("select * from p where id = 7 and c > 0", "Point_Get"))
+	tk.MustQuery("select * from p where id = 7 and c > 0").Check(testkit.Rows("7 9"))
+	tk.MustQuery("select * from p where id = 7 and c > 9").Check(testkit.Rows())
+	tk.MustQuery("select * from p partition(p1) where id = 3").Check(testkit.Rows("3 4"))
+	tk.MustQuery("select * from p partition(p0) where id = 3").Check(testkit.Rows())
+	require.True(t, tk.HasPlan("select * from p where id in (1, 3, 4, 7)", "Batch_Point_Get"))
+	tk.MustQuery("select * from p where id in (1, 3, 4, 7)").Sort().Check(testkit.Rows("1 3", "3 4", "7 9"))
+	tk.MustQuery("select * from p partition(p1, p2) where id in (1, 3, 7)").Sort().Check(testkit.Rows("3 4", "7 9"))
+
+	// Update and delete by the global index.
+	tk.MustExec("update p set c = 8 where id = 3")
+	tk.MustQuery("select * from p where id = 3").Check(testkit.Rows("3 8"))
+	tk.MustQuery("select * from p partition(p2) order by id").Check(testkit.Rows("3 8", "7 9"))
+	tk.MustExec("delete from p where id = 5")
+	tk.MustQuery("select * from p where id = 5").Check(testkit.Rows())
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from p where id = 7 for update").Check(testkit.Rows("7 9"))
+	tk.MustExec("commit")
+
+	// The rows of the truncated partitions are not found.
+	tk.MustExec("alter table p truncate partition p2")
+	tk.MustQuery("select * from p where id = 7").Check(testkit.Rows())
+	tk.MustQuery("select * from p where id = 1").Check(testkit.Rows("1 3"))
+	tk.MustQuery("select * from p where id in (1, 3, 7)").Check(testkit.Rows("1 3"))
+	tk.MustExec("insert into p values (7, 1)")
+	tk.MustQuery("select * from p where id = 7").Check(testkit.Rows("7 1"))
+	tk.MustExec("admin check table p")
+}
+
 func TestIssue20028(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 
@@ -227,7 +227,9 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 	} else {
 		tblID = e.tblInfo.ID
 	}
-	if e.lock {
+	// The partition of the row is decided by the value of the global index.
+	pidInIndex := e.partInfo == nil && e.idxInfo != nil && e.idxInfo.Global
+	if e.lock && !pidInIndex {
 		e.UpdateDeltaForTableID(tblID)
 	}
 	if e.idxInfo != nil {
@@ -244,7 +246,11 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 				return err
 			}
 		} else {
-			e.idxKey, err = EncodeUniqueIndexKey(e.Ctx(), e.tblInfo, e.idxInfo, e.idxVals, tblID)
+			idxTblID := tblID
+			if e.idxInfo.Global {
+				idxTblID = e.tblInfo.ID
+			}
+			e.idxKey, err = EncodeUniqueIndexKey(e.Ctx(), e.tblInfo, e.idxInfo, e.idxVals, idxTblID)
 			if err != nil && !kv.ErrNotExist.Equal(err) {
 				return err
 			}
@@ -291,6 +297,23 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 			}
 			e.handle = iv
 
+			if e.idxInfo.Global {
+				pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(e.handleVal)
+				if err != nil {
+					return err
+				}
+				// The row is not in the partition to read, or the entry belongs to a dropped partition.
+				if !ok || (e.partInfo != nil && pid != e.partInfo.ID) || !isPartitionOfTable(e.tblInfo, pid) {
+					return nil
+				}
+				if pidInIndex {
+					tblID = pid
+					if e.lock {
+						e.UpdateDeltaForTableID(tblID)
+					}
+				}
+			}
+
 			// The injection is used to simulate following scenario:
 			// 1. Session A create a point get query but pause before second time `GET` kv from backend
 			// 2. Session B create an UPDATE query to update the record that will be obtained in step 1
@@ -525,6 +548,20 @@ func (e *PointGetExecutor) verifyTxnScope() error {
 		fmt.Sprintf("table %v can not be read by %v txn_scope", tblName, e.txnScope))
 }
 
+// isPartitionOfTable checks whether pid is the ID of a partition of the table.
+func isPartitionOfTable(tblInfo *model.TableInfo, pid int64) bool {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return false
+	}
+	for _, def := range pi.Definitions {
+		if def.ID == pid {
+			return true
+		}
+	}
+	return false
+}
+
 // EncodeUniqueIndexKey encodes a unique index key.
 func EncodeUniqueIndexKey(ctx sessionctx.Context, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, idxVals []types.Datum, tID int64) (_ []byte, err error) {
 	encodedIdxVals, err := EncodeUniqueIndexValuesForKey(ctx, tblInfo, idxInfo, idxVals)
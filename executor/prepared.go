@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/executor/internal/exec"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/extension"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
@@ -111,6 +112,11 @@ func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		return exeerrors.ErrPrepareMulti
 	}
 	stmt0 := stmts[0]
+	if extensions := e.Ctx().GetExtensions(); extensions.HasStmtRewriters() && !vars.InRestrictedSQL {
+		if stmt0, err = extensions.RewriteStmt(extension.NewSessionInfo(vars), stmt0); err != nil {
+			return err
+		}
+	}
 	if e.needReset {
 		err = ResetContextOfStmt(e.Ctx(), stmt0)
 		if err != nil {
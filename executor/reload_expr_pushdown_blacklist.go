@@ -278,6 +278,7 @@ var funcName2Alias = map[string]string{
 	"connection_id":              ast.ConnectionID,
 	"current_user":               ast.CurrentUser,
 	"current_resource_group":     ast.CurrentResourceGroup,
+	"current_tenant":             ast.CurrentTenant,
 	"current_role":               ast.CurrentRole,
 	"database":                   ast.Database,
 	"found_rows":                 ast.FoundRows,
@@ -123,7 +123,7 @@ func (e *ReplaceExec) removeIndexRow(ctx context.Context, txn kv.Transaction, r
 		if err != nil {
 			return false, false, err
 		}
-		if handle == nil {
+		if handle == nil || isDroppedPartitionHandle(r.t, handle) {
 			continue
 		}
 		rowUnchanged, err := e.removeRow(ctx, txn, handle, r, true)
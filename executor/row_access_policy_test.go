@@ -0,0 +1,188 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowAccessPolicy(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, tenant_id varchar(16), v int, unique key (v))")
+	tk.MustExec("insert into t values (1, 'a', 1), (2, 'b', 2), (3, 'a', 3), (4, null, 4)")
+
+	tk.MustExec("create row access policy p_tenant on t using (tenant_id = current_tenant())")
+	tk.MustExec("create row access policy if not exists p_tenant on t using (id > 0)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8270 Row access policy 'p_tenant' already exists"))
+	tk.MustGetErrCode("create row access policy p_tenant on t using (id > 0)", errno.ErrRowAccessPolicyExists)
+	tk.MustGetErrCode("create row access policy p on t using (no_such_col > 0)", errno.ErrBadField)
+	tk.MustGetErrCode("create row access policy p on t using (test.t2.id > 0)", errno.ErrBadField)
+	tk.MustGetErrCode("create row access policy p on t using (id in (select 1))", errno.ErrInvalidRowAccessPolicy)
+	tk.MustGetErrCode("create row access policy p on t using (tenant_id = @tenant)", errno.ErrInvalidRowAccessPolicy)
+	tk.MustGetErrCode("create row access policy p on t using (count(id) > 0)", errno.ErrInvalidRowAccessPolicy)
+	tk.MustGetErrCode("create row access policy p on t using (no_such_func(id))", errno.ErrSpDoesNotExist)
+	tk.MustQuery("select policy_name, policy_expression from information_schema.row_access_policies " +
+		"where table_schema = 'test' and table_name = 't'").Check(testkit.Rows("p_tenant `tenant_id`=CURRENT_TENANT()"))
+	// The users without a tenant can't access any rows.
+	tk.MustExec("create user u0")
+	tk.MustExec(`create user ua attribute '{"tenant": "a"}'`)
+	tk.MustExec(`create user ub attribute '{"tenant": "b"}'`)
+	tk.MustExec("grant select, insert, update, delete on test.* to u0, ua, ub")
+	newTestKit := func(user string) *testkit.TestKit {
+		tk := testkit.NewTestKit(t, store)
+		require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: user, Hostname: "localhost"}, nil, nil, nil))
+		tk.MustExec("use test")
+		return tk
+	}
+	tk0, tka, tkb := newTestKit("u0"), newTestKit("ua"), newTestKit("ub")
+	tk0.MustQuery("select current_tenant(), count(*) from t").Check(testkit.Rows("<nil> 0"))
+	tka.MustQuery("select current_tenant()").Check(testkit.Rows("a"))
+	// The users can't change their own tenants.
+	errMsg := "[planner:1227]Access denied; you need (at least one of) the CREATE USER privilege(s) for this operation"
+	tka.MustGetErrMsg(`alter user current_user() attribute '{"tenant": "b"}'`, errMsg)
+	tka.MustGetErrMsg(`alter user ua attribute '{"tenant": null}'`, errMsg)
+	tka.MustGetErrMsg(`alter user ua attribute '"b"'`, errMsg)
+	tk0.MustGetErrMsg(`alter user current_user() attribute '{"tenant": "a"}'`, errMsg)
+	tka.MustExec(`alter user current_user() attribute '{"name": "alice"}'`)
+	tka.MustExec(`alter user current_user() comment 'tenant a'`)
+	tka.MustQuery("select current_tenant()").Check(testkit.Rows("a"))
+	tk.MustExec(`alter user u0 attribute '{"tenant": "c"}'`)
+	tk.MustExec(`alter user u0 attribute '{"tenant": null}'`)
+
+	// The rows of the other tenants are filtered out, including the point gets.
+	tka.MustQuery("select id from t order by id").Check(testkit.Rows("1", "3"))
+	tkb.MustQuery("select id from t order by id").Check(testkit.Rows("2"))
+	tka.MustQuery("select id from t where id = 2").Check(testkit.Rows())
+	tka.MustQuery("select id from t where id in (1, 2) order by id").Check(testkit.Rows("1"))
+	tka.MustQuery("select id from t where v = 2").Check(testkit.Rows())
+	tka.MustQuery("select count(*) from t t1 join t t2 on t1.id <> t2.id").Check(testkit.Rows("2"))
+	// The policies are evaluated when the plan is built, and pushed down with the other filters.
+	tka.MustQuery("explain format = 'brief' select id from t").Check(testkit.Rows(
+		"Projection 10.00 root  test.t.id",
+		"└─TableReader 10.00 root  data:Selection",
+		"  └─Selection 10.00 cop[tikv]  eq(test.t.tenant_id, \"a\")",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+	// The predicates which may leak the values of the filtered rows by their warnings are evaluated above the policies,
+	// the leakproof ones are pushed down with them.
+	tka.MustQuery("select id from t where cast(tenant_id as signed) = 0 and id < 4 order by id").Check(testkit.Rows("1", "3"))
+	tka.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1292 Truncated incorrect INTEGER value: 'a'", "Warning 1292 Truncated incorrect INTEGER value: 'a'"))
+	tka.MustQuery("explain format = 'brief' select id from t where cast(tenant_id as signed) = 0 and id < 4").Check(testkit.Rows(
+		"Projection 0.00 root  test.t.id",
+		"└─Selection 0.00 root  eq(cast(test.t.tenant_id, bigint(22) BINARY), 0)",
+		"  └─TableReader 0.00 root  data:Selection",
+		"    └─Selection 0.00 cop[tikv]  eq(test.t.tenant_id, \"a\")",
+		"      └─TableRangeScan 3333.33 cop[tikv] table:t range:[-inf,4), keep order:false, stats:pseudo",
+	))
+	tka.MustQuery("select id from t where id in (select id from t t2 where t2.id = t.id and cast(t2.tenant_id as signed) = 0) order by id").Check(testkit.Rows("1", "3"))
+	tka.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1292 Truncated incorrect INTEGER value: 'a'", "Warning 1292 Truncated incorrect INTEGER value: 'a'"))
+	// The policies which can't be pushed down to the storage are evaluated before the other predicates too.
+	tk.MustExec("create table t2 (id int primary key, tenant_id varchar(16))")
+	tk.MustExec("insert into t2 values (1, 'a'), (2, 'b')")
+	tk.MustExec("create row access policy p_tenant on t2 using (sha2(tenant_id, 256) = sha2(current_tenant(), 256))")
+	tka.MustQuery("select id from t2 where cast(tenant_id as signed) = 0").Check(testkit.Rows("1"))
+	tka.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect INTEGER value: 'a'"))
+	tka.MustExec("prepare stmt from 'select id from t where id = ?'")
+	tka.MustExec("set @id = 2")
+	tka.MustQuery("execute stmt using @id").Check(testkit.Rows())
+	tk.MustExec("prepare stmt from 'select id from t where id = ?'")
+	tk.MustExec("set @id = 2")
+	tk.MustQuery("execute stmt using @id").Check(testkit.Rows("2"))
+
+	// The rows of the other tenants can't be updated or deleted.
+	tka.MustExec("update t set v = v + 10 where id in (1, 2)")
+	tka.MustExec("update t set v = v + 100 where id = 2")
+	tka.MustExec("delete from t where id = 2")
+	tka.MustExec("delete from t where tenant_id = 'b'")
+	tk.MustQuery("select id, v from t order by id").Check(testkit.Rows("1 11", "2 2", "3 3", "4 4"))
+	tka.MustGetErrCode("update t set tenant_id = 'b' where id = 1", errno.ErrRowAccessPolicyViolation)
+
+	// The rows written by the tenants must belong to them.
+	tka.MustExec("insert into t values (5, 'a', 5)")
+	tka.MustGetErrMsg("insert into t values (6, 'b', 6)",
+		"[executor:8274]The row violates the row access policy 'p_tenant' of table 't'")
+	tka.MustGetErrCode("insert into t values (7, null, 7)", errno.ErrRowAccessPolicyViolation)
+	tka.MustGetErrCode("insert into t select 8, 'b', 8", errno.ErrRowAccessPolicyViolation)
+	tka.MustGetErrCode("replace into t values (2, 'a', 20)", errno.ErrRowAccessPolicyViolation)
+	tka.MustGetErrCode("insert into t values (9, 'a', 2) on duplicate key update v = 20", errno.ErrRowAccessPolicyViolation)
+	tka.MustGetErrCode("insert into t values (1, 'a', 1) on duplicate key update tenant_id = 'b'", errno.ErrRowAccessPolicyViolation)
+	tka.MustExec("insert into t values (1, 'a', 1) on duplicate key update v = 12")
+	tka.MustExec("replace into t values (3, 'a', 13)")
+	tk.MustQuery("select id, tenant_id, v from t order by id").Check(testkit.Rows(
+		"1 a 12", "2 b 2", "3 a 13", "4 <nil> 4", "5 a 5"))
+	tka.MustGetErrCode("load data local infile '/tmp/nonexistence.csv' into table t", errno.ErrNotSupportedYet)
+
+	// The internal SQL executed on behalf of a user, e.g. the timer statements, is filtered too.
+	rs, err := tka.Session().ExecuteInternal(kv.WithInternalSourceType(context.Background(), kv.InternalTimer), "select id from test.t order by id")
+	require.NoError(t, err)
+	tka.ResultSetToResult(rs, "internal sql").Check(testkit.Rows("1", "3", "5"))
+
+	// The changes of the rows of the other tenants aren't returned by TABLE_CHANGES.
+	timeSafe := time.Now().Add(-48 * time.Hour).Format("20060102-15:04:05 -0700 MST")
+	tk.MustExec(fmt.Sprintf(`INSERT HIGH_PRIORITY INTO mysql.tidb VALUES ('tikv_gc_safe_point', '%[1]s', '')
+		ON DUPLICATE KEY UPDATE variable_value = '%[1]s'`, timeSafe))
+	startVer, err := store.CurrentVersion(kv.GlobalTxnScope)
+	require.NoError(t, err)
+	tk.MustExec("insert into t values (10, 'a', 10), (11, 'b', 11)")
+	endVer, err := store.CurrentVersion(kv.GlobalTxnScope)
+	require.NoError(t, err)
+	query := fmt.Sprintf("select _tidb_op, id from table_changes(t, %d, %d) c", startVer.Ver, endVer.Ver)
+	tka.MustQuery(query).Check(testkit.Rows("insert 10"))
+	tkb.MustQuery(query).Check(testkit.Rows("insert 11"))
+	tk.MustQuery(query).Check(testkit.Rows("insert 10", "insert 11"))
+	tka.MustQuery(query + " where cast(tenant_id as signed) = 0").Check(testkit.Rows("insert 10"))
+	tka.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect INTEGER value: 'a'"))
+	tk.MustExec("delete from t where id in (10, 11)")
+
+	// The users with ROW_ACCESS_POLICY_EXEMPT access all the rows.
+	tk.MustExec("grant ROW_ACCESS_POLICY_EXEMPT on *.* to ua")
+	tka.MustQuery("select id from t order by id").Check(testkit.Rows("1", "2", "3", "4", "5"))
+	tka.MustExec("insert into t values (6, 'b', 6)")
+	tk.MustExec("revoke ROW_ACCESS_POLICY_EXEMPT on *.* from ua")
+	tka.MustQuery("select id from t order by id").Check(testkit.Rows("1", "3", "5"))
+
+	// The columns referred by the policies can't be dropped or renamed.
+	tk.MustGetErrCode("alter table t drop column tenant_id", errno.ErrDependentByRowAccessPolicy)
+	tk.MustGetErrCode("alter table t rename column tenant_id to tid", errno.ErrDependentByRowAccessPolicy)
+	tk.MustGetErrCode("alter table t change column tenant_id tid varchar(16)", errno.ErrDependentByRowAccessPolicy)
+	tk.MustExec("alter table t modify column tenant_id varchar(32)")
+	tk.MustExec("rename table t to t1")
+	tka.MustQuery("select id from t1 order by id").Check(testkit.Rows("1", "3", "5"))
+
+	// Only the users with ROW_ACCESS_POLICY_ADMIN can manage the policies.
+	errMsg = "[planner:1227]Access denied; you need (at least one of) the SUPER or ROW_ACCESS_POLICY_ADMIN privilege(s) for this operation"
+	tka.MustGetErrMsg("drop row access policy p_tenant on t1", errMsg)
+	tka.MustGetErrMsg("create row access policy p on t1 using (id > 0)", errMsg)
+	tk.MustExec("grant ROW_ACCESS_POLICY_ADMIN on *.* to ua")
+	tka.MustExec("drop row access policy p_tenant on t1")
+	tka.MustQuery("select id from t1 order by id").Check(testkit.Rows("1", "2", "3", "4", "5", "6"))
+	tka.MustExec("drop row access policy if exists p_tenant on t1")
+	tka.MustQuery("show warnings").Check(testkit.Rows("Note 8271 Unknown row access policy 'p_tenant'"))
+	tka.MustGetErrCode("drop row access policy p_tenant on t1", errno.ErrRowAccessPolicyNotExists)
+	tk.MustExec("alter table t1 drop column tenant_id")
+}
@@ -285,6 +285,8 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowSessionStates(ctx)
 	case ast.ShowImportJobs:
 		return e.fetchShowImportJobs(ctx)
+	case ast.ShowTimers:
+		return e.fetchShowTimers(ctx)
 	}
 	return nil
 }
@@ -1176,6 +1178,9 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 				buf.WriteString(" /*T![clustered_index] NONCLUSTERED */")
 			}
 		}
+		if idxInfo.Global {
+			buf.WriteString(" /*T![global_index] GLOBAL */")
+		}
 		if i != len(publicIndices)-1 {
 			buf.WriteString(",\n")
 		}
@@ -1304,6 +1309,16 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *model.CISt
 		fmt.Fprintf(buf, " /* CACHED ON */")
 	}
 
+	if info := tableInfo.IntervalPartitionInfo; info != nil {
+		// It must be written before the partition info, the table options can't follow the partition info.
+		fmt.Fprintf(buf, " /*T![interval_partition] PARTITION_INTERVAL=INTERVAL %s %s PARTITION_PRECREATE=%d",
+			info.IntervalExprStr, ast.TimeUnitType(info.IntervalTimeUnit).String(), info.Precreate)
+		if info.RetentionExprStr != "" {
+			fmt.Fprintf(buf, " PARTITION_RETENTION=INTERVAL %s %s", info.RetentionExprStr, ast.TimeUnitType(info.RetentionTimeUnit).String())
+		}
+		fmt.Fprintf(buf, " */")
+	}
+
 	// add partition info here.
 	ddl.AppendPartitionInfo(tableInfo.Partition, buf, sqlMode)
 
@@ -190,6 +190,16 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeAdmin(x)
 	case *ast.SetResourceGroupStmt:
 		err = e.executeSetResourceGroupName(x)
+	case *ast.CreateTimerStmt:
+		err = e.executeCreateTimer(ctx, x)
+	case *ast.AlterTimerStmt:
+		err = e.executeAlterTimer(ctx, x)
+	case *ast.DropTimerStmt:
+		err = e.executeDropTimer(ctx, x)
+	case *ast.TimerActionStmt:
+		err = e.executeTimerAction(ctx, x)
+	case *ast.RefreshMaterializedViewStmt:
+		err = e.executeRefreshMaterializedView(ctx, x)
 	}
 	e.done = true
 	return err
@@ -1623,6 +1633,24 @@ func checkPasswordReusePolicy(ctx context.Context, sqlExecutor sqlexec.SQLExecut
 	return nil
 }
 
+// attributeChangesTenant checks whether the ATTRIBUTE option of ALTER USER changes the tenant of the user. The option
+// is merged into the metadata of the user attributes, so any value which is not an object replaces the tenant too.
+func attributeChangesTenant(attribute string) (bool, error) {
+	bj, err := types.ParseBinaryJSONFromString(attribute)
+	if err != nil {
+		return false, err
+	}
+	if bj.TypeCode != types.JSONTypeCodeObject {
+		return true, nil
+	}
+	pathExpr, err := types.ParseJSONPathExpr("$.tenant")
+	if err != nil {
+		return false, err
+	}
+	_, found := bj.Extract([]types.JSONPathExpression{pathExpr})
+	return found, nil
+}
+
 func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt) error {
 	disableSandBoxMode := false
 	var err error
@@ -1711,6 +1739,17 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 		if spec.User.CurrentUser || ((user != nil) && (user.Username == spec.User.Username) && (user.AuthHostname == spec.User.Hostname)) {
 			spec.User.Username = user.Username
 			spec.User.Hostname = user.AuthHostname
+			// The tenant of the user is used by the row access policies, so the users can't change it by themselves.
+			if !(hasCreateUserPriv || hasSystemSchemaPriv) && s.CommentOrAttributeOption != nil &&
+				s.CommentOrAttributeOption.Type == ast.UserAttributeType {
+				changed, err := attributeChangesTenant(s.CommentOrAttributeOption.Value)
+				if err != nil {
+					return err
+				}
+				if changed {
+					return core.ErrSpecificAccessDenied.GenWithStackByArgs("CREATE USER")
+				}
+			}
 		} else {
 			// The user executing the query (user) does not match the user specified (spec.User)
 			// The MySQL manual states:
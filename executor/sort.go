@@ -18,17 +18,22 @@ import (
 	"container/heap"
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/executor/internal/exec"
 	"github.com/pingcap/tidb/expression"
 	plannercore "github.com/pingcap/tidb/planner/core"
-	"github.com/pingcap/tidb/planner/util"
+	plannerutil "github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mathutil"
 	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
 )
 
@@ -36,7 +41,7 @@ import (
 type SortExec struct {
 	exec.BaseExecutor
 
-	ByItems []*util.ByItems
+	ByItems []*plannerutil.ByItems
 	Idx     int
 	fetched bool
 	schema  *expression.Schema
@@ -59,6 +64,10 @@ type SortExec struct {
 	multiWayMerge *multiWayMerge
 	// spillAction save the Action for spill disk.
 	spillAction *chunk.SortAndSpillDiskAction
+
+	// concurrency is the number of workers sorting the partitions in parallel. The rows are sorted by the
+	// main goroutine if it's not larger than 1.
+	concurrency int
 }
 
 // Close implements the Executor Close interface.
@@ -109,6 +118,9 @@ func (e *SortExec) Open(ctx context.Context) error {
 //  3. If memory quota is not triggered and child is consumed, sort these rows in memory as partition N.
 //  4. Merge sort if the count of partitions is larger than 1. If there is only one partition in step 4, it works
 //     just like in-memory sort before.
+//
+// If the concurrency is larger than 1, the rows are also put into a new partition every sortBatchChunks full chunks,
+// and the partitions are sorted by the workers in parallel while fetching the rest rows.
 func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if !e.fetched {
@@ -118,6 +130,12 @@ func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		if err != nil {
 			return err
 		}
+		// The rows of a partition are lost if it fails to spill, e.g. the temporary storage quota is exceeded.
+		for _, partition := range e.partitionList {
+			if err := partition.GetSpillError(); err != nil {
+				return err
+			}
+		}
 		e.fetched = true
 	}
 
@@ -173,27 +191,22 @@ func (e *SortExec) externalSorting(req *chunk.Chunk) (err error) {
 	return nil
 }
 
-func (e *SortExec) fetchRowChunks(ctx context.Context) error {
+func (e *SortExec) fetchRowChunks(ctx context.Context) (err error) {
 	fields := retTypes(e)
 	byItemsDesc := make([]bool, len(e.ByItems))
 	for i, byItem := range e.ByItems {
 		byItemsDesc[i] = byItem.Desc
 	}
-	e.rowChunks = chunk.NewSortedRowContainer(fields, e.MaxChunkSize(), byItemsDesc, e.keyColumns, e.keyCmpFuncs)
-	e.rowChunks.GetMemTracker().AttachTo(e.memTracker)
-	e.rowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
-	if variable.EnableTmpStorageOnOOM.Load() {
-		e.spillAction = e.rowChunks.ActionSpill()
-		failpoint.Inject("testSortedRowContainerSpill", func(val failpoint.Value) {
-			if val.(bool) {
-				e.spillAction = e.rowChunks.ActionSpillForTest()
-				defer e.spillAction.WaitForTest()
-			}
-		})
-		e.Ctx().GetSessionVars().MemTracker.FallbackOldAndSetNewAction(e.spillAction)
-		e.rowChunks.GetDiskTracker().AttachTo(e.diskTracker)
-		e.rowChunks.GetDiskTracker().SetLabel(memory.LabelForRowChunks)
-	}
+	// sorter is started when the first partition is full, so the small sorts are not affected.
+	var sorter *partitionSorter
+	defer func() {
+		if sorter == nil {
+			return
+		}
+		if sortErr := sorter.wait(); err == nil {
+			err = sortErr
+		}
+	}()
 	for {
 		chk := tryNewCacheChunk(e.Children(0))
 		err := Next(ctx, e.Children(0), chk)
@@ -204,27 +217,42 @@ func (e *SortExec) fetchRowChunks(ctx context.Context) error {
 		if rowCount == 0 {
 			break
 		}
-		if err := e.rowChunks.Add(chk); err != nil {
-			if errors.Is(err, chunk.ErrCannotAddBecauseSorted) {
-				e.partitionList = append(e.partitionList, e.rowChunks)
+		for {
+			if e.rowChunks == nil {
 				e.rowChunks = chunk.NewSortedRowContainer(fields, e.MaxChunkSize(), byItemsDesc, e.keyColumns, e.keyCmpFuncs)
 				e.rowChunks.GetMemTracker().AttachTo(e.memTracker)
 				e.rowChunks.GetMemTracker().SetLabel(memory.LabelForRowChunks)
-				e.rowChunks.GetDiskTracker().AttachTo(e.diskTracker)
-				e.rowChunks.GetDiskTracker().SetLabel(memory.LabelForRowChunks)
-				e.spillAction = e.rowChunks.ActionSpill()
-				failpoint.Inject("testSortedRowContainerSpill", func(val failpoint.Value) {
-					if val.(bool) {
-						e.spillAction = e.rowChunks.ActionSpillForTest()
-						defer e.spillAction.WaitForTest()
-					}
-				})
-				e.Ctx().GetSessionVars().MemTracker.FallbackOldAndSetNewAction(e.spillAction)
-				err = e.rowChunks.Add(chk)
+				if variable.EnableTmpStorageOnOOM.Load() {
+					e.spillAction = e.rowChunks.ActionSpill()
+					failpoint.Inject("testSortedRowContainerSpill", func(val failpoint.Value) {
+						if val.(bool) {
+							e.spillAction = e.rowChunks.ActionSpillForTest()
+							defer e.spillAction.WaitForTest()
+						}
+					})
+					e.Ctx().GetSessionVars().MemTracker.FallbackOldAndSetNewAction(e.spillAction)
+					e.rowChunks.GetDiskTracker().AttachTo(e.diskTracker)
+					e.rowChunks.GetDiskTracker().SetLabel(memory.LabelForRowChunks)
+				}
 			}
-			if err != nil {
-				return err
+			err = e.rowChunks.Add(chk)
+			if !errors.Is(err, chunk.ErrCannotAddBecauseSorted) {
+				break
 			}
+			// The rows have been sorted and spilled to disk, put the rest rows into a new partition.
+			e.partitionList = append(e.partitionList, e.rowChunks)
+			e.rowChunks = nil
+		}
+		if err != nil {
+			return err
+		}
+		if e.concurrency > 1 && e.rowChunks.NumRow() >= sortBatchChunks*e.MaxChunkSize() {
+			if sorter == nil {
+				sorter = newPartitionSorter(e.concurrency)
+			}
+			e.partitionList = append(e.partitionList, e.rowChunks)
+			sorter.sort(e.rowChunks)
+			e.rowChunks = nil
 		}
 	}
 	failpoint.Inject("SignalCheckpointForSort", func(val failpoint.Value) {
@@ -234,13 +262,70 @@ func (e *SortExec) fetchRowChunks(ctx context.Context) error {
 			}
 		}
 	})
-	if e.rowChunks.NumRow() > 0 {
-		e.rowChunks.Sort()
+	if e.rowChunks != nil && e.rowChunks.NumRow() > 0 {
 		e.partitionList = append(e.partitionList, e.rowChunks)
+		if sorter != nil {
+			sorter.sort(e.rowChunks)
+		} else {
+			e.rowChunks.Sort()
+		}
 	}
 	return nil
 }
 
+// sortBatchChunks is the number of full chunks in a partition sorted by the workers of the parallel sort.
+const sortBatchChunks = 32
+
+// partitionSorter sorts the partitions of SortExec by a pool of workers.
+type partitionSorter struct {
+	taskCh chan *chunk.SortedRowContainer
+	wg     util.WaitGroupWrapper
+
+	mu  sync.Mutex
+	err error
+}
+
+func newPartitionSorter(concurrency int) *partitionSorter {
+	s := &partitionSorter{taskCh: make(chan *chunk.SortedRowContainer, concurrency)}
+	for i := 0; i < concurrency; i++ {
+		s.wg.Run(s.run)
+	}
+	return s
+}
+
+// sort sends the partition to the workers, the partition must not be changed until wait returns.
+func (s *partitionSorter) sort(partition *chunk.SortedRowContainer) {
+	s.taskCh <- partition
+}
+
+// wait waits until all the partitions are sorted, and returns the first error of the workers.
+func (s *partitionSorter) wait() error {
+	close(s.taskCh)
+	s.wg.Wait()
+	return s.err
+}
+
+func (s *partitionSorter) run() {
+	for partition := range s.taskCh {
+		s.sortPartition(partition)
+	}
+}
+
+func (s *partitionSorter) sortPartition(partition *chunk.SortedRowContainer) {
+	defer func() {
+		if r := recover(); r != nil {
+			logutil.BgLogger().Error("parallel sort panicked", zap.Any("recover", r), zap.Stack("stack"))
+			s.mu.Lock()
+			if s.err == nil {
+				s.err = fmt.Errorf("%v", r)
+			}
+			s.mu.Unlock()
+		}
+	}()
+	// The memory tracker may be triggered during sorting, e.g. the query is killed.
+	partition.Sort()
+}
+
 func (e *SortExec) initCompareFuncs() {
 	e.keyCmpFuncs = make([]chunk.CompareFunc, len(e.ByItems))
 	for i := range e.ByItems {
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/stretchr/testify/require"
 )
 
@@ -131,3 +132,87 @@ func TestIssue16696(t *testing.T) {
 		}
 	}
 }
+
+func TestSortTmpStorageQuota(t *testing.T) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TempStoragePath = t.TempDir()
+	})
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/testSortedRowContainerSpill", "return(true)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/testSortedRowContainerSpill"))
+	}()
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	defer tk.MustExec("SET GLOBAL tidb_mem_oom_action = DEFAULT")
+	tk.MustExec("SET GLOBAL tidb_mem_oom_action='LOG'")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(c1 int, c2 int, c3 int)")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values (0, 0, 0)")
+	for i := 1; i < 1024; i++ {
+		buf.WriteString(fmt.Sprintf(", (%v, %v, %v)", i, i, i))
+	}
+	tk.MustExec(buf.String())
+	tk.MustExec("set @@tidb_mem_quota_query=1")
+	tk.MustExec("set @@tidb_max_chunk_size=32")
+
+	diskTracker := tk.Session().GetSessionVars().DiskTracker
+	tk.MustQuery("select @@tidb_tmp_storage_quota_query").Check(testkit.Rows("-1"))
+	require.Equal(t, int64(-1), diskTracker.GetBytesLimit())
+	tk.MustExec("set @@tidb_tmp_storage_quota_query=1048576")
+	require.Equal(t, int64(1048576), diskTracker.GetBytesLimit())
+	// The quota is large enough for the sort.
+	require.Len(t, tk.MustQuery("select * from t order by c1").Rows(), 1024)
+	require.LessOrEqual(t, diskTracker.MaxConsumed(), int64(1048576))
+	require.GreaterOrEqual(t, diskTracker.TotalConsumed(), diskTracker.MaxConsumed())
+
+	// The quota is exceeded when the sort spills.
+	tk.MustExec("set @@tidb_tmp_storage_quota_query=1")
+	err := tk.QueryToErr("select * from t order by c1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), disk.PanicDiskExceedWarnMsg)
+	require.Greater(t, diskTracker.MaxConsumed(), int64(1))
+	// The tracker is reset for the next statement.
+	tk.MustQuery("select 1").Check(testkit.Rows("1"))
+	require.Equal(t, int64(0), diskTracker.BytesConsumed())
+	require.Equal(t, int64(0), diskTracker.MaxConsumed())
+	require.Equal(t, int64(0), diskTracker.TotalConsumed())
+
+	tk.MustExec("set @@tidb_tmp_storage_quota_query=default")
+	require.Equal(t, int64(-1), diskTracker.GetBytesLimit())
+	require.Len(t, tk.MustQuery("select * from t order by c2").Rows(), 1024)
+}
+
+func TestParallelSort(t *testing.T) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TempStoragePath = t.TempDir()
+	})
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b varchar(10), c int)")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values (0, '0', 0)")
+	for i := 1; i < 4096; i++ {
+		buf.WriteString(fmt.Sprintf(", (%v, '%v', %v)", i%97, i%13, i))
+	}
+	tk.MustExec(buf.String())
+	// Every 1024 rows are sorted as a partition by the workers.
+	tk.MustExec("set @@tidb_max_chunk_size=32")
+	sql := "select * from t order by a, b desc, c"
+
+	tk.MustExec("set @@tidb_executor_concurrency=1")
+	ordered := tk.MustQuery(sql).Rows()
+	require.Len(t, ordered, 4096)
+
+	tk.MustExec("set @@tidb_executor_concurrency=4")
+	tk.MustQuery(sql).Check(ordered)
+
+	// The partitions may be spilled to disk while being sorted by the workers.
+	defer tk.MustExec("SET GLOBAL tidb_mem_oom_action = DEFAULT")
+	tk.MustExec("SET GLOBAL tidb_mem_oom_action='LOG'")
+	tk.MustExec("set @@tidb_mem_quota_query=1")
+	tk.MustQuery(sql).Check(ordered)
+}
@@ -16,9 +16,12 @@ package executor
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -26,8 +29,10 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/set"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stmtsummary"
 	stmtsummaryv2 "github.com/pingcap/tidb/util/stmtsummary/v2"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -406,3 +411,108 @@ func buildTimeRanges(tr *plannercore.TimeRange) []*stmtsummaryv2.StmtTimeRange {
 		End:   tr.EndTime.Unix(),
 	}}
 }
+
+// stmtDigestHistoryRetriever is used to retrieve CLUSTER_STATEMENTS_DIGEST_HISTORY. It aggregates
+// CLUSTER_STATEMENTS_SUMMARY_HISTORY of all TiDB instances by digest into the buckets of the
+// compacted store, which are aligned to the unix epoch and shared by all instances.
+type stmtDigestHistoryRetriever struct {
+	dummyCloser
+	table     *model.TableInfo
+	columns   []*model.ColumnInfo
+	extractor *plannercore.StatementsSummaryExtractor
+	retrieved bool
+}
+
+func (e *stmtDigestHistoryRetriever) retrieve(ctx context.Context, sctx sessionctx.Context) ([][]types.Datum, error) {
+	if err := checkPrivilege(sctx); err != nil {
+		return nil, err
+	}
+	if e.retrieved || e.extractor.SkipRequest {
+		return nil, nil
+	}
+	e.retrieved = true
+
+	interval := int64(config.GetGlobalConfig().Instance.StmtSummaryCompactionInterval)
+	sql := new(strings.Builder)
+	args := []interface{}{interval, interval}
+	sqlexec.MustFormatSQL(sql, "select cast(floor(unix_timestamp(summary_begin_time) / %?) * %? as signed) as bucket, "+
+		"stmt_type, schema_name, digest, any_value(digest_text), count(distinct instance), "+
+		"cast(sum(exec_count) as unsigned), cast(sum(sum_errors) as unsigned), cast(sum(sum_warnings) as unsigned), "+
+		"cast(sum(sum_latency) as unsigned), max(max_latency), min(min_latency), "+
+		"cast(sum(avg_processed_keys * exec_count) as unsigned), cast(sum(avg_total_keys * exec_count) as unsigned), "+
+		"cast(sum(avg_mem * exec_count) as unsigned), cast(sum(avg_result_rows * exec_count) as signed), "+
+		"cast(floor(min(unix_timestamp(first_seen))) as signed), cast(floor(max(unix_timestamp(last_seen))) as signed) "+
+		"from information_schema.cluster_statements_summary_history where 1", args...)
+	// A bucket is either fully read or skipped, so the time range is widened to the buckets it overlaps.
+	if tr := e.extractor.CoarseTimeRange; tr != nil {
+		begin := tr.StartTime.Unix() / interval * interval
+		end := tr.EndTime.Unix()/interval*interval + interval
+		sqlexec.MustFormatSQL(sql, " and summary_begin_time >= from_unixtime(%?) and summary_begin_time < from_unixtime(%?)", begin, end)
+	}
+	if !e.extractor.Digests.Empty() {
+		digests := make([]string, 0, e.extractor.Digests.Count())
+		for digest := range e.extractor.Digests {
+			digests = append(digests, digest)
+		}
+		slices.Sort(digests)
+		sqlexec.MustFormatSQL(sql, " and digest in (%?)", digests)
+	}
+	sqlexec.MustFormatSQL(sql, " group by bucket, stmt_type, schema_name, digest")
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	chunkRows, _, err := exec.ExecRestrictedSQL(ctx, nil, sql.String())
+	if err != nil {
+		return nil, err
+	}
+
+	tz := sctx.GetSessionVars().Location()
+	toTime := func(unix int64) types.Time {
+		return types.NewTime(types.FromGoTime(time.Unix(unix, 0).In(tz)), mysql.TypeTimestamp, 0)
+	}
+	rows := make([][]types.Datum, 0, len(chunkRows))
+	for _, r := range chunkRows {
+		bucket := r.GetInt64(0)
+		execCount := r.GetUint64(6)
+		avg := func(sum uint64) uint64 {
+			if execCount == 0 {
+				return 0
+			}
+			return sum / execCount
+		}
+		var avgResultRows int64
+		if execCount > 0 {
+			avgResultRows = r.GetInt64(15) / int64(execCount)
+		}
+		row := types.MakeDatums(
+			toTime(bucket),
+			toTime(bucket+interval),
+			r.GetString(1),
+			nil,
+			nil,
+			r.GetString(4),
+			uint64(r.GetInt64(5)),
+			execCount,
+			r.GetUint64(7),
+			r.GetUint64(8),
+			r.GetUint64(9),
+			r.GetUint64(10),
+			r.GetUint64(11),
+			avg(r.GetUint64(9)),
+			avg(r.GetUint64(12)),
+			avg(r.GetUint64(13)),
+			avg(r.GetUint64(14)),
+			avgResultRows,
+			toTime(r.GetInt64(16)),
+			toTime(r.GetInt64(17)),
+		)
+		if !r.IsNull(2) {
+			row[3].SetString(r.GetString(2), mysql.DefaultCollationName)
+		}
+		if !r.IsNull(3) {
+			row[4].SetString(r.GetString(3), mysql.DefaultCollationName)
+		}
+		rows = append(rows, row)
+	}
+	return adjustColumns(rows, e.columns, e.table), nil
+}
@@ -0,0 +1,363 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"unsafe"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/tidb/executor/internal/exec"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	derr "github.com/pingcap/tidb/store/driver/error"
+	"github.com/pingcap/tidb/store/helper"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/rowcodec"
+	tikverr "github.com/tikv/client-go/v2/error"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	tableChangeInsert = "insert"
+	tableChangeUpdate = "update"
+	tableChangeDelete = "delete"
+)
+
+const (
+	// tableChangesBatchSize is the max number of the changed rows whose MVCC history is read at once.
+	tableChangesBatchSize = 1024
+	// tableChangesConcurrency is the max number of the concurrent requests reading the MVCC history of the rows.
+	tableChangesConcurrency = 16
+	// tableChangesMaxBackoff is the max backoff time in milliseconds of reading the MVCC history of a row.
+	tableChangesMaxBackoff = 20000
+)
+
+// tableChange is a change of a row read by TableChangesExec.
+type tableChange struct {
+	op       string
+	commitTS uint64
+	handle   kv.Handle
+	// value is the row after the change, or the row before the change for a deletion.
+	value []byte
+}
+
+// tableChangeSize is the memory usage of a tableChange without its handle and value.
+const tableChangeSize = int64(unsafe.Sizeof(tableChange{}))
+
+// TableChangesExec reads the row changes of a table committed in the time range (startTS, endTS].
+// The snapshots of the table at startTS and endTS are scanned together to find the rows whose values
+// are different, then the MVCC history of these rows is read from the leaders of their regions to get
+// every change of them in the time range. So the changes of a row which are cancelled out by each other,
+// e.g. a row inserted and then deleted in the time range, are not returned. The changes are returned
+// in the order of the rows, and the changes of a row are returned in the order of the commit ts.
+type TableChangesExec struct {
+	exec.BaseExecutor
+
+	table            *model.TableInfo
+	physicalTableIDs []int64
+	startTS          uint64
+	endTS            uint64
+
+	// rowSchema is the schema of the table columns, it's used to decode the rows.
+	rowSchema *expression.Schema
+	rowChk    *chunk.Chunk
+	decoder   *rowcodec.ChunkDecoder
+
+	store      helper.Storage
+	memTracker *memory.Tracker
+
+	// tableIdx is the index of the physical table being scanned, startIter and endIter scan it in the
+	// snapshots at startTS and endTS.
+	tableIdx  int
+	startIter kv.Iterator
+	endIter   kv.Iterator
+	// changes are the changes of the batch being returned.
+	changes      []tableChange
+	changesBytes int64
+	cursor       int
+}
+
+// Open implements the Executor Open interface.
+func (e *TableChangesExec) Open(context.Context) error {
+	store, ok := e.Ctx().GetStore().(helper.Storage)
+	if !ok {
+		return errors.New("TABLE_CHANGES is not supported by the current storage")
+	}
+	e.store = store
+	e.memTracker = memory.NewTracker(e.ID(), -1)
+	e.memTracker.AttachTo(e.Ctx().GetSessionVars().StmtCtx.MemTracker)
+	e.rowChk = chunk.New(retTypes(e)[2:], 1, 1)
+	e.decoder = NewRowDecoder(e.Ctx(), e.rowSchema, e.table)
+	e.tableIdx = 0
+	e.changes, e.changesBytes, e.cursor = nil, 0, 0
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *TableChangesExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for req.NumRows() < e.MaxChunkSize() {
+		if e.cursor >= len(e.changes) {
+			if e.tableIdx >= len(e.physicalTableIDs) {
+				return nil
+			}
+			if err := e.fetchNextBatch(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		change := e.changes[e.cursor]
+		e.cursor++
+		e.rowChk.Reset()
+		err := DecodeRowValToChunk(e.Ctx(), e.rowSchema, e.table, change.handle, change.value, e.rowChk, e.decoder)
+		if err != nil {
+			return err
+		}
+		req.AppendString(0, change.op)
+		req.AppendUint64(1, change.commitTS)
+		req.AppendPartialRow(2, e.rowChk.GetRow(0))
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *TableChangesExec) Close() error {
+	e.closeIters()
+	e.releaseChanges()
+	return e.BaseExecutor.Close()
+}
+
+func (e *TableChangesExec) releaseChanges() {
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.changesBytes)
+	}
+	e.changes, e.changesBytes, e.cursor = e.changes[:0], 0, 0
+}
+
+func (e *TableChangesExec) openIters() error {
+	prefix := tablecodec.GenTableRecordPrefix(e.physicalTableIDs[e.tableIdx])
+	iters := make([]kv.Iterator, 0, 2)
+	for _, ts := range []uint64{e.startTS, e.endTS} {
+		snap := e.store.GetSnapshot(kv.NewVersion(ts))
+		snap.SetOption(kv.NotFillCache, true)
+		iter, err := snap.Iter(prefix, prefix.PrefixNext())
+		if err != nil {
+			for _, it := range iters {
+				it.Close()
+			}
+			return err
+		}
+		iters = append(iters, iter)
+	}
+	e.startIter, e.endIter = iters[0], iters[1]
+	return nil
+}
+
+func (e *TableChangesExec) closeIters() {
+	if e.startIter != nil {
+		e.startIter.Close()
+		e.endIter.Close()
+		e.startIter, e.endIter = nil, nil
+	}
+}
+
+// fetchNextBatch finds the next batch of the rows whose values at startTS and endTS are different, and
+// replaces e.changes with the changes of these rows.
+func (e *TableChangesExec) fetchNextBatch(ctx context.Context) error {
+	e.releaseChanges()
+	keys := make([]kv.Key, 0, tableChangesBatchSize)
+	startVals := make([][]byte, 0, tableChangesBatchSize)
+	for len(keys) < tableChangesBatchSize && e.tableIdx < len(e.physicalTableIDs) {
+		if e.startIter == nil {
+			if err := e.openIters(); err != nil {
+				return err
+			}
+		}
+		key, startVal, err := e.nextChangedRow()
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			e.closeIters()
+			e.tableIdx++
+			continue
+		}
+		keys, startVals = append(keys, key), append(startVals, startVal)
+	}
+	infos := make([]*kvrpcpb.MvccInfo, len(keys))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(tableChangesConcurrency)
+	for i := range keys {
+		i := i
+		eg.Go(func() (err error) {
+			infos[i], err = getMVCCByKey(egCtx, e.store, keys[i])
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if err := e.appendRowChanges(key, startVals[i], infos[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextChangedRow returns the next row whose values at startTS and endTS are different, and its value
+// at startTS, which is nil if the row doesn't exist then. The key is nil if there are no more such rows.
+func (e *TableChangesExec) nextChangedRow() (kv.Key, []byte, error) {
+	for e.startIter.Valid() || e.endIter.Valid() {
+		cmp := -1
+		switch {
+		case !e.startIter.Valid():
+			cmp = 1
+		case e.endIter.Valid():
+			cmp = e.startIter.Key().Cmp(e.endIter.Key())
+		}
+		var (
+			key      kv.Key
+			startVal []byte
+			changed  = true
+			err      error
+		)
+		switch {
+		case cmp < 0:
+			key, startVal = e.startIter.Key().Clone(), bytes.Clone(e.startIter.Value())
+			err = e.startIter.Next()
+		case cmp > 0:
+			key = e.endIter.Key().Clone()
+			err = e.endIter.Next()
+		default:
+			key, startVal = e.startIter.Key().Clone(), bytes.Clone(e.startIter.Value())
+			changed = !bytes.Equal(e.startIter.Value(), e.endIter.Value())
+			if err = e.startIter.Next(); err == nil {
+				err = e.endIter.Next()
+			}
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if changed {
+			return key, startVal, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// getMVCCByKey reads the MVCC history of the key from the leader of its region. The reading is retried
+// with the backoff when the region is changed, e.g. split, merged or transferred to another leader.
+func getMVCCByKey(ctx context.Context, store helper.Storage, key kv.Key) (*kvrpcpb.MvccInfo, error) {
+	bo := tikv.NewBackofferWithVars(ctx, tableChangesMaxBackoff, nil)
+	for {
+		loc, err := store.GetRegionCache().LocateKey(bo, key)
+		if err != nil {
+			return nil, derr.ToTiDBErr(err)
+		}
+		req := tikvrpc.NewRequest(tikvrpc.CmdMvccGetByKey, &kvrpcpb.MvccGetByKeyRequest{Key: key})
+		resp, err := store.SendReq(bo, req, loc.Region, tikv.ReadTimeoutMedium)
+		if err != nil {
+			return nil, derr.ToTiDBErr(err)
+		}
+		regionErr, err := resp.GetRegionError()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if regionErr != nil {
+			if err = bo.Backoff(tikv.BoRegionMiss(), errors.New(regionErr.String())); err != nil {
+				return nil, derr.ToTiDBErr(err)
+			}
+			continue
+		}
+		if resp.Resp == nil {
+			return nil, errors.Trace(tikverr.ErrBodyMissing)
+		}
+		mvccResp := resp.Resp.(*kvrpcpb.MvccGetByKeyResponse)
+		if mvccResp.Error != "" {
+			return nil, errors.Errorf("get MVCC of key %s failed: %s", key, mvccResp.Error)
+		}
+		return mvccResp.Info, nil
+	}
+}
+
+// appendRowChanges appends the changes of the row `key` committed in the time range by its MVCC history.
+// `startVal` is the value of the row at startTS, it's nil if the row doesn't exist then.
+func (e *TableChangesExec) appendRowChanges(key kv.Key, startVal []byte, info *kvrpcpb.MvccInfo) error {
+	if info == nil {
+		return nil
+	}
+	writes := make([]*kvrpcpb.MvccWrite, 0, len(info.Writes))
+	for _, w := range info.Writes {
+		if w.CommitTs > e.startTS && w.CommitTs <= e.endTS && (w.Type == kvrpcpb.Op_Put || w.Type == kvrpcpb.Op_Del) {
+			writes = append(writes, w)
+		}
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	sort.Slice(writes, func(i, j int) bool { return writes[i].CommitTs < writes[j].CommitTs })
+	handle, err := tablecodec.DecodeRowKey(key)
+	if err != nil {
+		return err
+	}
+	// prev is the value of the row before the change, it's nil if the row doesn't exist then.
+	prev := startVal
+	for _, w := range writes {
+		var val []byte
+		if w.Type == kvrpcpb.Op_Put {
+			val = w.ShortValue
+			if len(val) == 0 {
+				val = findMvccValue(info.Values, w.StartTs)
+			}
+			if len(val) == 0 {
+				return errors.Errorf("the value of key %s committed at %d is not found", key, w.CommitTs)
+			}
+		}
+		change := tableChange{op: tableChangeInsert, commitTS: w.CommitTs, handle: handle, value: val}
+		switch {
+		case val == nil && prev == nil:
+			continue
+		case val == nil:
+			change.op, change.value = tableChangeDelete, prev
+		case prev != nil:
+			change.op = tableChangeUpdate
+		}
+		e.changes = append(e.changes, change)
+		memUsage := tableChangeSize + int64(len(change.value)) + int64(handle.MemUsage())
+		e.changesBytes += memUsage
+		e.memTracker.Consume(memUsage)
+		prev = val
+	}
+	return nil
+}
+
+func findMvccValue(values []*kvrpcpb.MvccValue, startTS uint64) []byte {
+	for _, v := range values {
+		if v.StartTs == startTS {
+			return v.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/testutils"
+)
+
+func TestTableChanges(t *testing.T) {
+	var cluster testutils.Cluster
+	store, dom := testkit.CreateMockStoreAndDomain(t, mockstore.WithClusterInspector(func(c testutils.Cluster) {
+		mockstore.BootstrapWithSingleStore(c)
+		cluster = c
+	}))
+	tk := testkit.NewTestKit(t, store)
+	// The `tikv_gc_safe_point` global variable must be there to validate the start ts.
+	timeSafe := time.Now().Add(-48 * 60 * 60 * time.Second).Format("20060102-15:04:05 -0700 MST")
+	tk.MustExec(fmt.Sprintf(`INSERT HIGH_PRIORITY INTO mysql.tidb VALUES ('tikv_gc_safe_point', '%[1]s', '')
+		ON DUPLICATE KEY UPDATE variable_value = '%[1]s'`, timeSafe))
+	currentTS := func() uint64 {
+		ver, err := store.CurrentVersion(kv.GlobalTxnScope)
+		require.NoError(t, err)
+		return ver.Ver
+	}
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, v varchar(10), g int as (id + 1) virtual)")
+	tk.MustExec("insert into t (id, v) values (1, 'a'), (2, 'b'), (3, 'c')")
+	ts1 := currentTS()
+	tk.MustExec("insert into t (id, v) values (4, 'd')")
+	tk.MustExec("update t set v = 'bb' where id = 2")
+	tk.MustExec("delete from t where id = 3")
+	tk.MustExec("update t set v = 'bbb' where id = 2")
+	// the changes of the rows which are cancelled out by each other are not returned
+	tk.MustExec("insert into t (id, v) values (5, 'e')")
+	tk.MustExec("delete from t where id = 5")
+	tk.MustExec("update t set v = 'x' where id = 1")
+	tk.MustExec("update t set v = 'a' where id = 1")
+	ts2 := currentTS()
+	tk.MustExec("delete from t where id = 1")
+
+	// the changes are returned in the order of the rows, then in the order of the commit ts
+	query := fmt.Sprintf("select _tidb_op, id, v from table_changes(t, %d, %d) c", ts1, ts2)
+	tk.MustQuery(query).Check(testkit.Rows("update 2 bb", "update 2 bbb", "delete 3 c", "insert 4 d"))
+	tk.MustQuery(fmt.Sprintf("select count(*) from table_changes(t, %d, %d) c where _tidb_commit_ts > %d and _tidb_commit_ts <= %d", ts1, ts2, ts1, ts2)).
+		Check(testkit.Rows("4"))
+	tk.MustQuery(fmt.Sprintf("select * from table_changes(test.t, %d, %d) where id = 4", ts1, ts2)).
+		CheckAt([]int{0, 2, 3}, testkit.Rows("insert 4 d"))
+	tk.MustQuery(fmt.Sprintf("select _tidb_op, id from table_changes(t, %d, %d) c", ts2, currentTS())).Check(testkit.Rows("delete 1"))
+	tk.MustQuery(fmt.Sprintf("select _tidb_op, id from table_changes(t, now() - interval 1 hour, %d) c order by id, _tidb_commit_ts", ts2)).
+		Check(testkit.Rows("insert 1", "update 1", "update 1", "insert 2", "update 2", "update 2", "insert 4"))
+	require.True(t, tk.HasPlan(query, "TableChanges"))
+
+	// the changes of the partitions
+	tk.MustExec("create table pt (id int, v int, key(id)) partition by range (id) (partition p0 values less than (10), partition p1 values less than (20))")
+	ts3 := currentTS()
+	tk.MustExec("insert into pt values (1, 1), (11, 11)")
+	tk.MustExec("update pt set v = v + 1")
+	ts4 := currentTS()
+	tk.MustQuery(fmt.Sprintf("select _tidb_op, id, v from table_changes(pt, %d, %d) c order by _tidb_op, id", ts3, ts4)).
+		Check(testkit.Rows("insert 1 1", "insert 11 11", "update 1 2", "update 11 12"))
+	tk.MustQuery(fmt.Sprintf("select _tidb_op, id, v from table_changes(pt partition (p1), %d, %d) c", ts3, ts4)).
+		Check(testkit.Rows("insert 11 11", "update 11 12"))
+
+	// the changes of more rows than a batch
+	tk.MustExec("create table t2 (id int primary key)")
+	ts5 := currentTS()
+	tk.MustExec("set @@cte_max_recursion_depth = 5000")
+	tk.MustExec("insert into t2 select * from (with recursive c(n) as (select 1 union all select n + 1 from c where n < 3000) select n from c) s")
+	ts6 := currentTS()
+	tk.MustExec("delete from t2 where id > 2000")
+	tk.MustExec("update t2 set id = id + 3000 where id <= 1000")
+	ts7 := currentTS()
+	tk.MustQuery(fmt.Sprintf("select _tidb_op, count(*), min(id), max(id) from table_changes(t2, %d, %d) c group by _tidb_op order by _tidb_op", ts5, ts7)).
+		Check(testkit.Rows("insert 2000 1001 4000"))
+	tk.MustQuery(fmt.Sprintf("select _tidb_op, count(*), min(id), max(id) from table_changes(t2, %d, %d) c group by _tidb_op order by _tidb_op", ts6, ts7)).
+		Check(testkit.Rows("delete 2000 1 3000", "insert 1000 3001 4000"))
+	// the regions are split behind the region cache, so the reading is retried in the new regions
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	require.NoError(t, err)
+	prefix := tablecodec.GenTableRecordPrefix(tbl.Meta().ID)
+	cluster.SplitKeys(prefix, prefix.PrefixNext(), 8)
+	tk.MustQuery(fmt.Sprintf("select _tidb_op, count(*), min(id), max(id) from table_changes(t2, %d, %d) c group by _tidb_op order by _tidb_op", ts6, ts7)).
+		Check(testkit.Rows("delete 2000 1 3000", "insert 1000 3001 4000"))
+
+	// the changes of the transactions which are prewritten but not committed are not returned
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.MustExec("use test")
+	tk1.MustExec("set @@tidb_enable_1pc = 0, @@tidb_enable_async_commit = 0")
+	tk1.MustExec("begin optimistic")
+	tk1.MustExec("insert into t2 values (5000)")
+	tk1.MustExec("update t2 set id = 6000 where id = 1001")
+	require.NoError(t, failpoint.Enable("tikvclient/beforeCommit", `return("fail")`))
+	require.NoError(t, failpoint.Enable("tikvclient/commitFailedSkipCleanup", "return"))
+	require.Error(t, tk1.ExecToErr("commit"))
+	require.NoError(t, failpoint.Disable("tikvclient/beforeCommit"))
+	require.NoError(t, failpoint.Disable("tikvclient/commitFailedSkipCleanup"))
+	tk.MustQuery(fmt.Sprintf("select count(*) from table_changes(t2, %d, %d) c", ts7, currentTS())).Check(testkit.Rows("0"))
+
+	tk.MustGetErrCode(fmt.Sprintf("select * from table_changes(t, %d, %d)", ts2, ts1), errno.ErrWrongArguments)
+	tk.MustGetErrCode(fmt.Sprintf("select * from table_changes(t, null, %d)", ts1), errno.ErrWrongArguments)
+	tk.MustGetErrCode(fmt.Sprintf("select * from table_changes(t partition (p0), %d, %d)", ts1, ts2), errno.ErrPartitionClauseOnNonpartitioned)
+	tk.MustGetErrCode(fmt.Sprintf("select * from table_changes(t_not_exists, %d, %d)", ts1, ts2), errno.ErrNoSuchTable)
+	tk.MustContainErrMsg(fmt.Sprintf("select * from table_changes(t, %d, %d)", ts1, ts2+(1<<40)), "is later than the current ts")
+	tk.MustContainErrMsg(fmt.Sprintf("explain select * from table_changes(t, %d, %d)", ts1, ts2+(1<<40)), "is later than the current ts")
+	// The changes before the GC safe point may have been removed.
+	timeSafe = time.Now().Add(time.Hour).Format("20060102-15:04:05 -0700 MST")
+	tk.MustExec(fmt.Sprintf("UPDATE mysql.tidb SET variable_value = '%s' WHERE variable_name = 'tikv_gc_safe_point'", timeSafe))
+	tk.MustGetErrCode(fmt.Sprintf("select * from table_changes(t, %d, %d)", ts1, ts2), errno.ErrSnapshotTooOld)
+	tk.MustGetErrCode(fmt.Sprintf("explain select * from table_changes(t, %d, %d)", ts1, ts2), errno.ErrSnapshotTooOld)
+	tk.MustExec("create view vt as select * from t")
+	tk.MustGetErrCode(fmt.Sprintf("select * from table_changes(vt, %d, %d)", ts1, ts2), errno.ErrWrongObject)
+}
@@ -1504,6 +1504,47 @@ func TestAggInDisk(t *testing.T) {
 	tk.MustQuery("select /*+ HASH_AGG() */ count(c) from t group by c1;").Check(testkit.Rows())
 }
 
+func TestParallelAggInDisk(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set tidb_hashagg_final_concurrency = 4;")
+	tk.MustExec("set tidb_hashagg_partial_concurrency = 4;")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	sql := "insert into t values (0, 0)"
+	for i := 1; i < 1000; i++ {
+		sql += fmt.Sprintf(",(%v, %v)", i%100, i)
+	}
+	tk.MustExec(sql)
+	queries := []string{
+		"select /*+ HASH_AGG() */ t1.a, count(*), sum(t1.b), avg(t2.b) from t t1 join t t2 on t1.b = t2.b group by t1.a",
+		"select /*+ HASH_AGG() */ t1.a, t2.a, max(t1.b), count(distinct t2.b) from t t1 join t t2 on t1.b = t2.b group by t1.a, t2.a",
+		"select /*+ HASH_AGG() */ b % 7, count(*), group_concat(a order by b) from t group by b % 7",
+	}
+	expected := make([][][]interface{}, 0, len(queries))
+	for _, query := range queries {
+		expected = append(expected, tk.MustQuery(query).Sort().Rows())
+	}
+
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/triggerParallelHashAggSpill", "return(true)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/triggerParallelHashAggSpill"))
+	}()
+	for i, query := range queries {
+		tk.MustQuery(query).Sort().Check(expected[i])
+	}
+	rows := tk.MustQuery("explain analyze " + queries[0]).Rows()
+	for _, row := range rows {
+		line := fmt.Sprintf("%v", row)
+		disk := fmt.Sprintf("%v", row[len(row)-1])
+		if strings.Contains(line, "HashAgg") {
+			require.False(t, strings.Contains(disk, "0 Bytes"))
+		}
+	}
+}
+
 func TestRandomPanicConsume(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -309,6 +309,11 @@ func TestShow(t *testing.T) {
 		"RESTRICTED_CONNECTION_ADMIN Server Admin ",
 		"RESTRICTED_REPLICA_WRITER_ADMIN Server Admin ",
 		"RESOURCE_GROUP_ADMIN Server Admin ",
+		"TIMER_ADMIN Server Admin ",
+		"MASKING_POLICY_ADMIN Server Admin ",
+		"UNMASKED_READ Server Admin ",
+		"ROW_ACCESS_POLICY_ADMIN Server Admin ",
+		"ROW_ACCESS_POLICY_EXEMPT Server Admin ",
 	))
 	require.Len(t, tk.MustQuery("show table status").Rows(), 1)
 }
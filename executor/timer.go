@@ -0,0 +1,367 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/pingcap/tidb/timer/usertimer"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+)
+
+// userTimer is a user-defined timer with its decoded data and summary.
+type userTimer struct {
+	*timerapi.TimerRecord
+	data    *usertimer.TimerData
+	summary *usertimer.TimerSummary
+}
+
+func getUserTimerClient(sctx sessionctx.Context) (timerapi.TimerClient, error) {
+	store := domain.GetDomain(sctx).UserTimerStore()
+	if store == nil {
+		return nil, errors.New("user-defined timers are not available")
+	}
+	return timerapi.NewDefaultTimerClient(store), nil
+}
+
+// getUserTimer returns the user-defined timer with the name. It returns nil if the timer does not exist.
+func getUserTimer(ctx context.Context, cli timerapi.TimerClient, name *ast.TableName) (*timerapi.TimerRecord, error) {
+	timer, err := cli.GetTimerByKey(ctx, usertimer.TimerKey(name.Schema.O, name.Name.O))
+	if errors.ErrorEqual(err, timerapi.ErrTimerNotExist) {
+		return nil, nil
+	}
+	return timer, err
+}
+
+// listUserTimers returns the user-defined timers ordered by schema and name.
+// All timers are returned if `schema` is empty.
+func listUserTimers(ctx context.Context, sctx sessionctx.Context, schema string) ([]*userTimer, error) {
+	cli, err := getUserTimerClient(sctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPrefix := usertimer.KeyPrefix
+	if schema != "" {
+		keyPrefix = usertimer.SchemaKeyPrefix(schema)
+	}
+
+	timers, err := cli.GetTimers(ctx, timerapi.WithKeyPrefix(keyPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*userTimer, 0, len(timers))
+	for _, timer := range timers {
+		data, err := usertimer.DecodeTimerData(timer)
+		if err != nil {
+			logutil.BgLogger().Warn("skip invalid user timer", zap.String("key", timer.Key), zap.Error(err))
+			continue
+		}
+
+		summary, err := usertimer.DecodeTimerSummary(timer)
+		if err != nil {
+			logutil.BgLogger().Warn("invalid summary of user timer", zap.String("key", timer.Key), zap.Error(err))
+			summary = &usertimer.TimerSummary{}
+		}
+		result = append(result, &userTimer{TimerRecord: timer, data: data, summary: summary})
+	}
+
+	slices.SortFunc(result, func(a, b *userTimer) bool {
+		return a.Key < b.Key
+	})
+	return result, nil
+}
+
+// hasTimerAdminPriv returns whether the current user has the privilege to manage the user-defined timers.
+func hasTimerAdminPriv(sctx sessionctx.Context) bool {
+	pm := privilege.GetPrivilegeManager(sctx)
+	return pm == nil || pm.RequestDynamicVerification(sctx.GetSessionVars().ActiveRoles, "TIMER_ADMIN", false)
+}
+
+// timerTimeValue converts a time of the timer to a datetime in the session time zone.
+// It returns nil for a zero time.
+func timerTimeValue(sctx sessionctx.Context, t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return types.NewTime(types.FromGoTime(t.In(sctx.GetSessionVars().Location())), mysql.TypeDatetime, 0)
+}
+
+func timerEnabledValue(enable bool) string {
+	if enable {
+		return "YES"
+	}
+	return "NO"
+}
+
+func timerNextEventTimeValue(sctx sessionctx.Context, timer *userTimer) interface{} {
+	if timer.EventStatus == timerapi.SchedEventTrigger {
+		return nil
+	}
+
+	next, ok, err := timer.NextEventTime()
+	if err != nil || !ok {
+		return nil
+	}
+	return timerTimeValue(sctx, next)
+}
+
+func restoreTimerBody(body ast.StmtNode) (string, error) {
+	var sb strings.Builder
+	if err := body.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// timerCreator returns the current user as the creator of a timer, the body of the timer is executed with the
+// privileges of the creator.
+func timerCreator(sctx sessionctx.Context) (string, error) {
+	user := sctx.GetSessionVars().User
+	if user == nil {
+		return "", errors.New("the timer body can't be set without a user")
+	}
+	return user.String(), nil
+}
+
+// applyTimerOptions applies the options to the spec and the data of a user-defined timer, and validates them.
+func applyTimerOptions(spec *timerapi.TimerSpec, data *usertimer.TimerData, options []*ast.TimerOption) error {
+	for _, opt := range options {
+		switch opt.Tp {
+		case ast.TimerOptionSchedule:
+			spec.SchedPolicyType = timerapi.SchedPolicyType(opt.SchedPolicyType)
+			spec.SchedPolicyExpr = opt.StrValue
+		case ast.TimerOptionTimeZone:
+			spec.TimeZone = opt.StrValue
+		case ast.TimerOptionEnable:
+			spec.Enable = opt.BoolValue
+		case ast.TimerOptionComment:
+			data.Comment = opt.StrValue
+		}
+	}
+
+	if spec.SchedPolicyType == "" {
+		return errors.New("SCHEDULE should be specified for the timer")
+	}
+
+	if _, err := spec.CreateSchedEventPolicy(); err != nil {
+		return err
+	}
+
+	_, err := spec.Location()
+	return err
+}
+
+func (e *SimpleExec) executeCreateTimer(ctx context.Context, s *ast.CreateTimerStmt) error {
+	if _, ok := e.is.SchemaByName(s.Name.Schema); !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.Name.Schema.O)
+	}
+
+	cli, err := getUserTimerClient(e.Ctx())
+	if err != nil {
+		return err
+	}
+
+	timer, err := getUserTimer(ctx, cli, s.Name)
+	if err != nil {
+		return err
+	}
+
+	if timer != nil {
+		err = infoschema.ErrTimerExists.GenWithStackByArgs(s.Name.Name.O)
+		if s.IfNotExists {
+			e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	sql, err := restoreTimerBody(s.Body)
+	if err != nil {
+		return err
+	}
+
+	creator, err := timerCreator(e.Ctx())
+	if err != nil {
+		return err
+	}
+
+	data := &usertimer.TimerData{
+		Schema:  s.Name.Schema.O,
+		Name:    s.Name.Name.O,
+		SQL:     sql,
+		Creator: creator,
+	}
+
+	spec := timerapi.TimerSpec{
+		Key:       usertimer.TimerKey(s.Name.Schema.O, s.Name.Name.O),
+		HookClass: usertimer.HookClass,
+		Enable:    true,
+		// The first event is scheduled one period after the timer is created.
+		Watermark: time.Now(),
+	}
+
+	if err = applyTimerOptions(&spec, data, s.Options); err != nil {
+		return err
+	}
+
+	if spec.Data, err = usertimer.EncodeTimerData(data); err != nil {
+		return err
+	}
+
+	_, err = cli.CreateTimer(ctx, spec)
+	return err
+}
+
+func (e *SimpleExec) executeAlterTimer(ctx context.Context, s *ast.AlterTimerStmt) error {
+	cli, err := getUserTimerClient(e.Ctx())
+	if err != nil {
+		return err
+	}
+
+	timer, err := getUserTimer(ctx, cli, s.Name)
+	if err != nil {
+		return err
+	}
+
+	if timer == nil {
+		err = infoschema.ErrTimerNotExists.GenWithStackByArgs(s.Name.Name.O)
+		if s.IfExists {
+			e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	data, err := usertimer.DecodeTimerData(timer)
+	if err != nil {
+		return err
+	}
+
+	if s.Body != nil {
+		if data.SQL, err = restoreTimerBody(s.Body); err != nil {
+			return err
+		}
+		// The new body is executed with the privileges of the user who alters it.
+		if data.Creator, err = timerCreator(e.Ctx()); err != nil {
+			return err
+		}
+	}
+
+	spec := timer.TimerSpec
+	if err = applyTimerOptions(&spec, data, s.Options); err != nil {
+		return err
+	}
+
+	if spec.Data, err = usertimer.EncodeTimerData(data); err != nil {
+		return err
+	}
+
+	return cli.UpdateTimer(ctx, timer.ID,
+		timerapi.WithSetSchedExpr(spec.SchedPolicyType, spec.SchedPolicyExpr),
+		timerapi.WithSetTimeZone(spec.TimeZone),
+		timerapi.WithSetEnable(spec.Enable),
+		timerapi.WithSetData(spec.Data),
+	)
+}
+
+func (e *SimpleExec) executeDropTimer(ctx context.Context, s *ast.DropTimerStmt) error {
+	cli, err := getUserTimerClient(e.Ctx())
+	if err != nil {
+		return err
+	}
+
+	timer, err := getUserTimer(ctx, cli, s.Name)
+	if err != nil {
+		return err
+	}
+
+	exists := false
+	if timer != nil {
+		if exists, err = cli.DeleteTimer(ctx, timer.ID); err != nil {
+			return err
+		}
+	}
+
+	if !exists {
+		err = infoschema.ErrTimerNotExists.GenWithStackByArgs(s.Name.Name.O)
+		if s.IfExists {
+			e.Ctx().GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (e *SimpleExec) executeTimerAction(ctx context.Context, s *ast.TimerActionStmt) error {
+	cli, err := getUserTimerClient(e.Ctx())
+	if err != nil {
+		return err
+	}
+
+	timer, err := getUserTimer(ctx, cli, s.Name)
+	if err != nil {
+		return err
+	}
+
+	if timer == nil {
+		return infoschema.ErrTimerNotExists.GenWithStackByArgs(s.Name.Name.O)
+	}
+
+	return cli.UpdateTimer(ctx, timer.ID, timerapi.WithSetEnable(s.Tp == ast.TimerActionResume))
+}
+
+// fetchShowTimers fills the result with the schema:
+// {"Name", "Schedule_type", "Schedule", "Time_zone", "Enabled", "Watermark",
+// "Next_event_time", "Event_status", "Last_event_status", "Last_event_end_time", "Comment"}
+func (e *ShowExec) fetchShowTimers(ctx context.Context) error {
+	timers, err := listUserTimers(ctx, e.Ctx(), e.DBName.O)
+	if err != nil {
+		return err
+	}
+
+	for _, timer := range timers {
+		e.appendRow([]interface{}{
+			timer.data.Name,
+			string(timer.SchedPolicyType),
+			timer.SchedPolicyExpr,
+			timer.TimeZone,
+			timerEnabledValue(timer.Enable),
+			timerTimeValue(e.Ctx(), timer.Watermark),
+			timerNextEventTimeValue(e.Ctx(), timer),
+			string(timer.EventStatus),
+			timer.summary.LastEventStatus,
+			timerTimeValue(e.Ctx(), timer.summary.LastEventEnd),
+			timer.data.Comment,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/timer/usertimer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserTimerStmt(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+
+	tk.MustExec("create timer t1 schedule interval '1h' comment 'c1' do delete from t where a < 10")
+	tk.MustGetErrCode("create timer T1 schedule interval '1h' do delete from t", errno.ErrTimerExists)
+	tk.MustExec("create timer if not exists t1 schedule interval '1h' do delete from t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8263 Timer 't1' already exists"))
+	tk.MustExec("create timer test.t2 schedule cron '0 0 * * *' time zone 'Asia/Shanghai' disable do insert into t values (1)")
+	tk.MustGetErrMsg("create timer t3 do delete from t", "SCHEDULE should be specified for the timer")
+	tk.MustContainErrMsg("create timer t3 schedule interval 'abc' do delete from t", "invalid")
+	tk.MustContainErrMsg("create timer t3 schedule cron '1 2' do delete from t", "invalid cron expr")
+	tk.MustContainErrMsg("create timer t3 schedule interval '1h' time zone 'invalid' do delete from t", "invalid time zone")
+	tk.MustGetErrCode("create timer db_not_exist.t3 schedule interval '1h' do delete from t", errno.ErrBadDB)
+
+	tk.MustQuery("show timers").CheckAt([]int{0, 1, 2, 3, 4, 7, 8, 9, 10}, testkit.RowsWithSep("|",
+		"t1|INTERVAL|1h||YES|IDLE||<nil>|c1",
+		"t2|CRON|0 0 * * *|Asia/Shanghai|NO|IDLE||<nil>|",
+	))
+	tk.MustQuery("show timers like 't2'").CheckAt([]int{0, 1, 4}, testkit.Rows("t2 CRON NO"))
+	// the watermark is set when the timer is created, and only the enabled timer has the next event time
+	tk.MustQuery("show timers where Watermark is not null and Next_event_time is not null").CheckAt([]int{0}, testkit.Rows("t1"))
+	tk.MustQuery("select timer_schema, timer_name, schedule_policy_expr, enabled, sql_text, timer_comment from information_schema.timers").Check(testkit.Rows(
+		"test t1 1h YES DELETE FROM `t` WHERE `a`<10 c1",
+		"test t2 0 0 * * * NO INSERT INTO `t` VALUES (1) ",
+	))
+	tk.MustQuery("select count(*) from information_schema.timers where watermark is not null and create_time is not null").Check(testkit.Rows("2"))
+
+	// alter timer
+	tk.MustExec("alter timer t1 schedule cron '@daily' disable comment 'c2'")
+	tk.MustExec("alter timer t2 do delete from t")
+	tk.MustGetErrCode("alter timer t3 enable", errno.ErrTimerNotExists)
+	tk.MustExec("alter timer if exists t3 enable")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8264 Unknown timer 't3'"))
+	tk.MustContainErrMsg("alter timer t1 schedule interval 'abc'", "invalid")
+	tk.MustQuery("select timer_name, schedule_policy_type, schedule_policy_expr, enabled, sql_text, timer_comment from information_schema.timers").Check(testkit.Rows(
+		"t1 CRON @daily NO DELETE FROM `t` WHERE `a`<10 c2",
+		"t2 CRON 0 0 * * * NO DELETE FROM `t` ",
+	))
+
+	// pause/resume timer
+	tk.MustExec("resume timer t1")
+	tk.MustQuery("select timer_name, enabled from information_schema.timers").Check(testkit.Rows("t1 YES", "t2 NO"))
+	tk.MustExec("pause timer t1")
+	tk.MustQuery("select timer_name, enabled from information_schema.timers").Check(testkit.Rows("t1 NO", "t2 NO"))
+	tk.MustGetErrCode("resume timer t3", errno.ErrTimerNotExists)
+
+	// timers are in different schemas
+	tk.MustExec("create database test2")
+	tk.MustExec("create timer test2.t1 schedule interval '1h' do delete from t")
+	tk.MustQuery("show timers").CheckAt([]int{0}, testkit.Rows("t1", "t2"))
+	tk.MustQuery("show timers in test2").CheckAt([]int{0}, testkit.Rows("t1"))
+	tk.MustQuery("select timer_schema, timer_name from information_schema.timers").Check(testkit.Rows("test t1", "test t2", "test2 t1"))
+
+	// drop timer
+	tk.MustExec("drop timer t1")
+	tk.MustGetErrCode("drop timer t1", errno.ErrTimerNotExists)
+	tk.MustExec("drop timer if exists t1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8264 Unknown timer 't1'"))
+	tk.MustExec("drop timer test2.t1")
+	tk.MustQuery("select timer_schema, timer_name from information_schema.timers").Check(testkit.Rows("test t2"))
+}
+
+func TestUserTimerPrivilege(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create timer t1 schedule interval '1h' do delete from t")
+	tk.MustExec("create user u1")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil, nil))
+	errMsg := "[planner:1227]Access denied; you need (at least one of) the SUPER or TIMER_ADMIN privilege(s) for this operation"
+	tk1.MustGetErrMsg("create timer test.t2 schedule interval '1h' do delete from t", errMsg)
+	tk1.MustGetErrMsg("alter timer test.t1 disable", errMsg)
+	tk1.MustGetErrMsg("pause timer test.t1", errMsg)
+	tk1.MustGetErrMsg("resume timer test.t1", errMsg)
+	tk1.MustGetErrMsg("drop timer test.t1", errMsg)
+	tk1.MustGetErrMsg("show timers in test", errMsg)
+	tk1.MustQuery("select timer_name from information_schema.timers").Check(testkit.Rows())
+
+	tk.MustExec("grant TIMER_ADMIN on *.* to u1")
+	tk1.MustExec("alter timer test.t1 disable")
+	tk1.MustQuery("show timers in test").CheckAt([]int{0, 4}, testkit.Rows("t1 NO"))
+	tk1.MustQuery("select timer_name from information_schema.timers").Check(testkit.Rows("t1"))
+	tk1.MustExec("drop timer test.t1")
+}
+
+func TestUserTimerExecuteAsCreator(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert into t values (1), (2)")
+	tk.MustExec("create user u1")
+	tk.MustExec("grant TIMER_ADMIN on *.* to u1")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil, nil))
+	tk1.MustExec("create timer test.t1 schedule interval '1h' do delete from t where a = 1")
+	tk.MustQuery("select creator from information_schema.timers where timer_name = 't1'").Check(testkit.Rows("u1@%"))
+
+	exec := usertimer.NewSessionPoolSQLExecutor(dom.SysSessionPool(), dom.PrivilegeHandle)
+	data := &usertimer.TimerData{Schema: "test", Name: "t1", SQL: "DELETE FROM t WHERE a = 1", Creator: "u1@%"}
+	_, err := exec(context.Background(), data)
+	require.ErrorContains(t, err, "SELECT command denied to user 'u1'@'%' for table 't'")
+	tk.MustQuery("select a from t order by a").Check(testkit.Rows("1", "2"))
+
+	tk.MustExec("grant select, delete on test.t to u1")
+	rows, err := exec(context.Background(), data)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), rows)
+	tk.MustQuery("select a from t order by a").Check(testkit.Rows("2"))
+
+	// the session in the pool should not keep the privileges of the creator
+	tk.MustExec("revoke delete on test.t from u1")
+	_, err = exec(context.Background(), &usertimer.TimerData{Schema: "test", SQL: "DELETE FROM t", Creator: "root@%"})
+	require.NoError(t, err)
+	tk.MustQuery("select a from t").Check(testkit.Rows())
+
+	data.Creator = "u2@%"
+	_, err = exec(context.Background(), data)
+	require.EqualError(t, err, "the creator 'u2@%' of the timer does not exist")
+
+	// the timer altered with a new body is executed with the privileges of the user who alters it
+	tk.MustExec("create timer test.t2 schedule interval '1h' do delete from t")
+	tk.MustQuery("select creator from information_schema.timers where timer_name = 't2'").Check(testkit.Rows("root@%"))
+	tk1.MustExec("alter timer test.t2 disable")
+	tk.MustQuery("select creator from information_schema.timers where timer_name = 't2'").Check(testkit.Rows("root@%"))
+	tk1.MustExec("alter timer test.t2 do delete from mysql.user")
+	tk.MustQuery("select creator from information_schema.timers where timer_name = 't2'").Check(testkit.Rows("u1@%"))
+
+	// the timers can't be created without a user
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustGetErrMsg("create timer test.t3 schedule interval '1h' do delete from t", "the timer body can't be set without a user")
+}
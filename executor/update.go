@@ -67,6 +67,8 @@ type UpdateExec struct {
 	tableUpdatable []bool
 	changed        []bool
 	matches        []bool
+	// rowAccessChecks contains the row access policies checked against the updated rows. the map is tableID -> []*RowAccessCheck
+	rowAccessChecks map[int64][]*plannercore.RowAccessCheck
 	// fkChecks contains the foreign key checkers. the map is tableID -> []*FKCheckExec
 	fkChecks map[int64][]*FKCheckExec
 	// fkCascades contains the foreign key cascade. the map is tableID -> []*FKCascadeExec
@@ -194,6 +196,9 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		oldData := row[content.Start:content.End]
 		newTableData := newData[content.Start:content.End]
 		flags := bAssignFlag[content.Start:content.End]
+		if err := checkRowAccessPolicies(e.Ctx(), e.rowAccessChecks[content.TblID], tbl.Meta(), newTableData); err != nil {
+			return err
+		}
 
 		// Update row
 		fkChecks := e.fkChecks[content.TblID]
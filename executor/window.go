@@ -16,16 +16,23 @@ package executor
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/executor/internal/exec"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mathutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
 )
 
 // WindowExec is the executor for window functions.
@@ -41,31 +48,110 @@ type WindowExec struct {
 	resultChunks []*chunk.Chunk
 	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
 	remainingRowsInChunk []int
+	// memUsageOfChunks indicates the memory consumed by resultChunks[i].
+	memUsageOfChunks []int64
 
 	numWindowFuncs int
 	processor      windowProcessor
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+
+	// spillable indicates whether the partitions can be spilled to disk. It's true only when the window has no
+	// frame and all the window functions can be updated incrementally without keeping the rows of the partition,
+	// see windowSpillable.
+	spillable bool
+	// inSpillMode indicates whether WindowExec is asked to enter `spill mode` by the spill action.
+	// WindowExec enters spill mode when it meets a partition across chunks after that, and since then the
+	// partitions are processed one by one: the window functions are updated when the rows of a partition are
+	// fetched, and the rows are buffered in partitionRows, which is spilled to disk when the memory quota is
+	// exceeded again. The rows are returned with the final results after the whole partition is fetched.
+	inSpillMode uint32
+	// spillAction save the Action for spilling.
+	spillAction *WindowSpillDiskAction
+	// partitionRows stores the rows of the current partition in spill mode, it's nil before entering spill mode.
+	partitionRows *chunk.RowContainer
+	// partitionChk is the chunk to collect the rows before they're added to partitionRows.
+	partitionChk *chunk.Chunk
+	// partitionFetched indicates all the rows of the current partition are in partitionRows.
+	partitionFetched bool
+	// childDrained indicates the child executor is drained in spill mode.
+	childDrained bool
+	// spilledChk is the chunk of partitionRows being returned, and spilledChkIdx and spilledRowIdx indicate the
+	// next row to return.
+	spilledChk    *chunk.Chunk
+	spilledChkIdx int
+	spilledRowIdx int
+	childColIdxs  []int
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.BaseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.ID(), -1)
+	e.memTracker.AttachTo(e.Ctx().GetSessionVars().StmtCtx.MemTracker)
+	atomic.StoreUint32(&e.inSpillMode, 0)
+	e.partitionRows, e.partitionChk, e.spilledChk = nil, nil, nil
+	e.partitionFetched, e.childDrained = false, false
+	e.spilledChkIdx, e.spilledRowIdx = 0, 0
+	if e.spillable && variable.EnableTmpStorageOnOOM.Load() {
+		e.diskTracker = disk.NewTracker(e.ID(), -1)
+		e.diskTracker.AttachTo(e.Ctx().GetSessionVars().StmtCtx.DiskTracker)
+		e.Ctx().GetSessionVars().MemTracker.FallbackOldAndSetNewAction(e.ActionSpill())
+	}
+	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
-	return errors.Trace(e.BaseExecutor.Close())
+	var firstErr error
+	if e.partitionRows != nil {
+		e.partitionRows.ActionSpill().SetFinished()
+		firstErr = e.partitionRows.Close()
+		e.partitionRows = nil
+	}
+	if e.spillAction != nil {
+		e.spillAction.SetFinished()
+		e.spillAction = nil
+	}
+	e.resultChunks, e.remainingRowsInChunk, e.memUsageOfChunks = nil, nil, nil
+	e.partitionChk, e.spilledChk = nil, nil
+	if e.memTracker != nil {
+		e.memTracker.ReplaceBytesUsed(0)
+	}
+	if err := e.BaseExecutor.Close(); firstErr == nil {
+		firstErr = err
+	}
+	return errors.Trace(firstErr)
 }
 
 // Next implements the Executor Next interface.
 func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
-	for !e.executed && !e.preparedChunkAvailable() {
+	for !e.executed && e.partitionRows == nil && !e.preparedChunkAvailable() {
 		err := e.consumeOneGroup(ctx)
 		if err != nil {
 			e.executed = true
 			return err
 		}
 	}
+	// All the result chunks are prepared after entering spill mode, they're returned before the spilled rows.
 	if len(e.resultChunks) > 0 {
 		chk.SwapColumns(e.resultChunks[0])
+		e.memTracker.Consume(-e.memUsageOfChunks[0])
 		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
 		e.resultChunks = e.resultChunks[1:]
 		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
+		e.memUsageOfChunks = e.memUsageOfChunks[1:]
+		return nil
+	}
+	if e.partitionRows != nil && !e.executed {
+		if err := e.spilledNext(ctx, chk); err != nil {
+			e.executed = true
+			return err
+		}
 	}
 	return nil
 }
@@ -96,6 +182,15 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 	}
 
 	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
+		failpoint.Inject("triggerWindowSpill", func(val failpoint.Value) {
+			if val.(bool) && e.spillAction != nil {
+				atomic.StoreUint32(&e.inSpillMode, 1)
+			}
+		})
+		if atomic.LoadUint32(&e.inSpillMode) == 1 {
+			// The rest rows of the partition will be fetched in spill mode.
+			return e.enterSpillMode(groupRows)
+		}
 		meetLastGroup = false
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
@@ -168,8 +263,11 @@ func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
 	if err != nil {
 		return false, err
 	}
+	memUsage := childResult.MemoryUsage()
+	e.memTracker.Consume(memUsage)
 	e.resultChunks = append(e.resultChunks, resultChk)
 	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
+	e.memUsageOfChunks = append(e.memUsageOfChunks, memUsage)
 
 	e.childResult = childResult
 	return false, nil
@@ -185,6 +283,214 @@ func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
 	return nil
 }
 
+// enterSpillMode makes WindowExec process the partitions in spill mode. `groupRows` are the rows of the
+// current partition fetched so far, whose results are not prepared in resultChunks.
+func (e *WindowExec) enterSpillMode(groupRows []chunk.Row) error {
+	childTypes := retTypes(e.Children(0))
+	e.partitionRows = chunk.NewRowContainer(childTypes, e.MaxChunkSize())
+	e.partitionRows.GetMemTracker().AttachTo(e.memTracker)
+	e.partitionRows.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	e.partitionRows.GetDiskTracker().AttachTo(e.diskTracker)
+	e.partitionRows.GetDiskTracker().SetLabel(memory.LabelForRowChunks)
+	e.Ctx().GetSessionVars().MemTracker.FallbackOldAndSetNewAction(e.partitionRows.ActionSpill())
+	e.partitionChk = chunk.New(childTypes, e.InitCap(), e.MaxChunkSize())
+	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
+	e.childColIdxs = make([]int, 0, len(columns))
+	for _, col := range columns {
+		e.childColIdxs = append(e.childColIdxs, col.Index)
+	}
+	logutil.BgLogger().Info("window enters spill-mode", zap.Int("rowsInPartition", len(groupRows)))
+
+	for _, row := range groupRows {
+		if err := e.appendPartitionRow(row); err != nil {
+			return err
+		}
+	}
+	if _, err := e.processor.consumeGroupRows(e.Ctx(), groupRows); err != nil {
+		return errors.Trace(err)
+	}
+	// Remove the rows of the current partition from resultChunks, they'll be returned from partitionRows.
+	resultChunks := e.resultChunks[:0]
+	memUsageOfChunks := e.memUsageOfChunks[:0]
+	for i, chk := range e.resultChunks {
+		if e.remainingRowsInChunk[i] == 0 {
+			resultChunks = append(resultChunks, chk)
+			memUsageOfChunks = append(memUsageOfChunks, e.memUsageOfChunks[i])
+			continue
+		}
+		e.memTracker.Consume(-e.memUsageOfChunks[i])
+		// The results of the window functions are prepared for the rows of the previous partitions.
+		prepared := chk.NumRows() - e.remainingRowsInChunk[i]
+		if prepared == 0 {
+			continue
+		}
+		preparedChk := chunk.New(e.RetFieldTypes(), prepared, prepared)
+		preparedChk.Append(chk, 0, prepared)
+		memUsage := preparedChk.MemoryUsage()
+		e.memTracker.Consume(memUsage)
+		resultChunks = append(resultChunks, preparedChk)
+		memUsageOfChunks = append(memUsageOfChunks, memUsage)
+	}
+	e.resultChunks, e.memUsageOfChunks = resultChunks, memUsageOfChunks
+	e.remainingRowsInChunk = make([]int, len(resultChunks))
+	return nil
+}
+
+// spilledNext returns the rows of the partitions and the results of the window functions in spill mode.
+func (e *WindowExec) spilledNext(ctx context.Context, chk *chunk.Chunk) error {
+	for !chk.IsFull() {
+		if !e.partitionFetched {
+			if err := e.fetchSpilledPartition(ctx); err != nil {
+				return err
+			}
+		}
+		if e.spilledChkIdx >= e.partitionRows.NumChunks() {
+			// All the rows of the current partition are returned.
+			if e.childDrained {
+				e.executed = true
+				return nil
+			}
+			if err := e.resetSpilledPartition(); err != nil {
+				return err
+			}
+			continue
+		}
+		if e.spilledChk == nil {
+			var err error
+			e.spilledChk, err = e.partitionRows.GetChunk(e.spilledChkIdx)
+			if err != nil {
+				return err
+			}
+		}
+		for ; e.spilledRowIdx < e.spilledChk.NumRows() && !chk.IsFull(); e.spilledRowIdx++ {
+			chk.AppendPartialRowByColIdxs(0, e.spilledChk.GetRow(e.spilledRowIdx), e.childColIdxs)
+			if _, err := e.processor.appendResult2Chunk(e.Ctx(), nil, chk, 1); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if e.spilledRowIdx == e.spilledChk.NumRows() {
+			e.spilledChk, e.spilledChkIdx, e.spilledRowIdx = nil, e.spilledChkIdx+1, 0
+		}
+	}
+	return nil
+}
+
+// fetchSpilledPartition fetches the rest rows of the current partition in spill mode. The window functions are
+// updated with the rows incrementally, and the rows are buffered in partitionRows.
+func (e *WindowExec) fetchSpilledPartition(ctx context.Context) error {
+	failpoint.Inject("triggerWindowSpill", func(val failpoint.Value) {
+		if val.(bool) {
+			e.partitionRows.SpillToDisk()
+		}
+	})
+	numRows := e.partitionRows.NumRow() + e.partitionChk.NumRows()
+	var groupRows []chunk.Row
+	for {
+		if e.groupChecker.isExhausted() {
+			childResult := tryNewCacheChunk(e.Children(0))
+			if err := Next(ctx, e.Children(0), childResult); err != nil {
+				return errors.Trace(err)
+			}
+			if childResult.NumRows() == 0 {
+				e.childDrained = true
+				break
+			}
+			isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(childResult)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			e.childResult = childResult
+			if !isFirstGroupSameAsPrev && numRows > 0 {
+				// The current partition ends at the end of the previous chunk.
+				break
+			}
+		}
+		begin, end := e.groupChecker.getNextGroup()
+		groupRows = groupRows[:0]
+		for i := begin; i < end; i++ {
+			row := e.childResult.GetRow(i)
+			groupRows = append(groupRows, row)
+			if err := e.appendPartitionRow(row); err != nil {
+				return err
+			}
+		}
+		if _, err := e.processor.consumeGroupRows(e.Ctx(), groupRows); err != nil {
+			return errors.Trace(err)
+		}
+		numRows += end - begin
+		if end < e.childResult.NumRows() {
+			break
+		}
+	}
+	e.partitionFetched = true
+	return e.flushPartitionChk()
+}
+
+func (e *WindowExec) appendPartitionRow(row chunk.Row) error {
+	e.partitionChk.AppendRow(row)
+	if !e.partitionChk.IsFull() {
+		return nil
+	}
+	return e.flushPartitionChk()
+}
+
+func (e *WindowExec) flushPartitionChk() error {
+	if e.partitionChk.NumRows() == 0 {
+		return nil
+	}
+	if err := e.partitionRows.Add(e.partitionChk); err != nil {
+		return err
+	}
+	e.partitionChk = chunk.New(retTypes(e.Children(0)), e.InitCap(), e.MaxChunkSize())
+	return nil
+}
+
+func (e *WindowExec) resetSpilledPartition() error {
+	e.processor.resetPartialResult()
+	e.partitionFetched = false
+	e.spilledChk, e.spilledChkIdx, e.spilledRowIdx = nil, 0, 0
+	return e.partitionRows.Reset()
+}
+
+// ActionSpill returns a WindowSpillDiskAction for spilling the partitions of WindowExec.
+func (e *WindowExec) ActionSpill() *WindowSpillDiskAction {
+	if e.spillAction == nil {
+		e.spillAction = &WindowSpillDiskAction{
+			e: e,
+		}
+	}
+	return e.spillAction
+}
+
+// WindowSpillDiskAction implements memory.ActionOnExceed for WindowExec.
+// If the memory quota of a query is exceeded, WindowSpillDiskAction.Action is
+// triggered.
+type WindowSpillDiskAction struct {
+	memory.BaseOOMAction
+	e *WindowExec
+}
+
+// Action set WindowExec spill mode.
+func (a *WindowSpillDiskAction) Action(t *memory.Tracker) {
+	// Guarantee that the buffered data is at least 20% of the threshold, otherwise spilling the partitions can't help.
+	if atomic.LoadUint32(&a.e.inSpillMode) == 0 && a.e.memTracker.BytesConsumed() >= t.GetBytesLimit()/5 {
+		logutil.BgLogger().Info("memory exceeds quota, set window mode to spill-mode",
+			zap.Int64("consumed", t.BytesConsumed()),
+			zap.Int64("quota", t.GetBytesLimit()))
+		atomic.StoreUint32(&a.e.inSpillMode, 1)
+		memory.QueryForceDisk.Add(1)
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority get the priority of the Action
+func (*WindowSpillDiskAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
 // windowProcessor is the interface for processing different kinds of windows.
 type windowProcessor interface {
 	// consumeGroupRows updates the result for an window function using the input rows
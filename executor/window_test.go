@@ -16,9 +16,12 @@ package executor_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWindowFunctions(t *testing.T) {
@@ -498,3 +501,60 @@ func TestIssue29947(t *testing.T) {
 	result.Check(testkit.Rows("2", "3"))
 	tk.MustExec("commit")
 }
+
+func TestWindowInDisk(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	// The plans are built with different window concurrency, so they shouldn't be cached.
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 0")
+	tk.MustExec("create table t(a int, b int)")
+	values := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i%3, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	// Shuffle is used for the windows with the NDV of the partition columns from the statistics.
+	tk.MustExec("analyze table t")
+	queries := []string{
+		"select a, b, sum(b) over (partition by a), count(*) over (partition by a) from t",
+		"select a, b, avg(b) over (), max(b) over () from t",
+		"select a, sum(b) over (partition by a) as s from t where b < 100 or b > 900",
+		"select a, b, ntile(4) over (partition by a order by b), row_number() over (partition by a order by b) from t",
+		"select a, b, first_value(b) over w, last_value(b) over w, nth_value(b, 2) over w from t " +
+			"window w as (partition by a order by b rows between unbounded preceding and unbounded following)",
+		// The windows below are evaluated in memory.
+		"select a, b, rank() over (partition by a order by b), lead(b) over (partition by a order by b) from t",
+		"select a, b, sum(b) over (partition by a order by b rows 2 preceding) from t",
+	}
+	expected := make([][][]interface{}, 0, len(queries))
+	for _, query := range queries {
+		expected = append(expected, tk.MustQuery(query).Sort().Rows())
+	}
+
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/executor/triggerWindowSpill", "return(true)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/executor/triggerWindowSpill"))
+	}()
+	for _, concurrency := range []int{1, 4} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_window_concurrency = %d", concurrency))
+		for i, query := range queries {
+			tk.MustQuery(query).Sort().Check(expected[i])
+		}
+	}
+	checkSpilled := func(query string) {
+		rows := tk.MustQuery("explain analyze " + query).Rows()
+		for _, row := range rows {
+			if strings.Contains(fmt.Sprintf("%v", row[0]), "Window") {
+				require.NotEqual(t, "0 Bytes", fmt.Sprintf("%v", row[len(row)-1]))
+			}
+		}
+	}
+	// The windows are spilled in the workers of Shuffle.
+	require.Contains(t, fmt.Sprintf("%v", tk.MustQuery("explain "+queries[4]).Rows()), "Shuffle")
+	checkSpilled(queries[4])
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	checkSpilled(queries[1])
+	checkSpilled(queries[4])
+}
@@ -26,13 +26,17 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/dbterror/exeerrors"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/tracing"
 )
@@ -326,3 +330,22 @@ func resetErrDataTooLong(colName string, rowIdx int, err error) error {
 	newErr := types.ErrDataTooLong.GenWithStack("Data too long for column '%v' at row %v", colName, rowIdx)
 	return newErr
 }
+
+// checkRowAccessPolicies checks whether the row written to the table satisfies the row access policies,
+// the row is rejected if any of the policies is evaluated to false or NULL.
+func checkRowAccessPolicies(sctx sessionctx.Context, checks []*plannercore.RowAccessCheck, tblInfo *model.TableInfo, row []types.Datum) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	r := chunk.MutRowFromDatums(row).ToRow()
+	for _, check := range checks {
+		ok, _, err := expression.EvalBool(sctx, expression.CNFExprs{check.Expr}, r)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return exeerrors.ErrRowAccessPolicyViolation.GenWithStackByArgs(check.PolicyName.O, tblInfo.Name.O)
+		}
+	}
+	return nil
+}
@@ -751,6 +751,7 @@ var funcs = map[string]functionClass{
 	ast.CurrentRole:          &currentRoleFunctionClass{baseFunctionClass{ast.CurrentRole, 0, 0}},
 	ast.Database:             &databaseFunctionClass{baseFunctionClass{ast.Database, 0, 0}},
 	ast.CurrentResourceGroup: &currentResourceGroupFunctionClass{baseFunctionClass{ast.CurrentResourceGroup, 0, 0}},
+	ast.CurrentTenant:        &currentTenantFunctionClass{baseFunctionClass{ast.CurrentTenant, 0, 0}},
 
 	// This function is a synonym for DATABASE().
 	// See http://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_schema
@@ -283,6 +283,45 @@ func (b *builtinCurrentResourceGroupSig) evalString(row chunk.Row) (res string,
 	return data.ResourceGroupName, false, nil
 }
 
+type currentTenantFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentTenantFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.SetFlen(64)
+	sig := &builtinCurrentTenantSig{bf}
+	return sig, nil
+}
+
+type builtinCurrentTenantSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentTenantSig) Clone() builtinFunc {
+	newSig := &builtinCurrentTenantSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinCurrentTenantSig, it returns NULL if the current user doesn't belong to any tenant.
+func (b *builtinCurrentTenantSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	data := b.ctx.GetSessionVars()
+	if data == nil {
+		return "", true, errors.Errorf("Missing session variable when eval builtin")
+	}
+	if data.TenantName == "" {
+		return "", true, nil
+	}
+	return data.TenantName, false, nil
+}
+
 type userFunctionClass struct {
 	baseFunctionClass
 }
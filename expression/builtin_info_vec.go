@@ -136,6 +136,27 @@ func (b *builtinCurrentResourceGroupSig) vecEvalString(input *chunk.Chunk, resul
 	return nil
 }
 
+func (b *builtinCurrentTenantSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCurrentTenantSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	data := b.ctx.GetSessionVars()
+	if data == nil {
+		return errors.Errorf("Missing session variable when eval builtin")
+	}
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if data.TenantName == "" {
+			result.AppendNull()
+		} else {
+			result.AppendString(data.TenantName)
+		}
+	}
+	return nil
+}
+
 func (b *builtinCurrentRoleSig) vectorized() bool {
 	return true
 }
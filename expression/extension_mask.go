@@ -0,0 +1,160 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/extension"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// ExtensionMaskFuncName is the name of the function masking a column by the mask functions of the session extensions.
+// Its arguments are the masked expression and the names of the database, the table and the column, so it can be
+// rebuilt by the name like the other functions. It's only evaluated in TiDB, it's never pushed down.
+const ExtensionMaskFuncName = "extension_mask"
+
+// BuildExtensionMaskFunction masks `arg` reading the column `db`.`tbl`.`col` by the mask functions of the session
+// extensions. The names are in lower case. `arg` is returned if the extensions don't mask the column.
+func BuildExtensionMaskFunction(ctx sessionctx.Context, arg Expression, db, tbl, col string) (Expression, error) {
+	args := []Expression{arg}
+	for _, name := range []string{db, tbl, col} {
+		args = append(args, DatumToConstant(types.NewStringDatum(name), mysql.TypeVarString, 0))
+	}
+	return buildExtensionMaskFunction(ctx, args)
+}
+
+func buildExtensionMaskFunction(ctx sessionctx.Context, args []Expression) (Expression, error) {
+	if len(args) != 4 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(ExtensionMaskFuncName)
+	}
+	names := make([]string, 0, 3)
+	for _, arg := range args[1:] {
+		c, ok := arg.(*Constant)
+		if !ok || c.DeferredExpr != nil || c.ParamMarker != nil {
+			return nil, errors.Errorf("the names of the column masked by %s should be constants", ExtensionMaskFuncName)
+		}
+		names = append(names, c.Value.GetString())
+	}
+	tp := args[0].GetType().Clone()
+	masks := ctx.GetExtensions().ResultMaskFuncs(extension.NewSessionInfo(ctx.GetSessionVars()), []*extension.ResultColumn{{
+		DBName:     names[0],
+		TableName:  names[1],
+		ColumnName: names[2],
+		Type:       tp,
+	}})
+	if masks == nil {
+		return args[0], nil
+	}
+	bf, err := newBaseBuiltinFuncWithFieldType(ctx, tp, args)
+	if err != nil {
+		return nil, err
+	}
+	// The masked values keep the collation of the column, the names don't take part in the derivation.
+	bf.SetCoercibility(args[0].Coercibility())
+	bf.SetRepertoire(args[0].Repertoire())
+	return &ScalarFunction{
+		FuncName: model.NewCIStr(ExtensionMaskFuncName),
+		RetType:  tp,
+		Function: &builtinExtensionMaskSig{baseBuiltinFunc: bf, mask: masks[0]},
+	}, nil
+}
+
+type builtinExtensionMaskSig struct {
+	baseBuiltinFunc
+	mask extension.MaskFunc
+}
+
+func (b *builtinExtensionMaskSig) Clone() builtinFunc {
+	newSig := &builtinExtensionMaskSig{mask: b.mask}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalMasked evaluates the argument and masks it, a null value is also passed to the mask function.
+func (b *builtinExtensionMaskSig) evalMasked(row chunk.Row) (types.Datum, error) {
+	d, err := b.args[0].Eval(row)
+	if err != nil {
+		return d, err
+	}
+	return b.mask(d), nil
+}
+
+func (b *builtinExtensionMaskSig) evalInt(row chunk.Row) (int64, bool, error) {
+	d, err := b.evalMasked(row)
+	if err != nil || d.IsNull() {
+		return 0, true, err
+	}
+	switch d.Kind() {
+	case types.KindInt64, types.KindUint64:
+		return d.GetInt64(), false, nil
+	}
+	v, err := d.ToInt64(b.ctx.GetSessionVars().StmtCtx)
+	return v, err != nil, err
+}
+
+func (b *builtinExtensionMaskSig) evalReal(row chunk.Row) (float64, bool, error) {
+	d, err := b.evalMasked(row)
+	if err != nil || d.IsNull() {
+		return 0, true, err
+	}
+	v, err := d.ToFloat64(b.ctx.GetSessionVars().StmtCtx)
+	return v, err != nil, err
+}
+
+func (b *builtinExtensionMaskSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	d, err := b.evalMasked(row)
+	if err != nil || d.IsNull() {
+		return nil, true, err
+	}
+	v, err := d.ToDecimal(b.ctx.GetSessionVars().StmtCtx)
+	return v, err != nil, err
+}
+
+func (b *builtinExtensionMaskSig) evalString(row chunk.Row) (string, bool, error) {
+	d, err := b.evalMasked(row)
+	if err != nil || d.IsNull() {
+		return "", true, err
+	}
+	v, err := d.ToString()
+	return v, err != nil, err
+}
+
+func (b *builtinExtensionMaskSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	d, err := b.evalMasked(row)
+	if err != nil || d.IsNull() {
+		return types.ZeroTime, true, err
+	}
+	return d.GetMysqlTime(), false, nil
+}
+
+func (b *builtinExtensionMaskSig) evalDuration(row chunk.Row) (types.Duration, bool, error) {
+	d, err := b.evalMasked(row)
+	if err != nil || d.IsNull() {
+		return types.Duration{}, true, err
+	}
+	return d.GetMysqlDuration(), false, nil
+}
+
+func (b *builtinExtensionMaskSig) evalJSON(row chunk.Row) (types.BinaryJSON, bool, error) {
+	d, err := b.evalMasked(row)
+	if err != nil || d.IsNull() {
+		return types.BinaryJSON{}, true, err
+	}
+	return d.GetMysqlJSON(), false, nil
+}
@@ -25,6 +25,7 @@ var UnCacheableFunctions = map[string]struct{}{
 	ast.CurrentUser:          {},
 	ast.CurrentRole:          {},
 	ast.CurrentResourceGroup: {},
+	ast.CurrentTenant:        {},
 	ast.User:                 {},
 	ast.ConnectionID:         {},
 	ast.LastInsertId:         {},
@@ -108,6 +109,7 @@ var IllegalFunctions4GeneratedColumns = map[string]struct{}{
 	ast.UTCTimestamp:     {},
 	ast.Benchmark:        {},
 	ast.CurrentUser:      {},
+	ast.CurrentTenant:    {},
 	ast.Database:         {},
 	ast.FoundRows:        {},
 	ast.GetLock:          {},
@@ -1160,6 +1160,12 @@ func CheckFuncInExpr(e Expression, funcName string) bool {
 	return false
 }
 
+// IsMutableEffectsFunction checks if the function is mutable or has side effects.
+func IsMutableEffectsFunction(name string) bool {
+	_, ok := mutableEffectsFunctions[name]
+	return ok
+}
+
 // IsMutableEffectsExpr checks if expr contains function which is mutable or has side effects.
 func IsMutableEffectsExpr(expr Expression) bool {
 	switch x := expr.(type) {
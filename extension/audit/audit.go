@@ -0,0 +1,426 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/extension"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+const (
+	// ExtensionName is the name of the audit log extension
+	ExtensionName = "audit"
+	// PrivAuditAdmin is the dynamic privilege to manage the audit log
+	PrivAuditAdmin = "AUDIT_ADMIN"
+)
+
+// The system variables of the audit log, all of them are GLOBAL.
+const (
+	// TiDBAuditEnabled indicates whether the audit log is enabled
+	TiDBAuditEnabled = "tidb_audit_enabled"
+	// TiDBAuditLog is the file of the audit log, a relative path is relative to the directory of the TiDB log file
+	TiDBAuditLog = "tidb_audit_log"
+	// TiDBAuditLogMaxSize is the max size in MB of the audit log file before it's rotated
+	TiDBAuditLogMaxSize = "tidb_audit_log_max_size"
+	// TiDBAuditLogMaxDays is the max days to retain the rotated audit log files, 0 means no limit
+	TiDBAuditLogMaxDays = "tidb_audit_log_max_days"
+	// TiDBAuditLogMaxBackups is the max number of the rotated audit log files to retain, 0 means no limit
+	TiDBAuditLogMaxBackups = "tidb_audit_log_max_backups"
+	// TiDBAuditLogRedact indicates whether the literals in the statements are replaced by '?'
+	TiDBAuditLogRedact = "tidb_audit_log_redact"
+	// TiDBAuditLogFilters is the JSON array of the filters, an event is logged if it matches any enabled filter
+	TiDBAuditLogFilters = "tidb_audit_log_filters"
+)
+
+// Default values of the system variables of the audit log.
+const (
+	DefTiDBAuditEnabled       = false
+	DefTiDBAuditLog           = "tidb-audit.log"
+	DefTiDBAuditLogMaxSize    = 100
+	DefTiDBAuditLogMaxDays    = 0
+	DefTiDBAuditLogMaxBackups = 10
+	DefTiDBAuditLogRedact     = true
+	DefTiDBAuditLogFilters    = `[{"name":"all"}]`
+)
+
+// The functions to enable or disable a filter on the current TiDB instance at runtime.
+// The filter is enabled again when it's recreated with the system variable.
+const (
+	// FuncEnableFilter enables the filter, e.g. `SELECT audit_log_enable_filter('filter_name')`
+	FuncEnableFilter = "audit_log_enable_filter"
+	// FuncDisableFilter disables the filter, e.g. `SELECT audit_log_disable_filter('filter_name')`
+	FuncDisableFilter = "audit_log_disable_filter"
+)
+
+func init() {
+	terror.MustNil(Register())
+}
+
+// Register registers the audit log extension
+func Register() error {
+	return extension.RegisterFactory(ExtensionName, func() ([]extension.Option, error) {
+		a := newAuditLog()
+		return []extension.Option{
+			extension.WithCustomDynPrivs([]string{PrivAuditAdmin}),
+			extension.WithCustomSysVariables(a.sysVars()),
+			extension.WithCustomFunctions(a.funcs()),
+			extension.WithSessionHandlerFactory(a.sessionHandler),
+			extension.WithClose(a.close),
+		}, nil
+	})
+}
+
+// filterSet is the filters and the names of the disabled filters, it's immutable once created
+type filterSet struct {
+	filters  []*Filter
+	disabled map[string]struct{}
+}
+
+func (s *filterSet) match(e *event) bool {
+	for _, f := range s.filters {
+		if _, ok := s.disabled[f.Name]; !ok && f.match(e) {
+			return true
+		}
+	}
+	return false
+}
+
+type auditLog struct {
+	enabled atomic.Bool
+	redact  atomic.Bool
+	filters atomic.Pointer[filterSet]
+	writer  atomic.Pointer[fileWriter]
+
+	// mu protects the updates of the filters, the file config and the writer
+	mu      sync.Mutex
+	fileCfg fileConfig
+}
+
+func newAuditLog() *auditLog {
+	a := &auditLog{
+		fileCfg: fileConfig{
+			filename:   DefTiDBAuditLog,
+			maxSize:    DefTiDBAuditLogMaxSize,
+			maxDays:    DefTiDBAuditLogMaxDays,
+			maxBackups: DefTiDBAuditLogMaxBackups,
+		},
+	}
+	a.redact.Store(DefTiDBAuditLogRedact)
+	filters, err := parseFilters(DefTiDBAuditLogFilters)
+	terror.MustNil(err)
+	a.filters.Store(&filterSet{filters: filters})
+	return a
+}
+
+func requireAuditAdmin(bool, bool) []string {
+	return []string{PrivAuditAdmin}
+}
+
+func (a *auditLog) sysVars() []*variable.SysVar {
+	setFileConfig := func(update func(c *fileConfig)) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		c := a.fileCfg
+		update(&c)
+		if c == a.fileCfg {
+			return
+		}
+		a.fileCfg = c
+		if a.enabled.Load() {
+			a.resetWriterWithLock()
+		}
+	}
+	setInt := func(update func(c *fileConfig, v int)) func(context.Context, *variable.SessionVars, string) error {
+		return func(_ context.Context, _ *variable.SessionVars, val string) error {
+			v, err := strconv.Atoi(val)
+			if err != nil {
+				return err
+			}
+			setFileConfig(func(c *fileConfig) { update(c, v) })
+			return nil
+		}
+	}
+
+	return []*variable.SysVar{
+		{
+			Scope: variable.ScopeGlobal, Name: TiDBAuditEnabled, Value: variable.BoolToOnOff(DefTiDBAuditEnabled), Type: variable.TypeBool,
+			RequireDynamicPrivileges: requireAuditAdmin,
+			SetGlobal: func(_ context.Context, _ *variable.SessionVars, val string) error {
+				a.mu.Lock()
+				defer a.mu.Unlock()
+				enabled := variable.TiDBOptOn(val)
+				if enabled == a.enabled.Load() {
+					return nil
+				}
+				a.enabled.Store(enabled)
+				if enabled {
+					a.resetWriterWithLock()
+				} else if w := a.writer.Swap(nil); w != nil {
+					w.close()
+				}
+				return nil
+			},
+		},
+		{
+			Scope: variable.ScopeGlobal, Name: TiDBAuditLog, Value: DefTiDBAuditLog, Type: variable.TypeStr,
+			RequireDynamicPrivileges: requireAuditAdmin,
+			Validation: func(_ *variable.SessionVars, normalizedValue string, _ string, _ variable.ScopeFlag) (string, error) {
+				if normalizedValue == "" {
+					return "", errors.Errorf("%s should not be empty", TiDBAuditLog)
+				}
+				return normalizedValue, nil
+			},
+			SetGlobal: func(_ context.Context, _ *variable.SessionVars, val string) error {
+				setFileConfig(func(c *fileConfig) { c.filename = val })
+				return nil
+			},
+		},
+		{
+			Scope: variable.ScopeGlobal, Name: TiDBAuditLogMaxSize, Value: strconv.Itoa(DefTiDBAuditLogMaxSize), Type: variable.TypeUnsigned,
+			MinValue: 1, MaxValue: 10240,
+			RequireDynamicPrivileges: requireAuditAdmin,
+			SetGlobal:                setInt(func(c *fileConfig, v int) { c.maxSize = v }),
+		},
+		{
+			Scope: variable.ScopeGlobal, Name: TiDBAuditLogMaxDays, Value: strconv.Itoa(DefTiDBAuditLogMaxDays), Type: variable.TypeUnsigned,
+			MinValue: 0, MaxValue: 3650,
+			RequireDynamicPrivileges: requireAuditAdmin,
+			SetGlobal:                setInt(func(c *fileConfig, v int) { c.maxDays = v }),
+		},
+		{
+			Scope: variable.ScopeGlobal, Name: TiDBAuditLogMaxBackups, Value: strconv.Itoa(DefTiDBAuditLogMaxBackups), Type: variable.TypeUnsigned,
+			MinValue: 0, MaxValue: 10000,
+			RequireDynamicPrivileges: requireAuditAdmin,
+			SetGlobal:                setInt(func(c *fileConfig, v int) { c.maxBackups = v }),
+		},
+		{
+			Scope: variable.ScopeGlobal, Name: TiDBAuditLogRedact, Value: variable.BoolToOnOff(DefTiDBAuditLogRedact), Type: variable.TypeBool,
+			RequireDynamicPrivileges: requireAuditAdmin,
+			SetGlobal: func(_ context.Context, _ *variable.SessionVars, val string) error {
+				a.redact.Store(variable.TiDBOptOn(val))
+				return nil
+			},
+		},
+		{
+			Scope: variable.ScopeGlobal, Name: TiDBAuditLogFilters, Value: DefTiDBAuditLogFilters, Type: variable.TypeStr,
+			RequireDynamicPrivileges: requireAuditAdmin,
+			Validation: func(_ *variable.SessionVars, normalizedValue string, _ string, _ variable.ScopeFlag) (string, error) {
+				_, err := parseFilters(normalizedValue)
+				return normalizedValue, err
+			},
+			SetGlobal: func(_ context.Context, _ *variable.SessionVars, val string) error {
+				filters, err := parseFilters(val)
+				if err != nil {
+					return err
+				}
+				a.mu.Lock()
+				defer a.mu.Unlock()
+				// Keep the filters disabled at runtime disabled.
+				a.filters.Store(&filterSet{filters: filters, disabled: a.filters.Load().disabled})
+				return nil
+			},
+		},
+	}
+}
+
+// resetWriterWithLock replaces the writer with a new one using the current file config, a.mu should be held
+func (a *auditLog) resetWriterWithLock() {
+	if w := a.writer.Swap(newFileWriter(a.fileCfg)); w != nil {
+		w.close()
+	}
+}
+
+func (a *auditLog) funcs() []*extension.FunctionDef {
+	newFunc := func(name string, disable bool) *extension.FunctionDef {
+		return &extension.FunctionDef{
+			Name:   name,
+			EvalTp: types.ETInt,
+			ArgTps: []types.EvalType{types.ETString},
+			EvalIntFunc: func(ctx extension.FunctionContext, row chunk.Row) (int64, bool, error) {
+				args, err := ctx.EvalArgs(row)
+				if err != nil {
+					return 0, false, err
+				}
+				if args[0].IsNull() {
+					return 0, true, nil
+				}
+				if err = a.setFilterDisabled(args[0].GetString(), disable); err != nil {
+					return 0, false, err
+				}
+				return 1, false, nil
+			},
+			RequireDynamicPrivileges: func(bool) []string {
+				return []string{PrivAuditAdmin}
+			},
+		}
+	}
+	return []*extension.FunctionDef{
+		newFunc(FuncEnableFilter, false),
+		newFunc(FuncDisableFilter, true),
+	}
+}
+
+func (a *auditLog) setFilterDisabled(name string, disable bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	old := a.filters.Load()
+	found := false
+	for _, f := range old.filters {
+		if f.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.Errorf("unknown audit log filter '%s'", name)
+	}
+
+	disabled := make(map[string]struct{}, len(old.disabled)+1)
+	for n := range old.disabled {
+		disabled[n] = struct{}{}
+	}
+	if disable {
+		disabled[name] = struct{}{}
+	} else {
+		delete(disabled, name)
+	}
+	a.filters.Store(&filterSet{filters: old.filters, disabled: disabled})
+	return nil
+}
+
+func (a *auditLog) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enabled.Store(false)
+	if w := a.writer.Swap(nil); w != nil {
+		w.close()
+	}
+}
+
+func (a *auditLog) sessionHandler() *extension.SessionHandler {
+	return &extension.SessionHandler{
+		OnConnectionEvent: a.onConnectionEvent,
+		OnStmtEvent:       a.onStmtEvent,
+	}
+}
+
+var connEventNames = map[extension.ConnEventTp]string{
+	extension.ConnHandshakeAccepted: "CONNECT",
+	extension.ConnHandshakeRejected: "CONNECT",
+	extension.ConnReset:             "RESET",
+	extension.ConnDisconnected:      "DISCONNECT",
+}
+
+func (a *auditLog) onConnectionEvent(tp extension.ConnEventTp, info *extension.ConnEventInfo) {
+	name, ok := connEventNames[tp]
+	if !ok || !a.enabled.Load() || info.ConnectionInfo == nil {
+		return
+	}
+
+	// The user account is unknown before the handshake, so the user in the handshake response is used.
+	e := &event{user: info.User, db: info.DB, class: ClassConnection}
+	if !a.filters.Load().match(e) {
+		return
+	}
+
+	r := &record{
+		Class:        ClassConnection,
+		Event:        name,
+		ConnectionID: info.ConnectionID,
+		User:         info.User,
+		ClientIP:     info.ClientIP,
+		DB:           info.DB,
+	}
+	a.write(r, info.Error)
+}
+
+func (a *auditLog) onStmtEvent(_ extension.StmtEventTp, info extension.StmtEventInfo) {
+	if !a.enabled.Load() {
+		return
+	}
+
+	stmt := info.StmtNode()
+	if prepared := info.ExecutePreparedStmt(); prepared != nil {
+		stmt = prepared
+	}
+	e := &event{db: info.CurrentDB(), tables: info.RelatedTables(), class: stmtClass(stmt)}
+	if user := info.User(); user != nil {
+		e.user, e.host = user.AuthUsername, user.AuthHostname
+	}
+	if !a.filters.Load().match(e) {
+		return
+	}
+
+	r := &record{
+		Class:        e.class,
+		Event:        "QUERY",
+		User:         e.user,
+		Host:         e.host,
+		DB:           e.db,
+		AffectedRows: info.AffectedRows(),
+	}
+	if connInfo := info.ConnectionInfo(); connInfo != nil {
+		r.ConnectionID = connInfo.ConnectionID
+		r.ClientIP = connInfo.ClientIP
+	}
+	for _, tbl := range e.tables {
+		r.Tables = append(r.Tables, tbl.DB+"."+tbl.Table)
+	}
+	if a.redact.Load() {
+		if stmt != nil {
+			r.SQL, _ = info.SQLDigest()
+		} else {
+			// The statement is failed to parse, so the text is not normalized yet.
+			r.SQL = parser.Normalize(info.OriginalText())
+		}
+	} else {
+		r.SQL = info.OriginalText()
+		if info.ExecuteStmtNode() != nil {
+			for _, param := range info.PreparedParams() {
+				s, err := param.ToString()
+				if err != nil {
+					s = param.String()
+				}
+				r.Params = append(r.Params, s)
+			}
+		}
+	}
+	a.write(r, info.GetError())
+}
+
+func (a *auditLog) write(r *record, err error) {
+	w := a.writer.Load()
+	if w == nil {
+		return
+	}
+	r.Time = time.Now().Format(time.RFC3339Nano)
+	r.Status = "SUCCESS"
+	if err != nil {
+		r.Status = "FAILED"
+		r.Error = err.Error()
+	}
+	w.write(r)
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tidb/extension"
+	"github.com/pingcap/tidb/extension/audit"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/server"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+type auditRecord struct {
+	Class        string   `json:"class"`
+	Event        string   `json:"event"`
+	ConnectionID uint64   `json:"conn_id"`
+	User         string   `json:"user"`
+	DB           string   `json:"db"`
+	Tables       []string `json:"tables"`
+	SQL          string   `json:"sql"`
+	Status       string   `json:"status"`
+	Error        string   `json:"error"`
+}
+
+func readAuditLog(t *testing.T, file string) []auditRecord {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r auditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r), scanner.Text())
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func loggedSQLs(records []auditRecord) []string {
+	sqls := make([]string, 0, len(records))
+	for _, r := range records {
+		sqls = append(sqls, r.SQL)
+	}
+	return sqls
+}
+
+func setupAuditLog(t *testing.T) (server.MockConn, *server.Server, string) {
+	extension.Reset()
+	require.NoError(t, audit.Register())
+	require.NoError(t, extension.Setup())
+	t.Cleanup(extension.Reset)
+
+	store := testkit.CreateMockStore(t)
+	serv := server.CreateMockServer(t, store)
+	t.Cleanup(serv.Close)
+	conn := server.CreateMockConn(t, serv)
+	t.Cleanup(conn.Close)
+
+	file := filepath.Join(t.TempDir(), "audit.log")
+	ctx := context.Background()
+	require.NoError(t, conn.HandleQuery(ctx, fmt.Sprintf("set global tidb_audit_log = '%s'", file)))
+	require.NoError(t, conn.HandleQuery(ctx, "use test"))
+	require.NoError(t, conn.HandleQuery(ctx, "create table t1(id int primary key, name varchar(32))"))
+	require.NoError(t, conn.HandleQuery(ctx, "create table t2(id int primary key)"))
+	require.NoError(t, conn.HandleQuery(ctx, "set global tidb_audit_enabled = ON"))
+	return conn, serv, file
+}
+
+func TestAuditLog(t *testing.T) {
+	conn, _, file := setupAuditLog(t)
+	ctx := context.Background()
+
+	// The statements before enabling the audit log are not logged.
+	records := readAuditLog(t, file)
+	require.Equal(t, []string{"set global `tidb_audit_enabled` = on"}, loggedSQLs(records))
+	require.Equal(t, "OTHER", records[0].Class)
+
+	require.NoError(t, conn.HandleQuery(ctx, "insert into t1 values (1, 'secret')"))
+	require.Error(t, conn.HandleQuery(ctx, "insert into t1 values (1, 'secret')"))
+	require.NoError(t, conn.HandleQuery(ctx, "select * from t1 where name = 'secret'"))
+	records = readAuditLog(t, file)[1:]
+	require.Len(t, records, 3)
+	for _, r := range records {
+		require.NotContains(t, r.SQL, "secret")
+		require.Equal(t, "root", r.User)
+		require.Equal(t, "test", r.DB)
+		require.Equal(t, []string{"test.t1"}, r.Tables)
+		require.Equal(t, conn.ID(), r.ConnectionID)
+	}
+	require.Equal(t, []string{
+		"insert into `t1` values ( ... )",
+		"insert into `t1` values ( ... )",
+		"select * from `t1` where `name` = ?",
+	}, loggedSQLs(records))
+	require.Equal(t, "DML", records[0].Class)
+	require.Equal(t, "SUCCESS", records[0].Status)
+	require.Equal(t, "FAILED", records[1].Status)
+	require.Contains(t, records[1].Error, "Duplicate entry")
+	require.Equal(t, "QUERY", records[2].Class)
+
+	// The original statements are logged without redaction.
+	require.NoError(t, conn.HandleQuery(ctx, "set global tidb_audit_log_redact = OFF"))
+	require.NoError(t, conn.HandleQuery(ctx, "select * from t1 where name = 'secret'"))
+	records = readAuditLog(t, file)
+	require.Equal(t, "select * from t1 where name = 'secret'", records[len(records)-1].SQL)
+
+	// The audit log is written to the new file.
+	newFile := filepath.Join(filepath.Dir(file), "audit2.log")
+	require.NoError(t, conn.HandleQuery(ctx, fmt.Sprintf("set global tidb_audit_log = '%s'", newFile)))
+	require.NoError(t, conn.HandleQuery(ctx, "select * from t2"))
+	require.Equal(t, []string{
+		fmt.Sprintf("set global tidb_audit_log = '%s'", newFile),
+		"select * from t2",
+	}, loggedSQLs(readAuditLog(t, newFile)))
+
+	// Nothing is logged after disabling the audit log.
+	require.NoError(t, conn.HandleQuery(ctx, "set global tidb_audit_enabled = OFF"))
+	require.NoError(t, conn.HandleQuery(ctx, "select * from t2"))
+	require.Len(t, readAuditLog(t, newFile), 2)
+}
+
+func TestAuditLogFilters(t *testing.T) {
+	conn, _, file := setupAuditLog(t)
+	ctx := context.Background()
+
+	require.NoError(t, conn.HandleQuery(ctx, `set global tidb_audit_log_filters = '[{"name":"ddl","classes":["ddl"]},{"name":"t1","tables":["test.t1"],"classes":["query","dml"]}]'`))
+	require.NoError(t, conn.HandleQuery(ctx, "select * from t1"))
+	require.NoError(t, conn.HandleQuery(ctx, "select * from t2"))
+	require.NoError(t, conn.HandleQuery(ctx, "insert into t1 values (1, 'a')"))
+	require.NoError(t, conn.HandleQuery(ctx, "insert into t2 values (1)"))
+	require.NoError(t, conn.HandleQuery(ctx, "create table t3(id int)"))
+	require.NoError(t, conn.HandleQuery(ctx, "show tables"))
+	require.Equal(t, []string{
+		"select * from `t1`",
+		"insert into `t1` values ( ... )",
+		"create table `t3` ( `id` int )",
+	}, loggedSQLs(readAuditLog(t, file))[1:])
+
+	// The filters are disabled and enabled at runtime.
+	require.NoError(t, conn.HandleQuery(ctx, "select audit_log_disable_filter('ddl')"))
+	require.NoError(t, conn.HandleQuery(ctx, "create table t4(id int)"))
+	require.NoError(t, conn.HandleQuery(ctx, "select audit_log_enable_filter('ddl')"))
+	require.NoError(t, conn.HandleQuery(ctx, "create table t5(id int)"))
+	require.EqualError(t, conn.HandleQuery(ctx, "select audit_log_disable_filter('unknown')"), "unknown audit log filter 'unknown'")
+	records := readAuditLog(t, file)
+	require.Equal(t, "create table `t5` ( `id` int )", records[len(records)-1].SQL)
+	require.NotContains(t, loggedSQLs(records), "create table `t4` ( `id` int )")
+
+	// The filters are filtered by the users.
+	require.NoError(t, conn.HandleQuery(ctx, `set global tidb_audit_log_filters = '[{"name":"u1","users":["u1"]}]'`))
+	require.NoError(t, conn.HandleQuery(ctx, "select * from t1"))
+	require.Len(t, readAuditLog(t, file), len(records))
+
+	// Invalid filters are rejected.
+	require.ErrorContains(t, conn.HandleQuery(ctx, `set global tidb_audit_log_filters = '[{"name":"a","classes":["unknown"]}]'`), "unknown class 'unknown' in audit log filter 'a'")
+	require.ErrorContains(t, conn.HandleQuery(ctx, `set global tidb_audit_log_filters = '[{"name":"a"},{"name":"a"}]'`), "duplicated audit log filter 'a'")
+	require.ErrorContains(t, conn.HandleQuery(ctx, `set global tidb_audit_log_filters = '[{"name":"a","tables":["t1"]}]'`), "should be in the format of 'db.table'")
+	require.ErrorContains(t, conn.HandleQuery(ctx, `set global tidb_audit_log_filters = '{'`), "invalid audit log filters")
+}
+
+func TestAuditLogPrivilege(t *testing.T) {
+	conn, serv, _ := setupAuditLog(t)
+	ctx := context.Background()
+	require.NoError(t, conn.HandleQuery(ctx, "create user u1"))
+	require.NoError(t, conn.HandleQuery(ctx, "grant SYSTEM_VARIABLES_ADMIN on *.* to u1"))
+
+	conn2 := server.CreateMockConn(t, serv)
+	defer conn2.Close()
+	require.NoError(t, conn2.Context().Session.Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil, nil))
+	require.ErrorContains(t, conn2.HandleQuery(ctx, "set global tidb_audit_enabled = OFF"), "AUDIT_ADMIN")
+	require.ErrorContains(t, conn2.HandleQuery(ctx, "set global tidb_audit_log_filters = '[]'"), "AUDIT_ADMIN")
+	require.ErrorContains(t, conn2.HandleQuery(ctx, "select audit_log_disable_filter('all')"), "AUDIT_ADMIN")
+
+	require.NoError(t, conn.HandleQuery(ctx, "grant AUDIT_ADMIN on *.* to u1"))
+	require.NoError(t, conn2.HandleQuery(ctx, "set global tidb_audit_log_filters = '[]'"))
+	require.NoError(t, conn2.HandleQuery(ctx, "select audit_log_enable_filter(NULL)"))
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+)
+
+const (
+	// ClassConnection is the class of the connection events
+	ClassConnection = "CONNECTION"
+	// ClassQuery is the class of the statements reading data, e.g. SELECT
+	ClassQuery = "QUERY"
+	// ClassDML is the class of the statements writing data, e.g. INSERT, UPDATE and DELETE
+	ClassDML = "DML"
+	// ClassDDL is the class of the statements changing the schema
+	ClassDDL = "DDL"
+	// ClassDCL is the class of the statements managing the users and the privileges
+	ClassDCL = "DCL"
+	// ClassOther is the class of the other statements, e.g. SET and SHOW
+	ClassOther = "OTHER"
+)
+
+var allClasses = []string{ClassConnection, ClassQuery, ClassDML, ClassDDL, ClassDCL, ClassOther}
+
+// Filter decides which events are written to the audit log.
+// An event matches the filter if it matches all the non-empty conditions of the filter,
+// and it matches a condition if it matches any item of the condition.
+type Filter struct {
+	// Name is the unique name of the filter
+	Name string `json:"name"`
+	// Users are the users in the format of 'user' or 'user@host', the host is the host in the user account
+	Users []string `json:"users,omitempty"`
+	// DBs are the databases, the current database and the databases of the related tables are matched
+	DBs []string `json:"dbs,omitempty"`
+	// Tables are the related tables in the format of 'db.table'
+	Tables []string `json:"tables,omitempty"`
+	// Classes are the classes of the events, see ClassQuery, ClassDML and so on
+	Classes []string `json:"classes,omitempty"`
+}
+
+// event is the information of an event used to match the filters
+type event struct {
+	user   string
+	host   string
+	db     string
+	tables []stmtctx.TableEntry
+	class  string
+}
+
+func (f *Filter) match(e *event) bool {
+	return f.matchUser(e) && f.matchDB(e) && f.matchTable(e) && matchAny(f.Classes, e.class)
+}
+
+func (f *Filter) matchUser(e *event) bool {
+	if len(f.Users) == 0 {
+		return true
+	}
+	for _, user := range f.Users {
+		name, host, hasHost := strings.Cut(user, "@")
+		if name == e.user && (!hasHost || host == e.host) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchDB(e *event) bool {
+	if len(f.DBs) == 0 || matchAny(f.DBs, e.db) {
+		return true
+	}
+	for _, tbl := range e.tables {
+		if matchAny(f.DBs, tbl.DB) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchTable(e *event) bool {
+	if len(f.Tables) == 0 {
+		return true
+	}
+	for _, tbl := range e.tables {
+		if matchAny(f.Tables, tbl.DB+"."+tbl.Table) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny returns true if the items are empty or any item equals to the value case-insensitively
+func matchAny(items []string, val string) bool {
+	if len(items) == 0 {
+		return true
+	}
+	for _, item := range items {
+		if strings.EqualFold(item, val) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFilters parses the filters from a JSON array
+func parseFilters(s string) ([]*Filter, error) {
+	var filters []*Filter
+	if strings.TrimSpace(s) == "" {
+		return filters, nil
+	}
+	if err := json.Unmarshal([]byte(s), &filters); err != nil {
+		return nil, errors.Annotate(err, "invalid audit log filters")
+	}
+
+	names := make(map[string]struct{}, len(filters))
+	for _, f := range filters {
+		if f == nil || f.Name == "" {
+			return nil, errors.New("the name of the audit log filter should not be empty")
+		}
+		if _, ok := names[f.Name]; ok {
+			return nil, errors.Errorf("duplicated audit log filter '%s'", f.Name)
+		}
+		names[f.Name] = struct{}{}
+		for _, class := range f.Classes {
+			if !matchAny(allClasses, class) {
+				return nil, errors.Errorf("unknown class '%s' in audit log filter '%s'", class, f.Name)
+			}
+		}
+		for _, tbl := range f.Tables {
+			if !strings.Contains(tbl, ".") {
+				return nil, errors.Errorf("table '%s' in audit log filter '%s' should be in the format of 'db.table'", tbl, f.Name)
+			}
+		}
+	}
+	return filters, nil
+}
+
+// stmtClass returns the class of the statement
+func stmtClass(stmt ast.StmtNode) string {
+	switch stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt:
+		return ClassQuery
+	case *ast.GrantStmt, *ast.GrantRoleStmt, *ast.GrantProxyStmt, *ast.RevokeStmt, *ast.RevokeRoleStmt,
+		*ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt, *ast.SetPwdStmt,
+		*ast.SetDefaultRoleStmt:
+		return ClassDCL
+	case *ast.ShowStmt:
+		return ClassOther
+	case ast.DMLNode:
+		return ClassDML
+	case ast.DDLNode:
+		return ClassDDL
+	default:
+		return ClassOther
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	tables := []stmtctx.TableEntry{{DB: "db1", Table: "t1"}, {DB: "db2", Table: "t2"}}
+	e := &event{user: "u1", host: "%", db: "test", tables: tables, class: ClassDML}
+
+	cases := []struct {
+		filter Filter
+		match  bool
+	}{
+		{Filter{}, true},
+		{Filter{Users: []string{"u1"}}, true},
+		{Filter{Users: []string{"u1@%"}}, true},
+		{Filter{Users: []string{"u1@localhost"}}, false},
+		{Filter{Users: []string{"u2", "u1"}}, true},
+		{Filter{DBs: []string{"TEST"}}, true},
+		{Filter{DBs: []string{"db2"}}, true},
+		{Filter{DBs: []string{"db3"}}, false},
+		{Filter{Tables: []string{"db1.t1"}}, true},
+		{Filter{Tables: []string{"db1.t2"}}, false},
+		{Filter{Classes: []string{"dml", "ddl"}}, true},
+		{Filter{Classes: []string{ClassQuery}}, false},
+		{Filter{Users: []string{"u1"}, Tables: []string{"db2.t2"}, Classes: []string{ClassDML}}, true},
+		{Filter{Users: []string{"u1"}, Tables: []string{"db2.t2"}, Classes: []string{ClassDDL}}, false},
+	}
+	for i, c := range cases {
+		require.Equal(t, c.match, c.filter.match(e), "case %d", i)
+	}
+
+	set := &filterSet{
+		filters: []*Filter{
+			{Name: "f1", Classes: []string{ClassDDL}},
+			{Name: "f2", Users: []string{"u1"}},
+		},
+	}
+	require.True(t, set.match(e))
+	set.disabled = map[string]struct{}{"f2": {}}
+	require.False(t, set.match(e))
+}
+
+func TestStmtClass(t *testing.T) {
+	cases := []struct {
+		sql   string
+		class string
+	}{
+		{"select 1", ClassQuery},
+		{"select 1 union select 2", ClassQuery},
+		{"insert into t values (1)", ClassDML},
+		{"update t set a = 1", ClassDML},
+		{"delete from t", ClassDML},
+		{"create table t(a int)", ClassDDL},
+		{"alter table t add column b int", ClassDDL},
+		{"create user u1", ClassDCL},
+		{"grant select on *.* to u1", ClassDCL},
+		{"revoke select on *.* from u1", ClassDCL},
+		{"set password for u1 = 'abc'", ClassDCL},
+		{"show tables", ClassOther},
+		{"set @a = 1", ClassOther},
+	}
+	p := parser.New()
+	for _, c := range cases {
+		stmt, err := p.ParseOneStmt(c.sql, "", "")
+		require.NoError(t, err)
+		require.Equal(t, c.class, stmtClass(stmt), c.sql)
+	}
+}
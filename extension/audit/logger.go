@@ -0,0 +1,111 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// record is a line of the audit log
+type record struct {
+	Time         string   `json:"time"`
+	Class        string   `json:"class"`
+	Event        string   `json:"event"`
+	ConnectionID uint64   `json:"conn_id"`
+	User         string   `json:"user,omitempty"`
+	Host         string   `json:"host,omitempty"`
+	ClientIP     string   `json:"client_ip,omitempty"`
+	DB           string   `json:"db,omitempty"`
+	Tables       []string `json:"tables,omitempty"`
+	SQL          string   `json:"sql,omitempty"`
+	Params       []string `json:"params,omitempty"`
+	AffectedRows uint64   `json:"affected_rows,omitempty"`
+	Status       string   `json:"status"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// fileConfig is the config of the audit log file
+type fileConfig struct {
+	filename   string
+	maxSize    int
+	maxDays    int
+	maxBackups int
+}
+
+// path returns the path of the audit log file, a relative path is relative to the directory of the TiDB log file
+func (c *fileConfig) path() string {
+	if filepath.IsAbs(c.filename) {
+		return c.filename
+	}
+	if logFile := config.GetGlobalConfig().Log.File.Filename; logFile != "" {
+		return filepath.Join(filepath.Dir(logFile), c.filename)
+	}
+	return c.filename
+}
+
+// fileWriter writes the records to the file as JSON lines, the file is rotated by the size
+type fileWriter struct {
+	mu     sync.Mutex
+	out    *lumberjack.Logger
+	buf    []byte
+	closed bool
+}
+
+func newFileWriter(c fileConfig) *fileWriter {
+	return &fileWriter{
+		out: &lumberjack.Logger{
+			Filename:   c.path(),
+			MaxSize:    c.maxSize,
+			MaxAge:     c.maxDays,
+			MaxBackups: c.maxBackups,
+			LocalTime:  true,
+		},
+	}
+}
+
+func (w *fileWriter) write(r *record) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		logutil.BgLogger().Warn("failed to marshal audit log", zap.Error(err))
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// The file would be reopened by the writes after closing.
+	if w.closed {
+		return
+	}
+	w.buf = append(append(w.buf[:0], b...), '\n')
+	if _, err = w.out.Write(w.buf); err != nil {
+		logutil.BgLogger().Warn("failed to write audit log", zap.String("file", w.out.Filename), zap.Error(err))
+	}
+}
+
+func (w *fileWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if err := w.out.Close(); err != nil {
+		logutil.BgLogger().Warn("failed to close audit log", zap.String("file", w.out.Filename), zap.Error(err))
+	}
+}
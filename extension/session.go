@@ -90,10 +90,68 @@ type StmtEventInfo interface {
 	GetError() error
 }
 
+// SessionInfo is the information of the session
+type SessionInfo interface {
+	// User returns the user of the session
+	User() *auth.UserIdentity
+	// ActiveRoles returns the active roles of the user
+	ActiveRoles() []*auth.RoleIdentity
+	// CurrentDB returns the current database
+	CurrentDB() string
+	// ConnectionInfo returns the connection info of the current session
+	ConnectionInfo() *variable.ConnectionInfo
+}
+
+// NewSessionInfo returns the `SessionInfo` of the session vars
+func NewSessionInfo(vars *variable.SessionVars) SessionInfo {
+	return sessionInfo{vars: vars}
+}
+
+type sessionInfo struct {
+	vars *variable.SessionVars
+}
+
+func (s sessionInfo) User() *auth.UserIdentity {
+	return s.vars.User
+}
+
+func (s sessionInfo) ActiveRoles() []*auth.RoleIdentity {
+	return s.vars.ActiveRoles
+}
+
+func (s sessionInfo) CurrentDB() string {
+	return s.vars.CurrentDB
+}
+
+func (s sessionInfo) ConnectionInfo() *variable.ConnectionInfo {
+	return s.vars.ConnectionInfo
+}
+
+// ResultColumn is a column of a table read by a statement, the names are in lower case.
+type ResultColumn struct {
+	DBName     string
+	TableName  string
+	ColumnName string
+	Type       *types.FieldType
+}
+
+// MaskFunc masks a value of a column. It should return a null value or a value of the same kind.
+type MaskFunc func(types.Datum) types.Datum
+
 // SessionHandler is used to listen session events
 type SessionHandler struct {
 	OnConnectionEvent func(ConnEventTp, *ConnEventInfo)
 	OnStmtEvent       func(StmtEventTp, StmtEventInfo)
+	// OnStmtRewrite is called before a statement is planned, the returned statement is planned instead.
+	// Returning the input statement means no rewrite. It is also called when preparing a statement,
+	// and the rewritten statement is cached by the prepared statement.
+	OnStmtRewrite func(SessionInfo, ast.StmtNode) (ast.StmtNode, error)
+	// MaskResultColumn returns the function to mask the values of the column for the session, nil means the
+	// column is not masked. The columns are masked when they are read from the tables at plan time, so the
+	// expressions, the subqueries, the views and the statements writing the rows read, e.g. INSERT ... SELECT,
+	// all see the masked values. The rows of the tables written back by UPDATE and DELETE are not masked,
+	// but the references to their masked columns are.
+	MaskResultColumn func(SessionInfo, *ResultColumn) MaskFunc
 }
 
 func newSessionExtensions(es *Extensions) *SessionExtensions {
@@ -107,6 +165,12 @@ func newSessionExtensions(es *Extensions) *SessionExtensions {
 				if fn := handler.OnStmtEvent; fn != nil {
 					connExtensions.stmtEventFuncs = append(connExtensions.stmtEventFuncs, fn)
 				}
+				if fn := handler.OnStmtRewrite; fn != nil {
+					connExtensions.stmtRewriteFuncs = append(connExtensions.stmtRewriteFuncs, fn)
+				}
+				if fn := handler.MaskResultColumn; fn != nil {
+					connExtensions.maskResultColumnFuncs = append(connExtensions.maskResultColumnFuncs, fn)
+				}
 			}
 		}
 	}
@@ -115,8 +179,10 @@ func newSessionExtensions(es *Extensions) *SessionExtensions {
 
 // SessionExtensions is the extensions
 type SessionExtensions struct {
-	connectionEventFuncs []func(ConnEventTp, *ConnEventInfo)
-	stmtEventFuncs       []func(StmtEventTp, StmtEventInfo)
+	connectionEventFuncs  []func(ConnEventTp, *ConnEventInfo)
+	stmtEventFuncs        []func(StmtEventTp, StmtEventInfo)
+	stmtRewriteFuncs      []func(SessionInfo, ast.StmtNode) (ast.StmtNode, error)
+	maskResultColumnFuncs []func(SessionInfo, *ResultColumn) MaskFunc
 }
 
 // OnConnectionEvent will be called when a connection event happens
@@ -145,3 +211,77 @@ func (es *SessionExtensions) OnStmtEvent(tp StmtEventTp, event StmtEventInfo) {
 		fn(tp, event)
 	}
 }
+
+// HasStmtRewriters returns a bool that indicates if any stmt rewriter exists
+func (es *SessionExtensions) HasStmtRewriters() bool {
+	return es != nil && len(es.stmtRewriteFuncs) > 0
+}
+
+// RewriteStmt rewrites the stmt by the extensions in the order they are registered.
+// The original text of the stmt is kept if the rewritten stmt has no text.
+func (es *SessionExtensions) RewriteStmt(info SessionInfo, stmt ast.StmtNode) (ast.StmtNode, error) {
+	if es == nil {
+		return stmt, nil
+	}
+
+	text := stmt.Text()
+	for _, fn := range es.stmtRewriteFuncs {
+		rewritten, err := fn(info, stmt)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten != nil {
+			stmt = rewritten
+		}
+	}
+
+	if stmt.Text() == "" {
+		stmt.SetText(nil, text)
+	}
+	return stmt, nil
+}
+
+// HasResultMasks returns a bool that indicates if any result column mask exists
+func (es *SessionExtensions) HasResultMasks() bool {
+	return es != nil && len(es.maskResultColumnFuncs) > 0
+}
+
+// ResultMaskFuncs returns the mask functions of the columns. A nil slice is returned if no column is masked,
+// otherwise the function of a column not masked is nil. If more than one extension masks a column,
+// the functions are applied in the order the extensions are registered.
+func (es *SessionExtensions) ResultMaskFuncs(info SessionInfo, cols []*ResultColumn) []MaskFunc {
+	if !es.HasResultMasks() {
+		return nil
+	}
+
+	var masks []MaskFunc
+	for i, col := range cols {
+		var fns []MaskFunc
+		for _, fn := range es.maskResultColumnFuncs {
+			if mask := fn(info, col); mask != nil {
+				fns = append(fns, mask)
+			}
+		}
+
+		if len(fns) == 0 {
+			continue
+		}
+
+		if masks == nil {
+			masks = make([]MaskFunc, len(cols))
+		}
+
+		if len(fns) == 1 {
+			masks[i] = fns[0]
+			continue
+		}
+
+		masks[i] = func(d types.Datum) types.Datum {
+			for _, fn := range fns {
+				d = fn(d)
+			}
+			return d
+		}
+	}
+	return masks
+}
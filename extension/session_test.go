@@ -0,0 +1,187 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extension_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/extension"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func maskPhone(d types.Datum) types.Datum {
+	if d.IsNull() {
+		return d
+	}
+	s := d.GetString()
+	if len(s) > 4 {
+		s = strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+	}
+	return types.NewStringDatum(s)
+}
+
+func TestExtensionStmtRewriteAndResultMask(t *testing.T) {
+	defer extension.Reset()
+	extension.Reset()
+
+	var rewriteUsers []string
+	require.NoError(t, extension.Register("test", extension.WithSessionHandlerFactory(func() *extension.SessionHandler {
+		return &extension.SessionHandler{
+			OnStmtRewrite: func(info extension.SessionInfo, stmt ast.StmtNode) (ast.StmtNode, error) {
+				if user := info.User(); user != nil {
+					rewriteUsers = append(rewriteUsers, user.Username)
+				}
+				switch x := stmt.(type) {
+				case *ast.TruncateTableStmt:
+					return nil, errors.New("truncate is not allowed")
+				case *ast.SelectStmt:
+					if x.Limit == nil {
+						x.Limit = &ast.Limit{Count: ast.NewValueExpr(2, "", "")}
+					}
+				}
+				return stmt, nil
+			},
+			MaskResultColumn: func(info extension.SessionInfo, col *extension.ResultColumn) extension.MaskFunc {
+				if user := info.User(); user != nil && user.Username == "root" {
+					return nil
+				}
+				if col.DBName == "test" && col.TableName == "t" && col.ColumnName == "phone" {
+					return maskPhone
+				}
+				return nil
+			},
+		}
+	})))
+	require.NoError(t, extension.Setup())
+	extensions, err := extension.GetExtensions()
+	require.NoError(t, err)
+
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, phone varchar(20))")
+	tk.MustExec("insert into t values (1, '13800001111'), (2, '13900002222'), (3, null)")
+	tk.MustExec("create user u1@localhost")
+	tk.MustExec("grant ALL ON test.* to u1@localhost")
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.Session().SetExtensions(extensions.NewSessionExtensions())
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil, nil))
+	tk1.MustExec("use test")
+
+	// The select statements are rewritten to return 2 rows at most.
+	tk1.MustQuery("select id, phone from t order by id").Check(testkit.Rows("1 *******1111", "2 *******2222"))
+	tk1.MustQuery("select id, phone from t order by id limit 3").Check(testkit.Rows("1 *******1111", "2 *******2222", "3 <nil>"))
+	// The columns are masked when they are read, so the aliases and the expressions are masked.
+	tk1.MustQuery("select phone as p, upper(phone) from t where id = 1").Check(testkit.Rows("*******1111 *******1111"))
+	tk1.MustGetErrMsg("truncate table t", "truncate is not allowed")
+	require.Contains(t, rewriteUsers, "u1")
+
+	// The prepared statements are rewritten when they are prepared.
+	tk1.MustExec("prepare stmt from 'select phone from t where id >= ? order by id'")
+	tk1.MustExec("set @id = 0")
+	tk1.MustQuery("execute stmt using @id").Check(testkit.Rows("*******1111", "*******2222"))
+
+	// The mask functions are decided by the user of the session.
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil, nil))
+	tk1.MustQuery("select phone from t where id = 1").Check(testkit.Rows("13800001111"))
+
+	// The sessions without extensions are not affected.
+	tk.MustQuery("select phone from t order by id").Check(testkit.Rows("13800001111", "13900002222", "<nil>"))
+	tk.MustExec("truncate table t")
+}
+
+func TestExtensionMaskColumnsInStmts(t *testing.T) {
+	defer extension.Reset()
+	extension.Reset()
+
+	var maskedCols []string
+	require.NoError(t, extension.Register("test", extension.WithSessionHandlerFactory(func() *extension.SessionHandler {
+		return &extension.SessionHandler{
+			MaskResultColumn: func(info extension.SessionInfo, col *extension.ResultColumn) extension.MaskFunc {
+				if user := info.User(); user == nil || user.Username != "u1" {
+					return nil
+				}
+				if col.DBName == "test" && col.TableName == "t" && col.ColumnName == "phone" {
+					maskedCols = append(maskedCols, col.ColumnName)
+					return maskPhone
+				}
+				return nil
+			},
+		}
+	})))
+	require.NoError(t, extension.Setup())
+	extensions, err := extension.GetExtensions()
+	require.NoError(t, err)
+
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, phone varchar(20), k int, unique key(phone))")
+	tk.MustExec("insert into t values (1, '13800001111', 0), (2, '13900002222', 0)")
+	tk.MustExec("create table t2(id int, phone varchar(20))")
+	// The view needs a definer.
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("create view v as select id, phone from t")
+	tk.MustExec("create user u1@localhost")
+	tk.MustExec("grant ALL ON test.* to u1@localhost")
+
+	tk1 := testkit.NewTestKit(t, store)
+	tk1.Session().SetExtensions(extensions.NewSessionExtensions())
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil, nil))
+	tk1.MustExec("use test")
+
+	// The point get and the batch point get read the masked values.
+	tk1.MustQuery("select phone from t where id = 1").Check(testkit.Rows("*******1111"))
+	tk1.MustQuery("select phone from t where id in (1, 2) order by id").Check(testkit.Rows("*******1111", "*******2222"))
+	tk1.MustQuery("select id from t where phone = '13800001111'").Check(testkit.Rows())
+	tk1.MustQuery("select id from t where phone = '*******1111'").Check(testkit.Rows("1"))
+	require.Contains(t, maskedCols, "phone")
+	// The expressions, the subqueries, the unions, the CTEs and the views read the masked values.
+	tk1.MustQuery("select concat(phone, ''), upper(phone), length(phone) from t where id = 1").Check(testkit.Rows("*******1111 *******1111 11"))
+	tk1.MustQuery("select p from (select phone as p from t) x order by p").Check(testkit.Rows("*******1111", "*******2222"))
+	tk1.MustQuery("select (select phone from t where id = 1)").Check(testkit.Rows("*******1111"))
+	tk1.MustQuery("select phone from t where id = 1 union all select phone from t where id = 2").Sort().Check(testkit.Rows("*******1111", "*******2222"))
+	tk1.MustQuery("with c as (select phone from t) select phone from c order by phone").Check(testkit.Rows("*******1111", "*******2222"))
+	tk1.MustQuery("select phone from v order by id").Check(testkit.Rows("*******1111", "*******2222"))
+
+	// The rows written by INSERT ... SELECT are masked.
+	tk1.MustExec("insert into t2 select id, phone from t")
+	tk.MustQuery("select phone from t2 order by id").Check(testkit.Rows("*******1111", "*******2222"))
+	// The rows written back by UPDATE and DELETE are not masked, but the references to the masked columns are.
+	tk1.MustExec("update t set k = length(phone) where phone = '*******2222'")
+	tk1.MustExec("update t set k = 1 where id = 1")
+	tk.MustQuery("select id, phone, k from t order by id").Check(testkit.Rows("1 13800001111 1", "2 13900002222 11"))
+	tk1.MustExec("delete from t where phone = '13800001111'")
+	tk1.MustExec("delete from t where id = 2 and phone = '13900002222'")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("2"))
+	tk1.MustExec("delete from t where phone = '*******1111'")
+	tk.MustQuery("select id from t").Check(testkit.Rows("2"))
+
+	// The prepared statements aren't cached, since the masks depend on the session.
+	tk1.MustExec("prepare stmt from 'select phone from t where id = ?'")
+	tk1.MustExec("set @id = 2")
+	tk1.MustQuery("execute stmt using @id").Check(testkit.Rows("*******2222"))
+	tk1.MustQuery("execute stmt using @id").Check(testkit.Rows("*******2222"))
+	tk1.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil, nil))
+	tk1.MustQuery("execute stmt using @id").Check(testkit.Rows("13900002222"))
+}
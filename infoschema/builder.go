@@ -225,7 +225,8 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		return b.applyRecoverTable(m, diff)
 	case model.ActionCreateTables:
 		return b.applyCreateTables(m, diff)
-	case model.ActionReorganizePartition:
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+		model.ActionRemovePartitioning:
 		return b.applyReorganizePartition(m, diff)
 	case model.ActionFlashbackCluster:
 		return []int64{-1}, nil
@@ -298,6 +299,11 @@ func (b *Builder) applyReorganizePartition(m *meta.Meta, diff *model.SchemaDiff)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if diff.OldTableID != 0 && diff.OldTableID != diff.TableID {
+		// ALTER TABLE ... PARTITION BY assigns a new table ID in the end
+		b.deleteBundle(b.is, diff.OldTableID)
+		b.markTableBundleShouldUpdate(diff.TableID)
+	}
 	for _, opt := range diff.AffectedOpts {
 		if opt.OldTableID != 0 {
 			b.deleteBundle(b.is, opt.OldTableID)
@@ -415,7 +421,9 @@ func (b *Builder) applyTableUpdate(m *meta.Meta, diff *model.SchemaDiff) ([]int6
 		newTableID = diff.TableID
 	case model.ActionDropTable, model.ActionDropView, model.ActionDropSequence:
 		oldTableID = diff.TableID
-	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition:
+	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition,
+		model.ActionCreateMaterializedView, model.ActionDropMaterializedView,
+		model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
 		oldTableID = diff.OldTableID
 		newTableID = diff.TableID
 	default:
@@ -719,7 +727,8 @@ func (b *Builder) applyCreateTable(m *meta.Meta, dbInfo *model.DBInfo, tableID i
 	case model.ActionDropTablePartition:
 	case model.ActionTruncateTablePartition:
 	// ReorganizePartition handle the bundles in applyReorganizePartition
-	case model.ActionReorganizePartition:
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+		model.ActionRemovePartitioning:
 	default:
 		pi := tblInfo.GetPartitionInfo()
 		if pi != nil {
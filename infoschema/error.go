@@ -38,6 +38,18 @@ var (
 	ErrResourceGroupNotExists = dbterror.ClassSchema.NewStd(mysql.ErrResourceGroupNotExists)
 	// ErrResourceGroupInvalidBackgroundTaskName return for unknown resource group background task name.
 	ErrResourceGroupInvalidBackgroundTaskName = dbterror.ClassExecutor.NewStd(mysql.ErrResourceGroupInvalidBackgroundTaskName)
+	// ErrTimerExists return for user-defined timer already exists.
+	ErrTimerExists = dbterror.ClassSchema.NewStd(mysql.ErrTimerExists)
+	// ErrTimerNotExists return for user-defined timer not exists.
+	ErrTimerNotExists = dbterror.ClassSchema.NewStd(mysql.ErrTimerNotExists)
+	// ErrMaskingPolicyExists return for masking policy already exists.
+	ErrMaskingPolicyExists = dbterror.ClassSchema.NewStd(mysql.ErrMaskingPolicyExists)
+	// ErrMaskingPolicyNotExists return for masking policy not exists.
+	ErrMaskingPolicyNotExists = dbterror.ClassSchema.NewStd(mysql.ErrMaskingPolicyNotExists)
+	// ErrRowAccessPolicyExists return for row access policy already exists.
+	ErrRowAccessPolicyExists = dbterror.ClassSchema.NewStd(mysql.ErrRowAccessPolicyExists)
+	// ErrRowAccessPolicyNotExists return for row access policy not exists.
+	ErrRowAccessPolicyNotExists = dbterror.ClassSchema.NewStd(mysql.ErrRowAccessPolicyNotExists)
 	// ErrReservedSyntax for internal syntax.
 	ErrReservedSyntax = dbterror.ClassSchema.NewStd(mysql.ErrReservedSyntax)
 	// ErrTableExists returns for table already exists.
@@ -297,6 +297,11 @@ func TestInfoTables(t *testing.T) {
 		"PLACEMENT_POLICIES",
 		"TRX_SUMMARY",
 		"RESOURCE_GROUPS",
+		"TIMERS",
+		"MASKING_POLICIES",
+		"ROW_ACCESS_POLICIES",
+		"INTERVAL_PARTITIONS",
+		"CLUSTER_STATEMENTS_DIGEST_HISTORY",
 	}
 	for _, tbl := range infoTables {
 		tb, err1 := is.TableByName(util.InformationSchemaName, model.NewCIStr(tbl))
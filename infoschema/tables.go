@@ -203,6 +203,16 @@ const (
 	TableMemoryUsageOpsHistory = "MEMORY_USAGE_OPS_HISTORY"
 	// TableResourceGroups is the metadata of resource groups.
 	TableResourceGroups = "RESOURCE_GROUPS"
+	// TableTimers is the metadata of user-defined timers.
+	TableTimers = "TIMERS"
+	// TableMaskingPolicies is the metadata of masking policies.
+	TableMaskingPolicies = "MASKING_POLICIES"
+	// TableRowAccessPolicies is the metadata of row access policies.
+	TableRowAccessPolicies = "ROW_ACCESS_POLICIES"
+	// TableIntervalPartitions is the status of the tables with interval partition policies.
+	TableIntervalPartitions = "INTERVAL_PARTITIONS"
+	// TableClusterStatementsDigestHistory is the statement history of all TiDB instances grouped by bucket and digest.
+	TableClusterStatementsDigestHistory = "CLUSTER_STATEMENTS_DIGEST_HISTORY"
 )
 
 const (
@@ -310,6 +320,11 @@ var tableIDMap = map[string]int64{
 	ClusterTableMemoryUsage:              autoid.InformationSchemaDBID + 86,
 	ClusterTableMemoryUsageOpsHistory:    autoid.InformationSchemaDBID + 87,
 	TableResourceGroups:                  autoid.InformationSchemaDBID + 88,
+	TableTimers:                          autoid.InformationSchemaDBID + 89,
+	TableMaskingPolicies:                 autoid.InformationSchemaDBID + 90,
+	TableRowAccessPolicies:               autoid.InformationSchemaDBID + 91,
+	TableIntervalPartitions:              autoid.InformationSchemaDBID + 92,
+	TableClusterStatementsDigestHistory:  autoid.InformationSchemaDBID + 93,
 }
 
 // columnInfo represents the basic column information of all kinds of INFORMATION_SCHEMA tables
@@ -900,6 +915,7 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogCopWaitAddr, tp: mysql.TypeVarchar, size: 64},
 	{name: variable.SlowLogMemMax, tp: mysql.TypeLonglong, size: 20},
 	{name: variable.SlowLogDiskMax, tp: mysql.TypeLonglong, size: 20},
+	{name: variable.SlowLogDiskSpilled, tp: mysql.TypeLonglong, size: 20},
 	{name: variable.SlowLogKVTotal, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogPDTotal, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogBackoffTotal, tp: mysql.TypeDouble, size: 22},
@@ -1323,6 +1339,8 @@ var tableStatementsSummaryCols = []columnInfo{
 	{name: stmtsummary.MaxMemStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Max memory(byte) used"},
 	{name: stmtsummary.AvgDiskStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average disk space(byte) used"},
 	{name: stmtsummary.MaxDiskStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Max disk space(byte) used"},
+	{name: stmtsummary.AvgDiskSpilledStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average bytes spilled to disk"},
+	{name: stmtsummary.MaxDiskSpilledStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Max bytes spilled to disk"},
 	{name: stmtsummary.AvgKvTimeStr, tp: mysql.TypeLonglong, size: 22, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average time of TiKV used"},
 	{name: stmtsummary.AvgPdTimeStr, tp: mysql.TypeLonglong, size: 22, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average time of PD used"},
 	{name: stmtsummary.AvgBackoffTotalTimeStr, tp: mysql.TypeLonglong, size: 22, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average time of Backoff used"},
@@ -1606,6 +1624,83 @@ var tableResourceGroupsCols = []columnInfo{
 	{name: "BACKGROUND", tp: mysql.TypeVarchar, size: 256},
 }
 
+var tableTimersCols = []columnInfo{
+	{name: "TIMER_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TIMER_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "SCHEDULE_POLICY_TYPE", tp: mysql.TypeVarchar, size: 32},
+	{name: "SCHEDULE_POLICY_EXPR", tp: mysql.TypeVarchar, size: 256},
+	{name: "TIME_ZONE", tp: mysql.TypeVarchar, size: 64},
+	{name: "ENABLED", tp: mysql.TypeVarchar, size: 3},
+	{name: "WATERMARK", tp: mysql.TypeDatetime},
+	{name: "NEXT_EVENT_TIME", tp: mysql.TypeDatetime},
+	{name: "EVENT_STATUS", tp: mysql.TypeVarchar, size: 32},
+	{name: "EVENT_START_TIME", tp: mysql.TypeDatetime},
+	{name: "LAST_EVENT_STATUS", tp: mysql.TypeVarchar, size: 32},
+	{name: "LAST_EVENT_START_TIME", tp: mysql.TypeDatetime},
+	{name: "LAST_EVENT_END_TIME", tp: mysql.TypeDatetime},
+	{name: "LAST_EVENT_ERROR", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+	{name: "LAST_AFFECTED_ROWS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "TOTAL_SUCCESS_COUNT", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "TOTAL_FAILED_COUNT", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "SQL_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+	{name: "TIMER_COMMENT", tp: mysql.TypeVarchar, size: 1024},
+	{name: "CREATOR", tp: mysql.TypeVarchar, size: 256},
+	{name: "CREATE_TIME", tp: mysql.TypeDatetime},
+}
+
+var tableMaskingPoliciesCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "POLICY_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "COLUMN_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "MASKING_TYPE", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
+	{name: "MASKING_ARGS", tp: mysql.TypeVarchar, size: 256},
+}
+
+var tableRowAccessPoliciesCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "POLICY_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "POLICY_EXPRESSION", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag},
+}
+
+var tableIntervalPartitionsCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "PARTITION_INTERVAL", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "PARTITION_PRECREATE", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "PARTITION_RETENTION", tp: mysql.TypeVarchar, size: 64},
+	{name: "LAST_CHECK_TIME", tp: mysql.TypeDatetime},
+	{name: "LAST_STATUS", tp: mysql.TypeVarchar, size: 32},
+	{name: "LAST_ERROR", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+	{name: "LAST_SUCCESS_TIME", tp: mysql.TypeDatetime},
+	{name: "LAST_CHANGE", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+	{name: "LAST_CHANGE_TIME", tp: mysql.TypeDatetime},
+}
+
+var tableClusterStatementsDigestHistoryCols = []columnInfo{
+	{name: stmtsummary.SummaryBeginTimeStr, tp: mysql.TypeTimestamp, size: 26, flag: mysql.NotNullFlag, comment: "Begin time of this bucket"},
+	{name: stmtsummary.SummaryEndTimeStr, tp: mysql.TypeTimestamp, size: 26, flag: mysql.NotNullFlag, comment: "End time of this bucket"},
+	{name: stmtsummary.StmtTypeStr, tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, comment: "Statement type"},
+	{name: stmtsummary.SchemaNameStr, tp: mysql.TypeVarchar, size: 64, comment: "Current schema"},
+	{name: stmtsummary.DigestStr, tp: mysql.TypeVarchar, size: 64},
+	{name: stmtsummary.DigestTextStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag, comment: "Normalized statement"},
+	{name: "INSTANCE_COUNT", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Count of instances that executed these statements"},
+	{name: stmtsummary.ExecCountStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Count of executions"},
+	{name: stmtsummary.SumErrorsStr, tp: mysql.TypeLong, size: 11, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Sum of errors"},
+	{name: stmtsummary.SumWarningsStr, tp: mysql.TypeLong, size: 11, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Sum of warnings"},
+	{name: stmtsummary.SumLatencyStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Sum latency of these statements"},
+	{name: stmtsummary.MaxLatencyStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Max latency of these statements"},
+	{name: stmtsummary.MinLatencyStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Min latency of these statements"},
+	{name: stmtsummary.AvgLatencyStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average latency of these statements"},
+	{name: stmtsummary.AvgProcessedKeysStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average number of processed keys"},
+	{name: stmtsummary.AvgTotalKeysStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average number of scanned keys"},
+	{name: stmtsummary.AvgMemStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average memory(byte) used"},
+	{name: stmtsummary.AvgResultRowsStr, tp: mysql.TypeLonglong, size: 22, flag: mysql.NotNullFlag, comment: "Average count of sql result rows"},
+	{name: stmtsummary.FirstSeenStr, tp: mysql.TypeTimestamp, size: 26, flag: mysql.NotNullFlag, comment: "The time these statements are seen for the first time"},
+	{name: stmtsummary.LastSeenStr, tp: mysql.TypeTimestamp, size: 26, flag: mysql.NotNullFlag, comment: "The time these statements are seen for the last time"},
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
 //   - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
@@ -2118,6 +2213,11 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableMemoryUsage:                        tableMemoryUsageCols,
 	TableMemoryUsageOpsHistory:              tableMemoryUsageOpsHistoryCols,
 	TableResourceGroups:                     tableResourceGroupsCols,
+	TableTimers:                             tableTimersCols,
+	TableMaskingPolicies:                    tableMaskingPoliciesCols,
+	TableRowAccessPolicies:                  tableRowAccessPoliciesCols,
+	TableIntervalPartitions:                 tableIntervalPartitionsCols,
+	TableClusterStatementsDigestHistory:     tableClusterStatementsDigestHistoryCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
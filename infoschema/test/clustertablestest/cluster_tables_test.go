@@ -538,6 +538,53 @@ func TestStmtSummaryHistoryTable(t *testing.T) {
 	))
 }
 
+func TestStmtDigestHistoryTable(t *testing.T) {
+	// setup suite
+	s := new(clusterTablesSuite)
+	s.store, s.dom = testkit.CreateMockStoreAndDomain(t)
+	s.rpcserver, s.listenAddr = s.setUpRPCService(t, "127.0.0.1:0", nil)
+	s.httpServer, s.mockAddr = s.setUpMockPDHTTPServer()
+	s.startTime = time.Now()
+	defer s.httpServer.Close()
+	defer s.rpcserver.Stop()
+
+	tk := s.newTestKitWithRoot(t)
+	tk.MustExec("drop table if exists test_summary")
+	tk.MustExec("create table test_summary(a int, b varchar(10), key k(a))")
+	// Clear the summary of the former tests.
+	tk.MustExec("set global tidb_enable_stmt_summary = 0")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	// Disable refreshing summary.
+	tk.MustExec("set global tidb_stmt_summary_refresh_interval = 999999999")
+	defer tk.MustExec("set global tidb_stmt_summary_refresh_interval = 1800")
+
+	// Create a new session to test.
+	tk = s.newTestKitWithRoot(t)
+	tk.MustExec("insert into test_summary values(1, 'a')")
+	tk.MustExec("insert into test_summary values(2, 'b')")
+	tk.MustExec("insert into test_summary values(3, 'c')")
+
+	digest := tk.MustQuery("select digest from information_schema.cluster_statements_summary_history " +
+		"where digest_text like 'insert into `test_summary`%'").Rows()[0][0].(string)
+	sql := "select stmt_type, schema_name, instance_count, exec_count, sum_errors, avg_processed_keys, " +
+		"unix_timestamp(summary_begin_time) % 3600, timestampdiff(second, summary_begin_time, summary_end_time), " +
+		"first_seen >= summary_begin_time, last_seen < summary_end_time " +
+		"from information_schema.cluster_statements_digest_history where digest_text like 'insert into `test_summary`%'"
+	tk.MustQuery(sql).Check(testkit.Rows("Insert test 1 3 0 0 0 3600 1 1"))
+	// The digest and the time range are pushed down into the aggregation.
+	tk.MustQuery("select exec_count from information_schema.cluster_statements_digest_history " +
+		"where digest = '" + digest + "' and summary_begin_time <= now() and summary_end_time >= now()").Check(testkit.Rows("3"))
+	tk.MustQuery("select count(*) from information_schema.cluster_statements_digest_history " +
+		"where digest = '" + digest + "' and summary_end_time < '2000-01-01 00:00:00'").Check(testkit.Rows("0"))
+
+	tk.MustExec("create user 'testuser'@'localhost'")
+	defer tk.MustExec("drop user 'testuser'@'localhost'")
+	tk1 := s.newTestKitWithRoot(t)
+	require.NoError(t, tk1.Session().Auth(&auth.UserIdentity{Username: "testuser", Hostname: "localhost"}, nil, nil, nil))
+	err := tk1.QueryToErr("select * from information_schema.cluster_statements_digest_history")
+	require.ErrorContains(t, err, "the PROCESS privilege(s)")
+}
+
 func TestIssue26379(t *testing.T) {
 	s := new(clusterTablesSuite)
 	s.store, s.dom = testkit.CreateMockStoreAndDomain(t)
@@ -536,6 +536,7 @@ func TestSlowQuery(t *testing.T) {
 			"0",
 			"0",
 			"0",
+			"0",
 			"10",
 			"",
 			"",
@@ -606,6 +607,7 @@ func TestSlowQuery(t *testing.T) {
 			"",
 			"856544",
 			"0",
+			"0",
 			"86.635049185",
 			"0.015486658",
 			"100.054",
@@ -223,6 +223,8 @@ func RegisterMetrics() {
 	prometheus.MustRegister(TopSQLIgnoredCounter)
 	prometheus.MustRegister(TopSQLReportDurationHistogram)
 	prometheus.MustRegister(TopSQLReportDataHistogram)
+	prometheus.MustRegister(TopSQLTopNCPUTimeGauge)
+	prometheus.MustRegister(TopSQLTopNExecCountGauge)
 	prometheus.MustRegister(PDAPIExecutionHistogram)
 	prometheus.MustRegister(PDAPIRequestCounter)
 	prometheus.MustRegister(CPUProfileCounter)
@@ -231,6 +233,9 @@ func RegisterMetrics() {
 	prometheus.MustRegister(NonTransactionalDMLCount)
 	prometheus.MustRegister(PessimisticDMLDurationByAttempt)
 	prometheus.MustRegister(ResourceGroupQueryTotalCounter)
+	prometheus.MustRegister(ResourceGroupQueueWaitingGauge)
+	prometheus.MustRegister(ResourceGroupQueueWaitDuration)
+	prometheus.MustRegister(ResourceGroupQueueRejectCounter)
 	prometheus.MustRegister(MemoryUsage)
 	prometheus.MustRegister(StatsCacheLRUCounter)
 	prometheus.MustRegister(StatsCacheLRUGauge)
@@ -38,6 +38,9 @@ var (
 	LazyPessimisticUniqueCheckSetCount prometheus.Counter
 	PessimisticDMLDurationByAttempt    *prometheus.HistogramVec
 	ResourceGroupQueryTotalCounter     *prometheus.CounterVec
+	ResourceGroupQueueWaitingGauge     *prometheus.GaugeVec
+	ResourceGroupQueueWaitDuration     *prometheus.HistogramVec
+	ResourceGroupQueueRejectCounter    *prometheus.CounterVec
 	FairLockingUsageCount              *prometheus.CounterVec
 )
 
@@ -220,6 +223,31 @@ func InitSessionMetrics() {
 			Help:      "Counter of the total number of queries for the resource group",
 		}, []string{LblName})
 
+	ResourceGroupQueueWaitingGauge = NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "session",
+			Name:      "resource_group_queue_waiting",
+			Help:      "Gauge of the number of statements waiting in the admission queue of the resource group",
+		}, []string{LblName})
+
+	ResourceGroupQueueWaitDuration = NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "session",
+			Name:      "resource_group_queue_wait_duration_seconds",
+			Help:      "Bucketed histogram of the duration statements wait in the admission queue of the resource group",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 20), // 1ms ~ 524s
+		}, []string{LblName})
+
+	ResourceGroupQueueRejectCounter = NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "session",
+			Name:      "resource_group_queue_reject_total",
+			Help:      "Counter of the statements rejected by the admission queue of the resource group",
+		}, []string{LblName, LblType})
+
 	FairLockingUsageCount = NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "tidb",
@@ -277,4 +305,10 @@ const (
 	LblFairLockingStmtUsed      = "stmt-used"
 	LblFairLockingStmtEffective = "stmt-effective"
 	LblScope                    = "scope"
+
+	LblQueueFull    = "queue_full"
+	LblQueueTimeout = "queue_timeout"
+
+	LblSQLDigest  = "sql_digest"
+	LblPlanDigest = "plan_digest"
 )
@@ -21,6 +21,8 @@ var (
 	TopSQLIgnoredCounter          *prometheus.CounterVec
 	TopSQLReportDurationHistogram *prometheus.HistogramVec
 	TopSQLReportDataHistogram     *prometheus.HistogramVec
+	TopSQLTopNCPUTimeGauge        *prometheus.GaugeVec
+	TopSQLTopNExecCountGauge      *prometheus.GaugeVec
 )
 
 // InitTopSQLMetrics initializes top-sql metrics.
@@ -50,4 +52,20 @@ func InitTopSQLMetrics() {
 			Help:      "Bucket histogram of reporting records/sql/plan count to the top-sql agent.",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 20), // 1 ~ 524288
 		}, []string{LblType})
+
+	TopSQLTopNCPUTimeGauge = NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "topsql",
+			Name:      "top_n_cpu_time_ms",
+			Help:      "CPU time (ms) of the top N SQL in the last report interval of top-sql.",
+		}, []string{LblSQLDigest, LblPlanDigest})
+
+	TopSQLTopNExecCountGauge = NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "topsql",
+			Name:      "top_n_exec_count",
+			Help:      "Execution count of the top N SQL in the last report interval of top-sql.",
+		}, []string{LblSQLDigest, LblPlanDigest})
 }
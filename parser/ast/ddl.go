@@ -36,6 +36,9 @@ var (
 	_ DDLNode = &CreateIndexStmt{}
 	_ DDLNode = &CreateTableStmt{}
 	_ DDLNode = &CreateViewStmt{}
+	_ DDLNode = &CreateMaterializedViewStmt{}
+	_ DDLNode = &CreateMaskingPolicyStmt{}
+	_ DDLNode = &CreateRowAccessPolicyStmt{}
 	_ DDLNode = &CreateSequenceStmt{}
 	_ DDLNode = &CreatePlacementPolicyStmt{}
 	_ DDLNode = &CreateResourceGroupStmt{}
@@ -43,6 +46,9 @@ var (
 	_ DDLNode = &FlashBackDatabaseStmt{}
 	_ DDLNode = &DropIndexStmt{}
 	_ DDLNode = &DropTableStmt{}
+	_ DDLNode = &DropMaterializedViewStmt{}
+	_ DDLNode = &DropMaskingPolicyStmt{}
+	_ DDLNode = &DropRowAccessPolicyStmt{}
 	_ DDLNode = &DropSequenceStmt{}
 	_ DDLNode = &DropPlacementPolicyStmt{}
 	_ DDLNode = &DropResourceGroupStmt{}
@@ -713,6 +719,8 @@ type IndexOption struct {
 	ParserName   model.CIStr
 	Visibility   IndexVisibility
 	PrimaryKeyTp model.PrimaryKeyType
+	// Global indicates the index is a global index of a partitioned table.
+	Global bool
 }
 
 // Restore implements Node interface.
@@ -771,6 +779,17 @@ func (n *IndexOption) Restore(ctx *format.RestoreCtx) error {
 		case IndexVisibilityInvisible:
 			ctx.WriteKeyWord("INVISIBLE")
 		}
+		hasPrevOption = true
+	}
+
+	if n.Global {
+		if hasPrevOption {
+			ctx.WritePlain(" ")
+		}
+		_ = ctx.WriteWithSpecialComments(tidb.FeatureIDGlobalIndex, func() error {
+			ctx.WriteKeyWord("GLOBAL")
+			return nil
+		})
 	}
 	return nil
 }
@@ -1538,6 +1557,307 @@ func (n *CreateViewStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// CreateMaterializedViewStmt is a statement to create a materialized view.
+// See https://docs.oracle.com/en/database/oracle/oracle-database/19/sqlrf/CREATE-MATERIALIZED-VIEW.html
+type CreateMaterializedViewStmt struct {
+	ddlNode
+
+	IfNotExists bool
+	ViewName    *TableName
+	Cols        []model.CIStr
+	// RefreshSchedule is the schedule to refresh the view, it's nil if the view is only refreshed manually.
+	RefreshSchedule *TimerOption
+	Select          StmtNode
+	// SchemaCols and SchemaColTypes are the output columns of the select
+	// statement, they are filled by the planner.
+	SchemaCols     []model.CIStr
+	SchemaColTypes []*types.FieldType
+}
+
+// Restore implements Node interface.
+func (n *CreateMaterializedViewStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE MATERIALIZED VIEW ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	if err := n.ViewName.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateMaterializedViewStmt.ViewName")
+	}
+	for i, col := range n.Cols {
+		if i == 0 {
+			ctx.WritePlain(" (")
+		} else {
+			ctx.WritePlain(",")
+		}
+		ctx.WriteName(col.O)
+		if i == len(n.Cols)-1 {
+			ctx.WritePlain(")")
+		}
+	}
+	if n.RefreshSchedule != nil {
+		ctx.WriteKeyWord(" REFRESH ")
+		if err := n.RefreshSchedule.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore CreateMaterializedViewStmt.RefreshSchedule")
+		}
+	}
+	ctx.WriteKeyWord(" AS ")
+	if err := n.Select.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateMaterializedViewStmt.Select")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateMaterializedViewStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateMaterializedViewStmt)
+	node, ok := n.ViewName.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.ViewName = node.(*TableName)
+	selnode, ok := n.Select.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Select = selnode.(StmtNode)
+	return v.Leave(n)
+}
+
+// DropMaterializedViewStmt is a statement to drop a materialized view.
+type DropMaterializedViewStmt struct {
+	ddlNode
+
+	IfExists bool
+	ViewName *TableName
+}
+
+// Restore implements Node interface.
+func (n *DropMaterializedViewStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP MATERIALIZED VIEW ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	if err := n.ViewName.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore DropMaterializedViewStmt.ViewName")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropMaterializedViewStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropMaterializedViewStmt)
+	node, ok := n.ViewName.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.ViewName = node.(*TableName)
+	return v.Leave(n)
+}
+
+// CreateMaskingPolicyStmt is a statement to create a masking policy on a column.
+type CreateMaskingPolicyStmt struct {
+	ddlNode
+
+	IfNotExists bool
+	PolicyName  model.CIStr
+	Table       *TableName
+	Column      *ColumnName
+	MaskingType model.MaskingType
+	Args        []ExprNode
+}
+
+// Restore implements Node interface.
+func (n *CreateMaskingPolicyStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE MASKING POLICY ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	ctx.WriteName(n.PolicyName.O)
+	ctx.WriteKeyWord(" ON ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateMaskingPolicyStmt.Table")
+	}
+	ctx.WritePlain(" (")
+	if err := n.Column.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateMaskingPolicyStmt.Column")
+	}
+	ctx.WritePlain(")")
+	ctx.WriteKeyWord(" AS ")
+	ctx.WriteKeyWord(string(n.MaskingType))
+	ctx.WritePlain("(")
+	for i, arg := range n.Args {
+		if i > 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := arg.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore CreateMaskingPolicyStmt.Args[%d]", i)
+		}
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateMaskingPolicyStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateMaskingPolicyStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	node, ok = n.Column.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Column = node.(*ColumnName)
+	for i, arg := range n.Args {
+		node, ok = arg.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Args[i] = node.(ExprNode)
+	}
+	return v.Leave(n)
+}
+
+// DropMaskingPolicyStmt is a statement to drop a masking policy of a table.
+type DropMaskingPolicyStmt struct {
+	ddlNode
+
+	IfExists   bool
+	PolicyName model.CIStr
+	Table      *TableName
+}
+
+// Restore implements Node interface.
+func (n *DropMaskingPolicyStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP MASKING POLICY ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	ctx.WriteName(n.PolicyName.O)
+	ctx.WriteKeyWord(" ON ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore DropMaskingPolicyStmt.Table")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropMaskingPolicyStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropMaskingPolicyStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	return v.Leave(n)
+}
+
+// CreateRowAccessPolicyStmt is a statement to create a row access policy on a table.
+type CreateRowAccessPolicyStmt struct {
+	ddlNode
+
+	IfNotExists bool
+	PolicyName  model.CIStr
+	Table       *TableName
+	Expr        ExprNode
+}
+
+// Restore implements Node interface.
+func (n *CreateRowAccessPolicyStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE ROW ACCESS POLICY ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	ctx.WriteName(n.PolicyName.O)
+	ctx.WriteKeyWord(" ON ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateRowAccessPolicyStmt.Table")
+	}
+	ctx.WriteKeyWord(" USING ")
+	ctx.WritePlain("(")
+	if err := n.Expr.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateRowAccessPolicyStmt.Expr")
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateRowAccessPolicyStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateRowAccessPolicyStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	node, ok = n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// DropRowAccessPolicyStmt is a statement to drop a row access policy of a table.
+type DropRowAccessPolicyStmt struct {
+	ddlNode
+
+	IfExists   bool
+	PolicyName model.CIStr
+	Table      *TableName
+}
+
+// Restore implements Node interface.
+func (n *DropRowAccessPolicyStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP ROW ACCESS POLICY ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	ctx.WriteName(n.PolicyName.O)
+	ctx.WriteKeyWord(" ON ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore DropRowAccessPolicyStmt.Table")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropRowAccessPolicyStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropRowAccessPolicyStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	return v.Leave(n)
+}
+
 // CreatePlacementPolicyStmt is a statement to create a policy.
 type CreatePlacementPolicyStmt struct {
 	ddlNode
@@ -1776,7 +2096,7 @@ func (n *CreateIndexStmt) Restore(ctx *format.RestoreCtx) error {
 	}
 	ctx.WritePlain(")")
 
-	if n.IndexOption.Tp != model.IndexTypeInvalid || n.IndexOption.KeyBlockSize > 0 || n.IndexOption.Comment != "" || len(n.IndexOption.ParserName.O) > 0 || n.IndexOption.Visibility != IndexVisibilityDefault {
+	if n.IndexOption.Tp != model.IndexTypeInvalid || n.IndexOption.KeyBlockSize > 0 || n.IndexOption.Comment != "" || len(n.IndexOption.ParserName.O) > 0 || n.IndexOption.Visibility != IndexVisibilityDefault || n.IndexOption.Global {
 		ctx.WritePlain(" ")
 		if err := n.IndexOption.Restore(ctx); err != nil {
 			return errors.Annotate(err, "An error occurred while restore CreateIndexStmt.IndexOption")
@@ -2131,6 +2451,7 @@ type ResourceGroupOption struct {
 	BoolValue         bool
 	RunawayOptionList []*ResourceGroupRunawayOption
 	BackgroundOptions []*ResourceGroupBackgroundOption
+	QueueOptions      []*ResourceGroupQueueOption
 }
 
 type ResourceUnitType int
@@ -2148,6 +2469,7 @@ const (
 	ResourceBurstableOpiton
 	ResourceGroupRunaway
 	ResourceGroupBackground
+	ResourceGroupQueue
 )
 
 func (n *ResourceGroupOption) Restore(ctx *format.RestoreCtx) error {
@@ -2210,6 +2532,23 @@ func (n *ResourceGroupOption) Restore(ctx *format.RestoreCtx) error {
 		} else {
 			ctx.WritePlain("NULL")
 		}
+	case ResourceGroupQueue:
+		ctx.WritePlain("QUEUE ")
+		ctx.WritePlain("= ")
+		if len(n.QueueOptions) > 0 {
+			ctx.WritePlain("(")
+			for i, option := range n.QueueOptions {
+				if i > 0 {
+					ctx.WritePlain(", ")
+				}
+				if err := option.Restore(ctx); err != nil {
+					return errors.Annotatef(err, "An error occurred while splicing ResourceGroup Queue Option: [%v]", option)
+				}
+			}
+			ctx.WritePlain(")")
+		} else {
+			ctx.WritePlain("NULL")
+		}
 	default:
 		return errors.Errorf("invalid ResourceGroupOption: %d", n.Tp)
 	}
@@ -2222,13 +2561,18 @@ const (
 	RunawayRule RunawayOptionType = iota
 	RunawayAction
 	RunawayWatch
+	RunawayProcessedKeys
+	RunawayRequestUnit
+	RunawayResultRows
+	RunawayMemory
 )
 
 // ResourceGroupRunawayOption is used for parsing resource group runaway rule option.
 type ResourceGroupRunawayOption struct {
-	Tp       RunawayOptionType
-	StrValue string
-	IntValue int32
+	Tp        RunawayOptionType
+	StrValue  string
+	IntValue  int32
+	UintValue uint64
 }
 
 func (n *ResourceGroupRunawayOption) Restore(ctx *format.RestoreCtx) error {
@@ -2249,6 +2593,18 @@ func (n *ResourceGroupRunawayOption) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("DURATION ")
 		ctx.WritePlain("= ")
 		ctx.WriteString(n.StrValue)
+	case RunawayProcessedKeys:
+		ctx.WriteKeyWord("PROCESSED_KEYS ")
+		ctx.WritePlainf("= %d", n.UintValue)
+	case RunawayRequestUnit:
+		ctx.WriteKeyWord("RU ")
+		ctx.WritePlainf("= %d", n.UintValue)
+	case RunawayResultRows:
+		ctx.WriteKeyWord("RESULT_ROWS ")
+		ctx.WritePlainf("= %d", n.UintValue)
+	case RunawayMemory:
+		ctx.WriteKeyWord("MEMORY ")
+		ctx.WritePlainf("= %d", n.UintValue)
 	default:
 		return errors.Errorf("invalid ResourceGroupRunawayOption: %d", n.Tp)
 	}
@@ -2281,6 +2637,43 @@ func (n *ResourceGroupBackgroundOption) Restore(ctx *format.RestoreCtx) error {
 	return nil
 }
 
+type QueueOptionType int
+
+const (
+	QueueOptionNone QueueOptionType = iota
+	QueueOptionMaxConcurrency
+	QueueOptionMaxQueueLength
+	QueueOptionTimeout
+)
+
+// ResourceGroupQueueOption is used to config the admission queue of the resource group.
+type ResourceGroupQueueOption struct {
+	Type      QueueOptionType
+	UintValue uint64
+	StrValue  string
+}
+
+func (n *ResourceGroupQueueOption) Restore(ctx *format.RestoreCtx) error {
+	switch n.Type {
+	case QueueOptionMaxConcurrency:
+		ctx.WriteKeyWord("MAX_CONCURRENCY ")
+		ctx.WritePlain("= ")
+		ctx.WritePlainf("%d", n.UintValue)
+	case QueueOptionMaxQueueLength:
+		ctx.WriteKeyWord("MAX_QUEUE_LENGTH ")
+		ctx.WritePlain("= ")
+		ctx.WritePlainf("%d", n.UintValue)
+	case QueueOptionTimeout:
+		ctx.WriteKeyWord("QUEUE_TIMEOUT ")
+		ctx.WritePlain("= ")
+		ctx.WriteString(n.StrValue)
+	default:
+		return errors.Errorf("unknown ResourceGroupQueueOption: %d", n.Type)
+	}
+
+	return nil
+}
+
 type StatsOptionType int
 
 const (
@@ -2334,6 +2727,9 @@ const (
 	TableOptionTTL
 	TableOptionTTLEnable
 	TableOptionTTLJobInterval
+	TableOptionPartitionInterval
+	TableOptionPartitionPrecreate
+	TableOptionPartitionRetention
 	TableOptionPlacementPolicy = TableOptionType(PlacementOptionPolicy)
 	TableOptionStatsBuckets    = TableOptionType(StatsOptionBuckets)
 	TableOptionStatsTopN       = TableOptionType(StatsOptionTopN)
@@ -2699,6 +3095,28 @@ func (n *TableOption) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteString(n.StrValue)
 			return nil
 		})
+	case TableOptionPartitionInterval, TableOptionPartitionRetention:
+		return ctx.WriteWithSpecialComments(tidb.FeatureIDIntervalPartition, func() error {
+			if n.Tp == TableOptionPartitionInterval {
+				ctx.WriteKeyWord("PARTITION_INTERVAL ")
+			} else {
+				ctx.WriteKeyWord("PARTITION_RETENTION ")
+			}
+			ctx.WritePlain("= ")
+			ctx.WriteKeyWord("INTERVAL ")
+			if err := n.Value.Restore(ctx); err != nil {
+				return err
+			}
+			ctx.WritePlain(" ")
+			return n.TimeUnitValue.Restore(ctx)
+		})
+	case TableOptionPartitionPrecreate:
+		_ = ctx.WriteWithSpecialComments(tidb.FeatureIDIntervalPartition, func() error {
+			ctx.WriteKeyWord("PARTITION_PRECREATE ")
+			ctx.WritePlain("= ")
+			ctx.WritePlainf("%d", n.UintValue)
+			return nil
+		})
 	default:
 		return errors.Errorf("invalid TableOption: %d", n.Tp)
 	}
@@ -2918,6 +3336,7 @@ const (
 	AlterTableReorganizeLastPartition
 	AlterTableReorganizeFirstPartition
 	AlterTableRemoveTTL
+	AlterTableRemovePartitionInterval
 )
 
 // LockType is the type for AlterTableSpec.
@@ -3605,6 +4024,11 @@ func (n *AlterTableSpec) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteKeyWord("REMOVE TTL")
 			return nil
 		})
+	case AlterTableRemovePartitionInterval:
+		_ = ctx.WriteWithSpecialComments(tidb.FeatureIDIntervalPartition, func() error {
+			ctx.WriteKeyWord("REMOVE PARTITION_INTERVAL")
+			return nil
+		})
 	default:
 		// TODO: not support
 		ctx.WritePlainf(" /* AlterTableType(%d) is not supported */ ", n.Tp)
@@ -4602,6 +5026,15 @@ func CheckBackgroundAppend(ops []*ResourceGroupBackgroundOption, newOp *Resource
 	return true
 }
 
+func CheckQueueAppend(ops []*ResourceGroupQueueOption, newOp *ResourceGroupQueueOption) bool {
+	for _, op := range ops {
+		if op.Type == newOp.Type {
+			return false
+		}
+	}
+	return true
+}
+
 // AlterResourceGroupStmt is a statement to alter placement policy option.
 type AlterResourceGroupStmt struct {
 	ddlNode
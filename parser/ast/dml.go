@@ -609,6 +609,61 @@ func (n *TableSource) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// TableChanges is the TABLE_CHANGES table function which returns the row changes of a table
+// committed in the range (StartTS, EndTS].
+type TableChanges struct {
+	node
+
+	Table   *TableName
+	StartTS ExprNode
+	EndTS   ExprNode
+}
+
+func (*TableChanges) resultSet() {}
+
+// Restore implements Node interface.
+func (n *TableChanges) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("TABLE_CHANGES")
+	ctx.WritePlain("(")
+	n.Table.restoreName(ctx)
+	n.Table.restorePartitions(ctx)
+	ctx.WritePlain(", ")
+	if err := n.StartTS.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore TableChanges.StartTS")
+	}
+	ctx.WritePlain(", ")
+	if err := n.EndTS.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore TableChanges.EndTS")
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *TableChanges) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*TableChanges)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	node, ok = n.StartTS.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.StartTS = node.(ExprNode)
+	node, ok = n.EndTS.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.EndTS = node.(ExprNode)
+	return v.Leave(n)
+}
+
 // SelectLockType is the lock type for SelectStmt.
 type SelectLockType int
 
@@ -2977,6 +3032,7 @@ const (
 	ShowCreateResourceGroup
 	ShowImportJobs
 	ShowCreateProcedure
+	ShowTimers
 )
 
 const (
@@ -3356,6 +3412,9 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteKeyWord("PLACEMENT LABELS")
 		case ShowSessionStates:
 			ctx.WriteKeyWord("SESSION_STATES")
+		case ShowTimers:
+			ctx.WriteKeyWord("TIMERS")
+			restoreShowDatabaseNameOpt()
 		default:
 			return errors.New("Unknown ShowStmt type")
 		}
@@ -269,6 +269,7 @@ const (
 	FormatBytes          = "format_bytes"
 	FormatNanoTime       = "format_nano_time"
 	CurrentResourceGroup = "current_resource_group"
+	CurrentTenant        = "current_tenant"
 
 	// control functions
 	If     = "if"
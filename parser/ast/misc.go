@@ -60,6 +60,7 @@ var (
 	_ StmtNode = &PlanReplayerStmt{}
 	_ StmtNode = &CompactTableStmt{}
 	_ StmtNode = &SetResourceGroupStmt{}
+	_ StmtNode = &RefreshMaterializedViewStmt{}
 
 	_ Node = &PrivElem{}
 	_ Node = &VariableAssignment{}
@@ -3555,6 +3556,237 @@ func (n *ImportIntoActionStmt) Restore(ctx *format.RestoreCtx) error {
 	return nil
 }
 
+// TimerOptionType is the type of the option in CREATE/ALTER TIMER statement.
+type TimerOptionType int
+
+// TimerOption types.
+const (
+	TimerOptionSchedule TimerOptionType = iota
+	TimerOptionTimeZone
+	TimerOptionEnable
+	TimerOptionComment
+)
+
+// Schedule policies of the timer.
+const (
+	TimerSchedPolicyInterval = "INTERVAL"
+	TimerSchedPolicyCron     = "CRON"
+)
+
+// TimerOption is the option in CREATE/ALTER TIMER statement.
+type TimerOption struct {
+	Tp TimerOptionType
+	// SchedPolicyType is the schedule policy when Tp is TimerOptionSchedule.
+	SchedPolicyType string
+	StrValue        string
+	BoolValue       bool
+}
+
+// Restore implements Node interface.
+func (n *TimerOption) Restore(ctx *format.RestoreCtx) error {
+	switch n.Tp {
+	case TimerOptionSchedule:
+		ctx.WriteKeyWord("SCHEDULE ")
+		ctx.WriteKeyWord(n.SchedPolicyType)
+		ctx.WritePlain(" ")
+		ctx.WriteString(n.StrValue)
+	case TimerOptionTimeZone:
+		ctx.WriteKeyWord("TIME ZONE ")
+		ctx.WriteString(n.StrValue)
+	case TimerOptionEnable:
+		if n.BoolValue {
+			ctx.WriteKeyWord("ENABLE")
+		} else {
+			ctx.WriteKeyWord("DISABLE")
+		}
+	case TimerOptionComment:
+		ctx.WriteKeyWord("COMMENT ")
+		ctx.WriteString(n.StrValue)
+	default:
+		return errors.Errorf("invalid TimerOption: %d", n.Tp)
+	}
+	return nil
+}
+
+func restoreTimerOptionsAndBody(ctx *format.RestoreCtx, options []*TimerOption, body StmtNode) error {
+	for i, option := range options {
+		ctx.WritePlain(" ")
+		if err := option.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore TimerOptions[%d]", i)
+		}
+	}
+
+	if body != nil {
+		ctx.WriteKeyWord(" DO ")
+		if err := body.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore timer body")
+		}
+	}
+	return nil
+}
+
+// CreateTimerStmt is a statement to create a user-defined timer which executes the statement in Body periodically.
+type CreateTimerStmt struct {
+	stmtNode
+
+	IfNotExists bool
+	Name        *TableName
+	Options     []*TimerOption
+	// Body is the statement to execute. It is not visited by Accept because it is executed
+	// later by the timer with the schema of the timer as the current database.
+	Body StmtNode
+}
+
+// Restore implements Node interface.
+func (n *CreateTimerStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("CREATE TIMER ")
+	if n.IfNotExists {
+		ctx.WriteKeyWord("IF NOT EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore CreateTimerStmt.Name")
+	}
+	return restoreTimerOptionsAndBody(ctx, n.Options, n.Body)
+}
+
+// Accept implements Node Accept interface.
+func (n *CreateTimerStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*CreateTimerStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	return v.Leave(n)
+}
+
+// AlterTimerStmt is a statement to alter the options or the body of a user-defined timer.
+type AlterTimerStmt struct {
+	stmtNode
+
+	IfExists bool
+	Name     *TableName
+	Options  []*TimerOption
+	// Body is nil if the statement of the timer is not changed.
+	Body StmtNode
+}
+
+// Restore implements Node interface.
+func (n *AlterTimerStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("ALTER TIMER ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore AlterTimerStmt.Name")
+	}
+	return restoreTimerOptionsAndBody(ctx, n.Options, n.Body)
+}
+
+// Accept implements Node Accept interface.
+func (n *AlterTimerStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*AlterTimerStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	return v.Leave(n)
+}
+
+// DropTimerStmt is a statement to drop a user-defined timer.
+type DropTimerStmt struct {
+	stmtNode
+
+	IfExists bool
+	Name     *TableName
+}
+
+// Restore implements Node interface.
+func (n *DropTimerStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DROP TIMER ")
+	if n.IfExists {
+		ctx.WriteKeyWord("IF EXISTS ")
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore DropTimerStmt.Name")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DropTimerStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*DropTimerStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	return v.Leave(n)
+}
+
+// TimerActionTp is the type of the action in PAUSE/RESUME TIMER statement.
+type TimerActionTp int
+
+// TimerAction types.
+const (
+	// TimerActionPause disables the timer so that no new event is triggered.
+	TimerActionPause TimerActionTp = iota
+	// TimerActionResume enables the timer again.
+	TimerActionResume
+)
+
+// TimerActionStmt represent PAUSE/RESUME TIMER statement.
+type TimerActionStmt struct {
+	stmtNode
+
+	Tp   TimerActionTp
+	Name *TableName
+}
+
+// Restore implements Node interface.
+func (n *TimerActionStmt) Restore(ctx *format.RestoreCtx) error {
+	switch n.Tp {
+	case TimerActionPause:
+		ctx.WriteKeyWord("PAUSE TIMER ")
+	case TimerActionResume:
+		ctx.WriteKeyWord("RESUME TIMER ")
+	default:
+		return errors.Errorf("invalid timer action type: %d", n.Tp)
+	}
+	if err := n.Name.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore TimerActionStmt.Name")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *TimerActionStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*TimerActionStmt)
+	node, ok := n.Name.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Name = node.(*TableName)
+	return v.Leave(n)
+}
+
 // Ident is the table identifier composed of schema name and table name.
 type Ident struct {
 	Schema model.CIStr
@@ -3928,3 +4160,53 @@ func (n *DynamicCalibrateResourceOption) Accept(v Visitor) (Node, bool) {
 	}
 	return v.Leave(n)
 }
+
+// RefreshMaterializedViewType is the type of refreshing a materialized view.
+type RefreshMaterializedViewType int
+
+const (
+	// RefreshMaterializedViewDefault refreshes the view incrementally if it's possible.
+	RefreshMaterializedViewDefault RefreshMaterializedViewType = iota
+	// RefreshMaterializedViewComplete recomputes the whole view.
+	RefreshMaterializedViewComplete
+	// RefreshMaterializedViewIncremental applies the changes of the base table to the view.
+	RefreshMaterializedViewIncremental
+)
+
+// RefreshMaterializedViewStmt is a statement to refresh the data of a materialized view.
+type RefreshMaterializedViewStmt struct {
+	stmtNode
+
+	ViewName *TableName
+	Tp       RefreshMaterializedViewType
+}
+
+// Restore implements Node interface.
+func (n *RefreshMaterializedViewStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("REFRESH MATERIALIZED VIEW ")
+	if err := n.ViewName.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore RefreshMaterializedViewStmt.ViewName")
+	}
+	switch n.Tp {
+	case RefreshMaterializedViewComplete:
+		ctx.WriteKeyWord(" COMPLETE")
+	case RefreshMaterializedViewIncremental:
+		ctx.WriteKeyWord(" INCREMENTAL")
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *RefreshMaterializedViewStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*RefreshMaterializedViewStmt)
+	node, ok := n.ViewName.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.ViewName = node.(*TableName)
+	return v.Leave(n)
+}
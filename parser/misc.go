@@ -145,6 +145,7 @@ func isInTokenMap(target string) bool {
 // tokenMap is a map of known identifiers to the parser token ID.
 // Please try to keep the map in alphabetical order.
 var tokenMap = map[string]int{
+	"ACCESS":                   access,
 	"ACCOUNT":                  account,
 	"ACTION":                   action,
 	"ADD":                      add,
@@ -249,6 +250,7 @@ var tokenMap = map[string]int{
 	"COMMIT":                   commit,
 	"COMMITTED":                committed,
 	"COMPACT":                  compact,
+	"COMPLETE":                 complete,
 	"COMPRESSED":               compressed,
 	"COMPRESSION":              compression,
 	"CONCURRENCY":              concurrency,
@@ -266,6 +268,7 @@ var tokenMap = map[string]int{
 	"CORRELATION":              correlation,
 	"CPU":                      cpu,
 	"CREATE":                   create,
+	"CRON":                     cron,
 	"CROSS":                    cross,
 	"CSV_BACKSLASH_ESCAPE":     csvBackslashEscape,
 	"CSV_DELIMITER":            csvDelimiter,
@@ -497,12 +500,16 @@ var tokenMap = map[string]int{
 	"LONGBLOB":                 longblobType,
 	"LONGTEXT":                 longtextType,
 	"LOW_PRIORITY":             lowPriority,
+	"MASKING":                  masking,
 	"MASTER":                   master,
 	"MATCH":                    match,
+	"MATERIALIZED":             materialized,
+	"MAX_CONCURRENCY":          maxConcurrency,
 	"MAX_CONNECTIONS_PER_HOUR": maxConnectionsPerHour,
 	"MAX_IDXNUM":               max_idxnum,
 	"MAX_MINUTES":              max_minutes,
 	"MAX_QUERIES_PER_HOUR":     maxQueriesPerHour,
+	"MAX_QUEUE_LENGTH":         maxQueueLength,
 	"MAX_ROWS":                 maxRows,
 	"MAX_UPDATES_PER_HOUR":     maxUpdatesPerHour,
 	"MAX_USER_CONNECTIONS":     maxUserConnections,
@@ -580,6 +587,9 @@ var tokenMap = map[string]int{
 	"PARSER":                   parser,
 	"PARTIAL":                  partial,
 	"PARTITION":                partition,
+	"PARTITION_INTERVAL":       partitionInterval,
+	"PARTITION_PRECREATE":      partitionPrecreate,
+	"PARTITION_RETENTION":      partitionRetention,
 	"PARTITIONING":             partitioning,
 	"PARTITIONS":               partitions,
 	"PASSWORD":                 password,
@@ -606,6 +616,7 @@ var tokenMap = map[string]int{
 	"PRIVILEGES":               privileges,
 	"PROCEDURE":                procedure,
 	"PROCESS":                  process,
+	"PROCESSED_KEYS":           processedKeys,
 	"PROCESSLIST":              processlist,
 	"PROFILE":                  profile,
 	"PROFILES":                 profiles,
@@ -616,6 +627,8 @@ var tokenMap = map[string]int{
 	"QUERIES":                  queries,
 	"QUERY":                    query,
 	"QUERY_LIMIT":              queryLimit,
+	"QUEUE":                    queue,
+	"QUEUE_TIMEOUT":            queueTimeout,
 	"QUICK":                    quick,
 	"RANGE":                    rangeKwd,
 	"RATE_LIMIT":               rateLimit,
@@ -627,6 +640,7 @@ var tokenMap = map[string]int{
 	"RECURSIVE":                recursive,
 	"REDUNDANT":                redundant,
 	"REFERENCES":               references,
+	"REFRESH":                  refresh,
 	"REGEXP":                   regexpKwd,
 	"REGION":                   region,
 	"REGIONS":                  regions,
@@ -653,6 +667,7 @@ var tokenMap = map[string]int{
 	"RESTORES":                 restores,
 	"RESTORED_TS":              restoredTS,
 	"RESTRICT":                 restrict,
+	"RESULT_ROWS":              resultRows,
 	"REVERSE":                  reverse,
 	"REVOKE":                   revoke,
 	"RIGHT":                    right,
@@ -666,6 +681,7 @@ var tokenMap = map[string]int{
 	"ROW":                      row,
 	"ROWS":                     rows,
 	"RTREE":                    rtree,
+	"RU":                       requestUnit,
 	"HYPO":                     hypo,
 	"RESUME":                   resume,
 	"RUN":                      run,
@@ -776,6 +792,7 @@ var tokenMap = map[string]int{
 	"SYSTEM_TIME":              systemTime,
 	"TARGET":                   target,
 	"TASK_TYPES":               taskTypes,
+	"TABLE_CHANGES":            tableChanges,
 	"TABLE_CHECKSUM":           tableChecksum,
 	"TABLE":                    tableKwd,
 	"TABLES":                   tables,
@@ -795,6 +812,8 @@ var tokenMap = map[string]int{
 	"TIFLASH":                  tiFlash,
 	"TIKV_IMPORTER":            tikvImporter,
 	"TIME":                     timeType,
+	"TIMER":                    timer,
+	"TIMERS":                   timers,
 	"TIMESTAMP":                timestampType,
 	"TIMESTAMPADD":             timestampAdd,
 	"TIMESTAMPDIFF":            timestampDiff,
@@ -884,6 +903,7 @@ var tokenMap = map[string]int{
 	"YEAR_MONTH":               yearMonth,
 	"YEAR":                     yearType,
 	"ZEROFILL":                 zerofill,
+	"ZONE":                     zone,
 	"WAIT":                     wait,
 	"FAILED_LOGIN_ATTEMPTS":    failedLoginAttempts,
 	"PASSWORD_LOCK_TIME":       passwordLockTime,
@@ -102,6 +102,15 @@ const (
 	ActionCreateResourceGroup           ActionType = 68
 	ActionAlterResourceGroup            ActionType = 69
 	ActionDropResourceGroup             ActionType = 70
+	ActionCreateMaterializedView        ActionType = 71
+	ActionDropMaterializedView          ActionType = 72
+	ActionCreateMaskingPolicy           ActionType = 73
+	ActionDropMaskingPolicy             ActionType = 74
+	ActionCreateRowAccessPolicy         ActionType = 75
+	ActionDropRowAccessPolicy           ActionType = 76
+	ActionAlterTablePartitioning        ActionType = 77
+	ActionRemovePartitioning            ActionType = 78
+	ActionAlterIntervalPartition        ActionType = 79
 )
 
 var actionMap = map[ActionType]string{
@@ -170,6 +179,15 @@ var actionMap = map[ActionType]string{
 	ActionCreateResourceGroup:           "create resource group",
 	ActionAlterResourceGroup:            "alter resource group",
 	ActionDropResourceGroup:             "drop resource group",
+	ActionCreateMaterializedView:        "create materialized view",
+	ActionDropMaterializedView:          "drop materialized view",
+	ActionCreateMaskingPolicy:           "create masking policy",
+	ActionDropMaskingPolicy:             "drop masking policy",
+	ActionCreateRowAccessPolicy:         "create row access policy",
+	ActionDropRowAccessPolicy:           "drop row access policy",
+	ActionAlterTablePartitioning:        "alter table partition by",
+	ActionRemovePartitioning:            "alter table remove partitioning",
+	ActionAlterIntervalPartition:        "alter table interval partition",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
@@ -797,7 +815,8 @@ func (job *Job) NotStarted() bool {
 // MayNeedReorg indicates that this job may need to reorganize the data.
 func (job *Job) MayNeedReorg() bool {
 	switch job.Type {
-	case ActionAddIndex, ActionAddPrimaryKey, ActionReorganizePartition:
+	case ActionAddIndex, ActionAddPrimaryKey, ActionReorganizePartition,
+		ActionAlterTablePartitioning, ActionRemovePartitioning:
 		return true
 	case ActionModifyColumn:
 		if len(job.CtxVars) > 0 {
@@ -521,6 +521,22 @@ type TableInfo struct {
 	ExchangePartitionInfo *ExchangePartitionInfo `json:"exchange_partition_info"`
 
 	TTLInfo *TTLInfo `json:"ttl_info"`
+
+	// IntervalPartitionInfo is not nil if the partitions of the table are maintained automatically by time.
+	IntervalPartitionInfo *IntervalPartitionInfo `json:"interval_partition_info,omitempty"`
+
+	// MaterializedView is not nil if the table stores the data of a materialized view.
+	MaterializedView *MaterializedViewInfo `json:"materialized_view"`
+	// MaterializedViewLogs are the change logs of the table which are used to
+	// refresh the materialized views incrementally.
+	MaterializedViewLogs []*MaterializedViewLogInfo `json:"materialized_view_logs"`
+
+	// MaskingPolicies are the masking policies bound to the columns of the table.
+	MaskingPolicies []*MaskingPolicyInfo `json:"masking_policies,omitempty"`
+
+	// RowAccessPolicies are the row access policies of the table, the rows which don't satisfy all of
+	// them are invisible to the users without the ROW_ACCESS_POLICY_EXEMPT privilege.
+	RowAccessPolicies []*RowAccessPolicyInfo `json:"row_access_policies,omitempty"`
 }
 
 // SepAutoInc decides whether _rowid and auto_increment id use separate allocator.
@@ -732,10 +748,64 @@ func (t *TableInfo) Clone() *TableInfo {
 	if t.TTLInfo != nil {
 		nt.TTLInfo = t.TTLInfo.Clone()
 	}
+	if t.IntervalPartitionInfo != nil {
+		nt.IntervalPartitionInfo = t.IntervalPartitionInfo.Clone()
+	}
+	if t.MaterializedView != nil {
+		nt.MaterializedView = t.MaterializedView.Clone()
+	}
+	if t.MaterializedViewLogs != nil {
+		nt.MaterializedViewLogs = make([]*MaterializedViewLogInfo, len(t.MaterializedViewLogs))
+		for i, log := range t.MaterializedViewLogs {
+			nt.MaterializedViewLogs[i] = log.Clone()
+		}
+	}
+	if t.MaskingPolicies != nil {
+		nt.MaskingPolicies = make([]*MaskingPolicyInfo, len(t.MaskingPolicies))
+		for i, policy := range t.MaskingPolicies {
+			nt.MaskingPolicies[i] = policy.Clone()
+		}
+	}
+	if t.RowAccessPolicies != nil {
+		nt.RowAccessPolicies = make([]*RowAccessPolicyInfo, len(t.RowAccessPolicies))
+		for i, policy := range t.RowAccessPolicies {
+			nt.RowAccessPolicies[i] = policy.Clone()
+		}
+	}
 
 	return &nt
 }
 
+// FindMaskingPolicy finds the masking policy by its name.
+func (t *TableInfo) FindMaskingPolicy(name string) *MaskingPolicyInfo {
+	for _, policy := range t.MaskingPolicies {
+		if policy.Name.L == name {
+			return policy
+		}
+	}
+	return nil
+}
+
+// FindMaskingPolicyByColumnID finds the masking policy bound to the column.
+func (t *TableInfo) FindMaskingPolicyByColumnID(colID int64) *MaskingPolicyInfo {
+	for _, policy := range t.MaskingPolicies {
+		if policy.ColumnID == colID {
+			return policy
+		}
+	}
+	return nil
+}
+
+// FindRowAccessPolicy finds the row access policy by its name.
+func (t *TableInfo) FindRowAccessPolicy(name string) *RowAccessPolicyInfo {
+	for _, policy := range t.RowAccessPolicies {
+		if policy.Name.L == name {
+			return policy
+		}
+	}
+	return nil
+}
+
 // GetPkName will return the pk name if pk exists.
 func (t *TableInfo) GetPkName() CIStr {
 	for _, colInfo := range t.Columns {
@@ -1116,6 +1186,9 @@ type PartitionType int
 
 // Partition types.
 const (
+	// PartitionTypeNone is only used during ALTER TABLE ... PARTITION BY and
+	// REMOVE PARTITIONING, for a non-partitioned table seen as a table with a single partition.
+	PartitionTypeNone       PartitionType = 0
 	PartitionTypeRange      PartitionType = 1
 	PartitionTypeHash       PartitionType = 2
 	PartitionTypeList       PartitionType = 3
@@ -1145,6 +1218,9 @@ type ExchangePartitionInfo struct {
 	ExchangePartitionFlag  bool  `json:"exchange_partition_flag"`
 	ExchangePartitionID    int64 `json:"exchange_partition_id"`
 	ExchangePartitionDefID int64 `json:"exchange_partition_def_id"`
+	// ReadOnly is set when exchanging a partition of a table with global indexes,
+	// the table cannot be written until the global indexes are rebuilt.
+	ReadOnly bool `json:"read_only,omitempty"`
 }
 
 // PartitionInfo provides table partition info.
@@ -1170,6 +1246,25 @@ type PartitionInfo struct {
 	Num    uint64           `json:"num"`
 	// Only used during ReorganizePartition so far
 	DDLState SchemaState `json:"ddl_state"`
+	// DDLType, DDLExpr and DDLColumns are the partitioning of the AddingDefinitions
+	// during ReorganizePartition and ALTER TABLE ... PARTITION BY, and the ones of
+	// the DroppingDefinitions after the new partitioning has been switched in.
+	DDLType    PartitionType `json:"ddl_type"`
+	DDLExpr    string        `json:"ddl_expr"`
+	DDLColumns []CIStr       `json:"ddl_columns"`
+	// NewTableID is the table ID used after ALTER TABLE ... PARTITION BY and
+	// REMOVE PARTITIONING, since the old table ID may be the ID of a partition to drop.
+	NewTableID int64 `json:"new_table_id"`
+	// DDLChangedIndex contains the indexes replaced during the reorganization of partitions,
+	// true for the new indexes and false for the old ones.
+	// Global indexes are always replaced, since their entries contain the partition IDs.
+	DDLChangedIndex map[int64]bool `json:"ddl_changed_index,omitempty"`
+	// DDLExchangeTableID is the original ID of the table exchanged with the partition of DDLExchangePartitionID,
+	// they are set during EXCHANGE PARTITION if the table has global indexes.
+	// The partition cannot be written, and the unique global indexes are also checked
+	// against the index entries of the exchanged table, which are not in the global indexes yet.
+	DDLExchangeTableID     int64 `json:"ddl_exchange_table_id,omitempty"`
+	DDLExchangePartitionID int64 `json:"ddl_exchange_partition_id,omitempty"`
 }
 
 // Clone clones itself.
@@ -1177,6 +1272,8 @@ func (pi *PartitionInfo) Clone() *PartitionInfo {
 	newPi := *pi
 	newPi.Columns = make([]CIStr, len(pi.Columns))
 	copy(newPi.Columns, pi.Columns)
+	newPi.DDLColumns = make([]CIStr, len(pi.DDLColumns))
+	copy(newPi.DDLColumns, pi.DDLColumns)
 
 	newPi.Definitions = make([]PartitionDefinition, len(pi.Definitions))
 	for i := range pi.Definitions {
@@ -1193,9 +1290,28 @@ func (pi *PartitionInfo) Clone() *PartitionInfo {
 		newPi.DroppingDefinitions[i] = pi.DroppingDefinitions[i].Clone()
 	}
 
+	if pi.DDLChangedIndex != nil {
+		newPi.DDLChangedIndex = make(map[int64]bool, len(pi.DDLChangedIndex))
+		for id, isNew := range pi.DDLChangedIndex {
+			newPi.DDLChangedIndex[id] = isNew
+		}
+	}
+
 	return &newPi
 }
 
+// ClearReorgIntermediateInfo resets the information only used during the reorganization of partitions.
+func (pi *PartitionInfo) ClearReorgIntermediateInfo() {
+	pi.AddingDefinitions = nil
+	pi.DroppingDefinitions = nil
+	pi.DDLState = StateNone
+	pi.DDLType = PartitionTypeNone
+	pi.DDLExpr = ""
+	pi.DDLColumns = nil
+	pi.NewTableID = 0
+	pi.DDLChangedIndex = nil
+}
+
 // GetNameByID gets the partition name by ID.
 func (pi *PartitionInfo) GetNameByID(id int64) string {
 	definitions := pi.Definitions
@@ -1794,6 +1910,195 @@ func (t *TTLInfo) GetJobInterval() (time.Duration, error) {
 	return duration.ParseDuration(t.JobInterval)
 }
 
+// DefaultIntervalPartitionPrecreate is the default number of partitions created in advance
+// for a table with an interval partition policy.
+const DefaultIntervalPartitionPrecreate = 2
+
+// IntervalPartitionInfo is the policy to maintain the partitions of a table, which is RANGE COLUMNS
+// partitioned by a single DATE or DATETIME column, with the partitions of the same time interval.
+type IntervalPartitionInfo struct {
+	IntervalExprStr string `json:"interval_expr"`
+	// `IntervalTimeUnit` is actually ast.TimeUnitType. Use `int` to avoid cycle dependency
+	IntervalTimeUnit int `json:"interval_time_unit"`
+	// Precreate is the number of the partitions after the one containing the current time,
+	// which should always exist.
+	Precreate uint64 `json:"precreate"`
+	// RetentionExprStr is empty if the partitions are never dropped, otherwise the partitions
+	// whose data are all older than the retention are dropped.
+	RetentionExprStr  string `json:"retention_expr,omitempty"`
+	RetentionTimeUnit int    `json:"retention_time_unit,omitempty"`
+}
+
+// Clone clones IntervalPartitionInfo
+func (i *IntervalPartitionInfo) Clone() *IntervalPartitionInfo {
+	cloned := *i
+	return &cloned
+}
+
+const (
+	// MaterializedViewLogTablePrefix is the name prefix of the tables logging the changes for materialized views.
+	MaterializedViewLogTablePrefix = "_tidb_mvlog_"
+	// MaterializedViewLogSignCol is the column of a log table which is 1 for an inserted row and -1 for a deleted row.
+	MaterializedViewLogSignCol = "_tidb_mvlog_sign"
+)
+
+// MaterializedViewInfo records the definition of a materialized view.
+type MaterializedViewInfo struct {
+	// SelectStmt is the definition of the view, the table names in it are qualified by the schema names.
+	SelectStmt string `json:"select"`
+	// Cols are the names of the columns specified by the user.
+	Cols []CIStr `json:"cols"`
+	// Incremental is not nil if the view can be refreshed incrementally.
+	Incremental *MaterializedViewIncrementalInfo `json:"incremental"`
+	// RefreshSchedPolicyType and RefreshSchedPolicyExpr are the schedule policy to refresh the view in the
+	// background, they are empty if the view is only refreshed manually.
+	RefreshSchedPolicyType string `json:"refresh_sched_policy_type,omitempty"`
+	RefreshSchedPolicyExpr string `json:"refresh_sched_policy_expr,omitempty"`
+}
+
+// Clone clones MaterializedViewInfo.
+func (m *MaterializedViewInfo) Clone() *MaterializedViewInfo {
+	cloned := *m
+	cloned.Cols = append([]CIStr(nil), m.Cols...)
+	if m.Incremental != nil {
+		incremental := *m.Incremental
+		incremental.GroupByCols = append([]MaterializedViewGroupByCol(nil), m.Incremental.GroupByCols...)
+		incremental.Aggs = append([]MaterializedViewAgg(nil), m.Incremental.Aggs...)
+		cloned.Incremental = &incremental
+	}
+	return &cloned
+}
+
+// MaterializedViewIncrementalInfo records how to refresh a materialized view incrementally.
+// The view must be the SUM/COUNT aggregation of a single table grouped by the columns.
+type MaterializedViewIncrementalInfo struct {
+	BaseSchemaID int64 `json:"base_schema_id"`
+	BaseTableID  int64 `json:"base_table_id"`
+	// LogTableID is the table which logs the changes of the base table.
+	LogTableID int64 `json:"log_table_id"`
+	// GroupByCols are the group by columns of the base table and the columns of the view storing them.
+	GroupByCols []MaterializedViewGroupByCol `json:"group_by_cols"`
+	// Aggs are the aggregations of the view.
+	Aggs []MaterializedViewAgg `json:"aggs"`
+	// CountCol is the column of the view which stores COUNT(*), the groups whose count is 0 are removed.
+	CountCol CIStr `json:"count_col"`
+	// Where is the filter of the base table, it's empty if there is no filter.
+	Where string `json:"where"`
+}
+
+// MaterializedViewGroupByCol is a group by column of a materialized view.
+type MaterializedViewGroupByCol struct {
+	// Arg is the group by column of the base table.
+	Arg CIStr `json:"arg"`
+	// Col is the column of the view.
+	Col CIStr `json:"col"`
+}
+
+// MaterializedViewAgg is an aggregation in a materialized view.
+type MaterializedViewAgg struct {
+	// Name is the name of the aggregate function, it's "sum" or "count".
+	Name string `json:"name"`
+	// Arg is the argument column of the aggregation in the base table, it's empty for COUNT(*).
+	Arg CIStr `json:"arg"`
+	// Col is the column of the view.
+	Col CIStr `json:"col"`
+}
+
+// MaterializedViewLogInfo records a change log of a table.
+type MaterializedViewLogInfo struct {
+	// ViewID is the table ID of the materialized view using the log.
+	ViewID int64 `json:"view_id"`
+	// TableID is the table ID of the log table.
+	TableID int64 `json:"table_id"`
+	// Cols are the columns of the base table which are written to the log table.
+	Cols []CIStr `json:"cols"`
+}
+
+// Clone clones MaterializedViewLogInfo.
+func (m *MaterializedViewLogInfo) Clone() *MaterializedViewLogInfo {
+	cloned := *m
+	cloned.Cols = append([]CIStr(nil), m.Cols...)
+	return &cloned
+}
+
+// MaskingType is the type of the function masking a column.
+type MaskingType string
+
+const (
+	// MaskingFull replaces the value by a constant of its type, e.g. 'XXXX' for strings and 0 for numbers.
+	MaskingFull MaskingType = "MASK_FULL"
+	// MaskingPartial keeps the prefix and the suffix of a string and pads the rest, the args are
+	// the length of the prefix, the length of the suffix and an optional pad character.
+	MaskingPartial MaskingType = "MASK_PARTIAL"
+	// MaskingHash replaces a string by its SHA-256 hash.
+	MaskingHash MaskingType = "MASK_HASH"
+	// MaskingNull replaces the value by NULL.
+	MaskingNull MaskingType = "MASK_NULL"
+	// MaskingDate truncates a date or a datetime to the unit in the args, which is YEAR, MONTH or DAY.
+	MaskingDate MaskingType = "MASK_DATE"
+)
+
+// MaskingPolicyInfo is a masking policy bound to a column. The values of the column are masked
+// in the queries of the users without the UNMASKED_READ privilege.
+type MaskingPolicyInfo struct {
+	Name CIStr `json:"name"`
+	// ColumnID is the ID of the masked column, so the policy is kept when the column is renamed.
+	ColumnID int64       `json:"column_id"`
+	Type     MaskingType `json:"type"`
+	Args     []string    `json:"args,omitempty"`
+}
+
+// Clone clones MaskingPolicyInfo.
+func (m *MaskingPolicyInfo) Clone() *MaskingPolicyInfo {
+	cloned := *m
+	cloned.Args = append([]string(nil), m.Args...)
+	return &cloned
+}
+
+// SupportColumnType returns whether the masking function can be applied to a column of the type.
+func (m *MaskingPolicyInfo) SupportColumnType(tp byte) bool {
+	switch m.Type {
+	case MaskingFull, MaskingNull:
+		return true
+	case MaskingPartial, MaskingHash:
+		switch tp {
+		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob:
+			return true
+		}
+	case MaskingDate:
+		return tp == mysql.TypeDate || tp == mysql.TypeDatetime || tp == mysql.TypeTimestamp
+	}
+	return false
+}
+
+// RowAccessPolicyInfo is a row access policy of a table. The rows are filtered by the expression in
+// the queries and the rows written by INSERT must satisfy it.
+type RowAccessPolicyInfo struct {
+	Name CIStr `json:"name"`
+	// ExprString is the restored expression of the policy, the column names in it are not qualified.
+	ExprString string `json:"expr_string"`
+	// Columns are the names of the columns referred by the expression.
+	Columns []CIStr `json:"columns,omitempty"`
+}
+
+// Clone clones RowAccessPolicyInfo.
+func (r *RowAccessPolicyInfo) Clone() *RowAccessPolicyInfo {
+	cloned := *r
+	cloned.Columns = append([]CIStr(nil), r.Columns...)
+	return &cloned
+}
+
+// DependsOnColumn returns whether the expression of the policy refers the column.
+func (r *RowAccessPolicyInfo) DependsOnColumn(name string) bool {
+	for _, col := range r.Columns {
+		if col.L == name {
+			return true
+		}
+	}
+	return false
+}
+
 func writeSettingItemToBuilder(sb *strings.Builder, item string, separatorFns ...func()) {
 	if sb.Len() != 0 {
 		for _, fn := range separatorFns {
@@ -1911,6 +2216,10 @@ const (
 	RunawayRule RunawayOptionType = iota
 	RunawayAction
 	RunawayWatch
+	RunawayProcessedKeys
+	RunawayRequestUnit
+	RunawayResultRows
+	RunawayMemory
 )
 
 func (t RunawayActionType) String() string {
@@ -1933,17 +2242,38 @@ type ResourceGroupRefInfo struct {
 }
 
 // ResourceGroupRunawaySettings is the runaway settings of the resource group
+// A query is identified as runaway once any of its rules whose threshold is not 0 is matched.
 type ResourceGroupRunawaySettings struct {
 	ExecElapsedTimeMs uint64            `json:"exec_elapsed_time_ms"`
 	Action            RunawayActionType `json:"action"`
 	WatchType         RunawayWatchType  `json:"watch_type"`
 	WatchDurationMs   uint64            `json:"watch_duration_ms"`
+
+	// The rules below are only known by TiDB, the resource manager only knows ExecElapsedTimeMs.
+	ProcessedKeys uint64 `json:"processed_keys"`
+	RequestUnit   uint64 `json:"request_unit"`
+	ResultRows    uint64 `json:"result_rows"`
+	MemoryBytes   uint64 `json:"memory_bytes"`
+}
+
+// HasRule returns whether any rule is set in the runaway settings.
+func (p *ResourceGroupRunawaySettings) HasRule() bool {
+	return p.ExecElapsedTimeMs > 0 || p.ProcessedKeys > 0 || p.RequestUnit > 0 || p.ResultRows > 0 || p.MemoryBytes > 0
 }
 
 type ResourceGroupBackgroundSettings struct {
 	JobTypes []string `json:"job_types"`
 }
 
+// ResourceGroupQueueSettings is the admission queue settings of the resource group.
+// The statements exceeding MaxConcurrency wait in the queue instead of being executed immediately.
+type ResourceGroupQueueSettings struct {
+	MaxConcurrency uint64 `json:"max_concurrency"`
+	MaxQueueLength uint64 `json:"max_queue_length"`
+	// QueueTimeoutMs is the max time a statement waits in the queue, 0 means no limit.
+	QueueTimeoutMs uint64 `json:"queue_timeout_ms"`
+}
+
 // ResourceGroupSettings is the settings of the resource group
 type ResourceGroupSettings struct {
 	RURate           uint64                           `json:"ru_per_sec"`
@@ -1954,6 +2284,7 @@ type ResourceGroupSettings struct {
 	BurstLimit       int64                            `json:"burst_limit"`
 	Runaway          *ResourceGroupRunawaySettings    `json:"runaway"`
 	Background       *ResourceGroupBackgroundSettings `json:"background"`
+	Queue            *ResourceGroupQueueSettings      `json:"queue"`
 }
 
 // NewResourceGroupSettings creates a new ResourceGroupSettings.
@@ -2012,7 +2343,23 @@ func (p *ResourceGroupSettings) String() string {
 		writeSettingItemToBuilder(sb, "BURSTABLE", separatorFn)
 	}
 	if p.Runaway != nil {
-		writeSettingDurationToBuilder(sb, "QUERY_LIMIT=(EXEC_ELAPSED", time.Duration(p.Runaway.ExecElapsedTimeMs)*time.Millisecond, separatorFn)
+		rules := new(strings.Builder)
+		if p.Runaway.ExecElapsedTimeMs > 0 {
+			writeSettingDurationToBuilder(rules, "EXEC_ELAPSED", time.Duration(p.Runaway.ExecElapsedTimeMs)*time.Millisecond)
+		}
+		if p.Runaway.ProcessedKeys > 0 {
+			writeSettingIntegerToBuilder(rules, "PROCESSED_KEYS", p.Runaway.ProcessedKeys)
+		}
+		if p.Runaway.RequestUnit > 0 {
+			writeSettingIntegerToBuilder(rules, "RU", p.Runaway.RequestUnit)
+		}
+		if p.Runaway.ResultRows > 0 {
+			writeSettingIntegerToBuilder(rules, "RESULT_ROWS", p.Runaway.ResultRows)
+		}
+		if p.Runaway.MemoryBytes > 0 {
+			writeSettingIntegerToBuilder(rules, "MEMORY", p.Runaway.MemoryBytes)
+		}
+		writeSettingItemToBuilder(sb, "QUERY_LIMIT=("+rules.String(), separatorFn)
 		writeSettingItemToBuilder(sb, "ACTION="+p.Runaway.Action.String())
 		if p.Runaway.WatchDurationMs > 0 {
 			writeSettingItemToBuilder(sb, "WATCH="+p.Runaway.WatchType.String())
@@ -2023,6 +2370,13 @@ func (p *ResourceGroupSettings) String() string {
 	if p.Background != nil {
 		fmt.Fprintf(sb, ", BACKGROUND=(TASK_TYPES='%s')", strings.Join(p.Background.JobTypes, ","))
 	}
+	if p.Queue != nil {
+		fmt.Fprintf(sb, ", QUEUE=(MAX_CONCURRENCY=%d, MAX_QUEUE_LENGTH=%d", p.Queue.MaxConcurrency, p.Queue.MaxQueueLength)
+		if p.Queue.QueueTimeoutMs > 0 {
+			fmt.Fprintf(sb, ", QUEUE_TIMEOUT='%s'", time.Duration(p.Queue.QueueTimeoutMs)*time.Millisecond)
+		}
+		sb.WriteString(")")
+	}
 
 	return sb.String()
 }
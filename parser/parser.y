@@ -523,6 +523,9 @@ import (
 	pageSym               "PAGE"
 	parser                "PARSER"
 	partial               "PARTIAL"
+	partitionInterval     "PARTITION_INTERVAL"
+	partitionPrecreate    "PARTITION_PRECREATE"
+	partitionRetention    "PARTITION_RETENTION"
 	partitioning          "PARTITIONING"
 	partitions            "PARTITIONS"
 	password              "PASSWORD"
@@ -790,6 +793,23 @@ import (
 	similar               "SIMILAR"
 	queryLimit            "QUERY_LIMIT"
 	background            "BACKGROUND"
+	cron                  "CRON"
+	timer                 "TIMER"
+	timers                "TIMERS"
+	zone                  "ZONE"
+	complete              "COMPLETE"
+	materialized          "MATERIALIZED"
+	refresh               "REFRESH"
+	tableChanges          "TABLE_CHANGES"
+	queue                 "QUEUE"
+	maxConcurrency        "MAX_CONCURRENCY"
+	maxQueueLength        "MAX_QUEUE_LENGTH"
+	queueTimeout          "QUEUE_TIMEOUT"
+	processedKeys         "PROCESSED_KEYS"
+	requestUnit           "RU"
+	resultRows            "RESULT_ROWS"
+	masking               "MASKING"
+	access                "ACCESS"
 
 	/* The following tokens belong to TiDBKeyword. Notice: make sure these tokens are contained in TiDBKeyword. */
 	admin                      "ADMIN"
@@ -937,6 +957,7 @@ import (
 	AlterPolicyStmt            "Alter Placement Policy statement"
 	AlterResourceGroupStmt     "Alter Resource Group statement"
 	AlterSequenceStmt          "Alter sequence statement"
+	AlterTimerStmt             "ALTER TIMER statement"
 	AnalyzeTableStmt           "Analyze table statement"
 	BeginTransactionStmt       "BEGIN TRANSACTION statement"
 	BinlogStmt                 "Binlog base64 statement"
@@ -955,6 +976,10 @@ import (
 	CreateResourceGroupStmt    "CREATE RESOURCE GROUP statement"
 	CreateSequenceStmt         "CREATE SEQUENCE statement"
 	CreateStatisticsStmt       "CREATE STATISTICS statement"
+	CreateTimerStmt            "CREATE TIMER statement"
+	CreateMaterializedViewStmt "CREATE MATERIALIZED VIEW statement"
+	CreateMaskingPolicyStmt    "CREATE MASKING POLICY statement"
+	CreateRowAccessPolicyStmt  "CREATE ROW ACCESS POLICY statement"
 	DoStmt                     "Do statement"
 	DropDatabaseStmt           "DROP DATABASE statement"
 	DropIndexStmt              "DROP INDEX statement"
@@ -963,6 +988,10 @@ import (
 	DropStatisticsStmt         "DROP STATISTICS statement"
 	DropStatsStmt              "DROP STATS statement"
 	DropTableStmt              "DROP TABLE statement"
+	DropTimerStmt              "DROP TIMER statement"
+	DropMaterializedViewStmt   "DROP MATERIALIZED VIEW statement"
+	DropMaskingPolicyStmt      "DROP MASKING POLICY statement"
+	DropRowAccessPolicyStmt    "DROP ROW ACCESS POLICY statement"
 	DropSequenceStmt           "DROP SEQUENCE statement"
 	DropUserStmt               "DROP USER"
 	DropRoleStmt               "DROP ROLE"
@@ -1038,6 +1067,10 @@ import (
 	ResumeLoadDataStmt         "RESUME LOAD DATA JOB statement"
 	CancelImportStmt           "CANCEL IMPORT JOB statement"
 	DropLoadDataStmt           "DROP LOAD DATA JOB statement"
+	PauseTimerStmt             "PAUSE TIMER statement"
+	ResumeTimerStmt            "RESUME TIMER statement"
+	TimerBodyStmt              "The statement executed by a timer"
+	RefreshMaterializedViewStmt "REFRESH MATERIALIZED VIEW statement"
 	ProcedureUnlabeledBlock    "The statement block without label in procedure"
 	ProcedureBlockContent      "The statement block in procedure expressed with 'Begin ... End'"
 	SimpleWhenThen             "Procedure case when then"
@@ -1452,8 +1485,10 @@ import (
 	DirectPlacementOption                  "Subset of anonymous or direct placement option"
 	PlacementOptionList                    "Anomymous or direct placement option list"
 	DirectResourceGroupBackgroundOption    "Subset of direct resource group background option"
+	DirectResourceGroupQueueOption         "Subset of direct resource group queue option"
 	DirectResourceGroupRunawayOption       "Subset of anonymous or direct resource group runaway option"
 	ResourceGroupBackgroundOptionList      "Direct resource group background option list"
+	ResourceGroupQueueOptionList           "Direct resource group queue option list"
 	ResourceGroupRunawayActionOption       "Resource group runaway action option"
 	ResourceGroupRunawayWatchOption        "Resource group runaway watch option"
 	ResourceGroupRunawayOptionList         "Anomymous or direct resource group runaway option list"
@@ -1464,6 +1499,12 @@ import (
 	CalibrateOption                        "Dynamic or static calibrate option"
 	DynamicCalibrateOptionList             "Anomymous or direct dynamic resource calibrate option list"
 	CalibrateResourceWorkloadOption        "Calibrate Resource workload option"
+	TimerOption                            "Timer option"
+	TimerOptionList                        "Timer option list"
+	TimerOptionListOpt                     "Optional timer option list"
+	TimerBodyOpt                           "Optional statement executed by a timer"
+	RefreshMaterializedViewTypeOpt         "Optional refresh type of materialized view"
+	MaterializedViewRefreshScheduleOpt     "Optional refresh schedule of materialized view"
 	AttributesOpt                          "Attributes options"
 	AllColumnsOrPredicateColumnsOpt        "all columns or predicate columns option"
 	StatsOptionsOpt                        "Stats options"
@@ -1795,6 +1836,22 @@ DirectResourceGroupRunawayOption:
 		}
 		$$ = &ast.ResourceGroupRunawayOption{Tp: ast.RunawayRule, StrValue: $3}
 	}
+|	"PROCESSED_KEYS" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupRunawayOption{Tp: ast.RunawayProcessedKeys, UintValue: $3.(uint64)}
+	}
+|	"RU" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupRunawayOption{Tp: ast.RunawayRequestUnit, UintValue: $3.(uint64)}
+	}
+|	"RESULT_ROWS" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupRunawayOption{Tp: ast.RunawayResultRows, UintValue: $3.(uint64)}
+	}
+|	"MEMORY" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupRunawayOption{Tp: ast.RunawayMemory, UintValue: $3.(uint64)}
+	}
 |	"ACTION" EqOpt ResourceGroupRunawayActionOption
 	{
 		$$ = &ast.ResourceGroupRunawayOption{Tp: ast.RunawayAction, IntValue: $3.(int32)}
@@ -1850,6 +1907,18 @@ DirectResourceGroupOption:
 	{
 		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceGroupBackground, BackgroundOptions: nil}
 	}
+|	"QUEUE" EqOpt '(' ResourceGroupQueueOptionList ')'
+	{
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceGroupQueue, QueueOptions: $4.([]*ast.ResourceGroupQueueOption)}
+	}
+|	"QUEUE" EqOpt '(' ')'
+	{
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceGroupQueue, QueueOptions: nil}
+	}
+|	"QUEUE" EqOpt "NULL"
+	{
+		$$ = &ast.ResourceGroupOption{Tp: ast.ResourceGroupQueue, QueueOptions: nil}
+	}
 
 ResourceGroupBackgroundOptionList:
 	DirectResourceGroupBackgroundOption
@@ -1879,6 +1948,47 @@ DirectResourceGroupBackgroundOption:
 		$$ = &ast.ResourceGroupBackgroundOption{Type: ast.BackgroundOptionTaskNames, StrValue: $3}
 	}
 
+ResourceGroupQueueOptionList:
+	DirectResourceGroupQueueOption
+	{
+		$$ = []*ast.ResourceGroupQueueOption{$1.(*ast.ResourceGroupQueueOption)}
+	}
+|	ResourceGroupQueueOptionList DirectResourceGroupQueueOption
+	{
+		if !ast.CheckQueueAppend($1.([]*ast.ResourceGroupQueueOption), $2.(*ast.ResourceGroupQueueOption)) {
+			yylex.AppendError(yylex.Errorf("Dupliated queue options specified"))
+			return 1
+		}
+		$$ = append($1.([]*ast.ResourceGroupQueueOption), $2.(*ast.ResourceGroupQueueOption))
+	}
+|	ResourceGroupQueueOptionList ',' DirectResourceGroupQueueOption
+	{
+		if !ast.CheckQueueAppend($1.([]*ast.ResourceGroupQueueOption), $3.(*ast.ResourceGroupQueueOption)) {
+			yylex.AppendError(yylex.Errorf("Dupliated queue options specified"))
+			return 1
+		}
+		$$ = append($1.([]*ast.ResourceGroupQueueOption), $3.(*ast.ResourceGroupQueueOption))
+	}
+
+DirectResourceGroupQueueOption:
+	"MAX_CONCURRENCY" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupQueueOption{Type: ast.QueueOptionMaxConcurrency, UintValue: $3.(uint64)}
+	}
+|	"MAX_QUEUE_LENGTH" EqOpt LengthNum
+	{
+		$$ = &ast.ResourceGroupQueueOption{Type: ast.QueueOptionMaxQueueLength, UintValue: $3.(uint64)}
+	}
+|	"QUEUE_TIMEOUT" EqOpt stringLit
+	{
+		_, err := time.ParseDuration($3)
+		if err != nil {
+			yylex.AppendError(yylex.Errorf("The QUEUE_TIMEOUT option is not a valid duration: %s", err.Error()))
+			return 1
+		}
+		$$ = &ast.ResourceGroupQueueOption{Type: ast.QueueOptionTimeout, StrValue: $3}
+	}
+
 PlacementOptionList:
 	DirectPlacementOption
 	{
@@ -2058,6 +2168,12 @@ AlterTableSpecSingleOpt:
 			Tp: ast.AlterTableRemoveTTL,
 		}
 	}
+|	"REMOVE" "PARTITION_INTERVAL"
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp: ast.AlterTableRemovePartitionInterval,
+		}
+	}
 
 LocationLabelList:
 	{
@@ -4842,6 +4958,141 @@ CreateViewStmt:
 		$$ = x
 	}
 
+/*******************************************************************
+ *
+ *  Materialized View Statements
+ *
+ *  Example:
+ *	CREATE MATERIALIZED VIEW [IF NOT EXISTS] view_name [(column_list)]
+ *		[REFRESH SCHEDULE {INTERVAL | CRON} 'expr'] AS select_statement
+ *	DROP MATERIALIZED VIEW [IF EXISTS] view_name
+ *	REFRESH MATERIALIZED VIEW view_name [COMPLETE | INCREMENTAL]
+ *******************************************************************/
+CreateMaterializedViewStmt:
+	"CREATE" "MATERIALIZED" "VIEW" IfNotExists ViewName ViewFieldList MaterializedViewRefreshScheduleOpt "AS" SelectStmt
+	{
+		startOffset := parser.startOffset(&yyS[yypt])
+		selStmt := $9.(ast.StmtNode)
+		selStmt.SetText(parser.lexer.client, strings.TrimSpace(parser.src[startOffset:]))
+		x := &ast.CreateMaterializedViewStmt{
+			IfNotExists: $4.(bool),
+			ViewName:    $5.(*ast.TableName),
+			Select:      selStmt,
+		}
+		if $6 != nil {
+			x.Cols = $6.([]model.CIStr)
+		}
+		if $7 != nil {
+			x.RefreshSchedule = $7.(*ast.TimerOption)
+		}
+		$$ = x
+	}
+
+MaterializedViewRefreshScheduleOpt:
+	{
+		$$ = nil
+	}
+|	"REFRESH" "SCHEDULE" EqOpt "INTERVAL" stringLit
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionSchedule, SchedPolicyType: ast.TimerSchedPolicyInterval, StrValue: $5}
+	}
+|	"REFRESH" "SCHEDULE" EqOpt "CRON" stringLit
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionSchedule, SchedPolicyType: ast.TimerSchedPolicyCron, StrValue: $5}
+	}
+
+DropMaterializedViewStmt:
+	"DROP" "MATERIALIZED" "VIEW" IfExists TableName
+	{
+		$$ = &ast.DropMaterializedViewStmt{
+			IfExists: $4.(bool),
+			ViewName: $5.(*ast.TableName),
+		}
+	}
+
+/*******************************************************************
+ *
+ *  Masking Policy Statements
+ *
+ *  Example:
+ *	CREATE MASKING POLICY [IF NOT EXISTS] policy_name ON tbl_name (col_name) AS mask_function([arg, ...])
+ *	DROP MASKING POLICY [IF EXISTS] policy_name ON tbl_name
+ *******************************************************************/
+CreateMaskingPolicyStmt:
+	"CREATE" "MASKING" "POLICY" IfNotExists Identifier "ON" TableName '(' ColumnName ')' "AS" Identifier '(' ExpressionListOpt ')'
+	{
+		$$ = &ast.CreateMaskingPolicyStmt{
+			IfNotExists: $4.(bool),
+			PolicyName:  model.NewCIStr($5),
+			Table:       $7.(*ast.TableName),
+			Column:      $9.(*ast.ColumnName),
+			MaskingType: model.MaskingType(strings.ToUpper($12)),
+			Args:        $14.([]ast.ExprNode),
+		}
+	}
+
+DropMaskingPolicyStmt:
+	"DROP" "MASKING" "POLICY" IfExists Identifier "ON" TableName
+	{
+		$$ = &ast.DropMaskingPolicyStmt{
+			IfExists:   $4.(bool),
+			PolicyName: model.NewCIStr($5),
+			Table:      $7.(*ast.TableName),
+		}
+	}
+
+/*******************************************************************
+ *
+ *  Row Access Policy Statements
+ *
+ *  Example:
+ *	CREATE ROW ACCESS POLICY [IF NOT EXISTS] policy_name ON tbl_name USING (expr)
+ *	DROP ROW ACCESS POLICY [IF EXISTS] policy_name ON tbl_name
+ *******************************************************************/
+CreateRowAccessPolicyStmt:
+	"CREATE" "ROW" "ACCESS" "POLICY" IfNotExists Identifier "ON" TableName "USING" '(' Expression ')'
+	{
+		$$ = &ast.CreateRowAccessPolicyStmt{
+			IfNotExists: $5.(bool),
+			PolicyName:  model.NewCIStr($6),
+			Table:       $8.(*ast.TableName),
+			Expr:        $11,
+		}
+	}
+
+DropRowAccessPolicyStmt:
+	"DROP" "ROW" "ACCESS" "POLICY" IfExists Identifier "ON" TableName
+	{
+		$$ = &ast.DropRowAccessPolicyStmt{
+			IfExists:   $5.(bool),
+			PolicyName: model.NewCIStr($6),
+			Table:      $8.(*ast.TableName),
+		}
+	}
+
+RefreshMaterializedViewStmt:
+	"REFRESH" "MATERIALIZED" "VIEW" TableName RefreshMaterializedViewTypeOpt
+	{
+		$$ = &ast.RefreshMaterializedViewStmt{
+			ViewName: $4.(*ast.TableName),
+			Tp:       $5.(ast.RefreshMaterializedViewType),
+		}
+	}
+
+RefreshMaterializedViewTypeOpt:
+	/* EMPTY */
+	{
+		$$ = ast.RefreshMaterializedViewDefault
+	}
+|	"COMPLETE"
+	{
+		$$ = ast.RefreshMaterializedViewComplete
+	}
+|	"INCREMENTAL"
+	{
+		$$ = ast.RefreshMaterializedViewIncremental
+	}
+
 OrReplace:
 	/* EMPTY */
 	{
@@ -6324,6 +6575,8 @@ IndexOptionList:
 				opt1.Visibility = opt2.Visibility
 			} else if opt2.PrimaryKeyTp != model.PrimaryKeyTypeDefault {
 				opt1.PrimaryKeyTp = opt2.PrimaryKeyTp
+			} else if opt2.Global {
+				opt1.Global = true
 			}
 			$$ = opt1
 		}
@@ -6368,6 +6621,18 @@ IndexOption:
 			PrimaryKeyTp: $1.(model.PrimaryKeyType),
 		}
 	}
+|	"GLOBAL"
+	{
+		$$ = &ast.IndexOption{
+			Global: true,
+		}
+	}
+|	"LOCAL"
+	{
+		$$ = &ast.IndexOption{
+			Global: false,
+		}
+	}
 
 /*
   See: https://github.com/mysql/mysql-server/blob/8.0/sql/sql_yacc.yy#L7179
@@ -6805,6 +7070,9 @@ UnReservedKeyword:
 |	"TTL"
 |	"TTL_ENABLE"
 |	"TTL_JOB_INTERVAL"
+|	"PARTITION_INTERVAL"
+|	"PARTITION_PRECREATE"
+|	"PARTITION_RETENTION"
 |	"FAILED_LOGIN_ATTEMPTS"
 |	"PASSWORD_LOCK_TIME"
 |	"DIGEST"
@@ -6981,6 +7249,23 @@ NotKeywordToken:
 |	"QUERY_LIMIT"
 |	"BACKGROUND"
 |	"TASK_TYPES"
+|	"CRON"
+|	"TIMER"
+|	"TIMERS"
+|	"ZONE"
+|	"COMPLETE"
+|	"MATERIALIZED"
+|	"REFRESH"
+|	"TABLE_CHANGES"
+|	"QUEUE"
+|	"MAX_CONCURRENCY"
+|	"MAX_QUEUE_LENGTH"
+|	"QUEUE_TIMEOUT"
+|	"PROCESSED_KEYS"
+|	"RU"
+|	"RESULT_ROWS"
+|	"MASKING"
+|	"ACCESS"
 
 /************************************************************************************
  *
@@ -9549,6 +9834,15 @@ TableFactor:
 		j.ExplicitParens = true
 		$$ = $2
 	}
+|	"TABLE_CHANGES" '(' TableName PartitionNameListOpt ',' Expression ',' Expression ')' TableAsNameOpt
+	{
+		tn := $3.(*ast.TableName)
+		tn.PartitionNames = $4.([]model.CIStr)
+		$$ = &ast.TableSource{
+			Source: &ast.TableChanges{Table: tn, StartTS: $6, EndTS: $8},
+			AsName: $10.(model.CIStr),
+		}
+	}
 
 PartitionNameListOpt:
 	/* empty */
@@ -11412,6 +11706,13 @@ ShowTargetFilterable:
 			DBName: $3,
 		}
 	}
+|	"TIMERS" ShowDatabaseNameOpt
+	{
+		$$ = &ast.ShowStmt{
+			Tp:     ast.ShowTimers,
+			DBName: $2,
+		}
+	}
 |	"TABLE" "STATUS" ShowDatabaseNameOpt
 	{
 		$$ = &ast.ShowStmt{
@@ -11828,6 +12129,7 @@ Statement:
 |	AlterSequenceStmt
 |	AlterPolicyStmt
 |	AlterResourceGroupStmt
+|	AlterTimerStmt
 |	AnalyzeTableStmt
 |	BeginTransactionStmt
 |	BinlogStmt
@@ -11851,6 +12153,10 @@ Statement:
 |	CreateResourceGroupStmt
 |	CreateSequenceStmt
 |	CreateStatisticsStmt
+|	CreateTimerStmt
+|	CreateMaterializedViewStmt
+|	CreateMaskingPolicyStmt
+|	CreateRowAccessPolicyStmt
 |	DoStmt
 |	DropDatabaseStmt
 |	DropIndexStmt
@@ -11864,6 +12170,10 @@ Statement:
 |	DropRoleStmt
 |	DropStatisticsStmt
 |	DropStatsStmt
+|	DropTimerStmt
+|	DropMaterializedViewStmt
+|	DropMaskingPolicyStmt
+|	DropRowAccessPolicyStmt
 |	DropBindingStmt
 |	FlushStmt
 |	FlashbackTableStmt
@@ -11928,6 +12238,9 @@ Statement:
 |	ResumeLoadDataStmt
 |	CancelImportStmt
 |	DropLoadDataStmt
+|	PauseTimerStmt
+|	ResumeTimerStmt
+|	RefreshMaterializedViewStmt
 
 TraceableStmt:
 	DeleteFromStmt
@@ -12276,6 +12589,26 @@ TableOption:
 		}
 		$$ = &ast.TableOption{Tp: ast.TableOptionTTLJobInterval, StrValue: $3}
 	}
+|	"PARTITION_INTERVAL" EqOpt "INTERVAL" Literal TimeUnit
+	{
+		$$ = &ast.TableOption{
+			Tp:            ast.TableOptionPartitionInterval,
+			Value:         ast.NewValueExpr($4, parser.charset, parser.collation),
+			TimeUnitValue: &ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)},
+		}
+	}
+|	"PARTITION_PRECREATE" EqOpt LengthNum
+	{
+		$$ = &ast.TableOption{Tp: ast.TableOptionPartitionPrecreate, UintValue: $3.(uint64)}
+	}
+|	"PARTITION_RETENTION" EqOpt "INTERVAL" Literal TimeUnit
+	{
+		$$ = &ast.TableOption{
+			Tp:            ast.TableOptionPartitionRetention,
+			Value:         ast.NewValueExpr($4, parser.charset, parser.collation),
+			TimeUnitValue: &ast.TimeUnitExpr{Unit: $5.(ast.TimeUnitType)},
+		}
+	}
 
 ForceOpt:
 	/* empty */
@@ -14645,6 +14978,138 @@ DropResourceGroupStmt:
 		}
 	}
 
+/********************************************************************************************
+ *
+ *  Timer Statements
+ *
+ *  Example:
+ *	CREATE TIMER [IF NOT EXISTS] timer_name
+ *	SCHEDULE [=] {INTERVAL | CRON} 'expr'
+ *	[TIME ZONE [=] 'time_zone']
+ *	[ENABLE | DISABLE]
+ *	[COMMENT [=] 'comment']
+ *	DO statement
+ *
+ *	ALTER TIMER [IF EXISTS] timer_name [timer_options] [DO statement]
+ *	PAUSE TIMER timer_name
+ *	RESUME TIMER timer_name
+ *	DROP TIMER [IF EXISTS] timer_name
+ ********************************************************************************************/
+CreateTimerStmt:
+	"CREATE" "TIMER" IfNotExists TableName TimerOptionListOpt "DO" TimerBodyStmt
+	{
+		$$ = &ast.CreateTimerStmt{
+			IfNotExists: $3.(bool),
+			Name:        $4.(*ast.TableName),
+			Options:     $5.([]*ast.TimerOption),
+			Body:        $7,
+		}
+	}
+
+AlterTimerStmt:
+	"ALTER" "TIMER" IfExists TableName TimerOptionListOpt TimerBodyOpt
+	{
+		options := $5.([]*ast.TimerOption)
+		if len(options) == 0 && $6 == nil {
+			yylex.AppendError(yylex.Errorf("ALTER TIMER should specify at least one option or the statement"))
+			return 1
+		}
+		x := &ast.AlterTimerStmt{
+			IfExists: $3.(bool),
+			Name:     $4.(*ast.TableName),
+			Options:  options,
+		}
+		if $6 != nil {
+			x.Body = $6.(ast.StmtNode)
+		}
+		$$ = x
+	}
+
+PauseTimerStmt:
+	"PAUSE" "TIMER" TableName
+	{
+		$$ = &ast.TimerActionStmt{
+			Tp:   ast.TimerActionPause,
+			Name: $3.(*ast.TableName),
+		}
+	}
+
+ResumeTimerStmt:
+	"RESUME" "TIMER" TableName
+	{
+		$$ = &ast.TimerActionStmt{
+			Tp:   ast.TimerActionResume,
+			Name: $3.(*ast.TableName),
+		}
+	}
+
+DropTimerStmt:
+	"DROP" "TIMER" IfExists TableName
+	{
+		$$ = &ast.DropTimerStmt{
+			IfExists: $3.(bool),
+			Name:     $4.(*ast.TableName),
+		}
+	}
+
+TimerOptionListOpt:
+	{
+		$$ = []*ast.TimerOption{}
+	}
+|	TimerOptionList
+
+TimerOptionList:
+	TimerOption
+	{
+		$$ = []*ast.TimerOption{$1.(*ast.TimerOption)}
+	}
+|	TimerOptionList TimerOption
+	{
+		$$ = append($1.([]*ast.TimerOption), $2.(*ast.TimerOption))
+	}
+
+TimerOption:
+	"SCHEDULE" EqOpt "INTERVAL" stringLit
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionSchedule, SchedPolicyType: ast.TimerSchedPolicyInterval, StrValue: $4}
+	}
+|	"SCHEDULE" EqOpt "CRON" stringLit
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionSchedule, SchedPolicyType: ast.TimerSchedPolicyCron, StrValue: $4}
+	}
+|	"TIME" "ZONE" EqOpt stringLit
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionTimeZone, StrValue: $4}
+	}
+|	"ENABLE"
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionEnable, BoolValue: true}
+	}
+|	"DISABLE"
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionEnable, BoolValue: false}
+	}
+|	"COMMENT" EqOpt stringLit
+	{
+		$$ = &ast.TimerOption{Tp: ast.TimerOptionComment, StrValue: $3}
+	}
+
+TimerBodyOpt:
+	{
+		$$ = nil
+	}
+|	"DO" TimerBodyStmt
+	{
+		$$ = $2
+	}
+
+TimerBodyStmt:
+	InsertIntoStmt
+|	ReplaceIntoStmt
+|	UpdateStmt
+|	DeleteFromStmt
+|	RefreshMaterializedViewStmt
+
 CreatePolicyStmt:
 	"CREATE" OrReplace "PLACEMENT" "POLICY" IfNotExists PolicyName PlacementOptionList
 	{
@@ -3231,6 +3231,12 @@ func TestDDL(t *testing.T) {
 		{"CREATE INDEX idx ON t ( a ) VISIBLE INVISIBLE", true, "CREATE INDEX `idx` ON `t` (`a`) INVISIBLE"},
 		{"CREATE INDEX idx ON t ( a ) USING HASH VISIBLE", true, "CREATE INDEX `idx` ON `t` (`a`) USING HASH VISIBLE"},
 		{"CREATE INDEX idx ON t ( a ) USING HASH INVISIBLE", true, "CREATE INDEX `idx` ON `t` (`a`) USING HASH INVISIBLE"},
+		{"CREATE UNIQUE INDEX idx ON t ( a ) GLOBAL", true, "CREATE UNIQUE INDEX `idx` ON `t` (`a`) GLOBAL"},
+		{"CREATE UNIQUE INDEX idx ON t ( a ) LOCAL", true, "CREATE UNIQUE INDEX `idx` ON `t` (`a`)"},
+		{"CREATE UNIQUE INDEX idx ON t ( a ) INVISIBLE GLOBAL COMMENT 'foo'", true, "CREATE UNIQUE INDEX `idx` ON `t` (`a`) COMMENT 'foo' INVISIBLE GLOBAL"},
+		{"CREATE UNIQUE INDEX idx ON t ( a ) /*T![global_index] GLOBAL */", true, "CREATE UNIQUE INDEX `idx` ON `t` (`a`) GLOBAL"},
+		{"CREATE TABLE t (a int, b int, UNIQUE KEY idx (a) GLOBAL, PRIMARY KEY (b) NONCLUSTERED GLOBAL)", true, "CREATE TABLE `t` (`a` INT,`b` INT,UNIQUE `idx`(`a`) GLOBAL,PRIMARY KEY(`b`) NONCLUSTERED GLOBAL)"},
+		{"ALTER TABLE t ADD UNIQUE INDEX idx (a) GLOBAL", true, "ALTER TABLE `t` ADD UNIQUE `idx`(`a`) GLOBAL"},
 
 		// For create index with algorithm
 		{"CREATE INDEX idx ON t ( a ) ALGORITHM = DEFAULT", true, "CREATE INDEX `idx` ON `t` (`a`)"},
@@ -3734,6 +3740,11 @@ func TestDDL(t *testing.T) {
 		{"create resource group x ru_per_sec=1000 background = (task_types='')", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, BACKGROUND = (TASK_TYPES = '')"},
 		{"create resource group x ru_per_sec=1000 background (task_types='br,lightning')", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, BACKGROUND = (TASK_TYPES = 'br,lightning')"},
 		{`create resource group x ru_per_sec=1000 QUERY_LIMIT (EXEC_ELAPSED "10s" ACTION COOLDOWN WATCH EXACT DURATION='10m')  background (task_types 'br,lightning')`, true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, QUERY_LIMIT = (EXEC_ELAPSED = '10s' ACTION = COOLDOWN WATCH = EXACT DURATION = '10m'), BACKGROUND = (TASK_TYPES = 'br,lightning')"},
+		{"create resource group x ru_per_sec=1000 QUERY_LIMIT=(PROCESSED_KEYS=10000 RU 500, RESULT_ROWS=100 MEMORY=1048576 ACTION KILL)", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, QUERY_LIMIT = (PROCESSED_KEYS = 10000 RU = 500 RESULT_ROWS = 100 MEMORY = 1048576 ACTION = KILL)"},
+		{"create resource group x ru_per_sec=1000 QUERY_LIMIT=(EXEC_ELAPSED '10s' PROCESSED_KEYS 10000 ACTION COOLDOWN)", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, QUERY_LIMIT = (EXEC_ELAPSED = '10s' PROCESSED_KEYS = 10000 ACTION = COOLDOWN)"},
+		{"create resource group x ru_per_sec=1000 QUERY_LIMIT=(PROCESSED_KEYS=10000 PROCESSED_KEYS=100 ACTION KILL)", false, ""},
+		{"create resource group x ru_per_sec=1000 QUERY_LIMIT=(RU=-1 ACTION KILL)", false, ""},
+		{"create resource group x ru_per_sec=1000 QUERY_LIMIT=(RESULT_ROWS='10' ACTION KILL)", false, ""},
 		// This case is expected in parser test but not in actual ddl job.
 		{"create resource group x ru_per_sec=1000 QUERY_LIMIT = (EXEC_ELAPSED '10s')", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, QUERY_LIMIT = (EXEC_ELAPSED = '10s')"},
 		{"create resource group x ru_per_sec=1000 QUERY=(EXEC_ELAPSED '10s')", false, ""},
@@ -3777,6 +3788,16 @@ func TestDDL(t *testing.T) {
 		{"alter resource group x background NULL", true, "ALTER RESOURCE GROUP `x` BACKGROUND = NULL"},
 		{"alter resource group default priority=low background = ( task_types \"ttl\" )", true, "ALTER RESOURCE GROUP `default` PRIORITY = LOW, BACKGROUND = (TASK_TYPES = 'ttl')"},
 		{"alter resource group default burstable background ( task_types = 'a,b,c' )", true, "ALTER RESOURCE GROUP `default` BURSTABLE = TRUE, BACKGROUND = (TASK_TYPES = 'a,b,c')"},
+		{"create resource group x ru_per_sec=1000 queue = (max_concurrency = 10, max_queue_length = 100, queue_timeout = '30s')", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, QUEUE = (MAX_CONCURRENCY = 10, MAX_QUEUE_LENGTH = 100, QUEUE_TIMEOUT = '30s')"},
+		// The queue timeout is validated by DDL, so the negative and sub-millisecond ones are parsed.
+		{"alter resource group x queue = (queue_timeout = '-1s')", true, "ALTER RESOURCE GROUP `x` QUEUE = (QUEUE_TIMEOUT = '-1s')"},
+		{"alter resource group x queue = (queue_timeout = '500us')", true, "ALTER RESOURCE GROUP `x` QUEUE = (QUEUE_TIMEOUT = '500us')"},
+		{"create resource group x ru_per_sec=1000 queue (max_concurrency 10 max_queue_length 0)", true, "CREATE RESOURCE GROUP `x` RU_PER_SEC = 1000, QUEUE = (MAX_CONCURRENCY = 10, MAX_QUEUE_LENGTH = 0)"},
+		{"alter resource group x queue=()", true, "ALTER RESOURCE GROUP `x` QUEUE = NULL"},
+		{"alter resource group x queue NULL", true, "ALTER RESOURCE GROUP `x` QUEUE = NULL"},
+		{"alter resource group x queue = (max_concurrency = 1, max_concurrency = 2)", false, ""},
+		{"alter resource group x queue = (queue_timeout = '10')", false, ""},
+		{"alter resource group x queue = (max_concurrency = -1)", false, ""},
 
 		{"drop resource group x;", true, "DROP RESOURCE GROUP `x`"},
 		{"drop resource group DEFAULT;", true, "DROP RESOURCE GROUP `DEFAULT`"},
@@ -6558,6 +6579,13 @@ func (checker *nodeTextCleaner) Enter(in ast.Node) (out ast.Node, skipChildren b
 		node.Tp.CleanElemIsBinaryLit()
 	case *ast.PartitionOptions:
 		cleanPartition(node)
+	case *ast.CreateTimerStmt:
+		// The body of the timer is not visited by Accept.
+		node.Body.Accept(checker)
+	case *ast.AlterTimerStmt:
+		if node.Body != nil {
+			node.Body.Accept(checker)
+		}
 	}
 	return in, false
 }
@@ -7036,6 +7064,145 @@ func TestPlanReplayer(t *testing.T) {
 	require.True(t, v.Analyze)
 }
 
+func TestTimer(t *testing.T) {
+	table := []testCase{
+		// create timer
+		{"create timer t1 schedule interval '1h' do delete from t where a < 10", true, "CREATE TIMER `t1` SCHEDULE INTERVAL '1h' DO DELETE FROM `t` WHERE `a`<10"},
+		{"create timer if not exists test.t1 schedule = cron '0 0 * * *' time zone 'Asia/Shanghai' disable comment 'c1' do insert into t values (1)", true, "CREATE TIMER IF NOT EXISTS `test`.`t1` SCHEDULE CRON '0 0 * * *' TIME ZONE 'Asia/Shanghai' DISABLE COMMENT 'c1' DO INSERT INTO `t` VALUES (1)"},
+		{"create timer t1 schedule interval '10m' time zone = '+08:00' enable comment = 'c1' do update t set a = a + 1", true, "CREATE TIMER `t1` SCHEDULE INTERVAL '10m' TIME ZONE '+08:00' ENABLE COMMENT 'c1' DO UPDATE `t` SET `a`=`a`+1"},
+		{"create timer t1 schedule interval '10m' do replace into t values (1)", true, "CREATE TIMER `t1` SCHEDULE INTERVAL '10m' DO REPLACE INTO `t` VALUES (1)"},
+		{"create timer t1 do delete from t", true, "CREATE TIMER `t1` DO DELETE FROM `t`"},
+		{"create timer t1 schedule interval '1h' do select 1", false, ""},
+		{"create timer t1 schedule interval '1h'", false, ""},
+		{"create timer t1 schedule every '1h' do delete from t", false, ""},
+
+		// alter timer
+		{"alter timer t1 schedule cron '@daily'", true, "ALTER TIMER `t1` SCHEDULE CRON '@daily'"},
+		{"alter timer if exists test.t1 disable comment ''", true, "ALTER TIMER IF EXISTS `test`.`t1` DISABLE COMMENT ''"},
+		{"alter timer t1 do delete from t where a > 1", true, "ALTER TIMER `t1` DO DELETE FROM `t` WHERE `a`>1"},
+		{"alter timer t1 time zone 'UTC' do delete from t", true, "ALTER TIMER `t1` TIME ZONE 'UTC' DO DELETE FROM `t`"},
+		{"alter timer t1", false, ""},
+
+		// pause/resume/drop timer
+		{"pause timer t1", true, "PAUSE TIMER `t1`"},
+		{"resume timer test.t1", true, "RESUME TIMER `test`.`t1`"},
+		{"drop timer t1", true, "DROP TIMER `t1`"},
+		{"drop timer if exists test.t1", true, "DROP TIMER IF EXISTS `test`.`t1`"},
+
+		// show timers
+		{"show timers", true, "SHOW TIMERS"},
+		{"show timers from test", true, "SHOW TIMERS IN `test`"},
+		{"show timers in test like 't%'", true, "SHOW TIMERS IN `test` LIKE _UTF8MB4't%'"},
+		{"show timers where name = 't1'", true, "SHOW TIMERS WHERE `name`=_UTF8MB4't1'"},
+
+		// new keywords can still be used as identifiers
+		{"create table timer (timers int, cron int, zone int)", true, "CREATE TABLE `timer` (`timers` INT,`cron` INT,`zone` INT)"},
+		{"create timer timer schedule interval '1h' do delete from timers", true, "CREATE TIMER `timer` SCHEDULE INTERVAL '1h' DO DELETE FROM `timers`"},
+	}
+	RunTest(t, table, false)
+
+	p := parser.New()
+	stmts, _, err := p.Parse("create timer t1 schedule interval '1h' do delete from t; drop timer t1", "", "")
+	require.NoError(t, err)
+	require.Len(t, stmts, 2)
+	create, ok := stmts[0].(*ast.CreateTimerStmt)
+	require.True(t, ok)
+	require.Equal(t, "t1", create.Name.Name.O)
+	require.Len(t, create.Options, 1)
+	require.Equal(t, ast.TimerOptionSchedule, create.Options[0].Tp)
+	require.Equal(t, ast.TimerSchedPolicyInterval, create.Options[0].SchedPolicyType)
+	require.Equal(t, "1h", create.Options[0].StrValue)
+	_, ok = create.Body.(*ast.DeleteStmt)
+	require.True(t, ok)
+	_, ok = stmts[1].(*ast.DropTimerStmt)
+	require.True(t, ok)
+}
+
+func TestMaterializedView(t *testing.T) {
+	table := []testCase{
+		{"create materialized view mv as select a, count(*) from t group by a", true, "CREATE MATERIALIZED VIEW `mv` AS SELECT `a`,COUNT(1) FROM `t` GROUP BY `a`"},
+		{"create materialized view if not exists test.mv (a, b, c) as select a, sum(b), count(*) from t where c > 1 group by a", true, "CREATE MATERIALIZED VIEW IF NOT EXISTS `test`.`mv` (`a`,`b`,`c`) AS SELECT `a`,SUM(`b`),COUNT(1) FROM `t` WHERE `c`>1 GROUP BY `a`"},
+		{"create materialized view mv as select * from t1 join t2 on t1.a = t2.a", true, "CREATE MATERIALIZED VIEW `mv` AS SELECT * FROM `t1` JOIN `t2` ON `t1`.`a`=`t2`.`a`"},
+		{"create materialized view mv refresh schedule interval '1h' as select a from t", true, "CREATE MATERIALIZED VIEW `mv` REFRESH SCHEDULE INTERVAL '1h' AS SELECT `a` FROM `t`"},
+		{"create materialized view mv (a) refresh schedule = cron '0 * * * *' as select a from t", true, "CREATE MATERIALIZED VIEW `mv` (`a`) REFRESH SCHEDULE CRON '0 * * * *' AS SELECT `a` FROM `t`"},
+		{"create materialized view mv refresh schedule '1h' as select a from t", false, ""},
+		{"create materialized view mv", false, ""},
+		{"create or replace materialized view mv as select 1", false, ""},
+		{"drop materialized view mv", true, "DROP MATERIALIZED VIEW `mv`"},
+		{"drop materialized view if exists test.mv", true, "DROP MATERIALIZED VIEW IF EXISTS `test`.`mv`"},
+		{"refresh materialized view mv", true, "REFRESH MATERIALIZED VIEW `mv`"},
+		{"refresh materialized view test.mv complete", true, "REFRESH MATERIALIZED VIEW `test`.`mv` COMPLETE"},
+		{"refresh materialized view mv incremental", true, "REFRESH MATERIALIZED VIEW `mv` INCREMENTAL"},
+		{"refresh materialized view mv fast", false, ""},
+		{"create timer t1 schedule interval '1h' do refresh materialized view mv complete", true, "CREATE TIMER `t1` SCHEDULE INTERVAL '1h' DO REFRESH MATERIALIZED VIEW `mv` COMPLETE"},
+
+		// new keywords can still be used as identifiers
+		{"create table materialized (refresh int, complete int)", true, "CREATE TABLE `materialized` (`refresh` INT,`complete` INT)"},
+	}
+	RunTest(t, table, false)
+
+	p := parser.New()
+	sql := "create materialized view mv as select a, count(*) from t group by a"
+	stmt, err := p.ParseOneStmt(sql, "", "")
+	require.NoError(t, err)
+	create, ok := stmt.(*ast.CreateMaterializedViewStmt)
+	require.True(t, ok)
+	require.Equal(t, "select a, count(*) from t group by a", create.Select.Text())
+}
+
+func TestTableChanges(t *testing.T) {
+	table := []testCase{
+		{"select * from table_changes(t, 1, 2)", true, "SELECT * FROM TABLE_CHANGES(`t`, 1, 2)"},
+		{"select a, _tidb_op from table_changes(test.t partition(p0, p1), '2023-01-01 00:00:00', now()) as c where a > 1", true, "SELECT `a`,`_tidb_op` FROM TABLE_CHANGES(`test`.`t` PARTITION(`p0`, `p1`), _UTF8MB4'2023-01-01 00:00:00', NOW()) AS `c` WHERE `a`>1"},
+		{"select * from t1 join table_changes(t2, @a, @b) c on t1.a = c.a", true, "SELECT * FROM `t1` JOIN TABLE_CHANGES(`t2`, @`a`, @`b`) AS `c` ON `t1`.`a`=`c`.`a`"},
+		{"select * from table_changes(t, 1)", false, ""},
+		{"select * from table_changes((select * from t), 1, 2)", false, ""},
+
+		// TABLE_CHANGES can still be used as an identifier
+		{"create table table_changes (table_changes int)", true, "CREATE TABLE `table_changes` (`table_changes` INT)"},
+		{"select * from table_changes", true, "SELECT * FROM `table_changes`"},
+	}
+	RunTest(t, table, false)
+}
+
+func TestMaskingPolicy(t *testing.T) {
+	table := []testCase{
+		{"create masking policy p on t (c) as mask_full()", true, "CREATE MASKING POLICY `p` ON `t` (`c`) AS MASK_FULL()"},
+		{"create masking policy if not exists p on test.t (c) as mask_partial(3, 4, '#')", true, "CREATE MASKING POLICY IF NOT EXISTS `p` ON `test`.`t` (`c`) AS MASK_PARTIAL(3, 4, _UTF8MB4'#')"},
+		{"create masking policy p on t (c) as mask_hash()", true, "CREATE MASKING POLICY `p` ON `t` (`c`) AS MASK_HASH()"},
+		{"create masking policy p on t (c) as mask_null()", true, "CREATE MASKING POLICY `p` ON `t` (`c`) AS MASK_NULL()"},
+		{"create masking policy p on t (c) as mask_date('year')", true, "CREATE MASKING POLICY `p` ON `t` (`c`) AS MASK_DATE(_UTF8MB4'year')"},
+		{"create masking policy p on t (c, d) as mask_full()", false, ""},
+		{"create masking policy p on t (c) as mask_full", false, ""},
+		{"create masking policy p on t as mask_full()", false, ""},
+		{"drop masking policy p on t", true, "DROP MASKING POLICY `p` ON `t`"},
+		{"drop masking policy if exists p on test.t", true, "DROP MASKING POLICY IF EXISTS `p` ON `test`.`t`"},
+		{"drop masking policy p", false, ""},
+
+		// MASKING can still be used as an identifier
+		{"create table masking (masking int)", true, "CREATE TABLE `masking` (`masking` INT)"},
+		{"select masking from masking", true, "SELECT `masking` FROM `masking`"},
+	}
+	RunTest(t, table, false)
+}
+
+func TestRowAccessPolicy(t *testing.T) {
+	table := []testCase{
+		{"create row access policy p on t using (tenant_id = current_tenant())", true, "CREATE ROW ACCESS POLICY `p` ON `t` USING (`tenant_id`=CURRENT_TENANT())"},
+		{"create row access policy if not exists p on test.t using (a > 1 and b in ('x', 'y'))", true, "CREATE ROW ACCESS POLICY IF NOT EXISTS `p` ON `test`.`t` USING (`a`>1 AND `b` IN (_UTF8MB4'x',_UTF8MB4'y'))"},
+		{"create row access policy p on t using a = 1", false, ""},
+		{"create row access policy p on t", false, ""},
+		{"drop row access policy p on t", true, "DROP ROW ACCESS POLICY `p` ON `t`"},
+		{"drop row access policy if exists p on test.t", true, "DROP ROW ACCESS POLICY IF EXISTS `p` ON `test`.`t`"},
+		{"drop row access policy p", false, ""},
+
+		// ACCESS can still be used as an identifier
+		{"create table access (access int)", true, "CREATE TABLE `access` (`access` INT)"},
+		{"select access from access", true, "SELECT `access` FROM `access`"},
+	}
+	RunTest(t, table, false)
+}
+
 func TestGBKEncoding(t *testing.T) {
 	p := parser.New()
 	gbkEncoding, _ := charset.Lookup("gbk")
@@ -7251,6 +7418,23 @@ func TestTTLTableOption(t *testing.T) {
 	RunTest(t, table, false)
 }
 
+func TestIntervalPartitionTableOption(t *testing.T) {
+	table := []testCase{
+		{"create table t (d date) partition_interval = interval 1 day partition by range columns (d) interval (1 day) first partition less than ('2023-01-01') last partition less than ('2023-01-10')", true, "CREATE TABLE `t` (`d` DATE) PARTITION_INTERVAL = INTERVAL 1 DAY PARTITION BY RANGE COLUMNS (`d`) INTERVAL (1 DAY) FIRST PARTITION LESS THAN (_UTF8MB4'2023-01-01') LAST PARTITION LESS THAN (_UTF8MB4'2023-01-10')"},
+		{"create table t (d date) partition_interval interval 1 month partition_precreate 3 partition_retention interval 1 year", true, "CREATE TABLE `t` (`d` DATE) PARTITION_INTERVAL = INTERVAL 1 MONTH PARTITION_PRECREATE = 3 PARTITION_RETENTION = INTERVAL 1 YEAR"},
+		{"create table t (d date) /*T![interval_partition] PARTITION_INTERVAL = INTERVAL 1 WEEK PARTITION_PRECREATE = 2 */", true, "CREATE TABLE `t` (`d` DATE) PARTITION_INTERVAL = INTERVAL 1 WEEK PARTITION_PRECREATE = 2"},
+		{"alter table t partition_interval = interval 2 hour", true, "ALTER TABLE `t` PARTITION_INTERVAL = INTERVAL 2 HOUR"},
+		{"alter table t partition_precreate = 7, partition_retention = interval 30 day", true, "ALTER TABLE `t` PARTITION_PRECREATE = 7, PARTITION_RETENTION = INTERVAL 30 DAY"},
+		{"alter table t remove partition_interval", true, "ALTER TABLE `t` REMOVE PARTITION_INTERVAL"},
+		{"create table partition_interval (partition_precreate int, partition_retention int)", true, "CREATE TABLE `partition_interval` (`partition_precreate` INT,`partition_retention` INT)"},
+
+		{"create table t (d date) partition_interval = 1 day", false, ""},
+		{"create table t (d date) partition_precreate = 'a'", false, ""},
+	}
+
+	RunTest(t, table, false)
+}
+
 func TestMultiStmt(t *testing.T) {
 	p := parser.New()
 	stmts, _, err := p.Parse("SELECT 'foo'; SELECT 'foo;bar','baz'; select 'foo' , 'bar' , 'baz' ;select 1", "", "")
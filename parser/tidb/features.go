@@ -32,16 +32,22 @@ const (
 	FeatureIDTTL = "ttl"
 	// FeatureIDResourceGroup is the `resource group` feature.
 	FeatureIDResourceGroup = "resource_group"
+	// FeatureIDIntervalPartition is the `interval partition policy` feature.
+	FeatureIDIntervalPartition = "interval_partition"
+	// FeatureIDGlobalIndex is the `global index` feature.
+	FeatureIDGlobalIndex = "global_index"
 )
 
 var featureIDs = map[string]struct{}{
-	FeatureIDAutoRandom:     {},
-	FeatureIDAutoIDCache:    {},
-	FeatureIDAutoRandomBase: {},
-	FeatureIDClusteredIndex: {},
-	FeatureIDForceAutoInc:   {},
-	FeatureIDPlacement:      {},
-	FeatureIDTTL:            {},
+	FeatureIDAutoRandom:        {},
+	FeatureIDAutoIDCache:       {},
+	FeatureIDAutoRandomBase:    {},
+	FeatureIDClusteredIndex:    {},
+	FeatureIDForceAutoInc:      {},
+	FeatureIDPlacement:         {},
+	FeatureIDTTL:               {},
+	FeatureIDIntervalPartition: {},
+	FeatureIDGlobalIndex:       {},
 }
 
 // CanParseFeature is used to check if a feature can be parsed.
@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/planner/cascades"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/testkit"
@@ -376,8 +375,6 @@ func TestTopN(t *testing.T) {
 }
 
 func TestCascadePlannerHashedPartTable(t *testing.T) {
-	failpoint.Enable("github.com/pingcap/tidb/planner/core/forceDynamicPrune", `return(true)`)
-	defer failpoint.Disable("github.com/pingcap/tidb/planner/core/forceDynamicPrune")
 	store := testkit.CreateMockStore(t)
 
 	tk := testkit.NewTestKit(t, store)
@@ -390,6 +387,7 @@ func TestCascadePlannerHashedPartTable(t *testing.T) {
 	tk.MustExec(`insert into pt1 values(4,40)`)
 	tk.MustExec(`insert into pt1 values(5,50)`)
 
+	tk.MustExec("set @@tidb_opt_fix_control = '44262:ON'")
 	tk.MustExec("set @@tidb_enable_cascades_planner = 1")
 	var input []string
 	var output []struct {
@@ -439,3 +437,108 @@ func TestInlineProjection(t *testing.T) {
 		tk.MustQuery(sql).Check(testkit.Rows(output[i].Result...))
 	}
 }
+
+func TestOuterJoinReorder(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("create table t3(a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, null), (null, 4)")
+	tk.MustExec("insert into t2 values (1, 1), (2, null), (4, 4)")
+	tk.MustExec("insert into t3 values (1, 1), (2, 2), (null, 3)")
+	tk.MustExec("set session tidb_enable_cascades_planner = 1")
+	var input []string
+	var output []struct {
+		SQL    string
+		Plan   []string
+		Result []string
+	}
+	integrationSuiteData := cascades.GetIntegrationSuiteData()
+	integrationSuiteData.LoadTestCases(t, &input, &output)
+	for i, sql := range input {
+		testdata.OnRecord(func() {
+			output[i].SQL = sql
+			output[i].Plan = testdata.ConvertRowsToStrings(tk.MustQuery("explain format = 'brief' " + sql).Rows())
+			output[i].Result = testdata.ConvertRowsToStrings(tk.MustQuery(sql).Rows())
+		})
+		tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(output[i].Plan...))
+		tk.MustQuery(sql).Check(testkit.Rows(output[i].Result...))
+	}
+}
+
+func TestCascadePlannerRangePartTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists pt")
+	tk.MustExec("create table pt(a int, b int, index idx_b(b)) partition by range(a) (partition p0 values less than (10), partition p1 values less than (20), partition p2 values less than (maxvalue))")
+	tk.MustExec("insert into pt values (1, 1), (15, 2), (16, 1), (30, 3)")
+	tk.MustExec("set session tidb_enable_cascades_planner = 1")
+	var input []string
+	var output []struct {
+		SQL         string
+		StaticPlan  []string
+		DynamicPlan []string
+		Result      []string
+	}
+	integrationSuiteData := cascades.GetIntegrationSuiteData()
+	integrationSuiteData.LoadTestCases(t, &input, &output)
+	for i, sql := range input {
+		tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+		testdata.OnRecord(func() {
+			output[i].SQL = sql
+			output[i].StaticPlan = testdata.ConvertRowsToStrings(tk.MustQuery("explain format = 'brief' " + sql).Rows())
+			output[i].Result = testdata.ConvertRowsToStrings(tk.MustQuery(sql).Rows())
+		})
+		tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(output[i].StaticPlan...))
+		tk.MustQuery(sql).Check(testkit.Rows(output[i].Result...))
+		tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+		tk.MustExec("set @@tidb_opt_fix_control = '44262:ON'")
+		testdata.OnRecord(func() {
+			output[i].DynamicPlan = testdata.ConvertRowsToStrings(tk.MustQuery("explain format = 'brief' " + sql).Rows())
+		})
+		tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(output[i].DynamicPlan...))
+		tk.MustQuery(sql).Check(testkit.Rows(output[i].Result...))
+		tk.MustExec("set @@tidb_opt_fix_control = ''")
+	}
+}
+
+func TestAggPushDownJoin(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int primary key, b int, c int)")
+	tk.MustExec("create table t2(a int, b int, c int)")
+	tk.MustExec("insert into t1 values (1, 1, 1), (2, 1, 2), (3, 2, null), (4, null, 4)")
+	tk.MustExec("insert into t2 values (1, 1, 1), (1, 1, 2), (2, 2, null), (null, null, 4), (2, 3, 5)")
+	for i := 0; i < 5; i++ {
+		tk.MustExec("insert into t2 select a, b + 1, c + 1 from t2")
+	}
+	tk.MustExec("analyze table t1, t2")
+	tk.MustExec("set session tidb_opt_agg_push_down = 1")
+	tk.MustExec("set session tidb_enable_cascades_planner = 1")
+	var input []string
+	var output []struct {
+		SQL    string
+		Plan   []string
+		Result []string
+	}
+	integrationSuiteData := cascades.GetIntegrationSuiteData()
+	integrationSuiteData.LoadTestCases(t, &input, &output)
+	for i, sql := range input {
+		testdata.OnRecord(func() {
+			output[i].SQL = sql
+			output[i].Plan = testdata.ConvertRowsToStrings(tk.MustQuery("explain format = 'brief' " + sql).Rows())
+			output[i].Result = testdata.ConvertRowsToStrings(tk.MustQuery(sql).Rows())
+		})
+		tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(output[i].Plan...))
+		tk.MustQuery(sql).Check(testkit.Rows(output[i].Result...))
+	}
+}
@@ -300,7 +300,10 @@ func (opt *Optimizer) implGroup(g *memo.Group, reqPhysProp *property.PhysicalPro
 			if impl.GetCost() == math.MaxFloat64 {
 				continue
 			}
-			implCost := impl.CalcCost(outCount, childImpls...)
+			implCost, err := calcCost(g, impl, outCount, childImpls...)
+			if err != nil {
+				return nil, err
+			}
 			if implCost > costLimit {
 				continue
 			}
@@ -313,7 +316,12 @@ func (opt *Optimizer) implGroup(g *memo.Group, reqPhysProp *property.PhysicalPro
 	// Handle enforcer rules for required physical property.
 	for _, rule := range GetEnforcerRules(g, reqPhysProp) {
 		newReqPhysProp := rule.NewProperty(reqPhysProp)
-		enforceCost := rule.GetEnforceCost(g)
+		// The enforcer's cost depends on its child under the cost model ver2,
+		// so the child's cost limit can't be reduced in advance.
+		enforceCost := 0.0
+		if !useCostModelVer2(g) {
+			enforceCost = rule.GetEnforceCost(g)
+		}
 		childImpl, err := opt.implGroup(g, newReqPhysProp, costLimit-enforceCost)
 		if err != nil {
 			return nil, err
@@ -323,6 +331,12 @@ func (opt *Optimizer) implGroup(g *memo.Group, reqPhysProp *property.PhysicalPro
 		}
 		impl := rule.OnEnforce(reqPhysProp, childImpl)
 		implCost := enforceCost + childImpl.GetCost()
+		if useCostModelVer2(g) {
+			implCost, err = plannercore.GetPlanCost(impl.GetPlan(), taskTypeOf(g), plannercore.NewDefaultPlanCostOption())
+			if err != nil {
+				return nil, err
+			}
+		}
 		impl.SetCost(implCost)
 		if groupImpl == nil || groupImpl.GetCost() > implCost {
 			groupImpl = impl
@@ -336,6 +350,40 @@ func (opt *Optimizer) implGroup(g *memo.Group, reqPhysProp *property.PhysicalPro
 	return groupImpl, nil
 }
 
+// useCostModelVer2 returns whether the group is costed by the cost model ver2.
+func useCostModelVer2(g *memo.Group) bool {
+	return g.Equivalents.Front().Value.(*memo.GroupExpr).ExprNode.SCtx().GetSessionVars().CostModelVersion == 2
+}
+
+// taskTypeOf returns the task type of the plans implemented in the group.
+func taskTypeOf(g *memo.Group) property.TaskType {
+	if g.EngineType == memo.EngineTiDB {
+		return property.RootTaskType
+	}
+	return property.CopSingleReadTaskType
+}
+
+// calcCost calculates the cost of the implementation with its children. Under the
+// cost model ver2, the cost of the physical plan is used, so the children plans
+// are set to the plan before calculating.
+func calcCost(g *memo.Group, impl memo.Implementation, outCount float64, children ...memo.Implementation) (float64, error) {
+	if !useCostModelVer2(g) {
+		return impl.CalcCost(outCount, children...), nil
+	}
+	plan := impl.GetPlan()
+	childPlans := make([]plannercore.PhysicalPlan, 0, len(children))
+	for _, child := range children {
+		childPlans = append(childPlans, child.GetPlan())
+	}
+	plan.SetChildren(childPlans...)
+	cost, err := plannercore.GetPlanCost(plan, taskTypeOf(g), plannercore.NewDefaultPlanCostOption())
+	if err != nil {
+		return 0, err
+	}
+	impl.SetCost(cost)
+	return cost, nil
+}
+
 func (opt *Optimizer) implGroupExpr(cur *memo.GroupExpr, reqPhysProp *property.PhysicalProperty) (impls []memo.Implementation, err error) {
 	for _, rule := range opt.GetImplementationRules(cur.ExprNode) {
 		if !rule.Match(cur, reqPhysProp) {
@@ -16,6 +16,7 @@ package cascades
 
 import (
 	"math"
+	"math/bits"
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
@@ -59,6 +60,7 @@ type TransformationRuleBatch map[memo.Operand][]Transformation
 // Each batch will be applied to the memo independently.
 var DefaultRuleBatches = []TransformationRuleBatch{
 	TiDBLayerOptimizationBatch,
+	JoinReorderBatch,
 	TiKVLayerOptimizationBatch,
 	PostTransformationBatch,
 }
@@ -112,6 +114,25 @@ var TiDBLayerOptimizationBatch = TransformationRuleBatch{
 	},
 }
 
+// JoinReorderBatch enumerates the join orders. It's applied after the
+// TiDBLayerOptimizationBatch, so the conditions of the joins have been pushed
+// down as far as possible and won't be changed by the other rules. The rules
+// pushing operators down through the joins are applied to the reordered joins.
+var JoinReorderBatch = TransformationRuleBatch{
+	memo.OperandJoin: {
+		NewRuleJoinReorder(),
+	},
+	memo.OperandAggregation: {
+		NewRulePushAggDownJoin(),
+	},
+	memo.OperandLimit: {
+		NewRulePushLimitDownOuterJoin(),
+	},
+	memo.OperandTopN: {
+		NewRulePushTopNDownOuterJoin(),
+	},
+}
+
 // TiKVLayerOptimizationBatch does the optimization related to TiKV layer.
 // For example, rules about pushing down Operators like Selection, Limit,
 // Aggregation into TiKV layer should be inside this batch.
@@ -820,7 +841,7 @@ func (r *PushLimitDownUnionAll) Match(expr *memo.ExprIter) bool {
 // It will transform `Limit->UnionAll->X` to `Limit->UnionAll->Limit->X`.
 func (r *PushLimitDownUnionAll) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
 	limit := old.GetExpr().ExprNode.(*plannercore.LogicalLimit)
-	unionAll := old.Children[0].GetExpr().ExprNode.(*plannercore.LogicalUnionAll)
+	unionAll := old.Children[0].GetExpr().ExprNode
 	unionAllSchema := old.Children[0].Group.Prop.Schema
 
 	newLimit := plannercore.LogicalLimit{
@@ -1059,6 +1080,639 @@ func (r *TransformJoinCondToSel) OnTransform(old *memo.ExprIter) (newExprs []*me
 	return []*memo.GroupExpr{newJoinExpr}, true, false, nil
 }
 
+// maxJoinReorderLeaves is the max number of the leaves of a join tree which can
+// be reordered by JoinReorder, the number of the enumerated join expressions
+// grows exponentially with it.
+const maxJoinReorderLeaves = 6
+
+// JoinReorder enumerates the join orders of the join trees made up of inner
+// joins and outer joins, including the bushy ones. The leaves of a join tree
+// are the Groups which are not joins, e.g. DataSource or semi join. The inner
+// side of an outer join is a leaf which can only be outer joined, after all the
+// leaves referenced by the outer join conditions, i.e.
+// `(A left join B on p(A, B)) join C` is equal to `(A join C) left join B on
+// p(A, B)`. A Group is built for each subset of the leaves which can be joined
+// without cartesian product, so the join orders are shared in the memo and the
+// best one is chosen by the cost.
+type JoinReorder struct {
+	baseRule
+}
+
+// NewRuleJoinReorder creates a new Transformation JoinReorder.
+// The pattern of this rule is: `Join`.
+func NewRuleJoinReorder() Transformation {
+	rule := &JoinReorder{}
+	rule.pattern = memo.NewPattern(memo.OperandJoin, memo.EngineTiDBOnly)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *JoinReorder) Match(expr *memo.ExprIter) bool {
+	if !isReorderableJoin(expr.GetExpr(), true) {
+		return false
+	}
+	// The join orders of a Group only need to be enumerated once.
+	for elem := expr.GetExpr().Group.Equivalents.Front(); elem != nil; elem = elem.Next() {
+		if elem.Value.(*memo.GroupExpr).HasAppliedRule(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isReorderableJoin checks whether the join can be reordered. The joins with
+// join method hints are kept as they are, like the non-cascades join reorder.
+func isReorderableJoin(expr *memo.GroupExpr, outerJoin bool) bool {
+	join, ok := expr.ExprNode.(*plannercore.LogicalJoin)
+	if !ok || join.StraightJoin || join.HasJoinMethodHint() {
+		return false
+	}
+	switch join.JoinType {
+	case plannercore.InnerJoin:
+		return true
+	case plannercore.LeftOuterJoin, plannercore.RightOuterJoin:
+		return outerJoin
+	}
+	return false
+}
+
+// OnTransform implements Transformation interface.
+// This rule enumerates the join orders of the join tree rooted at the Join.
+// The Groups of the join sub-trees are reused, and a Projection is added when
+// the join order changes the order of the output columns.
+func (r *JoinReorder) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	solver := newJoinReorderSolver(r, old.GetExpr(), true)
+	if !solver.prepare(old.GetExpr()) {
+		if solver.outerLeaves == 0 {
+			return nil, false, false, nil
+		}
+		// The outer joins may be referenced by the conditions of the inner
+		// joins above them, take them as the leaves of the join tree instead.
+		solver = newJoinReorderSolver(r, old.GetExpr(), false)
+		if !solver.prepare(old.GetExpr()) {
+			return nil, false, false, nil
+		}
+	}
+	full := uint64(1)<<len(solver.leaves) - 1
+	for subset := uint64(1); subset < full; subset++ {
+		if bits.OnesCount64(subset) < 2 || !solver.isValidSubset(subset) {
+			continue
+		}
+		if _, ok := solver.groups[subset]; ok {
+			// It's a join sub-tree, whose join orders are enumerated by itself.
+			continue
+		}
+		exprs := solver.enumerateJoins(subset, old.GetExpr())
+		if len(exprs) == 0 {
+			continue
+		}
+		group := memo.NewGroupWithSchema(exprs[0], solver.subsetSchema(subset))
+		for _, expr := range exprs[1:] {
+			group.Insert(expr)
+		}
+		solver.groups[subset] = group
+	}
+	return solver.enumerateJoins(full, old.GetExpr()), false, false, nil
+}
+
+// joinReorderSolver keeps the state of JoinReorder when it's applied to a join tree.
+type joinReorderSolver struct {
+	rule        *JoinReorder
+	sctx        sessionctx.Context
+	blockOffset int
+	// outerJoin indicates whether the outer joins are flattened.
+	outerJoin bool
+
+	leaves []*memo.Group
+	conds  []expression.Expression
+	// condMasks are the subsets of the leaves referenced by the conds.
+	condMasks []uint64
+	// outerLeaves is the subset of the leaves which are the inner sides of the
+	// outer joins, outerConds are the conditions of their outer joins, and
+	// outerDeps are the subsets of the other leaves referenced by outerConds.
+	outerLeaves uint64
+	outerConds  map[int][]expression.Expression
+	outerDeps   map[int]uint64
+	// components are the connected components of the leaves.
+	components []uint64
+	// groups maps the subsets of the leaves to the Groups producing their join.
+	// The columns of the Groups are in the order of the leaves.
+	groups   map[uint64]*memo.Group
+	exceeded bool
+	// schema is the schema of the Group of the whole join tree, which may be
+	// pruned by the column pruning and contain fewer columns than the leaves.
+	schema *expression.Schema
+}
+
+func newJoinReorderSolver(rule *JoinReorder, expr *memo.GroupExpr, outerJoin bool) *joinReorderSolver {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	return &joinReorderSolver{
+		rule:        rule,
+		sctx:        join.SCtx(),
+		blockOffset: join.SelectBlockOffset(),
+		outerJoin:   outerJoin,
+		outerConds:  make(map[int][]expression.Expression),
+		outerDeps:   make(map[int]uint64),
+		groups:      make(map[uint64]*memo.Group),
+		schema:      expr.Group.Prop.Schema,
+	}
+}
+
+// prepare flattens the join tree rooted at the GroupExpr and classifies the
+// conditions, it returns false if the join tree can't be reordered.
+func (s *joinReorderSolver) prepare(expr *memo.GroupExpr) bool {
+	full := s.extractJoinTree(expr)
+	// The join of two leaves is not swapped, since the implementation rules
+	// can build the hash table on either side.
+	if s.exceeded || len(s.leaves) < 3 || !s.classifyConds() {
+		return false
+	}
+	s.groups[full] = expr.Group
+	return true
+}
+
+// extractJoinTree flattens the join tree rooted at the GroupExpr, it returns
+// the subset of the leaves of the join tree.
+func (s *joinReorderSolver) extractJoinTree(expr *memo.GroupExpr) (subset uint64) {
+	join := expr.ExprNode.(*plannercore.LogicalJoin)
+	conds := make([]expression.Expression, 0, len(join.EqualConditions)+len(join.LeftConditions)+
+		len(join.RightConditions)+len(join.OtherConditions))
+	for _, cond := range join.EqualConditions {
+		conds = append(conds, cond)
+	}
+	conds = append(conds, join.LeftConditions...)
+	conds = append(conds, join.RightConditions...)
+	conds = append(conds, join.OtherConditions...)
+	if join.JoinType == plannercore.InnerJoin {
+		s.conds = append(s.conds, conds...)
+	}
+	for i, child := range expr.Children {
+		isOuter := (join.JoinType == plannercore.LeftOuterJoin && i == 1) ||
+			(join.JoinType == plannercore.RightOuterJoin && i == 0)
+		if !isOuter {
+			subset |= s.extractGroup(child)
+			continue
+		}
+		leaf := s.addLeaf(child)
+		if leaf == 0 {
+			continue
+		}
+		subset |= leaf
+		s.outerLeaves |= leaf
+		s.outerConds[bits.TrailingZeros64(leaf)] = conds
+	}
+	return subset
+}
+
+func (s *joinReorderSolver) extractGroup(g *memo.Group) uint64 {
+	if elem := g.GetFirstElem(memo.OperandJoin); elem != nil && isReorderableJoin(elem.Value.(*memo.GroupExpr), s.outerJoin) {
+		subset := s.extractJoinTree(elem.Value.(*memo.GroupExpr))
+		s.groups[subset] = g
+		return subset
+	}
+	return s.addLeaf(g)
+}
+
+func (s *joinReorderSolver) addLeaf(g *memo.Group) uint64 {
+	if len(s.leaves) == maxJoinReorderLeaves {
+		s.exceeded = true
+		return 0
+	}
+	subset := uint64(1) << len(s.leaves)
+	s.leaves = append(s.leaves, g)
+	s.groups[subset] = g
+	return subset
+}
+
+// condMask returns the subset of the leaves referenced by the condition, it
+// returns false if any column of the condition is not from the leaves.
+func (s *joinReorderSolver) condMask(cond expression.Expression) (mask uint64, ok bool) {
+	for _, col := range expression.ExtractColumns(cond) {
+		found := false
+		for i, leaf := range s.leaves {
+			if leaf.Prop.Schema.Contains(col) {
+				mask |= uint64(1) << i
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return mask, true
+}
+
+// classifyConds computes the subsets of the leaves referenced by the conditions.
+// The conditions referencing only one leaf are pushed to the leaf, so they
+// can be applied by all the join orders. It returns false if there is any
+// condition which can't be handled, e.g. the constant condition, or the
+// condition of the inner joins referencing the inner sides of outer joins.
+func (s *joinReorderSolver) classifyConds() bool {
+	joinConds := make([]expression.Expression, 0, len(s.conds))
+	leafConds := make([][]expression.Expression, len(s.leaves))
+	for _, cond := range s.conds {
+		mask, ok := s.condMask(cond)
+		if !ok || mask&s.outerLeaves != 0 {
+			return false
+		}
+		switch bits.OnesCount64(mask) {
+		case 0:
+			return false
+		case 1:
+			i := bits.TrailingZeros64(mask)
+			leafConds[i] = append(leafConds[i], cond)
+		default:
+			joinConds = append(joinConds, cond)
+			s.condMasks = append(s.condMasks, mask)
+		}
+	}
+	s.conds = joinConds
+	for i, conds := range leafConds {
+		if len(conds) > 0 {
+			s.groups[uint64(1)<<i] = buildChildSelectionGroup(s.sctx, s.blockOffset, conds, s.leaves[i])
+		}
+	}
+	for i, conds := range s.outerConds {
+		for _, cond := range conds {
+			mask, ok := s.condMask(cond)
+			if !ok {
+				return false
+			}
+			s.outerDeps[i] |= mask &^ (uint64(1) << i)
+		}
+	}
+
+	// Merge the leaves connected by the conditions, the inner side of an outer
+	// join is connected to the leaves referenced by its conditions.
+	for i := range s.leaves {
+		s.components = append(s.components, uint64(1)<<i)
+	}
+	masks := append([]uint64(nil), s.condMasks...)
+	for i, deps := range s.outerDeps {
+		masks = append(masks, deps|uint64(1)<<i)
+	}
+	for _, mask := range masks {
+		merged := uint64(0)
+		remained := s.components[:0]
+		for _, component := range s.components {
+			if component&mask != 0 {
+				merged |= component
+			} else {
+				remained = append(remained, component)
+			}
+		}
+		s.components = append(remained, merged)
+	}
+	return true
+}
+
+// isValidSubset checks whether the join of the leaves in the subset can be
+// built. The inner side of an outer join can only be joined after the leaves
+// referenced by its conditions, and there must be a leaf which is not the
+// inner side of an outer join.
+func (s *joinReorderSolver) isValidSubset(subset uint64) bool {
+	if subset&^s.outerLeaves == 0 {
+		return false
+	}
+	for i, deps := range s.outerDeps {
+		if subset&(uint64(1)<<i) != 0 && deps&^subset != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isComponentUnion checks whether the subset is made up of whole connected components.
+func (s *joinReorderSolver) isComponentUnion(subset uint64) bool {
+	for _, component := range s.components {
+		if common := component & subset; common != 0 && common != component {
+			return false
+		}
+	}
+	return true
+}
+
+// isConnected checks whether there is any condition between the two subsets.
+func (s *joinReorderSolver) isConnected(left, right uint64) bool {
+	for _, mask := range s.condMasks {
+		if mask&^(left|right) == 0 && mask&left != 0 && mask&right != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// subsetSchema returns the columns of the leaves in the subset which are
+// needed by the parents, i.e. the output columns of the whole join tree and
+// the columns of the conditions which are not inside the subset. The columns
+// are in the order of the leaves.
+func (s *joinReorderSolver) subsetSchema(subset uint64) *expression.Schema {
+	if subset == uint64(1)<<len(s.leaves)-1 {
+		return s.schema
+	}
+	needed := expression.NewSchema(s.schema.Columns...)
+	for i, mask := range s.condMasks {
+		if mask&^subset != 0 {
+			needed.Append(expression.ExtractColumns(s.conds[i])...)
+		}
+	}
+	for i, conds := range s.outerConds {
+		if subset&(uint64(1)<<i) == 0 {
+			for _, cond := range conds {
+				needed.Append(expression.ExtractColumns(cond)...)
+			}
+		}
+	}
+	schema := expression.NewSchema()
+	for i, leaf := range s.leaves {
+		if subset&(uint64(1)<<i) == 0 {
+			continue
+		}
+		for _, col := range leaf.Prop.Schema.Columns {
+			idx := needed.ColumnIndex(col)
+			if idx < 0 {
+				continue
+			}
+			if s.outerLeaves&(uint64(1)<<i) != 0 {
+				// The columns of the inner side of an outer join are nullable,
+				// the ones kept by the parents are used.
+				col = needed.Columns[idx]
+				if mysql.HasNotNullFlag(col.RetType.GetFlag()) {
+					col = col.Clone().(*expression.Column)
+					col.RetType = col.RetType.Clone()
+					col.RetType.DelFlag(mysql.NotNullFlag)
+				}
+			}
+			schema.Append(col)
+		}
+	}
+	return schema
+}
+
+// enumerateJoins builds the join expressions of all the ways to split the
+// subset into two joined subsets. The cartesian products are only considered
+// to join the connected components. The join with the same children as
+// `origin` is skipped since it already exists.
+func (s *joinReorderSolver) enumerateJoins(subset uint64, origin *memo.GroupExpr) []*memo.GroupExpr {
+	var connected, cartesian []uint64
+	for left := (subset - 1) & subset; left > 0; left = (left - 1) & subset {
+		right := subset ^ left
+		if _, ok := s.groups[left]; !ok || !s.isValidSubset(left) {
+			continue
+		}
+		if _, ok := s.groups[right]; !ok {
+			continue
+		}
+		if right&s.outerLeaves == right && bits.OnesCount64(right) == 1 {
+			// The inner side of an outer join is outer joined at last.
+			if deps := s.outerDeps[bits.TrailingZeros64(right)]; deps&^left != 0 {
+				continue
+			} else if deps != 0 {
+				connected = append(connected, left)
+			} else {
+				cartesian = append(cartesian, left)
+			}
+			continue
+		}
+		if !s.isValidSubset(right) {
+			continue
+		}
+		if s.isConnected(left, right) {
+			connected = append(connected, left)
+		} else if s.isComponentUnion(left) && s.isComponentUnion(right) {
+			cartesian = append(cartesian, left)
+		}
+	}
+	if len(connected) == 0 {
+		connected = cartesian
+	}
+	exprs := make([]*memo.GroupExpr, 0, len(connected))
+	for _, left := range connected {
+		right := subset ^ left
+		if s.groups[left] == origin.Children[0] && s.groups[right] == origin.Children[1] {
+			continue
+		}
+		exprs = append(exprs, s.newJoinExpr(left, right, subset))
+	}
+	return exprs
+}
+
+// newJoinExpr builds the join expression of the two subsets, it's an outer
+// join if the right subset is the inner side of an outer join. The output
+// columns of the join are reordered by a Projection if they are not in the
+// order of the leaves.
+func (s *joinReorderSolver) newJoinExpr(left, right, subset uint64) *memo.GroupExpr {
+	leftGroup, rightGroup := s.groups[left], s.groups[right]
+	joinType := plannercore.InnerJoin
+	var conds []expression.Expression
+	if right&s.outerLeaves == right {
+		joinType = plannercore.LeftOuterJoin
+		conds = s.outerConds[bits.TrailingZeros64(right)]
+	} else {
+		for i, mask := range s.condMasks {
+			if mask&^subset == 0 && mask&left != 0 && mask&right != 0 {
+				conds = append(conds, s.conds[i])
+			}
+		}
+	}
+	schema := s.subsetSchema(subset)
+	// The columns not needed by the parents are pruned from the join like the
+	// inlined projection of the column pruning.
+	joinSchema := expression.NewSchema()
+	for _, col := range expression.MergeSchema(leftGroup.Prop.Schema, rightGroup.Prop.Schema).Columns {
+		if idx := schema.ColumnIndex(col); idx >= 0 {
+			joinSchema.Append(schema.Columns[idx])
+		}
+	}
+	join := plannercore.LogicalJoin{JoinType: joinType}.Init(s.sctx, s.blockOffset)
+	join.SetSchema(joinSchema)
+	eqConds, leftConds, rightConds, otherConds := join.ExtractOnCondition(conds, leftGroup.Prop.Schema, rightGroup.Prop.Schema, false, false)
+	join.AppendJoinConds(eqConds, leftConds, rightConds, otherConds)
+	joinExpr := memo.NewGroupExpr(join)
+	joinExpr.SetChildren(leftGroup, rightGroup)
+	joinExpr.AddAppliedRule(s.rule)
+	// All the leaves of the left subset are before the ones of the right subset.
+	if bits.Len64(left) <= bits.TrailingZeros64(right) {
+		return joinExpr
+	}
+
+	proj := plannercore.LogicalProjection{Exprs: expression.Column2Exprs(schema.Columns)}.Init(s.sctx, s.blockOffset)
+	proj.SetSchema(schema)
+	projExpr := memo.NewGroupExpr(proj)
+	projExpr.SetChildren(memo.NewGroupWithSchema(joinExpr, join.Schema()))
+	projExpr.AddAppliedRule(s.rule)
+	return projExpr
+}
+
+// PushAggDownJoin pushes a partial Aggregation down to a child of the inner Join
+// when the aggregate functions, except firstrow, only use the columns of the child.
+// The Aggregation above the Join merges the partial results in the final mode.
+type PushAggDownJoin struct {
+	baseRule
+}
+
+// NewRulePushAggDownJoin creates a new Transformation PushAggDownJoin.
+// The pattern of this rule is `Aggregation -> Join`.
+func NewRulePushAggDownJoin() Transformation {
+	rule := &PushAggDownJoin{}
+	rule.pattern = memo.BuildPattern(
+		memo.OperandAggregation,
+		memo.EngineTiDBOnly,
+		memo.NewPattern(memo.OperandJoin, memo.EngineTiDBOnly),
+	)
+	return rule
+}
+
+// Match implements Transformation interface.
+func (r *PushAggDownJoin) Match(expr *memo.ExprIter) bool {
+	agg := expr.GetExpr().ExprNode.(*plannercore.LogicalAggregation)
+	join := expr.Children[0].GetExpr().ExprNode.(*plannercore.LogicalJoin)
+	if expr.GetExpr().HasAppliedRule(r) || !agg.SCtx().GetSessionVars().AllowAggPushDown || join.JoinType != plannercore.InnerJoin {
+		return false
+	}
+	for _, aggFunc := range agg.AggFuncs {
+		if aggFunc.HasDistinct || len(aggFunc.OrderByItems) > 0 || aggFunc.Mode != aggregation.CompleteMode {
+			return false
+		}
+		switch aggFunc.Name {
+		case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// OnTransform implements Transformation interface.
+// This rule tries to transform `Aggregation -> Join -> (X, Y)` to
+// `Aggregation(final) -> Join -> (Aggregation(partial) -> X, Y)` and
+// `Aggregation(final) -> Join -> (X, Aggregation(partial) -> Y)`.
+func (r *PushAggDownJoin) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
+	for childIdx := range old.Children[0].GetExpr().Children {
+		newAggExpr, err := r.pushAggDown(old, childIdx)
+		if err != nil {
+			return nil, false, false, err
+		}
+		if newAggExpr != nil {
+			newExprs = append(newExprs, newAggExpr)
+		}
+	}
+	return newExprs, false, false, nil
+}
+
+func (r *PushAggDownJoin) pushAggDown(old *memo.ExprIter, childIdx int) (*memo.GroupExpr, error) {
+	agg := old.GetExpr().ExprNode.(*plannercore.LogicalAggregation)
+	joinExpr := old.Children[0].GetExpr()
+	join := joinExpr.ExprNode.(*plannercore.LogicalJoin)
+	sctx := agg.SCtx()
+	childGroup := joinExpr.Children[childIdx]
+	childSchema := childGroup.Prop.Schema
+	fromChild := func(exprs ...expression.Expression) bool {
+		for _, col := range expression.ExtractColumnsFromExpressions(nil, exprs, nil) {
+			if !childSchema.Contains(col) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// The partial Aggregation is grouped by the columns of the child used by
+	// the group-by items and the join conditions.
+	gbySchema := expression.NewSchema()
+	addGbyCols := func(exprs ...expression.Expression) {
+		for _, col := range expression.ExtractColumnsFromExpressions(nil, exprs, nil) {
+			if childSchema.Contains(col) && !gbySchema.Contains(col) {
+				gbySchema.Append(col)
+			}
+		}
+	}
+	addGbyCols(agg.GroupByItems...)
+	addGbyCols(expression.ScalarFuncs2Exprs(join.EqualConditions)...)
+	addGbyCols(join.LeftConditions...)
+	addGbyCols(join.RightConditions...)
+	addGbyCols(join.OtherConditions...)
+	// It's no need to push the Aggregation down if each group has only one row.
+	for _, key := range childSchema.Keys {
+		if gbySchema.ColumnsIndices(key) != nil {
+			return nil, nil
+		}
+	}
+
+	partialAgg := plannercore.LogicalAggregation{
+		GroupByItems: expression.Column2Exprs(gbySchema.Columns),
+	}.Init(sctx, agg.SelectBlockOffset())
+	partialSchema := expression.NewSchema()
+	finalAggFuncs := make([]*aggregation.AggFuncDesc, 0, len(agg.AggFuncs))
+	pushed := false
+	for _, aggFunc := range agg.AggFuncs {
+		finalAggFunc := aggFunc.Clone()
+		if aggFunc.Name == ast.AggFuncFirstRow {
+			// The firstrow only using the columns of the other child or the
+			// group-by columns of the partial Aggregation is evaluated above the Join.
+			cols := expression.ExtractColumnsFromExpressions(nil, aggFunc.Args, nil)
+			if len(cols) == 0 || !fromChild(aggFunc.Args...) || gbySchema.ColumnsIndices(cols) != nil {
+				finalAggFuncs = append(finalAggFuncs, finalAggFunc)
+				continue
+			}
+		} else if !fromChild(aggFunc.Args...) {
+			return nil, nil
+		} else {
+			pushed = true
+		}
+		col := &expression.Column{
+			UniqueID: sctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  aggFunc.RetTp,
+		}
+		partialAgg.AggFuncs = append(partialAgg.AggFuncs, aggFunc.Clone())
+		partialSchema.Append(col)
+		finalAggFunc.Args = []expression.Expression{col}
+		finalAggFunc.Mode = aggregation.FinalMode
+		finalAggFuncs = append(finalAggFuncs, finalAggFunc)
+	}
+	if !pushed {
+		return nil, nil
+	}
+	for _, col := range gbySchema.Columns {
+		firstRow, err := aggregation.NewAggFuncDesc(sctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
+		if err != nil {
+			return nil, err
+		}
+		partialAgg.AggFuncs = append(partialAgg.AggFuncs, firstRow)
+		partialSchema.Append(col)
+	}
+	// The Aggregation without group-by items returns a row for the empty input,
+	// so a constant group-by item is added like the logical optimizer does.
+	if len(partialAgg.GroupByItems) == 0 {
+		partialAgg.GroupByItems = []expression.Expression{&expression.Constant{
+			Value:   types.NewDatum(0),
+			RetType: types.NewFieldType(mysql.TypeLong),
+		}}
+	}
+	partialAgg.SetSchema(partialSchema)
+	partialAggExpr := memo.NewGroupExpr(partialAgg)
+	partialAggExpr.SetChildren(childGroup)
+	partialAggExpr.AddAppliedRule(r)
+
+	children := make([]*memo.Group, len(joinExpr.Children))
+	copy(children, joinExpr.Children)
+	children[childIdx] = memo.NewGroupWithSchema(partialAggExpr, partialSchema)
+	newJoin := join.Shallow()
+	newJoin.SetSchema(expression.MergeSchema(children[0].Prop.Schema, children[1].Prop.Schema))
+	newJoinExpr := memo.NewGroupExpr(newJoin)
+	newJoinExpr.SetChildren(children...)
+
+	finalAgg := plannercore.LogicalAggregation{
+		AggFuncs:     finalAggFuncs,
+		GroupByItems: agg.GroupByItems,
+	}.Init(sctx, agg.SelectBlockOffset())
+	finalAgg.SetSchema(old.GetExpr().Schema())
+	finalAggExpr := memo.NewGroupExpr(finalAgg)
+	finalAggExpr.SetChildren(memo.NewGroupWithSchema(newJoinExpr, newJoin.Schema()))
+	finalAggExpr.AddAppliedRule(r)
+	return finalAggExpr, nil
+}
+
 // PushSelDownUnionAll pushes selection through union all.
 type PushSelDownUnionAll struct {
 	baseRule
@@ -1080,7 +1734,7 @@ func NewRulePushSelDownUnionAll() Transformation {
 // It will transform `Selection->UnionAll->x` to `UnionAll->Selection->x`.
 func (*PushSelDownUnionAll) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
 	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
-	unionAll := old.Children[0].GetExpr().ExprNode.(*plannercore.LogicalUnionAll)
+	unionAll := old.Children[0].GetExpr().ExprNode
 	childGroups := old.Children[0].GetExpr().Children
 
 	newUnionAllExpr := memo.NewGroupExpr(unionAll)
@@ -1358,7 +2012,7 @@ func (r *PushTopNDownUnionAll) Match(expr *memo.ExprIter) bool {
 // It will transform `TopN->UnionAll->X` to `TopN->UnionAll->TopN->X`.
 func (r *PushTopNDownUnionAll) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
 	topN := old.GetExpr().ExprNode.(*plannercore.LogicalTopN)
-	unionAll := old.Children[0].GetExpr().ExprNode.(*plannercore.LogicalUnionAll)
+	unionAll := old.Children[0].GetExpr().ExprNode
 
 	newTopN := plannercore.LogicalTopN{
 		Count:   topN.Count + topN.Offset,
@@ -1665,8 +2319,16 @@ func NewRuleMergeAdjacentSelection() Transformation {
 	return rule
 }
 
+// Match implements Transformation interface.
+func (*MergeAdjacentSelection) Match(expr *memo.ExprIter) bool {
+	// The predicates above the row access filters may leak the values of the filtered rows, they're not merged.
+	return !expr.Children[0].GetExpr().ExprNode.(*plannercore.LogicalSelection).RowAccessFilter
+}
+
 // OnTransform implements Transformation interface.
-// This rule tries to merge adjacent selection, with no simplification.
+// This rule tries to merge adjacent selection, the duplicated conditions, e.g. the
+// not null conditions derived by the equivalent joins, are removed, but there is no
+// other simplification.
 func (*MergeAdjacentSelection) OnTransform(old *memo.ExprIter) (newExprs []*memo.GroupExpr, eraseOld bool, eraseAll bool, err error) {
 	sel := old.GetExpr().ExprNode.(*plannercore.LogicalSelection)
 	child := old.Children[0].GetExpr().ExprNode.(*plannercore.LogicalSelection)
@@ -1675,6 +2337,7 @@ func (*MergeAdjacentSelection) OnTransform(old *memo.ExprIter) (newExprs []*memo
 	conditions := make([]expression.Expression, 0, len(sel.Conditions)+len(child.Conditions))
 	conditions = append(conditions, sel.Conditions...)
 	conditions = append(conditions, child.Conditions...)
+	conditions = expression.RemoveDupExprs(sel.SCtx(), conditions)
 	newSel := plannercore.LogicalSelection{Conditions: conditions}.Init(sel.SCtx(), sel.SelectBlockOffset())
 	newSelExpr := memo.NewGroupExpr(newSel)
 	newSelExpr.SetChildren(childGroups...)
@@ -491,3 +491,75 @@ func TestMergeAdjacentWindow(t *testing.T) {
 	transformationRulesSuiteData.LoadTestCases(t, &input, &output)
 	testGroupToString(t, input, output, optimizer)
 }
+
+func TestJoinReorder(t *testing.T) {
+	optimizer := NewOptimizer()
+	// The conditions are pushed down to the joins before the join reorder.
+	optimizer.ResetTransformationRules(TiDBLayerOptimizationBatch, JoinReorderBatch)
+	defer func() {
+		optimizer.ResetTransformationRules(DefaultRuleBatches...)
+	}()
+
+	p := parser.New()
+	ctx := plannercore.MockContext()
+	is := infoschema.MockInfoSchema([]*model.TableInfo{plannercore.MockSignedTable()})
+	domain.GetDomain(ctx).MockInfoCacheAndLoadInfoSchema(is)
+
+	tests := []struct {
+		sql       string
+		joinExprs int
+		projExprs int
+	}{
+		// Join(Join(t1, t2), t3): Join(t1, Join(t2, t3)), Proj(Join(Join(t2, t3), t1)), Proj(Join(t3, Join(t1, t2))).
+		{"select t1.a, t2.b, t3.c from t t1 join t t2 on t1.a = t2.a join t t3 on t2.b = t3.b", 2, 2},
+		// The cartesian product is only used to join the connected components.
+		// Join(Join(t1, t2), t3): Proj(Join(t3, Join(t1, t2))).
+		{"select t1.a, t2.b, t3.c from t t1 join t t2 on t1.a = t2.a join t t3", 1, 1},
+		// The join of two leaves is not swapped.
+		{"select t1.a, t2.b from t t1 join t t2", 1, 0},
+		// The inner side of the outer join is outer joined after the leaves referenced by the outer join conditions.
+		// Join(LeftJoin(t1, t2), t3): Proj(LeftJoin(Join(t1, t3), t2)), Proj(Join(t3, LeftJoin(t1, t2))).
+		{"select t1.a, t2.b, t3.c from t t1 left join t t2 on t1.a = t2.a join t t3 on t1.b = t3.b", 1, 2},
+		// Join(RightJoin(t1, t2), t3): Proj(LeftJoin(Join(t2, t3), t1)), Proj(Join(t3, RightJoin(t1, t2))).
+		{"select t1.a, t2.b, t3.c from t t1 right join t t2 on t1.a = t2.a join t t3 on t2.b = t3.b", 1, 2},
+		// Join(LeftJoin(LeftJoin(t1, t2), t3), t4): Proj(LeftJoin(LeftJoin(Join(t1, t4), t3), t2)), Proj(LeftJoin(LeftJoin(Join(t1, t4), t2), t3)), Proj(Join(t4, LeftJoin(LeftJoin(t1, t2), t3))).
+		{"select t1.a, t2.b, t3.c from t t1 left join t t2 on t1.a = t2.a left join t t3 on t1.b = t3.b join t t4 on t1.c = t4.c", 1, 3},
+		// The outer join is a leaf of the join tree if its inner side is referenced by the inner join conditions.
+		// Join(Join(LeftJoin(t1, t2), t3), t4): Join(LeftJoin(t1, t2), Join(t3, t4)), Proj(Join(Join(t3, t4), LeftJoin(t1, t2))), Proj(Join(t4, Join(LeftJoin(t1, t2), t3))).
+		{"select t1.a, t2.b, t3.c from t t1 left join t t2 on t1.a = t2.a join t t3 on t2.b = t3.b join t t4 on t3.c = t4.c", 2, 2},
+		{"select t1.a, t2.b from t t1 straight_join t t2 on t1.a = t2.a", 1, 0},
+		// The join with join method hints is a leaf of the join tree.
+		{"select /*+ HASH_JOIN(t1) */ t1.a, t2.b, t3.c from t t1 join t t2 on t1.a = t2.a join t t3 on t2.b = t3.b", 1, 0},
+	}
+	for i, tt := range tests {
+		stmt, err := p.ParseOneStmt(tt.sql, "", "")
+		require.NoError(t, err)
+		plan, _, err := plannercore.BuildLogicalPlanForTest(context.Background(), ctx, stmt, is)
+		require.NoError(t, err)
+		logic, err := optimizer.onPhasePreprocessing(ctx, plan.(plannercore.LogicalPlan))
+		require.NoError(t, err)
+		group := memo.Convert2Group(logic)
+		require.NoError(t, optimizer.onPhaseExploration(ctx, group))
+
+		// The root Group is the Projection of the select fields if it's not eliminated.
+		joinGroup := group
+		if group.GetFirstElem(memo.OperandJoin) == nil {
+			joinGroup = group.GetFirstElem(memo.OperandProjection).Value.(*memo.GroupExpr).Children[0]
+		}
+		joinExprs, projExprs := 0, 0
+		for elem := joinGroup.Equivalents.Front(); elem != nil; elem = elem.Next() {
+			expr := elem.Value.(*memo.GroupExpr)
+			switch expr.ExprNode.(type) {
+			case *plannercore.LogicalJoin:
+				joinExprs++
+			case *plannercore.LogicalProjection:
+				projExprs++
+				// The Projection keeps the order of the output columns.
+				require.Equalf(t, joinGroup.Prop.Schema.Len(), expr.Children[0].Prop.Schema.Len(), "case:%v, sql:%s", i, tt.sql)
+				require.Falsef(t, joinGroup.Prop.Schema.ColumnsIndices(expr.Children[0].Prop.Schema.Columns) == nil, "case:%v, sql:%s", i, tt.sql)
+			}
+		}
+		require.Equalf(t, tt.joinExprs, joinExprs, "case:%v, sql:%s", i, tt.sql)
+		require.Equalf(t, tt.projExprs, projExprs, "case:%v, sql:%s", i, tt.sql)
+	}
+}
@@ -301,6 +301,18 @@ func (p *PhysicalMemTable) AccessObject() AccessObject {
 	}
 }
 
+// AccessObject implements dataAccesser interface.
+func (p *PhysicalTableChanges) AccessObject() AccessObject {
+	res := &ScanAccessObject{
+		Database: p.DBName.O,
+		Table:    p.Table.Name.O,
+	}
+	for _, name := range p.PartitionNames {
+		res.Partitions = append(res.Partitions, name.O)
+	}
+	return res
+}
+
 // AccessObject implements dataAccesser interface.
 func (p *PointGetPlan) AccessObject() AccessObject {
 	res := &ScanAccessObject{
@@ -378,6 +378,10 @@ type Insert struct {
 
 	RowLen int
 
+	// RowAccessChecks are the row access policies checked against the inserted rows and the rows
+	// replaced or updated by REPLACE and ON DUPLICATE KEY UPDATE.
+	RowAccessChecks []*RowAccessCheck
+
 	FKChecks   []*FKCheck
 	FKCascades []*FKCascade
 }
@@ -443,6 +447,9 @@ type Update struct {
 
 	tblID2Table map[int64]table.Table
 
+	// RowAccessChecks are the row access policies checked against the updated rows, the map is tableID -> []*RowAccessCheck
+	RowAccessChecks map[int64][]*RowAccessCheck
+
 	FKChecks   map[int64][]*FKCheck
 	FKCascades map[int64][]*FKCascade
 }
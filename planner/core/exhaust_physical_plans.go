@@ -3397,7 +3397,8 @@ func (p *LogicalSelection) exhaustPhysicalPlans(prop *property.PhysicalProperty)
 	ret := make([]PhysicalPlan, 0, len(newProps))
 	for _, newProp := range newProps {
 		sel := PhysicalSelection{
-			Conditions: p.Conditions,
+			Conditions:      p.Conditions,
+			rowAccessFilter: p.RowAccessFilter,
 		}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset, newProp)
 		ret = append(ret, sel)
 	}
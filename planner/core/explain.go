@@ -1091,6 +1091,16 @@ func (p *TiKVSingleGather) ExplainInfo() string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalTableChanges) ExplainInfo() string {
+	return p.AccessObject().String() + ", " + p.OperatorInfo(false)
+}
+
+// OperatorInfo implements dataAccesser interface.
+func (p *PhysicalTableChanges) OperatorInfo(_ bool) string {
+	return fmt.Sprintf("start_ts:%d, end_ts:%d", p.StartTS, p.EndTS)
+}
+
 // MetricTableTimeFormat is the time format for metric table explain and format.
 const MetricTableTimeFormat = "2006-01-02 15:04:05.999"
 
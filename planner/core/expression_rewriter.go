@@ -2091,7 +2091,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 			er.err = ErrUnknownColumn.GenWithStackByArgs(v.Name, clauseMsg[er.b.curClause])
 			return
 		}
-		er.ctxStackAppend(column, er.names[idx])
+		er.appendMaybeMaskedColumn(column, column, er.names[idx])
 		return
 	}
 	col, name, err := findFieldNameFromNaturalUsingJoin(er.p, v)
@@ -2099,7 +2099,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.err = err
 		return
 	} else if col != nil {
-		er.ctxStackAppend(col, name)
+		er.appendMaybeMaskedColumn(col, col, name)
 		return
 	}
 	for i := len(er.b.outerSchemas) - 1; i >= 0; i-- {
@@ -2107,7 +2107,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		idx, err = expression.FindFieldName(outerName, v)
 		if idx >= 0 {
 			column := outerSchema.Columns[idx]
-			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
+			er.appendMaybeMaskedColumn(column, &expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
 			return
 		}
 		if err != nil {
@@ -2125,6 +2125,16 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 	er.err = ErrUnknownColumn.GenWithStackByArgs(v.String(), clauseMsg[er.b.curClause])
 }
 
+// appendMaybeMaskedColumn appends `expr` referring to the column `col`, it's masked if `col` is a masked column of
+// the tables written back by UPDATE and DELETE.
+func (er *expressionRewriter) appendMaybeMaskedColumn(col *expression.Column, expr expression.Expression, name *types.FieldName) {
+	expr, er.err = er.b.maskWriteBackColumn(col, expr)
+	if er.err != nil {
+		return
+	}
+	er.ctxStackAppend(expr, name)
+}
+
 func findFieldNameFromNaturalUsingJoin(p LogicalPlan, v *ast.ColumnName) (col *expression.Column, name *types.FieldName, err error) {
 	switch x := p.(type) {
 	case *LogicalLimit, *LogicalSelection, *LogicalTopN, *LogicalSort, *LogicalMaxOneRow:
@@ -671,6 +671,25 @@ func (p *LogicalMemTable) findBestTask(prop *property.PhysicalProperty, planCoun
 	return &rootTask{p: memTable}, 1, nil
 }
 
+func (p *LogicalTableChanges) findBestTask(prop *property.PhysicalProperty, planCounter *PlanCounterTp, opt *physicalOptimizeOp) (task, int64, error) {
+	if !prop.IsSortItemEmpty() || prop.MPPPartitionTp != property.AnyType || planCounter.Empty() {
+		return invalidTask, 0, nil
+	}
+	tableChanges := PhysicalTableChanges{
+		DBName:           p.DBName,
+		Table:            p.Table,
+		PhysicalTableIDs: p.PhysicalTableIDs,
+		PartitionNames:   p.PartitionNames,
+		StartTS:          p.StartTS,
+		EndTS:            p.EndTS,
+		Columns:          p.Columns,
+	}.Init(p.ctx, p.stats, p.blockOffset)
+	tableChanges.SetSchema(p.schema)
+	planCounter.Dec(1)
+	opt.appendCandidate(p, tableChanges, prop)
+	return &rootTask{p: tableChanges}, 1, nil
+}
+
 // tryToGetDualTask will check if the push down predicate has false constant. If so, it will return table dual.
 func (ds *DataSource) tryToGetDualTask() (task, error) {
 	for _, cond := range ds.pushedDownConds {
@@ -1119,7 +1138,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		var hashPartColName *model.CIStr
 		if tblInfo := ds.table.Meta(); canConvertPointGet && tblInfo.GetPartitionInfo() != nil {
 			// We do not build [batch] point get for dynamic table partitions now. This can be optimized.
-			if ds.ctx.GetSessionVars().StmtCtx.UseDynamicPartitionPrune() {
+			// A point get on a global index finds the partition of the row by the index value.
+			if ds.ctx.GetSessionVars().StmtCtx.UseDynamicPartitionPrune() &&
+				(path.Index == nil || !path.Index.Global || len(path.Ranges) > 1 || len(ds.partitionNames) > 0) {
 				canConvertPointGet = false
 			}
 			if canConvertPointGet && len(path.Ranges) > 1 {
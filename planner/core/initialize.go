@@ -331,6 +331,19 @@ func (p PhysicalMemTable) Init(ctx sessionctx.Context, stats *property.StatsInfo
 	return &p
 }
 
+// Init initializes LogicalTableChanges.
+func (p LogicalTableChanges) Init(ctx sessionctx.Context, offset int) *LogicalTableChanges {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeTableChanges, &p, offset)
+	return &p
+}
+
+// Init initializes PhysicalTableChanges.
+func (p PhysicalTableChanges) Init(ctx sessionctx.Context, stats *property.StatsInfo, offset int) *PhysicalTableChanges {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeTableChanges, &p, offset)
+	p.stats = stats
+	return &p
+}
+
 // Init initializes PhysicalHashJoin.
 func (p PhysicalHashJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo, offset int, props ...*property.PhysicalProperty) *PhysicalHashJoin {
 	tp := plancodec.TypeHashJoin
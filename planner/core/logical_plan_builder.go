@@ -60,6 +60,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/logutil"
@@ -504,6 +505,9 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 			isTableName = true
+		case *ast.TableChanges:
+			p, err = b.buildTableChanges(ctx, v, &x.AsName)
+			isTableName = true
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -5098,6 +5102,14 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		}
 		result = us
 	}
+	result, err = b.buildRowAccessFilter(result, tableInfo)
+	if err != nil {
+		return nil, err
+	}
+	result, err = b.buildMaskingProjection(result, tn, ds, columns)
+	if err != nil {
+		return nil, err
+	}
 
 	// Adding ExtraPhysTblIDCol for SelectLock (SELECT FOR UPDATE) is done when building SelectLock
 
@@ -5337,7 +5349,7 @@ func (b *PlanBuilder) buildMemTable(_ context.Context, dbName model.CIStr, table
 			p.Extractor = &TableStorageStatsExtractor{}
 		case infoschema.TableTiFlashTables, infoschema.TableTiFlashSegments:
 			p.Extractor = &TiFlashSystemTableExtractor{}
-		case infoschema.TableStatementsSummary, infoschema.TableStatementsSummaryHistory:
+		case infoschema.TableStatementsSummary, infoschema.TableStatementsSummaryHistory, infoschema.TableClusterStatementsDigestHistory:
 			p.Extractor = &StatementsSummaryExtractor{}
 		case infoschema.TableTiKVRegionPeers:
 			p.Extractor = &TikvRegionPeersExtractor{}
@@ -5350,6 +5362,161 @@ func (b *PlanBuilder) buildMemTable(_ context.Context, dbName model.CIStr, table
 	return p, nil
 }
 
+const (
+	// TableChangesOpColName is the name of the column which is the type of the change returned by TABLE_CHANGES,
+	// it's one of `insert`, `update` and `delete`.
+	TableChangesOpColName = "_tidb_op"
+	// TableChangesCommitTSColName is the name of the column which is the commit ts of the change returned by TABLE_CHANGES.
+	TableChangesCommitTSColName = "_tidb_commit_ts"
+)
+
+// buildTableChanges builds the plan of TABLE_CHANGES(tbl, start_ts, end_ts), which returns the row changes of
+// the table committed in the time range (start_ts, end_ts]. The output columns are `_tidb_op`, `_tidb_commit_ts`
+// and the columns of the table.
+func (b *PlanBuilder) buildTableChanges(ctx context.Context, tc *ast.TableChanges, asName *model.CIStr) (LogicalPlan, error) {
+	tn := tc.Table
+	dbName := tn.Schema
+	sessionVars := b.ctx.GetSessionVars()
+	if dbName.L == "" {
+		dbName = model.NewCIStr(sessionVars.CurrentDB)
+	}
+	tbl, err := b.is.TableByName(dbName, tn.Name)
+	if err != nil {
+		return nil, err
+	}
+	tableInfo := tbl.Meta()
+	if tbl.Type().IsVirtualTable() || tableInfo.IsView() || tableInfo.IsSequence() {
+		return nil, dbterror.ErrWrongObject.GenWithStackByArgs(dbName.O, tableInfo.Name.O, "BASE TABLE")
+	}
+	if tableInfo.TempTableType != model.TempTableNone {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("TABLE_CHANGES on temporary tables")
+	}
+
+	var authErr error
+	if sessionVars.User != nil {
+		authErr = ErrTableaccessDenied.FastGenByArgs("SELECT", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tableInfo.Name.L)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
+
+	startTS, err := calcTSForTableChanges(ctx, b.ctx, tc.StartTS)
+	if err != nil {
+		return nil, err
+	}
+	endTS, err := calcTSForTableChanges(ctx, b.ctx, tc.EndTS)
+	if err != nil {
+		return nil, err
+	}
+	if startTS >= endTS {
+		return nil, ErrWrongArguments.GenWithStackByArgs("TABLE_CHANGES, the start ts must be less than the end ts")
+	}
+	// The changes before the GC safe point may have been removed, and the changes after the current ts are unknown.
+	if err := gcutil.ValidateSnapshot(b.ctx, startTS); err != nil {
+		return nil, err
+	}
+	ver, err := b.ctx.GetStore().CurrentVersion(kv.GlobalTxnScope)
+	if err != nil {
+		return nil, err
+	}
+	if endTS > ver.Ver {
+		return nil, ErrWrongArguments.GenWithStackByArgs(fmt.Sprintf("TABLE_CHANGES, the end ts %d is later than the current ts %d", endTS, ver.Ver))
+	}
+
+	var physicalIDs []int64
+	if pi := tableInfo.GetPartitionInfo(); pi != nil {
+		if len(tn.PartitionNames) > 0 {
+			for _, name := range tn.PartitionNames {
+				pid, err := tables.FindPartitionByName(tableInfo, name.L)
+				if err != nil {
+					return nil, err
+				}
+				physicalIDs = append(physicalIDs, pid)
+			}
+		} else {
+			for _, def := range pi.Definitions {
+				physicalIDs = append(physicalIDs, def.ID)
+			}
+		}
+	} else if len(tn.PartitionNames) != 0 {
+		return nil, ErrPartitionClauseOnNonpartitioned
+	} else {
+		physicalIDs = []int64{tableInfo.ID}
+	}
+
+	tblName := *asName
+	if tblName.L == "" {
+		tblName = tn.Name
+	}
+	columns := make([]*table.Column, 0, len(tableInfo.Columns))
+	colInfos := make([]*model.ColumnInfo, 0, len(tableInfo.Columns))
+	for _, col := range tbl.Cols() {
+		if col.Hidden || (col.IsGenerated() && !col.GeneratedStored) {
+			continue
+		}
+		columns, colInfos = append(columns, col), append(colInfos, col.ColumnInfo)
+	}
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(columns)+2)...)
+	names := make([]*types.FieldName, 0, len(columns)+2)
+	opType := types.NewFieldType(mysql.TypeVarchar)
+	opType.SetFlen(6)
+	opType.SetFlag(mysql.NotNullFlag)
+	opType.SetCharset(mysql.DefaultCharset)
+	opType.SetCollate(mysql.DefaultCollationName)
+	tsType := types.NewFieldType(mysql.TypeLonglong)
+	tsType.SetFlag(mysql.NotNullFlag | mysql.UnsignedFlag)
+	for _, extra := range []struct {
+		name string
+		tp   *types.FieldType
+	}{{TableChangesOpColName, opType}, {TableChangesCommitTSColName, tsType}} {
+		schema.Append(&expression.Column{
+			UniqueID: sessionVars.AllocPlanColumnID(),
+			RetType:  extra.tp,
+		})
+		names = append(names, &types.FieldName{
+			DBName:  dbName,
+			TblName: tblName,
+			ColName: model.NewCIStr(extra.name),
+		})
+	}
+	for _, col := range columns {
+		schema.Append(&expression.Column{
+			UniqueID: sessionVars.AllocPlanColumnID(),
+			ID:       col.ID,
+			RetType:  col.FieldType.Clone(),
+			OrigName: fmt.Sprintf("%v.%v.%v", dbName.L, tblName.L, col.Name.L),
+		})
+		names = append(names, &types.FieldName{
+			DBName:      dbName,
+			TblName:     tblName,
+			ColName:     col.Name,
+			OrigTblName: tableInfo.Name,
+			OrigColName: col.Name,
+		})
+	}
+	b.handleHelper.pushMap(nil)
+
+	p := LogicalTableChanges{
+		DBName:           dbName,
+		Table:            tableInfo,
+		PhysicalTableIDs: physicalIDs,
+		PartitionNames:   tn.PartitionNames,
+		StartTS:          startTS,
+		EndTS:            endTS,
+		Columns:          colInfos,
+	}.Init(b.ctx, b.getSelectOffset())
+	p.SetSchema(schema)
+	p.names = names
+	result, err := b.buildRowAccessFilter(p, tableInfo)
+	if err != nil {
+		return nil, err
+	}
+	masks := b.collectColumnMasks(dbName, tableInfo, columns)
+	if len(masks) == 0 {
+		return result, nil
+	}
+	// The table columns follow `_tidb_op` and `_tidb_commit_ts`.
+	return b.buildColumnMaskProjection(result, masks, 2)
+}
+
 // checkRecursiveView checks whether this view is recursively defined.
 func (b *PlanBuilder) checkRecursiveView(dbName model.CIStr, tableName model.CIStr) (func(), error) {
 	viewFullName := dbName.L + "." + tableName.L
@@ -5753,6 +5920,9 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 
 	b.inUpdateStmt = true
 	b.isForUpdateRead = true
+	b.writeBackTables = make(map[*ast.TableName]struct{})
+	b.writeBackMaskedCols = make(map[int64]*columnMask)
+	collectWriteBackTables(update.TableRefs.TableRefs, b.writeBackTables)
 
 	if update.With != nil {
 		l := len(b.outerCTEs)
@@ -5861,6 +6031,18 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 	}
 	updt.PartitionedTable = b.partitionedTable
 	updt.tblID2Table = tblID2table
+	for id, tbl := range tblID2table {
+		checks, err := b.buildRowAccessChecks(tbl.Meta(), tbl.WritableCols())
+		if err != nil {
+			return nil, err
+		}
+		if len(checks) > 0 {
+			if updt.RowAccessChecks == nil {
+				updt.RowAccessChecks = make(map[int64][]*RowAccessCheck)
+			}
+			updt.RowAccessChecks[id] = checks
+		}
+	}
 	err = updt.buildOnUpdateFKTriggers(b.ctx, b.is, tblID2table)
 	return updt, err
 }
@@ -6083,8 +6265,11 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 
 			o := b.allowBuildCastArray
 			b.allowBuildCastArray = true
+			// The generated columns are computed from the unmasked values written back.
+			b.rewriteUnmasked = true
 			newExpr, np, err = b.rewriteWithPreprocess(ctx, assign.Expr, p, nil, nil, false, rewritePreprocess(assign))
 			b.allowBuildCastArray = o
+			b.rewriteUnmasked = false
 			if err != nil {
 				return nil, nil, false, err
 			}
@@ -6160,6 +6345,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, ds *ast.DeleteStmt) (Plan
 
 	b.inDeleteStmt = true
 	b.isForUpdateRead = true
+	b.writeBackTables = make(map[*ast.TableName]struct{})
+	b.writeBackMaskedCols = make(map[int64]*columnMask)
+	collectWriteBackTables(ds.TableRefs.TableRefs, b.writeBackTables)
 
 	if ds.With != nil {
 		l := len(b.outerCTEs)
@@ -208,6 +208,11 @@ func (p *LogicalJoin) isNAAJ() bool {
 	return len(p.NAEQConditions) > 0
 }
 
+// HasJoinMethodHint returns whether the join method is specified by the hints.
+func (p *LogicalJoin) HasJoinMethodHint() bool {
+	return p.preferJoinType > 0
+}
+
 // Shallow shallow copies a LogicalJoin struct.
 func (p *LogicalJoin) Shallow() *LogicalJoin {
 	join := *p
@@ -831,6 +836,9 @@ type LogicalProjection struct {
 	// Proj4Expand is used for expand to project same column reference, while these
 	// col may be filled with null so we couldn't just eliminate this projection itself.
 	Proj4Expand bool
+
+	// Proj4Mask is used to mask the columns bound to masking policies above a data source.
+	Proj4Mask bool
 }
 
 // ExtractFD implements the logical plan interface, extracting the FD from bottom up.
@@ -1190,6 +1198,10 @@ type LogicalSelection struct {
 	// but after we converted to CNF(Conjunctive normal form), it can be
 	// split into a list of AND conditions.
 	Conditions []expression.Expression
+
+	// RowAccessFilter indicates the selection filters the rows by the row access policies, the predicates
+	// above it which may leak the values of the filtered rows by their warnings or errors aren't pushed below it.
+	RowAccessFilter bool
 }
 
 func extractNotNullFromConds(conditions []expression.Expression, p LogicalPlan) fd.FastIntSet {
@@ -1429,6 +1441,22 @@ type LogicalMemTable struct {
 	QueryTimeRange QueryTimeRange
 }
 
+// LogicalTableChanges represents the table function TABLE_CHANGES, which reads the changes of a table
+// committed in the time range (StartTS, EndTS] from the MVCC history of the rows.
+type LogicalTableChanges struct {
+	logicalSchemaProducer
+
+	DBName model.CIStr
+	Table  *model.TableInfo
+	// PhysicalTableIDs are the IDs of the table or the partitions whose changes are read.
+	PhysicalTableIDs []int64
+	PartitionNames   []model.CIStr
+	StartTS          uint64
+	EndTS            uint64
+	// Columns are the table columns output after the `_tidb_op` and `_tidb_commit_ts` columns.
+	Columns []*model.ColumnInfo
+}
+
 // LogicalUnionScan is used in non read-only txn or for scanning a local temporary table whose snapshot data is located in memory.
 type LogicalUnionScan struct {
 	baseLogicalPlan
@@ -0,0 +1,348 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/extension"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+)
+
+const fullMaskString = "XXXX"
+
+// canReadUnmasked checks whether the current user can read the columns with masking policies unmasked.
+// Only the sessions without a user, e.g. the internal sessions of DDL and the system sessions, are exempted,
+// the internal SQL executed on behalf of a user, e.g. the timer statements, is checked against the user.
+func canReadUnmasked(sctx sessionctx.Context) bool {
+	vars := sctx.GetSessionVars()
+	if vars.User == nil {
+		return true
+	}
+	pm := privilege.GetPrivilegeManager(sctx)
+	return pm == nil || pm.RequestDynamicVerification(vars.ActiveRoles, "UNMASKED_READ", false)
+}
+
+// columnMask masks a column by the mask functions of the session extensions and then by the masking policy.
+type columnMask struct {
+	policy *model.MaskingPolicyInfo
+	// extColumn is the column masked by the extensions, nil means it's not masked by them.
+	extColumn *extension.ResultColumn
+}
+
+// collectColumnMasks returns the masks of the columns read from the table by their offsets. The columns are masked
+// by the masking policies of the table unless the user can read them unmasked, and by the mask functions of the
+// session extensions. The generated columns depending on the masked columns are replaced by NULL, otherwise the
+// masked values can be inferred from them.
+func (b *PlanBuilder) collectColumnMasks(dbName model.CIStr, tableInfo *model.TableInfo, columns []*table.Column) map[int]*columnMask {
+	masks := make(map[int]*columnMask)
+	if len(tableInfo.MaskingPolicies) > 0 {
+		// The plan depends on the privileges of the user.
+		b.ctx.GetSessionVars().StmtCtx.SetSkipPlanCache(errors.New("table has masking policies"))
+		if !canReadUnmasked(b.ctx) {
+			for i, col := range columns {
+				if policy := tableInfo.FindMaskingPolicyByColumnID(col.ID); policy != nil {
+					masks[i] = &columnMask{policy: policy}
+				}
+			}
+		}
+	}
+	if extensions := b.ctx.GetExtensions(); extensions.HasResultMasks() {
+		// The plan depends on the session, which the mask functions are decided by.
+		b.ctx.GetSessionVars().StmtCtx.SetSkipPlanCache(errors.New("columns may be masked by the extensions"))
+		cols := make([]*extension.ResultColumn, 0, len(columns))
+		for _, col := range columns {
+			cols = append(cols, &extension.ResultColumn{
+				DBName:     dbName.L,
+				TableName:  tableInfo.Name.L,
+				ColumnName: col.Name.L,
+				Type:       &col.FieldType,
+			})
+		}
+		for i, fn := range extensions.ResultMaskFuncs(extension.NewSessionInfo(b.ctx.GetSessionVars()), cols) {
+			if fn == nil {
+				continue
+			}
+			if masks[i] == nil {
+				masks[i] = &columnMask{}
+			}
+			masks[i].extColumn = cols[i]
+		}
+	}
+	if len(masks) == 0 {
+		return nil
+	}
+
+	maskedNames := make(map[string]struct{}, len(masks))
+	for i := range masks {
+		maskedNames[columns[i].Name.L] = struct{}{}
+	}
+	for i, col := range columns {
+		if _, ok := masks[i]; ok || !col.IsGenerated() || col.Hidden {
+			continue
+		}
+		for dep := range col.Dependences {
+			if _, ok := maskedNames[dep]; ok {
+				masks[i] = &columnMask{policy: &model.MaskingPolicyInfo{ColumnID: col.ID, Type: model.MaskingNull}}
+				break
+			}
+		}
+	}
+	return masks
+}
+
+// buildMaskingProjection puts a projection above the data source to mask the columns by collectColumnMasks,
+// so all the operators above it, including the expressions, the filters, the joins, the subqueries, the
+// INSERT ... SELECT and the SELECT INTO OUTFILE, see the masked values.
+// The rows of the tables written back by UPDATE and DELETE must not be masked, so no projection is
+// built for them, the references to their masked columns are masked by maskWriteBackColumn instead.
+func (b *PlanBuilder) buildMaskingProjection(p LogicalPlan, tn *ast.TableName, ds *DataSource, columns []*table.Column) (LogicalPlan, error) {
+	masks := b.collectColumnMasks(ds.DBName, ds.tableInfo, columns)
+	if len(masks) == 0 {
+		return p, nil
+	}
+
+	if _, ok := b.writeBackTables[tn]; ok {
+		for i, mask := range masks {
+			b.writeBackMaskedCols[ds.Schema().Columns[i].UniqueID] = mask
+		}
+		return p, nil
+	}
+	return b.buildColumnMaskProjection(p, masks, 0)
+}
+
+// buildColumnMaskProjection builds the projection masking the columns of `p` by `masks`, which are keyed by the
+// offsets of the table columns, and the table columns start at `offset` in the schema of `p`.
+func (b *PlanBuilder) buildColumnMaskProjection(p LogicalPlan, masks map[int]*columnMask, offset int) (LogicalPlan, error) {
+	pSchema, pNames := p.Schema(), p.OutputNames()
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, pSchema.Len()), Proj4Mask: true}.Init(b.ctx, b.getSelectOffset())
+	schema := expression.NewSchema(make([]*expression.Column, 0, pSchema.Len())...)
+	names := make(types.NameSlice, 0, pSchema.Len())
+	for i, col := range pSchema.Columns {
+		mask, ok := masks[i-offset]
+		if !ok {
+			proj.Exprs = append(proj.Exprs, col)
+			schema.Append(col)
+			names = append(names, pNames[i])
+			continue
+		}
+		expr, err := b.buildColumnMaskExpr(col, mask)
+		if err != nil {
+			return nil, err
+		}
+		proj.Exprs = append(proj.Exprs, expr)
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  expr.GetType().Clone(),
+			OrigName: col.OrigName,
+			IsHidden: col.IsHidden,
+		})
+		names = append(names, pNames[i])
+	}
+	proj.SetSchema(schema)
+	proj.SetOutputNames(names)
+	proj.SetChildren(p)
+	return proj, nil
+}
+
+// hasColumnMasks checks whether the columns of the table may be masked, the fast plans aren't built for such tables
+// because the columns are masked by the projection above the data source.
+func hasColumnMasks(sctx sessionctx.Context, tblInfo *model.TableInfo) bool {
+	return len(tblInfo.MaskingPolicies) > 0 || sctx.GetExtensions().HasResultMasks()
+}
+
+// collectWriteBackTables collects the tables referred by the FROM clause of UPDATE and DELETE, the tables in
+// the subqueries and the views are not written back.
+func collectWriteBackTables(node ast.ResultSetNode, tables map[*ast.TableName]struct{}) {
+	switch x := node.(type) {
+	case *ast.Join:
+		collectWriteBackTables(x.Left, tables)
+		if x.Right != nil {
+			collectWriteBackTables(x.Right, tables)
+		}
+	case *ast.TableSource:
+		if tn, ok := x.Source.(*ast.TableName); ok {
+			tables[tn] = struct{}{}
+		}
+	}
+}
+
+// maskWriteBackColumn masks `expr` referring to the column `col` if it's a masked column of the tables written back
+// by UPDATE and DELETE, so the assignments, the filters and the joins of the statement see the masked values.
+func (b *PlanBuilder) maskWriteBackColumn(col *expression.Column, expr expression.Expression) (expression.Expression, error) {
+	mask, ok := b.writeBackMaskedCols[col.UniqueID]
+	if !ok || b.rewriteUnmasked {
+		return expr, nil
+	}
+	return b.buildColumnMaskExpr(expr, mask)
+}
+
+// appendLockColsToMaskingProj passes the handle columns and the physical table ID columns through the
+// masking projections, they are needed to lock the rows in SELECT FOR UPDATE even if they are masked.
+// The wildcards are already unfolded when the lock is built, and the names are not explicitly usable,
+// so they can't be referred by the queries.
+func appendLockColsToMaskingProj(p LogicalPlan, tblID2Handle map[int64][]HandleCols) {
+	for _, child := range p.Children() {
+		appendLockColsToMaskingProj(child, tblID2Handle)
+	}
+	proj, ok := p.(*LogicalProjection)
+	if !ok || !proj.Proj4Mask {
+		return
+	}
+	isLockCol := func(col *expression.Column) bool {
+		if col.ID == model.ExtraPhysTblID {
+			return true
+		}
+		for _, handles := range tblID2Handle {
+			for _, handle := range handles {
+				for i := 0; i < handle.NumCols(); i++ {
+					if handle.GetCol(i).Equal(nil, col) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	child := proj.children[0]
+	for i, col := range child.Schema().Columns {
+		if proj.schema.Contains(col) || !isLockCol(col) {
+			continue
+		}
+		name := *child.OutputNames()[i]
+		name.NotExplicitUsable = true
+		proj.Exprs = append(proj.Exprs, col)
+		proj.schema.Append(col)
+		proj.names = append(proj.names, &name)
+	}
+}
+
+// buildColumnMaskExpr builds the expression masking `expr` reading a column by the mask functions of the extensions
+// and then by the masking policy.
+func (b *PlanBuilder) buildColumnMaskExpr(expr expression.Expression, mask *columnMask) (expression.Expression, error) {
+	var err error
+	if c := mask.extColumn; c != nil {
+		expr, err = expression.BuildExtensionMaskFunction(b.ctx, expr, c.DBName, c.TableName, c.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if mask.policy == nil {
+		return expr, nil
+	}
+	return b.buildMaskingExpr(expr, mask.policy)
+}
+
+// buildMaskingExpr builds the expression masking the column, a NULL value is kept as NULL.
+func (b *PlanBuilder) buildMaskingExpr(col expression.Expression, policy *model.MaskingPolicyInfo) (expression.Expression, error) {
+	tp := col.GetType()
+	// The type of the column may be changed after the policy is created.
+	if !policy.SupportColumnType(tp.GetType()) {
+		return expression.NewNullWithFieldType(tp.Clone()), nil
+	}
+	switch policy.Type {
+	case model.MaskingFull:
+		var constant *expression.Constant
+		if types.IsString(tp.GetType()) {
+			ft := types.NewFieldType(mysql.TypeVarString)
+			ft.SetCharset(tp.GetCharset())
+			ft.SetCollate(tp.GetCollate())
+			ft.SetFlen(len(fullMaskString))
+			constant = &expression.Constant{Value: types.NewStringDatum(fullMaskString), RetType: ft}
+		} else if types.IsTypeNumeric(tp.GetType()) || types.IsTypeTime(tp.GetType()) ||
+			tp.GetType() == mysql.TypeDuration || tp.GetType() == mysql.TypeYear {
+			zero := types.NewIntDatum(0)
+			zero, err := zero.ConvertTo(b.ctx.GetSessionVars().StmtCtx, tp)
+			if err != nil {
+				return expression.NewNullWithFieldType(tp.Clone()), nil
+			}
+			constant = &expression.Constant{Value: zero, RetType: tp.Clone()}
+		} else {
+			return expression.NewNullWithFieldType(tp.Clone()), nil
+		}
+		isNull, err := expression.NewFunction(b.ctx, ast.IsNull, types.NewFieldType(mysql.TypeLonglong), col)
+		if err != nil {
+			return nil, err
+		}
+		return expression.NewFunction(b.ctx, ast.If, constant.RetType.Clone(), isNull, expression.NewNullWithFieldType(constant.RetType.Clone()), constant)
+	case model.MaskingPartial:
+		return b.buildPartialMaskingExpr(col, policy.Args)
+	case model.MaskingHash:
+		return expression.NewFunction(b.ctx, ast.SHA2, types.NewFieldType(mysql.TypeVarString), col, expression.NewInt64Const(256))
+	case model.MaskingDate:
+		format := "%Y-%m-%d"
+		switch policy.Args[0] {
+		case "YEAR":
+			format = "%Y-01-01"
+		case "MONTH":
+			format = "%Y-%m-01"
+		}
+		truncated, err := expression.NewFunction(b.ctx, ast.DateFormat, types.NewFieldType(mysql.TypeVarString),
+			col, expression.DatumToConstant(types.NewStringDatum(format), mysql.TypeVarString, 0))
+		if err != nil {
+			return nil, err
+		}
+		return expression.BuildCastFunction(b.ctx, truncated, tp.Clone()), nil
+	default:
+		return expression.NewNullWithFieldType(tp.Clone()), nil
+	}
+}
+
+// buildPartialMaskingExpr builds
+//
+//	IF(CHAR_LENGTH(col) > prefix + suffix,
+//	   CONCAT(LEFT(col, prefix), REPEAT(pad, CHAR_LENGTH(col) - prefix - suffix), RIGHT(col, suffix)),
+//	   REPEAT(pad, CHAR_LENGTH(col)))
+//
+// so the values too short to keep the prefix and the suffix are padded entirely.
+func (b *PlanBuilder) buildPartialMaskingExpr(col expression.Expression, args []string) (expression.Expression, error) {
+	prefix, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	suffix, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pad := expression.DatumToConstant(types.NewStringDatum(args[2]), mysql.TypeVarString, 0)
+	strType := types.NewFieldType(mysql.TypeVarString)
+	intType := types.NewFieldType(mysql.TypeLonglong)
+	newFunc := func(name string, retType *types.FieldType, args ...expression.Expression) expression.Expression {
+		if err != nil {
+			return nil
+		}
+		var f expression.Expression
+		f, err = expression.NewFunction(b.ctx, name, retType, args...)
+		return f
+	}
+
+	length := newFunc(ast.CharLength, intType, col)
+	keep := expression.NewInt64Const(prefix + suffix)
+	masked := newFunc(ast.Concat, strType,
+		newFunc(ast.Left, strType, col, expression.NewInt64Const(prefix)),
+		newFunc(ast.Repeat, strType, pad, newFunc(ast.Minus, intType, length, keep)),
+		newFunc(ast.Right, strType, col, expression.NewInt64Const(suffix)),
+	)
+	expr := newFunc(ast.If, strType, newFunc(ast.GT, intType, length, keep), masked, newFunc(ast.Repeat, strType, pad, length))
+	return expr, err
+}
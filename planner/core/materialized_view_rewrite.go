@@ -0,0 +1,383 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/dbterror"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// TryRewriteByMaterializedView rewrites the query to read the materialized view whose definition is the same as it.
+// It returns the rewritten statement which has been preprocessed, or nil if no fresh materialized view matches the
+// query, see isMaterializedViewFresh. The non-deterministic queries are not rewritten, since their results may be
+// different from the results stored in the materialized views.
+func TryRewriteByMaterializedView(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (ast.StmtNode, error) {
+	var sel *ast.SelectStmt
+	var explain *ast.ExplainStmt
+	switch x := node.(type) {
+	case *ast.SelectStmt:
+		sel = x
+	case *ast.ExplainStmt:
+		if s, ok := x.Stmt.(*ast.SelectStmt); ok && !x.Analyze {
+			sel, explain = s, x
+		}
+	}
+	if sel == nil || sel.SelectIntoOpt != nil || (sel.LockInfo != nil && sel.LockInfo.LockType != ast.SelectLockNone) {
+		return nil, nil
+	}
+	checker := &nonDeterministicChecker{}
+	if sel.Accept(checker); checker.found {
+		return nil, nil
+	}
+
+	collector := &tableNameCollector{}
+	sel.Accept(collector)
+	if len(collector.tables) == 0 {
+		return nil, nil
+	}
+	pm := privilege.GetPrivilegeManager(sctx)
+	activeRoles := sctx.GetSessionVars().ActiveRoles
+	schemas := make(map[string]model.CIStr, len(collector.tables))
+	for _, tn := range collector.tables {
+		// The rewritten query doesn't read the tables of the original query, so the privileges are checked here.
+		if tn.TableInfo == nil || tn.TableInfo.TempTableType != model.TempTableNone ||
+			(pm != nil && !pm.RequestVerification(activeRoles, tn.Schema.L, tn.Name.L, "", mysql.SelectPriv)) {
+			return nil, nil
+		}
+		schemas[tn.Schema.L] = tn.Schema
+	}
+	// The rows of the materialized views are neither masked nor filtered by the policies of the base tables,
+	// and the extensions mask the columns of the materialized views by their own names.
+	masked, rowAccess := findPolicyTables(is, sel, make(map[int64]struct{}))
+	if (masked != nil && !canReadUnmasked(sctx)) || (rowAccess != nil && !canBypassRowAccessPolicies(sctx)) ||
+		sctx.GetExtensions().HasResultMasks() {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	if err := sel.Restore(format.NewRestoreCtx(format.RestoreStringSingleQuotes|format.RestoreKeyWordUppercase|format.RestoreNameBackQuotes, &sb)); err != nil {
+		return nil, nil
+	}
+	query := sb.String()
+	for _, schema := range schemas {
+		for _, tbl := range is.SchemaTables(schema) {
+			mvInfo := tbl.Meta().MaterializedView
+			if mvInfo == nil || mvInfo.SelectStmt != query || len(tbl.Meta().Columns) != len(sel.Fields.Fields) {
+				continue
+			}
+			if pm != nil && !pm.RequestVerification(activeRoles, schema.L, tbl.Meta().Name.L, "", mysql.SelectPriv) {
+				continue
+			}
+			if !isMaterializedViewFresh(ctx, sctx, is, tbl.Meta()) {
+				continue
+			}
+			rewritten, ok, err := buildMaterializedViewQuery(ctx, sctx, sel, schema, tbl.Meta())
+			if err != nil {
+				logutil.Logger(ctx).Warn("failed to rewrite the query by materialized view", zap.String("view", tbl.Meta().Name.O), zap.Error(err))
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if explain != nil {
+				explain.Stmt = rewritten
+				return explain, nil
+			}
+			return rewritten, nil
+		}
+	}
+	return nil, nil
+}
+
+// materializedViewRefreshTimes caches the last refresh time of the materialized views read from
+// mysql.tidb_materialized_view_refresh, it maps the view id to the time. The refresh time of a view only
+// increases, so an outdated time in the cache only makes the view look staler, and the table is read again
+// in that case.
+var materializedViewRefreshTimes sync.Map
+
+// isMaterializedViewFresh checks whether the materialized view is fresh enough to answer the queries. It's fresh if
+// it has been refreshed within MaterializedViewMaxStaleness, or it can be refreshed incrementally and no change of
+// the base table has been logged since the last refresh.
+func isMaterializedViewFresh(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, tblInfo *model.TableInfo) bool {
+	maxStaleness := sctx.GetSessionVars().MaterializedViewMaxStaleness
+	if refreshTime, ok := materializedViewRefreshTimes.Load(tblInfo.ID); ok && time.Since(refreshTime.(time.Time)) <= maxStaleness {
+		return true
+	}
+	exec, ok := sctx.(sqlexec.RestrictedSQLExecutor)
+	if !ok {
+		return false
+	}
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnOthers)
+	rows, _, err := exec.ExecRestrictedSQL(ctx, nil, "SELECT TIMESTAMPDIFF(MICROSECOND, last_refresh_time, NOW(6)) FROM mysql.tidb_materialized_view_refresh WHERE view_id = %?", tblInfo.ID)
+	if err != nil {
+		logutil.Logger(ctx).Warn("failed to check the last refresh of materialized view", zap.String("view", tblInfo.Name.O), zap.Error(err))
+		return false
+	}
+	if len(rows) == 0 {
+		return false
+	}
+	staleness := time.Duration(rows[0].GetInt64(0)) * time.Microsecond
+	materializedViewRefreshTimes.Store(tblInfo.ID, time.Now().Add(-staleness))
+	if staleness <= maxStaleness {
+		return true
+	}
+
+	inc := tblInfo.MaterializedView.Incremental
+	if inc == nil {
+		return false
+	}
+	logTbl, ok := is.TableByID(inc.LogTableID)
+	if !ok {
+		return false
+	}
+	logSchema, ok := is.SchemaByID(inc.BaseSchemaID)
+	if !ok {
+		return false
+	}
+	rows, _, err = exec.ExecRestrictedSQL(ctx, nil, "SELECT 1 FROM %n.%n LIMIT 1", logSchema.Name.O, logTbl.Meta().Name.O)
+	if err != nil {
+		logutil.Logger(ctx).Warn("failed to check the log of materialized view", zap.String("view", tblInfo.Name.O), zap.Error(err))
+		return false
+	}
+	return len(rows) == 0
+}
+
+// buildMaterializedViewQuery builds the query reading the materialized view, the output names are kept the same
+// with the original query. The materialized view only stores the rows limited by the LIMIT clause of the query
+// but not their order, so the ORDER BY clause is added back, and the query isn't rewritten if the items of the
+// ORDER BY clause are not in the select fields.
+func buildMaterializedViewQuery(ctx context.Context, sctx sessionctx.Context, sel *ast.SelectStmt, schema model.CIStr, tblInfo *model.TableInfo) (ast.StmtNode, bool, error) {
+	sql := new(strings.Builder)
+	sql.WriteString("SELECT ")
+	for i, field := range sel.Fields.Fields {
+		var name string
+		switch {
+		case field.WildCard != nil:
+			return nil, false, nil
+		case field.AsName.L != "":
+			name = field.AsName.O
+		default:
+			switch x := getInnerFromParenthesesAndUnaryPlus(field.Expr).(type) {
+			case *ast.ColumnNameExpr:
+				name = x.Name.Name.O
+			case *driver.ValueExpr:
+				// The names of the literals are processed specially, see buildProjectionFieldNameFromExpressions.
+				return nil, false, nil
+			default:
+				name = parser.SpecFieldPattern.ReplaceAllStringFunc(field.Text(), parser.TrimComment)
+			}
+		}
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(sql, "%n AS %n", tblInfo.Columns[i].Name.O, name)
+	}
+	sqlexec.MustFormatSQL(sql, " FROM %n.%n", schema.O, tblInfo.Name.O)
+	if sel.OrderBy != nil {
+		sql.WriteString(" ORDER BY ")
+		for i, item := range sel.OrderBy.Items {
+			offset := findOrderByItemInFields(sel.Fields.Fields, item.Expr)
+			if offset < 0 {
+				return nil, false, nil
+			}
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sqlexec.MustFormatSQL(sql, "%n", tblInfo.Columns[offset].Name.O)
+			if item.Desc {
+				sql.WriteString(" DESC")
+			}
+		}
+	}
+
+	charset, collation := sctx.GetSessionVars().GetCharsetInfo()
+	stmt, err := parser.New().ParseOneStmt(sql.String(), charset, collation)
+	if err != nil {
+		return nil, false, err
+	}
+	if err = Preprocess(ctx, sctx, stmt); err != nil {
+		return nil, false, err
+	}
+	return stmt, true, nil
+}
+
+// findOrderByItemInFields returns the offset of the select field which the ORDER BY item refers to, or -1 if it's
+// not found.
+func findOrderByItemInFields(fields []*ast.SelectField, expr ast.ExprNode) int {
+	if pos, ok := expr.(*ast.PositionExpr); ok {
+		if pos.P != nil || pos.N < 1 || pos.N > len(fields) {
+			return -1
+		}
+		return pos.N - 1
+	}
+	if col, ok := expr.(*ast.ColumnNameExpr); ok && col.Name.Table.L == "" {
+		for i, field := range fields {
+			if field.AsName.L == col.Name.Name.L {
+				return i
+			}
+		}
+	}
+	exprText, ok := restoreExprForMaterializedView(expr)
+	if !ok {
+		return -1
+	}
+	for i, field := range fields {
+		if fieldText, ok := restoreExprForMaterializedView(field.Expr); ok && fieldText == exprText {
+			return i
+		}
+	}
+	return -1
+}
+
+func restoreExprForMaterializedView(expr ast.ExprNode) (string, bool) {
+	var sb strings.Builder
+	if err := expr.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", false
+	}
+	return sb.String(), true
+}
+
+// sessionDependentFunctions are the functions whose results depend on the session.
+var sessionDependentFunctions = map[string]struct{}{
+	ast.Database:             {},
+	ast.Schema:               {},
+	ast.CurrentUser:          {},
+	ast.CurrentRole:          {},
+	ast.CurrentResourceGroup: {},
+	ast.CurrentTenant:        {},
+	ast.User:                 {},
+	ast.SessionUser:          {},
+	ast.SystemUser:           {},
+	ast.ConnectionID:         {},
+	ast.LastInsertId:         {},
+	ast.RowCount:             {},
+	ast.FoundRows:            {},
+}
+
+// nonDeterministicChecker checks whether the query is non-deterministic, i.e. it reads the variables or calls the
+// functions which are mutable, have side effects or depend on the session.
+type nonDeterministicChecker struct {
+	found bool
+}
+
+// Enter implements ast.Visitor interface.
+func (c *nonDeterministicChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.VariableExpr:
+		c.found = true
+	case *ast.FuncCallExpr:
+		if _, ok := sessionDependentFunctions[x.FnName.L]; ok || expression.IsMutableEffectsFunction(x.FnName.L) {
+			c.found = true
+		}
+	}
+	return in, c.found
+}
+
+// Leave implements ast.Visitor interface.
+func (c *nonDeterministicChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, !c.found
+}
+
+// CheckMaterializedViewPolicies checks the query of a materialized view doesn't read the tables with masking
+// policies or row access policies. The views are refreshed by the system sessions, so their rows would be
+// neither masked nor filtered by the policies.
+func CheckMaterializedViewPolicies(is infoschema.InfoSchema, query ast.Node) error {
+	masked, rowAccess := findPolicyTables(is, query, make(map[int64]struct{}))
+	if masked != nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("materialized view on table " + masked.Name.O + " with masking policies")
+	}
+	if rowAccess != nil {
+		return dbterror.ErrGeneralUnsupportedDDL.GenWithStackByArgs("materialized view on table " + rowAccess.Name.O + " with row access policies")
+	}
+	return nil
+}
+
+// findPolicyTables finds a table with masking policies and a table with row access policies read by `node`,
+// the tables read by the views are included. The table names in `node` should be qualified by the schema
+// names, the others, e.g. the names of the CTEs, are ignored.
+func findPolicyTables(is infoschema.InfoSchema, node ast.Node, visited map[int64]struct{}) (masked, rowAccess *model.TableInfo) {
+	collector := &tableNameCollector{}
+	node.Accept(collector)
+	for _, tn := range collector.tables {
+		if tn.Schema.L == "" {
+			continue
+		}
+		tbl, err := is.TableByName(tn.Schema, tn.Name)
+		if err != nil {
+			continue
+		}
+		tblInfo := tbl.Meta()
+		if _, ok := visited[tblInfo.ID]; ok {
+			continue
+		}
+		visited[tblInfo.ID] = struct{}{}
+		if masked == nil && len(tblInfo.MaskingPolicies) > 0 {
+			masked = tblInfo
+		}
+		if rowAccess == nil && len(tblInfo.RowAccessPolicies) > 0 {
+			rowAccess = tblInfo
+		}
+		if !tblInfo.IsView() {
+			continue
+		}
+		stmt, err := parser.New().ParseOneStmt(tblInfo.View.SelectStmt, tblInfo.Charset, tblInfo.Collate)
+		if err != nil {
+			continue
+		}
+		viewMasked, viewRowAccess := findPolicyTables(is, stmt, visited)
+		if masked == nil {
+			masked = viewMasked
+		}
+		if rowAccess == nil {
+			rowAccess = viewRowAccess
+		}
+	}
+	return masked, rowAccess
+}
+
+type tableNameCollector struct {
+	tables []*ast.TableName
+}
+
+// Enter implements ast.Visitor interface.
+func (c *tableNameCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok {
+		c.tables = append(c.tables, tn)
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (*tableNameCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -388,7 +388,7 @@ func mergeContinuousSelections(p PhysicalPlan) {
 		for {
 			childSel := sel.children[0]
 			tmp, ok := childSel.(*PhysicalSelection)
-			if !ok {
+			if !ok || tmp.rowAccessFilter {
 				break
 			}
 			sel.Conditions = append(sel.Conditions, tmp.Conditions...)
@@ -1109,6 +1109,40 @@ func LogicalOptimizeTest(ctx context.Context, flag uint64, logic LogicalPlan) (L
 	return logicalOptimize(ctx, flag, logic)
 }
 
+// PreprocessPartitionedTables expands the partitioned tables of the logical plan into their partitions if it's required
+// by the plan builder, i.e. the partitioned tables are read in the static prune mode. It's used by the cascades planner,
+// which doesn't run the logical optimization rules, and the predicates are pushed down to prune the partitions.
+func PreprocessPartitionedTables(ctx context.Context, flag uint64, logic LogicalPlan) (LogicalPlan, error) {
+	if flag&flagPartitionProcessor == 0 {
+		return logic, nil
+	}
+	logic, err := logicalOptimize(ctx, flagPredicatePushDown|flagPartitionProcessor, logic)
+	if err != nil {
+		return nil, err
+	}
+	return restorePushedDownConds(logic), nil
+}
+
+// restorePushedDownConds moves the conditions pushed down to the DataSources back
+// into Selections, since the cascades planner pushes them down by its own rules.
+func restorePushedDownConds(p LogicalPlan) LogicalPlan {
+	for i, child := range p.Children() {
+		p.Children()[i] = restorePushedDownConds(child)
+	}
+	ds, ok := p.(*DataSource)
+	if !ok || len(ds.pushedDownConds) == 0 {
+		return p
+	}
+	// The conditions include the row access policies of the table, the predicates kept above them aren't merged.
+	sel := LogicalSelection{
+		Conditions:      ds.pushedDownConds,
+		RowAccessFilter: len(ds.tableInfo.RowAccessPolicies) > 0,
+	}.Init(ds.SCtx(), ds.SelectBlockOffset())
+	sel.SetChildren(ds)
+	ds.pushedDownConds = nil
+	return sel
+}
+
 func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (LogicalPlan, error) {
 	if logic.SCtx().GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
 		debugtrace.EnterContextCommon(logic.SCtx())
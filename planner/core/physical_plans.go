@@ -249,6 +249,12 @@ func (sg *TiKVSingleGather) GetPhysicalTableReader(schema *expression.Schema, st
 // GetPhysicalIndexReader returns PhysicalIndexReader for logical TiKVSingleGather.
 func (sg *TiKVSingleGather) GetPhysicalIndexReader(schema *expression.Schema, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalIndexReader {
 	reader := PhysicalIndexReader{}.Init(sg.ctx, sg.blockOffset)
+	reader.PartitionInfo = PartitionInfo{
+		PruningConds:   sg.Source.allConds,
+		PartitionNames: sg.Source.partitionNames,
+		Columns:        sg.Source.TblCols,
+		ColumnNames:    sg.Source.names,
+	}
 	reader.stats = stats
 	reader.SetSchema(schema)
 	reader.childrenReqProps = props
@@ -821,6 +827,33 @@ func (p *PhysicalMemTable) MemoryUsage() (sum int64) {
 	return
 }
 
+// PhysicalTableChanges reads the changes of a table committed in the time range (StartTS, EndTS].
+type PhysicalTableChanges struct {
+	physicalSchemaProducer
+
+	DBName           model.CIStr
+	Table            *model.TableInfo
+	PhysicalTableIDs []int64
+	PartitionNames   []model.CIStr
+	StartTS          uint64
+	EndTS            uint64
+	Columns          []*model.ColumnInfo
+}
+
+// MemoryUsage return the memory usage of PhysicalTableChanges
+func (p *PhysicalTableChanges) MemoryUsage() (sum int64) {
+	if p == nil {
+		return
+	}
+
+	sum = p.physicalSchemaProducer.MemoryUsage() + p.DBName.MemoryUsage() + size.SizeOfPointer + size.SizeOfSlice*3 +
+		int64(cap(p.PhysicalTableIDs))*size.SizeOfInt64 + size.SizeOfUint64*2 + int64(cap(p.Columns))*size.SizeOfPointer
+	for _, name := range p.PartitionNames {
+		sum += name.MemoryUsage()
+	}
+	return
+}
+
 // PhysicalTableScan represents a table scan plan.
 type PhysicalTableScan struct {
 	physicalSchemaProducer
@@ -2147,6 +2180,10 @@ type PhysicalSelection struct {
 	// Please see https://github.com/pingcap/tidb/issues/36243 for more details.
 	fromDataSource bool
 
+	// rowAccessFilter indicates the selection filters the rows by the row access policies, the selections above it
+	// aren't merged with it, otherwise their conditions are evaluated on the filtered rows.
+	rowAccessFilter bool
+
 	// todo Since the feature of adding filter operators has not yet been implemented,
 	// the following code for this function will not be used for now.
 	// The flag indicates whether this Selection is used for RuntimeFilter
@@ -183,6 +183,10 @@ func (checker *cacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren
 			checker.reason = "query has ? in window function frames is un-cacheable"
 			return in, true
 		}
+	case *ast.TableChanges:
+		checker.cacheable = false
+		checker.reason = "query accesses TABLE_CHANGES is un-cacheable"
+		return in, true
 	case *ast.TableName:
 		if checker.schema != nil {
 			if isPartitionTable(checker.schema, node) {
@@ -517,6 +517,12 @@ type PlanBuilder struct {
 	windowSpecs  map[string]*ast.WindowSpec
 	inUpdateStmt bool
 	inDeleteStmt bool
+	// writeBackTables are the tables whose rows are written back by the current UPDATE or DELETE, their masked
+	// columns are recorded in writeBackMaskedCols by the unique IDs and masked when they are referred.
+	writeBackTables     map[*ast.TableName]struct{}
+	writeBackMaskedCols map[int64]*columnMask
+	// rewriteUnmasked indicates the expressions being rewritten read the unmasked values written back.
+	rewriteUnmasked bool
 	// inStraightJoin represents whether the current "SELECT" statement has
 	// "STRAIGHT_JOIN" option.
 	inStraightJoin bool
@@ -844,7 +850,9 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
 		*ast.RenameUserStmt, *ast.NonTransactionalDMLStmt, *ast.SetSessionStatesStmt, *ast.SetResourceGroupStmt,
-		*ast.LoadDataActionStmt, *ast.ImportIntoActionStmt, *ast.CalibrateResourceStmt:
+		*ast.LoadDataActionStmt, *ast.ImportIntoActionStmt, *ast.CalibrateResourceStmt,
+		*ast.CreateTimerStmt, *ast.AlterTimerStmt, *ast.DropTimerStmt, *ast.TimerActionStmt,
+		*ast.RefreshMaterializedViewStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -1601,6 +1609,7 @@ func (b *PlanBuilder) buildSelectLock(src LogicalPlan, lock *ast.SelectLockInfo)
 		tblID2Handle:       b.handleHelper.tailMap(),
 		tblID2PhysTblIDCol: tblID2PhysTblIDCol,
 	}.Init(b.ctx)
+	appendLockColsToMaskingProj(src, selectLock.tblID2Handle)
 	selectLock.SetChildren(src)
 	return selectLock, nil
 }
@@ -1728,12 +1737,7 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 	case ast.AdminCaptureBindings:
 		return &SQLBindPlan{SQLBindOp: OpCaptureBindings}, nil
 	case ast.AdminEvolveBindings:
-		var err error
-		// The 'baseline evolution' only work in the test environment before the feature is GA.
-		if !config.CheckTableBeforeDrop {
-			err = errors.Errorf("Cannot enable baseline evolution feature, it is not generally available now")
-		}
-		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, err
+		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, nil
 	case ast.AdminReloadBindings:
 		return &SQLBindPlan{SQLBindOp: OpReloadBindings}, nil
 	case ast.AdminShowTelemetry:
@@ -3420,6 +3424,12 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 	case ast.ShowRestores:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESTORE_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "RESTORE_ADMIN", false, err)
+	case ast.ShowTimers:
+		if p.DBName == "" {
+			return nil, ErrNoDB
+		}
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or TIMER_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "TIMER_ADMIN", false, err)
 	case ast.ShowTableNextRowId:
 		p := &ShowNextRowID{TableName: show.Table}
 		p.setSchemaAndNames(buildShowNextRowID())
@@ -3544,6 +3554,16 @@ func (b *PlanBuilder) buildSimple(ctx context.Context, node ast.StmtNode) (Plan,
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESOURCE_GROUP_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "RESOURCE_GROUP_ADMIN", false, err)
 		p.setSchemaAndNames(buildCalibrateResourceSchema())
+	case *ast.CreateTimerStmt, *ast.AlterTimerStmt, *ast.DropTimerStmt, *ast.TimerActionStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or TIMER_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "TIMER_ADMIN", false, err)
+	case *ast.RefreshMaterializedViewStmt:
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			err := ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, raw.ViewName.Name.L)
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, raw.ViewName.Schema.L, raw.ViewName.Name.L, "", err)
+			err = ErrTableaccessDenied.GenWithStackByArgs("DELETE", user.AuthUsername, user.AuthHostname, raw.ViewName.Name.L)
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, raw.ViewName.Schema.L, raw.ViewName.Name.L, "", err)
+		}
 	case *ast.GrantRoleStmt:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or ROLE_ADMIN")
 		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROLE_ADMIN", false, err)
@@ -3951,6 +3971,10 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	if err != nil {
 		return nil, err
 	}
+	insertPlan.RowAccessChecks, err = b.buildRowAccessChecks(tableInfo, insertPlan.Table.Cols())
+	if err != nil {
+		return nil, err
+	}
 
 	err = insertPlan.ResolveIndices()
 	if err != nil {
@@ -4333,6 +4357,10 @@ func (b *PlanBuilder) buildLoadData(ctx context.Context, ld *ast.LoadDataStmt) (
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, p.Table.Schema.O, p.Table.Name.O, "", deleteErr)
 	}
 	tableInfo := p.Table.TableInfo
+	// The loaded rows are not checked against the row access policies.
+	if len(tableInfo.RowAccessPolicies) > 0 && !canBypassRowAccessPolicies(b.ctx) {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("LOAD DATA into the table with row access policies")
+	}
 	tableInPlan, ok := b.is.TableByID(tableInfo.ID)
 	if !ok {
 		db := b.ctx.GetSessionVars().CurrentDB
@@ -4414,6 +4442,10 @@ func (b *PlanBuilder) buildImportInto(ctx context.Context, ld *ast.ImportIntoStm
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
 	}
 	tableInfo := p.Table.TableInfo
+	// The loaded rows are not checked against the row access policies.
+	if len(tableInfo.RowAccessPolicies) > 0 && !canBypassRowAccessPolicies(b.ctx) {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("IMPORT INTO the table with row access policies")
+	}
 	tableInPlan, ok := b.is.TableByID(tableInfo.ID)
 	if !ok {
 		db := b.ctx.GetSessionVars().CurrentDB
@@ -4884,6 +4916,34 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
 				"", "", err)
 		}
+	case *ast.CreateMaterializedViewStmt:
+		plan, err := b.Build(ctx, v.Select)
+		if err != nil {
+			return nil, err
+		}
+		schema := plan.Schema()
+		if v.Cols == nil {
+			names := plan.OutputNames()
+			v.SchemaCols = make([]model.CIStr, len(names))
+			for i, name := range names {
+				v.SchemaCols[i] = name.ColName
+			}
+		} else {
+			v.SchemaCols = v.Cols
+		}
+		if len(v.SchemaCols) != schema.Len() {
+			return nil, dbterror.ErrViewWrongList
+		}
+		v.SchemaColTypes = make([]*types.FieldType, schema.Len())
+		for i, col := range schema.Columns {
+			v.SchemaColTypes[i] = col.RetType.Clone()
+		}
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("CREATE", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
 	case *ast.CreateSequenceStmt:
 		if b.ctx.GetSessionVars().User != nil {
 			authErr = ErrTableaccessDenied.GenWithStackByArgs("CREATE", b.ctx.GetSessionVars().User.AuthUsername,
@@ -4914,6 +4974,19 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, tableVal.Schema.L,
 				tableVal.Name.L, "", authErr)
 		}
+	case *ast.DropMaterializedViewStmt:
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("DROP", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.ViewName.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.ViewName.Schema.L,
+			v.ViewName.Name.L, "", authErr)
+	case *ast.CreateMaskingPolicyStmt, *ast.DropMaskingPolicyStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or MASKING_POLICY_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "MASKING_POLICY_ADMIN", false, err)
+	case *ast.CreateRowAccessPolicyStmt, *ast.DropRowAccessPolicyStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or ROW_ACCESS_POLICY_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROW_ACCESS_POLICY_ADMIN", false, err)
 	case *ast.DropSequenceStmt:
 		for _, sequence := range v.Sequences {
 			if b.ctx.GetSessionVars().User != nil {
@@ -5415,6 +5488,11 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 	case ast.ShowImportJobs:
 		names = importIntoSchemaNames
 		ftypes = importIntoSchemaFTypes
+	case ast.ShowTimers:
+		names = []string{"Name", "Schedule_type", "Schedule", "Time_zone", "Enabled", "Watermark",
+			"Next_event_time", "Event_status", "Last_event_status", "Last_event_end_time", "Comment"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime,
+			mysql.TypeDatetime, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeVarchar}
 	}
 	return convert2OutputSchemasAndNames(names, ftypes)
 }
@@ -5499,6 +5577,27 @@ func calcTSForPlanReplayer(sctx sessionctx.Context, tsExpr ast.ExprNode) uint64
 	return oracle.GoTimeToTS(goTime)
 }
 
+// calcTSForTableChanges evaluates the start ts or the end ts of TABLE_CHANGES. An integer is treated as a TSO and
+// other values are treated as a date/time.
+func calcTSForTableChanges(ctx context.Context, sctx sessionctx.Context, tsExpr ast.ExprNode) (uint64, error) {
+	tsVal, err := expression.EvalAstExpr(sctx, tsExpr)
+	if err != nil {
+		return 0, err
+	}
+	switch tsVal.Kind() {
+	case types.KindNull:
+		return 0, ErrWrongArguments.GenWithStackByArgs("TABLE_CHANGES, the ts cannot be NULL")
+	case types.KindInt64:
+		if tsVal.GetInt64() <= 0 {
+			return 0, ErrWrongArguments.GenWithStackByArgs("TABLE_CHANGES, the ts must be positive")
+		}
+		return uint64(tsVal.GetInt64()), nil
+	case types.KindUint64:
+		return tsVal.GetUint64(), nil
+	}
+	return staleread.CalculateAsOfTsExpr(ctx, sctx, tsExpr)
+}
+
 func buildChecksumTableSchema() (*expression.Schema, []*types.FieldName) {
 	schema := newColumnsWithNames(5)
 	schema.Append(buildColumnWithName("", "Db_name", mysql.TypeVarchar, 128))
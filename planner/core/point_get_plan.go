@@ -765,9 +765,16 @@ func newBatchPointGetPlan(
 		return nil
 	}
 
-	pos, err := getPartitionColumnPos(matchIdxInfo, partitionExpr, tbl)
-	if err != nil {
-		return nil
+	var pos int
+	if matchIdxInfo.Global {
+		// The partitions of the rows are found by the values of the global index.
+		partitionExpr = nil
+	} else {
+		var err error
+		pos, err = getPartitionColumnPos(matchIdxInfo, partitionExpr, tbl)
+		if err != nil {
+			return nil
+		}
 	}
 
 	indexValues := make([][]types.Datum, len(patternInExpr.List))
@@ -873,7 +880,7 @@ func newBatchPointGetPlan(
 		}
 		indexValues[i] = values
 		indexValueParams[i] = valuesParams
-		if tbl.GetPartitionInfo() != nil {
+		if tbl.GetPartitionInfo() != nil && !matchIdxInfo.Global {
 			tmpPartitionDefinition, _, pos, isTableDual := getPartitionInfo(ctx, tbl, pairs)
 			if isTableDual {
 				return nil
@@ -932,6 +939,14 @@ func tryWhereIn2BatchPointGet(ctx sessionctx.Context, selStmt *ast.SelectStmt) *
 	if tbl == nil {
 		return nil
 	}
+	// The rows are filtered by the row access policies above the data source.
+	if len(tbl.RowAccessPolicies) > 0 {
+		return nil
+	}
+	// The columns are masked by the projection above the data source.
+	if hasColumnMasks(ctx, tbl) {
+		return nil
+	}
 	// Skip the optimization with partition selection.
 	if len(tblName.PartitionNames) > 0 {
 		return nil
@@ -1028,6 +1043,14 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 	if tbl == nil {
 		return nil
 	}
+	// The rows are filtered by the row access policies above the data source.
+	if len(tbl.RowAccessPolicies) > 0 {
+		return nil
+	}
+	// The columns are masked by the projection above the data source.
+	if hasColumnMasks(ctx, tbl) {
+		return nil
+	}
 	pi := tbl.GetPartitionInfo()
 
 	for _, col := range tbl.Columns {
@@ -1065,10 +1088,12 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 			return p
 		}
 		if partitionInfo == nil {
-			return nil
-		}
-		// Take partition selection into consideration.
-		if len(tblName.PartitionNames) > 0 {
+			// The partition of the row can still be found by a global index.
+			if len(tblName.PartitionNames) > 0 || !hasPublicGlobalIndex(tbl) {
+				return nil
+			}
+		} else if len(tblName.PartitionNames) > 0 {
+			// Take partition selection into consideration.
 			if !partitionNameInSet(partitionInfo.Name, tblName.PartitionNames) {
 				p := newPointGetPlan(ctx, tblName.Schema.O, schema, tbl, names)
 				p.IsTableDual = true
@@ -1076,9 +1101,11 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 			}
 		}
 	}
+	// Only the global indexes can be used if the partition is unknown.
+	onlyGlobalIndex := pi != nil && partitionInfo == nil
 
 	handlePair, fieldType := findPKHandle(tbl, pairs)
-	if handlePair.value.Kind() != types.KindNull && len(pairs) == 1 && indexIsAvailableByHints(nil, tblName.IndexHints) {
+	if handlePair.value.Kind() != types.KindNull && len(pairs) == 1 && indexIsAvailableByHints(nil, tblName.IndexHints) && !onlyGlobalIndex {
 		if isTableDual {
 			p := newPointGetPlan(ctx, tblName.Schema.O, schema, tbl, names)
 			p.IsTableDual = true
@@ -1103,7 +1130,7 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 
 	for _, idxInfo := range tbl.Indices {
 		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.Invisible || idxInfo.MVIndex ||
-			!indexIsAvailableByHints(idxInfo, tblName.IndexHints) {
+			!indexIsAvailableByHints(idxInfo, tblName.IndexHints) || (onlyGlobalIndex && !idxInfo.Global) {
 			continue
 		}
 		if isTableDual {
@@ -1153,6 +1180,15 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 	return nil
 }
 
+func hasPublicGlobalIndex(tbl *model.TableInfo) bool {
+	for _, idx := range tbl.Indices {
+		if idx.Global && idx.State == model.StatePublic {
+			return true
+		}
+	}
+	return false
+}
+
 // indexIsAvailableByHints checks whether this index is filtered by these specified index hints.
 // idxInfo is PK if it's nil
 func indexIsAvailableByHints(idxInfo *model.IndexInfo, idxHints []*ast.IndexHint) bool {
@@ -1893,6 +1929,9 @@ func getPartitionColumnPos(idx *model.IndexInfo, partitionExpr *tables.Partition
 
 	var partitionColName model.CIStr
 	switch pi.Type {
+	case model.PartitionTypeNone:
+		// The non-partitioned table is being partitioned by ALTER TABLE ... PARTITION BY
+		return 0, errors.Errorf("unsupported partition type in BatchGet")
 	case model.PartitionTypeHash:
 		col, ok := partitionExpr.OrigExpr.(*ast.ColumnNameExpr)
 		if !ok {
@@ -64,6 +64,11 @@ func InTxnRetry(p *preprocessor) {
 	p.flag |= inTxnRetry
 }
 
+// InCreateMaterializedView is a PreprocessOpt that indicates preprocess is executing for the query of a materialized view.
+func InCreateMaterializedView(p *preprocessor) {
+	p.flag |= inCreateMaterializedView
+}
+
 // InitTxnContextProvider is a PreprocessOpt that indicates preprocess should init transaction's context
 func InitTxnContextProvider(p *preprocessor) {
 	p.flag |= initTxnContextProvider
@@ -139,7 +144,7 @@ func Preprocess(ctx context.Context, sctx sessionctx.Context, node ast.Node, pre
 	return errors.Trace(v.err)
 }
 
-type preprocessorFlag uint8
+type preprocessorFlag uint16
 
 const (
 	// inPrepare is set when visiting in prepare statement.
@@ -159,6 +164,8 @@ const (
 	initTxnContextProvider
 	// inImportInto is set when visiting an import into statement.
 	inImportInto
+	// inCreateMaterializedView is set when visiting the query of a create materialized view statement.
+	inCreateMaterializedView
 )
 
 // Make linter happy.
@@ -263,10 +270,18 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.flag |= inCreateOrDropTable
 		p.checkCreateViewGrammar(node)
 		p.checkCreateViewWithSelectGrammar(node)
+	case *ast.CreateMaterializedViewStmt:
+		p.stmtTp = TypeCreate
+		p.flag |= inCreateOrDropTable
+		p.checkCreateMaterializedViewGrammar(node)
 	case *ast.DropTableStmt:
 		p.flag |= inCreateOrDropTable
 		p.stmtTp = TypeDrop
 		p.checkDropTableGrammar(node)
+	case *ast.DropMaterializedViewStmt:
+		p.flag |= inCreateOrDropTable
+		p.stmtTp = TypeDrop
+		p.checkDropTableNames([]*ast.TableName{node.ViewName})
 	case *ast.RenameTableStmt:
 		p.stmtTp = TypeRename
 		p.flag |= inCreateOrDropTable
@@ -291,6 +306,22 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		p.stmtTp = TypeShow
 		p.showTp = node.Tp
 		p.resolveShowStmt(node)
+	case *ast.CreateTimerStmt:
+		p.stmtTp = TypeCreate
+		p.resolveTimerName(node.Name)
+		// The body is executed by the timer runtime later, so it is not resolved here.
+		return in, true
+	case *ast.AlterTimerStmt:
+		p.stmtTp = TypeAlter
+		p.resolveTimerName(node.Name)
+		return in, true
+	case *ast.DropTimerStmt:
+		p.stmtTp = TypeDrop
+		p.resolveTimerName(node.Name)
+		return in, true
+	case *ast.TimerActionStmt:
+		p.resolveTimerName(node.Name)
+		return in, true
 	case *ast.SetOprSelectList:
 		p.checkSetOprSelectList(node)
 	case *ast.DeleteTableList:
@@ -557,9 +588,9 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		p.flag &= ^inCreateOrDropTable
 		p.checkAutoIncrement(x)
 		p.checkContainDotColumn(x)
-	case *ast.CreateViewStmt:
+	case *ast.CreateViewStmt, *ast.CreateMaterializedViewStmt:
 		p.flag &= ^inCreateOrDropTable
-	case *ast.DropTableStmt, *ast.AlterTableStmt, *ast.RenameTableStmt:
+	case *ast.DropTableStmt, *ast.AlterTableStmt, *ast.RenameTableStmt, *ast.DropMaterializedViewStmt:
 		p.flag &= ^inCreateOrDropTable
 	case *driver.ParamMarkerExpr:
 		if p.flag&inPrepare == 0 {
@@ -1000,6 +1031,31 @@ func (p *preprocessor) checkCreateViewWithSelectGrammar(stmt *ast.CreateViewStmt
 	}
 }
 
+func (p *preprocessor) checkCreateMaterializedViewGrammar(stmt *ast.CreateMaterializedViewStmt) {
+	vName := stmt.ViewName.Name.String()
+	if isIncorrectName(vName) {
+		p.err = dbterror.ErrWrongTableName.GenWithStackByArgs(vName)
+		return
+	}
+	for _, col := range stmt.Cols {
+		if isIncorrectName(col.String()) {
+			p.err = dbterror.ErrWrongColumnName.GenWithStackByArgs(col)
+			return
+		}
+	}
+	sel, ok := stmt.Select.(*ast.SelectStmt)
+	if !ok {
+		return
+	}
+	if sel.SelectIntoOpt != nil {
+		p.err = dbterror.ErrViewSelectClause.GenWithStackByArgs("INFO")
+		return
+	}
+	if sel.LockInfo != nil && sel.LockInfo.LockType != ast.SelectLockNone {
+		sel.LockInfo.LockType = ast.SelectLockNone
+	}
+}
+
 func (p *preprocessor) checkDropSequenceGrammar(stmt *ast.DropSequenceStmt) {
 	p.checkDropTableNames(stmt.Sequences)
 }
@@ -1644,6 +1700,20 @@ func (p *preprocessor) resolveShowStmt(node *ast.ShowStmt) {
 	}
 }
 
+// resolveTimerName fills the schema of a timer name with the current database if it is not specified.
+// A timer is not a table, so it should not be resolved by handleTableName.
+func (p *preprocessor) resolveTimerName(tn *ast.TableName) {
+	if tn.Schema.L != "" {
+		return
+	}
+	currentDB := p.sctx.GetSessionVars().CurrentDB
+	if currentDB == "" {
+		p.err = errors.Trace(ErrNoDB)
+		return
+	}
+	tn.Schema = model.NewCIStr(currentDB)
+}
+
 func (p *preprocessor) resolveExecuteStmt(node *ast.ExecuteStmt) {
 	prepared, err := GetPreparedStmt(node, p.sctx.GetSessionVars())
 	if err != nil {
@@ -1916,5 +1986,7 @@ func tryLockMDLAndUpdateSchemaIfNecessary(sctx sessionctx.Context, dbName model.
 func (p *preprocessor) skipLockMDL() bool {
 	// skip lock mdl for IMPORT INTO statement,
 	// because it's a batch process and will do both DML and DDL.
-	return p.flag&inImportInto > 0
+	// skip lock mdl for CREATE MATERIALIZED VIEW statement,
+	// because its DDL job changes the base table, and the job would wait for the statement itself.
+	return p.flag&(inImportInto|inCreateMaterializedView) > 0
 }
@@ -0,0 +1,162 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/generatedexpr"
+)
+
+// RowAccessCheck is a row access policy checked against the rows written by INSERT and UPDATE.
+type RowAccessCheck struct {
+	PolicyName model.CIStr
+	Expr       expression.Expression
+}
+
+// canBypassRowAccessPolicies checks whether the current user can access all the rows of the tables
+// with row access policies. Like canReadUnmasked, only the sessions without a user are exempted.
+func canBypassRowAccessPolicies(sctx sessionctx.Context) bool {
+	vars := sctx.GetSessionVars()
+	if vars.User == nil {
+		return true
+	}
+	pm := privilege.GetPrivilegeManager(sctx)
+	return pm == nil || pm.RequestDynamicVerification(vars.ActiveRoles, "ROW_ACCESS_POLICY_EXEMPT", false)
+}
+
+// buildRowAccessPolicyExprs builds the expressions of the row access policies of the table over the
+// given schema. Nothing is returned if the current user is exempted from the policies.
+func (b *PlanBuilder) buildRowAccessPolicyExprs(tblInfo *model.TableInfo, schema *expression.Schema, names types.NameSlice) ([]*RowAccessCheck, error) {
+	if len(tblInfo.RowAccessPolicies) == 0 {
+		return nil, nil
+	}
+	// The plan depends on the privileges and the tenant of the user.
+	b.ctx.GetSessionVars().StmtCtx.SetSkipPlanCache(errors.New("table has row access policies"))
+	if canBypassRowAccessPolicies(b.ctx) {
+		return nil, nil
+	}
+
+	checks := make([]*RowAccessCheck, 0, len(tblInfo.RowAccessPolicies))
+	for _, policy := range tblInfo.RowAccessPolicies {
+		node, err := generatedexpr.ParseExpression(policy.ExprString)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		expr, err := expression.RewriteSimpleExprWithNames(b.ctx, node, schema, names)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, &RowAccessCheck{PolicyName: policy.Name, Expr: expr})
+	}
+	return checks, nil
+}
+
+// buildRowAccessFilter puts a selection above the data source or TABLE_CHANGES to filter out the rows which
+// can't be accessed by the current user. It's built below the masking projection, so the policies are
+// evaluated on the unmasked values, and the rows read by UPDATE and DELETE are filtered as well.
+// The other predicates are only pushed below it when they're leakproof, see splitLeakproofExprs.
+func (b *PlanBuilder) buildRowAccessFilter(p LogicalPlan, tblInfo *model.TableInfo) (LogicalPlan, error) {
+	checks, err := b.buildRowAccessPolicyExprs(tblInfo, p.Schema(), p.OutputNames())
+	if err != nil || len(checks) == 0 {
+		return p, err
+	}
+	conds := make([]expression.Expression, 0, len(checks))
+	for _, check := range checks {
+		conds = append(conds, expression.SplitCNFItems(check.Expr)...)
+	}
+	b.optFlag |= flagPredicatePushDown
+	sel := LogicalSelection{Conditions: conds, RowAccessFilter: true}.Init(b.ctx, b.getSelectOffset())
+	sel.SetChildren(p)
+	return sel, nil
+}
+
+// leakproofFuncs are the functions which raise no warnings or errors whatever the values of their arguments are,
+// as long as the arguments are columns or constants, which means no implicit casts.
+var leakproofFuncs = map[string]struct{}{
+	ast.EQ: {}, ast.NE: {}, ast.LT: {}, ast.LE: {}, ast.GT: {}, ast.GE: {}, ast.NullEQ: {}, ast.In: {},
+	ast.IsNull: {}, ast.IsTruthWithNull: {}, ast.IsTruthWithoutNull: {}, ast.IsFalsity: {},
+	ast.LogicAnd: {}, ast.LogicOr: {}, ast.UnaryNot: {},
+}
+
+// isLeakproofExpr checks whether the expression can be evaluated on the rows filtered out by the row access
+// policies, i.e. it can't leak the values of the rows by its warnings or errors.
+func isLeakproofExpr(expr expression.Expression) bool {
+	switch x := expr.(type) {
+	case *expression.Constant, *expression.CorrelatedColumn:
+		return true
+	case *expression.Column:
+		// The virtual generated columns are evaluated by their expressions.
+		return x.VirtualExpr == nil
+	case *expression.ScalarFunction:
+		if _, ok := leakproofFuncs[x.FuncName.L]; !ok {
+			return false
+		}
+		for _, arg := range x.GetArgs() {
+			if !isLeakproofExpr(arg) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// splitLeakproofExprs splits the predicates pushed down to the row access filter into the leakproof ones, which
+// can be pushed below it and used to build the ranges, and the others, which are kept above it.
+func splitLeakproofExprs(exprs []expression.Expression) (leakproof, others []expression.Expression) {
+	for _, expr := range exprs {
+		if isLeakproofExpr(expr) {
+			leakproof = append(leakproof, expr)
+		} else {
+			others = append(others, expr)
+		}
+	}
+	return leakproof, others
+}
+
+// buildRowAccessChecks builds the row access policies checked against the rows written to the table,
+// the rows consist of the given columns in order.
+func (b *PlanBuilder) buildRowAccessChecks(tblInfo *model.TableInfo, cols []*table.Column) ([]*RowAccessCheck, error) {
+	if len(tblInfo.RowAccessPolicies) == 0 {
+		return nil, nil
+	}
+	colInfos := make([]*model.ColumnInfo, 0, len(cols))
+	for _, col := range cols {
+		colInfos = append(colInfos, col.ColumnInfo)
+	}
+	columns, names, err := expression.ColumnInfos2ColumnsAndNames(b.ctx, model.CIStr{}, tblInfo.Name, colInfos, tblInfo)
+	if err != nil {
+		return nil, err
+	}
+	schema := expression.NewSchema(columns...)
+	checks, err := b.buildRowAccessPolicyExprs(tblInfo, schema, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, check := range checks {
+		check.Expr, err = check.Expr.ResolveIndices(schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return checks, nil
+}
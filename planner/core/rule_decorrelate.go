@@ -207,9 +207,10 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan, opt *lo
 			appendApplySimplifiedTraceStep(apply, join, opt)
 		} else if apply.NoDecorrelate {
 			goto NoOptimize
-		} else if sel, ok := innerPlan.(*LogicalSelection); ok {
+		} else if sel, ok := innerPlan.(*LogicalSelection); ok && !sel.RowAccessFilter {
 			// If the inner plan is a selection, we add this condition to join predicates.
 			// Notice that no matter what kind of join is, it's always right.
+			// The row access filters are kept, otherwise they're merged with the other predicates.
 			newConds := make([]expression.Expression, 0, len(sel.Conditions))
 			for _, cond := range sel.Conditions {
 				newConds = append(newConds, cond.Decorrelate(outerPlan.Schema()))
@@ -109,7 +109,7 @@ func (a *maxMinEliminator) cloneSubPlans(plan LogicalPlan) LogicalPlan {
 	case *LogicalSelection:
 		newConditions := make([]expression.Expression, len(p.Conditions))
 		copy(newConditions, p.Conditions)
-		sel := LogicalSelection{Conditions: newConditions}.Init(p.ctx, p.blockOffset)
+		sel := LogicalSelection{Conditions: newConditions, RowAccessFilter: p.RowAccessFilter}.Init(p.ctx, p.blockOffset)
 		sel.SetChildren(a.cloneSubPlans(p.children[0]))
 		return sel
 	case *DataSource:
@@ -100,6 +100,11 @@ func splitSetGetVarFunc(filters []expression.Expression) ([]expression.Expressio
 func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression, opt *logicalOptimizeOp) ([]expression.Expression, LogicalPlan) {
 	predicates = DeleteTrueExprs(p, predicates)
 	p.Conditions = DeleteTrueExprs(p, p.Conditions)
+	var blocked []expression.Expression
+	if p.RowAccessFilter {
+		// The predicates which may leak the values of the rows are kept above, and evaluated on the accessible rows only.
+		predicates, blocked = splitLeakproofExprs(predicates)
+	}
 	var child LogicalPlan
 	var retConditions []expression.Expression
 	var originConditions []expression.Expression
@@ -108,6 +113,8 @@ func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression,
 	retConditions, child = p.children[0].PredicatePushDown(append(canBePushDown, predicates...), opt)
 	retConditions = append(retConditions, canNotBePushDown...)
 	if len(retConditions) > 0 {
+		// The child may be replaced, e.g. a row access filter whose conditions are all pushed down.
+		p.children[0] = child
 		p.Conditions = expression.PropagateConstant(p.ctx, retConditions)
 		// Return table dual when filter is constant false or null.
 		dual := Conds2TableDual(p, p.Conditions)
@@ -115,10 +122,10 @@ func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression,
 			appendTableDualTraceStep(p, dual, p.Conditions, opt)
 			return nil, dual
 		}
-		return nil, p
+		return blocked, p
 	}
 	appendSelectionPredicatePushDownTraceStep(p, originConditions, opt)
-	return nil, child
+	return blocked, child
 }
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
@@ -176,6 +176,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		}
 	case *LogicalShowDDLJobs, *PhysicalShowDDLJobs:
 		str = "ShowDDLJobs"
+	case *LogicalTableChanges, *PhysicalTableChanges:
+		str = "TableChanges"
 	case *LogicalSort, *PhysicalSort:
 		str = "Sort"
 	case *LogicalJoin:
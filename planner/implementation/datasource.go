@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/planner/memo"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
 )
 
@@ -90,6 +91,16 @@ func (impl *TableReaderImpl) CalcCost(outCount float64, children ...memo.Impleme
 	return impl.cost
 }
 
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *TableReaderImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	reader := impl.plan.(*plannercore.PhysicalTableReader)
+	reader.SetChildren(children[0].GetPlan())
+	if impl.tblInfo.GetPartitionInfo() != nil {
+		reader.PartitionInfo.PruningConds = appendPruningConds(reader.SCtx(), reader.PartitionInfo.PruningConds, children[0].GetPlan())
+	}
+	return impl
+}
+
 // GetCostLimit implements Implementation interface.
 func (impl *TableReaderImpl) GetCostLimit(costLimit float64, _ ...memo.Implementation) float64 {
 	reader := impl.plan.(*plannercore.PhysicalTableReader)
@@ -162,6 +173,45 @@ func (impl *IndexReaderImpl) CalcCost(outCount float64, children ...memo.Impleme
 	return impl.cost
 }
 
+// AttachChildren implements Implementation AttachChildren interface.
+func (impl *IndexReaderImpl) AttachChildren(children ...memo.Implementation) memo.Implementation {
+	reader := impl.plan.(*plannercore.PhysicalIndexReader)
+	reader.SetChildren(children[0].GetPlan())
+	if impl.tblInfo.GetPartitionInfo() != nil {
+		reader.PartitionInfo.PruningConds = appendPruningConds(reader.SCtx(), reader.PartitionInfo.PruningConds, children[0].GetPlan())
+	}
+	return impl
+}
+
+// appendPruningConds appends the conditions pushed down to the reader, which are
+// used to prune the partitions in the dynamic prune mode. Only the conditions
+// applied to the scan before any other operator, e.g. Limit, can be used.
+func appendPruningConds(sctx sessionctx.Context, conds []expression.Expression, pushedDown plannercore.PhysicalPlan) []expression.Expression {
+	var plans []plannercore.PhysicalPlan
+	for p := pushedDown; p != nil; {
+		plans = append(plans, p)
+		if len(p.Children()) == 0 {
+			break
+		}
+		p = p.Children()[0]
+	}
+	pruningConds := make([]expression.Expression, 0, len(conds))
+	pruningConds = append(pruningConds, conds...)
+	for i := len(plans) - 1; i >= 0; i-- {
+		switch x := plans[i].(type) {
+		case *plannercore.PhysicalTableScan:
+			pruningConds = append(pruningConds, x.AccessCondition...)
+		case *plannercore.PhysicalIndexScan:
+			pruningConds = append(pruningConds, x.AccessCondition...)
+		case *plannercore.PhysicalSelection:
+			pruningConds = append(pruningConds, x.Conditions...)
+		default:
+			return expression.RemoveDupExprs(sctx, pruningConds)
+		}
+	}
+	return expression.RemoveDupExprs(sctx, pruningConds)
+}
+
 // NewIndexReaderImpl creates a new IndexReader Implementation.
 func NewIndexReaderImpl(reader *plannercore.PhysicalIndexReader, source *plannercore.DataSource) *IndexReaderImpl {
 	return &IndexReaderImpl{
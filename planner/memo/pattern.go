@@ -93,7 +93,7 @@ func GetOperand(p plannercore.LogicalPlan) Operand {
 		return OperandDataSource
 	case *plannercore.LogicalUnionScan:
 		return OperandUnionScan
-	case *plannercore.LogicalUnionAll:
+	case *plannercore.LogicalUnionAll, *plannercore.LogicalPartitionUnionAll:
 		return OperandUnionAll
 	case *plannercore.LogicalSort:
 		return OperandSort
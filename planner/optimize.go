@@ -46,6 +46,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	utilparser "github.com/pingcap/tidb/util/parser"
 	"github.com/pingcap/tidb/util/topsql"
+	"github.com/pingcap/tidb/util/tracing"
 	"go.uber.org/zap"
 )
 
@@ -139,6 +140,9 @@ func getPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Contex
 // Optimize does optimization and creates a Plan.
 // The node must be prepared first.
 func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plan core.Plan, slice types.NameSlice, retErr error) {
+	r, ctx := tracing.StartRegionEx(ctx, "planner.Optimize")
+	defer r.End()
+
 	sessVars := sctx.GetSessionVars()
 	if sessVars.StmtCtx.EnableOptimizerDebugTrace {
 		debugtrace.EnterContextCommon(sctx)
@@ -220,6 +224,16 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		return nil, nil, err
 	}
 
+	if sessVars.EnableMaterializedViewRewrite && !sessVars.InRestrictedSQL {
+		rewritten, err := core.TryRewriteByMaterializedView(ctx, sctx, node, is)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rewritten != nil {
+			node = rewritten
+		}
+	}
+
 	enableUseBinding := sessVars.UsePlanBaselines
 	stmtNode, isStmtNode := node.(ast.StmtNode)
 	bindRecord, scope, match := matchSQLBinding(sctx, stmtNode)
@@ -506,6 +520,10 @@ func optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 
 	// Handle the logical plan statement, use cascades planner if enabled.
 	if sessVars.GetEnableCascadesPlanner() {
+		logic, err = core.PreprocessPartitionedTables(ctx, builder.GetOptFlag(), logic)
+		if err != nil {
+			return nil, nil, 0, err
+		}
 		finalPlan, cost, err := cascades.DefaultOptimizer.FindBestPlan(sctx, logic)
 		return finalPlan, names, cost, err
 	}
@@ -37,6 +37,9 @@ type VerificationInfo struct {
 	FailedDueToWrongPassword bool
 	// ResourceGroupName records the resource group name for the user.
 	ResourceGroupName string
+	// TenantName records the tenant of the user, which is the "tenant" in the metadata of the user attributes.
+	// The users can't change their own tenants without the CREATE USER privilege.
+	TenantName string
 }
 
 // Manager is the interface for providing privilege related operations.
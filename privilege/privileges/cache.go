@@ -94,7 +94,8 @@ type baseRecord struct {
 
 // MetadataInfo is the User_attributes->>"$.metadata".
 type MetadataInfo struct {
-	Email string
+	Email  string
+	Tenant string
 }
 
 // UserAttributesInfo is the 'User_attributes' in privilege cache.
@@ -689,6 +690,17 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 				}
 				value.Email = email
 			}
+			pathExpr, err = types.ParseJSONPathExpr("$.metadata.tenant")
+			if err != nil {
+				return err
+			}
+			if tenantBJ, found := bj.Extract([]types.JSONPathExpression{pathExpr}); found {
+				tenant, err := tenantBJ.Unquote()
+				if err != nil {
+					return err
+				}
+				value.Tenant = tenant
+			}
 			pathExpr, err = types.ParseJSONPathExpr("$.resource_group")
 			if err != nil {
 				return err
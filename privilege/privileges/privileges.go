@@ -66,6 +66,11 @@ var dynamicPrivs = []string{
 	"RESTRICTED_CONNECTION_ADMIN",     // Can not be killed by PROCESS/CONNECTION_ADMIN privilege
 	"RESTRICTED_REPLICA_WRITER_ADMIN", // Can write to the sever even when tidb_restriced_read_only is turned on.
 	"RESOURCE_GROUP_ADMIN",            // Create/Drop/Alter RESOURCE GROUP
+	"TIMER_ADMIN",                     // Create/Alter/Drop/Pause/Resume TIMER
+	"MASKING_POLICY_ADMIN",            // Create/Drop MASKING POLICY
+	"UNMASKED_READ",                   // Can read the columns with masking policies unmasked.
+	"ROW_ACCESS_POLICY_ADMIN",         // Create/Drop ROW ACCESS POLICY
+	"ROW_ACCESS_POLICY_EXEMPT",        // Can read and write the rows of the tables without the row access policies applied.
 }
 var dynamicPrivLock sync.Mutex
 var defaultTokenLife = 15 * time.Minute
@@ -635,6 +640,7 @@ func (p *UserPrivileges) ConnectionVerification(user *auth.UserIdentity, authUse
 	if record.ResourceGroup != "" {
 		info.ResourceGroupName = record.ResourceGroup
 	}
+	info.TenantName = record.Tenant
 	// Skip checking password expiration if the session is migrated from another session.
 	// Otherwise, the user cannot log in or execute statements after migration.
 	if user.AuthPlugin != mysql.AuthTiDBSessionToken {
@@ -54,6 +54,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/config"
@@ -94,6 +95,7 @@ import (
 	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
@@ -101,6 +103,7 @@ import (
 	tlsutil "github.com/pingcap/tidb/util/tls"
 	topsqlstate "github.com/pingcap/tidb/util/topsql/state"
 	"github.com/pingcap/tidb/util/tracing"
+	"github.com/pingcap/tidb/util/tracing/otlp"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
@@ -1159,6 +1162,17 @@ func (cc *clientConn) addMetrics(cmd byte, startTime time.Time, err error) {
 	}
 }
 
+// traceParent returns the W3C trace context passed by the client. It's read from the comment of the query first,
+// and then the connection attribute `traceparent`.
+func (cc *clientConn) traceParent(data []byte) string {
+	if len(data) > 0 && data[0] == mysql.ComQuery {
+		if traceParent := otlp.TraceParentFromSQL(string(hack.String(data[1:]))); traceParent != "" {
+			return traceParent
+		}
+	}
+	return cc.attrs[otlp.TraceParentKey]
+}
+
 // dispatch handles client request based on command which is the first byte of the data.
 // It also gets a token from server which is used to limit the concurrently handling clients.
 // The most frequently used command is ComQuery.
@@ -1175,7 +1189,13 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	}
 
 	cfg := config.GetGlobalConfig()
-	if cfg.OpenTracing.Enable {
+	if tracer := otlp.GlobalTracer(); tracer != nil {
+		if span := tracer.StartRootSpan("server.dispatch", cc.traceParent(data)); span != nil {
+			span.SetTag("conn", cc.connectionID)
+			ctx = opentracing.ContextWithSpan(ctx, span)
+			defer span.Finish()
+		}
+	} else if cfg.OpenTracing.Enable {
 		var r tracing.Region
 		r, ctx = tracing.StartRegionEx(ctx, "server.dispatch")
 		defer r.End()
@@ -2127,7 +2147,7 @@ func (cc *clientConn) writeResultSet(ctx context.Context, rs resultset.ResultSet
 		if r == nil {
 			return
 		}
-		if str, ok := r.(string); !ok || !strings.HasPrefix(str, memory.PanicMemoryExceedWarnMsg) {
+		if str, ok := r.(string); !ok || !strings.HasPrefix(str, memory.PanicMemoryExceedWarnMsg) && !strings.HasPrefix(str, disk.PanicDiskExceedWarnMsg) {
 			panic(r)
 		}
 		// TODO(jianzhang.zj: add metrics here)
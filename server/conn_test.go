@@ -1990,3 +1990,18 @@ func TestEmptyOrgName(t *testing.T) {
 
 	testDispatch(t, inputs, 0)
 }
+
+func TestTraceParent(t *testing.T) {
+	traceParent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	attrTraceParent := "00-0af7651916cd43dd8448eb211c80319d-b7ad6b7169203332-01"
+	cc := &clientConn{}
+	query := append([]byte{mysql.ComQuery}, []byte("select 1 /*traceparent='"+traceParent+"'*/")...)
+	require.Equal(t, traceParent, cc.traceParent(query))
+	require.Equal(t, "", cc.traceParent([]byte{mysql.ComQuery, 's'}))
+
+	// the trace context in the query comment takes precedence over the connection attribute
+	cc.attrs = map[string]string{"traceparent": attrTraceParent}
+	require.Equal(t, traceParent, cc.traceParent(query))
+	require.Equal(t, attrTraceParent, cc.traceParent([]byte{mysql.ComQuery, 's'}))
+	require.Equal(t, attrTraceParent, cc.traceParent([]byte{mysql.ComStmtExecute, 1, 0, 0, 0}))
+}
@@ -297,6 +297,29 @@ const (
 		INDEX time_index(update_time) COMMENT "accelerate the speed when querying with last update time"
 	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
 
+	// CreateBindEvolveHistoryTable stores the results of the plan baseline evolution.
+	CreateBindEvolveHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.bind_evolve_history (
+		original_sql TEXT NOT NULL,
+		bind_sql TEXT NOT NULL,
+		default_db TEXT NOT NULL,
+		sql_digest varchar(64),
+		plan_digest varchar(64),
+		status varchar(20) NOT NULL,
+		accepted_plan_time BIGINT NOT NULL COMMENT "the execution time of the accepted plans in milliseconds, -1 means timeout",
+		verify_plan_time BIGINT NOT NULL COMMENT "the execution time of the verified plan in milliseconds, -1 means timeout",
+		reason TEXT,
+		evolve_time TIMESTAMP(3) NOT NULL,
+		INDEX digest_index(sql_digest) COMMENT "accelerate the speed when querying the history of a statement",
+		INDEX time_index(evolve_time) COMMENT "accelerate the speed when deleting the expired history"
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+
+	// CreateMaterializedViewRefreshTable stores the last refresh of each materialized view.
+	CreateMaterializedViewRefreshTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_materialized_view_refresh (
+		view_id BIGINT NOT NULL PRIMARY KEY,
+		last_refresh_type VARCHAR(32) NOT NULL,
+		last_refresh_time TIMESTAMP(6) NOT NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
+
 	// CreateRoleEdgesTable stores the role and user relationship information.
 	CreateRoleEdgesTable = `CREATE TABLE IF NOT EXISTS mysql.role_edges (
 		FROM_HOST 			CHAR(60) COLLATE utf8_bin NOT NULL DEFAULT '',
@@ -614,6 +637,7 @@ const (
 		original_sql TEXT NOT NULL,
 		plan_digest TEXT NOT NULL,
 		tidb_server varchar(64),
+		rule varchar(64),
 		INDEX plan_index(plan_digest(64)) COMMENT "accelerate the speed when select runaway query",
 		INDEX time_index(time) COMMENT "accelerate the speed when querying with active watch"
 	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;`
@@ -947,11 +971,17 @@ const (
 	version168 = 168
 	version169 = 169
 	version170 = 170
+	// version 171 add table `mysql.bind_evolve_history`
+	version171 = 171
+	// version 172 add column `rule` to `mysql.tidb_runaway_queries`
+	version172 = 172
+	// version 173 add table `mysql.tidb_materialized_view_refresh`
+	version173 = 173
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version170
+var currentBootstrapVersion int64 = version173
 
 // DDL owner key's expired time is ManagerSessionTTL seconds, we should wait the time and give more time to have a chance to finish it.
 var internalSQLTimeout = owner.ManagerSessionTTL + 15
@@ -1090,6 +1120,9 @@ var (
 		upgradeToVer168,
 		upgradeToVer169,
 		upgradeToVer170,
+		upgradeToVer171,
+		upgradeToVer172,
+		upgradeToVer173,
 	}
 )
 
@@ -2744,6 +2777,27 @@ func upgradeToVer170(s Session, ver int64) {
 	mustExecute(s, CreateTimers)
 }
 
+func upgradeToVer171(s Session, ver int64) {
+	if ver >= version171 {
+		return
+	}
+	mustExecute(s, CreateBindEvolveHistoryTable)
+}
+
+func upgradeToVer172(s Session, ver int64) {
+	if ver >= version172 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.tidb_runaway_queries ADD COLUMN `rule` VARCHAR(64) AFTER `tidb_server`", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer173(s Session, ver int64) {
+	if ver >= version173 {
+		return
+	}
+	mustExecute(s, CreateMaterializedViewRefreshTable)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -2866,6 +2920,10 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateRunawayTable)
 	// create tidb_timers
 	mustExecute(s, CreateTimers)
+	// create bind_evolve_history
+	mustExecute(s, CreateBindEvolveHistoryTable)
+	// create tidb_materialized_view_refresh
+	mustExecute(s, CreateMaterializedViewRefreshTable)
 }
 
 // doBootstrapSQLFile executes SQL commands in a file as the last stage of bootstrap.
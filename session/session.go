@@ -2159,6 +2159,13 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	sessVars := s.sessionVars
 	sessVars.StartTime = time.Now()
 
+	if s.extensions.HasStmtRewriters() && !sessVars.InRestrictedSQL {
+		var err error
+		if stmtNode, err = s.extensions.RewriteStmt(extension.NewSessionInfo(sessVars), stmtNode); err != nil {
+			return nil, err
+		}
+	}
+
 	// Some executions are done in compile stage, so we reset them before compile.
 	if err := executor.ResetContextOfStmt(s, stmtNode); err != nil {
 		return nil, err
@@ -2739,6 +2746,7 @@ func (s *session) Auth(user *auth.UserIdentity, authentication, salt []byte, aut
 	if variable.EnableResourceControl.Load() && info.ResourceGroupName != "" {
 		s.sessionVars.ResourceGroupName = strings.ToLower(info.ResourceGroupName)
 	}
+	s.sessionVars.TenantName = info.TenantName
 
 	if info.InSandBoxMode {
 		// Enter sandbox mode, only execute statement for resetting password.
@@ -3510,6 +3518,9 @@ func bootstrapSessionImpl(store kv.Storage, createSessionsImpl func(store kv.Sto
 		return s
 	}
 	dom.StartTTLJobManager()
+	dom.StartUserTimers()
+	dom.StartIntervalPartitionWorker()
+	dom.StartMaterializedViewRefreshWorker()
 
 	analyzeCtxs, err := createSessions(store, analyzeConcurrencyQuota)
 	if err != nil {
@@ -359,6 +359,9 @@ type StatementContext struct {
 	// RCCheckTS indicates the current read-consistency read select statement will use `RCCheckTS` path.
 	RCCheckTS bool
 
+	// WaitingInResourceGroupQueue indicates whether the statement is waiting in the admission queue of its resource group.
+	WaitingInResourceGroupQueue atomic2.Bool
+
 	// IsSQLRegistered uses to indicate whether the SQL has been registered for TopSQL.
 	IsSQLRegistered atomic2.Bool
 	// IsSQLAndPlanRegistered uses to indicate whether the SQL and plan has been registered for TopSQL.
@@ -1424,6 +1424,10 @@ type SessionVars struct {
 	MemDBFootprint *memory.Tracker
 	DiskTracker    *memory.Tracker
 
+	// TmpStorageQuotaQuery is the quota of the temporary storage used by a query, the query is cancelled
+	// when it's exceeded. It's not limited if it's less than or equal to 0.
+	TmpStorageQuotaQuery int64
+
 	// OptPrefixIndexSingleScan indicates whether to do some optimizations to avoid double scan for prefix index.
 	// When set to true, `col is (not) null`(`col` is index prefix column) is regarded as index filter rather than table filter.
 	OptPrefixIndexSingleScan bool
@@ -1459,6 +1463,9 @@ type SessionVars struct {
 	// Resource group name
 	ResourceGroupName string
 
+	// TenantName is the tenant of the current user, which is returned by CURRENT_TENANT().
+	TenantName string
+
 	// PessimisticTransactionFairLocking controls whether fair locking for pessimistic transaction
 	// is enabled.
 	PessimisticTransactionFairLocking bool
@@ -1492,6 +1499,13 @@ type SessionVars struct {
 	// EnableMPPSharedCTEExecution indicates whether we enable the shared CTE execution strategy on MPP side.
 	EnableMPPSharedCTEExecution bool
 
+	// EnableMaterializedViewRewrite indicates whether the optimizer answers the queries from the materialized views.
+	EnableMaterializedViewRewrite bool
+
+	// MaterializedViewMaxStaleness is the max duration since the last refresh of a materialized view which can be
+	// used to answer a query. A view which can be refreshed incrementally is also used if the base table is not changed.
+	MaterializedViewMaxStaleness time.Duration
+
 	// OptimizerFixControl control some details of the optimizer behavior through the tidb_opt_fix_control variable.
 	OptimizerFixControl map[uint64]string
 
@@ -2022,7 +2036,9 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 	vars.TiFlashMaxBytesBeforeExternalSort = DefTiFlashMaxBytesBeforeExternalSort
 	vars.TiFlashEnablePipelineMode = DefTiDBEnableTiFlashPipelineMode
 	vars.MPPStoreFailTTL = DefTiDBMPPStoreFailTTL
-	vars.DiskTracker = disk.NewTracker(memory.LabelForSession, -1)
+	vars.TmpStorageQuotaQuery = DefTiDBTmpStorageQuotaQuery
+	vars.DiskTracker = disk.NewTracker(memory.LabelForSession, vars.TmpStorageQuotaQuery)
+	vars.DiskTracker.TrackTotalConsumed()
 	vars.MemTracker = memory.NewTracker(memory.LabelForSession, vars.MemQuotaQuery)
 	vars.MemTracker.IsRootTrackerOfSess = true
 
@@ -2985,6 +3001,8 @@ const (
 	SlowLogMemMax = "Mem_max"
 	// SlowLogDiskMax is the nax number bytes of disk used in this statement.
 	SlowLogDiskMax = "Disk_max"
+	// SlowLogDiskSpilled is the total number bytes spilled to disk in this statement.
+	SlowLogDiskSpilled = "Disk_spilled"
 	// SlowLogPrepared is used to indicate whether this sql execute in prepare.
 	SlowLogPrepared = "Prepared"
 	// SlowLogPlanFromCache is used to indicate whether this plan is from plan cache.
@@ -3069,6 +3087,7 @@ type SlowQueryLogItems struct {
 	ExecDetail        execdetails.ExecDetails
 	MemMax            int64
 	DiskMax           int64
+	DiskSpilled       int64
 	Succ              bool
 	Prepared          bool
 	PlanFromCache     bool
@@ -3113,6 +3132,7 @@ type SlowQueryLogItems struct {
 // # Cop_wait: Avg_time: 10ms P90_time: 20ms Max_time: 30ms Max_Addr: 10.6.131.79
 // # Memory_max: 4096
 // # Disk_max: 65535
+// # Disk_spilled: 131070
 // # Succ: true
 // # Prev_stmt: begin;
 // select * from t_slim;
@@ -3250,6 +3270,9 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	if logItems.DiskMax > 0 {
 		writeSlowLogItem(&buf, SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
 	}
+	if logItems.DiskSpilled > 0 {
+		writeSlowLogItem(&buf, SlowLogDiskSpilled, strconv.FormatInt(logItems.DiskSpilled, 10))
+	}
 
 	writeSlowLogItem(&buf, SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
 	writeSlowLogItem(&buf, SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
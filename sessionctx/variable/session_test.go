@@ -218,6 +218,7 @@ func TestSlowLogFormat(t *testing.T) {
 
 	var memMax int64 = 2333
 	var diskMax int64 = 6666
+	var diskSpilled int64 = 8888
 	resultFields := `# Txn_start_ts: 406649736972468225
 # Keyspace_name: keyspace_a
 # Keyspace_ID: 1
@@ -244,6 +245,7 @@ func TestSlowLogFormat(t *testing.T) {
 # Cop_backoff_rpcTiKV_total_times: 200 Cop_backoff_rpcTiKV_total_time: 0.2 Cop_backoff_rpcTiKV_max_time: 0.2 Cop_backoff_rpcTiKV_max_addr: 127.0.0.1 Cop_backoff_rpcTiKV_avg_time: 0.2 Cop_backoff_rpcTiKV_p90_time: 0.2
 # Mem_max: 2333
 # Disk_max: 6666
+# Disk_spilled: 8888
 # Prepared: true
 # Plan_from_cache: true
 # Plan_from_binding: true
@@ -275,6 +277,7 @@ func TestSlowLogFormat(t *testing.T) {
 		ExecDetail:        execDetail,
 		MemMax:            memMax,
 		DiskMax:           diskMax,
+		DiskSpilled:       diskSpilled,
 		Prepared:          true,
 		PlanFromCache:     true,
 		PlanFromBinding:   true,
@@ -140,6 +140,8 @@ const (
 	TiDBMemQuotaQuery = "tidb_mem_quota_query" // Bytes.
 	// TiDBMemQuotaApplyCache controls the memory quota of a query.
 	TiDBMemQuotaApplyCache = "tidb_mem_quota_apply_cache"
+	// TiDBTmpStorageQuotaQuery controls the quota of the temporary storage used by a query when spilling to disk.
+	TiDBTmpStorageQuotaQuery = "tidb_tmp_storage_quota_query" // Bytes.
 
 	// TiDBGeneralLog is used to log every query in the server in info level.
 	TiDBGeneralLog = "tidb_general_log"
@@ -218,6 +220,10 @@ const (
 	TiDBEvolvePlanTaskStartTime = "tidb_evolve_plan_task_start_time"
 	// TiDBEvolvePlanTaskEndTime is the end time of evolution task.
 	TiDBEvolvePlanTaskEndTime = "tidb_evolve_plan_task_end_time"
+	// TiDBEvolvePlanTaskMaxMemory controls the max memory used by a statement of a single evolution task.
+	TiDBEvolvePlanTaskMaxMemory = "tidb_evolve_plan_task_max_memory"
+	// TiDBEvolvePlanHistoryRetentionDays is the number of days to keep the records in mysql.bind_evolve_history.
+	TiDBEvolvePlanHistoryRetentionDays = "tidb_evolve_plan_history_retention_days"
 
 	// TiDBSlowLogThreshold is used to set the slow log threshold in the server.
 	TiDBSlowLogThreshold = "tidb_slow_log_threshold"
@@ -884,6 +890,12 @@ const (
 
 	// TiDBOptEnableMPPSharedCTEExecution indicates whehter the optimizer try to build shared CTE scan during MPP execution.
 	TiDBOptEnableMPPSharedCTEExecution = "tidb_opt_enable_mpp_shared_cte_execution"
+	// TiDBOptEnableMaterializedViewRewrite indicates whether the optimizer rewrites a query to read the materialized view
+	// whose definition is the same as the query.
+	TiDBOptEnableMaterializedViewRewrite = "tidb_opt_enable_materialized_view_rewrite"
+	// TiDBOptMaterializedViewMaxStaleness is the max duration since the last refresh of a materialized view
+	// which can be used to answer a query.
+	TiDBOptMaterializedViewMaxStaleness = "tidb_opt_materialized_view_max_staleness"
 	// TiDBOptFixControl makes the user able to control some details of the optimizer behavior.
 	TiDBOptFixControl = "tidb_opt_fix_control"
 
@@ -1022,6 +1034,14 @@ const (
 	TiDBStmtSummaryFileMaxSize = "tidb_stmt_summary_file_max_size"
 	// TiDBStmtSummaryFileMaxBackups indicates the maximum number of files written by stmtsummary.
 	TiDBStmtSummaryFileMaxBackups = "tidb_stmt_summary_file_max_backups"
+	// TiDBStmtSummaryEnableCompaction indicates whether to persist stmtsummary to a compacted store.
+	TiDBStmtSummaryEnableCompaction = "tidb_stmt_summary_enable_compaction"
+	// TiDBStmtSummaryCompactionInterval indicates the length (in seconds) of the time buckets compacted by stmtsummary.
+	TiDBStmtSummaryCompactionInterval = "tidb_stmt_summary_compaction_interval"
+	// TiDBStmtSummaryHistoryMaxDays indicates how many days the statements compacted by stmtsummary will be kept.
+	TiDBStmtSummaryHistoryMaxDays = "tidb_stmt_summary_history_max_days"
+	// TiDBStmtSummaryHistoryMaxSize indicates the maximum total size (in mb) of the statements compacted by stmtsummary.
+	TiDBStmtSummaryHistoryMaxSize = "tidb_stmt_summary_history_max_size"
 	// TiDBTTLRunningTasks limits the count of running ttl tasks. Default to 0, means 3 times the count of TiKV (or no
 	// limitation, if the storage is not TiKV).
 	TiDBTTLRunningTasks = "tidb_ttl_running_tasks"
@@ -1203,6 +1223,8 @@ const (
 	DefTiDBEvolvePlanTaskMaxTime                   = 600 // 600s
 	DefTiDBEvolvePlanTaskStartTime                 = "00:00 +0000"
 	DefTiDBEvolvePlanTaskEndTime                   = "23:59 +0000"
+	DefTiDBEvolvePlanTaskMaxMemory                 = 1 << 30 // 1GB
+	DefTiDBEvolvePlanHistoryRetentionDays          = 30
 	DefInnodbLockWaitTimeout                       = 50 // 50s
 	DefTiDBStoreLimit                              = 0
 	DefTiDBMetricSchemaStep                        = 60 // 60s
@@ -1267,6 +1289,7 @@ const (
 	DefMaxAllowedPacket                     uint64 = 67108864
 	DefTiDBEnableBatchDML                          = false
 	DefTiDBMemQuotaQuery                           = 1073741824 // 1GB
+	DefTiDBTmpStorageQuotaQuery                    = -1
 	DefTiDBStatsCacheMemQuota                      = 0
 	MaxTiDBStatsCacheMemQuota                      = 1024 * 1024 * 1024 * 1024 // 1TB
 	DefTiDBQueryLogMaxLen                          = 4096
@@ -1359,6 +1382,8 @@ const (
 	DefTiDBOptEnableLateMaterialization               = true
 	DefTiDBOptOrderingIdxSelThresh                    = 0.0
 	DefTiDBOptEnableMPPSharedCTEExecution             = false
+	DefTiDBOptEnableMaterializedViewRewrite           = false
+	DefTiDBOptMaterializedViewMaxStaleness            = 5 * time.Minute
 	DefTiDBPlanCacheInvalidationOnFreshStats          = true
 	DefTiDBEnableRowLevelChecksum                     = false
 	DefAuthenticationLDAPSASLAuthMethodName           = "SCRAM-SHA-1"
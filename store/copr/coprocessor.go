@@ -1180,6 +1180,8 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		}
 	}
 	req.StoreTp = getEndPointType(task.storeType)
+	r := tracing.StartRegion(bo.GetCtx(), "copr.handleTaskOnce")
+	defer r.End()
 	startTime := time.Now()
 	if worker.kvclient.Stats == nil {
 		worker.kvclient.Stats = make(map[tikvrpc.CmdType]*tikv.RPCRuntimeStats)
@@ -1215,6 +1217,10 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 
 	// Set task.storeAddr field so its task.String() method have the store address information.
 	task.storeAddr = storeAddr
+	if r.Span != nil {
+		r.Span.SetTag("region_id", task.region.GetID())
+		r.Span.SetTag("store_addr", storeAddr)
+	}
 
 	costTime := time.Since(startTime)
 	copResp := resp.Resp.(*coprocessor.Response)
@@ -1223,7 +1229,7 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		worker.logTimeCopTask(costTime, task, bo, copResp)
 	}
 	if worker.req.RunawayChecker != nil {
-		worker.req.RunawayChecker.AfterCopRequest()
+		worker.req.RunawayChecker.AfterCopRequest(copResp)
 	}
 
 	storeID := strconv.FormatUint(req.Context.GetPeer().GetStoreId(), 10)
@@ -2186,7 +2186,8 @@ func (w *GCWorker) doGCPlacementRules(se session.Session, safePoint uint64, dr u
 		if err = historyJob.DecodeArgs(&physicalTableIDs); err != nil {
 			return
 		}
-	case model.ActionReorganizePartition:
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning,
+		model.ActionRemovePartitioning:
 		if err = historyJob.DecodeArgs(&physicalTableIDs); err != nil {
 			return
 		}
@@ -15,7 +15,6 @@
 package tables
 
 import (
-	"bytes"
 	"context"
 	"sync"
 
@@ -27,7 +26,6 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
-	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tidb/util/tracing"
 )
@@ -62,7 +60,7 @@ func NewIndex(physicalID int64, tblInfo *model.TableInfo, indexInfo *model.Index
 	var prefix kv.Key
 	if indexInfo.Global {
 		// In glabal index of partition table, prefix start with tblInfo.ID.
-		prefix = tablecodec.EncodeTableIndexPrefix(tblInfo.ID, indexInfo.ID)
+		prefix = tablecodec.EncodeTableIndexPrefix(globalIndexTableID(tblInfo, indexInfo), indexInfo.ID)
 	} else {
 		// Otherwise, start with physicalID.
 		prefix = tablecodec.EncodeTableIndexPrefix(physicalID, indexInfo.ID)
@@ -76,6 +74,16 @@ func NewIndex(physicalID int64, tblInfo *model.TableInfo, indexInfo *model.Index
 	return index
 }
 
+// globalIndexTableID returns the table ID in the keys of the global index.
+// The global indexes added by ALTER TABLE ... PARTITION BY are encoded with the new table ID,
+// since the old table ID is not used after the partitioning is changed.
+func globalIndexTableID(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) int64 {
+	if pi := tblInfo.GetPartitionInfo(); pi != nil && pi.NewTableID != 0 && pi.DDLChangedIndex[indexInfo.ID] {
+		return pi.NewTableID
+	}
+	return tblInfo.ID
+}
+
 // Meta returns index info.
 func (c *index) Meta() *model.IndexInfo {
 	return c.idxInfo
@@ -91,7 +99,7 @@ func (c *index) TableMeta() *model.TableInfo {
 func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.Datum, h kv.Handle, buf []byte) (key []byte, distinct bool, err error) {
 	idxTblID := c.phyTblID
 	if c.idxInfo.Global {
-		idxTblID = c.tblInfo.ID
+		idxTblID = globalIndexTableID(c.tblInfo, c.idxInfo)
 	}
 	return tablecodec.GenIndexKey(sc, c.tblInfo, c.idxInfo, idxTblID, indexedValues, h, buf)
 }
@@ -237,7 +245,15 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 			return nil, err
 		}
 
-		opt.IgnoreAssertion = opt.IgnoreAssertion || c.idxInfo.State != model.StatePublic
+		// The entries of the dropped partitions may be overwritten before they are cleaned up.
+		opt.IgnoreAssertion = opt.IgnoreAssertion || c.idxInfo.State != model.StatePublic || c.mayHaveStaleGlobalEntries()
+
+		// The backfill of the exchanged rows adds the entries checked against, skip it.
+		if distinct && !keyIsTempIdxKey && !opt.Untouched && !opt.FromBackFill {
+			if err = c.checkExchangingTableKey(ctx, txn, key); err != nil {
+				return nil, err
+			}
+		}
 
 		if !distinct || skipCheck || opt.Untouched {
 			val := idxVal
@@ -279,7 +295,7 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 		if c.tblInfo.TempTableType != model.TempTableNone {
 			// Always check key for temporary table because it does not write to TiKV
 			value, err = txn.Get(ctx, key)
-		} else if sctx.GetSessionVars().LazyCheckKeyNotExists() && !keyIsTempIdxKey {
+		} else if sctx.GetSessionVars().LazyCheckKeyNotExists() && !keyIsTempIdxKey && !c.mayHaveStaleGlobalEntries() {
 			// For temp index keys, we can't get the temp value from memory buffer, even if the lazy check is enabled.
 			// Otherwise, it may cause the temp index value to be overwritten, leading to data inconsistency.
 			value, err = txn.GetMemBuffer().Get(ctx, key)
@@ -304,7 +320,7 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 				tempVal := tablecodec.TempIndexValueElem{Value: idxVal, KeyVer: keyVer, Distinct: true}
 				val = tempVal.Encode(value)
 			}
-			needPresumeNotExists, err := needPresumeKeyNotExistsFlag(ctx, txn, key, tempKey, h,
+			needPresumeNotExists, err := needPresumeKeyNotExistsFlag(ctx, txn, key, tempKey, c.handleInIndex(h),
 				keyIsTempIdxKey, c.tblInfo.IsCommonHandle, c.tblInfo.ID)
 			if err != nil {
 				return nil, err
@@ -350,19 +366,26 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 			}
 			continue
 		}
-		if c.idxInfo.Global && len(value) != 0 && !bytes.Equal(value, idxVal) {
-			val := idxVal
-			err = txn.GetMemBuffer().Set(key, val)
+
+		if !keyIsTempIdxKey && len(tempKey) == 0 {
+			stale, err := c.isStaleGlobalIndexValue(value)
 			if err != nil {
 				return nil, err
 			}
-			continue
+			if stale {
+				// The entry of a dropped partition is not cleaned up yet, overwrite it.
+				if err = txn.GetMemBuffer().Set(key, idxVal); err != nil {
+					return nil, err
+				}
+				continue
+			}
 		}
-
 		if keyIsTempIdxKey && !tempIdxVal.IsEmpty() {
 			value = tempIdxVal.Current().Value
 		}
-		handle, err := tablecodec.DecodeHandleInUniqueIndexValue(value, c.tblInfo.IsCommonHandle)
+		// The duplicated row of a global index may be in another partition,
+		// so the handle is returned as a kv.PartitionHandle.
+		handle, err := tablecodec.DecodeHandleInGlobalIndexValue(value, c.tblInfo.IsCommonHandle)
 		if err != nil {
 			return nil, err
 		}
@@ -371,6 +394,53 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 	return nil, nil
 }
 
+// mayHaveStaleGlobalEntries checks whether the global index may contain the entries of
+// the partitions being dropped or truncated, which are cleaned up in the background.
+func (c *index) mayHaveStaleGlobalEntries() bool {
+	pi := c.tblInfo.GetPartitionInfo()
+	if !c.idxInfo.Global || pi == nil || len(pi.DroppingDefinitions) == 0 {
+		return false
+	}
+	// The indexes replaced by reorganizing partitions are rebuilt instead of cleaned up.
+	_, changed := pi.DDLChangedIndex[c.idxInfo.ID]
+	return !changed
+}
+
+// checkExchangingTableKey checks the unique global index against the index entries of the table
+// being exchanged with a partition, whose rows are not in the global index until the exchange finishes.
+func (c *index) checkExchangingTableKey(ctx context.Context, txn kv.Transaction, key kv.Key) error {
+	pi := c.tblInfo.GetPartitionInfo()
+	if !c.idxInfo.Global || pi == nil || pi.DDLExchangeTableID == 0 {
+		return nil
+	}
+	prefix := tablecodec.EncodeTableIndexPrefix(pi.DDLExchangeTableID, c.idxInfo.ID)
+	_, err := txn.Get(ctx, append(prefix, key[len(prefix):]...))
+	if err == nil {
+		return kv.ErrKeyExists
+	}
+	if kv.IsErrNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// isStaleGlobalIndexValue checks whether the value of the global index belongs to a partition being dropped or truncated.
+func (c *index) isStaleGlobalIndexValue(value []byte) (bool, error) {
+	if !c.mayHaveStaleGlobalEntries() {
+		return false, nil
+	}
+	pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(value)
+	if err != nil || !ok {
+		return false, err
+	}
+	for _, def := range c.tblInfo.Partition.DroppingDefinitions {
+		if def.ID == pid {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func needPresumeKeyNotExistsFlag(ctx context.Context, txn kv.Transaction, key, tempKey kv.Key,
 	h kv.Handle, keyIsTempIdxKey bool, isCommon bool, tblID int64) (needFlag bool, err error) {
 	var uniqueTempKey kv.Key
@@ -414,17 +484,20 @@ func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexed
 
 		// If index is global, decode the pid from value (if exists) and compare with c.physicalID.
 		// Only when pid in value equals to c.physicalID, the key can be deleted.
-		if c.idxInfo.Global {
-			if val, err := txn.GetMemBuffer().Get(context.Background(), key); err == nil {
-				segs := tablecodec.SplitIndexValue(val)
-				if len(segs.PartitionID) != 0 {
-					_, pid, err := codec.DecodeInt(segs.PartitionID)
-					if err != nil {
-						return err
-					}
-					if pid != c.phyTblID {
-						continue
-					}
+		// The value is read from the transaction instead of the memory buffer, otherwise the entry
+		// written by another partition may be deleted when cleaning up a dropped partition.
+		if c.idxInfo.Global && distinct && len(key) > 0 {
+			val, err := getKeyInTxn(context.TODO(), txn, key)
+			if err != nil {
+				return err
+			}
+			if len(val) > 0 {
+				pid, ok, err := tablecodec.DecodePartitionIDInIndexValue(val)
+				if err != nil {
+					return err
+				}
+				if ok && pid != c.phyTblID {
+					continue
 				}
 			}
 		}
@@ -563,7 +636,7 @@ func (c *index) Exist(sc *stmtctx.StatementContext, txn kv.Transaction, indexedV
 		if err != nil || !foundKey {
 			return false, nil, err
 		}
-		if dupHandle != nil && !dupHandle.Equal(h) {
+		if dupHandle != nil && !dupHandle.Equal(c.handleInIndex(h)) {
 			return false, nil, err
 		}
 		continue
@@ -571,6 +644,15 @@ func (c *index) Exist(sc *stmtctx.StatementContext, txn kv.Transaction, indexedV
 	return true, h, nil
 }
 
+// handleInIndex returns the handle of the row as it is decoded from the unique index value,
+// the handle of a global index is located by the partition ID.
+func (c *index) handleInIndex(h kv.Handle) kv.Handle {
+	if c.idxInfo.Global {
+		return kv.NewPartitionHandle(c.phyTblID, h)
+	}
+	return h
+}
+
 // FetchDuplicatedHandle is used to find the duplicated row's handle for a given unique index key.
 func FetchDuplicatedHandle(ctx context.Context, key kv.Key, distinct bool,
 	txn kv.Transaction, tableID int64, isCommon bool) (foundKey bool, dupHandle kv.Handle, err error) {
@@ -583,7 +665,7 @@ func FetchDuplicatedHandle(ctx context.Context, key kv.Key, distinct bool,
 		return false, nil, err
 	}
 	if distinct {
-		h, err := tablecodec.DecodeHandleInUniqueIndexValue(val, isCommon)
+		h, err := tablecodec.DecodeHandleInGlobalIndexValue(val, isCommon)
 		return true, h, err
 	}
 	return true, nil, nil
@@ -603,7 +685,7 @@ func fetchDuplicatedHandleForTempIndexKey(ctx context.Context, tempKey kv.Key, d
 			return false, nil, err
 		}
 		if distinct {
-			originHandle, err := tablecodec.DecodeHandleInUniqueIndexValue(originVal, isCommon)
+			originHandle, err := tablecodec.DecodeHandleInGlobalIndexValue(originVal, isCommon)
 			if err != nil {
 				return false, nil, err
 			}
@@ -624,18 +706,23 @@ func fetchDuplicatedHandleForTempIndexKey(ctx context.Context, tempKey kv.Key, d
 			return false, nil, err
 		}
 		if distinct {
-			originHandle, err := tablecodec.DecodeHandleInUniqueIndexValue(originVal, isCommon)
+			originHandle, err := tablecodec.DecodeHandleInGlobalIndexValue(originVal, isCommon)
 			if err != nil {
 				return false, nil, err
 			}
-			if originHandle.Equal(curElem.Handle) {
+			rowHandle := originHandle
+			if ph, ok := originHandle.(kv.PartitionHandle); ok {
+				// The row of a global index is in the partition recorded in the value.
+				rowHandle, tableID = ph.Handle, ph.PartitionID
+			}
+			if rowHandle.Equal(curElem.Handle) {
 				// The key has been deleted. This is not a duplicated key.
 				return false, nil, nil
 			}
 			// The inequality means multiple modifications happened in the same key.
 			// We use the handle in origin index value to check if the row exists.
 			recPrefix := tablecodec.GenTableRecordPrefix(tableID)
-			rowKey := tablecodec.EncodeRecordKey(recPrefix, originHandle)
+			rowKey := tablecodec.EncodeRecordKey(recPrefix, rowHandle)
 			rowVal, err := getKeyInTxn(ctx, txn, rowKey)
 			if err != nil || rowVal == nil {
 				return false, nil, err
@@ -647,7 +734,7 @@ func fetchDuplicatedHandleForTempIndexKey(ctx context.Context, tempKey kv.Key, d
 	}
 	// The value in temp index is not the delete marker.
 	if distinct {
-		h, err := tablecodec.DecodeHandleInUniqueIndexValue(curElem.Value, isCommon)
+		h, err := tablecodec.DecodeHandleInGlobalIndexValue(curElem.Value, isCommon)
 		return true, h, err
 	}
 	return true, nil, nil
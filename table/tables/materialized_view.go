@@ -0,0 +1,69 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+)
+
+// tableByIDGetter is the part of infoschema.InfoSchema used to get the log tables.
+// The infoschema package can't be imported here because of the import cycle.
+type tableByIDGetter interface {
+	TableByID(id int64) (table.Table, bool)
+}
+
+// writeMaterializedViewLogs writes the changed row to the log tables of the materialized views
+// which are refreshed incrementally. `sign` is 1 for an inserted row and -1 for a deleted row.
+func (t *TableCommon) writeMaterializedViewLogs(sctx sessionctx.Context, r []types.Datum, sign int64, touched []bool) error {
+	for _, log := range t.meta.MaterializedViewLogs {
+		offsets := make([]int, 0, len(log.Cols))
+		logTouched := touched == nil
+		for _, name := range log.Cols {
+			col := model.FindColumnInfo(t.meta.Columns, name.L)
+			if col == nil || col.Offset >= len(r) {
+				return errors.Errorf("column %s of the materialized view log is not found in table %s", name.O, t.meta.Name.O)
+			}
+			offsets = append(offsets, col.Offset)
+			if !logTouched && col.Offset < len(touched) && touched[col.Offset] {
+				logTouched = true
+			}
+		}
+		if !logTouched {
+			continue
+		}
+
+		is, ok := sctx.GetInfoSchema().(tableByIDGetter)
+		if !ok {
+			return errors.Errorf("cannot write the materialized view log of table %s without information schema", t.meta.Name.O)
+		}
+		logTbl, ok := is.TableByID(log.TableID)
+		if !ok {
+			return errors.Errorf("the log table %d of the materialized view %d is not found", log.TableID, log.ViewID)
+		}
+		row := make([]types.Datum, 0, len(offsets)+1)
+		for _, offset := range offsets {
+			row = append(row, r[offset])
+		}
+		row = append(row, types.NewIntDatum(sign))
+		if _, err := logTbl.AddRecord(sctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
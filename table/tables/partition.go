@@ -147,7 +147,7 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Part
 	if pi.DDLState == model.StateDeleteReorganization {
 		origIdx := setIndexesState(ret, pi.DDLState)
 		defer unsetIndexesState(ret, origIdx)
-		ret.reorgPartitionExpr, err = newPartitionExpr(tblInfo, pi.DroppingDefinitions)
+		ret.reorgPartitionExpr, err = newPartitionExpr(getReorgPartitionTableInfo(tblInfo), pi.DroppingDefinitions)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -168,7 +168,7 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Part
 		if len(pi.AddingDefinitions) > 0 {
 			origIdx := setIndexesState(ret, pi.DDLState)
 			defer unsetIndexesState(ret, origIdx)
-			ret.reorgPartitionExpr, err = newPartitionExpr(tblInfo, pi.AddingDefinitions)
+			ret.reorgPartitionExpr, err = newPartitionExpr(getReorgPartitionTableInfo(tblInfo), pi.AddingDefinitions)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
@@ -217,6 +217,27 @@ func unsetIndexesState(t *partitionedTable, orig []*model.IndexInfo) {
 	t.meta.Indices = orig
 }
 
+// getReorgPartitionTableInfo returns a shallow copy of the table info, using the partitioning
+// of the partitions being reorganized, which differs from the current one for ALTER TABLE ... PARTITION BY.
+func getReorgPartitionTableInfo(tblInfo *model.TableInfo) *model.TableInfo {
+	reorgTblInfo := *tblInfo
+	pi := *tblInfo.Partition
+	useReorgPartitioning(&pi)
+	reorgTblInfo.Partition = &pi
+	return &reorgTblInfo
+}
+
+// useReorgPartitioning replaces the partitioning with the one of the partitions being reorganized.
+// The DDL partitioning is not set by the REORGANIZE PARTITION jobs of the older versions, which
+// don't change the partitioning, so the current one is kept. ALTER TABLE ... PARTITION BY and
+// REMOVE PARTITIONING always set NewTableID, and the latter reorganizes into a non-partitioned table.
+func useReorgPartitioning(pi *model.PartitionInfo) {
+	if pi.DDLType == model.PartitionTypeNone && pi.NewTableID == 0 {
+		return
+	}
+	pi.Type, pi.Expr, pi.Columns = pi.DDLType, pi.DDLExpr, pi.DDLColumns
+}
+
 func initPartition(t *partitionedTable, def model.PartitionDefinition) (*partition, error) {
 	var newPart partition
 	err := initTableCommonWithIndices(&newPart.TableCommon, t.meta, def.ID, t.Columns, t.allocs, t.Constraints)
@@ -237,6 +258,9 @@ func newPartitionExpr(tblInfo *model.TableInfo, defs []model.PartitionDefinition
 	}
 	pi := tblInfo.GetPartitionInfo()
 	switch pi.Type {
+	case model.PartitionTypeNone:
+		// Nothing to do, the whole non-partitioned table is the single partition.
+		return &PartitionExpr{}, nil
 	case model.PartitionTypeRange:
 		return generateRangePartitionExpr(ctx, pi, defs, columns, names)
 	case model.PartitionTypeHash:
@@ -1228,7 +1252,9 @@ func (t *partitionedTable) CheckForExchangePartition(ctx sessionctx.Context, pi
 func (t *partitionedTable) locatePartitionCommon(ctx sessionctx.Context, pi *model.PartitionInfo, partitionExpr *PartitionExpr, num uint64, r []types.Datum) (int, error) {
 	var err error
 	var idx int
-	switch t.meta.Partition.Type {
+	switch pi.Type {
+	case model.PartitionTypeNone:
+		idx = 0
 	case model.PartitionTypeRange:
 		if len(pi.Columns) == 0 {
 			idx, err = t.locateRangePartition(ctx, partitionExpr, r)
@@ -1270,7 +1296,8 @@ func (t *partitionedTable) locateReorgPartition(ctx sessionctx.Context, r []type
 	} else {
 		numParts = uint64(len(pi.AddingDefinitions))
 	}
-	idx, err := t.locatePartitionCommon(ctx, pi, t.reorgPartitionExpr, numParts, r)
+	reorgPi := getReorgPartitionTableInfo(t.Meta()).Partition
+	idx, err := t.locatePartitionCommon(ctx, reorgPi, t.reorgPartitionExpr, numParts, r)
 	if err != nil {
 		return 0, errors.Trace(err)
 	}
@@ -1337,7 +1364,7 @@ func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, partitio
 		isNull bool
 		err    error
 	)
-	if col, ok := t.partitionExpr.Expr.(*expression.Column); ok {
+	if col, ok := partitionExpr.Expr.(*expression.Column); ok {
 		if r[col.Index].IsNull() {
 			isNull = true
 		}
@@ -1346,13 +1373,13 @@ func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, partitio
 		evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 		defer t.evalBufferPool.Put(evalBuffer)
 		evalBuffer.SetDatums(r...)
-		val, isNull, err = t.partitionExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
+		val, isNull, err = partitionExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
 		if err != nil {
 			return 0, err
 		}
 		ret = val
 	}
-	unsigned := mysql.HasUnsignedFlag(t.partitionExpr.Expr.GetType().GetFlag())
+	unsigned := mysql.HasUnsignedFlag(partitionExpr.Expr.GetType().GetFlag())
 	ranges := partitionExpr.ForRangePruning
 	length := len(ranges.LessThan)
 	pos := sort.Search(length, func(i int) bool {
@@ -1449,6 +1476,8 @@ func GetReorganizedPartitionedTable(t table.Table) (table.PartitionedTable, erro
 		return nil, dbterror.ErrUnsupportedReorganizePartition.GenWithStackByArgs()
 	}
 	tblInfo := t.Meta().Clone()
+	pi := tblInfo.Partition
+	useReorgPartitioning(pi)
 	tblInfo.Partition.Definitions = tblInfo.Partition.AddingDefinitions
 	tblInfo.Partition.AddingDefinitions = nil
 	tblInfo.Partition.DroppingDefinitions = nil
@@ -1609,6 +1638,15 @@ func (t *partitionTableWithGivenSets) UpdateRecord(ctx context.Context, sctx ses
 	return partitionedTableUpdateRecord(ctx, sctx, t.partitionedTable, h, currData, newData, touched, t.givenSetPartitions)
 }
 
+func hasGlobalIndex(tblInfo *model.TableInfo) bool {
+	for _, idx := range tblInfo.Indices {
+		if idx.Global {
+			return true
+		}
+	}
+	return false
+}
+
 func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context, t *partitionedTable, h kv.Handle, currData, newData []types.Datum, touched []bool, partitionSelection map[int64]struct{}) error {
 	from, err := t.locatePartition(ctx, currData)
 	if err != nil {
@@ -1634,20 +1672,34 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		// UpdateRecord should be side effect free, but there're two steps here.
-		// What would happen if step1 succeed but step2 meets error? It's hard
-		// to rollback.
-		// So this special order is chosen: add record first, errors such as
-		// 'Key Already Exists' will generally happen during step1, errors are
-		// unlikely to happen in step2.
-		err = t.GetPartition(from).RemoveRecord(ctx, h, currData)
-		if err != nil {
-			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
-			return errors.Trace(err)
+		if hasGlobalIndex(t.Meta()) {
+			// The entries of the global indexes are shared by the partitions, they must be removed
+			// before adding the record to the new partition, otherwise they are duplicated with themselves.
+			// The statement is rolled back as a whole if adding the record fails.
+			err = t.GetPartition(from).RemoveRecord(ctx, h, currData)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			_, err = t.GetPartition(to).AddRecord(ctx, newData)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		} else {
+			_, err = t.GetPartition(to).AddRecord(ctx, newData)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// UpdateRecord should be side effect free, but there're two steps here.
+			// What would happen if step1 succeed but step2 meets error? It's hard
+			// to rollback.
+			// So this special order is chosen: add record first, errors such as
+			// 'Key Already Exists' will generally happen during step1, errors are
+			// unlikely to happen in step2.
+			err = t.GetPartition(from).RemoveRecord(ctx, h, currData)
+			if err != nil {
+				logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
+				return errors.Trace(err)
+			}
 		}
 		newTo, newFrom := int64(0), int64(0)
 		if _, ok := t.reorganizePartitions[to]; ok {
@@ -211,6 +211,10 @@ func initTableIndices(t *TableCommon) error {
 			return table.ErrIndexStateCantNone.GenWithStackByArgs(idxInfo.Name)
 		}
 
+		if !isIndexInPartition(tblInfo, idxInfo, t.physicalTableID) {
+			continue
+		}
+
 		// Use partition ID for index, because TableCommon may be table or partition.
 		idx := NewIndex(t.physicalTableID, tblInfo, idxInfo)
 		t.indices = append(t.indices, idx)
@@ -218,6 +222,42 @@ func initTableIndices(t *TableCommon) error {
 	return nil
 }
 
+// isIndexInPartition checks whether the index is maintained in the partition.
+// When reorganizing partitions, the replaced indexes are only maintained in the partitions being dropped,
+// and the new indexes are only maintained in the partitions being added.
+func isIndexInPartition(tblInfo *model.TableInfo, idxInfo *model.IndexInfo, pid int64) bool {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return true
+	}
+	isNew, changed := pi.DDLChangedIndex[idxInfo.ID]
+	if !changed {
+		return true
+	}
+	defs := pi.AddingDefinitions
+	if isNew {
+		defs = pi.DroppingDefinitions
+	}
+	for _, def := range defs {
+		if def.ID == pid {
+			return false
+		}
+	}
+	return true
+}
+
+// checkExchangeReadOnly checks whether the table or the partition is being exchanged,
+// they cannot be written until the global indexes of the partitioned table are rebuilt.
+func checkExchangeReadOnly(tblInfo *model.TableInfo, physicalTableID int64) error {
+	if info := tblInfo.ExchangePartitionInfo; info != nil && info.ReadOnly {
+		return table.ErrUnsupportedOp.GenWithStack("table %s is being exchanged with a partition of a table with global indexes", tblInfo.Name.O)
+	}
+	if pi := tblInfo.GetPartitionInfo(); pi != nil && pi.DDLExchangePartitionID != 0 && pi.DDLExchangePartitionID == physicalTableID {
+		return table.ErrUnsupportedOp.GenWithStack("the partition of table %s is being exchanged with a table", tblInfo.Name.O)
+	}
+	return nil
+}
+
 func initTableCommonWithIndices(t *TableCommon, tblInfo *model.TableInfo, physicalTableID int64, cols []*table.Column, allocs autoid.Allocators, constraints []*table.Constraint) error {
 	initTableCommon(t, tblInfo, physicalTableID, cols, allocs, constraints)
 	return initTableIndices(t)
@@ -413,6 +453,9 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 	if err != nil {
 		return err
 	}
+	if err = checkExchangeReadOnly(t.meta, t.physicalTableID); err != nil {
+		return err
+	}
 
 	memBuffer := txn.GetMemBuffer()
 	sh := memBuffer.Staging()
@@ -579,6 +622,14 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 	}
 
 	memBuffer.Release(sh)
+	if len(t.meta.MaterializedViewLogs) > 0 {
+		if err = t.writeMaterializedViewLogs(sctx, oldData, -1, touched); err != nil {
+			return err
+		}
+		if err = t.writeMaterializedViewLogs(sctx, newData, 1, touched); err != nil {
+			return err
+		}
+	}
 	if shouldWriteBinlog(sctx, t.meta) {
 		if !t.meta.PKIsHandle && !t.meta.IsCommonHandle {
 			binlogColIDs = append(binlogColIDs, model.ExtraHandleID)
@@ -800,6 +851,10 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 		return nil, err
 	}
 
+	if err = checkExchangeReadOnly(t.meta, t.physicalTableID); err != nil {
+		return nil, err
+	}
+
 	var opt table.AddRecordOpt
 	for _, fn := range opts {
 		fn.ApplyOn(&opt)
@@ -1075,6 +1130,12 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 
 	memBuffer.Release(sh)
 
+	if len(t.meta.MaterializedViewLogs) > 0 {
+		if err = t.writeMaterializedViewLogs(sctx, r, 1, nil); err != nil {
+			return nil, err
+		}
+	}
+
 	if shouldWriteBinlog(sctx, t.meta) {
 		// For insert, TiDB and Binlog can use same row and schema.
 		binlogRow = row
@@ -1293,6 +1354,9 @@ func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []type
 	if err != nil {
 		return err
 	}
+	if err = checkExchangeReadOnly(t.meta, t.physicalTableID); err != nil {
+		return err
+	}
 
 	memBuffer := txn.GetMemBuffer()
 	sh := memBuffer.Staging()
@@ -1344,6 +1408,12 @@ func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []type
 	}
 	memBuffer.Release(sh)
 
+	if len(t.meta.MaterializedViewLogs) > 0 {
+		if err = t.writeMaterializedViewLogs(ctx, r, -1, nil); err != nil {
+			return err
+		}
+	}
+
 	if shouldWriteBinlog(ctx, t.meta) {
 		cols := t.Cols()
 		colIDs := make([]int64, 0, len(cols)+1)
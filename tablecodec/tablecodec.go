@@ -1653,6 +1653,63 @@ func DecodeHandleInUniqueIndexValue(data []byte, isCommonHandle bool) (kv.Handle
 	return h, nil
 }
 
+// DecodePartitionIDInIndexValue decodes the partition ID in the value of a global index.
+// It returns false if there is no partition ID in the value.
+func DecodePartitionIDInIndexValue(data []byte) (int64, bool, error) {
+	if len(data) <= MaxOldEncodeValueLen {
+		return 0, false, nil
+	}
+	var segs IndexValueSegments
+	if getIndexVersion(data) == 1 {
+		segs = SplitIndexValueForClusteredIndexVersion1(data)
+	} else {
+		segs = SplitIndexValue(data)
+	}
+	if len(segs.PartitionID) == 0 {
+		return 0, false, nil
+	}
+	_, pid, err := codec.DecodeInt(segs.PartitionID)
+	if err != nil {
+		return 0, false, errors.Trace(err)
+	}
+	return pid, true, nil
+}
+
+// DecodeHandleInGlobalIndexValue decodes the handle in the value of a unique global index.
+// The handle is wrapped in a kv.PartitionHandle with the partition ID in the value.
+func DecodeHandleInGlobalIndexValue(data []byte, isCommonHandle bool) (kv.Handle, error) {
+	h, err := DecodeHandleInUniqueIndexValue(data, isCommonHandle)
+	if err != nil {
+		return nil, err
+	}
+	pid, ok, err := DecodePartitionIDInIndexValue(data)
+	if err != nil || !ok {
+		return h, err
+	}
+	return kv.NewPartitionHandle(pid, h), nil
+}
+
+// RewritePartitionIDInIndexValue returns a copy of the value of a global index with the partition ID replaced by pid.
+// It returns false if there is no partition ID in the value.
+func RewritePartitionIDInIndexValue(data []byte, pid int64) ([]byte, bool) {
+	if len(data) <= MaxOldEncodeValueLen {
+		return nil, false
+	}
+	newData := make([]byte, len(data))
+	copy(newData, data)
+	var segs IndexValueSegments
+	if getIndexVersion(newData) == 1 {
+		segs = SplitIndexValueForClusteredIndexVersion1(newData)
+	} else {
+		segs = SplitIndexValue(newData)
+	}
+	if len(segs.PartitionID) == 0 {
+		return nil, false
+	}
+	binary.BigEndian.PutUint64(segs.PartitionID, codec.EncodeIntToCmpUint(pid))
+	return newData, true
+}
+
 func encodePartitionID(idxVal []byte, partitionID int64) []byte {
 	idxVal = append(idxVal, PartitionIDFlag)
 	idxVal = codec.EncodeInt(idxVal, partitionID)
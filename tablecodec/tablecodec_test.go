@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -597,6 +598,44 @@ func TestUntouchedIndexKValue(t *testing.T) {
 	require.False(t, IsUntouchedIndexKValue(untouchedIndexKey, tmpIdxVal))
 }
 
+func TestGlobalIndexValue(t *testing.T) {
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	tblInfo := &model.TableInfo{}
+	idxInfo := &model.IndexInfo{Unique: true, Global: true}
+	val, err := GenIndexValuePortal(sc, tblInfo, idxInfo, false, true, false, nil, kv.IntHandle(10), 100, nil)
+	require.NoError(t, err)
+	pid, ok, err := DecodePartitionIDInIndexValue(val)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(100), pid)
+	h, err := DecodeHandleInGlobalIndexValue(val, false)
+	require.NoError(t, err)
+	require.Equal(t, kv.NewPartitionHandle(100, kv.IntHandle(10)), h)
+
+	newVal, ok := RewritePartitionIDInIndexValue(val, 200)
+	require.True(t, ok)
+	pid, ok, err = DecodePartitionIDInIndexValue(newVal)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, int64(200), pid)
+	h, err = DecodeHandleInGlobalIndexValue(newVal, false)
+	require.NoError(t, err)
+	require.Equal(t, kv.NewPartitionHandle(200, kv.IntHandle(10)), h)
+	// The original value is not changed.
+	pid, _, err = DecodePartitionIDInIndexValue(val)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), pid)
+
+	// The value of a local index has no partition ID.
+	val, err = GenIndexValuePortal(sc, tblInfo, &model.IndexInfo{Unique: true}, false, true, false, nil, kv.IntHandle(10), 0, nil)
+	require.NoError(t, err)
+	_, ok, err = DecodePartitionIDInIndexValue(val)
+	require.NoError(t, err)
+	require.False(t, ok)
+	_, ok = RewritePartitionIDInIndexValue(val, 200)
+	require.False(t, ok)
+}
+
 func TestTempIndexKey(t *testing.T) {
 	values := []types.Datum{types.NewIntDatum(1), types.NewBytesDatum([]byte("abc")), types.NewFloat64Datum(5.5)}
 	encodedValue, err := codec.EncodeKey(&stmtctx.StatementContext{TimeZone: time.UTC}, nil, values...)
@@ -19,6 +19,7 @@ import (
 	"flag"
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
 	"runtime"
 	"strconv"
@@ -38,6 +39,7 @@ import (
 	"github.com/pingcap/tidb/executor/mppcoordmanager"
 	"github.com/pingcap/tidb/extension"
 	_ "github.com/pingcap/tidb/extension/_import"
+	_ "github.com/pingcap/tidb/extension/audit"
 	"github.com/pingcap/tidb/keyspace"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
@@ -78,6 +80,7 @@ import (
 	"github.com/pingcap/tidb/util/systimemon"
 	"github.com/pingcap/tidb/util/tiflashcompute"
 	"github.com/pingcap/tidb/util/topsql"
+	"github.com/pingcap/tidb/util/tracing/otlp"
 	"github.com/pingcap/tidb/util/versioninfo"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
@@ -249,6 +252,7 @@ func main() {
 	signal.SetupSignalHandler(func() {
 		svr.Close()
 		cleanup(svr, storage, dom)
+		closeTracing()
 		cpuprofile.StopCPUProfiler()
 		resourcemanager.InstanceResourceManager.Stop()
 		executor.Stop()
@@ -844,6 +848,40 @@ func setupTracing() {
 		log.Fatal("setup jaeger tracer failed", zap.String("error message", err.Error()))
 	}
 	opentracing.SetGlobalTracer(tracer)
+
+	otelCfg := cfg.OpenTelemetry
+	if !otelCfg.Enable {
+		return
+	}
+	exporter, err := otlp.NewExporter(otlp.ExporterConfig{
+		Protocol: otelCfg.Exporter,
+		Endpoint: otelCfg.Endpoint,
+		Insecure: otelCfg.Insecure,
+		Headers:  otelCfg.Headers,
+		FilePath: otelCfg.FilePath,
+	})
+	if err != nil {
+		log.Fatal("setup OTLP exporter failed", zap.Error(err))
+	}
+	otlpTracer := otlp.NewTracer(exporter, otlp.TracerConfig{
+		SampleRate: otelCfg.SampleRate,
+		BatchSize:  otelCfg.BatchSize,
+		QueueSize:  otelCfg.QueueSize,
+		ResourceAttributes: map[string]string{
+			"service.name":        "tidb",
+			"service.version":     mysql.TiDBReleaseVersion,
+			"service.instance.id": net.JoinHostPort(cfg.AdvertiseAddress, strconv.Itoa(int(cfg.Port))),
+		},
+	})
+	otlp.SetGlobalTracer(otlpTracer)
+	opentracing.SetGlobalTracer(otlpTracer)
+}
+
+func closeTracing() {
+	if tracer := otlp.GlobalTracer(); tracer != nil {
+		otlp.SetGlobalTracer(nil)
+		tracer.Close()
+	}
 }
 
 func closeDomainAndStorage(storage kv.Storage, dom *domain.Domain) {
@@ -897,6 +935,11 @@ func setupStmtSummary() {
 			FileMaxSize:    instanceCfg.StmtSummaryFileMaxSize,
 			FileMaxDays:    instanceCfg.StmtSummaryFileMaxDays,
 			FileMaxBackups: instanceCfg.StmtSummaryFileMaxBackups,
+
+			EnableCompaction:   instanceCfg.StmtSummaryEnableCompaction,
+			CompactionInterval: instanceCfg.StmtSummaryCompactionInterval,
+			HistoryMaxDays:     instanceCfg.StmtSummaryHistoryMaxDays,
+			HistoryMaxSize:     instanceCfg.StmtSummaryHistoryMaxSize,
 		})
 		if err != nil {
 			logutil.BgLogger().Error("failed to setup statements summary", zap.Error(err))
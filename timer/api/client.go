@@ -84,6 +84,13 @@ func WithSetSchedExpr(tp SchedPolicyType, expr string) UpdateTimerOption {
 	}
 }
 
+// WithSetTimeZone indicates to set the timer's time zone.
+func WithSetTimeZone(name string) UpdateTimerOption {
+	return func(update *TimerUpdate) {
+		update.TimeZone.Set(name)
+	}
+}
+
 // WithSetWatermark indicates to set the timer's watermark.
 func WithSetWatermark(watermark time.Time) UpdateTimerOption {
 	return func(update *TimerUpdate) {
@@ -105,6 +112,13 @@ func WithSetTags(tags []string) UpdateTimerOption {
 	}
 }
 
+// WithSetData indicates to set the timer's data.
+func WithSetData(data []byte) UpdateTimerOption {
+	return func(update *TimerUpdate) {
+		update.Data.Set(data)
+	}
+}
+
 // TimerClient is an interface exposed to user to manage timers.
 type TimerClient interface {
 	// GetDefaultNamespace returns the default namespace of this client.
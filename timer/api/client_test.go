@@ -110,6 +110,15 @@ func TestUpdateTimerOption(t *testing.T) {
 	require.Equal(t, "1h", expr)
 	require.Equal(t, []string{"Enable", "SchedPolicyType", "SchedPolicyExpr"}, update.FieldsSet())
 
+	// test 'TimeZone' field
+	require.False(t, update.TimeZone.Present())
+
+	WithSetTimeZone("Asia/Shanghai")(&update)
+	tz, ok := update.TimeZone.Get()
+	require.True(t, ok)
+	require.Equal(t, "Asia/Shanghai", tz)
+	require.Equal(t, []string{"Enable", "SchedPolicyType", "SchedPolicyExpr", "TimeZone"}, update.FieldsSet())
+
 	// test 'Watermark' field
 	require.False(t, update.Watermark.Present())
 
@@ -117,7 +126,7 @@ func TestUpdateTimerOption(t *testing.T) {
 	watermark, ok := update.Watermark.Get()
 	require.True(t, ok)
 	require.Equal(t, time.Unix(1234, 5678), watermark)
-	require.Equal(t, []string{"Enable", "SchedPolicyType", "SchedPolicyExpr", "Watermark"}, update.FieldsSet())
+	require.Equal(t, []string{"Enable", "SchedPolicyType", "SchedPolicyExpr", "TimeZone", "Watermark"}, update.FieldsSet())
 
 	// test 'SummaryData' field
 	require.False(t, update.SummaryData.Present())
@@ -126,7 +135,7 @@ func TestUpdateTimerOption(t *testing.T) {
 	summary, ok := update.SummaryData.Get()
 	require.True(t, ok)
 	require.Equal(t, []byte("hello"), summary)
-	require.Equal(t, []string{"Enable", "SchedPolicyType", "SchedPolicyExpr", "Watermark", "SummaryData"}, update.FieldsSet())
+	require.Equal(t, []string{"Enable", "SchedPolicyType", "SchedPolicyExpr", "TimeZone", "Watermark", "SummaryData"}, update.FieldsSet())
 
 	// test 'Tags' field
 	require.False(t, update.Tags.Present())
@@ -138,7 +147,15 @@ func TestUpdateTimerOption(t *testing.T) {
 	tags, ok = update.Tags.Get()
 	require.True(t, ok)
 	require.Equal(t, []string{"l1", "l2"}, tags)
-	require.Equal(t, []string{"Tags", "Enable", "SchedPolicyType", "SchedPolicyExpr", "Watermark", "SummaryData"}, update.FieldsSet())
+	require.Equal(t, []string{"Tags", "Enable", "SchedPolicyType", "SchedPolicyExpr", "TimeZone", "Watermark", "SummaryData"}, update.FieldsSet())
+
+	// test 'Data' field
+	require.False(t, update.Data.Present())
+	WithSetData([]byte("data1"))(&update)
+	data, ok := update.Data.Get()
+	require.True(t, ok)
+	require.Equal(t, []byte("data1"), data)
+	require.Equal(t, []string{"Tags", "Data", "Enable", "SchedPolicyType", "SchedPolicyExpr", "TimeZone", "Watermark", "SummaryData"}, update.FieldsSet())
 }
 
 func TestDefaultClient(t *testing.T) {
@@ -65,3 +65,98 @@ func TestIntervalPolicy(t *testing.T) {
 		require.Equal(t, watermark2.Add(c.interval), tm)
 	}
 }
+
+func TestCronPolicy(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	cases := []struct {
+		expr      string
+		watermark time.Time
+		next      time.Time
+	}{
+		{
+			expr:      "0 2 * * 1-5",
+			watermark: time.Date(2023, 8, 4, 3, 0, 0, 0, loc),
+			next:      time.Date(2023, 8, 7, 2, 0, 0, 0, loc),
+		},
+		{
+			expr:      "0 2 * * MON-FRI",
+			watermark: time.Date(2023, 8, 4, 1, 0, 0, 0, loc),
+			next:      time.Date(2023, 8, 4, 2, 0, 0, 0, loc),
+		},
+		{
+			// 6 fields with seconds
+			expr:      "15 0 2 * * *",
+			watermark: time.Date(2023, 8, 4, 3, 0, 0, 0, loc),
+			next:      time.Date(2023, 8, 5, 2, 0, 15, 0, loc),
+		},
+		{
+			expr:      "@daily",
+			watermark: time.Date(2023, 8, 4, 3, 0, 0, 0, loc),
+			next:      time.Date(2023, 8, 5, 0, 0, 0, 0, loc),
+		},
+		{
+			// 02:30 is skipped on 2023-03-12 and should be triggered when the clock jumps
+			expr:      "30 2 * * *",
+			watermark: time.Date(2023, 3, 11, 3, 0, 0, 0, loc),
+			next:      time.Date(2023, 3, 12, 3, 0, 0, 0, loc),
+		},
+		{
+			expr:      "30 2 * * *",
+			watermark: time.Date(2023, 3, 12, 3, 0, 5, 0, loc),
+			next:      time.Date(2023, 3, 13, 2, 30, 0, 0, loc),
+		},
+		{
+			expr:      "0 30 2 12 3 *",
+			watermark: time.Date(2022, 7, 1, 0, 0, 0, 0, loc),
+			next:      time.Date(2023, 3, 12, 3, 0, 0, 0, loc),
+		},
+		{
+			// 01:30 is repeated on 2023-11-05 and should only be triggered once
+			expr:      "30 1 * * *",
+			watermark: time.Date(2023, 11, 5, 0, 0, 0, 0, loc),
+			next:      time.Date(2023, 11, 5, 1, 30, 0, 0, loc),
+		},
+		{
+			expr:      "30 1 * * *",
+			watermark: time.Date(2023, 11, 5, 1, 30, 0, 0, loc),
+			next:      time.Date(2023, 11, 6, 1, 30, 0, 0, loc),
+		},
+		{
+			expr:      "*/20 1 * * *",
+			watermark: time.Date(2023, 11, 5, 1, 50, 0, 0, loc),
+			next:      time.Date(2023, 11, 6, 1, 0, 0, 0, loc),
+		},
+		{
+			// the events are scheduled by the elapsed time when the hour field is "*"
+			expr:      "*/20 * * * *",
+			watermark: time.Date(2023, 11, 5, 1, 50, 0, 0, loc),
+			next:      time.Date(2023, 11, 5, 1, 50, 0, 0, loc).Add(10 * time.Minute),
+		},
+	}
+
+	for _, c := range cases {
+		p, err := NewCronPolicy(c.expr)
+		require.NoError(t, err, c.expr)
+		tm, ok := p.NextEventTime(c.watermark)
+		require.True(t, ok, c.expr)
+		require.Equal(t, c.next.Unix(), tm.Unix(), "expr: %s, watermark: %s, next: %s", c.expr, c.watermark, tm)
+	}
+
+	// no more event
+	p, err := NewCronPolicy("0 0 30 2 *")
+	require.NoError(t, err)
+	_, ok := p.NextEventTime(time.Date(2023, 8, 4, 3, 0, 0, 0, loc))
+	require.False(t, ok)
+
+	for _, expr := range []string{"", "0 2 * *", "61 * * * *", "1 2 3 4 5 6 7"} {
+		_, err = NewCronPolicy(expr)
+		require.ErrorContains(t, err, fmt.Sprintf("invalid cron expr '%s'", expr))
+	}
+
+	_, err = NewCronPolicy("@every 1h")
+	require.EqualError(t, err, "invalid cron expr '@every 1h': use the INTERVAL policy instead")
+	_, err = NewCronPolicy("CRON_TZ=UTC 0 2 * * *")
+	require.EqualError(t, err, "invalid cron expr 'CRON_TZ=UTC 0 2 * * *': time zone should be specified by the timer instead of the expr")
+}
@@ -166,12 +166,16 @@ func (c *TimerCond) Clear() {
 type TimerUpdate struct {
 	// Tags indicates to set all tags for a timer.
 	Tags OptionalVal[[]string]
+	// Data indicates to set the timer's `Data` field.
+	Data OptionalVal[[]byte]
 	// Enable indicates to set the timer's `Enable` field.
 	Enable OptionalVal[bool]
 	// SchedPolicyType indicates to set the timer's `SchedPolicyType` field.
 	SchedPolicyType OptionalVal[SchedPolicyType]
 	// SchedPolicyExpr indicates to set the timer's `SchedPolicyExpr` field.
 	SchedPolicyExpr OptionalVal[string]
+	// TimeZone indicates to set the timer's `TimeZone` field.
+	TimeZone OptionalVal[string]
 	// ManualRequest indicates to set the timer's manual request.
 	ManualRequest OptionalVal[ManualRequest]
 	// EventStatus indicates the event status.
@@ -212,6 +216,10 @@ func (u *TimerUpdate) Apply(record *TimerRecord) (*TimerRecord, error) {
 		record.Tags = v
 	}
 
+	if v, ok := u.Data.Get(); ok {
+		record.Data = v
+	}
+
 	if v, ok := u.Enable.Get(); ok {
 		record.Enable = v
 	}
@@ -224,6 +232,10 @@ func (u *TimerUpdate) Apply(record *TimerRecord) (*TimerRecord, error) {
 		record.SchedPolicyExpr = v
 	}
 
+	if v, ok := u.TimeZone.Get(); ok {
+		record.TimeZone = v
+	}
+
 	if v, ok := u.ManualRequest.Get(); ok {
 		record.ManualRequest = v
 	}
@@ -246,6 +246,7 @@ func TestTimerUpdate(t *testing.T) {
 		Enable:          NewOptionalVal(true),
 		SchedPolicyType: NewOptionalVal(SchedEventInterval),
 		SchedPolicyExpr: NewOptionalVal("5h"),
+		TimeZone:        NewOptionalVal("Asia/Shanghai"),
 		Watermark:       NewOptionalVal(now),
 		SummaryData:     NewOptionalVal([]byte("summarydata1")),
 		EventStatus:     NewOptionalVal(SchedEventTrigger),
@@ -253,6 +254,7 @@ func TestTimerUpdate(t *testing.T) {
 		EventData:       NewOptionalVal([]byte("eventdata1")),
 		EventStart:      NewOptionalVal(now.Add(time.Second)),
 		Tags:            NewOptionalVal([]string{"l1", "l2"}),
+		Data:            NewOptionalVal([]byte("timerdata1")),
 		ManualRequest: NewOptionalVal(ManualRequest{
 			ManualRequestID:   "req1",
 			ManualRequestTime: time.Unix(123, 0),
@@ -272,6 +274,7 @@ func TestTimerUpdate(t *testing.T) {
 	require.True(t, record.Enable)
 	require.Equal(t, SchedEventInterval, record.SchedPolicyType)
 	require.Equal(t, "5h", record.SchedPolicyExpr)
+	require.Equal(t, "Asia/Shanghai", record.TimeZone)
 	require.Equal(t, now, record.Watermark)
 	require.Equal(t, []byte("summarydata1"), record.SummaryData)
 	require.Equal(t, SchedEventTrigger, record.EventStatus)
@@ -279,6 +282,7 @@ func TestTimerUpdate(t *testing.T) {
 	require.Equal(t, []byte("eventdata1"), record.EventData)
 	require.Equal(t, now.Add(time.Second), record.EventStart)
 	require.Equal(t, []string{"l1", "l2"}, record.Tags)
+	require.Equal(t, []byte("timerdata1"), record.Data)
 	require.Equal(t, ManualRequest{
 		ManualRequestID:   "req1",
 		ManualRequestTime: time.Unix(123, 0),
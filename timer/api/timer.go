@@ -15,10 +15,13 @@
 package api
 
 import (
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/duration"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/robfig/cron/v3"
 )
 
 // SchedPolicyType is the type of the event schedule policy.
@@ -27,6 +30,8 @@ type SchedPolicyType string
 const (
 	// SchedEventInterval indicates to schedule events every fixed interval.
 	SchedEventInterval SchedPolicyType = "INTERVAL"
+	// SchedEventCron indicates to schedule events by a cron expression.
+	SchedEventCron SchedPolicyType = "CRON"
 )
 
 // SchedEventPolicy is an interface to tell the runtime how to schedule a timer's events.
@@ -64,6 +69,101 @@ func (p *SchedIntervalPolicy) NextEventTime(watermark time.Time) (time.Time, boo
 	return watermark.Add(p.interval), true
 }
 
+// cronParser accepts the standard 5-field cron expressions and the 6-field ones with a leading seconds field.
+// Descriptors such as "@daily" or "@weekly" are also supported.
+var cronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// cronStarBit is set in a field of cron.SpecSchedule when the field is "*".
+const cronStarBit = 1 << 63
+
+// CronPolicy implements SchedEventPolicy, it is the policy of type `SchedEventCron`.
+// The cron expression is evaluated in the location of the watermark passed to `NextEventTime`.
+// Like the traditional cron, if the hour field of the expression is not "*", the DST transitions are handled as below:
+//   - A time skipped by a forward transition, for example, 02:30 when the clock jumps from 02:00 to 03:00,
+//     is scheduled at the time of the transition.
+//   - A time repeated by a backward transition is only scheduled once.
+//
+// Otherwise, the events are scheduled by the elapsed time and are not affected by the transitions.
+type CronPolicy struct {
+	expr     string
+	schedule *cron.SpecSchedule
+}
+
+// NewCronPolicy creates a new CronPolicy.
+func NewCronPolicy(expr string) (*CronPolicy, error) {
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, errors.Errorf("invalid cron expr '%s': time zone should be specified by the timer instead of the expr", expr)
+	}
+
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid cron expr '%s'", expr)
+	}
+
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return nil, errors.Errorf("invalid cron expr '%s': use the INTERVAL policy instead", expr)
+	}
+
+	return &CronPolicy{
+		expr:     expr,
+		schedule: spec,
+	}, nil
+}
+
+// NextEventTime returns the next time of the timer event.
+func (p *CronPolicy) NextEventTime(watermark time.Time) (time.Time, bool) {
+	next := p.schedule.Next(watermark)
+	if next.IsZero() {
+		return next, false
+	}
+
+	if p.schedule.Hour&cronStarBit != 0 {
+		return next, true
+	}
+
+	for isRepeatedWallClock(next) {
+		if next = p.schedule.Next(next); next.IsZero() {
+			return next, false
+		}
+	}
+
+	for t := watermark; ; {
+		_, transition := t.ZoneBounds()
+		if transition.IsZero() || !transition.Before(next) {
+			break
+		}
+
+		_, offsetBefore := t.Zone()
+		_, offsetAfter := transition.Zone()
+		if offsetAfter > offsetBefore {
+			// The wall clocks in [transition, transition + gap) before the transition are skipped.
+			gap := time.Duration(offsetAfter-offsetBefore) * time.Second
+			before := transition.Add(-time.Second).In(time.FixedZone("", offsetBefore))
+			if tm := p.schedule.Next(before); !tm.IsZero() && tm.Before(transition.Add(gap)) {
+				return transition, true
+			}
+		}
+		t = transition
+	}
+
+	return next, true
+}
+
+// isRepeatedWallClock returns whether the wall clock of `t` has occurred before because of a backward DST transition.
+func isRepeatedWallClock(t time.Time) bool {
+	transition, _ := t.ZoneBounds()
+	if transition.IsZero() {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, offsetBefore := transition.Add(-time.Second).Zone()
+	return offsetBefore > offset && t.Before(transition.Add(time.Duration(offsetBefore-offset)*time.Second))
+}
+
 // ManualRequest is the request info to trigger timer manually.
 type ManualRequest struct {
 	// ManualRequestID is the id of manual request.
@@ -115,6 +215,9 @@ type TimerSpec struct {
 	SchedPolicyType SchedPolicyType
 	// SchedPolicyExpr is the expression of event schedule policy with the type specified by SchedPolicyType.
 	SchedPolicyExpr string
+	// TimeZone is the time zone name used to evaluate the schedule policy, for example, "Asia/Shanghai" or "+08:00".
+	// If it is empty, TiDB's system time zone is used.
+	TimeZone string
 	// HookClass is the class of the hook.
 	HookClass string
 	// Watermark indicates the progress the timer's event schedule.
@@ -148,9 +251,26 @@ func (t *TimerSpec) Validate() error {
 		return errors.Wrap(err, "schedule event configuration is not valid")
 	}
 
+	if _, err := t.Location(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Location returns the location to evaluate the timer's schedule policy.
+func (t *TimerSpec) Location() (*time.Location, error) {
+	if t.TimeZone == "" {
+		return timeutil.SystemLocation(), nil
+	}
+
+	loc, err := timeutil.ParseTimeZone(t.TimeZone)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid time zone '%s'", t.TimeZone)
+	}
+	return loc, nil
+}
+
 // CreateSchedEventPolicy creates a SchedEventPolicy according to `SchedPolicyType` and `SchedPolicyExpr`.
 func (t *TimerSpec) CreateSchedEventPolicy() (SchedEventPolicy, error) {
 	return CreateSchedEventPolicy(t.SchedPolicyType, t.SchedPolicyExpr)
@@ -161,6 +281,8 @@ func CreateSchedEventPolicy(tp SchedPolicyType, expr string) (SchedEventPolicy,
 	switch tp {
 	case SchedEventInterval:
 		return NewSchedIntervalPolicy(expr)
+	case SchedEventCron:
+		return NewCronPolicy(expr)
 	default:
 		return nil, errors.Errorf("invalid schedule event type: '%s'", tp)
 	}
@@ -211,3 +333,31 @@ func (r *TimerRecord) Clone() *TimerRecord {
 	cloned.TimerSpec = *r.TimerSpec.Clone()
 	return &cloned
 }
+
+// NextEventTime returns the time to schedule the next event of the timer according to its watermark.
+// The second return value is false if the timer is disabled or there is no more event to schedule.
+func (r *TimerRecord) NextEventTime() (time.Time, bool, error) {
+	if !r.Enable {
+		return time.Time{}, false, nil
+	}
+
+	policy, err := r.CreateSchedEventPolicy()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	watermark := r.Watermark
+	if !watermark.IsZero() {
+		loc, err := r.Location()
+		if err != nil {
+			return time.Time{}, false, err
+		}
+
+		if watermark.Location() != loc {
+			watermark = watermark.In(loc)
+		}
+	}
+
+	tm, ok := policy.NextEventTime(watermark)
+	return tm, ok, nil
+}
@@ -16,6 +16,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -45,4 +46,68 @@ func TestTimerValidate(t *testing.T) {
 
 	record.SchedPolicyExpr = "1h"
 	require.Nil(t, record.Validate())
+
+	record.SchedPolicyType = SchedEventCron
+	record.SchedPolicyExpr = "0 2 * *"
+	err = record.Validate()
+	require.EqualError(t, err, "schedule event configuration is not valid: invalid cron expr '0 2 * *': expected 5 to 6 fields, found 4: [0 2 * *]")
+
+	record.SchedPolicyExpr = "0 2 * * 1-5"
+	require.Nil(t, record.Validate())
+
+	record.TimeZone = "Invalid/Zone"
+	err = record.Validate()
+	require.EqualError(t, err, "invalid time zone 'Invalid/Zone': unknown or incorrect time zone: 'Invalid/Zone'")
+
+	record.TimeZone = "+08:00"
+	require.Nil(t, record.Validate())
+
+	record.TimeZone = "Asia/Shanghai"
+	require.Nil(t, record.Validate())
+}
+
+func TestTimerNextEventTime(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	record := &TimerRecord{
+		TimerSpec: TimerSpec{
+			Namespace:       "n1",
+			Key:             "k1",
+			SchedPolicyType: SchedEventCron,
+			SchedPolicyExpr: "0 2 * * 1-5",
+			TimeZone:        "Asia/Shanghai",
+			Watermark:       time.Date(2023, 8, 4, 3, 0, 0, 0, loc).UTC(),
+		},
+	}
+
+	// disabled timer has no next event
+	_, ok, err := record.NextEventTime()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// the cron expression should be evaluated in the timer's time zone
+	record.Enable = true
+	tm, ok, err := record.NextEventTime()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2023, 8, 7, 2, 0, 0, 0, loc).Unix(), tm.Unix())
+
+	record.TimeZone = "+00:00"
+	tm, ok, err = record.NextEventTime()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2023, 8, 4, 2, 0, 0, 0, time.UTC).Unix(), tm.Unix())
+
+	// interval policy is not affected by the time zone
+	record.SchedPolicyType = SchedEventInterval
+	record.SchedPolicyExpr = "1h"
+	tm, ok, err = record.NextEventTime()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, record.Watermark.Add(time.Hour).Unix(), tm.Unix())
+
+	record.TimeZone = "Invalid/Zone"
+	_, _, err = record.NextEventTime()
+	require.EqualError(t, err, "invalid time zone 'Invalid/Zone': unknown or incorrect time zone: 'Invalid/Zone'")
 }
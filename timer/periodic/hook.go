@@ -0,0 +1,135 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const defaultRetryCloseEventInterval = 10 * time.Second
+
+type jobHook struct {
+	cli                     timerapi.TimerClient
+	name                    string
+	job                     Job
+	ctx                     context.Context
+	cancel                  func()
+	wg                      sync.WaitGroup
+	nowFunc                 func() time.Time
+	retryCloseEventInterval time.Duration
+
+	mu sync.Mutex
+	// running contains the ids of the events which are running the job.
+	running map[string]struct{}
+}
+
+func newJobHook(cli timerapi.TimerClient, name string, job Job) *jobHook {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &jobHook{
+		cli:                     cli,
+		name:                    name,
+		job:                     job,
+		ctx:                     ctx,
+		cancel:                  cancel,
+		nowFunc:                 time.Now,
+		retryCloseEventInterval: defaultRetryCloseEventInterval,
+		running:                 make(map[string]struct{}),
+	}
+}
+
+func (h *jobHook) Start() {}
+
+func (h *jobHook) Stop() {
+	h.cancel()
+	h.wg.Wait()
+}
+
+func (h *jobHook) OnPreSchedEvent(_ context.Context, _ timerapi.TimerShedEvent) (r timerapi.PreSchedEventResult, err error) {
+	return
+}
+
+func (h *jobHook) OnSchedEvent(_ context.Context, event timerapi.TimerShedEvent) error {
+	timer := event.Timer()
+	eventID := event.EventID()
+	logger := logutil.BgLogger().With(
+		zap.String("job", h.name),
+		zap.String("key", timer.Key),
+		zap.String("eventID", eventID),
+		zap.Time("eventStart", timer.EventStart),
+	)
+
+	if err := h.ctx.Err(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.running[eventID]; ok {
+		return nil
+	}
+
+	h.running[eventID] = struct{}{}
+	h.wg.Add(1)
+	go h.executeEvent(logger, timer.ID, eventID, timer.EventStart, timer.SummaryData)
+	return nil
+}
+
+func (h *jobHook) executeEvent(logger *zap.Logger, timerID, eventID string, eventStart time.Time, summary []byte) {
+	defer func() {
+		h.mu.Lock()
+		delete(h.running, eventID)
+		h.mu.Unlock()
+		h.wg.Done()
+	}()
+
+	logger.Info("timer triggered to run periodic job")
+	newSummary, err := h.job(h.ctx, h.nowFunc(), summary)
+	if err != nil {
+		if h.ctx.Err() != nil {
+			// leave the event in trigger state, it will be executed again when the timer is triggered again.
+			logger.Info("stop running periodic job because of context cancelled", zap.Error(err))
+			return
+		}
+		logger.Warn("failed to run periodic job", zap.Error(err))
+	}
+
+	opts := []timerapi.UpdateTimerOption{timerapi.WithSetWatermark(eventStart)}
+	if newSummary != nil {
+		opts = append(opts, timerapi.WithSetSummaryData(newSummary))
+	}
+	ticker := time.NewTicker(h.retryCloseEventInterval)
+	defer ticker.Stop()
+	for {
+		err := h.cli.CloseTimerEvent(h.ctx, timerID, eventID, opts...)
+		if err == nil || errors.ErrorEqual(err, timerapi.ErrTimerNotExist) || errors.ErrorEqual(err, timerapi.ErrEventIDNotMatch) {
+			logger.Info("periodic job timer event finished", zap.Error(err))
+			return
+		}
+
+		logger.Error("CloseTimerEvent error", zap.Error(err))
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/stretchr/testify/require"
+)
+
+var testTimerConfig = TimerConfig{
+	Name:      "test",
+	HookClass: "tidb.test",
+	Key:       "/tidb/test/job",
+	Interval:  "10m",
+}
+
+type mockTimerSchedEvent struct {
+	eventID string
+	timer   *timerapi.TimerRecord
+}
+
+func (e *mockTimerSchedEvent) EventID() string {
+	return e.eventID
+}
+
+func (e *mockTimerSchedEvent) Timer() *timerapi.TimerRecord {
+	return e.timer
+}
+
+func triggerTestTimer(t *testing.T, store *timerapi.TimerStore, timerID string, eventID string) *timerapi.TimerRecord {
+	err := store.Update(context.TODO(), timerID, &timerapi.TimerUpdate{
+		EventStatus: timerapi.NewOptionalVal(timerapi.SchedEventTrigger),
+		EventID:     timerapi.NewOptionalVal(eventID),
+		EventStart:  timerapi.NewOptionalVal(time.Unix(time.Now().Unix()-2, 0)),
+	})
+	require.NoError(t, err)
+	timer, err := store.GetByID(context.TODO(), timerID)
+	require.NoError(t, err)
+	return timer
+}
+
+func waitEventClosed(t *testing.T, cli timerapi.TimerClient, timerID string) *timerapi.TimerRecord {
+	start := time.Now()
+	for {
+		if time.Since(start) > time.Minute {
+			require.FailNow(t, "timeout")
+		}
+
+		tm, err := cli.GetTimerByID(context.TODO(), timerID)
+		require.NoError(t, err)
+		if tm.EventStatus == timerapi.SchedEventIdle {
+			return tm
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEnsureTimer(t *testing.T) {
+	store := timerapi.NewMemoryTimerStore()
+	defer store.Close()
+	cli := timerapi.NewDefaultTimerClient(store)
+
+	require.NoError(t, ensureTimer(context.TODO(), cli, testTimerConfig))
+	timer, err := cli.GetTimerByKey(context.TODO(), testTimerConfig.Key)
+	require.NoError(t, err)
+	require.Equal(t, testTimerConfig.HookClass, timer.HookClass)
+	require.Equal(t, timerapi.SchedEventInterval, timer.SchedPolicyType)
+	require.Equal(t, testTimerConfig.Interval, timer.SchedPolicyExpr)
+	require.True(t, timer.Enable)
+	require.True(t, timer.Watermark.IsZero())
+
+	// creating it again does nothing
+	require.NoError(t, ensureTimer(context.TODO(), cli, testTimerConfig))
+	timers, err := cli.GetTimers(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, timers, 1)
+	require.Equal(t, timer.ID, timers[0].ID)
+
+	// the first event of the delayed timer is scheduled one interval after it's created
+	cfg := testTimerConfig
+	cfg.Key, cfg.DelayFirstEvent = "/tidb/test/delayed", true
+	require.NoError(t, ensureTimer(context.TODO(), cli, cfg))
+	timer, err = cli.GetTimerByKey(context.TODO(), cfg.Key)
+	require.NoError(t, err)
+	require.False(t, timer.Watermark.IsZero())
+}
+
+func TestJobHookOnEvent(t *testing.T) {
+	store := timerapi.NewMemoryTimerStore()
+	defer store.Close()
+	cli := timerapi.NewDefaultTimerClient(store)
+	require.NoError(t, ensureTimer(context.TODO(), cli, testTimerConfig))
+	timer, err := cli.GetTimerByKey(context.TODO(), testTimerConfig.Key)
+	require.NoError(t, err)
+
+	now := time.Unix(time.Now().Unix(), 0).UTC()
+	calls := make(chan string, 3)
+	hook := newJobHook(cli, testTimerConfig.Name, func(_ context.Context, n time.Time, summary []byte) ([]byte, error) {
+		require.Equal(t, now, n)
+		calls <- string(summary)
+		switch string(summary) {
+		case "":
+			return []byte("s1"), nil
+		case "s1":
+			return []byte("s2"), errors.New("mock error")
+		default:
+			return nil, nil
+		}
+	})
+	hook.nowFunc = func() time.Time {
+		return now
+	}
+	hook.Start()
+	defer hook.Stop()
+
+	// the returned summary is stored when the event is closed
+	timer = triggerTestTimer(t, store, timer.ID, "event1")
+	require.NoError(t, hook.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event1", timer: timer}))
+	require.Equal(t, "", <-calls)
+	closed := waitEventClosed(t, cli, timer.ID)
+	require.Equal(t, timer.EventStart, closed.Watermark)
+	require.Equal(t, "s1", string(closed.SummaryData))
+
+	// the event is closed with the returned summary even if the job returns an error
+	timer = triggerTestTimer(t, store, timer.ID, "event2")
+	require.NoError(t, hook.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event2", timer: timer}))
+	require.Equal(t, "s1", <-calls)
+	closed = waitEventClosed(t, cli, timer.ID)
+	require.Equal(t, timer.EventStart, closed.Watermark)
+	require.Equal(t, "s2", string(closed.SummaryData))
+
+	// the summary is kept if the job returns nil
+	timer = triggerTestTimer(t, store, timer.ID, "event3")
+	require.NoError(t, hook.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event3", timer: timer}))
+	require.Equal(t, "s2", <-calls)
+	closed = waitEventClosed(t, cli, timer.ID)
+	require.Equal(t, timer.EventStart, closed.Watermark)
+	require.Equal(t, "s2", string(closed.SummaryData))
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package periodic
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	timerrt "github.com/pingcap/tidb/timer/runtime"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// Job executes a periodic job at `now`. `summary` is the summary data stored in the timer by the last event,
+// and the returned summary data, if it's not nil, is stored in the timer when the event is closed, even if
+// an error is returned. The job should be idempotent, since an event triggered by another instance may be
+// executed again.
+type Job func(ctx context.Context, now time.Time, summary []byte) ([]byte, error)
+
+// TimerConfig is the config of the timer which triggers a periodic job.
+type TimerConfig struct {
+	// Name is the name of the job, it's used as the name of the timer runtime and in the logs.
+	Name string
+	// HookClass is the hook class of the timer.
+	HookClass string
+	// Key is the key of the timer, there is only one timer of the job in the cluster.
+	Key string
+	// Interval is the interval between two events of the timer.
+	Interval string
+	// DelayFirstEvent schedules the first event one interval after the timer is created,
+	// otherwise the first event is scheduled immediately.
+	DelayFirstEvent bool
+}
+
+// Runtime runs a periodic job by a timer. It should only be resumed in one TiDB instance of the cluster.
+type Runtime struct {
+	cfg   TimerConfig
+	rt    *timerrt.TimerGroupRuntime
+	store *timerapi.TimerStore
+	job   Job
+}
+
+// NewRuntime creates a new Runtime which runs `job` by the timer in `store`.
+func NewRuntime(cfg TimerConfig, store *timerapi.TimerStore, job Job) *Runtime {
+	return &Runtime{
+		cfg:   cfg,
+		store: store,
+		job:   job,
+	}
+}
+
+// Resume creates the timer if it does not exist, and starts to run the job if it is not running.
+func (r *Runtime) Resume() {
+	if r.rt != nil {
+		return
+	}
+
+	if err := ensureTimer(context.Background(), timerapi.NewDefaultTimerClient(r.store), r.cfg); err != nil {
+		logutil.BgLogger().Warn("failed to create the timer of periodic job", zap.String("job", r.cfg.Name), zap.Error(err))
+		return
+	}
+
+	r.rt = timerrt.NewTimerRuntimeBuilder(r.cfg.Name, r.store).
+		SetCond(&timerapi.TimerCond{Key: timerapi.NewOptionalVal(r.cfg.Key)}).
+		RegisterHookFactory(r.cfg.HookClass, func(hookClass string, cli timerapi.TimerClient) timerapi.Hook {
+			return newJobHook(cli, r.cfg.Name, r.job)
+		}).
+		Build()
+	r.rt.Start()
+}
+
+// Pause stops running the job.
+func (r *Runtime) Pause() {
+	if rt := r.rt; rt != nil {
+		r.rt = nil
+		rt.Stop()
+	}
+}
+
+// ensureTimer creates the timer of the job if it does not exist.
+func ensureTimer(ctx context.Context, cli timerapi.TimerClient, cfg TimerConfig) error {
+	_, err := cli.GetTimerByKey(ctx, cfg.Key)
+	if !errors.ErrorEqual(err, timerapi.ErrTimerNotExist) {
+		return err
+	}
+
+	spec := timerapi.TimerSpec{
+		Key:             cfg.Key,
+		HookClass:       cfg.HookClass,
+		SchedPolicyType: timerapi.SchedEventInterval,
+		SchedPolicyExpr: cfg.Interval,
+		Enable:          true,
+	}
+	if cfg.DelayFirstEvent {
+		spec.Watermark = time.Now()
+	}
+	_, err = cli.CreateTimer(ctx, spec)
+	if errors.ErrorEqual(err, timerapi.ErrTimerExists) {
+		return nil
+	}
+	return err
+}
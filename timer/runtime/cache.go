@@ -49,11 +49,8 @@ func (c *timerCacheItem) update(timer *api.TimerRecord, nowFunc func() time.Time
 	c.nextTryTriggerTime = time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	if timer.Enable {
-		p, err := timer.CreateSchedEventPolicy()
-		if err == nil {
-			if t, ok := p.NextEventTime(c.timer.Watermark); ok {
-				c.nextEventTime = &t
-			}
+		if t, ok, err := timer.NextEventTime(); err == nil && ok {
+			c.nextEventTime = &t
 		}
 
 		if timer.IsManualRequesting() {
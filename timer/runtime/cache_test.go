@@ -164,6 +164,34 @@ func TestCacheUpdate(t *testing.T) {
 	checkSortedCache(t, cache, [][]any{{t1, now}})
 }
 
+func TestCacheUpdateCronTimer(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	cache := newTimersCache()
+
+	t1 := newTestTimer("t1", "0 2 * * 1-5", time.Date(2023, 8, 4, 3, 0, 0, 0, loc).UTC())
+	t1.SchedPolicyType = api.SchedEventCron
+	t1.TimeZone = "Asia/Shanghai"
+	require.True(t, cache.updateTimer(t1))
+	next := time.Date(2023, 8, 7, 2, 0, 0, 0, loc)
+	require.Equal(t, next.Unix(), cache.items[t1.ID].nextEventTime.Unix())
+	require.Equal(t, next.Unix(), cache.items[t1.ID].nextTryTriggerTime.Unix())
+
+	t1.TimeZone = "UTC"
+	t1.Version++
+	require.True(t, cache.updateTimer(t1))
+	next = time.Date(2023, 8, 4, 2, 0, 0, 0, time.UTC)
+	require.Equal(t, next.Unix(), cache.items[t1.ID].nextEventTime.Unix())
+	require.Equal(t, next.Unix(), cache.items[t1.ID].nextTryTriggerTime.Unix())
+
+	// invalid time zone
+	t1.TimeZone = "Invalid/Zone"
+	t1.Version++
+	require.True(t, cache.updateTimer(t1))
+	require.Nil(t, cache.items[t1.ID].nextEventTime)
+	require.Equal(t, time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC), cache.items[t1.ID].nextTryTriggerTime)
+}
+
 func TestCacheSort(t *testing.T) {
 	now := time.Now()
 	nowFunc := func() time.Time {
@@ -366,14 +394,9 @@ func checkSortedCache(t *testing.T, cache *timersCache, sorted [][]any) {
 		if timer.IsManualRequesting() {
 			require.Equal(t, tryTriggerTime, *nextEventTime)
 		} else {
-			if p, err := timer.CreateSchedEventPolicy(); err == nil && timer.Enable {
+			if tm, ok, err := timer.NextEventTime(); err == nil && ok {
 				require.NotNil(t, nextEventTime)
-				tm, ok := p.NextEventTime(timer.Watermark)
-				if !ok {
-					require.Nil(t, nextEventTime)
-				} else {
-					require.Equal(t, tm, *nextEventTime)
-				}
+				require.Equal(t, tm, *nextEventTime)
 			} else {
 				require.Nil(t, nextEventTime)
 			}
@@ -163,6 +163,7 @@ func runTimerStoreUpdate(ctx context.Context, t *testing.T, store *api.TimerStor
 	err = store.Update(ctx, tpl.ID, &api.TimerUpdate{
 		Tags:            api.NewOptionalVal([]string{"l1", "l2"}),
 		SchedPolicyExpr: api.NewOptionalVal("2h"),
+		TimeZone:        api.NewOptionalVal("Asia/Shanghai"),
 		ManualRequest: api.NewOptionalVal(api.ManualRequest{
 			ManualRequestID:   "req1",
 			ManualRequestTime: time.Unix(123, 0),
@@ -190,6 +191,7 @@ func runTimerStoreUpdate(ctx context.Context, t *testing.T, store *api.TimerStor
 	require.Greater(t, record.Version, tpl.Version)
 	tpl.Version = record.Version
 	tpl.SchedPolicyExpr = "2h"
+	tpl.TimeZone = "Asia/Shanghai"
 	tpl.Tags = []string{"l1", "l2"}
 	tpl.EventStatus = api.SchedEventTrigger
 	tpl.EventID = eventID
@@ -309,6 +311,37 @@ func runTimerStoreUpdate(ctx context.Context, t *testing.T, store *api.TimerStor
 	record, err = store.GetByID(ctx, tpl.ID)
 	require.NoError(t, err)
 	require.Equal(t, *tpl, *record)
+
+	err = store.Update(ctx, tpl.ID, &api.TimerUpdate{
+		TimeZone: api.NewOptionalVal("Invalid/Zone"),
+	})
+	require.EqualError(t, err, "invalid time zone 'Invalid/Zone': unknown or incorrect time zone: 'Invalid/Zone'")
+	record, err = store.GetByID(ctx, tpl.ID)
+	require.NoError(t, err)
+	require.Equal(t, *tpl, *record)
+
+	// update to cron policy
+	err = store.Update(ctx, tpl.ID, &api.TimerUpdate{
+		SchedPolicyType: api.NewOptionalVal(api.SchedEventCron),
+		SchedPolicyExpr: api.NewOptionalVal("0 2 * * 1-5"),
+		TimeZone:        api.NewOptionalVal(""),
+	})
+	require.NoError(t, err)
+	record, err = store.GetByID(ctx, tpl.ID)
+	require.NoError(t, err)
+	tpl.Version = record.Version
+	tpl.SchedPolicyType = api.SchedEventCron
+	tpl.SchedPolicyExpr = "0 2 * * 1-5"
+	tpl.TimeZone = ""
+	require.Equal(t, *tpl, *record)
+
+	err = store.Update(ctx, tpl.ID, &api.TimerUpdate{
+		SchedPolicyExpr: api.NewOptionalVal("0 2 * *"),
+	})
+	require.EqualError(t, err, "schedule event configuration is not valid: invalid cron expr '0 2 * *': expected 5 to 6 fields, found 4: [0 2 * *]")
+	record, err = store.GetByID(ctx, tpl.ID)
+	require.NoError(t, err)
+	require.Equal(t, *tpl, *record)
 }
 
 func runTimerStoreDelete(ctx context.Context, t *testing.T, store *api.TimerStore, tpl *api.TimerRecord) {
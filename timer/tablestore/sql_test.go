@@ -28,10 +28,10 @@ func TestBuildInsertTimerSQL(t *testing.T) {
 	now := time.Now()
 	sql1 := "INSERT INTO `db1`.`t1` (NAMESPACE, TIMER_KEY, TIMER_DATA, TIMEZONE, SCHED_POLICY_TYPE, SCHED_POLICY_EXPR, " +
 		"HOOK_CLASS, WATERMARK, ENABLE, TIMER_EXT, EVENT_ID, EVENT_STATUS, EVENT_START, EVENT_DATA, SUMMARY_DATA, VERSION) " +
-		"VALUES (%?, %?, %?, 'TIDB', %?, %?, %?, FROM_UNIXTIME(%?), %?, JSON_MERGE_PATCH('{}', %?), %?, %?, FROM_UNIXTIME(%?), %?, %?, 1)"
+		"VALUES (%?, %?, %?, %?, %?, %?, %?, FROM_UNIXTIME(%?), %?, JSON_MERGE_PATCH('{}', %?), %?, %?, FROM_UNIXTIME(%?), %?, %?, 1)"
 	sql2 := "INSERT INTO `db1`.`t1` (NAMESPACE, TIMER_KEY, TIMER_DATA, TIMEZONE, SCHED_POLICY_TYPE, SCHED_POLICY_EXPR, " +
 		"HOOK_CLASS, WATERMARK, ENABLE, TIMER_EXT, EVENT_ID, EVENT_STATUS, EVENT_START, EVENT_DATA, SUMMARY_DATA, VERSION) " +
-		"VALUES (%?, %?, %?, 'TIDB', %?, %?, %?, %?, %?, JSON_MERGE_PATCH('{}', %?), %?, %?, %?, %?, %?, 1)"
+		"VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, JSON_MERGE_PATCH('{}', %?), %?, %?, %?, %?, %?, 1)"
 
 	cases := []struct {
 		sql    string
@@ -45,6 +45,7 @@ func TestBuildInsertTimerSQL(t *testing.T) {
 					Namespace:       "n1",
 					Key:             "k1",
 					Data:            []byte("data1"),
+					TimeZone:        "Asia/Shanghai",
 					SchedPolicyType: api.SchedEventInterval,
 					SchedPolicyExpr: "1h",
 					HookClass:       "h1",
@@ -70,7 +71,7 @@ func TestBuildInsertTimerSQL(t *testing.T) {
 				SummaryData: []byte("summary1"),
 			},
 			args: []any{
-				"n1", "k1", []byte("data1"), "INTERVAL", "1h", "h1", now.Unix(),
+				"n1", "k1", []byte("data1"), "Asia/Shanghai", "INTERVAL", "1h", "h1", now.Unix(),
 				true, json.RawMessage(`{"tags":["l1","l2"],` +
 					`"manual":{"request_id":"req1","request_time_unix":123,"timeout_sec":60,"processed":true,"event_id":"event1"},` +
 					`"event":{"manual_request_id":"req1","watermark_unix":456}}`),
@@ -88,7 +89,7 @@ func TestBuildInsertTimerSQL(t *testing.T) {
 				},
 			},
 			args: []any{
-				"n1", "k1", []byte(nil), "INTERVAL", "1h", "", nil,
+				"n1", "k1", []byte(nil), "", "INTERVAL", "1h", "", nil,
 				false, json.RawMessage("{}"), "", "IDLE", nil, []byte(nil), []byte(nil),
 			},
 		},
@@ -406,8 +407,10 @@ func TestBuildUpdateCriteria(t *testing.T) {
 			update: &api.TimerUpdate{
 				Enable:          api.NewOptionalVal(false),
 				Tags:            api.NewOptionalVal([]string{"l1", "l2"}),
+				Data:            api.NewOptionalVal([]byte("timerData")),
 				SchedPolicyType: api.NewOptionalVal(api.SchedEventInterval),
 				SchedPolicyExpr: api.NewOptionalVal("1h"),
+				TimeZone:        api.NewOptionalVal("Asia/Shanghai"),
 				ManualRequest: api.NewOptionalVal(api.ManualRequest{
 					ManualRequestID:   "req1",
 					ManualRequestTime: time.Unix(123, 0),
@@ -428,13 +431,13 @@ func TestBuildUpdateCriteria(t *testing.T) {
 				CheckEventID: api.NewOptionalVal("ee"),
 				CheckVersion: api.NewOptionalVal(uint64(1)),
 			},
-			criteria: "ENABLE = %?, SCHED_POLICY_TYPE = %?, SCHED_POLICY_EXPR = %?, EVENT_STATUS = %?, " +
+			criteria: "ENABLE = %?, TIMER_DATA = %?, SCHED_POLICY_TYPE = %?, SCHED_POLICY_EXPR = %?, TIMEZONE = %?, EVENT_STATUS = %?, " +
 				"EVENT_ID = %?, EVENT_DATA = %?, EVENT_START = FROM_UNIXTIME(%?), " +
 				"WATERMARK = FROM_UNIXTIME(%?), SUMMARY_DATA = %?, " +
 				"TIMER_EXT = JSON_MERGE_PATCH(TIMER_EXT, %?), " +
 				"VERSION = VERSION + 1",
 			args: []any{
-				false, "INTERVAL", "1h", "TRIGGER", "event1", []byte("data1"), now.Unix(),
+				false, []byte("timerData"), "INTERVAL", "1h", "Asia/Shanghai", "TRIGGER", "event1", []byte("data1"), now.Unix(),
 				now.Unix() + 1, []byte("summary"),
 				json.RawMessage(`{` +
 					`"event":{"manual_request_id":"req2","watermark_unix":456},` +
@@ -33,6 +33,10 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
+// legacyDefaultTimeZone is the value of column `TIMEZONE` written by the older versions for all timers.
+// It means to use TiDB's system time zone which is the same as an empty `TimeZone` now.
+const legacyDefaultTimeZone = "TIDB"
+
 type sessionPool interface {
 	Get() (pools.Resource, error)
 	Put(pools.Resource)
@@ -187,6 +191,11 @@ func (s *tableTimerStoreCore) List(ctx context.Context, cond api.Cond) ([]*api.T
 			}
 		}
 
+		timeZone := row.GetString(4)
+		if timeZone == legacyDefaultTimeZone {
+			timeZone = ""
+		}
+
 		timer := &api.TimerRecord{
 			ID: strconv.FormatUint(row.GetUint64(0), 10),
 			TimerSpec: api.TimerSpec{
@@ -194,6 +203,7 @@ func (s *tableTimerStoreCore) List(ctx context.Context, cond api.Cond) ([]*api.T
 				Key:             row.GetString(2),
 				Tags:            ext.Tags,
 				Data:            timerData,
+				TimeZone:        timeZone,
 				SchedPolicyType: api.SchedPolicyType(row.GetString(5)),
 				SchedPolicyExpr: row.GetString(6),
 				HookClass:       row.GetString(7),
@@ -356,6 +366,13 @@ func checkUpdateConstraints(update *api.TimerUpdate, eventID string, version uin
 		}
 	}
 
+	if val, ok := update.TimeZone.Get(); ok {
+		spec := api.TimerSpec{TimeZone: val}
+		if _, err := spec.Location(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
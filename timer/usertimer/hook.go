@@ -0,0 +1,213 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usertimer
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const defaultRetryCloseEventInterval = 10 * time.Second
+
+// SQLExecutor executes the statement of a user-defined timer with the schema of the timer as the current database.
+// It returns the affected rows of the statement.
+type SQLExecutor func(ctx context.Context, data *TimerData) (uint64, error)
+
+type eventData struct {
+	// TriggerBy is the id of the hook which triggers the event.
+	TriggerBy string `json:"trigger_by"`
+}
+
+type sqlTimerHook struct {
+	id                      string
+	cli                     timerapi.TimerClient
+	exec                    SQLExecutor
+	ctx                     context.Context
+	cancel                  func()
+	wg                      sync.WaitGroup
+	nowFunc                 func() time.Time
+	retryCloseEventInterval time.Duration
+
+	mu sync.Mutex
+	// running contains the ids of the events whose statements are executing.
+	running map[string]struct{}
+}
+
+func newSQLTimerHook(cli timerapi.TimerClient, exec SQLExecutor) *sqlTimerHook {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &sqlTimerHook{
+		id:                      uuid.NewString(),
+		cli:                     cli,
+		exec:                    exec,
+		ctx:                     ctx,
+		cancel:                  cancel,
+		nowFunc:                 time.Now,
+		retryCloseEventInterval: defaultRetryCloseEventInterval,
+		running:                 make(map[string]struct{}),
+	}
+}
+
+func (t *sqlTimerHook) Start() {}
+
+func (t *sqlTimerHook) Stop() {
+	t.cancel()
+	t.wg.Wait()
+}
+
+func (t *sqlTimerHook) OnPreSchedEvent(_ context.Context, _ timerapi.TimerShedEvent) (r timerapi.PreSchedEventResult, err error) {
+	r.EventData, err = json.Marshal(&eventData{TriggerBy: t.id})
+	return
+}
+
+func (t *sqlTimerHook) OnSchedEvent(ctx context.Context, event timerapi.TimerShedEvent) error {
+	timer := event.Timer()
+	eventID := event.EventID()
+	logger := logutil.BgLogger().With(
+		zap.String("key", timer.Key),
+		zap.String("eventID", eventID),
+		zap.Time("eventStart", timer.EventStart),
+	)
+
+	if err := t.ctx.Err(); err != nil {
+		return err
+	}
+
+	if t.isRunning(eventID) {
+		return nil
+	}
+
+	summary, err := DecodeTimerSummary(timer)
+	if err != nil {
+		logger.Warn("invalid user timer summary, reset it", zap.Error(err))
+		summary = &TimerSummary{}
+	}
+
+	var evData eventData
+	if len(timer.EventData) > 0 {
+		if err = json.Unmarshal(timer.EventData, &evData); err != nil {
+			logger.Warn("invalid user timer event data", zap.ByteString("eventData", timer.EventData))
+		}
+	}
+
+	if evData.TriggerBy != t.id {
+		// The event was triggered by another hook, for example, in another TiDB instance which has been stopped.
+		// The statement may have been executed, so we just close the event to make sure it is executed at most once.
+		logger.Warn("close user timer event which is triggered by another hook", zap.String("triggerBy", evData.TriggerBy))
+		now := t.nowFunc()
+		summary.setResult(eventID, EventStatusInterrupted, timer.EventStart, now, 0,
+			errors.New("event is interrupted because the TiDB instance triggering it is stopped"))
+		return t.closeEvent(ctx, timer.ID, eventID, timer.EventStart, summary)
+	}
+
+	data, err := DecodeTimerData(timer)
+	if err != nil {
+		logger.Error("invalid user timer data", zap.ByteString("data", timer.Data), zap.Error(err))
+		summary.setResult(eventID, EventStatusFailed, timer.EventStart, t.nowFunc(), 0, err)
+		return t.closeEvent(ctx, timer.ID, eventID, timer.EventStart, summary)
+	}
+
+	logger.Info("timer triggered to execute user statement", zap.String("schema", data.Schema), zap.String("name", data.Name))
+	t.mu.Lock()
+	t.running[eventID] = struct{}{}
+	t.mu.Unlock()
+	t.wg.Add(1)
+	go t.executeEvent(logger, timer.ID, eventID, timer.EventStart, data, summary)
+	return nil
+}
+
+func (t *sqlTimerHook) isRunning(eventID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.running[eventID]
+	return ok
+}
+
+func (t *sqlTimerHook) executeEvent(logger *zap.Logger, timerID, eventID string, eventStart time.Time, data *TimerData, summary *TimerSummary) {
+	defer func() {
+		t.mu.Lock()
+		delete(t.running, eventID)
+		t.mu.Unlock()
+		t.wg.Done()
+	}()
+
+	start := t.nowFunc()
+	affectedRows, err := t.exec(t.ctx, data)
+	status := EventStatusSuccess
+	if err != nil {
+		if t.ctx.Err() != nil {
+			// leave the event in trigger state, it will be closed as interrupted when the timer is triggered again.
+			logger.Info("stop executing user statement because of context cancelled", zap.Error(err))
+			return
+		}
+		status = EventStatusFailed
+		logger.Warn("failed to execute user statement", zap.Error(err))
+	}
+	summary.setResult(eventID, status, start, t.nowFunc(), affectedRows, err)
+
+	ticker := time.NewTicker(t.retryCloseEventInterval)
+	defer ticker.Stop()
+	for {
+		err = t.closeEvent(t.ctx, timerID, eventID, eventStart, summary)
+		if err == nil || errors.ErrorEqual(err, timerapi.ErrTimerNotExist) || errors.ErrorEqual(err, timerapi.ErrEventIDNotMatch) {
+			logger.Info("user timer event finished", zap.String("status", status), zap.Uint64("affectedRows", affectedRows), zap.Error(err))
+			return
+		}
+
+		logger.Error("CloseTimerEvent error", zap.Error(err))
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *sqlTimerHook) closeEvent(ctx context.Context, timerID, eventID string, eventStart time.Time, summary *TimerSummary) error {
+	summaryData, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	return t.cli.CloseTimerEvent(ctx, timerID, eventID,
+		timerapi.WithSetWatermark(eventStart),
+		timerapi.WithSetSummaryData(summaryData),
+	)
+}
+
+func (s *TimerSummary) setResult(eventID, status string, start, end time.Time, affectedRows uint64, err error) {
+	s.LastEventID = eventID
+	s.LastEventStatus = status
+	s.LastEventStart = start
+	s.LastEventEnd = end
+	s.LastAffectedRows = affectedRows
+	s.LastEventError = ""
+	if err != nil {
+		s.LastEventError = err.Error()
+	}
+
+	if status == EventStatusSuccess {
+		s.TotalSuccessCount++
+	} else {
+		s.TotalFailedCount++
+	}
+}
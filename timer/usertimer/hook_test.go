@@ -0,0 +1,205 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usertimer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTimerSchedEvent struct {
+	eventID string
+	timer   *timerapi.TimerRecord
+}
+
+func (e *mockTimerSchedEvent) EventID() string {
+	return e.eventID
+}
+
+func (e *mockTimerSchedEvent) Timer() *timerapi.TimerRecord {
+	return e.timer
+}
+
+type execRequest struct {
+	schema string
+	sql    string
+}
+
+func createTestTimer(t *testing.T, cli timerapi.TimerClient) *timerapi.TimerRecord {
+	data, err := EncodeTimerData(&TimerData{Schema: "Test", Name: "T1", SQL: "DELETE FROM t WHERE a < 10"})
+	require.NoError(t, err)
+	timer, err := cli.CreateTimer(context.TODO(), timerapi.TimerSpec{
+		Key:             TimerKey("Test", "T1"),
+		Data:            data,
+		SchedPolicyType: timerapi.SchedEventInterval,
+		SchedPolicyExpr: "1h",
+		HookClass:       HookClass,
+		Enable:          true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/tidb/user/test/t1", timer.Key)
+	return timer
+}
+
+func triggerTestTimer(t *testing.T, hook *sqlTimerHook, store *timerapi.TimerStore, timerID string, eventID string) *timerapi.TimerRecord {
+	timer, err := store.GetByID(context.TODO(), timerID)
+	require.NoError(t, err)
+	r, err := hook.OnPreSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: eventID, timer: timer})
+	require.NoError(t, err)
+	require.Zero(t, r.Delay)
+	err = store.Update(context.TODO(), timerID, &timerapi.TimerUpdate{
+		EventStatus: timerapi.NewOptionalVal(timerapi.SchedEventTrigger),
+		EventID:     timerapi.NewOptionalVal(eventID),
+		EventData:   timerapi.NewOptionalVal(r.EventData),
+		EventStart:  timerapi.NewOptionalVal(time.Unix(time.Now().Unix()-2, 0)),
+	})
+	require.NoError(t, err)
+	timer, err = store.GetByID(context.TODO(), timerID)
+	require.NoError(t, err)
+	return timer
+}
+
+func waitEventClosed(t *testing.T, cli timerapi.TimerClient, timerID string) *timerapi.TimerRecord {
+	start := time.Now()
+	for {
+		if time.Since(start) > time.Minute {
+			require.FailNow(t, "timeout")
+		}
+
+		tm, err := cli.GetTimerByID(context.TODO(), timerID)
+		require.NoError(t, err)
+		if tm.EventStatus == timerapi.SchedEventIdle {
+			return tm
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTimerKey(t *testing.T) {
+	require.Equal(t, "/tidb/user/db1/timer1", TimerKey("DB1", "Timer1"))
+	require.Equal(t, "/tidb/user/db1/", SchemaKeyPrefix("Db1"))
+
+	timer := &timerapi.TimerRecord{TimerSpec: timerapi.TimerSpec{Key: "/tidb/user/db1/timer1", HookClass: HookClass}}
+	_, err := DecodeTimerData(timer)
+	require.ErrorContains(t, err, "invalid data of timer '/tidb/user/db1/timer1'")
+
+	timer.Data = []byte(`{"schema":"Db1","name":"Timer1","sql":"select 1","comment":"c1"}`)
+	data, err := DecodeTimerData(timer)
+	require.NoError(t, err)
+	require.Equal(t, TimerData{Schema: "Db1", Name: "Timer1", SQL: "select 1", Comment: "c1"}, *data)
+
+	timer.HookClass = "other"
+	_, err = DecodeTimerData(timer)
+	require.EqualError(t, err, "timer '/tidb/user/db1/timer1' is not a user-defined timer")
+
+	summary, err := DecodeTimerSummary(timer)
+	require.NoError(t, err)
+	require.Equal(t, TimerSummary{}, *summary)
+}
+
+func TestSQLTimerHookOnEvent(t *testing.T) {
+	store := timerapi.NewMemoryTimerStore()
+	defer store.Close()
+	cli := timerapi.NewDefaultTimerClient(store)
+	timer := createTestTimer(t, cli)
+
+	execCh := make(chan execRequest, 1)
+	execResult := make(chan error, 1)
+	hook := newSQLTimerHook(cli, func(ctx context.Context, data *TimerData) (uint64, error) {
+		execCh <- execRequest{schema: data.Schema, sql: data.SQL}
+		return 3, <-execResult
+	})
+	hook.retryCloseEventInterval = time.Millisecond
+	hook.Start()
+	defer hook.Stop()
+
+	// execute successfully
+	timer = triggerTestTimer(t, hook, store, timer.ID, "event1")
+	eventStart := timer.EventStart
+	require.NoError(t, hook.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event1", timer: timer}))
+	require.Equal(t, execRequest{schema: "Test", sql: "DELETE FROM t WHERE a < 10"}, <-execCh)
+	// the same event should not be executed again when it is running
+	require.NoError(t, hook.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event1", timer: timer}))
+	execResult <- nil
+	timer = waitEventClosed(t, cli, timer.ID)
+	require.Equal(t, eventStart, timer.Watermark)
+	summary, err := DecodeTimerSummary(timer)
+	require.NoError(t, err)
+	require.Equal(t, "event1", summary.LastEventID)
+	require.Equal(t, EventStatusSuccess, summary.LastEventStatus)
+	require.Equal(t, uint64(3), summary.LastAffectedRows)
+	require.Equal(t, uint64(1), summary.TotalSuccessCount)
+	require.Equal(t, uint64(0), summary.TotalFailedCount)
+	require.Empty(t, summary.LastEventError)
+	require.Empty(t, execCh)
+
+	// execute failed
+	timer = triggerTestTimer(t, hook, store, timer.ID, "event2")
+	require.NoError(t, hook.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event2", timer: timer}))
+	<-execCh
+	execResult <- errors.New("mockErr")
+	timer = waitEventClosed(t, cli, timer.ID)
+	summary, err = DecodeTimerSummary(timer)
+	require.NoError(t, err)
+	require.Equal(t, "event2", summary.LastEventID)
+	require.Equal(t, EventStatusFailed, summary.LastEventStatus)
+	require.Equal(t, "mockErr", summary.LastEventError)
+	require.Equal(t, uint64(1), summary.TotalSuccessCount)
+	require.Equal(t, uint64(1), summary.TotalFailedCount)
+
+	// event triggered by another hook should be closed without executing
+	timer = triggerTestTimer(t, hook, store, timer.ID, "event3")
+	other := newSQLTimerHook(cli, func(ctx context.Context, data *TimerData) (uint64, error) {
+		require.FailNow(t, "should not execute")
+		return 0, nil
+	})
+	require.NoError(t, other.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event3", timer: timer}))
+	other.Stop()
+	timer, err = cli.GetTimerByID(context.TODO(), timer.ID)
+	require.NoError(t, err)
+	require.Equal(t, timerapi.SchedEventIdle, timer.EventStatus)
+	summary, err = DecodeTimerSummary(timer)
+	require.NoError(t, err)
+	require.Equal(t, "event3", summary.LastEventID)
+	require.Equal(t, EventStatusInterrupted, summary.LastEventStatus)
+	require.Equal(t, uint64(2), summary.TotalFailedCount)
+
+	// event without valid event data is also closed
+	err = store.Update(context.TODO(), timer.ID, &timerapi.TimerUpdate{
+		EventStatus: timerapi.NewOptionalVal(timerapi.SchedEventTrigger),
+		EventID:     timerapi.NewOptionalVal("event4"),
+		EventStart:  timerapi.NewOptionalVal(time.Now()),
+	})
+	require.NoError(t, err)
+	timer, err = cli.GetTimerByID(context.TODO(), timer.ID)
+	require.NoError(t, err)
+	require.NoError(t, hook.OnSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event4", timer: timer}))
+	timer, err = cli.GetTimerByID(context.TODO(), timer.ID)
+	require.NoError(t, err)
+	require.Equal(t, timerapi.SchedEventIdle, timer.EventStatus)
+	require.Empty(t, execCh)
+
+	var evData eventData
+	r, err := hook.OnPreSchedEvent(context.TODO(), &mockTimerSchedEvent{eventID: "event5", timer: timer})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(r.EventData, &evData))
+	require.Equal(t, hook.id, evData.TriggerBy)
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usertimer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx"
+	timerapi "github.com/pingcap/tidb/timer/api"
+	timerrt "github.com/pingcap/tidb/timer/runtime"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/tikv/client-go/v2/util"
+)
+
+type sessionPool interface {
+	Get() (pools.Resource, error)
+	Put(pools.Resource)
+}
+
+// Runtime triggers the user-defined timers and executes their statements.
+// It should only be resumed in one TiDB instance of the cluster.
+type Runtime struct {
+	rt    *timerrt.TimerGroupRuntime
+	store *timerapi.TimerStore
+	exec  SQLExecutor
+}
+
+// NewRuntime creates a new Runtime to run the user-defined timers in `store`.
+// The statements are executed with the privileges of the timer creators, which are checked by `privHandle`.
+func NewRuntime(store *timerapi.TimerStore, pool sessionPool, privHandle func() *privileges.Handle) *Runtime {
+	return &Runtime{
+		store: store,
+		exec:  NewSessionPoolSQLExecutor(pool, privHandle),
+	}
+}
+
+// Resume starts to run the timers if it is not running.
+func (r *Runtime) Resume() {
+	if r.rt != nil {
+		return
+	}
+
+	r.rt = timerrt.NewTimerRuntimeBuilder("user", r.store).
+		SetCond(&timerapi.TimerCond{Key: timerapi.NewOptionalVal(KeyPrefix), KeyPrefix: true}).
+		RegisterHookFactory(HookClass, func(hookClass string, cli timerapi.TimerClient) timerapi.Hook {
+			return newSQLTimerHook(cli, r.exec)
+		}).
+		Build()
+	r.rt.Start()
+}
+
+// Pause stops running the timers.
+func (r *Runtime) Pause() {
+	if rt := r.rt; rt != nil {
+		r.rt = nil
+		rt.Stop()
+	}
+}
+
+// NewSessionPoolSQLExecutor returns a SQLExecutor which executes statements with the sessions in `pool`.
+// If `privHandle` is not nil, a statement is executed as the creator of the timer, so the sessions,
+// which are internal and not restricted by privileges, can not be used to bypass the privileges of the creator.
+func NewSessionPoolSQLExecutor(pool sessionPool, privHandle func() *privileges.Handle) SQLExecutor {
+	return func(ctx context.Context, data *TimerData) (uint64, error) {
+		r, err := pool.Get()
+		if err != nil {
+			return 0, err
+		}
+
+		sctx, ok := r.(sessionctx.Context)
+		if !ok {
+			pool.Put(r)
+			return 0, errors.New("session is not the type sessionctx.Context")
+		}
+
+		exec, ok := r.(sqlexec.SQLExecutor)
+		if !ok {
+			pool.Put(r)
+			return 0, errors.New("session is not the type of SQLExecutor")
+		}
+
+		sessVars := sctx.GetSessionVars()
+		originalDB := sessVars.CurrentDB
+		sessVars.CurrentDB = data.Schema
+		defer func() {
+			sessVars.CurrentDB = originalDB
+			rollbackCtx := util.WithInternalSourceType(context.Background(), kv.InternalTimer)
+			if _, err := exec.ExecuteInternal(rollbackCtx, "ROLLBACK"); err != nil {
+				terror.Log(err)
+				return
+			}
+			pool.Put(r)
+		}()
+
+		if privHandle != nil {
+			restore, err := switchToCreator(sctx, privHandle(), data.Creator)
+			if err != nil {
+				return 0, err
+			}
+			defer restore()
+		}
+
+		ctx = util.WithInternalSourceType(ctx, kv.InternalTimer)
+		rs, err := exec.ExecuteInternal(ctx, data.SQL)
+		if err != nil {
+			return 0, err
+		}
+
+		if rs != nil {
+			_, err = sqlexec.DrainRecordSet(ctx, rs, 8)
+			terror.Call(rs.Close)
+			if err != nil {
+				return 0, err
+			}
+		}
+		return sessVars.StmtCtx.AffectedRows(), nil
+	}
+}
+
+// switchToCreator makes the session check the privileges of the statements as the creator of a timer.
+// It returns a function to restore the user and the privilege manager of the session.
+func switchToCreator(sctx sessionctx.Context, handle *privileges.Handle, creator string) (func(), error) {
+	idx := strings.LastIndex(creator, "@")
+	if idx < 0 {
+		return nil, errors.Errorf("invalid creator '%s' of the timer", creator)
+	}
+
+	username, hostname := creator[:idx], creator[idx+1:]
+	pm := privileges.NewUserPrivileges(handle, nil)
+	if !pm.GetAuthWithoutVerification(username, hostname) {
+		return nil, errors.Errorf("the creator '%s' of the timer does not exist", creator)
+	}
+
+	sessVars := sctx.GetSessionVars()
+	originalUser, originalRoles := sessVars.User, sessVars.ActiveRoles
+	originalPM := privilege.GetPrivilegeManager(sctx)
+
+	sessVars.User = &auth.UserIdentity{
+		Username:     username,
+		Hostname:     hostname,
+		AuthUsername: username,
+		AuthHostname: hostname,
+	}
+	sessVars.ActiveRoles = pm.GetDefaultRoles(username, hostname)
+	privilege.BindPrivilegeManager(sctx, pm)
+	return func() {
+		sessVars.User, sessVars.ActiveRoles = originalUser, originalRoles
+		privilege.BindPrivilegeManager(sctx, originalPM)
+	}, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usertimer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	timerapi "github.com/pingcap/tidb/timer/api"
+)
+
+const (
+	// HookClass is the hook class of the user-defined timers.
+	HookClass = "tidb.user_sql"
+	// KeyPrefix is the key prefix of the user-defined timers.
+	KeyPrefix = "/tidb/user/"
+)
+
+const (
+	// EventStatusSuccess indicates the statement of the event is executed successfully.
+	EventStatusSuccess = "SUCCESS"
+	// EventStatusFailed indicates the statement of the event returns an error.
+	EventStatusFailed = "FAILED"
+	// EventStatusInterrupted indicates the event is interrupted before the statement finished,
+	// for example, the TiDB instance which triggers the event is stopped.
+	EventStatusInterrupted = "INTERRUPTED"
+)
+
+// TimerKey returns the timer key of a user-defined timer.
+// Names are case-insensitive, so they are stored in lower case in the key.
+func TimerKey(schema, name string) string {
+	return fmt.Sprintf("%s%s/%s", KeyPrefix, strings.ToLower(schema), strings.ToLower(name))
+}
+
+// SchemaKeyPrefix returns the key prefix of all user-defined timers in a schema.
+func SchemaKeyPrefix(schema string) string {
+	return fmt.Sprintf("%s%s/", KeyPrefix, strings.ToLower(schema))
+}
+
+// TimerData is the data stored in a user-defined timer.
+type TimerData struct {
+	// Schema is the schema of the timer, it is also the current database when executing the statement.
+	Schema string `json:"schema"`
+	// Name is the name of the timer.
+	Name string `json:"name"`
+	// SQL is the statement to execute for each event.
+	SQL string `json:"sql"`
+	// Comment is the comment of the timer.
+	Comment string `json:"comment,omitempty"`
+	// Creator is the user who created the timer.
+	Creator string `json:"creator,omitempty"`
+}
+
+// EncodeTimerData encodes the timer data.
+func EncodeTimerData(data *TimerData) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+// DecodeTimerData decodes the timer data from a user-defined timer.
+func DecodeTimerData(timer *timerapi.TimerRecord) (*TimerData, error) {
+	if timer.HookClass != HookClass {
+		return nil, errors.Errorf("timer '%s' is not a user-defined timer", timer.Key)
+	}
+
+	var data TimerData
+	if err := json.Unmarshal(timer.Data, &data); err != nil {
+		return nil, errors.Wrapf(err, "invalid data of timer '%s'", timer.Key)
+	}
+	return &data, nil
+}
+
+// TimerSummary is the summary stored in a user-defined timer, it records the result of the last event.
+type TimerSummary struct {
+	LastEventID       string    `json:"last_event_id,omitempty"`
+	LastEventStatus   string    `json:"last_event_status,omitempty"`
+	LastEventStart    time.Time `json:"last_event_start"`
+	LastEventEnd      time.Time `json:"last_event_end"`
+	LastEventError    string    `json:"last_event_error,omitempty"`
+	LastAffectedRows  uint64    `json:"last_affected_rows,omitempty"`
+	TotalSuccessCount uint64    `json:"total_success_count,omitempty"`
+	TotalFailedCount  uint64    `json:"total_failed_count,omitempty"`
+}
+
+// DecodeTimerSummary decodes the summary of a user-defined timer.
+// An empty summary is returned if no event has been finished.
+func DecodeTimerSummary(timer *timerapi.TimerRecord) (*TimerSummary, error) {
+	var summary TimerSummary
+	if len(timer.SummaryData) == 0 {
+		return &summary, nil
+	}
+
+	if err := json.Unmarshal(timer.SummaryData, &summary); err != nil {
+		return nil, errors.Wrapf(err, "invalid summary of timer '%s'", timer.Key)
+	}
+	return &summary, nil
+}
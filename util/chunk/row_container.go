@@ -17,6 +17,7 @@ package chunk
 import (
 	"errors"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -135,6 +136,18 @@ func (c *RowContainer) SpillToDisk() {
 		defer c.actionSpill.cond.Broadcast()
 		defer c.actionSpill.setStatus(spilledYet)
 	}
+	defer func() {
+		// The query is cancelled when the temporary storage quota is exceeded, as spilling may run
+		// in a separate goroutine, the panic is recovered and returned by the following operations
+		// and GetSpillError.
+		if r := recover(); r != nil {
+			str, ok := r.(string)
+			if !ok || !strings.HasPrefix(str, disk.PanicDiskExceedWarnMsg) {
+				panic(r)
+			}
+			c.m.records.spillError = errors.New(str)
+		}
+	}()
 	var err error
 	memory.QueryForceDisk.Add(1)
 	n := c.m.records.inMemory.NumChunks()
@@ -181,6 +194,14 @@ func (c *RowContainer) AlreadySpilledSafeForTest() bool {
 	return c.m.records.inDisk != nil
 }
 
+// GetSpillError returns the error when spilling, e.g. the temporary storage quota is exceeded.
+// The rows may be lost if the spilling fails, so the error must be checked before reading them.
+func (c *RowContainer) GetSpillError() error {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.m.records.spillError
+}
+
 // NumRow returns the number of rows in the container
 func (c *RowContainer) NumRow() int {
 	c.m.RLock()
@@ -539,6 +560,9 @@ func (c *SortedRowContainer) Add(chk *Chunk) (err error) {
 	c.ptrM.RLock()
 	defer c.ptrM.RUnlock()
 	if c.ptrM.rowPtrs != nil {
+		if err := c.GetSpillError(); err != nil {
+			return err
+		}
 		return ErrCannotAddBecauseSorted
 	}
 	// Consume the memory usage of rowPtrs in advance
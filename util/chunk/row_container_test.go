@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/stretchr/testify/require"
 )
@@ -355,6 +356,28 @@ func TestRowContainerReaderInDisk(t *testing.T) {
 	}
 }
 
+func TestSpillToDiskExceedQuota(t *testing.T) {
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TempStoragePath = t.TempDir()
+	})
+
+	rc, _ := insertBytesRowsIntoRowContainer(t, 16, 16)
+	quota := disk.NewTracker(memory.LabelForSession, 1024)
+	quota.SetActionOnExceed(&disk.PanicOnExceed{ConnID: 1})
+	rc.GetDiskTracker().AttachTo(quota)
+	// The panic is recovered and returned by the following reads.
+	rc.SpillToDisk()
+	require.True(t, rc.AlreadySpilledSafeForTest())
+	_, err := rc.GetRow(RowPtr{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), disk.PanicDiskExceedWarnMsg)
+	_, err = rc.GetChunk(0)
+	require.Error(t, err)
+	require.NoError(t, rc.Close())
+}
+
 func TestCloseRowContainerReader(t *testing.T) {
 	restore := config.RestoreFunc()
 	defer restore()
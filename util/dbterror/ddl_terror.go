@@ -467,6 +467,12 @@ var (
 	ErrCheckConstraintUsingFKReferActionColumn = ClassDDL.NewStd(mysql.ErrCheckConstraintClauseUsingFKReferActionColumn)
 	// ErrNonBooleanExprForCheckConstraint is returned for non bool expression.
 	ErrNonBooleanExprForCheckConstraint = ClassDDL.NewStd(mysql.ErrNonBooleanExprForCheckConstraint)
+	// ErrInvalidMaskingPolicy is returned when the masking function doesn't fit the column.
+	ErrInvalidMaskingPolicy = ClassDDL.NewStd(mysql.ErrInvalidMaskingPolicy)
+	// ErrInvalidRowAccessPolicy is returned when the expression of the row access policy is invalid.
+	ErrInvalidRowAccessPolicy = ClassDDL.NewStd(mysql.ErrInvalidRowAccessPolicy)
+	// ErrDependentByRowAccessPolicy is returned when a column referred by a row access policy is dropped or renamed.
+	ErrDependentByRowAccessPolicy = ClassDDL.NewStd(mysql.ErrDependentByRowAccessPolicy)
 )
 
 // ReorgRetryableErrCodes is the error codes that are retryable for reorganization.
@@ -53,6 +53,8 @@ var (
 	ErrMaxExecTimeExceeded                  = dbterror.ClassExecutor.NewStd(mysql.ErrMaxExecTimeExceeded)
 	ErrResourceGroupQueryRunawayInterrupted = dbterror.ClassExecutor.NewStd(mysql.ErrResourceGroupQueryRunawayInterrupted)
 	ErrResourceGroupQueryRunawayQuarantine  = dbterror.ClassExecutor.NewStd(mysql.ErrResourceGroupQueryRunawayQuarantine)
+	ErrResourceGroupQueueFull               = dbterror.ClassExecutor.NewStd(mysql.ErrResourceGroupQueueFull)
+	ErrResourceGroupQueueTimeout            = dbterror.ClassExecutor.NewStd(mysql.ErrResourceGroupQueueTimeout)
 	ErrDynamicPrivilegeNotRegistered        = dbterror.ClassExecutor.NewStd(mysql.ErrDynamicPrivilegeNotRegistered)
 	ErrIllegalPrivilegeLevel                = dbterror.ClassExecutor.NewStd(mysql.ErrIllegalPrivilegeLevel)
 	ErrInvalidSplitRegionRanges             = dbterror.ClassExecutor.NewStd(mysql.ErrInvalidSplitRegionRanges)
@@ -99,4 +101,5 @@ var (
 	ErrLoadDataInvalidOperation       = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataInvalidOperation)
 	ErrLoadDataLocalUnsupportedOption = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataLocalUnsupportedOption)
 	ErrLoadDataPreCheckFailed         = dbterror.ClassExecutor.NewStd(mysql.ErrLoadDataPreCheckFailed)
+	ErrRowAccessPolicyViolation       = dbterror.ClassExecutor.NewStd(mysql.ErrRowAccessPolicyViolation)
 )
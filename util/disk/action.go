@@ -0,0 +1,56 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+const (
+	// PanicDiskExceedWarnMsg represents the panic message when out of the temporary storage quota of a query.
+	PanicDiskExceedWarnMsg string = "Your query has been cancelled due to exceeding the allowed temporary storage quota"
+	// WarnMsgSuffixForQuery represents the suffix of the warning message when out of the temporary storage quota of a query.
+	WarnMsgSuffixForQuery string = " for a single SQL query. Please try narrowing your query scope or increase the tidb_tmp_storage_quota_query limit and try again."
+)
+
+// PanicOnExceed panics when the disk usage of a query exceeds its temporary storage quota.
+type PanicOnExceed struct {
+	memory.BaseOOMAction
+	ConnID uint64
+	mutex  sync.Mutex // For synchronization.
+	acted  bool
+}
+
+// Action panics when the disk usage exceeds the quota.
+func (a *PanicOnExceed) Action(t *Tracker) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !a.acted {
+		logutil.BgLogger().Warn("temporary storage exceeds quota",
+			zap.Uint64("conn", a.ConnID), zap.Int64("consumed", t.BytesConsumed()), zap.Int64("quota", t.GetBytesLimit()))
+	}
+	a.acted = true
+	panic(PanicDiskExceedWarnMsg + WarnMsgSuffixForQuery + fmt.Sprintf("[conn=%d]", a.ConnID))
+}
+
+// GetPriority get the priority of the Action
+func (*PanicOnExceed) GetPriority() int64 {
+	return memory.DefPanicPriority
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanicOnExceed(t *testing.T) {
+	parent := NewTracker(memory.LabelForSession, 100)
+	parent.SetActionOnExceed(&PanicOnExceed{ConnID: 3})
+	child := NewTracker(1, -1)
+	child.AttachTo(parent)
+
+	child.Consume(99)
+	require.PanicsWithValue(t, PanicDiskExceedWarnMsg+WarnMsgSuffixForQuery+"[conn=3]", func() {
+		child.Consume(1)
+	})
+	require.Equal(t, int64(100), parent.BytesConsumed())
+
+	// The quota is not limited.
+	parent.SetBytesLimit(-1)
+	require.NotPanics(t, func() {
+		child.Consume(1 << 30)
+	})
+}
@@ -91,11 +91,13 @@ type Tracker struct {
 	bytesConsumed       int64             // Consumed bytes.
 	bytesReleased       int64             // Released bytes.
 	maxConsumed         atomicutil.Int64  // max number of bytes consumed during execution.
+	totalConsumed       atomicutil.Int64  // total number of bytes consumed, only maintained when trackTotal is true.
 	SessionID           atomicutil.Uint64 // SessionID indicates the sessionID the tracker is bound.
 	NeedKill            atomic.Bool       // NeedKill indicates whether this session need kill because OOM
 	NeedKillReceived    sync.Once
 	IsRootTrackerOfSess bool // IsRootTrackerOfSess indicates whether this tracker is bound for session
 	isGlobal            bool // isGlobal indicates whether this tracker is global tracker
+	trackTotal          bool // trackTotal indicates whether this tracker maintains the totalConsumed
 }
 
 type actionMu struct {
@@ -141,7 +143,9 @@ func InitTracker(t *Tracker, label int, bytesLimit int64, action ActionOnExceed)
 		bytesSoftLimit: int64(float64(bytesLimit) * softScale),
 	})
 	t.maxConsumed.Store(0)
+	t.totalConsumed.Store(0)
 	t.isGlobal = false
+	t.trackTotal = false
 }
 
 // NewTracker creates a memory tracker.
@@ -408,6 +412,9 @@ func (t *Tracker) Consume(bs int64) {
 			sessionRootTracker = tracker
 		}
 		bytesConsumed := atomic.AddInt64(&tracker.bytesConsumed, bs)
+		if bs > 0 && tracker.trackTotal {
+			tracker.totalConsumed.Add(bs)
+		}
 		bytesReleased := atomic.LoadInt64(&tracker.bytesReleased)
 		limits := tracker.bytesLimit.Load().(*bytesLimits)
 		if bytesConsumed+bytesReleased >= limits.bytesHardLimit && limits.bytesHardLimit > 0 {
@@ -577,6 +584,24 @@ func (t *Tracker) ResetMaxConsumed() {
 	t.maxConsumed.Store(t.BytesConsumed())
 }
 
+// TrackTotalConsumed makes the tracker maintain the total number of bytes consumed, in which the released
+// bytes are not deducted, e.g. the bytes spilled to disk by a statement.
+// It should be called before the tracker is used.
+func (t *Tracker) TrackTotalConsumed() {
+	t.trackTotal = true
+}
+
+// TotalConsumed returns the total number of bytes consumed since the last ResetTotalConsumed,
+// it's always 0 unless TrackTotalConsumed is called.
+func (t *Tracker) TotalConsumed() int64 {
+	return t.totalConsumed.Load()
+}
+
+// ResetTotalConsumed should be invoked before executing a new statement in a session.
+func (t *Tracker) ResetTotalConsumed() {
+	t.totalConsumed.Store(0)
+}
+
 // SearchTrackerWithoutLock searches the specific tracker under this tracker without lock.
 func (t *Tracker) SearchTrackerWithoutLock(label int) *Tracker {
 	if t.label == label {
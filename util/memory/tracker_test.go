@@ -408,6 +408,29 @@ func TestMaxConsumed(t *testing.T) {
 	}
 }
 
+func TestTotalConsumed(t *testing.T) {
+	r := NewTracker(1, -1)
+	c1 := NewTracker(2, -1)
+	c1.AttachTo(r)
+
+	c1.Consume(100)
+	c1.Consume(-100)
+	require.Equal(t, int64(0), r.TotalConsumed())
+
+	r.TrackTotalConsumed()
+	c1.Consume(100)
+	c1.Consume(-50)
+	r.Consume(20)
+	require.Equal(t, int64(70), r.BytesConsumed())
+	require.Equal(t, int64(120), r.TotalConsumed())
+	require.Equal(t, int64(0), c1.TotalConsumed())
+
+	r.ResetTotalConsumed()
+	require.Equal(t, int64(0), r.TotalConsumed())
+	c1.Consume(10)
+	require.Equal(t, int64(10), r.TotalConsumed())
+}
+
 func TestGlobalTracker(t *testing.T) {
 	r := NewGlobalTracker(1, -1)
 	c1 := NewTracker(2, -1)
@@ -0,0 +1,212 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProtocolGRPC sends the requests to an OTLP/gRPC endpoint.
+	ProtocolGRPC = "grpc"
+	// ProtocolHTTP sends the requests to an OTLP/HTTP endpoint in the binary protobuf encoding.
+	ProtocolHTTP = "http"
+
+	// DefaultTimeout is the timeout of an export request if it's not set in the Config.
+	DefaultTimeout = 10 * time.Second
+
+	// maxErrorMessageLen is the max length of the response body kept in the error of a failed HTTP request.
+	maxErrorMessageLen = 1024
+)
+
+// Service is an OTLP collector service, e.g. the trace service or the metrics service.
+type Service struct {
+	// GRPCMethod is the full name of the Export method of the gRPC service.
+	GRPCMethod string
+	// HTTPPath is the path of the OTLP/HTTP endpoint, it's used when the endpoint URL has no path.
+	HTTPPath string
+}
+
+var (
+	// TraceService receives the ExportTraceServiceRequest.
+	TraceService = Service{
+		GRPCMethod: "/opentelemetry.proto.collector.trace.v1.TraceService/Export",
+		HTTPPath:   "/v1/traces",
+	}
+	// MetricsService receives the ExportMetricsServiceRequest.
+	MetricsService = Service{
+		GRPCMethod: "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export",
+		HTTPPath:   "/v1/metrics",
+	}
+)
+
+// Config is the config of a Client.
+type Config struct {
+	// Protocol is ProtocolGRPC or ProtocolHTTP.
+	Protocol string
+	// Endpoint is the address of the collector. It's `host:port` for gRPC, and a URL for HTTP.
+	Endpoint string
+	// Insecure disables the TLS for gRPC.
+	Insecure bool
+	// Headers are sent with every export request, they're usually used for authentication.
+	Headers map[string]string
+	// Timeout is the timeout of each export request, DefaultTimeout is used if it's not positive.
+	Timeout time.Duration
+}
+
+// Client sends the export requests of an OTLP service to the collector.
+type Client interface {
+	// Export sends the request and decodes the response of the collector into resp.
+	Export(ctx context.Context, req, resp proto.Message) error
+	Close() error
+}
+
+// New creates a Client of the service by the config.
+func New(cfg Config, service Service) (Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	switch strings.ToLower(cfg.Protocol) {
+	case ProtocolGRPC:
+		return newGRPCClient(cfg, service)
+	case ProtocolHTTP:
+		return newHTTPClient(cfg, service)
+	}
+	return nil, errors.Errorf("unknown OTLP protocol %s, it should be grpc or http", cfg.Protocol)
+}
+
+type grpcClient struct {
+	conn    *grpc.ClientConn
+	method  string
+	md      metadata.MD
+	timeout time.Duration
+}
+
+func newGRPCClient(cfg Config, service Service) (*grpcClient, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("the endpoint of the OTLP/gRPC client is empty")
+	}
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	// The connection is established lazily, so the collector being down doesn't block the startup.
+	conn, err := grpc.Dial(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &grpcClient{
+		conn:    conn,
+		method:  service.GRPCMethod,
+		md:      metadata.New(cfg.Headers),
+		timeout: cfg.Timeout,
+	}, nil
+}
+
+// Export implements the Client interface.
+func (c *grpcClient) Export(ctx context.Context, req, resp proto.Message) error {
+	ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(ctx, c.md), c.timeout)
+	defer cancel()
+	return errors.Trace(c.conn.Invoke(ctx, c.method, req, resp))
+}
+
+// Close implements the Client interface.
+func (c *grpcClient) Close() error {
+	return c.conn.Close()
+}
+
+type httpClient struct {
+	client   *http.Client
+	endpoint string
+	headers  map[string]string
+}
+
+func newHTTPClient(cfg Config, service Service) (*httpClient, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("the endpoint of the OTLP/HTTP client is empty")
+	}
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = service.HTTPPath
+	}
+	return &httpClient{
+		client:   &http.Client{Timeout: cfg.Timeout},
+		endpoint: u.String(),
+		headers:  cfg.Headers,
+	}, nil
+}
+
+// Export implements the Client interface.
+func (c *httpClient) Export(ctx context.Context, req, resp proto.Message) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range c.headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, maxErrorMessageLen))
+		return errors.Errorf("export to %s failed, status: %s, message: %s", c.endpoint, httpResp.Status, msg)
+	}
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Some collectors and proxies reply with an empty or a non-protobuf body on success, it's not an error.
+	if strings.HasPrefix(httpResp.Header.Get("Content-Type"), "application/x-protobuf") {
+		return errors.Trace(proto.Unmarshal(data, resp))
+	}
+	return nil
+}
+
+// Close implements the Client interface.
+func (c *httpClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
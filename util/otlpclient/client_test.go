@@ -0,0 +1,193 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpclient
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func testTraceRequest() *coltracepb.ExportTraceServiceRequest {
+	return &coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+		InstrumentationLibrarySpans: []*tracepb.InstrumentationLibrarySpans{{Spans: []*tracepb.Span{{Name: "test"}}}},
+	}}}
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := New(Config{Protocol: "unknown", Endpoint: "127.0.0.1:4317"}, TraceService)
+	require.ErrorContains(t, err, "unknown OTLP protocol unknown")
+	_, err = New(Config{Protocol: ProtocolGRPC}, TraceService)
+	require.ErrorContains(t, err, "endpoint of the OTLP/gRPC client is empty")
+	_, err = New(Config{Protocol: ProtocolHTTP}, TraceService)
+	require.ErrorContains(t, err, "endpoint of the OTLP/HTTP client is empty")
+
+	cli, err := New(Config{Protocol: "HTTP", Endpoint: "127.0.0.1:4318"}, MetricsService)
+	require.NoError(t, err)
+	require.Equal(t, "http://127.0.0.1:4318/v1/metrics", cli.(*httpClient).endpoint)
+	require.Equal(t, DefaultTimeout, cli.(*httpClient).client.Timeout)
+	require.NoError(t, cli.Close())
+	cli, err = New(Config{Protocol: ProtocolHTTP, Endpoint: "https://collector/custom", Timeout: time.Second}, MetricsService)
+	require.NoError(t, err)
+	require.Equal(t, "https://collector/custom", cli.(*httpClient).endpoint)
+	require.Equal(t, time.Second, cli.(*httpClient).client.Timeout)
+	require.NoError(t, cli.Close())
+}
+
+func TestHTTPClient(t *testing.T) {
+	reqCh := make(chan *coltracepb.ExportTraceServiceRequest, 1)
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("bad token"))
+			return
+		}
+		if r.Header.Get("X-Block") != "" {
+			<-block
+			return
+		}
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := &coltracepb.ExportTraceServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, req))
+		reqCh <- req
+		resp, err := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+	defer close(block)
+
+	cli, err := New(Config{Protocol: ProtocolHTTP, Endpoint: server.URL, Headers: map[string]string{"Authorization": "secret"}}, TraceService)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, cli.Close())
+	}()
+	require.NoError(t, cli.Export(context.Background(), testTraceRequest(), &coltracepb.ExportTraceServiceResponse{}))
+	require.Equal(t, "test", (<-reqCh).ResourceSpans[0].InstrumentationLibrarySpans[0].Spans[0].Name)
+
+	badCli, err := New(Config{Protocol: ProtocolHTTP, Endpoint: server.URL}, TraceService)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, badCli.Close())
+	}()
+	err = badCli.Export(context.Background(), testTraceRequest(), &coltracepb.ExportTraceServiceResponse{})
+	require.ErrorContains(t, err, "401 Unauthorized, message: bad token")
+
+	// The request is canceled by the timeout if the collector doesn't respond.
+	blockCli, err := New(Config{
+		Protocol: ProtocolHTTP,
+		Endpoint: server.URL,
+		Headers:  map[string]string{"Authorization": "secret", "X-Block": "1"},
+		Timeout:  100 * time.Millisecond,
+	}, TraceService)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, blockCli.Close())
+	}()
+	start := time.Now()
+	err = blockCli.Export(context.Background(), testTraceRequest(), &coltracepb.ExportTraceServiceResponse{})
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+type mockTraceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	reqCh chan *coltracepb.ExportTraceServiceRequest
+	block chan struct{}
+}
+
+func (s *mockTraceServer) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("x-block")) > 0 {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+		}
+		return nil, status.Error(codes.Canceled, "blocked")
+	}
+	if auth := md.Get("authorization"); len(auth) != 1 || auth[0] != "secret" {
+		return nil, status.Error(codes.Unauthenticated, "bad token")
+	}
+	s.reqCh <- req
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestGRPCClient(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	mock := &mockTraceServer{reqCh: make(chan *coltracepb.ExportTraceServiceRequest, 1), block: make(chan struct{})}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, mock)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+	defer close(mock.block)
+
+	cli, err := New(Config{
+		Protocol: ProtocolGRPC,
+		Endpoint: lis.Addr().String(),
+		Insecure: true,
+		Headers:  map[string]string{"Authorization": "secret"},
+	}, TraceService)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, cli.Close())
+	}()
+	require.NoError(t, cli.Export(context.Background(), testTraceRequest(), &coltracepb.ExportTraceServiceResponse{}))
+	require.Equal(t, "test", (<-mock.reqCh).ResourceSpans[0].InstrumentationLibrarySpans[0].Spans[0].Name)
+
+	badCli, err := New(Config{Protocol: ProtocolGRPC, Endpoint: lis.Addr().String(), Insecure: true}, TraceService)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, badCli.Close())
+	}()
+	err = badCli.Export(context.Background(), testTraceRequest(), &coltracepb.ExportTraceServiceResponse{})
+	require.Equal(t, codes.Unauthenticated, status.Code(errors.Cause(err)))
+
+	// The request is canceled by the timeout if the collector doesn't respond.
+	blockCli, err := New(Config{
+		Protocol: ProtocolGRPC,
+		Endpoint: lis.Addr().String(),
+		Insecure: true,
+		Headers:  map[string]string{"X-Block": "1"},
+		Timeout:  100 * time.Millisecond,
+	}, TraceService)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, blockCli.Close())
+	}()
+	err = blockCli.Export(context.Background(), testTraceRequest(), &coltracepb.ExportTraceServiceResponse{})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(errors.Cause(err)))
+}
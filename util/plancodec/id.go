@@ -135,6 +135,8 @@ const (
 	TypeForeignKeyCascade = "Foreign_Key_Cascade"
 	// TypeImportInto is the type of ImportInto.
 	TypeImportInto = "ImportInto"
+	// TypeTableChanges is the type of TableChanges.
+	TypeTableChanges = "TableChanges"
 	// TypeSequence is the type of Sequence
 	TypeSequence = "Sequence"
 )
@@ -201,6 +203,7 @@ const (
 	typeForeignKeyCascade     int = 57
 	typeExpandID              int = 58
 	typeImportIntoID          int = 59
+	typeTableChangesID        int = 60
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -324,6 +327,8 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeExpandID
 	case TypeImportInto:
 		return typeImportIntoID
+	case TypeTableChanges:
+		return typeTableChangesID
 	}
 	// Should never reach here.
 	return 0
@@ -450,6 +455,8 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeExpand
 	case typeImportIntoID:
 		return TypeImportInto
+	case typeTableChangesID:
+		return TypeTableChanges
 	}
 
 	// Should never reach here.
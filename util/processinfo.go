@@ -99,11 +99,22 @@ func (pi *ProcessInfo) ToRowForShow(full bool) []interface{} {
 		db,
 		mysql.Command2Str[pi.Command],
 		t,
-		serverStatus2Str(pi.State),
+		pi.stateString(),
 		info,
 	}
 }
 
+func (pi *ProcessInfo) stateString() string {
+	state := serverStatus2Str(pi.State)
+	if pi.StmtCtx == nil || !pi.StmtCtx.WaitingInResourceGroupQueue.Load() {
+		return state
+	}
+	if state == "" {
+		return waitingInResourceGroupQueue
+	}
+	return state + "; " + waitingInResourceGroupQueue
+}
+
 func (pi *ProcessInfo) String() string {
 	rows := pi.ToRowForShow(false)
 	return fmt.Sprintf("{id:%v, user:%v, host:%v, db:%v, command:%v, time:%v, state:%v, info:%v}", rows...)
@@ -133,6 +144,9 @@ func (pi *ProcessInfo) ToRow(tz *time.Location) []interface{} {
 	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz), pi.ResourceGroupName)
 }
 
+// waitingInResourceGroupQueue is the state of the statement waiting in the admission queue of its resource group.
+const waitingInResourceGroupQueue = "waiting in resource group queue"
+
 // ascServerStatus is a slice of all defined server status in ascending order.
 var ascServerStatus = []uint16{
 	mysql.ServerStatusInTrans,
@@ -368,6 +368,10 @@ func addInfo(addTo *stmtSummaryByDigestElement, addWith *stmtSummaryByDigestElem
 	if addTo.maxDisk < addWith.maxDisk {
 		addTo.maxDisk = addWith.maxDisk
 	}
+	addTo.sumDiskSpilled += addWith.sumDiskSpilled
+	if addTo.maxDiskSpilled < addWith.maxDiskSpilled {
+		addTo.maxDiskSpilled = addWith.maxDiskSpilled
+	}
 	if addTo.firstSeen.After(addWith.firstSeen) {
 		addTo.firstSeen = addWith.firstSeen
 	}
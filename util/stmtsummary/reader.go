@@ -291,6 +291,8 @@ const (
 	MaxMemStr                         = "MAX_MEM"
 	AvgDiskStr                        = "AVG_DISK"
 	MaxDiskStr                        = "MAX_DISK"
+	AvgDiskSpilledStr                 = "AVG_DISK_SPILLED"
+	MaxDiskSpilledStr                 = "MAX_DISK_SPILLED"
 	AvgKvTimeStr                      = "AVG_KV_TIME"
 	AvgPdTimeStr                      = "AVG_PD_TIME"
 	AvgBackoffTotalTimeStr            = "AVG_BACKOFF_TOTAL_TIME"
@@ -553,6 +555,12 @@ var columnValueFactoryMap = map[string]columnValueFactory{
 	MaxDiskStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
 		return ssElement.maxDisk
 	},
+	AvgDiskSpilledStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
+		return avgInt(ssElement.sumDiskSpilled, ssElement.execCount)
+	},
+	MaxDiskSpilledStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
+		return ssElement.maxDiskSpilled
+	},
 	AvgKvTimeStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
 		return avgInt(int64(ssElement.sumKVTotal), ssElement.commitCount)
 	},
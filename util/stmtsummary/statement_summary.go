@@ -190,6 +190,8 @@ type stmtSummaryByDigestElement struct {
 	maxMem               int64
 	sumDisk              int64
 	maxDisk              int64
+	sumDiskSpilled       int64
+	maxDiskSpilled       int64
 	sumAffectedRows      uint64
 	sumKVTotal           time.Duration
 	sumPDTotal           time.Duration
@@ -235,6 +237,7 @@ type StmtExecInfo struct {
 	ExecDetail          *execdetails.ExecDetails
 	MemMax              int64
 	DiskMax             int64
+	DiskSpilled         int64
 	StartTime           time.Time
 	IsInternal          bool
 	Succeed             bool
@@ -863,6 +866,10 @@ func (ssElement *stmtSummaryByDigestElement) add(sei *StmtExecInfo, intervalSeco
 	if sei.DiskMax > ssElement.maxDisk {
 		ssElement.maxDisk = sei.DiskMax
 	}
+	ssElement.sumDiskSpilled += sei.DiskSpilled
+	if sei.DiskSpilled > ssElement.maxDiskSpilled {
+		ssElement.maxDiskSpilled = sei.DiskSpilled
+	}
 	if sei.StartTime.Before(ssElement.firstSeen) {
 		ssElement.firstSeen = sei.StartTime
 	}
@@ -105,6 +105,8 @@ const (
 	MaxMemStr                         = "MAX_MEM"
 	AvgDiskStr                        = "AVG_DISK"
 	MaxDiskStr                        = "MAX_DISK"
+	AvgDiskSpilledStr                 = "AVG_DISK_SPILLED"
+	MaxDiskSpilledStr                 = "MAX_DISK_SPILLED"
 	AvgKvTimeStr                      = "AVG_KV_TIME"
 	AvgPdTimeStr                      = "AVG_PD_TIME"
 	AvgBackoffTotalTimeStr            = "AVG_BACKOFF_TOTAL_TIME"
@@ -372,6 +374,12 @@ var columnFactoryMap = map[string]columnFactory{
 	MaxDiskStr: func(info columnInfo, record *StmtRecord) interface{} {
 		return record.MaxDisk
 	},
+	AvgDiskSpilledStr: func(info columnInfo, record *StmtRecord) interface{} {
+		return avgInt(record.SumDiskSpilled, record.ExecCount)
+	},
+	MaxDiskSpilledStr: func(info columnInfo, record *StmtRecord) interface{} {
+		return record.MaxDiskSpilled
+	},
 	AvgKvTimeStr: func(info columnInfo, record *StmtRecord) interface{} {
 		return avgInt(int64(record.SumKVTotal), record.CommitCount)
 	},
@@ -541,6 +541,9 @@ type stmtFiles struct {
 }
 
 func newStmtFiles(ctx context.Context, timeRanges []*StmtTimeRange) (*stmtFiles, error) {
+	if cfg := config.GetGlobalConfig().Instance; cfg.StmtSummaryEnableCompaction {
+		return newStoreStmtFiles(ctx, storeDir(cfg.StmtSummaryFilename), int64(cfg.StmtSummaryCompactionInterval), timeRanges)
+	}
 	filename := config.GetGlobalConfig().Instance.StmtSummaryFilename
 	ext := filepath.Ext(filename)
 	prefix := filename[:len(filename)-len(ext)]
@@ -592,6 +595,45 @@ func newStmtFiles(ctx context.Context, timeRanges []*StmtTimeRange) (*stmtFiles,
 	return &stmtFiles{files: files}, nil
 }
 
+// newStoreStmtFiles opens the files of the compacted store within the time ranges. The time range
+// of each file is parsed from its name, so the files out of the time ranges are never opened.
+func newStoreStmtFiles(ctx context.Context, dir string, interval int64, timeRanges []*StmtTimeRange) (*stmtFiles, error) {
+	storeFiles, err := listStoreFiles(dir, interval)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &stmtFiles{}, nil
+		}
+		return nil, err
+	}
+	var files []*stmtFile
+	for _, sf := range storeFiles {
+		if isCtxDone(ctx) {
+			for _, f := range files {
+				_ = f.close()
+			}
+			return nil, ctx.Err()
+		}
+		matched := len(timeRanges) == 0
+		for _, tr := range timeRanges {
+			if timeRangeOverlap(sf.begin, sf.end, tr.Begin, tr.End) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		file, err := os.Open(filepath.Join(dir, sf.name)) // #nosec G304
+		if err != nil {
+			// The file may be compacted or purged after listing.
+			logutil.BgLogger().Warn("failed to open statements file", zap.Error(err), zap.String("file", sf.name))
+			continue
+		}
+		files = append(files, &stmtFile{file: file, begin: sf.begin, end: sf.end})
+	}
+	return &stmtFiles{files: files}, nil
+}
+
 func (f *stmtFiles) close() {
 	for _, f := range f.files {
 		_ = f.close()
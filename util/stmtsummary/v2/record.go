@@ -124,6 +124,8 @@ type StmtRecord struct {
 	MaxMem               int64         `json:"max_mem"`
 	SumDisk              int64         `json:"sum_disk"`
 	MaxDisk              int64         `json:"max_disk"`
+	SumDiskSpilled       int64         `json:"sum_disk_spilled"`
+	MaxDiskSpilled       int64         `json:"max_disk_spilled"`
 	SumAffectedRows      uint64        `json:"sum_affected_rows"`
 	SumKVTotal           time.Duration `json:"sum_kv_total"`
 	SumPDTotal           time.Duration `json:"sum_pd_total"`
@@ -380,6 +382,10 @@ func (r *StmtRecord) Add(info *stmtsummary.StmtExecInfo) {
 	if info.DiskMax > r.MaxDisk {
 		r.MaxDisk = info.DiskMax
 	}
+	r.SumDiskSpilled += info.DiskSpilled
+	if info.DiskSpilled > r.MaxDiskSpilled {
+		r.MaxDiskSpilled = info.DiskSpilled
+	}
 	if info.StartTime.Before(r.FirstSeen) {
 		r.FirstSeen = info.StartTime
 	}
@@ -546,6 +552,10 @@ func (r *StmtRecord) Merge(other *StmtRecord) {
 	if r.MaxDisk < other.MaxDisk {
 		r.MaxDisk = other.MaxDisk
 	}
+	r.SumDiskSpilled += other.SumDiskSpilled
+	if r.MaxDiskSpilled < other.MaxDiskSpilled {
+		r.MaxDiskSpilled = other.MaxDiskSpilled
+	}
 	if r.FirstSeen.After(other.FirstSeen) {
 		r.FirstSeen = other.FirstSeen
 	}
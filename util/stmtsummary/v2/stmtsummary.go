@@ -73,6 +73,12 @@ type Config struct {
 	FileMaxSize    int
 	FileMaxDays    int
 	FileMaxBackups int
+
+	// EnableCompaction persists the windows to a compacted store instead of the log files.
+	EnableCompaction   bool
+	CompactionInterval int
+	HistoryMaxDays     int
+	HistoryMaxSize     int
 }
 
 // StmtSummary represents the complete statements summary statistics.
@@ -101,6 +107,27 @@ func NewStmtSummary(cfg *Config) (*StmtSummary, error) {
 		return nil, errors.New("stmtsummary: empty filename")
 	}
 
+	var storage stmtStorage
+	if cfg.EnableCompaction {
+		if cfg.CompactionInterval <= 0 {
+			return nil, errors.New("stmtsummary: invalid compaction interval")
+		}
+		var err error
+		storage, err = newStmtCompactStorage(storeDir(cfg.Filename), int64(cfg.CompactionInterval), cfg.HistoryMaxDays, cfg.HistoryMaxSize)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		storage = newStmtLogStorage(&log.Config{
+			File: log.FileLogConfig{
+				Filename:   cfg.Filename,
+				MaxSize:    cfg.FileMaxSize,
+				MaxDays:    cfg.FileMaxDays,
+				MaxBackups: cfg.FileMaxBackups,
+			},
+		})
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	s := &StmtSummary{
 		ctx:    ctx,
@@ -115,14 +142,7 @@ func NewStmtSummary(cfg *Config) (*StmtSummary, error) {
 		optMaxSQLLength:        atomic2.NewUint32(defaultMaxSQLLength),
 		optRefreshInterval:     atomic2.NewUint32(defaultRefreshInterval),
 		window:                 newStmtWindow(timeNow(), uint(defaultMaxStmtCount)),
-		storage: newStmtLogStorage(&log.Config{
-			File: log.FileLogConfig{
-				Filename:   cfg.Filename,
-				MaxSize:    cfg.FileMaxSize,
-				MaxDays:    cfg.FileMaxDays,
-				MaxBackups: cfg.FileMaxBackups,
-			},
-		}),
+		storage:                storage,
 	}
 
 	s.closeWg.Add(1)
@@ -0,0 +1,331 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	storeFilePrefix    = "stmt-"
+	storeRawFileSuffix = ".raw.log"
+	storeFileSuffix    = ".log"
+)
+
+// storeDir returns the directory of the compacted store, it's next to the statements log file,
+// e.g. `tidb-statements-history` for `tidb-statements.log`.
+func storeDir(filename string) string {
+	ext := filepath.Ext(filename)
+	return filename[:len(filename)-len(ext)] + "-history"
+}
+
+// stmtCompactStorage persists the statistics windows into time buckets of a fixed length. The buckets
+// are aligned to the unix epoch, so the buckets of all TiDB instances share the same boundaries, and
+// the history of the whole cluster is aggregated by bucket and digest in CLUSTER_STATEMENTS_DIGEST_HISTORY.
+//
+// Each bucket has two kinds of files in the store directory:
+//
+//	stmt-<begin>.raw.log      the windows of an incomplete bucket, appended once they are persisted.
+//	stmt-<begin>-<end>.log    the records of a complete bucket, merged by their keys.
+//
+// A window is counted in the bucket its begin time falls in. The raw file of a bucket is compacted
+// once a window begins after the bucket ends. The time range of a file is encoded in its name, so
+// the files out of the time range of a query are skipped without opening them.
+type stmtCompactStorage struct {
+	sync.Mutex
+	dir      string
+	interval int64
+	maxDays  int
+	maxSize  int64
+}
+
+func newStmtCompactStorage(dir string, interval int64, maxDays, maxSizeMB int) (*stmtCompactStorage, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	s := &stmtCompactStorage{
+		dir:      dir,
+		interval: interval,
+		maxDays:  maxDays,
+		maxSize:  int64(maxSizeMB) << 20,
+	}
+	// Compact the buckets left by the last run, so the history is complete after restarting.
+	s.Lock()
+	s.compact(timeNow().Unix())
+	s.Unlock()
+	return s, nil
+}
+
+func (s *stmtCompactStorage) persist(w *stmtWindow, end time.Time) {
+	begin := w.begin.Unix()
+	records := make([]*StmtRecord, 0, w.lru.Size()+1)
+	for _, v := range w.lru.Values() {
+		r := v.(*lockedStmtRecord)
+		r.Lock()
+		r.Begin = begin
+		r.End = end.Unix()
+		records = append(records, r.StmtRecord)
+		r.Unlock()
+	}
+	w.evicted.Lock()
+	if w.evicted.other.ExecCount > 0 {
+		w.evicted.other.Begin = begin
+		w.evicted.other.End = end.Unix()
+		records = append(records, w.evicted.other)
+	}
+	w.evicted.Unlock()
+
+	s.Lock()
+	defer s.Unlock()
+	bucket := begin - begin%s.interval
+	if err := appendRecords(filepath.Join(s.dir, rawStoreFileName(bucket)), records); err != nil {
+		logutil.BgLogger().Warn("failed to persist statement summary", zap.Error(err))
+	}
+	s.compact(begin)
+	s.purge(end)
+}
+
+func (*stmtCompactStorage) sync() error {
+	// The files are closed after each write.
+	return nil
+}
+
+// compact merges the raw files of the buckets ending before `before` into the compacted files.
+func (s *stmtCompactStorage) compact(before int64) {
+	files, err := listStoreFiles(s.dir, s.interval)
+	if err != nil {
+		logutil.BgLogger().Warn("failed to list statement summary store", zap.Error(err))
+		return
+	}
+	for _, f := range files {
+		if !f.raw || f.end > before {
+			continue
+		}
+		if err := s.compactBucket(f.begin, f.end); err != nil {
+			logutil.BgLogger().Warn("failed to compact statement summary",
+				zap.Int64("begin", f.begin), zap.Int64("end", f.end), zap.Error(err))
+		}
+	}
+}
+
+func (s *stmtCompactStorage) compactBucket(begin, end int64) error {
+	rawPath := filepath.Join(s.dir, rawStoreFileName(begin))
+	path := filepath.Join(s.dir, storeFileName(begin, end))
+	records := make(map[string]*StmtRecord)
+	var keys []string
+	merge := func(r *StmtRecord) {
+		key := strings.Join([]string{r.SchemaName, r.Digest, r.PrevSQL, r.PlanDigest}, "\x00")
+		if merged, ok := records[key]; ok {
+			merged.Merge(r)
+			return
+		}
+		r.Begin, r.End = begin, end
+		if r.AuthUsers == nil {
+			r.AuthUsers = make(map[string]struct{})
+		}
+		if r.BackoffTypes == nil {
+			r.BackoffTypes = make(map[string]int)
+		}
+		records[key] = r
+		keys = append(keys, key)
+	}
+	// The compacted file exists if a window of the bucket is persisted after the bucket is compacted.
+	if err := readRecords(path, merge); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := readRecords(rawPath, merge); err != nil {
+		return err
+	}
+	slices.Sort(keys)
+	sorted := make([]*StmtRecord, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, records[key])
+	}
+
+	tmpPath := path + ".tmp"
+	_ = os.Remove(tmpPath)
+	if err := appendRecords(tmpPath, sorted); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return os.Remove(rawPath)
+}
+
+// purge removes the oldest buckets exceeding the max days or the max total size.
+func (s *stmtCompactStorage) purge(now time.Time) {
+	files, err := listStoreFiles(s.dir, s.interval)
+	if err != nil {
+		logutil.BgLogger().Warn("failed to list statement summary store", zap.Error(err))
+		return
+	}
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	var expired int64
+	if s.maxDays > 0 {
+		expired = now.Add(-time.Duration(s.maxDays) * 24 * time.Hour).Unix()
+	}
+	for _, f := range files {
+		if f.raw || (f.end > expired && (s.maxSize <= 0 || total <= s.maxSize)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, f.name)); err != nil {
+			logutil.BgLogger().Warn("failed to purge statement summary", zap.String("file", f.name), zap.Error(err))
+			continue
+		}
+		total -= f.size
+	}
+}
+
+type storeFile struct {
+	name  string
+	begin int64
+	end   int64
+	size  int64
+	raw   bool
+}
+
+func rawStoreFileName(begin int64) string {
+	return storeFilePrefix + strconv.FormatInt(begin, 10) + storeRawFileSuffix
+}
+
+func storeFileName(begin, end int64) string {
+	return storeFilePrefix + strconv.FormatInt(begin, 10) + "-" + strconv.FormatInt(end, 10) + storeFileSuffix
+}
+
+// parseStoreFileName parses the time range of the bucket from the file name, `interval` is used
+// for the raw files whose end isn't in their names.
+func parseStoreFileName(name string, interval int64) (f storeFile, ok bool) {
+	if !strings.HasPrefix(name, storeFilePrefix) {
+		return f, false
+	}
+	f.name = name
+	name = strings.TrimPrefix(name, storeFilePrefix)
+	var err error
+	if strings.HasSuffix(name, storeRawFileSuffix) {
+		f.raw = true
+		f.begin, err = strconv.ParseInt(strings.TrimSuffix(name, storeRawFileSuffix), 10, 64)
+		f.end = f.begin + interval
+		return f, err == nil
+	}
+	if !strings.HasSuffix(name, storeFileSuffix) {
+		return f, false
+	}
+	begin, end, found := strings.Cut(strings.TrimSuffix(name, storeFileSuffix), "-")
+	if !found {
+		return f, false
+	}
+	if f.begin, err = strconv.ParseInt(begin, 10, 64); err != nil {
+		return f, false
+	}
+	if f.end, err = strconv.ParseInt(end, 10, 64); err != nil {
+		return f, false
+	}
+	return f, true
+}
+
+// listStoreFiles returns the files in the store sorted by their begin time.
+func listStoreFiles(dir string, interval int64) ([]storeFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]storeFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		f, ok := parseStoreFileName(entry.Name(), interval)
+		if !ok {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			f.size = info.Size()
+		}
+		files = append(files, f)
+	}
+	slices.SortFunc(files, func(i, j storeFile) bool {
+		if i.begin != j.begin {
+			return i.begin < j.begin
+		}
+		// Read the compacted file before the raw file of the same bucket.
+		return !i.raw && j.raw
+	})
+	return files, nil
+}
+
+func appendRecords(path string, records []*StmtRecord) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	for _, r := range records {
+		b, err := json.Marshal(r)
+		if err != nil {
+			logutil.BgLogger().Warn("failed to marshal statement summary", zap.Error(err))
+			continue
+		}
+		_, _ = writer.Write(b)
+		_ = writer.WriteByte('\n')
+	}
+	if err = writer.Flush(); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func readRecords(path string, fn func(*StmtRecord)) error {
+	file, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	reader := bufio.NewReader(file)
+	for {
+		line, err := readLine(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var record StmtRecord
+		// ignore invalid lines
+		if err := json.Unmarshal(line, &record); err == nil {
+			fn(&record)
+		}
+	}
+}
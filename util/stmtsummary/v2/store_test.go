@@ -0,0 +1,168 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWindow(begin int64, digests ...string) *stmtWindow {
+	w := newStmtWindow(time.Unix(begin, 0), 10)
+	for _, digest := range digests {
+		info := GenerateStmtExecInfo4Test(digest)
+		record := &lockedStmtRecord{StmtRecord: NewStmtRecord(info)}
+		record.Add(info)
+		w.lru.Put(&stmtKey{schemaName: info.SchemaName, digest: info.Digest, planDigest: info.PlanDigest}, record)
+	}
+	return w
+}
+
+func readStoreFile(t *testing.T, dir string, f storeFile) map[string]*StmtRecord {
+	records := make(map[string]*StmtRecord)
+	require.NoError(t, readRecords(filepath.Join(dir, f.name), func(r *StmtRecord) {
+		records[r.Digest] = r
+	}))
+	return records
+}
+
+func TestParseStoreFileName(t *testing.T) {
+	f, ok := parseStoreFileName(rawStoreFileName(3600), 60)
+	require.True(t, ok)
+	require.True(t, f.raw)
+	require.Equal(t, int64(3600), f.begin)
+	require.Equal(t, int64(3660), f.end)
+
+	f, ok = parseStoreFileName(storeFileName(3600, 7200), 60)
+	require.True(t, ok)
+	require.False(t, f.raw)
+	require.Equal(t, int64(3600), f.begin)
+	require.Equal(t, int64(7200), f.end)
+
+	for _, name := range []string{"tidb-statements.log", "stmt-1.log", "stmt-a-b.log", "stmt-3600-7200.log.tmp"} {
+		_, ok = parseStoreFileName(name, 60)
+		require.False(t, ok, name)
+	}
+}
+
+func TestStmtCompactStorage(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newStmtCompactStorage(dir, 3600, 0, 0)
+	require.NoError(t, err)
+
+	s.persist(newTestWindow(3600, "digest1", "digest2"), time.Unix(5400, 0))
+	s.persist(newTestWindow(5400, "digest1"), time.Unix(7200, 0))
+	files, err := listStoreFiles(dir, 3600)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.True(t, files[0].raw)
+	require.Len(t, readStoreFile(t, dir, files[0]), 2)
+
+	// The bucket [3600, 7200) is compacted once a window begins after it.
+	s.persist(newTestWindow(7200, "digest1"), time.Unix(9000, 0))
+	files, err = listStoreFiles(dir, 3600)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.False(t, files[0].raw)
+	require.Equal(t, storeFileName(3600, 7200), files[0].name)
+	require.True(t, files[1].raw)
+	records := readStoreFile(t, dir, files[0])
+	require.Len(t, records, 2)
+	require.Equal(t, int64(2), records["digest1"].ExecCount)
+	require.Equal(t, int64(3600), records["digest1"].Begin)
+	require.Equal(t, int64(7200), records["digest1"].End)
+	require.Equal(t, int64(1), records["digest2"].ExecCount)
+
+	// A window persisted after its bucket is compacted is merged into the compacted file.
+	s.persist(newTestWindow(4000, "digest2"), time.Unix(5000, 0))
+	s.persist(newTestWindow(9000, "digest3"), time.Unix(10800, 0))
+	files, err = listStoreFiles(dir, 3600)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	records = readStoreFile(t, dir, files[0])
+	require.Equal(t, int64(2), records["digest2"].ExecCount)
+
+	// The buckets left by the last run are compacted when the storage is created.
+	s, err = newStmtCompactStorage(dir, 3600, 0, 0)
+	require.NoError(t, err)
+	files, err = listStoreFiles(dir, 3600)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, storeFileName(7200, 10800), files[1].name)
+	require.Len(t, readStoreFile(t, dir, files[1]), 2)
+
+	// Purge by age.
+	s.maxDays = 1
+	s.purge(time.Unix(86400+7200, 0))
+	files, err = listStoreFiles(dir, 3600)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, storeFileName(7200, 10800), files[0].name)
+
+	// Purge by size.
+	s.maxSize = 1
+	s.purge(time.Unix(7200, 0))
+	files, err = listStoreFiles(dir, 3600)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestHistoryReaderWithCompaction(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "tidb-statements.log")
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Instance.StmtSummaryFilename = filename
+		conf.Instance.StmtSummaryEnableCompaction = true
+		conf.Instance.StmtSummaryCompactionInterval = 3600
+	})
+
+	s, err := newStmtCompactStorage(storeDir(filename), 3600, 0, 0)
+	require.NoError(t, err)
+	s.persist(newTestWindow(3600, "digest1", "digest2"), time.Unix(5400, 0))
+	s.persist(newTestWindow(7200, "digest1"), time.Unix(9000, 0))
+	s.persist(newTestWindow(10800, "digest3"), time.Unix(12600, 0))
+
+	// The files out of the time range are skipped by their names.
+	files, err := newStmtFiles(context.Background(), []*StmtTimeRange{{Begin: 7300, End: 7400}})
+	require.NoError(t, err)
+	require.Len(t, files.files, 1)
+	require.Equal(t, int64(7200), files.files[0].begin)
+	require.Equal(t, int64(10800), files.files[0].end)
+	files.close()
+
+	columns := []*model.ColumnInfo{
+		{Name: model.NewCIStr(DigestStr)},
+		{Name: model.NewCIStr(ExecCountStr)},
+	}
+	reader, err := NewHistoryReader(context.Background(), columns, "", time.Local, nil, false, nil, nil, 2)
+	require.NoError(t, err)
+	rows := readAllRows(t, reader)
+	require.NoError(t, reader.Close())
+	require.Len(t, rows, 4)
+
+	reader, err = NewHistoryReader(context.Background(), columns, "", time.Local, nil, false, nil, []*StmtTimeRange{{Begin: 10801, End: 0}}, 2)
+	require.NoError(t, err)
+	rows = readAllRows(t, reader)
+	require.NoError(t, reader.Close())
+	require.Len(t, rows, 1)
+	require.Equal(t, "digest3", rows[0][0].GetString())
+}
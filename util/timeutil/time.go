@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -207,6 +208,37 @@ func ConstructTimeZone(name string, offset int) (*time.Location, error) {
 	return time.FixedZone("", offset), nil
 }
 
+// ParseTimeZone parses the time zone name in the same formats as the variable `time_zone`:
+// "SYSTEM", an IANA time zone name such as "Asia/Shanghai", or an offset from UTC such as "+10:00" or "-6:00".
+// The offset should in [-12:59,+14:00].
+func ParseTimeZone(s string) (*time.Location, error) {
+	if strings.EqualFold(s, "SYSTEM") {
+		return SystemLocation(), nil
+	}
+
+	if loc, err := LoadLocation(s); err == nil {
+		return loc, nil
+	}
+
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		if hour, minute, ok := strings.Cut(s[1:], ":"); ok {
+			h, err1 := strconv.ParseUint(hour, 10, 8)
+			m, err2 := strconv.ParseUint(minute, 10, 8)
+			if err1 == nil && err2 == nil && m < 60 {
+				ofst := int(h*3600 + m*60)
+				if (s[0] == '+' && ofst <= 14*3600) || (s[0] == '-' && ofst <= 12*3600+59*60) {
+					if s[0] == '-' {
+						ofst = -ofst
+					}
+					return time.FixedZone("", ofst), nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unknown or incorrect time zone: '%s'", s)
+}
+
 // WithinDayTimePeriod tests whether `now` is between `start` and `end`.
 func WithinDayTimePeriod(start, end, now time.Time) bool {
 	// Converts to UTC and only keeps the hour and minute info.
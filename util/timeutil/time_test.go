@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -80,3 +81,35 @@ func TestInferOneStepLinkForPath(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEqual(t, -1, strings.Index(link3, link1.Name()))
 }
+
+func TestParseTimeZone(t *testing.T) {
+	loc, err := ParseTimeZone("Asia/Shanghai")
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Shanghai", loc.String())
+
+	loc, err = ParseTimeZone("system")
+	require.NoError(t, err)
+	require.Equal(t, SystemLocation(), loc)
+
+	cases := []struct {
+		tz     string
+		offset int
+	}{
+		{tz: "+08:00", offset: 8 * 3600},
+		{tz: "-6:30", offset: -(6*3600 + 30*60)},
+		{tz: "+14:00", offset: 14 * 3600},
+		{tz: "-12:59", offset: -(12*3600 + 59*60)},
+	}
+
+	for _, c := range cases {
+		loc, err = ParseTimeZone(c.tz)
+		require.NoError(t, err, c.tz)
+		_, offset := time.Date(2023, 1, 1, 0, 0, 0, 0, loc).Zone()
+		require.Equal(t, c.offset, offset, c.tz)
+	}
+
+	for _, tz := range []string{"Invalid/Zone", "+14:01", "-13:00", "+8", "+08:60", "08:00"} {
+		_, err = ParseTimeZone(tz)
+		require.EqualError(t, err, "unknown or incorrect time zone: '"+tz+"'")
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/logutil"
+	reporter_metrics "github.com/pingcap/tidb/util/topsql/reporter/metrics"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	fileRecordTypeRecord = "record"
+	fileRecordTypeSQL    = "sql_meta"
+	fileRecordTypePlan   = "plan_meta"
+)
+
+// FileDataSink writes the TopSQL data to a local file in JSON lines, so TopSQL can be used without
+// ng-monitoring. The file is rotated by its size, and the rotated files are removed by their age and number.
+//
+// Each line is one of the following objects, distinguished by the `type` field:
+//
+//	{"type":"record","sql_digest":"..","plan_digest":"..","timestamp":1689840000,"cpu_time_ms":10,"exec_count":2,...}
+//	{"type":"sql_meta","sql_digest":"..","normalized_sql":"..","is_internal":false}
+//	{"type":"plan_meta","plan_digest":"..","normalized_plan":"..","encoded_normalized_plan":".."}
+type FileDataSink struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	registerer DataSinkRegisterer
+	logger     *zap.Logger
+	sendTaskCh chan sendTask
+	wg         sync.WaitGroup
+}
+
+// NewFileDataSink returns a new FileDataSink.
+func NewFileDataSink(registerer DataSinkRegisterer, cfg *config.TopSQLFileSink) (*FileDataSink, error) {
+	_, prop, err := log.InitLogger(&log.Config{
+		File: log.FileLogConfig{
+			Filename:   cfg.Filename,
+			MaxSize:    cfg.MaxSize,
+			MaxDays:    cfg.MaxDays,
+			MaxBackups: cfg.MaxBackups,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The encoder config without keys omits the time, level and message, so only the fields are written.
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), prop.Syncer, zapcore.InfoLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &FileDataSink{
+		ctx:        ctx,
+		cancel:     cancel,
+		registerer: registerer,
+		logger:     zap.New(core),
+		sendTaskCh: make(chan sendTask, 1),
+	}, nil
+}
+
+// Start registers the FileDataSink and starts to write the data.
+func (ds *FileDataSink) Start() error {
+	if err := ds.registerer.Register(ds); err != nil {
+		return err
+	}
+	ds.wg.Add(1)
+	go func() {
+		defer ds.wg.Done()
+		ds.run()
+	}()
+	return nil
+}
+
+func (ds *FileDataSink) run() {
+	for {
+		select {
+		case <-ds.ctx.Done():
+			return
+		case task := <-ds.sendTaskCh:
+			start := time.Now()
+			ds.write(task.data)
+			reporter_metrics.ReportAllDurationSuccHistogram.Observe(time.Since(start).Seconds())
+		}
+	}
+}
+
+func (ds *FileDataSink) write(data *ReportData) {
+	for _, meta := range data.SQLMetas {
+		ds.logger.Info("",
+			zap.String("type", fileRecordTypeSQL),
+			zap.String("sql_digest", hex.EncodeToString(meta.SqlDigest)),
+			zap.String("normalized_sql", meta.NormalizedSql),
+			zap.Bool("is_internal", meta.IsInternalSql))
+	}
+	for _, meta := range data.PlanMetas {
+		ds.logger.Info("",
+			zap.String("type", fileRecordTypePlan),
+			zap.String("plan_digest", hex.EncodeToString(meta.PlanDigest)),
+			zap.String("normalized_plan", meta.NormalizedPlan),
+			zap.String("encoded_normalized_plan", meta.EncodedNormalizedPlan))
+	}
+	for _, record := range data.DataRecords {
+		sqlDigest, planDigest := hex.EncodeToString(record.SqlDigest), hex.EncodeToString(record.PlanDigest)
+		for _, item := range record.Items {
+			ds.logger.Info("",
+				zap.String("type", fileRecordTypeRecord),
+				zap.String("sql_digest", sqlDigest),
+				zap.String("plan_digest", planDigest),
+				zap.Uint64("timestamp", item.TimestampSec),
+				zap.Uint32("cpu_time_ms", item.CpuTimeMs),
+				zap.Uint64("exec_count", item.StmtExecCount),
+				zap.Uint64("duration_sum_ns", item.StmtDurationSumNs),
+				zap.Uint64("duration_count", item.StmtDurationCount),
+				zap.Any("kv_exec_count", item.StmtKvExecCount))
+		}
+	}
+	if err := ds.logger.Sync(); err != nil {
+		logutil.BgLogger().Warn("file dataSink failed to sync the file", zap.String("category", "top-sql"), zap.Error(err))
+	}
+}
+
+var _ DataSink = &FileDataSink{}
+
+// TrySend implements the DataSink interface.
+func (ds *FileDataSink) TrySend(data *ReportData, deadline time.Time) error {
+	select {
+	case ds.sendTaskCh <- sendTask{data: data, deadline: deadline}:
+		return nil
+	case <-ds.ctx.Done():
+		return ds.ctx.Err()
+	default:
+		reporter_metrics.IgnoreReportChannelFullCounter.Inc()
+		return errors.New("the channel of file dataSink is full")
+	}
+}
+
+// OnReporterClosing implements the DataSink interface.
+func (ds *FileDataSink) OnReporterClosing() {
+	ds.cancel()
+}
+
+// Close stops writing the data and deregisters the FileDataSink.
+func (ds *FileDataSink) Close() {
+	ds.cancel()
+	ds.wg.Wait()
+	ds.registerer.Deregister(ds)
+	_ = ds.logger.Sync()
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tipb/go-tipb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDataSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "topsql.log")
+	registerer := NewDefaultDataSinkRegisterer(context.Background())
+	ds, err := NewFileDataSink(&registerer, &config.TopSQLFileSink{Enable: true, Filename: filename, MaxSize: 1})
+	require.NoError(t, err)
+	require.NoError(t, ds.Start())
+	require.Len(t, registerer.dataSinks, 1)
+
+	err = ds.TrySend(&ReportData{
+		DataRecords: []tipb.TopSQLRecord{{
+			SqlDigest:  []byte("S1"),
+			PlanDigest: []byte("P1"),
+			Items: []*tipb.TopSQLRecordItem{
+				{TimestampSec: 1, CpuTimeMs: 10, StmtExecCount: 2},
+				{TimestampSec: 2, CpuTimeMs: 20, StmtExecCount: 3},
+			},
+		}},
+		SQLMetas:  []tipb.SQLMeta{{SqlDigest: []byte("S1"), NormalizedSql: "select ?"}},
+		PlanMetas: []tipb.PlanMeta{{PlanDigest: []byte("P1"), NormalizedPlan: "plan"}},
+	}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	var lines [][]byte
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return false
+		}
+		lines = bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+		return len(lines) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	ds.Close()
+	require.Empty(t, registerer.dataSinks)
+
+	var objs []map[string]interface{}
+	for _, line := range lines {
+		obj := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(line, &obj))
+		objs = append(objs, obj)
+	}
+	require.Equal(t, fileRecordTypeSQL, objs[0]["type"])
+	require.Equal(t, "5331", objs[0]["sql_digest"])
+	require.Equal(t, "select ?", objs[0]["normalized_sql"])
+	require.Equal(t, fileRecordTypePlan, objs[1]["type"])
+	require.Equal(t, "plan", objs[1]["normalized_plan"])
+	require.Equal(t, fileRecordTypeRecord, objs[2]["type"])
+	require.Equal(t, "5331", objs[2]["sql_digest"])
+	require.Equal(t, "5031", objs[2]["plan_digest"])
+	require.Equal(t, float64(1), objs[2]["timestamp"])
+	require.Equal(t, float64(10), objs[2]["cpu_time_ms"])
+	require.Equal(t, float64(3), objs[3]["exec_count"])
+	// The time, level and message aren't written.
+	require.NotContains(t, objs[3], "level")
+	require.NotContains(t, objs[3], "time")
+}
@@ -0,0 +1,315 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/otlpclient"
+	reporter_metrics "github.com/pingcap/tidb/util/topsql/reporter/metrics"
+	"github.com/pingcap/tipb/go-tipb"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxSQLTextLabelLen is the max length of the normalized SQL in the labels of the OTLP metrics.
+	maxSQLTextLabelLen = 256
+	// maxCachedSQLTexts is the max number of the normalized SQL cached by the MetricsDataSink.
+	maxCachedSQLTexts = 10000
+)
+
+// topSQLStat is the resource usage of a SQL and plan in a report interval.
+type topSQLStat struct {
+	sqlDigest     []byte
+	planDigest    []byte
+	cpuTimeMs     uint64
+	execCount     uint64
+	durationSumNs uint64
+}
+
+// aggregateTopN sums up the items of each record, and returns the top N stats by the CPU time. The others are
+// aggregated into one stat with empty digests at the end. `begin` and `end` are the time range of the items.
+func aggregateTopN(records []tipb.TopSQLRecord, n int) (stats []topSQLStat, begin, end uint64) {
+	stats = make([]topSQLStat, 0, len(records))
+	var others topSQLStat
+	for _, record := range records {
+		stat := topSQLStat{sqlDigest: record.SqlDigest, planDigest: record.PlanDigest}
+		for _, item := range record.Items {
+			stat.cpuTimeMs += uint64(item.CpuTimeMs)
+			stat.execCount += item.StmtExecCount
+			stat.durationSumNs += item.StmtDurationSumNs
+			if begin == 0 || item.TimestampSec < begin {
+				begin = item.TimestampSec
+			}
+			if item.TimestampSec+1 > end {
+				end = item.TimestampSec + 1
+			}
+		}
+		if len(record.SqlDigest) == 0 {
+			others.merge(&stat)
+			continue
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].cpuTimeMs > stats[j].cpuTimeMs })
+	if len(stats) > n {
+		for i := n; i < len(stats); i++ {
+			others.merge(&stats[i])
+		}
+		stats = stats[:n]
+	}
+	if others.cpuTimeMs > 0 || others.execCount > 0 {
+		stats = append(stats, others)
+	}
+	return stats, begin, end
+}
+
+func (s *topSQLStat) merge(other *topSQLStat) {
+	s.cpuTimeMs += other.cpuTimeMs
+	s.execCount += other.execCount
+	s.durationSumNs += other.durationSumNs
+}
+
+// MetricsDataSink exposes the top N SQL of each report interval as metrics, by the Prometheus metrics of the
+// status port and/or the OTLP metrics pushed to a collector.
+type MetricsDataSink struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	registerer DataSinkRegisterer
+	sendTaskCh chan sendTask
+	wg         sync.WaitGroup
+
+	topN             int
+	enablePrometheus bool
+	exporter         *otlpMetricsExporter
+	resource         *resourcepb.Resource
+	// sqlTexts caches the normalized SQL of the digests, because the SQL meta is only reported once.
+	sqlTexts map[string]string
+}
+
+// NewMetricsDataSink returns a new MetricsDataSink.
+func NewMetricsDataSink(registerer DataSinkRegisterer, cfg *config.TopSQLMetricsSink) (*MetricsDataSink, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ds := &MetricsDataSink{
+		ctx:              ctx,
+		cancel:           cancel,
+		registerer:       registerer,
+		sendTaskCh:       make(chan sendTask, 1),
+		topN:             cfg.TopN,
+		enablePrometheus: cfg.EnablePrometheus,
+		sqlTexts:         make(map[string]string),
+	}
+	if ds.topN <= 0 {
+		ds.topN = 10
+	}
+	if cfg.OTLPEndpoint != "" {
+		exporter, err := newOTLPMetricsExporter(cfg)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		ds.exporter = exporter
+		globalCfg := config.GetGlobalConfig()
+		ds.resource = &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+			stringKeyValue("service.name", "tidb"),
+			stringKeyValue("service.instance.id", net.JoinHostPort(globalCfg.AdvertiseAddress, strconv.Itoa(int(globalCfg.Port)))),
+		}}
+	}
+	return ds, nil
+}
+
+// Start registers the MetricsDataSink and starts to expose the metrics.
+func (ds *MetricsDataSink) Start() error {
+	if err := ds.registerer.Register(ds); err != nil {
+		return err
+	}
+	ds.wg.Add(1)
+	go func() {
+		defer ds.wg.Done()
+		ds.run()
+	}()
+	return nil
+}
+
+func (ds *MetricsDataSink) run() {
+	for {
+		select {
+		case <-ds.ctx.Done():
+			return
+		case task := <-ds.sendTaskCh:
+			ds.doSend(task)
+		}
+	}
+}
+
+func (ds *MetricsDataSink) doSend(task sendTask) {
+	for _, meta := range task.data.SQLMetas {
+		if len(ds.sqlTexts) >= maxCachedSQLTexts {
+			ds.sqlTexts = make(map[string]string)
+		}
+		sql := meta.NormalizedSql
+		if len(sql) > maxSQLTextLabelLen {
+			sql = sql[:maxSQLTextLabelLen]
+		}
+		ds.sqlTexts[string(meta.SqlDigest)] = sql
+	}
+	stats, begin, end := aggregateTopN(task.data.DataRecords, ds.topN)
+	if ds.enablePrometheus {
+		metrics.TopSQLTopNCPUTimeGauge.Reset()
+		metrics.TopSQLTopNExecCountGauge.Reset()
+		for i := range stats {
+			sqlDigest, planDigest := hex.EncodeToString(stats[i].sqlDigest), hex.EncodeToString(stats[i].planDigest)
+			metrics.TopSQLTopNCPUTimeGauge.WithLabelValues(sqlDigest, planDigest).Set(float64(stats[i].cpuTimeMs))
+			metrics.TopSQLTopNExecCountGauge.WithLabelValues(sqlDigest, planDigest).Set(float64(stats[i].execCount))
+		}
+	}
+	if ds.exporter == nil || len(stats) == 0 {
+		return
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithDeadline(ds.ctx, task.deadline)
+	defer cancel()
+	err := ds.exporter.Export(ctx, ds.buildResourceMetrics(stats, begin, end))
+	if err != nil {
+		logutil.BgLogger().Warn("metrics dataSink failed to export OTLP metrics", zap.String("category", "top-sql"), zap.Error(err))
+		reporter_metrics.ReportAllDurationFailedHistogram.Observe(time.Since(start).Seconds())
+	} else {
+		reporter_metrics.ReportAllDurationSuccHistogram.Observe(time.Since(start).Seconds())
+	}
+}
+
+func (ds *MetricsDataSink) buildResourceMetrics(stats []topSQLStat, begin, end uint64) []*metricspb.ResourceMetrics {
+	startNano, endNano := uint64(time.Unix(int64(begin), 0).UnixNano()), uint64(time.Unix(int64(end), 0).UnixNano())
+	cpuTime := make([]*metricspb.IntDataPoint, 0, len(stats))
+	execCount := make([]*metricspb.IntDataPoint, 0, len(stats))
+	duration := make([]*metricspb.IntDataPoint, 0, len(stats))
+	for i := range stats {
+		labels := []*commonpb.StringKeyValue{
+			{Key: metrics.LblSQLDigest, Value: hex.EncodeToString(stats[i].sqlDigest)},
+			{Key: metrics.LblPlanDigest, Value: hex.EncodeToString(stats[i].planDigest)},
+		}
+		if sql, ok := ds.sqlTexts[string(stats[i].sqlDigest)]; ok {
+			labels = append(labels, &commonpb.StringKeyValue{Key: "sql_text", Value: sql})
+		}
+		newPoint := func(v uint64) *metricspb.IntDataPoint {
+			return &metricspb.IntDataPoint{Labels: labels, StartTimeUnixNano: startNano, TimeUnixNano: endNano, Value: int64(v)}
+		}
+		cpuTime = append(cpuTime, newPoint(stats[i].cpuTimeMs))
+		execCount = append(execCount, newPoint(stats[i].execCount))
+		duration = append(duration, newPoint(stats[i].durationSumNs))
+	}
+	newSum := func(name, desc, unit string, points []*metricspb.IntDataPoint) *metricspb.Metric {
+		return &metricspb.Metric{
+			Name:        name,
+			Description: desc,
+			Unit:        unit,
+			Data: &metricspb.Metric_IntSum{IntSum: &metricspb.IntSum{
+				DataPoints:             points,
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+				IsMonotonic:            true,
+			}},
+		}
+	}
+	return []*metricspb.ResourceMetrics{{
+		Resource: ds.resource,
+		InstrumentationLibraryMetrics: []*metricspb.InstrumentationLibraryMetrics{{
+			InstrumentationLibrary: &commonpb.InstrumentationLibrary{Name: "github.com/pingcap/tidb/util/topsql"},
+			Metrics: []*metricspb.Metric{
+				newSum("tidb_topsql_cpu_time", "CPU time of the top N SQL", "ms", cpuTime),
+				newSum("tidb_topsql_exec_count", "Execution count of the top N SQL", "1", execCount),
+				newSum("tidb_topsql_duration", "Total execution duration of the top N SQL", "ns", duration),
+			},
+		}},
+	}}
+}
+
+var _ DataSink = &MetricsDataSink{}
+
+// TrySend implements the DataSink interface.
+func (ds *MetricsDataSink) TrySend(data *ReportData, deadline time.Time) error {
+	select {
+	case ds.sendTaskCh <- sendTask{data: data, deadline: deadline}:
+		return nil
+	case <-ds.ctx.Done():
+		return ds.ctx.Err()
+	default:
+		reporter_metrics.IgnoreReportChannelFullCounter.Inc()
+		return errors.New("the channel of metrics dataSink is full")
+	}
+}
+
+// OnReporterClosing implements the DataSink interface.
+func (ds *MetricsDataSink) OnReporterClosing() {
+	ds.cancel()
+}
+
+// Close stops exposing the metrics and deregisters the MetricsDataSink.
+func (ds *MetricsDataSink) Close() {
+	ds.cancel()
+	ds.wg.Wait()
+	ds.registerer.Deregister(ds)
+	if ds.exporter != nil {
+		if err := ds.exporter.Close(); err != nil {
+			logutil.BgLogger().Warn("metrics dataSink failed to close OTLP exporter", zap.String("category", "top-sql"), zap.Error(err))
+		}
+	}
+}
+
+func stringKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+// otlpMetricsExporter exports the metrics to an OTLP collector.
+type otlpMetricsExporter struct {
+	client otlpclient.Client
+}
+
+func newOTLPMetricsExporter(cfg *config.TopSQLMetricsSink) (*otlpMetricsExporter, error) {
+	client, err := otlpclient.New(otlpclient.Config{
+		Protocol: cfg.OTLPProtocol,
+		Endpoint: cfg.OTLPEndpoint,
+		Insecure: cfg.OTLPInsecure,
+		Headers:  cfg.OTLPHeaders,
+	}, otlpclient.MetricsService)
+	if err != nil {
+		return nil, err
+	}
+	return &otlpMetricsExporter{client: client}, nil
+}
+
+// Export sends the metrics to the collector.
+func (e *otlpMetricsExporter) Export(ctx context.Context, metrics []*metricspb.ResourceMetrics) error {
+	return e.client.Export(ctx, &colmetricspb.ExportMetricsServiceRequest{ResourceMetrics: metrics}, &colmetricspb.ExportMetricsServiceResponse{})
+}
+
+// Close closes the connections to the collector.
+func (e *otlpMetricsExporter) Close() error {
+	return e.client.Close()
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tipb/go-tipb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestRecord(sqlDigest, planDigest string, ts uint64, cpuTimeMs uint32, execCount uint64) tipb.TopSQLRecord {
+	record := tipb.TopSQLRecord{
+		PlanDigest: []byte(planDigest),
+		Items: []*tipb.TopSQLRecordItem{{
+			TimestampSec:      ts,
+			CpuTimeMs:         cpuTimeMs,
+			StmtExecCount:     execCount,
+			StmtDurationSumNs: execCount * 1000,
+		}},
+	}
+	if sqlDigest != "" {
+		record.SqlDigest = []byte(sqlDigest)
+	}
+	return record
+}
+
+func TestAggregateTopN(t *testing.T) {
+	records := []tipb.TopSQLRecord{
+		newTestRecord("S1", "P1", 10, 10, 1),
+		newTestRecord("S2", "P2", 11, 30, 2),
+		newTestRecord("S3", "P3", 12, 20, 3),
+		newTestRecord("S4", "P4", 13, 5, 4),
+		newTestRecord("", "", 9, 1, 5),
+	}
+	records[0].Items = append(records[0].Items, &tipb.TopSQLRecordItem{TimestampSec: 11, CpuTimeMs: 25, StmtExecCount: 1})
+
+	stats, begin, end := aggregateTopN(records, 2)
+	require.Equal(t, uint64(9), begin)
+	require.Equal(t, uint64(14), end)
+	require.Len(t, stats, 3)
+	require.Equal(t, "S1", string(stats[0].sqlDigest))
+	require.Equal(t, uint64(35), stats[0].cpuTimeMs)
+	require.Equal(t, uint64(2), stats[0].execCount)
+	require.Equal(t, "S2", string(stats[1].sqlDigest))
+	require.Equal(t, uint64(30), stats[1].cpuTimeMs)
+	// The others are S3, S4 and the original others.
+	require.Empty(t, stats[2].sqlDigest)
+	require.Equal(t, uint64(26), stats[2].cpuTimeMs)
+	require.Equal(t, uint64(12), stats[2].execCount)
+	require.Equal(t, uint64(12000), stats[2].durationSumNs)
+
+	stats, _, _ = aggregateTopN(records[:2], 10)
+	require.Len(t, stats, 2)
+}
+
+func TestMetricsDataSinkPrometheus(t *testing.T) {
+	registerer := NewDefaultDataSinkRegisterer(context.Background())
+	ds, err := NewMetricsDataSink(&registerer, &config.TopSQLMetricsSink{TopN: 1, EnablePrometheus: true})
+	require.NoError(t, err)
+	require.NoError(t, ds.Start())
+	defer ds.Close()
+
+	data := &ReportData{DataRecords: []tipb.TopSQLRecord{
+		newTestRecord("S1", "P1", 10, 10, 1),
+		newTestRecord("S2", "P2", 10, 30, 2),
+	}}
+	require.NoError(t, ds.TrySend(data, time.Now().Add(time.Minute)))
+	require.Eventually(t, func() bool {
+		return testutil.CollectAndCount(metrics.TopSQLTopNCPUTimeGauge) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, float64(30), testutil.ToFloat64(metrics.TopSQLTopNCPUTimeGauge.WithLabelValues("5332", "5032")))
+	require.Equal(t, float64(2), testutil.ToFloat64(metrics.TopSQLTopNExecCountGauge.WithLabelValues("5332", "5032")))
+	require.Equal(t, float64(10), testutil.ToFloat64(metrics.TopSQLTopNCPUTimeGauge.WithLabelValues("", "")))
+}
+
+func TestMetricsDataSinkOTLP(t *testing.T) {
+	reqCh := make(chan *colmetricspb.ExportMetricsServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/metrics", r.URL.Path)
+		require.Equal(t, "secret", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := &colmetricspb.ExportMetricsServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, req))
+		reqCh <- req
+	}))
+	defer server.Close()
+
+	registerer := NewDefaultDataSinkRegisterer(context.Background())
+	ds, err := NewMetricsDataSink(&registerer, &config.TopSQLMetricsSink{
+		TopN:         10,
+		OTLPEndpoint: server.URL,
+		OTLPProtocol: "http",
+		OTLPHeaders:  map[string]string{"Authorization": "secret"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ds.Start())
+	defer ds.Close()
+
+	data := &ReportData{
+		DataRecords: []tipb.TopSQLRecord{newTestRecord("S1", "P1", 10, 10, 1)},
+		SQLMetas:    []tipb.SQLMeta{{SqlDigest: []byte("S1"), NormalizedSql: "select ?"}},
+	}
+	require.NoError(t, ds.TrySend(data, time.Now().Add(time.Minute)))
+	var req *colmetricspb.ExportMetricsServiceRequest
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timeout waiting for the OTLP request")
+	}
+	require.Len(t, req.ResourceMetrics, 1)
+	ms := req.ResourceMetrics[0].InstrumentationLibraryMetrics[0].Metrics
+	require.Len(t, ms, 3)
+	require.Equal(t, "tidb_topsql_cpu_time", ms[0].Name)
+	points := ms[0].GetIntSum().DataPoints
+	require.Len(t, points, 1)
+	require.Equal(t, int64(10), points[0].Value)
+	require.Equal(t, uint64(10*time.Second), points[0].StartTimeUnixNano)
+	require.Equal(t, uint64(11*time.Second), points[0].TimeUnixNano)
+	labels := make(map[string]string)
+	for _, l := range points[0].Labels {
+		labels[l.Key] = l.Value
+	}
+	require.Equal(t, map[string]string{"sql_digest": "5331", "plan_digest": "5031", "sql_text": "select ?"}, labels)
+	require.Equal(t, int64(1), ms[1].GetIntSum().DataPoints[0].Value)
+	require.Equal(t, int64(1000), ms[2].GetIntSum().DataPoints[0].Value)
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/plancodec"
@@ -42,6 +43,8 @@ const (
 var (
 	globalTopSQLReport   reporter.TopSQLReporter
 	singleTargetDataSink *reporter.SingleTargetDataSink
+	fileDataSink         *reporter.FileDataSink
+	metricsDataSink      *reporter.MetricsDataSink
 )
 
 func init() {
@@ -54,11 +57,43 @@ func init() {
 func SetupTopSQL() {
 	globalTopSQLReport.Start()
 	singleTargetDataSink.Start()
+	setupLocalDataSinks()
 
 	stmtstats.RegisterCollector(globalTopSQLReport)
 	stmtstats.SetupAggregator()
 }
 
+// setupLocalDataSinks sets up the data sinks which don't need ng-monitoring, they enable Top SQL once started.
+func setupLocalDataSinks() {
+	register, ok := globalTopSQLReport.(reporter.DataSinkRegisterer)
+	if !ok {
+		return
+	}
+	cfg := config.GetGlobalConfig().TopSQL
+	if cfg.FileSink.Enable {
+		ds, err := reporter.NewFileDataSink(register, &cfg.FileSink)
+		if err == nil {
+			err = ds.Start()
+		}
+		if err != nil {
+			logutil.BgLogger().Warn("failed to start file dataSink", zap.String("category", "top-sql"), zap.Error(err))
+		} else {
+			fileDataSink = ds
+		}
+	}
+	if cfg.MetricsSink.EnablePrometheus || cfg.MetricsSink.OTLPEndpoint != "" {
+		ds, err := reporter.NewMetricsDataSink(register, &cfg.MetricsSink)
+		if err == nil {
+			err = ds.Start()
+		}
+		if err != nil {
+			logutil.BgLogger().Warn("failed to start metrics dataSink", zap.String("category", "top-sql"), zap.Error(err))
+		} else {
+			metricsDataSink = ds
+		}
+	}
+}
+
 // SetupTopSQLForTest sets up the global top-sql reporter, it's exporting for test.
 func SetupTopSQLForTest(r reporter.TopSQLReporter) {
 	globalTopSQLReport = r
@@ -75,6 +110,12 @@ func RegisterPubSubServer(s *grpc.Server) {
 // Close uses to close and release the top sql resource.
 func Close() {
 	singleTargetDataSink.Close()
+	if fileDataSink != nil {
+		fileDataSink.Close()
+	}
+	if metricsDataSink != nil {
+		metricsDataSink.Close()
+	}
 	globalTopSQLReport.Close()
 	stmtstats.CloseAggregator()
 }
@@ -0,0 +1,132 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/otlpclient"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// ProtocolGRPC exports the spans to an OTLP/gRPC endpoint.
+	ProtocolGRPC = otlpclient.ProtocolGRPC
+	// ProtocolHTTP exports the spans to an OTLP/HTTP endpoint in the binary protobuf encoding.
+	ProtocolHTTP = otlpclient.ProtocolHTTP
+	// ProtocolFile writes the spans to a file in the OTLP JSON encoding, one request per line.
+	ProtocolFile = "file"
+	// ProtocolStdout writes the spans to the stdout in the OTLP JSON encoding, one request per line.
+	ProtocolStdout = "stdout"
+)
+
+// ExporterConfig is the config of an Exporter.
+type ExporterConfig struct {
+	Protocol string
+	// Endpoint is the address of the collector. It's `host:port` for gRPC, and a URL for HTTP.
+	Endpoint string
+	// Insecure disables the TLS for gRPC.
+	Insecure bool
+	// Headers are sent with every export request, they're usually used for authentication.
+	Headers map[string]string
+	// FilePath is the path of the file for the file protocol.
+	FilePath string
+	// Timeout is the timeout of each export request for gRPC and HTTP.
+	Timeout time.Duration
+}
+
+// Exporter exports the spans to the collector.
+type Exporter interface {
+	Export(ctx context.Context, spans []*tracepb.ResourceSpans) error
+	Close() error
+}
+
+// NewExporter creates an Exporter by the config.
+func NewExporter(cfg ExporterConfig) (Exporter, error) {
+	switch strings.ToLower(cfg.Protocol) {
+	case ProtocolGRPC, ProtocolHTTP:
+		client, err := otlpclient.New(otlpclient.Config{
+			Protocol: cfg.Protocol,
+			Endpoint: cfg.Endpoint,
+			Insecure: cfg.Insecure,
+			Headers:  cfg.Headers,
+			Timeout:  cfg.Timeout,
+		}, otlpclient.TraceService)
+		if err != nil {
+			return nil, err
+		}
+		return &clientExporter{client: client}, nil
+	case ProtocolFile:
+		f, err := os.OpenFile(cfg.FilePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &writerExporter{w: f, closer: f}, nil
+	case ProtocolStdout:
+		return &writerExporter{w: os.Stdout}, nil
+	}
+	return nil, errors.Errorf("unknown OTLP protocol %s, it should be one of grpc, http, file or stdout", cfg.Protocol)
+}
+
+// clientExporter exports the spans by the OTLP/gRPC or OTLP/HTTP client.
+type clientExporter struct {
+	client otlpclient.Client
+}
+
+// Export implements the Exporter interface.
+func (e *clientExporter) Export(ctx context.Context, spans []*tracepb.ResourceSpans) error {
+	return e.client.Export(ctx, &coltracepb.ExportTraceServiceRequest{ResourceSpans: spans}, &coltracepb.ExportTraceServiceResponse{})
+}
+
+// Close implements the Exporter interface.
+func (e *clientExporter) Close() error {
+	return e.client.Close()
+}
+
+// writerExporter writes the spans in the OTLP JSON encoding, it's used for the local testing.
+type writerExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// Export implements the Exporter interface.
+func (e *writerExporter) Export(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	data, err := protojson.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data = append(data, '\n')
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(data)
+	return errors.Trace(err)
+}
+
+// Close implements the Exporter interface.
+func (e *writerExporter) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
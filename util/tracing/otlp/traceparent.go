@@ -0,0 +1,95 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// TraceParentKey is the name of the W3C trace context, it's used as the key in the query comment and
+// the connection attribute.
+const TraceParentKey = "traceparent"
+
+// traceFlagSampled is the `sampled` flag of the W3C trace context.
+const traceFlagSampled = 0x01
+
+var (
+	commentRegexp     = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	traceParentRegexp = regexp.MustCompile(`traceparent\s*=\s*'?([0-9a-fA-F-]{55})'?`)
+)
+
+// SpanContext is the W3C trace context of a span from the client.
+// See https://www.w3.org/TR/trace-context/#traceparent-header.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// ParseTraceParent parses the `traceparent` in the format of `version-traceid-parentid-flags`.
+func ParseTraceParent(s string) (sc SpanContext, ok bool) {
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+	// The version `ff` is invalid, and the version `00` must have exactly 4 parts.
+	if strings.EqualFold(parts[0], "ff") || (parts[0] == "00" && len(parts) != 4) {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	var flags [1]byte
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return sc, false
+	}
+	if sc.TraceID == [16]byte{} || sc.SpanID == [8]byte{} {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&traceFlagSampled != 0
+	return sc, true
+}
+
+// String formats the span context as a `traceparent`.
+func (sc SpanContext) String() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(sc.TraceID[:]) + "-" + hex.EncodeToString(sc.SpanID[:]) + "-" + flags
+}
+
+// TraceParentFromSQL returns the `traceparent` in the comments of the SQL, such as the one added by sqlcommenter:
+//
+//	SELECT * FROM t /*traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'*/
+//
+// It returns an empty string if there isn't one.
+func TraceParentFromSQL(sql string) string {
+	if !strings.Contains(sql, TraceParentKey) {
+		return ""
+	}
+	for _, comment := range commentRegexp.FindAllString(sql, -1) {
+		if m := traceParentRegexp.FindStringSubmatch(comment); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
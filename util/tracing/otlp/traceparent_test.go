@@ -0,0 +1,70 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	sc, ok := ParseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.True(t, ok)
+	require.Equal(t, "0af7651916cd43dd8448eb211c80319c", hex.EncodeToString(sc.TraceID[:]))
+	require.Equal(t, "b7ad6b7169203331", hex.EncodeToString(sc.SpanID[:]))
+	require.True(t, sc.Sampled)
+	require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", sc.String())
+
+	sc, ok = ParseTraceParent(" 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00 ")
+	require.True(t, ok)
+	require.False(t, sc.Sampled)
+	// the future versions may have more fields
+	_, ok = ParseTraceParent("01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-abc")
+	require.True(t, ok)
+
+	for _, s := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-abc",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0af7651916cd43dd8448eb211c80319x-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b716920333-01",
+	} {
+		_, ok = ParseTraceParent(s)
+		require.False(t, ok, s)
+	}
+}
+
+func TestTraceParentFromSQL(t *testing.T) {
+	tp := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	cases := []struct {
+		sql      string
+		expected string
+	}{
+		{"select 1", ""},
+		{"select * from t /*traceparent='" + tp + "'*/", tp},
+		{"/* action='index',traceparent='" + tp + "' */ select * from t", tp},
+		{"select * from t /* traceparent = " + tp + " */", tp},
+		{"select * from t where a = 'traceparent=" + tp + "'", ""},
+		{"select * from t /* traceparent='invalid' */", ""},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, TraceParentFromSQL(c.sql), c.sql)
+	}
+}
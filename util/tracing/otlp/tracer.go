@@ -0,0 +1,301 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/basictracer-go"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/pingcap/tidb/util/logutil"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.uber.org/zap"
+)
+
+const (
+	// baggageTraceID and baggageParentID carry the W3C trace context from the client. The baggage of basictracer
+	// is propagated to all the descendant spans, but only the root span uses the parent id.
+	baggageTraceID  = "otlp.trace_id"
+	baggageParentID = "otlp.parent_id"
+
+	instrumentationName = "github.com/pingcap/tidb"
+
+	defaultBatchSize     = 512
+	defaultQueueSize     = 4096
+	defaultFlushInterval = time.Second
+)
+
+var globalTracer atomic.Pointer[Tracer]
+
+// SetGlobalTracer sets the tracer used to trace the SQL execution. Nil means the OTLP tracing is disabled.
+func SetGlobalTracer(t *Tracer) {
+	globalTracer.Store(t)
+}
+
+// GlobalTracer returns the tracer used to trace the SQL execution, it returns nil if the OTLP tracing is disabled.
+func GlobalTracer() *Tracer {
+	return globalTracer.Load()
+}
+
+// TracerConfig is the config of a Tracer.
+type TracerConfig struct {
+	// SampleRate is the ratio of the statements traced when the client doesn't decide it by the trace context.
+	SampleRate float64
+	// BatchSize is the max number of spans exported in one request.
+	BatchSize int
+	// QueueSize is the max number of spans waiting to be exported, the spans exceeding it are dropped.
+	QueueSize int
+	// ResourceAttributes describes the TiDB instance, such as `service.name`.
+	ResourceAttributes map[string]string
+}
+
+// Tracer is an opentracing.Tracer which exports the finished spans by OTLP. It reuses the opentracing
+// instrumentation all over TiDB and the TiKV client, so the spans of parsing, compiling, executing and
+// committing are exported without depending on another tracing library.
+type Tracer struct {
+	opentracing.Tracer
+
+	exporter   Exporter
+	sampleRate float64
+	batchSize  int
+	resource   *resourcepb.Resource
+
+	spanCh  chan basictracer.RawSpan
+	dropped atomic.Int64
+	exitCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewTracer creates a Tracer and starts the background goroutine exporting the spans.
+func NewTracer(exporter Exporter, cfg TracerConfig) *Tracer {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	t := &Tracer{
+		exporter:   exporter,
+		sampleRate: cfg.SampleRate,
+		batchSize:  cfg.BatchSize,
+		resource:   &resourcepb.Resource{Attributes: stringAttributes(cfg.ResourceAttributes)},
+		spanCh:     make(chan basictracer.RawSpan, cfg.QueueSize),
+		exitCh:     make(chan struct{}),
+	}
+	opts := basictracer.DefaultOptions()
+	// The sampling is decided when starting the root span, all the spans created by the tracer are recorded.
+	opts.ShouldSample = func(uint64) bool { return true }
+	opts.Recorder = t
+	opts.MaxLogsPerSpan = 64
+	t.Tracer = basictracer.NewWithOptions(opts)
+	t.wg.Add(1)
+	go t.run()
+	return t
+}
+
+// StartRootSpan starts the root span of a request. `traceParent` is the W3C trace context from the client,
+// the span continues the trace of the client if it's valid. It returns nil if the request isn't sampled.
+func (t *Tracer) StartRootSpan(opName string, traceParent string) opentracing.Span {
+	parent, ok := ParseTraceParent(traceParent)
+	if ok && !parent.Sampled {
+		return nil
+	}
+	if !ok && (t.sampleRate <= 0 || rand.Float64() >= t.sampleRate) { // #nosec G404
+		return nil
+	}
+	span := t.StartSpan(opName, ext.SpanKindRPCServer)
+	if ok {
+		span.SetBaggageItem(baggageTraceID, hex.EncodeToString(parent.TraceID[:]))
+		span.SetBaggageItem(baggageParentID, hex.EncodeToString(parent.SpanID[:]))
+	}
+	return span
+}
+
+// RecordSpan implements basictracer.SpanRecorder.
+func (t *Tracer) RecordSpan(span basictracer.RawSpan) {
+	select {
+	case t.spanCh <- span:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// Close flushes the spans and stops the tracer.
+func (t *Tracer) Close() {
+	close(t.exitCh)
+	t.wg.Wait()
+	if err := t.exporter.Close(); err != nil {
+		logutil.BgLogger().Warn("close OTLP exporter failed", zap.Error(err))
+	}
+}
+
+func (t *Tracer) run() {
+	defer t.wg.Done()
+	ticker := time.NewTicker(defaultFlushInterval)
+	defer ticker.Stop()
+	batch := make([]basictracer.RawSpan, 0, t.batchSize)
+	for {
+		select {
+		case span := <-t.spanCh:
+			batch = append(batch, span)
+			if len(batch) >= t.batchSize {
+				batch = t.export(batch)
+			}
+		case <-ticker.C:
+			batch = t.export(batch)
+		case <-t.exitCh:
+			for {
+				select {
+				case span := <-t.spanCh:
+					batch = append(batch, span)
+					if len(batch) >= t.batchSize {
+						batch = t.export(batch)
+					}
+				default:
+					t.export(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// export exports the spans and returns the batch for reuse.
+func (t *Tracer) export(batch []basictracer.RawSpan) []basictracer.RawSpan {
+	if dropped := t.dropped.Swap(0); dropped > 0 {
+		logutil.BgLogger().Warn("OTLP spans are dropped because the export queue is full", zap.Int64("count", dropped))
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+	spans := make([]*tracepb.Span, 0, len(batch))
+	for i := range batch {
+		spans = append(spans, convertSpan(&batch[i]))
+	}
+	resourceSpans := []*tracepb.ResourceSpans{{
+		Resource: t.resource,
+		InstrumentationLibrarySpans: []*tracepb.InstrumentationLibrarySpans{{
+			InstrumentationLibrary: &commonpb.InstrumentationLibrary{Name: instrumentationName},
+			Spans:                  spans,
+		}},
+	}}
+	if err := t.exporter.Export(context.Background(), resourceSpans); err != nil {
+		logutil.BgLogger().Warn("export OTLP spans failed", zap.Int("count", len(spans)), zap.Error(err))
+	}
+	return batch[:0]
+}
+
+func convertSpan(raw *basictracer.RawSpan) *tracepb.Span {
+	span := &tracepb.Span{
+		TraceId:           traceIDOf(raw),
+		SpanId:            uint64ToBytes(raw.Context.SpanID),
+		Name:              raw.Operation,
+		Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: uint64(raw.Start.UnixNano()),
+		EndTimeUnixNano:   uint64(raw.Start.Add(raw.Duration).UnixNano()),
+	}
+	if raw.ParentSpanID != 0 {
+		span.ParentSpanId = uint64ToBytes(raw.ParentSpanID)
+	} else if parentID, err := hex.DecodeString(raw.Context.Baggage[baggageParentID]); err == nil && len(parentID) == 8 {
+		span.ParentSpanId = parentID
+	}
+	for k, v := range raw.Tags {
+		switch k {
+		case string(ext.SpanKind):
+			if v == ext.SpanKindRPCServerEnum {
+				span.Kind = tracepb.Span_SPAN_KIND_SERVER
+			}
+			continue
+		case string(ext.Error):
+			if isErr, ok := v.(bool); ok && isErr {
+				span.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR}
+			}
+		}
+		span.Attributes = append(span.Attributes, &commonpb.KeyValue{Key: k, Value: anyValue(v)})
+	}
+	// Sort the attributes to make the output stable.
+	sort.Slice(span.Attributes, func(i, j int) bool { return span.Attributes[i].Key < span.Attributes[j].Key })
+	for _, log := range raw.Logs {
+		event := &tracepb.Span_Event{Name: "log", TimeUnixNano: uint64(log.Timestamp.UnixNano())}
+		for _, field := range log.Fields {
+			if field.Key() == "event" {
+				event.Name = fmt.Sprint(field.Value())
+				continue
+			}
+			event.Attributes = append(event.Attributes, &commonpb.KeyValue{Key: field.Key(), Value: anyValue(field.Value())})
+		}
+		span.Events = append(span.Events, event)
+	}
+	return span
+}
+
+// traceIDOf returns the 16-byte trace id of the span. It's the trace id from the client if there is one,
+// otherwise it's the 8-byte trace id of basictracer padded with zeros.
+func traceIDOf(raw *basictracer.RawSpan) []byte {
+	if traceID, err := hex.DecodeString(raw.Context.Baggage[baggageTraceID]); err == nil && len(traceID) == 16 {
+		return traceID
+	}
+	traceID := make([]byte, 16)
+	binary.BigEndian.PutUint64(traceID[8:], raw.Context.TraceID)
+	return traceID
+}
+
+func uint64ToBytes(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+func anyValue(v interface{}) *commonpb.AnyValue {
+	switch x := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: x}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: x}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(x)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(x)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: x}}
+	case uint32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(x)}}
+	case uint64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(x)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: x}}
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprint(v)}}
+}
+
+func stringAttributes(attrs map[string]string) []*commonpb.KeyValue {
+	kvs := make([]*commonpb.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, &commonpb.KeyValue{Key: k, Value: anyValue(v)})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
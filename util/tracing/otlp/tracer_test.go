@@ -0,0 +1,168 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type mockExporter struct {
+	sync.Mutex
+	spans  []*tracepb.Span
+	closed bool
+}
+
+func (e *mockExporter) Export(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	e.Lock()
+	defer e.Unlock()
+	for _, rs := range spans {
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			e.spans = append(e.spans, ils.Spans...)
+		}
+	}
+	return nil
+}
+
+func (e *mockExporter) Close() error {
+	e.closed = true
+	return nil
+}
+
+func (e *mockExporter) spanByName(name string) *tracepb.Span {
+	for _, span := range e.spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestTracerContinueClientTrace(t *testing.T) {
+	exporter := &mockExporter{}
+	tracer := NewTracer(exporter, TracerConfig{ResourceAttributes: map[string]string{"service.name": "tidb"}})
+	root := tracer.StartRootSpan("server.dispatch", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.NotNil(t, root)
+	child := tracer.StartSpan("executor.Compile", opentracing.ChildOf(root.Context()))
+	child.SetTag("sql", "select 1")
+	child.SetTag("rows", 3)
+	child.LogKV("event", "retry", "times", 1)
+	grandChild := tracer.StartSpan("twoPhaseCommitter.prewriteMutations", opentracing.ChildOf(child.Context()))
+	grandChild.SetTag("error", true)
+	grandChild.Finish()
+	child.Finish()
+	root.Finish()
+	tracer.Close()
+	require.True(t, exporter.closed)
+
+	require.Len(t, exporter.spans, 3)
+	rootSpan := exporter.spanByName("server.dispatch")
+	childSpan := exporter.spanByName("executor.Compile")
+	grandChildSpan := exporter.spanByName("twoPhaseCommitter.prewriteMutations")
+	for _, span := range exporter.spans {
+		require.Equal(t, "0af7651916cd43dd8448eb211c80319c", hex.EncodeToString(span.TraceId))
+		require.Len(t, span.SpanId, 8)
+		require.LessOrEqual(t, span.StartTimeUnixNano, span.EndTimeUnixNano)
+	}
+	require.Equal(t, "b7ad6b7169203331", hex.EncodeToString(rootSpan.ParentSpanId))
+	require.Equal(t, tracepb.Span_SPAN_KIND_SERVER, rootSpan.Kind)
+	require.Equal(t, rootSpan.SpanId, childSpan.ParentSpanId)
+	require.Equal(t, childSpan.SpanId, grandChildSpan.ParentSpanId)
+	require.Equal(t, tracepb.Span_SPAN_KIND_INTERNAL, childSpan.Kind)
+
+	require.Len(t, childSpan.Attributes, 2)
+	require.Equal(t, "rows", childSpan.Attributes[0].Key)
+	require.Equal(t, int64(3), childSpan.Attributes[0].Value.GetIntValue())
+	require.Equal(t, "sql", childSpan.Attributes[1].Key)
+	require.Equal(t, "select 1", childSpan.Attributes[1].Value.GetStringValue())
+	require.Len(t, childSpan.Events, 1)
+	require.Equal(t, "retry", childSpan.Events[0].Name)
+	require.Equal(t, "times", childSpan.Events[0].Attributes[0].Key)
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, grandChildSpan.Status.Code)
+}
+
+func TestTracerSampling(t *testing.T) {
+	exporter := &mockExporter{}
+	tracer := NewTracer(exporter, TracerConfig{SampleRate: 0})
+	// the client decides not to sample the trace
+	require.Nil(t, tracer.StartRootSpan("server.dispatch", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00"))
+	// the sample rate is 0
+	require.Nil(t, tracer.StartRootSpan("server.dispatch", ""))
+	require.Nil(t, tracer.StartRootSpan("server.dispatch", "invalid"))
+	tracer.Close()
+	require.Len(t, exporter.spans, 0)
+
+	exporter = &mockExporter{}
+	tracer = NewTracer(exporter, TracerConfig{SampleRate: 1, BatchSize: 1})
+	root := tracer.StartRootSpan("server.dispatch", "")
+	require.NotNil(t, root)
+	root.Finish()
+	tracer.Close()
+	require.Len(t, exporter.spans, 1)
+	// the trace id is generated by TiDB
+	require.Len(t, exporter.spans[0].TraceId, 16)
+	require.NotEqual(t, make([]byte, 16), exporter.spans[0].TraceId)
+	require.Nil(t, exporter.spans[0].ParentSpanId)
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	exporter, err := NewExporter(ExporterConfig{Protocol: ProtocolFile, FilePath: path})
+	require.NoError(t, err)
+	tracer := NewTracer(exporter, TracerConfig{SampleRate: 1})
+	for i := 0; i < 2; i++ {
+		root := tracer.StartRootSpan("server.dispatch", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		tracer.StartSpan("session.ParseSQL", opentracing.ChildOf(root.Context())).Finish()
+		root.Finish()
+	}
+	tracer.Close()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	scanner := bufio.NewScanner(f)
+	numSpans := 0
+	for scanner.Scan() {
+		req := &coltracepb.ExportTraceServiceRequest{}
+		require.NoError(t, protojson.Unmarshal(scanner.Bytes(), req))
+		for _, rs := range req.ResourceSpans {
+			for _, ils := range rs.InstrumentationLibrarySpans {
+				numSpans += len(ils.Spans)
+			}
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, 4, numSpans)
+
+	_, err = NewExporter(ExporterConfig{Protocol: "unknown"})
+	require.Error(t, err)
+	_, err = NewExporter(ExporterConfig{Protocol: ProtocolGRPC})
+	require.Error(t, err)
+	_, err = NewExporter(ExporterConfig{Protocol: ProtocolFile, FilePath: filepath.Join(t.TempDir(), "not-exist", "trace.json")})
+	require.Error(t, err)
+}